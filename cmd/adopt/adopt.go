@@ -0,0 +1,186 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package adopt
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gifflet/ccmd/core"
+	"github.com/gifflet/ccmd/pkg/errors"
+	"github.com/gifflet/ccmd/pkg/output"
+)
+
+// NewCommand creates a new adopt command.
+func NewCommand() *cobra.Command {
+	var yes, localOnly, dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "adopt [command-name...]",
+		Short: "Bring hand-copied commands under ccmd management",
+		Long: `Bring command directories and .md files under .claude/commands that
+ccmd-lock.yaml does not track, such as prompt files copied by hand, under ccmd
+management. Without names, every untracked command is offered.
+
+The source of each command is inferred from, in order:
+
+  git remote   the origin remote of a directory that is a git checkout
+  ccmd.yaml    the repository field of the command's own ccmd.yaml
+  header       the header of a <name>.md written by ccmd
+
+Adopting a command with a source adds it to ccmd.yaml and records its files as they
+are in ccmd-lock.yaml. Commands without a source, or adopted with --local-only, are
+tracked as local commands, as 'ccmd track' does: ccmd-lock.yaml records them with
+source local, ccmd.yaml does not list them, and sync and update leave them alone. A lone .md file gets a command directory with a copy of it as
+index.md, and the file itself is kept as a local override.
+
+In a terminal, each command is confirmed; --yes adopts them all without asking.
+
+Examples:
+  # See what can be adopted
+  ccmd adopt --dry-run
+
+  # Adopt everything, keeping commands without a source local-only
+  ccmd adopt --yes
+
+  # Adopt one command without recording its source
+  ccmd adopt review --local-only`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runAdopt(args, yes, localOnly, dryRun)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Adopt without asking for confirmation")
+	cmd.Flags().BoolVar(&localOnly, "local-only", false, "Record the commands in ccmd-lock.yaml only, without their source")
+	cmd.Flags().BoolVarP(&dryRun, "dry-run", "n", false, "List the commands that can be adopted without adopting them")
+
+	return cmd
+}
+
+func runAdopt(names []string, yes, localOnly, dryRun bool) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	candidates, err := core.FindAdoptable(cwd)
+	if err != nil {
+		return err
+	}
+	if len(names) > 0 {
+		var selected []core.AdoptCandidate
+		for _, name := range names {
+			i := slices.IndexFunc(candidates, func(c core.AdoptCandidate) bool { return c.Name == name })
+			if i < 0 {
+				return errors.NotFound(fmt.Sprintf("untracked command %q", name))
+			}
+			selected = append(selected, candidates[i])
+		}
+		candidates = selected
+	}
+
+	if len(candidates) == 0 {
+		output.PrintInfof("Nothing to adopt: ccmd-lock.yaml tracks every command under .claude/commands")
+		return nil
+	}
+
+	interactive := !yes && !dryRun && output.IsTerminal(os.Stdin)
+	if !yes && !dryRun && !interactive {
+		return fmt.Errorf("adopting needs confirmation; use --yes to adopt without asking")
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	adopted := 0
+	for _, candidate := range candidates {
+		printCandidate(candidate, localOnly)
+		if dryRun {
+			continue
+		}
+
+		opts := core.AdoptOptions{ProjectPath: cwd, LocalOnly: localOnly}
+		if interactive {
+			var ok bool
+			if ok, opts.LocalOnly = confirm(reader, candidate, localOnly); !ok {
+				output.PrintInfof("Skipped %s", candidate.Name)
+				continue
+			}
+		}
+
+		if err := core.Adopt(candidate, opts); err != nil {
+			output.PrintErrorf("Failed to adopt %s: %v", candidate.Name, err)
+			continue
+		}
+		adopted++
+		if opts.LocalOnly || candidate.Repository == "" {
+			output.PrintSuccessf("Adopted %s as local-only", candidate.Name)
+		} else {
+			output.PrintSuccessf("Adopted %s from %s", candidate.Name, candidate.Repository)
+		}
+	}
+
+	if dryRun {
+		output.PrintInfof("\n(dry-run mode - no changes made)")
+		return nil
+	}
+	if adopted < len(candidates) && !interactive {
+		return fmt.Errorf("%d command(s) failed to adopt", len(candidates)-adopted)
+	}
+	return nil
+}
+
+// printCandidate shows an untracked command, its files and the source found for it
+func printCandidate(candidate core.AdoptCandidate, localOnly bool) {
+	output.Printf("\n%s", candidate.Name)
+	for _, path := range candidate.Paths {
+		output.Printf("  %s", path)
+	}
+	switch {
+	case candidate.Repository == "":
+		output.Printf("  source: none found, adopted as local-only")
+	case localOnly:
+		output.Printf("  source: %s (from %s), not recorded with --local-only", sourceLabel(candidate), candidate.Origin)
+	default:
+		output.Printf("  source: %s (from %s)", sourceLabel(candidate), candidate.Origin)
+	}
+}
+
+// sourceLabel returns the repository of a candidate with its version or commit
+func sourceLabel(candidate core.AdoptCandidate) string {
+	switch {
+	case candidate.Version != "":
+		return candidate.Repository + "@" + candidate.Version
+	case candidate.Commit != "":
+		return fmt.Sprintf("%s (%.7s)", candidate.Repository, candidate.Commit)
+	}
+	return candidate.Repository
+}
+
+// confirm asks whether to adopt a command, and whether as local-only
+func confirm(reader *bufio.Reader, candidate core.AdoptCandidate, localOnly bool) (ok, asLocalOnly bool) {
+	if candidate.Repository == "" || localOnly {
+		output.Printf("Adopt %s as local-only? [Y/n]: ", candidate.Name)
+	} else {
+		output.Printf("Adopt %s with its source, as local-only, or skip it? [Y/l/n]: ", candidate.Name)
+	}
+
+	response, _ := reader.ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(response)) {
+	case "", "y", "yes":
+		return true, localOnly
+	case "l", "local", "local-only":
+		return true, true
+	}
+	return false, false
+}
@@ -0,0 +1,31 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package adopt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCommand(t *testing.T) {
+	cmd := NewCommand()
+
+	assert.Equal(t, "adopt [command-name...]", cmd.Use)
+	assert.NotEmpty(t, cmd.Short)
+	assert.NotEmpty(t, cmd.Long)
+
+	for _, name := range []string{"yes", "local-only", "dry-run"} {
+		flag := cmd.Flags().Lookup(name)
+		assert.NotNil(t, flag, name)
+		assert.Equal(t, "false", flag.DefValue)
+	}
+	assert.Equal(t, "y", cmd.Flags().Lookup("yes").Shorthand)
+}
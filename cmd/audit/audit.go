@@ -0,0 +1,134 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gifflet/ccmd/core"
+	"github.com/gifflet/ccmd/pkg/output"
+)
+
+// NewCommand creates a new audit command.
+func NewCommand() *cobra.Command {
+	var (
+		action     string
+		since      string
+		until      string
+		jsonFormat bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "audit [command-name]",
+		Short: "Show the audit log of ccmd operations",
+		Long: `Show the audit log of install, update, rollback, remove, sync and verify operations.
+
+Every operation appends a JSON line to .claude/ccmd-audit.log recording when it
+ran, who ran it, the repository, version and commit, and whether it succeeded.
+The actor is $CCMD_ACTOR, the git user.email, or the OS user. The log is rotated
+at 1 MiB and the last three rotated files are kept and included in the output.
+
+--since and --until accept a duration before now (24h, 7d), a date (2025-01-31)
+or an RFC 3339 time.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			query := core.AuditQuery{Action: action}
+			if len(args) > 0 {
+				query.Name = args[0]
+			}
+			return runAudit(query, since, until, jsonFormat)
+		},
+	}
+
+	cmd.Flags().StringVarP(&action, "action", "a", "", "Only show one operation (install, update, rollback, remove, sync, verify)")
+	cmd.Flags().StringVar(&since, "since", "", "Only show events after this time")
+	cmd.Flags().StringVar(&until, "until", "", "Only show events before this time")
+	cmd.Flags().BoolVar(&jsonFormat, "json", false, "Output events as JSON lines")
+
+	return cmd
+}
+
+func runAudit(query core.AuditQuery, since, until string, jsonFormat bool) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	query.ProjectPath = cwd
+
+	now := time.Now()
+	if since != "" {
+		if query.Since, err = core.ParseAuditTime(since, now); err != nil {
+			return err
+		}
+	}
+	if until != "" {
+		if query.Until, err = core.ParseAuditTime(until, now); err != nil {
+			return err
+		}
+	}
+
+	events, err := core.ReadAudit(query)
+	if err != nil {
+		return fmt.Errorf("failed to read audit log: %w", err)
+	}
+
+	if jsonFormat {
+		for _, event := range events {
+			data, err := json.Marshal(event)
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(data))
+		}
+		return nil
+	}
+
+	if len(events) == 0 {
+		output.PrintInfof("No audit events found.")
+		return nil
+	}
+
+	header := fmt.Sprintf("%-17s %-8s %-24s %-22s %-8s %s", "TIME", "ACTION", "NAME", "VERSION", "RESULT", "ACTOR")
+	output.Printf(header)
+	output.Printf(strings.Repeat("-", len(header)))
+
+	for _, event := range events {
+		name := event.Name
+		if name == "" {
+			name = event.Details
+		}
+		version := event.Version
+		if event.PreviousVersion != "" && event.PreviousVersion != event.Version {
+			version = event.PreviousVersion + " → " + version
+		}
+		if version == "" {
+			version = "-"
+		}
+
+		line := fmt.Sprintf("%-17s %-8s %-24s %-22s %-8s %s",
+			event.Time.Local().Format("2006-01-02 15:04"), event.Action, name, version, event.Result, event.Actor)
+		if event.Result == core.AuditFailure {
+			output.PrintErrorf("%s", line)
+			if event.Error != "" {
+				output.PrintErrorf("  %s", event.Error)
+			}
+			continue
+		}
+		output.Printf("%s", line)
+	}
+
+	return nil
+}
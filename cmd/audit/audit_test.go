@@ -0,0 +1,33 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package audit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCommand(t *testing.T) {
+	cmd := NewCommand()
+
+	assert.Equal(t, "audit [command-name]", cmd.Use)
+	assert.NotEmpty(t, cmd.Short)
+	assert.NotEmpty(t, cmd.Long)
+
+	for _, flag := range []string{"action", "since", "until", "json"} {
+		assert.NotNil(t, cmd.Flags().Lookup(flag), "missing --%s", flag)
+	}
+	assert.Equal(t, "a", cmd.Flags().Lookup("action").Shorthand)
+
+	assert.NoError(t, cmd.Args(cmd, []string{}))
+	assert.NoError(t, cmd.Args(cmd, []string{"name"}))
+	assert.Error(t, cmd.Args(cmd, []string{"a", "b"}))
+}
@@ -0,0 +1,318 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package browse
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gifflet/ccmd/core"
+	"github.com/gifflet/ccmd/pkg/config"
+	"github.com/gifflet/ccmd/pkg/output"
+)
+
+// NewCommand creates a new browse command.
+func NewCommand() *cobra.Command {
+	var (
+		tags     []string
+		catalogs []string
+		noGitHub bool
+		limit    int
+		profile  string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "browse [keyword]",
+		Short: "Browse catalogs and install several commands at once",
+		Long: `Browse commands from the configured catalogs, taps and GitHub, then pick the ones to install.
+
+Results are listed with their description, tags and stars, the same way as
+'ccmd search --remote'. Select entries by number, separated by spaces or commas,
+with ranges such as 2-5, or 'all'. Enter ?N to show the version, details and first
+line of the prompt of entry N; they are read in the background from each repository's
+ccmd.yaml and index.md, without cloning. The selected commands are installed in one batch
+and added to ccmd.yaml (or to a profile with --profile). Commands that are already
+installed are skipped.
+
+Examples:
+  # Browse everything in the configured catalogs and on GitHub
+  ccmd browse
+
+  # Browse commands matching a keyword and tag
+  ccmd browse review --tags git
+
+  # Add the picked commands to the "backend" profile
+  ccmd browse --profile backend`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var keyword string
+			if len(args) > 0 {
+				keyword = args[0]
+			}
+
+			cwd, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+
+			settings, err := config.Load(cwd)
+			if err != nil {
+				return err
+			}
+			taps, err := core.ListTaps(cwd)
+			if err != nil {
+				return err
+			}
+
+			spinner := output.NewSpinner("Searching remote sources...")
+			spinner.Start()
+			report, err := core.SearchRemote(cmd.Context(), core.RemoteSearchOptions{
+				Keyword:     keyword,
+				Tags:        tags,
+				Catalogs:    append(settings.Catalogs, catalogs...),
+				Taps:        taps,
+				NoGitHub:    noGitHub,
+				Limit:       limit,
+				ProjectPath: cwd,
+			})
+			spinner.Stop()
+			if err != nil {
+				return fmt.Errorf("search failed: %w", err)
+			}
+
+			for _, warning := range report.Warnings {
+				output.PrintWarningf("Skipped %s", warning)
+			}
+
+			if len(report.Results) == 0 {
+				output.PrintInfof("No remote commands found matching your criteria.")
+				return nil
+			}
+
+			for i := range report.Results {
+				displayEntry(i+1, &report.Results[i])
+			}
+
+			// Details are loaded while the list is read, so ?N answers at once
+			loader, err := core.NewDetailLoader(nil, len(report.Results))
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithCancel(cmd.Context())
+			defer cancel()
+			repositories := make([]string, len(report.Results))
+			for i, result := range report.Results {
+				repositories[i] = result.Repository
+			}
+			go loader.Prefetch(ctx, repositories, settings.Jobs)
+
+			describe := func(i int) {
+				displayDetails(ctx, loader, &report.Results[i])
+			}
+			selected, err := promptSelection(cmd.InOrStdin(), len(report.Results), describe)
+			if err != nil {
+				return err
+			}
+			if len(selected) == 0 {
+				output.PrintInfof("Nothing selected")
+				return nil
+			}
+
+			return installSelected(cmd, report.Results, selected, profile)
+		},
+	}
+
+	cmd.Flags().StringSliceVarP(&tags, "tags", "t", []string{}, "Filter by tags (comma-separated)")
+	cmd.Flags().StringSliceVar(&catalogs, "catalog", []string{}, "Additional catalog URL or file to browse")
+	cmd.Flags().BoolVar(&noGitHub, "no-github", false, "Skip the GitHub search API")
+	cmd.Flags().IntVar(&limit, "limit", 50, "Maximum number of results to list")
+	cmd.Flags().StringVar(&profile, "profile", "", "Record the selected commands under this ccmd.yaml profile")
+
+	return cmd
+}
+
+func displayEntry(index int, result *core.RemoteSearchResult) {
+	header := fmt.Sprintf("%3d) %s", index, result.Name)
+	if result.Stars > 0 {
+		header += fmt.Sprintf(" ★%d", result.Stars)
+	}
+	if result.Installed {
+		header += fmt.Sprintf(" [installed v%s]", result.InstalledVersion)
+	}
+	output.PrintInfof("%s", header)
+
+	if result.Description != "" {
+		output.Printf("     %s", result.Description)
+	}
+	if len(result.Tags) > 0 {
+		output.Printf("     Tags: %s", strings.Join(result.Tags, ", "))
+	}
+	output.Printf("     %s", result.Repository)
+}
+
+// displayDetails shows the details of an entry read from its repository
+func displayDetails(ctx context.Context, loader *core.DetailLoader, result *core.RemoteSearchResult) {
+	details, err := loader.Load(ctx, result.Repository)
+	if err != nil {
+		output.PrintWarningf("No details for %s: %v", result.Name, err)
+		return
+	}
+
+	header := result.Name
+	if details.Version != "" {
+		header += " v" + details.Version
+	}
+	output.PrintInfof("\n%s", header)
+	if details.Description != "" {
+		output.Printf("  %s", details.Description)
+	}
+	if details.Author != "" {
+		output.Printf("  Author: %s", details.Author)
+	}
+	if details.License != "" {
+		output.Printf("  License: %s", details.License)
+	}
+	if len(details.Tags) > 0 {
+		output.Printf("  Tags: %s", strings.Join(details.Tags, ", "))
+	}
+	if details.Summary != "" {
+		output.Printf("  > %s", details.Summary)
+	}
+}
+
+// promptSelection reads the chosen entries from in, asking again on invalid input.
+// An answer of ?N passes entry N-1 to describe and asks again. An empty answer or end
+// of input selects nothing.
+func promptSelection(in io.Reader, count int, describe func(int)) ([]int, error) {
+	reader := bufio.NewReader(in)
+	for {
+		output.Printf("\nSelect commands to install (e.g. 1 3 5-7, 'all', ?N for details, empty to cancel): ")
+
+		line, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+
+		if answer := strings.TrimSpace(line); strings.HasPrefix(answer, "?") && describe != nil {
+			index, convErr := strconv.Atoi(strings.TrimSpace(answer[1:]))
+			switch {
+			case convErr != nil || index < 1 || index > count:
+				output.PrintWarningf("details entry %q is out of range 1-%d", answer[1:], count)
+			default:
+				describe(index - 1)
+			}
+			if err == io.EOF {
+				return nil, nil
+			}
+			continue
+		}
+
+		selected, parseErr := parseSelection(line, count)
+		if parseErr == nil {
+			return selected, nil
+		}
+		if err == io.EOF {
+			return nil, parseErr
+		}
+		output.PrintWarningf("%v", parseErr)
+	}
+}
+
+// parseSelection turns an answer such as "1, 3 5-7" or "all" into sorted, unique
+// zero-based indexes into a list of count entries
+func parseSelection(input string, count int) ([]int, error) {
+	input = strings.TrimSpace(input)
+	if strings.EqualFold(input, "all") {
+		all := make([]int, count)
+		for i := range all {
+			all[i] = i
+		}
+		return all, nil
+	}
+
+	seen := make(map[int]bool)
+	for _, field := range strings.FieldsFunc(input, func(r rune) bool { return r == ',' || r == ' ' }) {
+		from, to := field, field
+		if before, after, ok := strings.Cut(field, "-"); ok {
+			from, to = before, after
+		}
+
+		start, err := strconv.Atoi(from)
+		if err != nil {
+			return nil, fmt.Errorf("invalid selection %q", field)
+		}
+		end, err := strconv.Atoi(to)
+		if err != nil {
+			return nil, fmt.Errorf("invalid selection %q", field)
+		}
+		if start < 1 || end > count || start > end {
+			return nil, fmt.Errorf("selection %q is out of range 1-%d", field, count)
+		}
+
+		for i := start; i <= end; i++ {
+			seen[i-1] = true
+		}
+	}
+
+	selected := make([]int, 0, len(seen))
+	for i := range seen {
+		selected = append(selected, i)
+	}
+	sort.Ints(selected)
+
+	return selected, nil
+}
+
+// installSelected installs the chosen entries one after another. A failed install is
+// reported and the batch continues; the error lists every command that failed.
+func installSelected(cmd *cobra.Command, results []core.RemoteSearchResult, selected []int, profile string) error {
+	var installed, failed []string
+
+	for _, i := range selected {
+		result := results[i]
+		if result.Installed {
+			output.PrintInfof("Skipping %s: already installed", result.Name)
+			continue
+		}
+
+		if err := cmd.Context().Err(); err != nil {
+			return err
+		}
+
+		name, _, err := core.Install(cmd.Context(), core.InstallOptions{
+			Repository: result.Repository,
+			Profile:    profile,
+		})
+		if err != nil {
+			output.PrintErrorf("Failed to install %s: %v", result.Name, err)
+			failed = append(failed, result.Name)
+			continue
+		}
+		installed = append(installed, name)
+	}
+
+	if len(installed) > 0 {
+		output.PrintSuccessf("\nInstalled %d command(s): %s", len(installed), strings.Join(installed, ", "))
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to install %d command(s): %s", len(failed), strings.Join(failed, ", "))
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package browse
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCommand(t *testing.T) {
+	cmd := NewCommand()
+
+	assert.Equal(t, "browse [keyword]", cmd.Use)
+	assert.NotEmpty(t, cmd.Short)
+	assert.NotEmpty(t, cmd.Long)
+
+	for _, flag := range []string{"tags", "catalog", "no-github", "profile"} {
+		assert.NotNil(t, cmd.Flags().Lookup(flag), flag)
+	}
+	assert.Equal(t, "50", cmd.Flags().Lookup("limit").DefValue)
+
+	assert.NoError(t, cmd.Args(cmd, []string{}))
+	assert.Error(t, cmd.Args(cmd, []string{"a", "b"}))
+}
+
+func TestParseSelection(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []int
+	}{
+		{"", []int{}},
+		{"2", []int{1}},
+		{"3, 1 3", []int{0, 2}},
+		{"2-4,1", []int{0, 1, 2, 3}},
+		{"ALL", []int{0, 1, 2, 3, 4}},
+	}
+
+	for _, tt := range tests {
+		selected, err := parseSelection(tt.input, 5)
+		require.NoError(t, err, tt.input)
+		assert.Equal(t, tt.expected, selected, tt.input)
+	}
+
+	for _, input := range []string{"0", "6", "x", "4-2", "1-"} {
+		_, err := parseSelection(input, 5)
+		assert.Error(t, err, input)
+	}
+}
+
+func TestPromptSelectionRetries(t *testing.T) {
+	selected, err := promptSelection(strings.NewReader("9\n1 2\n"), 3, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []int{0, 1}, selected)
+
+	selected, err = promptSelection(strings.NewReader(""), 3, nil)
+	require.NoError(t, err)
+	assert.Empty(t, selected)
+
+	_, err = promptSelection(strings.NewReader("9"), 3, nil)
+	assert.Error(t, err)
+}
+
+func TestPromptSelectionDetails(t *testing.T) {
+	var described []int
+	describe := func(i int) { described = append(described, i) }
+
+	selected, err := promptSelection(strings.NewReader("?2\n?9\n? 3\n1\n"), 3, describe)
+	require.NoError(t, err)
+	assert.Equal(t, []int{0}, selected)
+	assert.Equal(t, []int{1, 2}, described)
+}
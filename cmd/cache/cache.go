@@ -0,0 +1,98 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gifflet/ccmd/core"
+	"github.com/gifflet/ccmd/pkg/output"
+)
+
+// NewCommand creates a new cache command.
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Manage the cache of repositories, tag lists and temporary files",
+		Long: `Manage what ccmd keeps under cache_dir:
+- repos: bare copies of command repositories, so reinstalls only fetch new objects
+- tags: remote tag lists, reused for tag_cache_ttl seconds
+- taps: catalogs of the taps added with 'ccmd tap'
+- bases: shared base configurations named by 'extends' in ccmd.yaml
+- tmp: working directories of running installs, updates and diffs
+
+Temporary directories left behind by crashed runs are removed when ccmd starts
+once they are older than tmp.max_age_hours, or when together they exceed
+tmp.max_size_mb, oldest first. Directories changed within the last hour are
+assumed to belong to a running ccmd and are kept.`,
+		Example: `  # Remove every cached repository, tag list, catalog and base configuration
+  ccmd cache clean
+
+  # Remove only temporary directories left behind by crashed runs
+  ccmd cache clean --tmp`,
+	}
+
+	cmd.AddCommand(newCleanCommand())
+
+	return cmd
+}
+
+func newCleanCommand() *cobra.Command {
+	var tmp, jsonFormat bool
+
+	cmd := &cobra.Command{
+		Use:   "clean",
+		Short: "Remove cached data and stale temporary directories",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runClean(tmp, jsonFormat)
+		},
+	}
+
+	cmd.Flags().BoolVar(&tmp, "tmp", false, "Remove only the temporary directories not in use, whatever their age")
+	cmd.Flags().BoolVar(&jsonFormat, "json", false, "Output the result in JSON format")
+
+	return cmd
+}
+
+func runClean(tmp, jsonFormat bool) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	result, err := core.CleanCache(core.CacheCleanOptions{ProjectPath: cwd, Tmp: tmp})
+	if err != nil {
+		return fmt.Errorf("failed to clean the cache: %w", err)
+	}
+
+	if jsonFormat {
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	for _, path := range result.Removed {
+		output.PrintVerbosef("Removed %s", path)
+	}
+	if len(result.Removed) == 0 {
+		output.PrintInfof("Nothing to clean")
+		return nil
+	}
+	output.PrintSuccessf("Removed %d item(s), freeing %s", len(result.Removed), core.FormatSize(result.Freed))
+	return nil
+}
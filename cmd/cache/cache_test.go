@@ -0,0 +1,35 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package cache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCommand(t *testing.T) {
+	cmd := NewCommand()
+
+	assert.Equal(t, "cache", cmd.Use)
+	assert.NotEmpty(t, cmd.Short)
+	assert.NotEmpty(t, cmd.Long)
+
+	clean, _, err := cmd.Find([]string{"clean"})
+	require.NoError(t, err)
+	assert.Equal(t, "clean", clean.Name())
+	assert.NoError(t, clean.Args(clean, []string{}))
+	assert.Error(t, clean.Args(clean, []string{"extra"}))
+	tmp := clean.Flags().Lookup("tmp")
+	require.NotNil(t, tmp)
+	assert.Equal(t, "false", tmp.DefValue)
+	assert.NotNil(t, clean.Flags().Lookup("json"))
+}
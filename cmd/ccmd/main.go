@@ -10,19 +10,66 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 
+	"github.com/gifflet/ccmd/cmd/adopt"
+	"github.com/gifflet/ccmd/cmd/audit"
+	"github.com/gifflet/ccmd/cmd/browse"
+	"github.com/gifflet/ccmd/cmd/cache"
+	"github.com/gifflet/ccmd/cmd/ci"
+	cmdconfig "github.com/gifflet/ccmd/cmd/config"
+	"github.com/gifflet/ccmd/cmd/diff"
+	cmdexec "github.com/gifflet/ccmd/cmd/exec"
+	"github.com/gifflet/ccmd/cmd/export"
+	cmdfmt "github.com/gifflet/ccmd/cmd/fmt"
+	"github.com/gifflet/ccmd/cmd/history"
+	"github.com/gifflet/ccmd/cmd/hooks"
 	"github.com/gifflet/ccmd/cmd/info"
 	cmdinit "github.com/gifflet/ccmd/cmd/init"
 	"github.com/gifflet/ccmd/cmd/install"
+	"github.com/gifflet/ccmd/cmd/lint"
 	"github.com/gifflet/ccmd/cmd/list"
+	"github.com/gifflet/ccmd/cmd/login"
+	"github.com/gifflet/ccmd/cmd/logout"
+	"github.com/gifflet/ccmd/cmd/migratelayout"
+	"github.com/gifflet/ccmd/cmd/pack"
+	"github.com/gifflet/ccmd/cmd/pin"
+	"github.com/gifflet/ccmd/cmd/plan"
+	"github.com/gifflet/ccmd/cmd/regen"
 	"github.com/gifflet/ccmd/cmd/remove"
+	"github.com/gifflet/ccmd/cmd/restore"
+	"github.com/gifflet/ccmd/cmd/rollback"
+	"github.com/gifflet/ccmd/cmd/scan"
 	"github.com/gifflet/ccmd/cmd/search"
+	"github.com/gifflet/ccmd/cmd/serve"
+	"github.com/gifflet/ccmd/cmd/stats"
+	"github.com/gifflet/ccmd/cmd/status"
 	"github.com/gifflet/ccmd/cmd/sync"
+	"github.com/gifflet/ccmd/cmd/tap"
+	"github.com/gifflet/ccmd/cmd/track"
+	"github.com/gifflet/ccmd/cmd/unpin"
 	"github.com/gifflet/ccmd/cmd/update"
+	"github.com/gifflet/ccmd/cmd/upgradeself"
+	"github.com/gifflet/ccmd/cmd/verify"
+	"github.com/gifflet/ccmd/cmd/why"
+	"github.com/gifflet/ccmd/core"
+	"github.com/gifflet/ccmd/pkg/config"
+	ccmderrors "github.com/gifflet/ccmd/pkg/errors"
+	"github.com/gifflet/ccmd/pkg/i18n"
+	"github.com/gifflet/ccmd/pkg/logger"
 	"github.com/gifflet/ccmd/pkg/output"
+	"github.com/gifflet/ccmd/pkg/telemetry"
 )
 
 // Build information, injected at build time
@@ -32,11 +79,50 @@ var (
 	buildDate = "unknown"
 )
 
+var (
+	insecureSkipVerify bool
+	verboseErrors      bool
+	jsonErrors         bool
+	quiet              bool
+	noColor            bool
+	theme              string
+	projectDir         string
+)
+
 var rootCmd = &cobra.Command{
 	Use:     "ccmd",
 	Short:   "A CLI tool for managing Claude Code commands",
 	Long:    `ccmd is a command-line interface tool designed to help manage Claude Code commands efficiently.`,
 	Version: fmt.Sprintf("%s (commit: %s, built: %s)", version, commit, buildDate),
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		// Flags and arguments are valid by now, so a failure is not a usage problem
+		cmd.SilenceUsage = true
+
+		if err := applyProjectDir(cmd); err != nil {
+			return err
+		}
+		applySettings(cmd)
+		if err := applyOutputFlags(); err != nil {
+			return err
+		}
+		if wd, err := os.Getwd(); err == nil {
+			if root, err := core.LocateProjectRoot(wd); err == nil {
+				output.PrintVerbosef("Project root: %s", root.Describe())
+			}
+		}
+		if err := checkWritable(cmd); err != nil {
+			return err
+		}
+		sweepTempDirs()
+
+		if insecureSkipVerify {
+			core.SetInsecureSkipVerify(true)
+			output.PrintErrorf("WARNING: TLS certificate verification is disabled (--insecure-skip-verify).")
+			output.PrintErrorf("WARNING: Downloads can be intercepted or tampered with. Configure tls.ca_file instead.")
+		}
+		startTelemetry(cmd)
+		return nil
+	},
 	Run: func(cmd *cobra.Command, args []string) {
 		// Default action when no subcommand is provided
 		if err := cmd.Help(); err != nil {
@@ -47,17 +133,338 @@ var rootCmd = &cobra.Command{
 }
 
 func main() {
+	rootCmd.PersistentFlags().BoolVar(&insecureSkipVerify, "insecure-skip-verify", false,
+		"Disable TLS certificate verification for git and HTTP (unsafe)")
+	rootCmd.PersistentFlags().BoolVar(&verboseErrors, "verbose", false,
+		"Show detailed progress, and the error code and full error chain on failure")
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "Print only warnings, errors and results")
+	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "Disable colored output (same as NO_COLOR)")
+	rootCmd.PersistentFlags().StringVar(&theme, "theme", "",
+		"Output theme: auto, unicode or ascii (ASCII marks and spinners for dumb terminals and logs)")
+	rootCmd.PersistentFlags().BoolVar(&jsonErrors, "json", false, "Print errors as JSON with their code")
+	rootCmd.PersistentFlags().StringVar(&projectDir, "project", "",
+		"Project directory to use instead of searching for ccmd.yaml (default $"+core.ProjectDirEnv+")")
+	// Errors are rendered below with their code and hints
+	rootCmd.SilenceErrors = true
+
 	// Register subcommands
+	rootCmd.AddCommand(adopt.NewCommand())
+	rootCmd.AddCommand(audit.NewCommand())
+	rootCmd.AddCommand(browse.NewCommand())
+	rootCmd.AddCommand(cache.NewCommand())
+	rootCmd.AddCommand(ci.NewCommand())
+	rootCmd.AddCommand(cmdconfig.NewCommand())
+	rootCmd.AddCommand(diff.NewCommand())
+	rootCmd.AddCommand(cmdexec.NewCommand())
+	rootCmd.AddCommand(export.NewCommand())
+	rootCmd.AddCommand(cmdfmt.NewCommand())
+	rootCmd.AddCommand(history.NewCommand())
+	rootCmd.AddCommand(hooks.NewCommand())
 	rootCmd.AddCommand(info.NewCommand())
 	rootCmd.AddCommand(cmdinit.NewCommand())
 	rootCmd.AddCommand(install.NewCommand())
+	rootCmd.AddCommand(lint.NewCommand())
 	rootCmd.AddCommand(list.NewCommand())
+	rootCmd.AddCommand(login.NewCommand())
+	rootCmd.AddCommand(logout.NewCommand())
+	rootCmd.AddCommand(migratelayout.NewCommand())
+	rootCmd.AddCommand(pack.NewCommand())
+	rootCmd.AddCommand(pin.NewCommand())
+	rootCmd.AddCommand(plan.NewCommand())
+	rootCmd.AddCommand(regen.NewCommand())
 	rootCmd.AddCommand(remove.NewCommand())
+	rootCmd.AddCommand(restore.NewCommand())
+	rootCmd.AddCommand(rollback.NewCommand())
+	rootCmd.AddCommand(scan.NewCommand())
 	rootCmd.AddCommand(search.NewCommand())
+	rootCmd.AddCommand(serve.NewCommand())
+	rootCmd.AddCommand(stats.NewCommand())
+	rootCmd.AddCommand(status.NewCommand())
 	rootCmd.AddCommand(sync.NewCommand())
+	rootCmd.AddCommand(tap.NewCommand())
+	rootCmd.AddCommand(track.NewCommand())
+	rootCmd.AddCommand(unpin.NewCommand())
 	rootCmd.AddCommand(update.NewCommand())
+	rootCmd.AddCommand(upgradeself.NewCommand(version))
+	rootCmd.AddCommand(verify.NewCommand())
+	rootCmd.AddCommand(why.NewCommand())
+	localize(rootCmd)
+
+	// The first Ctrl+C cancels the running operation so it can clean up; a second one
+	// falls back to the default behaviour and terminates immediately
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	cmd, err := rootCmd.ExecuteContextC(ctx)
+	stop()
+	core.RemoveTempDirs()
+	stopTelemetry(err)
+	closeLogFile(err)
+	if err != nil {
+		switch {
+		case wantsJSON(cmd):
+			output.RenderErrorJSON(err, verboseErrors)
+		case errors.Is(err, context.Canceled):
+			output.PrintWarningf("Operation cancelled")
+		default:
+			output.RenderError(err, verboseErrors)
+		}
+
+		if errors.Is(err, context.Canceled) {
+			os.Exit(130)
+		}
+		// ccmd exec exits with the status of the script it ran
+		var exitErr interface{ ExitCode() int }
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.ExitCode())
+		}
+		os.Exit(1)
+	}
+}
+
+// wantsJSON reports whether the failed command was run with --json, either its own
+// flag for JSON results or the global one for errors only
+func wantsJSON(cmd *cobra.Command) bool {
+	if cmd == nil {
+		return jsonErrors
+	}
+	flag := cmd.Flags().Lookup("json")
+	return flag != nil && flag.Value.String() == "true"
+}
+
+// readOnlyCommands only read the project. In a project that cannot be written they run
+// in no-write mode, where the index is not refreshed on disk.
+var readOnlyCommands = map[string]bool{"info": true, "list": true, "search": true, "status": true}
+
+// writingCommands change the project, and fail before starting when it cannot be written
+var writingCommands = map[string]bool{
+	"adopt": true, "browse": true, "ci": true, "fmt": true, "install": true, "migrate-layout": true,
+	"pin": true, "regen": true, "remove": true, "restore": true, "rollback": true, "sync": true,
+	"track": true, "unpin": true, "update": true,
+}
+
+// checkWritable checks that the project can be written before a command changes it,
+// and puts commands that only read a read-only project into no-write mode
+func checkWritable(cmd *cobra.Command) error {
+	name := cmd.Name()
+	for c := cmd; c.HasParent() && c.Parent() != cmd.Root(); c = c.Parent() {
+		name = c.Parent().Name()
+	}
+	if !readOnlyCommands[name] && !writingCommands[name] {
+		return nil
+	}
+	projectRoot, err := core.FindProjectRoot()
+	if err != nil {
+		return nil
+	}
+
+	err = core.CheckWritable(projectRoot)
+	switch {
+	case err == nil:
+		return nil
+	case writingCommands[name]:
+		return err
+	}
+	output.PrintVerbosef("Read-only project (%v); nothing will be written", err)
+	core.SetNoWrite(projectRoot)
+	return nil
+}
+
+// commandSpan traces the whole invocation when telemetry is enabled
+var commandSpan *telemetry.Span
+
+// startTelemetry starts recording when telemetry.enabled is set, with a span for the
+// command that the spans of its operations nest under
+func startTelemetry(cmd *cobra.Command) {
+	if err := core.EnableTelemetry(version); err != nil {
+		output.PrintWarningf("Ignoring telemetry settings: %v", err)
+		return
+	}
+	var ctx context.Context
+	ctx, commandSpan = telemetry.Start(cmd.Context(), cmd.CommandPath())
+	cmd.SetContext(ctx)
+}
+
+// stopTelemetry ends the command span and exports what was recorded. A collector
+// that cannot be reached never fails the command.
+func stopTelemetry(err error) {
+	commandSpan.End(err)
+	if err := core.FlushTelemetry(); err != nil {
+		output.PrintVerbosef("Telemetry not exported: %v", err)
+	}
+}
+
+// sweepTempDirs removes the temporary directories crashed runs left behind
+func sweepTempDirs() {
+	projectRoot, _ := core.FindProjectRoot()
+	if swept := core.SweepTempDirs(projectRoot); len(swept.Removed) > 0 {
+		output.PrintVerbosef("Removed %d stale temporary directories (%s)", len(swept.Removed), core.FormatSize(swept.Freed))
+	}
+}
+
+// applyProjectDir makes the --project flag, or CCMD_PROJECT_DIR, the project root of
+// every operation. 'ccmd config set' and 'unset' have a --project flag of their own.
+func applyProjectDir(cmd *cobra.Command) error {
+	dir := os.Getenv(core.ProjectDirEnv)
+	if flag := cmd.Flags().Lookup("project"); flag != nil && flag.Value.Type() == "string" && flag.Changed {
+		dir = flag.Value.String()
+	}
+	if dir == "" {
+		return nil
+	}
+	return core.SetProjectDir(dir)
+}
+
+// applySettings loads the layered configuration and applies the global output and
+// logging settings
+func applySettings(cmd *cobra.Command) {
+	projectRoot, _ := core.FindProjectRoot()
+
+	settings, err := config.Load(projectRoot)
+	if err != nil {
+		output.PrintWarningf("Ignoring configuration: %v", err)
+		return
+	}
+	if projectRoot != "" {
+		ignored, _ := config.IgnoredProjectKeys(projectRoot)
+		for _, key := range ignored {
+			output.PrintWarningf("Ignoring %s in %s: it can only be set in the user config or with %s",
+				key, config.ProjectFileName, config.EnvName(key))
+		}
+	}
+
+	output.SetColor(settings.Color)
+	if err := output.SetTheme(settings.Theme); err != nil {
+		output.PrintWarningf("Ignoring theme setting: %v", err)
+	}
+
+	logger.SetLevel(settings.LogLevel)
+	openLogFile(cmd, settings)
+}
+
+// localize selects the locale of the locale setting or the environment and translates
+// the help of every command. Help is rendered before flags are applied, so the setting
+// is read for the project of the working directory even with --project.
+func localize(root *cobra.Command) {
+	setting := ""
+	projectRoot, _ := core.FindProjectRoot()
+	if settings, err := config.Load(projectRoot); err == nil {
+		setting = settings.Locale
+	}
+	if err := i18n.SetLocale(i18n.Detect(setting)); err != nil && setting != "" {
+		output.PrintWarningf("Ignoring locale setting: %v", err)
+	}
+	if i18n.Locale() == i18n.DefaultLocale {
+		return
+	}
+
+	root.InitDefaultHelpCmd()
+	root.InitDefaultVersionFlag()
+	root.SetUsageTemplate(strings.NewReplacer(
+		"Usage:", i18n.T("Usage:"),
+		"Aliases:", i18n.T("Aliases:"),
+		"Examples:", i18n.T("Examples:"),
+		"Available Commands:", i18n.T("Available Commands:"),
+		"Additional Commands:", i18n.T("Additional Commands:"),
+		"Global Flags:", i18n.T("Global Flags:"),
+		"Flags:", i18n.T("Flags:"),
+		"Additional help topics:", i18n.T("Additional help topics:"),
+		`Use "{{.CommandPath}} [command] --help" for more information about a command.`,
+		strings.Replace(i18n.T(`Use "%s [command] --help" for more information about a command.`), "%s", "{{.CommandPath}}", 1),
+	).Replace(root.UsageTemplate()))
+	localizeCommand(root)
+}
+
+// localizeCommand translates the help texts and flag descriptions of cmd and its
+// subcommands
+func localizeCommand(cmd *cobra.Command) {
+	cmd.Short = i18n.T(cmd.Short)
+	cmd.Long = i18n.T(cmd.Long)
+	cmd.Example = i18n.T(cmd.Example)
+
+	cmd.InitDefaultHelpFlag()
+	translate := func(flag *pflag.Flag) {
+		switch {
+		case flag.Name == "help" && flag.Usage == "help for "+cmd.Name():
+			flag.Usage = i18n.Sprintf("help for %s", cmd.Name())
+		case flag.Name == "version" && flag.Usage == "version for "+cmd.Name():
+			flag.Usage = i18n.Sprintf("version for %s", cmd.Name())
+		default:
+			flag.Usage = i18n.T(flag.Usage)
+		}
+	}
+	cmd.Flags().VisitAll(translate)
+	cmd.PersistentFlags().VisitAll(translate)
+
+	for _, sub := range cmd.Commands() {
+		localizeCommand(sub)
+	}
+}
+
+// closeLogFile records how the invocation ended and closes the log file, if one is open
+var closeLogFile = func(error) {}
+
+// openLogFile sends the log records of this invocation to the log.file setting as JSON,
+// tagged with a correlation ID so the records of one run can be found together
+func openLogFile(cmd *cobra.Command, settings *config.Settings) {
+	if settings.Log.File == "" {
+		return
+	}
+	path := settings.Log.File
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(settings.CacheDir, "logs", path)
+	}
+	file, err := logger.OpenRotatingFile(path, int64(settings.Log.MaxSizeMB)<<20, settings.Log.MaxFiles)
+	if err != nil {
+		output.PrintWarningf("Ignoring log file: %v", err)
+		return
+	}
+	restore := logger.SetFile(file, logger.Fields{
+		"correlation_id": logger.NewCorrelationID(),
+		"ccmd_version":   version,
+	})
+
+	// Arguments and flag values are left out: they can hold secrets
+	var flags []string
+	cmd.Flags().Visit(func(f *pflag.Flag) { flags = append(flags, f.Name) })
+	log := logger.WithFields(logger.Fields{"command": cmd.CommandPath(), "flags": flags})
+	log.Debug("Command started")
+
+	start := time.Now()
+	closeLogFile = func(err error) {
+		log = log.WithField("duration_ms", time.Since(start).Milliseconds())
+		if err != nil {
+			log.WithError(err).Debug("Command failed")
+		} else {
+			log.Debug("Command finished")
+		}
+		restore()
+		_ = file.Close()
+	}
+}
+
+// applyOutputFlags applies the global output flags, which take precedence over the
+// color and theme settings
+func applyOutputFlags() error {
+	if noColor {
+		output.SetColor(config.ColorNever)
+	}
+	if theme != "" {
+		if err := output.SetTheme(theme); err != nil {
+			return ccmderrors.InvalidInput(err.Error())
+		}
+	}
 
-	if err := rootCmd.Execute(); err != nil {
-		output.Fatalf("Command failed: %v", err)
+	switch {
+	case quiet && verboseErrors:
+		return ccmderrors.InvalidInput("--quiet and --verbose cannot be combined")
+	case quiet:
+		output.SetLevel(output.LevelQuiet)
+	case verboseErrors:
+		output.SetLevel(output.LevelVerbose)
 	}
+	return nil
 }
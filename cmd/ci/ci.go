@@ -0,0 +1,190 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package ci
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gifflet/ccmd/core"
+	"github.com/gifflet/ccmd/pkg/output"
+)
+
+// checks lists the checks of a CI run with their summary labels, in order
+var checks = []struct{ name, label string }{
+	{core.CICheckLock, "Lock file matches ccmd.yaml"},
+	{core.CICheckSync, "Frozen sync"},
+	{core.CICheckIntegrity, "Installed files match checksums"},
+	{core.CICheckPolicy, "Policy (allowed_hosts, size_limit_mb, allowed_licenses)"},
+}
+
+// NewCommand creates a new ci command.
+func NewCommand() *cobra.Command {
+	var (
+		profile    string
+		jsonFormat bool
+		github     bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "ci",
+		Short: "Install from the lock file and run every check a pipeline needs",
+		Long: `Run the checks a CI pipeline needs in one step:
+
+- lock: ccmd-lock.yaml must match ccmd.yaml (as with 'ccmd sync --frozen')
+- sync: install the locked commands without writing ccmd.yaml or ccmd-lock.yaml
+- integrity: installed files must match the checksums in ccmd-lock.yaml, and
+  standalone .md files must be current (as with 'ccmd verify')
+- policy: command sources must come from the allowed_hosts setting, when set,
+  stay below size_limit_mb and have a license in allowed_licenses, when set
+
+When lock file drift is found the other checks are skipped. Exits with an error
+when any problem is found.
+
+In GitHub Actions (GITHUB_ACTIONS=true) or with --github, problems are printed as
+workflow annotations that point at the ccmd.yaml entry or the installed files, and
+a summary table is appended to $GITHUB_STEP_SUMMARY when it is set.
+
+Example workflow step:
+  - run: ccmd ci`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			annotate := github || os.Getenv("GITHUB_ACTIONS") == "true"
+			return runCI(cmd.Context(), profile, jsonFormat, annotate)
+		},
+	}
+
+	cmd.Flags().StringVar(&profile, "profile", "", "Also install and check the commands of this ccmd.yaml profile")
+	cmd.Flags().BoolVar(&jsonFormat, "json", false, "Output the report in JSON format")
+	cmd.Flags().BoolVar(&github, "github", false, "Print GitHub Actions annotations outside GitHub Actions")
+
+	return cmd
+}
+
+func runCI(ctx context.Context, profile string, jsonFormat, annotate bool) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	report, err := core.CI(ctx, core.CIOptions{ProjectPath: cwd, Profile: profile})
+	if err != nil {
+		return fmt.Errorf("ci failed: %w", err)
+	}
+
+	switch {
+	case jsonFormat:
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+	case annotate:
+		for _, finding := range report.Findings {
+			output.Printf("%s", annotation(finding))
+		}
+	default:
+		for _, finding := range report.Findings {
+			output.PrintErrorf("%s %s: %s", finding.Check, finding.Name, finding.Message)
+		}
+	}
+
+	if path := os.Getenv("GITHUB_STEP_SUMMARY"); path != "" && annotate {
+		if err := appendSummary(path, report); err != nil {
+			output.PrintWarningf("Failed to write the step summary: %v", err)
+		}
+	}
+
+	if !report.OK() {
+		return fmt.Errorf("%d problem(s) found", len(report.Findings))
+	}
+	if !jsonFormat {
+		output.PrintSuccessf("All checks passed for %d installed command(s) and plugin(s)", report.Checked)
+	}
+	return nil
+}
+
+// annotation formats a finding as a GitHub Actions error workflow command
+func annotation(finding core.CIFinding) string {
+	props := []string{"file=" + escapeProperty(finding.File)}
+	if finding.Line > 0 {
+		props = append(props, fmt.Sprintf("line=%d", finding.Line))
+	}
+	props = append(props, "title="+escapeProperty("ccmd ci: "+finding.Check))
+
+	return fmt.Sprintf("::error %s::%s", strings.Join(props, ","), escapeData(finding.Name+": "+finding.Message))
+}
+
+// escapeData escapes the message of a workflow command
+func escapeData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// escapeProperty escapes a property value of a workflow command
+func escapeProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
+
+func appendSummary(path string, report *core.CIReport) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return writeSummary(f, report)
+}
+
+// writeSummary writes the report as markdown for the job summary: one row per check,
+// then the problems found
+func writeSummary(w io.Writer, report *core.CIReport) error {
+	var b strings.Builder
+	b.WriteString("## ccmd ci\n\n| Check | Result |\n|-------|--------|\n")
+
+	skipped := report.Count(core.CICheckLock) > 0
+	for _, check := range checks {
+		result := "✅ passed"
+		switch n := report.Count(check.name); {
+		case n > 0:
+			result = fmt.Sprintf("❌ %d problem(s)", n)
+		case skipped:
+			result = "⏭️ skipped"
+		}
+		fmt.Fprintf(&b, "| %s | %s |\n", check.label, result)
+	}
+
+	if report.OK() {
+		fmt.Fprintf(&b, "\n%d command(s) and plugin(s) checked, %d installed.\n", report.Checked, len(report.Installed))
+	} else {
+		b.WriteString("\n| Check | Name | File | Problem |\n|-------|------|------|---------|\n")
+		for _, finding := range report.Findings {
+			file := finding.File
+			if finding.Line > 0 {
+				file = fmt.Sprintf("%s:%d", file, finding.Line)
+			}
+			fmt.Fprintf(&b, "| %s | %s | `%s` | %s |\n",
+				finding.Check, escapeCell(finding.Name), file, escapeCell(finding.Message))
+		}
+	}
+	b.WriteString("\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// escapeCell keeps text inside one markdown table cell
+func escapeCell(s string) string {
+	return strings.NewReplacer("|", `\|`, "\n", "<br>").Replace(s)
+}
@@ -0,0 +1,66 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package ci
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gifflet/ccmd/core"
+)
+
+func TestNewCommand(t *testing.T) {
+	cmd := NewCommand()
+
+	assert.Equal(t, "ci", cmd.Use)
+	assert.NotEmpty(t, cmd.Short)
+	assert.NotEmpty(t, cmd.Long)
+	for _, name := range []string{"profile", "json", "github"} {
+		assert.NotNil(t, cmd.Flags().Lookup(name), name)
+	}
+	assert.NoError(t, cmd.Args(cmd, []string{}))
+	assert.Error(t, cmd.Args(cmd, []string{"extra"}))
+}
+
+func TestAnnotation(t *testing.T) {
+	finding := core.CIFinding{
+		Check:   core.CICheckPolicy,
+		Name:    "review",
+		File:    "ccmd.yaml",
+		Line:    4,
+		Message: "installed size 60%\nabove the limit",
+	}
+
+	assert.Equal(t,
+		"::error file=ccmd.yaml,line=4,title=ccmd ci%3A policy::review: installed size 60%25%0Aabove the limit",
+		annotation(finding))
+
+	finding.Line = 0
+	assert.NotContains(t, annotation(finding), "line=")
+}
+
+func TestWriteSummary(t *testing.T) {
+	var b strings.Builder
+	require.NoError(t, writeSummary(&b, &core.CIReport{Checked: 2, Installed: []string{"acme/review"}}))
+	assert.Contains(t, b.String(), "| Frozen sync | ✅ passed |")
+	assert.Contains(t, b.String(), "2 command(s) and plugin(s) checked, 1 installed.")
+
+	b.Reset()
+	report := &core.CIReport{Findings: []core.CIFinding{
+		{Check: core.CICheckLock, Name: "acme/review", File: "ccmd.yaml", Line: 3, Message: "a | b"},
+	}}
+	require.NoError(t, writeSummary(&b, report))
+	assert.Contains(t, b.String(), "| Lock file matches ccmd.yaml | ❌ 1 problem(s) |")
+	assert.Contains(t, b.String(), "| Frozen sync | ⏭️ skipped |")
+	assert.Contains(t, b.String(), "| lock | acme/review | `ccmd.yaml:3` | a \\| b |")
+}
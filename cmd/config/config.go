@@ -0,0 +1,217 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gifflet/ccmd/core"
+	settings "github.com/gifflet/ccmd/pkg/config"
+	"github.com/gifflet/ccmd/pkg/errors"
+	"github.com/gifflet/ccmd/pkg/output"
+)
+
+// NewCommand creates a new config command.
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Read and write ccmd settings",
+		Long: `Read and write ccmd settings.
+
+Settings are resolved from, in increasing priority:
+  1. built-in defaults
+  2. the user config file ($CCMD_CONFIG, or ~/.config/ccmd/config.yaml)
+  3. .ccmdrc.yaml in the project root
+  4. CCMD_<KEY> environment variables (e.g. CCMD_LOG_LEVEL)
+
+cache_dir, log.file, mirrors, taps and the proxy, tls, telemetry and tmp sections
+can only be set in the user config or the environment; .ccmdrc.yaml is shared with
+everyone who clones the project, so ccmd ignores them there.
+
+Available keys: ` + strings.Join(settings.Keys(), ", "),
+	}
+
+	cmd.AddCommand(newGetCommand())
+	cmd.AddCommand(newSetCommand())
+	cmd.AddCommand(newUnsetCommand())
+	cmd.AddCommand(newListCommand())
+
+	return cmd
+}
+
+func newGetCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "get <key>",
+		Short: "Print the effective value of a setting",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runGet(args[0])
+		},
+	}
+}
+
+func newSetCommand() *cobra.Command {
+	var project bool
+
+	cmd := &cobra.Command{
+		Use:   "set <key> <value>",
+		Short: "Store a setting in the user or project config file",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSet(args[0], args[1], project)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&project, "project", "p", false, "Write to "+settings.ProjectFileName+" instead of the user config")
+
+	return cmd
+}
+
+func newUnsetCommand() *cobra.Command {
+	var project bool
+
+	cmd := &cobra.Command{
+		Use:   "unset <key>",
+		Short: "Remove a setting from the user or project config file",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runUnset(args[0], project)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&project, "project", "p", false, "Remove from "+settings.ProjectFileName+" instead of the user config")
+
+	return cmd
+}
+
+func newListCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List effective settings and where each one comes from",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runList()
+		},
+	}
+}
+
+func runGet(key string) error {
+	projectRoot, err := core.FindProjectRoot()
+	if err != nil {
+		return err
+	}
+
+	s, err := settings.Load(projectRoot)
+	if err != nil {
+		return err
+	}
+
+	value, err := settings.Get(s, key)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(value)
+	return nil
+}
+
+func runSet(key, value string, project bool) error {
+	if project && settings.UserOnly(key) {
+		return errors.InvalidInput(fmt.Sprintf("%s can only be set in the user config or with %s, not in %s",
+			key, settings.EnvName(key), settings.ProjectFileName))
+	}
+
+	path, err := targetPath(project)
+	if err != nil {
+		return err
+	}
+
+	values, err := settings.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	if err := settings.SetValue(values, key, value); err != nil {
+		return err
+	}
+
+	if err := settings.WriteFile(path, values); err != nil {
+		return err
+	}
+
+	output.PrintSuccessf("Set %s = %s in %s", key, value, path)
+	return nil
+}
+
+func runUnset(key string, project bool) error {
+	if !settings.IsKey(key) {
+		return fmt.Errorf("unknown config key %q", key)
+	}
+
+	path, err := targetPath(project)
+	if err != nil {
+		return err
+	}
+
+	values, err := settings.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	if !settings.UnsetValue(values, key) {
+		output.PrintInfof("%s is not set in %s", key, path)
+		return nil
+	}
+
+	if err := settings.WriteFile(path, values); err != nil {
+		return err
+	}
+
+	output.PrintSuccessf("Removed %s from %s", key, path)
+	return nil
+}
+
+func runList() error {
+	projectRoot, err := core.FindProjectRoot()
+	if err != nil {
+		return err
+	}
+
+	s, sources, err := settings.LoadWithSources(projectRoot)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range settings.Keys() {
+		value, err := settings.Get(s, key)
+		if err != nil {
+			return err
+		}
+		output.Printf("%-14s %-30s (%s)", key, value, sources[key])
+	}
+
+	return nil
+}
+
+// targetPath returns the config file that set/unset should modify
+func targetPath(project bool) (string, error) {
+	if !project {
+		return settings.UserConfigPath()
+	}
+
+	projectRoot, err := core.FindProjectRoot()
+	if err != nil {
+		return "", err
+	}
+	return settings.ProjectConfigPath(projectRoot), nil
+}
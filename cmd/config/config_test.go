@@ -0,0 +1,68 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	settings "github.com/gifflet/ccmd/pkg/config"
+)
+
+func TestNewCommand(t *testing.T) {
+	cmd := NewCommand()
+
+	assert.Equal(t, "config", cmd.Use)
+	assert.NotEmpty(t, cmd.Short)
+	assert.NotEmpty(t, cmd.Long)
+
+	names := make([]string, 0, len(cmd.Commands()))
+	for _, sub := range cmd.Commands() {
+		names = append(names, sub.Name())
+	}
+	assert.ElementsMatch(t, []string{"get", "set", "unset", "list"}, names)
+
+	set, _, err := cmd.Find([]string{"set"})
+	require.NoError(t, err)
+	projectFlag := set.Flags().Lookup("project")
+	require.NotNil(t, projectFlag)
+	assert.Equal(t, "false", projectFlag.DefValue)
+}
+
+func TestRunSetAndUnset(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	t.Setenv(settings.ConfigEnv, path)
+
+	require.NoError(t, runSet("jobs", "3", false))
+
+	values, err := settings.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, 3, values["jobs"])
+
+	assert.Error(t, runSet("jobs", "many", false))
+	assert.Error(t, runSet("nope", "1", false))
+
+	require.NoError(t, runUnset("jobs", false))
+	values, err = settings.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, values, "jobs")
+}
+
+func TestRunSetProjectRefusesUserOnlyKeys(t *testing.T) {
+	t.Setenv(settings.ConfigEnv, filepath.Join(t.TempDir(), "config.yaml"))
+
+	err := runSet("proxy.https", "http://proxy.corp:3128", true)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "CCMD_PROXY_HTTPS")
+	assert.Error(t, runSet("cache_dir", "..", true))
+}
@@ -0,0 +1,156 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gifflet/ccmd/core"
+	"github.com/gifflet/ccmd/pkg/output"
+)
+
+// maxStatWidth caps the +/- bar of --stat
+const maxStatWidth = 40
+
+// NewCommand creates a new diff command.
+func NewCommand() *cobra.Command {
+	var (
+		version    string
+		stat       bool
+		jsonFormat bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "diff <command-name>",
+		Short: "Show local changes to an installed command",
+		Long: `Compare an installed command with its source.
+
+The source is fetched at the commit recorded in ccmd-lock.yaml (or at --version)
+and compared with the files under .claude/commands/<name>. Lines starting with '+'
+exist only in the installed copy, so local edits show up as additions. Run it
+before 'ccmd update' or 'ccmd install --force', which replace the installed files.
+
+Examples:
+  # Show local modifications as a unified diff
+  ccmd diff hello
+
+  # Summarize changed files
+  ccmd diff hello --stat
+
+  # Compare with another version of the source
+  ccmd diff hello --version v2.0.0`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cwd, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+
+			spinner := output.NewSpinner("Fetching source...")
+			if !jsonFormat {
+				spinner.Start()
+			}
+			report, err := core.Diff(cmd.Context(), core.DiffOptions{
+				Name:        args[0],
+				Version:     version,
+				ProjectPath: cwd,
+			})
+			spinner.Stop()
+			if err != nil {
+				return err
+			}
+
+			switch {
+			case jsonFormat:
+				data, err := json.MarshalIndent(report, "", "  ")
+				if err != nil {
+					return err
+				}
+				output.Printf("%s", string(data))
+			case !report.Modified():
+				output.PrintSuccessf("✓ %s matches %s@%s", report.Name, report.Repository, shortRef(report.Ref))
+			case stat:
+				printStat(report)
+			default:
+				printPatch(report)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&version, "version", "v", "", "Version/tag to compare against instead of the locked commit")
+	cmd.Flags().BoolVar(&stat, "stat", false, "Show a summary of changed files instead of the full diff")
+	cmd.Flags().BoolVar(&jsonFormat, "json", false, "Output the report in JSON format")
+
+	return cmd
+}
+
+func printPatch(report *core.DiffReport) {
+	for _, file := range report.Files {
+		for _, line := range strings.Split(strings.TrimSuffix(file.Patch, "\n"), "\n") {
+			switch {
+			case strings.HasPrefix(line, "--- "), strings.HasPrefix(line, "+++ "):
+				output.Printf("%s", output.Bold(line))
+			case strings.HasPrefix(line, "@@"):
+				output.Printf("%s", output.Info(line))
+			case strings.HasPrefix(line, "+"):
+				output.Printf("%s", output.Success(line))
+			case strings.HasPrefix(line, "-"):
+				output.Printf("%s", output.Error(line))
+			default:
+				output.Printf("%s", line)
+			}
+		}
+	}
+}
+
+func printStat(report *core.DiffReport) {
+	width, most, added, removed := 0, 0, 0, 0
+	for _, file := range report.Files {
+		width = max(width, len(file.Path))
+		most = max(most, file.Added+file.Removed)
+		added += file.Added
+		removed += file.Removed
+	}
+
+	for _, file := range report.Files {
+		plus, minus := file.Added, file.Removed
+		if most > maxStatWidth {
+			plus = (plus*maxStatWidth + most - 1) / most
+			minus = (minus*maxStatWidth + most - 1) / most
+		}
+
+		count := fmt.Sprintf("%d", file.Added+file.Removed)
+		if file.Patch != "" && file.Added+file.Removed == 0 {
+			count = "Bin"
+		}
+		output.Printf(" %-*s | %4s %s%s", width, file.Path, count,
+			output.Success(strings.Repeat("+", plus)), output.Error(strings.Repeat("-", minus)))
+	}
+
+	output.Printf(" %d file(s) changed, %d insertion(s)(+), %d deletion(s)(-)", len(report.Files), added, removed)
+}
+
+// shortRef abbreviates commit hashes and archive digests
+func shortRef(ref string) string {
+	if len(ref) > 19 && strings.HasPrefix(ref, "sha256:") {
+		return ref[:19]
+	}
+	if len(ref) == 40 {
+		return ref[:12]
+	}
+	return ref
+}
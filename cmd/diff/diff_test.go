@@ -0,0 +1,38 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCommand(t *testing.T) {
+	cmd := NewCommand()
+
+	assert.Equal(t, "diff <command-name>", cmd.Use)
+	assert.NotEmpty(t, cmd.Short)
+	assert.NotEmpty(t, cmd.Long)
+
+	for _, flag := range []string{"version", "stat", "json"} {
+		assert.NotNil(t, cmd.Flags().Lookup(flag), flag)
+	}
+	assert.Equal(t, "v", cmd.Flags().Lookup("version").Shorthand)
+
+	assert.NoError(t, cmd.Args(cmd, []string{"hello"}))
+	assert.Error(t, cmd.Args(cmd, []string{}))
+}
+
+func TestShortRef(t *testing.T) {
+	assert.Equal(t, "0123456789ab", shortRef("0123456789abcdef0123456789abcdef01234567"))
+	assert.Equal(t, "sha256:9f86d081884c", shortRef("sha256:9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"))
+	assert.Equal(t, "v1.0.0", shortRef("v1.0.0"))
+}
@@ -0,0 +1,90 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package cmdexec
+
+import (
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gifflet/ccmd/core"
+)
+
+// NewCommand creates a new exec command.
+func NewCommand() *cobra.Command {
+	var (
+		env     []string
+		timeout time.Duration
+		sandbox bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "exec <command> [args...]",
+		Short: "Run the helper script of an installed command",
+		Long: `Run the helper script an installed command declares with exec in its ccmd.yaml.
+
+The script path is relative to the command directory and must stay inside it. The
+script runs in the project root with the arguments after the command name. Only
+a small set of environment variables (PATH, HOME, USER, SHELL, TERM, locale and
+temporary directory settings) is passed through; use --env to pass more. The
+script also gets CCMD_COMMAND, CCMD_COMMAND_DIR, CCMD_COMMAND_VERSION and
+CCMD_PROJECT_ROOT. ccmd exits with the exit status of the script.
+
+With sandbox.enabled set, or --sandbox, the script gets a temporary HOME and the
+resource limits of the sandbox settings, and runs without network access unless
+sandbox.network is set.
+
+Flags for ccmd go before the command name; everything after it is passed to the
+script as is.
+
+Examples:
+  # Run the script of the release command
+  ccmd exec release --dry-run
+
+  # Pass a token and allow the script ten minutes
+  ccmd exec --env GITHUB_TOKEN --timeout 10m release v1.2.0
+
+  # Set a variable for the script
+  ccmd exec --env MODE=ci release
+
+  # Run a script from an unfamiliar source in the sandbox
+  ccmd exec --sandbox release --dry-run`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cwd, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+
+			if timeout == 0 {
+				timeout = -1
+			}
+			return core.Exec(cmd.Context(), core.ExecOptions{
+				ProjectPath: cwd,
+				Name:        args[0],
+				Args:        args[1:],
+				Env:         env,
+				Timeout:     timeout,
+				Sandbox:     sandbox,
+				Stdin:       os.Stdin,
+				Stdout:      os.Stdout,
+				Stderr:      os.Stderr,
+			})
+		},
+	}
+
+	cmd.Flags().SetInterspersed(false)
+	cmd.Flags().StringArrayVarP(&env, "env", "e", nil, "Pass an environment variable to the script (NAME or NAME=value, repeatable)")
+	cmd.Flags().BoolVar(&sandbox, "sandbox", false, "Run the script in the sandbox even when sandbox.enabled is off")
+	cmd.Flags().DurationVar(&timeout, "timeout", core.DefaultExecTimeout, "Stop the script after this long (0 for no limit)")
+
+	return cmd
+}
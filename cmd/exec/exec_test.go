@@ -0,0 +1,46 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package cmdexec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gifflet/ccmd/core"
+)
+
+func TestNewCommand(t *testing.T) {
+	cmd := NewCommand()
+
+	assert.Equal(t, "exec <command> [args...]", cmd.Use)
+	assert.NotEmpty(t, cmd.Short)
+	assert.NotEmpty(t, cmd.Long)
+
+	for _, flag := range []string{"env", "timeout", "sandbox"} {
+		assert.NotNil(t, cmd.Flags().Lookup(flag), flag)
+	}
+	assert.Equal(t, core.DefaultExecTimeout.String(), cmd.Flags().Lookup("timeout").DefValue)
+
+	assert.NoError(t, cmd.Args(cmd, []string{"release"}))
+	assert.NoError(t, cmd.Args(cmd, []string{"release", "--dry-run", "v1"}))
+	assert.Error(t, cmd.Args(cmd, []string{}))
+}
+
+func TestFlagsStopAtCommandName(t *testing.T) {
+	cmd := NewCommand()
+
+	require.NoError(t, cmd.Flags().Parse([]string{"--env", "TOKEN", "release", "--timeout", "1s", "-e"}))
+	assert.Equal(t, []string{"release", "--timeout", "1s", "-e"}, cmd.Flags().Args())
+	env, err := cmd.Flags().GetStringArray("env")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"TOKEN"}, env)
+}
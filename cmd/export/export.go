@@ -0,0 +1,97 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package export
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gifflet/ccmd/core"
+	"github.com/gifflet/ccmd/pkg/output"
+)
+
+// NewCommand creates a new export command.
+func NewCommand() *cobra.Command {
+	var (
+		targets []string
+		save    bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "export [command-name]",
+		Short: "Export installed commands for other AI tools",
+		Long: `Export installed commands in the format other AI tools read.
+
+Each target is a type, optionally followed by a directory relative to the
+project root (type:path):
+
+  claude           .claude/commands/<name>.md
+  cursor           .cursor/rules/<name>.mdc (Cursor rules)
+  cursor-commands  .cursor/commands/<name>.md (Cursor custom commands)
+  agents           .agents/commands/<name>/AGENTS.md
+  markdown:<path>  <path>/<name>.md
+
+Without a name, every installed command is exported. Use --save to add the
+targets to ccmd.yaml, so install, update and sync keep the exported files current.`,
+		Example: `  # Write Cursor rules for every installed command
+  ccmd export --target cursor
+
+  # Export one command as AGENTS.md and keep it current on install and update
+  ccmd export review --target agents --save`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var name string
+			if len(args) > 0 {
+				name = args[0]
+			}
+			return runExport(name, targets, save)
+		},
+	}
+
+	cmd.Flags().StringSliceVarP(&targets, "target", "t", nil, "Target to export to, as type or type:path (repeatable)")
+	cmd.Flags().BoolVar(&save, "save", false, "Add the targets to ccmd.yaml")
+	_ = cmd.MarkFlagRequired("target")
+
+	return cmd
+}
+
+func runExport(name string, targets []string, save bool) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	result, err := core.Export(core.ExportOptions{ProjectPath: cwd, Name: name, Targets: targets, Save: save})
+	if err != nil {
+		return err
+	}
+
+	for _, file := range result.Files {
+		output.PrintSuccessf("Exported %s", file)
+	}
+	for _, failure := range result.Failed {
+		output.PrintErrorf("Failed to export %s: %v", failure.Command, failure.Error)
+	}
+
+	if len(result.Files) == 0 && len(result.Failed) == 0 {
+		output.PrintInfof("No installed commands to export.")
+	}
+	if save {
+		output.PrintInfof("Saved targets to ccmd.yaml")
+	}
+
+	if len(result.Failed) > 0 {
+		return fmt.Errorf("%d command(s) failed to export", len(result.Failed))
+	}
+
+	return nil
+}
@@ -0,0 +1,36 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package export
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCommand(t *testing.T) {
+	cmd := NewCommand()
+
+	assert.Equal(t, "export [command-name]", cmd.Use)
+	assert.NotEmpty(t, cmd.Short)
+	assert.NotEmpty(t, cmd.Long)
+	assert.NoError(t, cmd.Args(cmd, []string{}))
+	assert.NoError(t, cmd.Args(cmd, []string{"name"}))
+	assert.Error(t, cmd.Args(cmd, []string{"a", "b"}))
+
+	targetFlag := cmd.Flags().Lookup("target")
+	assert.NotNil(t, targetFlag)
+	assert.Equal(t, "t", targetFlag.Shorthand)
+	assert.Equal(t, "[]", targetFlag.DefValue)
+
+	saveFlag := cmd.Flags().Lookup("save")
+	assert.NotNil(t, saveFlag)
+	assert.Equal(t, "false", saveFlag.DefValue)
+}
@@ -0,0 +1,94 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package cmdfmt
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gifflet/ccmd/core"
+	"github.com/gifflet/ccmd/pkg/output"
+)
+
+// NewCommand creates a new fmt command.
+func NewCommand() *cobra.Command {
+	var check, jsonFormat bool
+
+	cmd := &cobra.Command{
+		Use:   "fmt",
+		Short: "Normalize the entries of ccmd.yaml",
+		Long: `Rewrite ccmd.yaml in a canonical form:
+
+  - Entries of commands, plugins and profiles are written the way install records
+    them: owner/repo for the default host, host.tld/owner/repo for others, with
+    the case of owner and repository kept, then @version and 'as name'
+  - Version ranges are spaced consistently, as in ^1.2.0 || >=2.0.0 <3.0.0
+  - Entries written as mappings with nothing but a repo become plain entries
+  - Each list is sorted by repository and profiles are sorted by name
+
+Comments move with the entries they belong to. Entries that do not parse are
+only trimmed; 'ccmd sync' reports them.
+
+Use --check in CI to exit with an error when ccmd.yaml is not formatted, without
+changing it.`,
+		Example: `  # Format ccmd.yaml
+  ccmd fmt
+
+  # Fail when ccmd.yaml is not formatted
+  ccmd fmt --check`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runFmt(check, jsonFormat)
+		},
+	}
+
+	cmd.Flags().BoolVar(&check, "check", false, "Exit with an error when ccmd.yaml is not formatted, without changing it")
+	cmd.Flags().BoolVar(&jsonFormat, "json", false, "Output the result in JSON format")
+
+	return cmd
+}
+
+func runFmt(check, jsonFormat bool) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	result, err := core.Format(core.FormatOptions{ProjectPath: cwd, Check: check})
+	if err != nil {
+		return err
+	}
+
+	if jsonFormat {
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+	} else {
+		for _, change := range result.Changes {
+			output.PrintInfof("%s", change)
+		}
+		switch {
+		case !result.Changed:
+			output.PrintSuccessf("%s is formatted", core.ConfigFileName)
+		case !check:
+			output.PrintSuccessf("Formatted %s", core.ConfigFileName)
+		}
+	}
+
+	if check && result.Changed {
+		return fmt.Errorf("%s is not formatted; run 'ccmd fmt'", core.ConfigFileName)
+	}
+	return nil
+}
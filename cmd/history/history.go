@@ -0,0 +1,88 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gifflet/ccmd/core"
+	"github.com/gifflet/ccmd/pkg/output"
+)
+
+// NewCommand creates a new history command.
+func NewCommand() *cobra.Command {
+	var jsonFormat bool
+
+	cmd := &cobra.Command{
+		Use:   "history <command-name>",
+		Short: "Show the versions a command was installed at",
+		Long: fmt.Sprintf(`Show the installed version of a command and the versions it had before.
+
+Whenever an update, install or sync changes the commit of a command, the version
+it replaces is recorded in ccmd-lock.yaml, up to the last %d. Any of them can be
+reinstalled with 'ccmd rollback <command-name> --to <version>'.`, core.HistoryLimit),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runHistory(args[0], jsonFormat)
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonFormat, "json", false, "Output in JSON format")
+
+	return cmd
+}
+
+func runHistory(name string, jsonFormat bool) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	history, err := core.History(name, cwd)
+	if err != nil {
+		return err
+	}
+
+	if jsonFormat {
+		data, err := json.MarshalIndent(history, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	header := fmt.Sprintf("%-20s %-9s %s", "VERSION", "COMMIT", "INSTALLED")
+	output.Printf(header)
+	output.Printf("%s", formatEntry(history.Current)+"  "+output.Success("(current)"))
+	for _, entry := range history.Previous {
+		output.Printf("%s", formatEntry(entry))
+	}
+
+	if len(history.Previous) == 0 {
+		output.PrintInfof("\nNo previous versions recorded.")
+	} else {
+		output.PrintInfof("\nRoll back with 'ccmd rollback %s [--to <version>]'.", name)
+	}
+	return nil
+}
+
+// formatEntry formats one row of the history table
+func formatEntry(entry core.LockHistory) string {
+	installed := "-"
+	if !entry.UpdatedAt.IsZero() {
+		installed = entry.UpdatedAt.Local().Format("2006-01-02 15:04")
+	}
+	return fmt.Sprintf("%-20s %-9.7s %s", entry.Ref(), entry.Commit, installed)
+}
@@ -0,0 +1,43 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package history
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gifflet/ccmd/core"
+)
+
+func TestNewCommand(t *testing.T) {
+	cmd := NewCommand()
+
+	assert.Equal(t, "history <command-name>", cmd.Use)
+	assert.NotEmpty(t, cmd.Short)
+	assert.Error(t, cmd.Args(cmd, []string{}))
+	assert.NoError(t, cmd.Args(cmd, []string{"tool"}))
+
+	jsonFlag := cmd.Flags().Lookup("json")
+	assert.NotNil(t, jsonFlag)
+	assert.Equal(t, "false", jsonFlag.DefValue)
+}
+
+func TestPrintEntry(t *testing.T) {
+	entry := core.LockHistory{
+		Resolved: "acme/tool@v1.2.0",
+		Commit:   "a76c96359914b84ed1bcdbc11df03e6313e09ecf",
+	}
+	assert.Equal(t, "v1.2.0               a76c963   -", formatEntry(entry))
+
+	entry.UpdatedAt = time.Date(2025, 3, 1, 12, 30, 0, 0, time.Local)
+	assert.Contains(t, formatEntry(entry), "2025-03-01 12:30")
+}
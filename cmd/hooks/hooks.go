@@ -0,0 +1,120 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package hooks
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gifflet/ccmd/core"
+	"github.com/gifflet/ccmd/pkg/output"
+)
+
+// NewCommand creates a new hooks command.
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "hooks",
+		Short: "Install git hooks that keep commands in sync",
+		Long: `Manage git hooks that keep a team's commands in sync with ccmd.yaml.
+
+'ccmd hooks install' writes three hooks into the repository holding the project:
+- post-merge and post-checkout run 'ccmd sync --frozen --quiet' when a pull,
+  merge or branch switch changed ccmd.yaml or ccmd-lock.yaml
+- pre-commit runs 'ccmd status --check --short' when a commit changes either
+  file, refusing commits where the two disagree or commands are broken
+
+The hooks do nothing on machines without ccmd in PATH. The hooks directory
+honors core.hooksPath.`,
+		Example: `  # Install the hooks
+  ccmd hooks install
+
+  # Replace existing hooks, keeping them with a .pre-ccmd suffix
+  ccmd hooks install --force
+
+  # Remove them again
+  ccmd hooks uninstall`,
+	}
+
+	cmd.AddCommand(newInstallCommand())
+	cmd.AddCommand(newUninstallCommand())
+
+	return cmd
+}
+
+func newInstallCommand() *cobra.Command {
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "install",
+		Short: "Write the post-merge, post-checkout and pre-commit hooks",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runInstall(force)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&force, "force", "f", false, "Replace hooks not written by ccmd, keeping them with a .pre-ccmd suffix")
+
+	return cmd
+}
+
+func newUninstallCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "uninstall",
+		Short: "Remove the hooks written by ccmd, restoring replaced ones",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runUninstall()
+		},
+	}
+}
+
+func runInstall(force bool) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	results, err := core.InstallHooks(cwd, force)
+	if err != nil {
+		return fmt.Errorf("failed to install hooks: %w", err)
+	}
+
+	for _, result := range results {
+		output.PrintSuccessf("Installed %s hook", result.Name)
+		if result.BackedUp != "" {
+			output.PrintInfof("  the previous hook was moved to %s", result.BackedUp)
+		}
+	}
+	return nil
+}
+
+func runUninstall() error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	removed, err := core.UninstallHooks(cwd)
+	if err != nil {
+		return fmt.Errorf("failed to uninstall hooks: %w", err)
+	}
+
+	if len(removed) == 0 {
+		output.PrintInfof("No ccmd hooks installed")
+		return nil
+	}
+	for _, name := range removed {
+		output.PrintSuccessf("Removed %s hook", name)
+	}
+	return nil
+}
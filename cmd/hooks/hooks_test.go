@@ -0,0 +1,38 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package hooks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCommand(t *testing.T) {
+	cmd := NewCommand()
+
+	assert.Equal(t, "hooks", cmd.Use)
+	assert.NotEmpty(t, cmd.Short)
+	assert.NotEmpty(t, cmd.Long)
+
+	names := make([]string, 0, len(cmd.Commands()))
+	for _, sub := range cmd.Commands() {
+		names = append(names, sub.Name())
+	}
+	assert.ElementsMatch(t, []string{"install", "uninstall"}, names)
+
+	install, _, err := cmd.Find([]string{"install"})
+	require.NoError(t, err)
+	assert.Error(t, install.Args(install, []string{"extra"}))
+	force := install.Flags().Lookup("force")
+	require.NotNil(t, force)
+	assert.Equal(t, "false", force.DefValue)
+}
@@ -10,10 +10,12 @@
 package info
 
 import (
+	"bytes"
 	"fmt"
+	"os"
+	"os/exec"
 	"strings"
 
-	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 
 	"github.com/gifflet/ccmd/core"
@@ -21,31 +23,50 @@ import (
 	"github.com/gifflet/ccmd/pkg/output"
 )
 
+// defaultPreviewLines is how much of index.md the info view shows without --full
+const defaultPreviewLines = 10
+
+// infoOptions holds the flags of the info command
+type infoOptions struct {
+	json  bool
+	lines int  // lines of index.md to preview
+	full  bool // show all of index.md, through the pager on terminals
+}
+
 // NewCommand creates a new info command.
 func NewCommand() *cobra.Command {
-	var jsonFormat bool
+	opts := infoOptions{}
 
 	cmd := &cobra.Command{
 		Use:   "info <command-name>",
 		Short: "Display detailed information about an installed command",
 		Long: `Display detailed information about a specific installed command,
-including metadata and structure verification.`,
+including metadata, dependencies, install footprint and structure verification.
+
+The first lines of the command's index.md are rendered as markdown below the
+details; --lines changes how many. With --full the whole document is rendered,
+and the view is shown through $PAGER (less -R by default) on terminals.`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runInfo(args[0], jsonFormat)
+			if opts.lines < 0 {
+				return fmt.Errorf("--lines must not be negative")
+			}
+			return runInfo(args[0], opts)
 		},
 	}
 
-	cmd.Flags().BoolVar(&jsonFormat, "json", false, "Output in JSON format")
+	cmd.Flags().BoolVar(&opts.json, "json", false, "Output in JSON format")
+	cmd.Flags().IntVar(&opts.lines, "lines", defaultPreviewLines, "Lines of index.md to preview (0 hides the preview)")
+	cmd.Flags().BoolVar(&opts.full, "full", false, "Show the whole index.md, paged on terminals")
 
 	return cmd
 }
 
-func runInfo(commandName string, jsonFormat bool) error {
-	return runInfoWithFS(commandName, jsonFormat, nil)
+func runInfo(commandName string, opts infoOptions) error {
+	return runInfoWithFS(commandName, opts, nil)
 }
 
-func runInfoWithFS(commandName string, jsonFormat bool, filesystem fs.FileSystem) error {
+func runInfoWithFS(commandName string, opts infoOptions, filesystem fs.FileSystem) error {
 	if filesystem == nil {
 		filesystem = fs.OS{}
 	}
@@ -53,115 +74,175 @@ func runInfoWithFS(commandName string, jsonFormat bool, filesystem fs.FileSystem
 	// Get detailed command information from core
 	info, err := core.GetCommandDetails(commandName, ".", filesystem)
 	if err != nil {
-		if jsonFormat {
+		if opts.json {
 			return err
 		}
 		output.PrintErrorf("Error: %s", err.Error())
 		return fmt.Errorf("command not found")
 	}
 
-	if jsonFormat {
+	if opts.json {
 		// Output as JSON
 		jsonStr, err := core.FormatCommandInfoJSON(info)
 		if err != nil {
 			return err
 		}
 		fmt.Println(jsonStr)
-	} else {
-		// Output as formatted text
-		displayCommandInfo(info, commandName, filesystem)
+		return nil
+	}
+
+	if opts.full && output.IsTerminal(os.Stdout) {
+		// Render the view first, so the pager gets all of it
+		var buf bytes.Buffer
+		restore := output.SetOutput(&buf, os.Stderr)
+		displayCommandInfo(info, commandName, filesystem, opts)
+		restore()
+		return page(buf.String())
 	}
 
+	displayCommandInfo(info, commandName, filesystem, opts)
 	return nil
 }
 
-func displayCommandInfo(info *core.CommandInfo, commandName string, filesystem fs.FileSystem) {
+func displayCommandInfo(info *core.CommandInfo, commandName string, filesystem fs.FileSystem, opts infoOptions) {
 	// Header
-	fmt.Println()
+	output.Printf("")
 	output.PrintInfof("=== Command Information ===")
-	fmt.Println()
+	output.Printf("")
 
 	// Basic info
-	fmt.Printf("%s %s\n", color.CyanString("Name:"), info.Name)
-	fmt.Printf("%s %s\n", color.CyanString("Version:"), info.Version)
+	output.Printf("%s %s", output.Label("Name:"), info.Name)
+	output.Printf("%s %s", output.Label("Version:"), info.Version)
 
 	if info.Author != "" {
-		fmt.Printf("%s %s\n", color.CyanString("Author:"), info.Author)
+		output.Printf("%s %s", output.Label("Author:"), info.Author)
 	}
 
 	if info.Description != "" {
-		fmt.Printf("%s %s\n", color.CyanString("Description:"), info.Description)
+		output.Printf("%s %s", output.Label("Description:"), info.Description)
 	}
 
 	if info.Repository != "" {
-		fmt.Printf("%s %s\n", color.CyanString("Repository:"), info.Repository)
+		output.Printf("%s %s", output.Label("Repository:"), info.Repository)
 	}
 
 	if info.Homepage != "" {
-		fmt.Printf("%s %s\n", color.CyanString("Homepage:"), info.Homepage)
+		output.Printf("%s %s", output.Label("Homepage:"), info.Homepage)
 	}
 
 	if info.License != "" {
-		fmt.Printf("%s %s\n", color.CyanString("License:"), info.License)
+		output.Printf("%s %s", output.Label("License:"), info.License)
 	}
 
 	if len(info.Tags) > 0 {
-		fmt.Printf("%s %s\n", color.CyanString("Tags:"), strings.Join(info.Tags, ", "))
+		output.Printf("%s %s", output.Label("Tags:"), strings.Join(info.Tags, ", "))
 	}
 
 	if info.Entry != "" {
-		fmt.Printf("%s %s\n", color.CyanString("Entry Point:"), info.Entry)
+		output.Printf("%s %s", output.Label("Entry Point:"), info.Entry)
+	}
+
+	if len(info.Overrides) > 0 {
+		output.Printf("%s %s", output.Label("Overrides:"), strings.Join(info.Overrides, ", "))
+	}
+
+	if len(info.Dependencies) > 0 {
+		output.Printf("%s", output.Label("Dependencies:"))
+		for _, dependency := range info.Dependencies {
+			output.Printf("  • %s", dependency)
+		}
 	}
 
 	// Installation info
-	fmt.Println()
+	output.Printf("")
 	output.PrintInfof("=== Installation Details ===")
-	fmt.Println()
+	output.Printf("")
 
-	fmt.Printf("%s %s\n", color.CyanString("Source:"), info.Source)
-	fmt.Printf("%s %s\n", color.CyanString("Installed:"), info.InstalledAt)
-	fmt.Printf("%s %s\n", color.CyanString("Updated:"), info.UpdatedAt)
+	output.Printf("%s %s", output.Label("Source:"), info.Source)
+	output.Printf("%s %s", output.Label("Installed:"), info.InstalledAt)
+	output.Printf("%s %s", output.Label("Updated:"), info.UpdatedAt)
+	if info.Size > 0 {
+		output.Printf("%s %s", output.Label("Footprint:"), core.FormatSize(info.Size))
+	}
 
 	// Structure verification
-	fmt.Println()
+	output.Printf("")
 	output.PrintInfof("=== Structure Verification ===")
-	fmt.Println()
+	output.Printf("")
 
 	printStatus("Command directory", info.Structure.DirectoryExists)
 	printStatus("Standalone .md file", info.Structure.MarkdownExists)
 	printStatus("ccmd.yaml", info.Structure.HasCcmdYaml)
 	printStatus("index.md", info.Structure.HasIndexMd)
 
+	output.Printf("")
+	if len(info.Structure.Issues) == 0 {
+		output.Printf("%s %s", output.Label("Health:"), output.Success("healthy"))
+	} else {
+		output.Printf("%s %s", output.Label("Health:"), output.Warning(fmt.Sprintf("%d issue(s)", len(info.Structure.Issues))))
+	}
+
 	if len(info.Structure.Issues) > 0 {
-		fmt.Println()
+		output.Printf("")
 		output.PrintWarningf("Issues found:")
 		for _, issue := range info.Structure.Issues {
-			fmt.Printf("  - %s\n", issue)
+			output.Printf("  - %s", issue)
 		}
 	}
 
 	// Preview content if available
-	if info.Structure.HasIndexMd {
-		fmt.Println()
-		output.PrintInfof("=== Content Preview ===")
-		fmt.Println()
-
-		preview, totalLines, err := core.ReadCommandContentPreview(commandName, ".claude", filesystem, 10)
+	if info.Structure.HasIndexMd && (opts.full || opts.lines > 0) {
+		document, err := core.ReadCommandDocument(info.Dir, filesystem)
 		if err == nil {
-			fmt.Print(preview)
-			if totalLines > 10 {
-				fmt.Printf("\n... (showing first 10 lines of %d total)\n", totalLines)
+			lines := strings.Split(strings.TrimRight(document, "\n"), "\n")
+			shown := len(lines)
+			if !opts.full && opts.lines < shown {
+				shown = opts.lines
+			}
+
+			output.Printf("")
+			if shown < len(lines) {
+				output.PrintInfof("=== Content Preview ===")
+			} else {
+				output.PrintInfof("=== Content ===")
+			}
+			output.Printf("")
+			output.Printf("%s", output.RenderMarkdown(strings.Join(lines[:shown], "\n")))
+			if shown < len(lines) {
+				output.Printf("\n... (showing first %d lines of %d total; use --full for all)", shown, len(lines))
 			}
 		}
 	}
 
-	fmt.Println()
+	output.Printf("")
+}
+
+// page shows text through $PAGER, or less -R, printing it directly when no pager runs
+func page(text string) error {
+	pager := strings.Fields(os.Getenv("PAGER"))
+	if len(pager) == 0 {
+		pager = []string{"less", "-R"}
+	}
+	path, err := exec.LookPath(pager[0])
+	if err != nil {
+		fmt.Print(text)
+		return nil
+	}
+
+	cmd := exec.Command(path, pager[1:]...)
+	cmd.Stdin = strings.NewReader(text)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("pager %s failed: %w", pager[0], err)
+	}
+	return nil
 }
 
 func printStatus(label string, ok bool) {
-	status := color.GreenString("✓")
+	status := output.Success("✓")
 	if !ok {
-		status = color.RedString("✗")
+		status = output.Error("✗")
 	}
-	fmt.Printf("  %s %s\n", status, label)
+	output.Printf("  %s %s", status, label)
 }
@@ -26,6 +26,14 @@ func TestNewCommand(t *testing.T) {
 	jsonFlag := cmd.Flags().Lookup("json")
 	assert.NotNil(t, jsonFlag)
 	assert.Equal(t, "false", jsonFlag.DefValue)
+
+	linesFlag := cmd.Flags().Lookup("lines")
+	assert.NotNil(t, linesFlag)
+	assert.Equal(t, "10", linesFlag.DefValue)
+
+	fullFlag := cmd.Flags().Lookup("full")
+	assert.NotNil(t, fullFlag)
+	assert.Equal(t, "false", fullFlag.DefValue)
 }
 
 func TestCommandIntegration(t *testing.T) {
@@ -43,6 +51,12 @@ func TestCommandIntegration(t *testing.T) {
 	err = cmd.Execute()
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "accepts 1 arg(s), received 2")
+
+	// Test with a negative preview length
+	cmd.SetArgs([]string{"cmd1", "--lines", "-1"})
+	err = cmd.Execute()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "--lines must not be negative")
 }
 
 // Note: Full integration tests for info command would require setting up
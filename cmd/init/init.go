@@ -62,9 +62,9 @@ func runInit(plugin bool) error {
 	output.Printf("This utility will walk you through creating a ccmd.yaml file.")
 	output.Printf("Press ^C at any time to quit.\n")
 
-	currentDir, err := os.Getwd()
+	currentDir, err := projectDir()
 	if err != nil {
-		return fmt.Errorf("failed to get current directory: %w", err)
+		return err
 	}
 
 	defaults, existingCommands, err := core.LoadExistingConfig(currentDir)
@@ -99,7 +99,11 @@ func runInit(plugin bool) error {
 		return err
 	}
 
-	output.Printf("\nAbout to write to %s:\n", filepath.Join(currentDir, "ccmd.yaml"))
+	paths, err := core.ResolvePaths(currentDir)
+	if err != nil {
+		return err
+	}
+	output.Printf("\nAbout to write to %s:\n", paths.Config)
 	output.Printf("%s", preview)
 
 	confirm := promptUser(scanner, "\nIs this OK?", "yes")
@@ -127,13 +131,26 @@ func runInit(plugin bool) error {
 	return nil
 }
 
+// projectDir returns the directory to initialize: the --project directory, else the
+// working directory
+func projectDir() (string, error) {
+	if dir := core.ProjectDir(); dir != "" {
+		return dir, nil
+	}
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("failed to get current directory: %w", err)
+	}
+	return dir, nil
+}
+
 func runPluginInit(scanner *bufio.Scanner) error {
 	output.Printf("This utility will walk you through creating a Claude Code plugin.")
 	output.Printf("Press ^C at any time to quit.\n")
 
-	currentDir, err := os.Getwd()
+	currentDir, err := projectDir()
 	if err != nil {
-		return fmt.Errorf("failed to get current directory: %w", err)
+		return err
 	}
 
 	defaults := core.InitDefaults(currentDir)
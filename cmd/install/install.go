@@ -10,8 +10,13 @@
 package install
 
 import (
-	"context"
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/spf13/cobra"
 
@@ -24,7 +29,34 @@ func NewCommand() *cobra.Command {
 	var (
 		version string
 		name    string
+		rename  string
+		as      string
+		profile string
 		force   bool
+
+		overwriteLocal bool
+		backup         bool
+
+		saveExact bool
+		saveCaret bool
+		saveTilde bool
+		pre       bool
+
+		latestRelease bool
+		defaultBranch bool
+
+		fromArchive string
+		checksum    string
+
+		frozen bool
+		all    bool
+		save   bool
+		noSave bool
+
+		recurseSubmodules bool
+		strict            bool
+
+		file string
 	)
 
 	cmd := &cobra.Command{
@@ -35,6 +67,67 @@ func NewCommand() *cobra.Command {
 When no repository is provided, installs all commands defined in the project's ccmd.yaml file.
 When a repository is provided, installs the command and adds it to ccmd.yaml and ccmd-lock.yaml.
 
+Without a version, the newest semver tag is installed and recorded in ccmd.yaml as a
+constraint: ^X.Y.Z by default, or as chosen with --save-exact, --save-caret or
+--save-tilde. The default can be changed with 'ccmd config set save_strategy <strategy>'.
+With --default-branch, or 'ccmd config set default_version default-branch', the head of
+the default branch is installed and tracked instead; --latest-release overrides that
+setting. ccmd-lock.yaml records the choice, and reinstalls and updates keep it.
+Prerelease tags such as v2.0.0-rc.1 are skipped unless --pre is passed or the ccmd.yaml
+entry sets "channel: beta".
+
+Release archives (.tar.gz, .tgz, .tar or .zip URLs or files) are installed without git.
+Use --from-archive for archive URLs without one of these extensions, and --checksum
+to verify the download.
+
+An http(s) URL of a single .md file, such as a raw gist, is installed as a command.
+Metadata missing from its front matter is derived from the URL and the first heading;
+the lock file records the URL with the file's sha256.
+
+If another repository (or a hand-written command) already uses the command name, the
+install fails. Pass --rename to install under another name, or choose one when
+prompted. The chosen name is kept in ccmd-lock.yaml for later installs and updates.
+
+With --as, the command is installed side by side with other versions of the same
+repository, for example while moving between major versions. The instance is recorded
+in ccmd.yaml as "owner/repo@v1 as <name>", keyed by that name in ccmd-lock.yaml, and
+its standalone files use the name. Sync, update and remove treat it as a command of
+its own.
+
+A repository can publish several commands, each in a directory under commands/ with
+its own ccmd.yaml. Install one of them with "owner/repo//<command>", or run
+'ccmd install owner/repo' to choose them from a list (or take all with --all). Each
+command is locked and recorded in ccmd.yaml on its own, all at the same commit.
+
+Repositories with a .gitmodules file get their git submodules checked out
+recursively, at the commits the repository records, and ccmd-lock.yaml pins each
+submodule commit. --recurse-submodules=false installs without them.
+
+With --profile, 'ccmd install' installs the shared commands plus the commands of that
+ccmd.yaml profile, and 'ccmd install <repository> --profile <name>' records the command
+under the profile instead of the shared commands list.
+
+With --frozen (or --locked), meant for CI, 'ccmd install' installs exactly what
+ccmd-lock.yaml records and writes neither ccmd.yaml nor ccmd-lock.yaml. It fails
+without installing anything when the lock file is missing an entry of ccmd.yaml,
+holds a version ccmd.yaml does not accept, or holds entries ccmd.yaml no longer lists.
+
+With --no-save (or --save=false), the command is installed to try it out without
+touching ccmd.yaml. ccmd-lock.yaml records it as ephemeral, 'ccmd list' marks it, and
+'ccmd sync' leaves it alone until run with --adopt to add it to ccmd.yaml or with
+--prune-ephemeral to remove it. Installing it again without --no-save saves it.
+
+With --strict, or the strict_metadata setting, a command is only installed when its
+metadata is complete: a description, an author, a license, at least one tag and a
+semantic version. Anything missing is reported and nothing is installed.
+
+With --file, every repository of a list is installed: one spec per line (blank
+lines and # comments are ignored) or a YAML or JSON array of specs, read from a
+file or from stdin with "--file -". Specs are written as in ccmd.yaml, such as
+"owner/repo@^1.2.0" or "owner/repo@v1 as repo-v1". Versions are resolved in
+parallel up front; a failed install does not stop the others, and a summary lists
+what was installed, skipped and failed.
+
 Examples:
   # Install all commands from ccmd.yaml
   ccmd install
@@ -45,33 +138,154 @@ Examples:
   # Install specific version
   ccmd install github.com/user/repo@v1.0.0
 
+  # Install the newest version matching a constraint
+  ccmd install "github.com/user/repo@^1.2.0"
+
+  # Install the latest tag and pin it exactly in ccmd.yaml
+  ccmd install github.com/user/repo --save-exact
+
+  # Track the head of the default branch instead of the newest tag
+  ccmd install github.com/user/repo --default-branch
+
+  # Install the newest tag, prereleases included
+  ccmd install github.com/user/repo --pre
+
+  # Install exactly what ccmd-lock.yaml records, failing if it is out of date
+  ccmd install --frozen
+
+  # Install the shared commands plus the "backend" profile
+  ccmd install --profile backend
+
+  # Install every repository listed in a file
+  ccmd install --file commands.txt
+
+  # Install a list read from stdin
+  printf 'acme/review\nacme/deploy@^2.0.0\n' | ccmd install --file -
+
+  # Install a command and record it under the "docs" profile
+  ccmd install github.com/user/repo --profile docs
+
+  # Try a command without adding it to ccmd.yaml
+  ccmd install github.com/user/repo --no-save
+
+  # Refuse commands with incomplete metadata
+  ccmd install github.com/user/repo --strict
+
   # Install with custom name
   ccmd install github.com/user/repo --name mycommand
 
   # Force reinstall
-  ccmd install github.com/user/repo --force`,
+  ccmd install github.com/user/repo --force
+
+  # Install under another name when "repo" is already taken
+  ccmd install github.com/other/repo --rename other-repo
+
+  # Install one command of a multi-command repository
+  ccmd install github.com/user/prompts//review
+
+  # Install every command of a multi-command repository
+  ccmd install github.com/user/prompts --all
+
+  # Keep v1 available next to the regular install of v2
+  ccmd install github.com/user/repo@v1.4.0 --as repo-v1
+
+  # Install a release archive without git
+  ccmd install https://github.com/user/repo/archive/refs/tags/v1.0.0.tar.gz \
+    --checksum sha256:9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08
+
+  # Install a single markdown file from a gist
+  ccmd install https://gist.githubusercontent.com/user/1a2b3c/raw/prompt.md --name quick-fix`,
 		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			ctx := context.Background()
+			ctx := cmd.Context()
+
+			if file != "" {
+				for _, flag := range []string{"version", "name", "rename", "as", "from-archive", "checksum", "frozen", "locked"} {
+					if cmd.Flags().Changed(flag) {
+						return fmt.Errorf("--file cannot be combined with --%s", flag)
+					}
+				}
+				if len(args) > 0 {
+					return fmt.Errorf("--file cannot be combined with a repository argument")
+				}
+			}
+
+			if fromArchive != "" {
+				if len(args) > 0 {
+					return fmt.Errorf("cannot combine a repository argument with --from-archive")
+				}
+				args = []string{fromArchive}
+			}
+
+			if as != "" && (len(args) == 0 || name != "") {
+				return fmt.Errorf("--as needs a repository and cannot be combined with --name")
+			}
+
+			fromConfig := len(args) == 0 && file == ""
+
+			if pre && fromConfig {
+				return fmt.Errorf("--pre needs a repository; set \"channel: beta\" on ccmd.yaml entries instead")
+			}
+
+			if frozen && len(args) > 0 {
+				return fmt.Errorf("--frozen installs from ccmd.yaml and cannot be combined with a repository")
+			}
 
-			if len(args) == 0 {
+			noSave = noSave || !save
+			if noSave && fromConfig {
+				return fmt.Errorf("--no-save needs a repository; commands installed from ccmd.yaml are already saved")
+			}
+
+			if fromConfig {
 				// Install from config
 				cwd, err := os.Getwd()
 				if err != nil {
 					return err
 				}
-				return core.InstallFromConfig(ctx, cwd, force)
+				return core.InstallProfile(ctx, cwd, core.ConfigInstallOptions{
+					Profile:        profile,
+					Force:          force,
+					OverwriteLocal: overwriteLocal,
+					Backup:         backup,
+					Frozen:         frozen,
+					Strict:         strict,
+				})
 			}
 
-			// Install specific repository
 			opts := core.InstallOptions{
-				Repository: args[0],
-				Version:    version,
-				Name:       name,
-				Force:      force,
+				Version:      version,
+				Name:         name,
+				Rename:       rename,
+				As:           as,
+				Force:        force,
+				SaveStrategy: saveStrategy(saveExact, saveCaret, saveTilde),
+				Pre:          pre,
+
+				DefaultVersion: defaultVersion(latestRelease, defaultBranch),
+				OverwriteLocal: overwriteLocal,
+				Backup:         backup,
+				Archive:        fromArchive != "",
+				Checksum:       checksum,
+				Profile:        profile,
+				NoSubmodules:   !recurseSubmodules,
+				NoSave:         noSave,
+				Strict:         strict,
+			}
+			if file != "" {
+				return installFromFile(cmd, file, opts, all)
+			}
+
+			// Install specific repository
+			opts.Repository = args[0]
+			if output.IsTerminal(os.Stdin) {
+				opts.PromptRename = promptRename
 			}
 
 			commandName, isPlugin, err := core.Install(ctx, opts)
+			var multi *core.MultiCommandError
+			if errors.As(err, &multi) {
+				return installRepositoryCommands(cmd, opts, multi, all)
+			}
 			if err != nil {
 				return err
 			}
@@ -87,7 +301,181 @@ Examples:
 
 	cmd.Flags().StringVarP(&version, "version", "v", "", "Version/tag to install")
 	cmd.Flags().StringVarP(&name, "name", "n", "", "Override command name")
+	cmd.Flags().StringVar(&rename, "rename", "", "Name to install under if the command name is already taken")
+	cmd.Flags().StringVar(&as, "as", "", "Install side by side with other versions of the repository under this name")
+	cmd.Flags().StringVar(&profile, "profile", "", "ccmd.yaml profile to install, or to record the command under")
 	cmd.Flags().BoolVarP(&force, "force", "f", false, "Force reinstall if already exists")
+	cmd.Flags().BoolVar(&overwriteLocal, "overwrite-local", false, "Let --force discard local modifications to installed files")
+	cmd.Flags().BoolVar(&backup, "backup", false, "Let --force replace modified files after copying them to .claude/.backups")
+	cmd.Flags().BoolVar(&saveExact, "save-exact", false, "Record the exact installed version in ccmd.yaml")
+	cmd.Flags().BoolVar(&saveCaret, "save-caret", false, "Record a ^ constraint allowing minor and patch updates (default)")
+	cmd.Flags().BoolVar(&saveTilde, "save-tilde", false, "Record a ~ constraint allowing patch updates")
+	cmd.Flags().BoolVar(&pre, "pre", false, "Allow prerelease tags for the newest version and constraints")
+	cmd.MarkFlagsMutuallyExclusive("save-exact", "save-caret", "save-tilde")
+	cmd.Flags().BoolVar(&latestRelease, "latest-release", false, "Without a version, install the newest stable tag (default)")
+	cmd.Flags().BoolVar(&defaultBranch, "default-branch", false, "Without a version, install and track the head of the default branch")
+	cmd.MarkFlagsMutuallyExclusive("latest-release", "default-branch")
+	cmd.Flags().StringVar(&fromArchive, "from-archive", "", "Install from a release archive URL or file instead of git")
+	cmd.Flags().StringVar(&checksum, "checksum", "", "Expected archive or markdown file checksum (sha256:<hex>)")
+	cmd.Flags().BoolVar(&frozen, "frozen", false, "Install from ccmd-lock.yaml without writing it; fail if it is out of date")
+	cmd.Flags().BoolVar(&frozen, "locked", false, "Same as --frozen")
+	cmd.Flags().BoolVar(&all, "all", false, "Install every command of a multi-command repository")
+	cmd.Flags().BoolVar(&recurseSubmodules, "recurse-submodules", true, "Check out the git submodules of repositories with a .gitmodules file")
+	cmd.Flags().BoolVar(&save, "save", true, "Record the command in ccmd.yaml")
+	cmd.Flags().BoolVar(&strict, "strict", false, "Refuse commands without a description, author, license, tags and semantic version")
+	cmd.Flags().StringVar(&file, "file", "", "Install every repository listed in a file, or stdin with -")
+	cmd.Flags().BoolVar(&noSave, "no-save", false, "Install without recording the command in ccmd.yaml (marked ephemeral in the lock file)")
 
 	return cmd
 }
+
+// installFromFile installs the repositories listed in a file, or stdin for "-", and
+// prints a summary. It fails when any of them failed.
+func installFromFile(cmd *cobra.Command, path string, opts core.InstallOptions, all bool) error {
+	var (
+		data []byte
+		err  error
+	)
+	if path == "-" {
+		data, err = io.ReadAll(cmd.InOrStdin())
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return fmt.Errorf("read %s: %w", path, err)
+	}
+	specs, err := core.ParseSpecList(data)
+	if err != nil {
+		return err
+	}
+
+	result, err := core.InstallBatch(cmd.Context(), core.BatchInstallOptions{Specs: specs, Install: opts, All: all})
+	if result != nil {
+		printBatchResult(result)
+	}
+	if err != nil {
+		return err
+	}
+	if err := result.Err(); err != nil {
+		return err
+	}
+	output.PrintSuccessf("\n✓ Installed %d of %d repositories", len(result.Installed), len(specs))
+	return nil
+}
+
+// printBatchResult shows what a batch install did with each repository
+func printBatchResult(result *core.BatchResult) {
+	if len(result.Installed) > 0 {
+		output.PrintInfof("\nInstalled:")
+		for _, spec := range result.Installed {
+			output.PrintSuccessf("  ✓ %s", spec)
+		}
+	}
+	if len(result.Skipped) > 0 {
+		output.PrintInfof("\nAlready installed (use --force to reinstall):")
+		for _, spec := range result.Skipped {
+			output.PrintInfof("  - %s", spec)
+		}
+	}
+}
+
+// installRepositoryCommands installs the commands of a multi-command repository that
+// --all or the prompt selects
+func installRepositoryCommands(cmd *cobra.Command, opts core.InstallOptions, multi *core.MultiCommandError, all bool) error {
+	selected := multi.Commands
+	if !all {
+		if !output.IsTerminal(os.Stdin) {
+			return multi
+		}
+		selected = promptCommands(multi)
+		if len(selected) == 0 {
+			return fmt.Errorf("no commands selected")
+		}
+	}
+
+	installed, err := core.InstallRepositoryCommands(cmd.Context(), opts, multi, selected)
+	if len(installed) > 0 {
+		output.PrintInfof("\nTo use the commands, run:")
+		for _, name := range installed {
+			output.PrintInfof("/%s", name)
+		}
+	}
+	return err
+}
+
+// promptCommands asks which commands of a multi-command repository to install
+func promptCommands(multi *core.MultiCommandError) []string {
+	output.PrintInfof("%s publishes %d commands:", multi.Repository, len(multi.Commands))
+	for i, name := range multi.Commands {
+		output.Printf("  %d) %s\n", i+1, name)
+	}
+	output.Printf("Commands to install (numbers or names separated by commas, \"all\", empty to cancel): ")
+
+	// Read the whole line: the selection may contain spaces
+	response, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	return parseSelection(response, multi.Commands)
+}
+
+// parseSelection maps a response such as "1,3", "review" or "all" to command names.
+// Entries that match no command are ignored.
+func parseSelection(response string, commands []string) []string {
+	response = strings.TrimSpace(response)
+	if strings.EqualFold(response, "all") {
+		return commands
+	}
+
+	var selected []string
+	seen := make(map[string]bool)
+	for _, field := range strings.FieldsFunc(response, func(r rune) bool { return r == ',' || r == ' ' }) {
+		name := field
+		if i, err := strconv.Atoi(field); err == nil {
+			if i < 1 || i > len(commands) {
+				continue
+			}
+			name = commands[i-1]
+		}
+		for _, command := range commands {
+			if command == name && !seen[name] {
+				seen[name] = true
+				selected = append(selected, name)
+			}
+		}
+	}
+	return selected
+}
+
+// promptRename asks for an alternative name when the command name is already taken
+func promptRename(name, conflict string) string {
+	output.PrintWarningf("Command name %q is already used by %s", name, conflict)
+	output.Printf("Install under another name (leave empty to cancel): ")
+
+	var response string
+	_, _ = fmt.Scanln(&response)
+	return strings.TrimSpace(response)
+}
+
+// defaultVersion maps the --latest-release and --default-branch flags to a core default version
+func defaultVersion(latestRelease, defaultBranch bool) string {
+	switch {
+	case latestRelease:
+		return core.DefaultVersionLatestRelease
+	case defaultBranch:
+		return core.DefaultVersionDefaultBranch
+	default:
+		return ""
+	}
+}
+
+// saveStrategy maps the --save-* flags to a core save strategy
+func saveStrategy(exact, caret, tilde bool) string {
+	switch {
+	case exact:
+		return core.SaveExact
+	case caret:
+		return core.SaveCaret
+	case tilde:
+		return core.SaveTilde
+	default:
+		return ""
+	}
+}
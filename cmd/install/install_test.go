@@ -10,7 +10,12 @@
 package install
 
 import (
+	"strings"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gifflet/ccmd/core"
 )
 
 func TestVersionPrecedence(t *testing.T) {
@@ -66,3 +71,163 @@ func TestVersionPrecedence(t *testing.T) {
 }
 
 // Test removed as extractRepoPath is now handled internally by the installer package
+
+func TestSaveStrategyFlags(t *testing.T) {
+	cmd := NewCommand()
+
+	for _, flag := range []string{"save-exact", "save-caret", "save-tilde"} {
+		f := cmd.Flags().Lookup(flag)
+		if assert.NotNil(t, f, flag) {
+			assert.Equal(t, "false", f.DefValue)
+		}
+	}
+
+	assert.Equal(t, "", saveStrategy(false, false, false))
+	assert.Equal(t, core.SaveExact, saveStrategy(true, false, false))
+	assert.Equal(t, core.SaveCaret, saveStrategy(false, true, false))
+	assert.Equal(t, core.SaveTilde, saveStrategy(false, false, true))
+
+	cmd.SetArgs([]string{"owner/repo", "--save-exact", "--save-tilde"})
+	assert.Error(t, cmd.Execute())
+}
+
+func TestDefaultVersionFlags(t *testing.T) {
+	cmd := NewCommand()
+
+	for _, flag := range []string{"latest-release", "default-branch"} {
+		assert.NotNil(t, cmd.Flags().Lookup(flag), flag)
+	}
+
+	assert.Equal(t, "", defaultVersion(false, false))
+	assert.Equal(t, core.DefaultVersionLatestRelease, defaultVersion(true, false))
+	assert.Equal(t, core.DefaultVersionDefaultBranch, defaultVersion(false, true))
+
+	cmd.SetArgs([]string{"owner/repo", "--latest-release", "--default-branch"})
+	assert.Error(t, cmd.Execute())
+}
+
+func TestPreFlag(t *testing.T) {
+	cmd := NewCommand()
+	f := cmd.Flags().Lookup("pre")
+	if assert.NotNil(t, f) {
+		assert.Equal(t, "false", f.DefValue)
+	}
+
+	cmd.SetArgs([]string{"--pre"})
+	assert.ErrorContains(t, cmd.Execute(), "channel: beta")
+}
+
+func TestArchiveFlags(t *testing.T) {
+	cmd := NewCommand()
+
+	assert.NotNil(t, cmd.Flags().Lookup("from-archive"))
+	assert.NotNil(t, cmd.Flags().Lookup("checksum"))
+
+	cmd.SetArgs([]string{"owner/repo", "--from-archive", "release.tar.gz"})
+	assert.Error(t, cmd.Execute())
+}
+
+func TestRenameFlag(t *testing.T) {
+	cmd := NewCommand()
+
+	flag := cmd.Flags().Lookup("rename")
+	assert.NotNil(t, flag)
+	assert.Equal(t, "", flag.DefValue)
+}
+
+func TestAsFlag(t *testing.T) {
+	cmd := NewCommand()
+
+	flag := cmd.Flags().Lookup("as")
+	assert.NotNil(t, flag)
+	assert.Equal(t, "", flag.DefValue)
+
+	cmd.SetArgs([]string{"--as", "repo-v1"})
+	assert.Error(t, cmd.Execute())
+
+	cmd = NewCommand()
+	cmd.SetArgs([]string{"owner/repo@v1.0.0", "--as", "repo-v1", "--name", "repo"})
+	assert.Error(t, cmd.Execute())
+}
+
+func TestProfileFlag(t *testing.T) {
+	cmd := NewCommand()
+
+	flag := cmd.Flags().Lookup("profile")
+	assert.NotNil(t, flag)
+	assert.Equal(t, "", flag.DefValue)
+}
+
+func TestLocalModificationFlags(t *testing.T) {
+	cmd := NewCommand()
+
+	for _, name := range []string{"overwrite-local", "backup"} {
+		flag := cmd.Flags().Lookup(name)
+		assert.NotNil(t, flag, name)
+		assert.Equal(t, "false", flag.DefValue)
+	}
+}
+
+func TestFrozenFlags(t *testing.T) {
+	cmd := NewCommand()
+
+	for _, name := range []string{"frozen", "locked"} {
+		flag := cmd.Flags().Lookup(name)
+		assert.NotNil(t, flag, name)
+		assert.Equal(t, "false", flag.DefValue)
+	}
+
+	cmd.SetArgs([]string{"owner/repo", "--frozen"})
+	assert.Error(t, cmd.Execute())
+}
+
+func TestRecurseSubmodulesFlag(t *testing.T) {
+	cmd := NewCommand()
+
+	flag := cmd.Flags().Lookup("recurse-submodules")
+	assert.NotNil(t, flag)
+	assert.Equal(t, "true", flag.DefValue)
+}
+
+func TestParseSelection(t *testing.T) {
+	commands := []string{"explain", "review", "test"}
+
+	assert.Equal(t, commands, parseSelection("all\n", commands))
+	assert.Equal(t, []string{"explain", "test"}, parseSelection("1,3", commands))
+	assert.Equal(t, []string{"review", "explain"}, parseSelection("review, 1 2", commands))
+	assert.Empty(t, parseSelection("", commands))
+	assert.Empty(t, parseSelection("0, 4, lint", commands))
+}
+
+func TestNoSaveFlags(t *testing.T) {
+	cmd := NewCommand()
+	for flag, def := range map[string]string{"save": "true", "no-save": "false", "strict": "false"} {
+		f := cmd.Flags().Lookup(flag)
+		if assert.NotNil(t, f, flag) {
+			assert.Equal(t, def, f.DefValue, flag)
+		}
+	}
+
+	for _, args := range [][]string{{"--no-save"}, {"--save=false"}} {
+		cmd := NewCommand()
+		cmd.SetArgs(args)
+		assert.ErrorContains(t, cmd.Execute(), "needs a repository", "%v", args)
+	}
+}
+
+func TestFileFlag(t *testing.T) {
+	cmd := NewCommand()
+	assert.NotNil(t, cmd.Flags().Lookup("file"))
+
+	cmd.SetArgs([]string{"owner/repo", "--file", "commands.txt"})
+	assert.ErrorContains(t, cmd.Execute(), "repository argument")
+
+	cmd = NewCommand()
+	cmd.SetArgs([]string{"--file", "commands.txt", "--as", "repo-v1"})
+	assert.ErrorContains(t, cmd.Execute(), "--as")
+
+	cmd = NewCommand()
+	cmd.SetIn(strings.NewReader("# nothing to install\n\n"))
+	cmd.SetArgs([]string{"--file", "-"})
+	assert.ErrorContains(t, cmd.Execute(), "list of repositories is empty")
+}
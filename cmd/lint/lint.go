@@ -0,0 +1,104 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gifflet/ccmd/core"
+	"github.com/gifflet/ccmd/pkg/output"
+)
+
+// NewCommand creates a new lint command.
+func NewCommand() *cobra.Command {
+	var (
+		jsonFormat bool
+		strict     bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "lint [path]",
+		Short: "Check a command repository before publishing",
+		Long: `Check a command repository before publishing it.
+
+Validates ccmd.yaml (required fields, name rules, semantic version), index.md
+presence and front matter, relative links in Markdown files, file sizes, and
+content that must not be published such as private keys or API tokens.
+
+In a project, whose ccmd.yaml lists commands rather than describing one, lint
+checks every entry of commands, plugins and profiles instead. YAML syntax errors
+and invalid entries are reported with their line and column.
+
+Each finding has a severity: error, warning or info. The command fails when
+any error is found, or any warning with --strict.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := "."
+			if len(args) > 0 {
+				path = args[0]
+			}
+			return runLint(path, jsonFormat, strict)
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonFormat, "json", false, "Output diagnostics in JSON format")
+	cmd.Flags().BoolVar(&strict, "strict", false, "Treat warnings as errors")
+
+	return cmd
+}
+
+func runLint(path string, jsonFormat, strict bool) error {
+	report, err := core.Lint(path)
+	if err != nil {
+		return fmt.Errorf("lint failed: %w", err)
+	}
+
+	if jsonFormat {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+	} else {
+		printReport(report)
+	}
+
+	if report.Errors > 0 || (strict && report.Warnings > 0) {
+		return fmt.Errorf("%d error(s), %d warning(s)", report.Errors, report.Warnings)
+	}
+
+	return nil
+}
+
+func printReport(report *core.LintReport) {
+	for _, d := range report.Diagnostics {
+		switch d.Severity {
+		case core.SeverityError:
+			output.PrintErrorf("%s", d)
+		case core.SeverityWarning:
+			output.PrintWarningf("%s", d)
+		default:
+			output.PrintInfof("%s", d)
+		}
+		if d.Snippet != "" {
+			output.Printf("%s", d.Snippet)
+		}
+	}
+
+	if report.Errors == 0 && report.Warnings == 0 {
+		output.PrintSuccessf("%s looks good", report.Path)
+		return
+	}
+
+	output.PrintInfof("\n%d error(s), %d warning(s)", report.Errors, report.Warnings)
+}
@@ -0,0 +1,37 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package lint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCommand(t *testing.T) {
+	cmd := NewCommand()
+
+	assert.Equal(t, "lint [path]", cmd.Use)
+	assert.NotEmpty(t, cmd.Short)
+	assert.NotEmpty(t, cmd.Long)
+
+	jsonFlag := cmd.Flags().Lookup("json")
+	assert.NotNil(t, jsonFlag)
+	assert.Equal(t, "false", jsonFlag.DefValue)
+
+	strictFlag := cmd.Flags().Lookup("strict")
+	assert.NotNil(t, strictFlag)
+	assert.Equal(t, "false", strictFlag.DefValue)
+}
+
+func TestRunLintFailsOnErrors(t *testing.T) {
+	// An empty directory has no ccmd.yaml or index.md
+	assert.Error(t, runLint(t.TempDir(), true, false))
+}
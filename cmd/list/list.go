@@ -12,6 +12,8 @@ package list
 import (
 	"fmt"
 	"os"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 
@@ -21,9 +23,72 @@ import (
 	"github.com/gifflet/ccmd/pkg/output"
 )
 
+// column is one column of the list table
+type column struct {
+	output.Column
+	value func(cmd core.CommandDetail) string
+}
+
+// columns holds the columns accepted by --columns
+var columns = map[string]column{
+	"name": {output.Column{Header: "NAME", MaxWidth: 30}, func(cmd core.CommandDetail) string {
+		// Mark commands whose structure is broken, and those installed with --no-save
+		switch {
+		case cmd.BrokenStructure:
+			return "⚠ " + cmd.Name
+		case cmd.Ephemeral:
+			return "~ " + cmd.Name
+		}
+		return cmd.Name
+	}},
+	"version": {output.Column{Header: "VERSION", MaxWidth: 20}, func(cmd core.CommandDetail) string {
+		switch {
+		case cmd.Branch != "":
+			return core.BranchLabel(cmd.Branch)
+		case cmd.Version == "":
+			return "unknown"
+		}
+		return cmd.Version
+	}},
+	"type": {output.Column{Header: "TYPE"}, func(cmd core.CommandDetail) string {
+		if cmd.Type == "" {
+			return "command"
+		}
+		return cmd.Type
+	}},
+	"description": {output.Column{Header: "DESCRIPTION", MaxWidth: 40}, func(cmd core.CommandDetail) string { return formatOrDash(cmd.Description) }},
+	"source":      {output.Column{Header: "SOURCE", MaxWidth: 40}, func(cmd core.CommandDetail) string { return formatOrDash(cmd.Repository) }},
+	"author":      {output.Column{Header: "AUTHOR", MaxWidth: 20}, func(cmd core.CommandDetail) string { return formatOrDash(cmd.Author) }},
+	"license":     {output.Column{Header: "LICENSE", MaxWidth: 20}, func(cmd core.CommandDetail) string { return formatOrDash(cmd.License) }},
+	"tags":        {output.Column{Header: "TAGS", MaxWidth: 24}, func(cmd core.CommandDetail) string { return formatOrDash(strings.Join(cmd.Tags, ",")) }},
+	"updated":     {output.Column{Header: "UPDATED"}, func(cmd core.CommandDetail) string { return formatTimeAgo(cmd.UpdatedAt) }},
+	"installed":   {output.Column{Header: "INSTALLED"}, func(cmd core.CommandDetail) string { return formatTimeAgo(cmd.InstalledAt) }},
+	"size":        {output.Column{Header: "SIZE", Right: true}, func(cmd core.CommandDetail) string { return core.FormatSize(cmd.Size) }},
+	"status": {output.Column{Header: "STATUS", Status: true}, func(cmd core.CommandDetail) string {
+		if cmd.BrokenStructure {
+			return output.StatusBroken
+		}
+		return output.StatusOK
+	}},
+}
+
+// defaultColumns are shown without --columns
+var defaultColumns = []string{"name", "version", "type", "description", "updated"}
+
 // NewCommand creates a new list command.
 func NewCommand() *cobra.Command {
-	var long bool
+	var (
+		long       bool
+		size       bool
+		filters    []string
+		sortBy     string
+		columnList []string
+		brokenOnly bool
+		csv        bool
+		tsv        bool
+		noIndex    bool
+		tree       bool
+	)
 
 	cmd := &cobra.Command{
 		Use:   "list",
@@ -31,19 +96,110 @@ func NewCommand() *cobra.Command {
 		Long: `List all commands managed by ccmd with their versions, sources, and metadata.
 
 This command shows only commands that are tracked in the ccmd-lock.yaml file
-and have entries in the .claude/commands/ directory.`,
+and have entries in the .claude/commands/ directory.
+
+With --size, the installed size of each command and the total footprint are shown.
+
+--filter keeps the commands matching field=value, and can be repeated to combine
+filters: tag and license (exact match), author and source (substring) or type
+(command or plugin), ignoring case. --broken-only keeps the commands with a missing directory
+or standalone .md file.
+
+--sort orders by name (default), updated or installed (newest first) or size
+(largest first). --columns chooses the table columns from name, version, type,
+description, source, author, license, tags, updated, installed, size and status
+(OK or BROKEN). --csv and --tsv print the table for scripts and spreadsheets, without
+truncating cells.
+
+The metadata of installed commands is read from the index in
+.claude/.ccmd-index.json, which is refreshed as commands change. --no-index
+reads every command instead and rewrites the index.
+
+--tree groups the entries of ccmd.yaml by profile and nests under each command the
+dependencies its own ccmd.yaml lists, like npm ls. Broken and missing commands are
+shown in red, and commands with a newer version in the tag cache in yellow.
+Installed commands that no entry reaches are listed last.
+
+Examples:
+  # Commands tagged "cli", largest first
+  ccmd list --filter tag=cli --sort size
+
+  # Names and sources only
+  ccmd list --columns name,version,source,updated
+
+  # Commands by profile, with their dependencies
+  ccmd list --tree`,
 		Args: cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runList(long)
+			if tree {
+				for _, name := range []string{"long", "size", "filter", "sort", "columns", "broken-only", "csv", "tsv"} {
+					if cmd.Flags().Changed(name) {
+						return fmt.Errorf("--%s cannot be combined with --tree", name)
+					}
+				}
+				return runTree(core.ListOptions{NoIndex: noIndex})
+			}
+			if long && (len(columnList) > 0 || csv || tsv) {
+				return fmt.Errorf("--columns, --csv and --tsv cannot be combined with --long")
+			}
+			opts := core.ListOptions{Sort: sortBy, BrokenOnly: brokenOnly, NoIndex: noIndex}
+			for _, f := range filters {
+				filter, err := core.ParseListFilter(f)
+				if err != nil {
+					return err
+				}
+				opts.Filters = append(opts.Filters, filter)
+			}
+			selected, err := selectColumns(columnList, size)
+			if err != nil {
+				return err
+			}
+			return runList(opts, long, size, selected, output.TableFormat(csv, tsv))
 		},
 	}
 
 	cmd.Flags().BoolVarP(&long, "long", "l", false, "Show detailed output including metadata")
+	cmd.Flags().BoolVarP(&size, "size", "s", false, "Show the installed size of each command and the total")
+	cmd.Flags().StringArrayVar(&filters, "filter", nil, "Show only commands matching field=value (tag, license, author, source, type); repeatable")
+	cmd.Flags().StringVar(&sortBy, "sort", core.ListSortName, "Sort by name, updated, installed or size")
+	cmd.Flags().StringSliceVar(&columnList, "columns", nil, "Comma-separated table columns (name, version, type, description, source, author, license, tags, updated, installed, size, status)")
+	cmd.Flags().BoolVar(&brokenOnly, "broken-only", false, "Show only commands with a missing directory or standalone .md file")
+	cmd.Flags().BoolVar(&csv, "csv", false, "Print the table as comma-separated values")
+	cmd.Flags().BoolVar(&tsv, "tsv", false, "Print the table as tab-separated values")
+	cmd.Flags().BoolVar(&noIndex, "no-index", false, "Read every installed command instead of the index")
+	cmd.Flags().BoolVar(&tree, "tree", false, "Show commands by profile with their dependencies nested")
+	cmd.MarkFlagsMutuallyExclusive("csv", "tsv")
 
 	return cmd
 }
 
-func runList(long bool) error {
+// selectColumns validates the --columns names, defaulting to defaultColumns. --size adds
+// the size column when it is not selected.
+func selectColumns(names []string, size bool) ([]string, error) {
+	if len(names) == 0 {
+		names = defaultColumns
+	}
+
+	selected := make([]string, 0, len(names)+1)
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if _, ok := columns[name]; !ok {
+			valid := make([]string, 0, len(columns))
+			for key := range columns {
+				valid = append(valid, key)
+			}
+			sort.Strings(valid)
+			return nil, fmt.Errorf("unknown column %q (valid: %s)", name, strings.Join(valid, ", "))
+		}
+		selected = append(selected, name)
+	}
+	if size && !slices.Contains(selected, "size") {
+		selected = append(selected, "size")
+	}
+	return selected, nil
+}
+
+func runList(opts core.ListOptions, long, size bool, columnNames []string, format string) error {
 	// Get current directory
 	cwd, err := os.Getwd()
 	if err != nil {
@@ -51,38 +207,55 @@ func runList(long bool) error {
 	}
 
 	// Get detailed command information
-	opts := core.ListOptions{
-		ProjectPath: cwd,
-	}
+	opts.ProjectPath = cwd
 	details, err := core.List(opts)
 	if err != nil {
 		return fmt.Errorf("failed to list commands: %w", err)
 	}
 
+	if format != output.FormatTable {
+		// Only the rows, so scripts can parse the output
+		return newListTable(details, columnNames).Print(format)
+	}
+
 	if len(details) == 0 {
+		if len(opts.Filters) > 0 || opts.BrokenOnly {
+			output.PrintInfof("No commands or plugins match.")
+			return nil
+		}
 		output.PrintInfof("No commands or plugins installed yet.")
 		output.PrintInfof("Use 'ccmd install' to install commands or plugins.")
 		return nil
 	}
 
-	// Check for structure issues
-	hasStructureIssues := false
+	// Check for structure issues and ephemeral installs
+	hasStructureIssues, hasEphemeral := false, false
 	for _, detail := range details {
-		if detail.BrokenStructure {
-			hasStructureIssues = true
-			break
-		}
+		hasStructureIssues = hasStructureIssues || detail.BrokenStructure
+		hasEphemeral = hasEphemeral || detail.Ephemeral
 	}
 
 	// Print table
 	if long {
-		printLongList(details)
+		printLongList(details, size)
 	} else {
-		printSimpleList(details)
+		printSimpleList(details, columnNames)
+	}
+
+	if size {
+		var total int64
+		for _, detail := range details {
+			total += detail.Size
+		}
+		output.Printf("\nTotal: %s in %d item(s)", core.FormatSize(total), len(details))
+	}
+
+	if hasEphemeral {
+		output.PrintInfof("\n~ ephemeral: installed with --no-save and not in ccmd.yaml; 'ccmd sync --adopt' saves them")
 	}
 
 	// Show warning if there are structure issues
-	if hasStructureIssues {
+	if hasStructureIssues && !opts.BrokenOnly {
 		output.PrintWarningf("\nSome commands have broken dual structure (missing directory or .md file).")
 		output.PrintWarningf("Run with --long flag to see details.")
 	}
@@ -90,81 +263,107 @@ func runList(long bool) error {
 	return nil
 }
 
-func printSimpleList(commands []core.CommandDetail) {
-	output.PrintInfof("Found %d item(s) managed by ccmd:\n", len(commands))
+// runTree prints the command tree of the project
+func runTree(opts core.ListOptions) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
 
-	// Define column widths
-	const (
-		nameWidth        = 20
-		versionWidth     = 10
-		typeWidth        = 9
-		descriptionWidth = 40
-		updatedWidth     = 20
-	)
+	opts.ProjectPath = cwd
+	tree, err := core.ListTree(opts)
+	if err != nil {
+		return fmt.Errorf("failed to list commands: %w", err)
+	}
 
-	// Print header
-	header := fmt.Sprintf("%-*s %-*s %-*s %-*s %-*s",
-		nameWidth, "NAME",
-		versionWidth, "VERSION",
-		typeWidth, "TYPE",
-		descriptionWidth, "DESCRIPTION",
-		updatedWidth, "UPDATED")
-	output.Printf(header)
-	output.Printf(strings.Repeat("-", len(header)))
-
-	// Print each command
-	for _, cmd := range commands {
-		// Format name with warning icon if structure is broken
-		name := cmd.Name
-		if cmd.BrokenStructure {
-			name = "⚠ " + name
-		}
-		if len(name) > nameWidth {
-			name = name[:nameWidth-3] + "..."
-		}
+	if len(tree.Groups) == 0 && len(tree.Unlisted) == 0 {
+		output.PrintInfof("No commands or plugins installed yet.")
+		output.PrintInfof("Use 'ccmd install' to install commands or plugins.")
+		return nil
+	}
 
-		// Format version
-		version := cmd.Version
-		if version == "" {
-			version = "unknown"
+	for i, group := range tree.Groups {
+		if i > 0 {
+			output.Printf("")
 		}
-		if len(version) > versionWidth {
-			version = version[:versionWidth-3] + "..."
+		if group.Profile == "" {
+			output.Printf("%s", output.Bold(core.ConfigFileName))
+		} else {
+			output.Printf("%s", output.Bold("profile "+group.Profile))
 		}
-
-		// Format type
-		cmdType := cmd.Type
-		if cmdType == "" {
-			cmdType = "command"
+		printTreeNodes(group.Nodes, "")
+	}
+	if len(tree.Unlisted) > 0 {
+		if len(tree.Groups) > 0 {
+			output.Printf("")
 		}
+		output.Printf("%s", output.Bold("not in "+core.ConfigFileName))
+		printTreeNodes(tree.Unlisted, "")
+	}
+	return nil
+}
 
-		// Format description
-		description := cmd.Description
-		if description == "" {
-			description = "-"
-		}
-		if len(description) > descriptionWidth {
-			description = description[:descriptionWidth-3] + "..."
+// printTreeNodes prints nodes below a line starting with prefix, npm ls style
+func printTreeNodes(nodes []*core.TreeNode, prefix string) {
+	for i, node := range nodes {
+		branch, indent := "├── ", "│   "
+		if i == len(nodes)-1 {
+			branch, indent = "└── ", "    "
 		}
+		output.Printf("%s%s%s", prefix, branch, treeLabel(node))
+		printTreeNodes(node.Dependencies, prefix+indent)
+	}
+}
 
-		// Format updated time
-		updated := formatTimeAgo(cmd.UpdatedAt)
-		if len(updated) > updatedWidth {
-			updated = updated[:updatedWidth-3] + "..."
-		}
+// treeLabel describes a node: its name and version, colored when it needs attention
+func treeLabel(node *core.TreeNode) string {
+	if node.Missing {
+		return output.Error(node.Spec + " (not installed)")
+	}
 
-		// Print row
-		row := fmt.Sprintf("%-*s %-*s %-*s %-*s %-*s",
-			nameWidth, name,
-			versionWidth, version,
-			typeWidth, cmdType,
-			descriptionWidth, description,
-			updatedWidth, updated)
-		output.Printf(row)
+	label := node.Name
+	if node.Version != "" {
+		label += " " + node.Version
+	}
+	if node.Type == "plugin" {
+		label += " [plugin]"
+	}
+	switch {
+	case node.Broken:
+		label = output.Error(label + " ⚠ " + node.Problem)
+	case node.Outdated():
+		label = output.Warning(label + " → " + node.Latest)
+	}
+	if node.Cycle {
+		label += " (cycle)"
 	}
+	return label
 }
 
-func printLongList(commands []core.CommandDetail) {
+func printSimpleList(commands []core.CommandDetail, columnNames []string) {
+	output.PrintInfof("Found %d item(s) managed by ccmd:\n", len(commands))
+	_ = newListTable(commands, columnNames).Print(output.FormatTable)
+}
+
+// newListTable builds the table of the selected columns
+func newListTable(commands []core.CommandDetail, columnNames []string) *output.Table {
+	cols := make([]output.Column, len(columnNames))
+	for i, name := range columnNames {
+		cols[i] = columns[name].Column
+	}
+	table := output.NewTable(cols...)
+
+	cells := make([]string, len(columnNames))
+	for _, cmd := range commands {
+		for i, name := range columnNames {
+			cells[i] = columns[name].value(cmd)
+		}
+		table.AddRow(cells...)
+	}
+	return table
+}
+
+func printLongList(commands []core.CommandDetail, size bool) {
 	output.PrintInfof("Found %d item(s) managed by ccmd:\n", len(commands))
 
 	for i, cmd := range commands {
@@ -175,7 +374,19 @@ func printLongList(commands []core.CommandDetail) {
 		// Basic info
 		output.Printf("Name:        %s", cmd.Name)
 		output.Printf("Version:     %s", formatOrDash(cmd.Version))
+		if cmd.Branch != "" {
+			output.Printf("Branch:      %s (updates follow the branch head)", cmd.Branch)
+		}
 		output.Printf("Type:        %s", formatOrDash(cmd.Type))
+		if cmd.Pinned {
+			output.Printf("Pinned:      yes ('ccmd unpin %s' lets it update again)", cmd.Name)
+		}
+		if cmd.Ephemeral {
+			output.Printf("Saved:       no (ephemeral, installed with --no-save)")
+		}
+		if cmd.LocalOnly {
+			output.Printf("Saved:       no (local-only, lives in the project)")
+		}
 		output.Printf("Source:      %s", formatOrDash(cmd.Repository))
 		output.Printf("Description: %s", formatOrDash(cmd.Description))
 
@@ -203,6 +414,9 @@ func printLongList(commands []core.CommandDetail) {
 		// Timestamps
 		output.Printf("Installed:   %s", formatTimestamp(cmd.InstalledAt))
 		output.Printf("Updated:     %s", formatTimestamp(cmd.UpdatedAt))
+		if size {
+			output.Printf("Size:        %s", core.FormatSize(cmd.Size))
+		}
 	}
 }
 
@@ -165,9 +165,9 @@ func runListWithFS(long bool, baseDir string, filesystem fs.FileSystem) error {
 
 	// Print table
 	if long {
-		printLongList(details)
+		printLongList(details, false)
 	} else {
-		printSimpleList(details)
+		printSimpleList(details, defaultColumns)
 	}
 
 	// Show warning if there are structure issues
@@ -19,6 +19,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/gifflet/ccmd/core"
+	"github.com/gifflet/ccmd/pkg/output"
 )
 
 func TestPrintSimpleList(t *testing.T) {
@@ -35,6 +36,7 @@ func TestPrintSimpleList(t *testing.T) {
 			Description:     "A test command for unit testing",
 			Author:          "Test Author",
 			Repository:      "github.com/user/repo",
+			Size:            2048,
 			BrokenStructure: false,
 		},
 		{
@@ -44,6 +46,12 @@ func TestPrintSimpleList(t *testing.T) {
 			BrokenStructure: true,
 			StructureError:  "broken structure: [missing directory]",
 		},
+		{
+			Name:      "trial-cmd",
+			Version:   "2.0.0",
+			UpdatedAt: now.Format(time.RFC3339),
+			Ephemeral: true,
+		},
 	}
 
 	// Capture output
@@ -52,7 +60,7 @@ func TestPrintSimpleList(t *testing.T) {
 	os.Stdout = w
 
 	// Test passes if function doesn't panic
-	printSimpleList(commands)
+	printSimpleList(commands, []string{"name", "version", "type", "description", "updated", "size"})
 
 	w.Close()
 	os.Stdout = oldStdout
@@ -64,6 +72,9 @@ func TestPrintSimpleList(t *testing.T) {
 	// Basic validation
 	assert.Contains(t, output, "test-cmd")
 	assert.Contains(t, output, "broken-cmd")
+	assert.Contains(t, output, "~ trial-cmd")
+	assert.Contains(t, output, "SIZE")
+	assert.Contains(t, output, "2.0 KiB")
 }
 
 func TestPrintLongList(t *testing.T) {
@@ -103,7 +114,7 @@ func TestPrintLongList(t *testing.T) {
 	os.Stdout = w
 
 	// Test passes if function doesn't panic
-	printLongList(commands)
+	printLongList(commands, false)
 
 	w.Close()
 	os.Stdout = oldStdout
@@ -130,6 +141,11 @@ func TestNewCommand(t *testing.T) {
 	longFlag := cmd.Flags().Lookup("long")
 	assert.NotNil(t, longFlag)
 	assert.Equal(t, "false", longFlag.DefValue)
+
+	sizeFlag := cmd.Flags().Lookup("size")
+	assert.NotNil(t, sizeFlag)
+	assert.Equal(t, "false", sizeFlag.DefValue)
+	assert.Equal(t, "s", sizeFlag.Shorthand)
 }
 
 func TestFormatTime(t *testing.T) {
@@ -230,3 +246,51 @@ func verifyTableOutput(t *testing.T, output string, patterns []string) {
 		}
 	}
 }
+
+func TestListFlags(t *testing.T) {
+	cmd := NewCommand()
+
+	for _, name := range []string{"filter", "sort", "columns", "broken-only", "csv", "tsv", "no-index", "tree"} {
+		assert.NotNil(t, cmd.Flags().Lookup(name), name)
+	}
+	assert.Equal(t, core.ListSortName, cmd.Flags().Lookup("sort").DefValue)
+}
+
+func TestPrintTreeNodes(t *testing.T) {
+	var buf bytes.Buffer
+	restore := output.SetOutput(&buf, &buf)
+	defer restore()
+
+	nodes := []*core.TreeNode{
+		{Spec: "acme/deploy", Name: "deploy", Type: "command", Version: "1.4.0", Latest: "v1.5.0", Dependencies: []*core.TreeNode{
+			{Spec: "acme/shell", Name: "shell", Type: "command", Version: "2.0.0", Broken: true, Problem: "standalone .md file not found"},
+			{Spec: "acme/deploy", Name: "deploy", Type: "command", Version: "1.4.0", Cycle: true},
+		}},
+		{Spec: "acme/lint", Type: "command", Missing: true},
+	}
+	printTreeNodes(nodes, "")
+
+	assert.Equal(t, []string{
+		"├── deploy 1.4.0 → v1.5.0",
+		"│   ├── shell 2.0.0 ⚠ standalone .md file not found",
+		"│   └── deploy 1.4.0 (cycle)",
+		"└── acme/lint (not installed)",
+	}, strings.Split(strings.TrimSpace(buf.String()), "\n"))
+}
+
+func TestSelectColumns(t *testing.T) {
+	selected, err := selectColumns(nil, false)
+	assert.NoError(t, err)
+	assert.Equal(t, defaultColumns, selected)
+
+	selected, err = selectColumns([]string{"Name", "source"}, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"name", "source", "size"}, selected)
+
+	selected, err = selectColumns([]string{"size", "name"}, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"size", "name"}, selected)
+
+	_, err = selectColumns([]string{"name", "color"}, false)
+	assert.ErrorContains(t, err, `unknown column "color"`)
+}
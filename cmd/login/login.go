@@ -0,0 +1,111 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package login
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gifflet/ccmd/core"
+	"github.com/gifflet/ccmd/pkg/output"
+)
+
+// NewCommand creates a new login command.
+func NewCommand() *cobra.Command {
+	var (
+		username string
+		store    string
+		list     bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "login [host]",
+		Short: "Store a token for a Git forge",
+		Long: `Store a personal access token for a Git forge (default github.com).
+
+The token is read from standard input. It is kept in the OS keychain when one is
+available (the macOS keychain, or the Secret Service through secret-tool on Linux),
+otherwise encrypted in credentials.json next to the user config file. Use --store
+to choose.
+
+ccmd sends the token to that host only: as an HTTP header on git clones and
+ls-remote calls, and as a bearer token on forge API requests and downloads over
+HTTPS. $GITHUB_TOKEN still takes precedence for the GitHub API.`,
+		Example: `  # Paste a GitHub token when prompted
+  ccmd login
+
+  # Store a GitLab token from a secret manager
+  op read op://dev/gitlab/token | ccmd login gitlab.example.com --username oauth2
+
+  # Show the hosts with a stored token
+  ccmd login --list`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if list {
+				return runList()
+			}
+			host := core.DefaultHost
+			if len(args) > 0 {
+				host = args[0]
+			}
+			return runLogin(cmd.InOrStdin(), host, username, store)
+		},
+	}
+
+	cmd.Flags().StringVarP(&username, "username", "u", core.DefaultTokenUsername, "User name sent with the token")
+	cmd.Flags().StringVar(&store, "store", core.CredentialStoreAuto, "Where to keep the token: auto, keychain or file")
+	cmd.Flags().BoolVarP(&list, "list", "l", false, "List the hosts with a stored token")
+
+	return cmd
+}
+
+func runLogin(in io.Reader, host, username, store string) error {
+	if output.IsTerminal(os.Stdin) {
+		output.Printf("Paste the token for %s and press Enter:", host)
+	}
+	token, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return err
+	}
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return fmt.Errorf("no token given on standard input")
+	}
+
+	cred, err := core.Login(core.LoginOptions{Host: host, Token: token, Username: username, Store: store})
+	if err != nil {
+		return err
+	}
+
+	output.PrintSuccessf("Stored token for %s in the %s store", cred.Host, cred.Store)
+	return nil
+}
+
+func runList() error {
+	credentials, err := core.ListCredentials()
+	if err != nil {
+		return err
+	}
+	if len(credentials) == 0 {
+		output.PrintInfof("No stored tokens. Run 'ccmd login <host>' to add one.")
+		return nil
+	}
+
+	output.Printf("%-30s %-20s %s", "HOST", "USERNAME", "STORE")
+	for _, cred := range credentials {
+		output.Printf("%-30s %-20s %s", cred.Host, cred.Username, cred.Store)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package login
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gifflet/ccmd/core"
+	"github.com/gifflet/ccmd/pkg/config"
+)
+
+func TestNewCommand(t *testing.T) {
+	cmd := NewCommand()
+
+	assert.Equal(t, "login [host]", cmd.Use)
+	assert.NotEmpty(t, cmd.Short)
+	assert.NotEmpty(t, cmd.Long)
+	assert.NoError(t, cmd.Args(cmd, []string{}))
+	assert.NoError(t, cmd.Args(cmd, []string{"github.com"}))
+	assert.Error(t, cmd.Args(cmd, []string{"a", "b"}))
+
+	usernameFlag := cmd.Flags().Lookup("username")
+	require.NotNil(t, usernameFlag)
+	assert.Equal(t, "u", usernameFlag.Shorthand)
+	assert.Equal(t, core.DefaultTokenUsername, usernameFlag.DefValue)
+
+	storeFlag := cmd.Flags().Lookup("store")
+	require.NotNil(t, storeFlag)
+	assert.Equal(t, core.CredentialStoreAuto, storeFlag.DefValue)
+
+	listFlag := cmd.Flags().Lookup("list")
+	require.NotNil(t, listFlag)
+	assert.Equal(t, "false", listFlag.DefValue)
+}
+
+func TestRunLogin(t *testing.T) {
+	t.Setenv(config.ConfigEnv, filepath.Join(t.TempDir(), "config.yaml"))
+
+	assert.Error(t, runLogin(strings.NewReader("\n"), "github.com", "", core.CredentialStoreFile))
+
+	require.NoError(t, runLogin(strings.NewReader("ghp_secret\n"), "github.com", "", core.CredentialStoreFile))
+	credentials, err := core.ListCredentials()
+	require.NoError(t, err)
+	require.Len(t, credentials, 1)
+	assert.Equal(t, "github.com", credentials[0].Host)
+	require.NoError(t, core.Logout("github.com"))
+}
@@ -0,0 +1,40 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package logout
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/gifflet/ccmd/core"
+	"github.com/gifflet/ccmd/pkg/output"
+)
+
+// NewCommand creates a new logout command.
+func NewCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "logout [host]",
+		Short: "Remove the stored token of a Git forge",
+		Long: `Remove the token stored with 'ccmd login' for a Git forge (default github.com),
+from the OS keychain or the encrypted credentials file.`,
+		Example: `  ccmd logout gitlab.example.com`,
+		Args:    cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			host := core.DefaultHost
+			if len(args) > 0 {
+				host = args[0]
+			}
+			if err := core.Logout(host); err != nil {
+				return err
+			}
+			output.PrintSuccessf("Removed token for %s", host)
+			return nil
+		},
+	}
+}
@@ -0,0 +1,92 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package migratelayout
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gifflet/ccmd/core"
+	"github.com/gifflet/ccmd/pkg/config"
+	"github.com/gifflet/ccmd/pkg/output"
+)
+
+// NewCommand creates a new migrate-layout command.
+func NewCommand() *cobra.Command {
+	var layout string
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "migrate-layout",
+		Short: "Move installed commands to the flat or nested layout",
+		Long: `Move installed commands to the flat or nested layout.
+
+In the flat layout (the default) commands are installed to
+.claude/commands/<name>/ with the standalone file <name>.md. In the nested
+layout, set with layout.commands: nested, commands from git repositories are
+installed to .claude/commands/<owner>/<name>/ with the standalone file
+<owner>--<name>.md (layout.file_name), so commands of the same name from
+different owners no longer collide. Their lock entries are keyed owner/name.
+
+Changing layout.commands only affects new installs. migrate-layout moves the
+commands already installed: their directories, standalone files in every
+target and local overrides, and their lock entries. Commands whose new name
+is taken and side-by-side instances keep their names.`,
+		Example: `  # Preview moving the installed commands to the nested layout
+  ccmd migrate-layout --to nested --dry-run
+
+  # Move them to the layout configured in layout.commands
+  ccmd migrate-layout`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runMigrateLayout(layout, dryRun)
+		},
+	}
+
+	cmd.Flags().StringVar(&layout, "to", "", fmt.Sprintf("Layout to move to, %s or %s (default: the layout.commands setting)",
+		config.LayoutFlat, config.LayoutNested))
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show the moves without making them")
+
+	return cmd
+}
+
+func runMigrateLayout(layout string, dryRun bool) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	result, err := core.MigrateLayout(core.MigrateLayoutOptions{ProjectPath: cwd, Layout: layout, DryRun: dryRun})
+	if err != nil {
+		return fmt.Errorf("failed to migrate layout: %w", err)
+	}
+
+	for _, move := range result.Moved {
+		if dryRun {
+			output.PrintInfof("Would move %s to %s", move.From, move.To)
+		} else {
+			output.PrintSuccessf("Moved %s to %s", move.From, move.To)
+		}
+	}
+	for _, failure := range result.Failed {
+		output.PrintErrorf("Failed to move %s: %v", failure.Command, failure.Error)
+	}
+	if len(result.Moved) == 0 && len(result.Failed) == 0 {
+		output.PrintInfof("All commands already use the layout.")
+	}
+
+	if len(result.Failed) > 0 {
+		return fmt.Errorf("%d command(s) failed to move", len(result.Failed))
+	}
+
+	return nil
+}
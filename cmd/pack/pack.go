@@ -0,0 +1,105 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package pack
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gifflet/ccmd/core"
+	"github.com/gifflet/ccmd/pkg/output"
+)
+
+// NewCommand creates a new pack command.
+func NewCommand() *cobra.Command {
+	var (
+		outputDir  string
+		jsonFormat bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "pack [path]",
+		Short: "Build a distributable artifact from a command repository",
+		Long: `Build a distributable artifact from a command repository.
+
+The repository is linted first and packing stops on any lint error. Files ending
+in .tmpl are rendered with the command metadata ({{.Name}}, {{.Version}},
+{{.Description}}, ...) and packed without the suffix. The result is:
+
+  <name>-<version>.ccmd.tgz   the artifact, with ccmd.yaml and an embedded
+                              ccmd-manifest.json listing every file checksum
+  <name>-<version>.ccmd.json  the manifest plus the artifact checksum, for catalogs
+
+Artifacts are reproducible and can be installed directly with
+'ccmd install <name>-<version>.ccmd.tgz' or from a URL. Install verifies every
+file against the embedded manifest.`,
+		Example: `  # Pack the command in the current directory
+  ccmd pack
+
+  # Pack another directory into dist/
+  ccmd pack ./my-command --output dist`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := "."
+			if len(args) > 0 {
+				path = args[0]
+			}
+			return runPack(path, outputDir, jsonFormat)
+		},
+	}
+
+	cmd.Flags().StringVarP(&outputDir, "output", "o", "", "Directory for the artifact and manifest (default: the command directory)")
+	cmd.Flags().BoolVar(&jsonFormat, "json", false, "Print the manifest in JSON format")
+
+	return cmd
+}
+
+func runPack(path, outputDir string, jsonFormat bool) error {
+	result, err := core.Pack(core.PackOptions{Path: path, OutputDir: outputDir})
+	if result != nil && result.Lint != nil && !jsonFormat {
+		for _, d := range result.Lint.Diagnostics {
+			switch d.Severity {
+			case core.SeverityError:
+				output.PrintErrorf("%s", d)
+			case core.SeverityWarning:
+				output.PrintWarningf("%s", d)
+			}
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	manifest := result.Manifest
+	if jsonFormat {
+		data, err := json.MarshalIndent(manifest, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	output.PrintSuccessf("Packed %s %s (%d files, %s)", manifest.Name, manifest.Version, len(manifest.Files), formatSize(manifest.Size))
+	output.Printf("  artifact: %s", result.Artifact)
+	output.Printf("  manifest: %s", result.ManifestPath)
+	output.Printf("  checksum: %s", manifest.Checksum)
+
+	return nil
+}
+
+func formatSize(size int64) string {
+	if size < 1024 {
+		return fmt.Sprintf("%d B", size)
+	}
+	return fmt.Sprintf("%.1f KiB", float64(size)/1024)
+}
@@ -0,0 +1,44 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package pack
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCommand(t *testing.T) {
+	cmd := NewCommand()
+
+	assert.Equal(t, "pack [path]", cmd.Use)
+	assert.NotEmpty(t, cmd.Short)
+	assert.NotEmpty(t, cmd.Long)
+
+	outputFlag := cmd.Flags().Lookup("output")
+	assert.NotNil(t, outputFlag)
+	assert.Equal(t, "o", outputFlag.Shorthand)
+
+	jsonFlag := cmd.Flags().Lookup("json")
+	assert.NotNil(t, jsonFlag)
+	assert.Equal(t, "false", jsonFlag.DefValue)
+
+	assert.Error(t, cmd.Args(cmd, []string{"a", "b"}))
+}
+
+func TestRunPackFailsOnLintErrors(t *testing.T) {
+	// An empty directory has no metadata or index.md
+	assert.Error(t, runPack(t.TempDir(), "", true))
+}
+
+func TestFormatSize(t *testing.T) {
+	assert.Equal(t, "512 B", formatSize(512))
+	assert.Equal(t, "1.5 KiB", formatSize(1536))
+}
@@ -0,0 +1,65 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package pin
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/gifflet/ccmd/core"
+	"github.com/gifflet/ccmd/pkg/output"
+)
+
+// NewCommand creates a new pin command.
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pin <command-name>...",
+		Short: "Freeze installed commands at their current commit",
+		Long: `Freeze installed commands at the commit they are installed at, for example
+before a release. The ccmd.yaml entry of each command is rewritten to name the
+full commit, and its entry in ccmd-lock.yaml is marked pinned, so 'ccmd update'
+skips it even with --force and sync keeps installing that commit.
+
+The version ccmd.yaml requested before is remembered, and 'ccmd unpin' restores
+it. Nothing is reinstalled.`,
+		Example: `  # Freeze two commands before a release
+  ccmd pin review deploy
+
+  # Let them follow updates again afterwards
+  ccmd unpin review deploy`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			for _, name := range args {
+				result, err := core.Pin(".", name)
+				if err != nil {
+					return err
+				}
+				switch {
+				case result.Unchanged:
+					output.PrintInfof("%s is already pinned at %.7s", name, result.Commit)
+				case result.Version == "":
+					output.PrintSuccessf("Pinned %s at %.7s (ccmd.yaml does not list it)", name, result.Commit)
+				default:
+					output.PrintSuccessf("Pinned %s at %.7s%s", name, result.Commit, previous(result.Previous))
+				}
+			}
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// previous mentions the version ccmd.yaml requested before
+func previous(version string) string {
+	if version == "" {
+		return ""
+	}
+	return " (was " + version + ")"
+}
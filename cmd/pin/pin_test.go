@@ -0,0 +1,30 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package pin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCommand(t *testing.T) {
+	cmd := NewCommand()
+
+	assert.Equal(t, "pin <command-name>...", cmd.Use)
+	assert.NotEmpty(t, cmd.Short)
+	assert.Error(t, cmd.Args(cmd, []string{}))
+	assert.NoError(t, cmd.Args(cmd, []string{"review", "deploy"}))
+}
+
+func TestPrevious(t *testing.T) {
+	assert.Empty(t, previous(""))
+	assert.Equal(t, " (was ^1.2.0)", previous("^1.2.0"))
+}
@@ -0,0 +1,118 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package plan
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gifflet/ccmd/core"
+	"github.com/gifflet/ccmd/pkg/output"
+)
+
+// NewCommand creates a new plan command.
+func NewCommand() *cobra.Command {
+	var (
+		out        string
+		profile    string
+		refresh    bool
+		jsonFormat bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "plan",
+		Short: "Show the changes a sync would make",
+		Long: `Compute the changes 'ccmd sync' would make, without making them.
+
+Every install, update and removal is listed with the version and commit it resolves
+to and the reason for it:
+
+  new-entry          listed in ccmd.yaml but not installed
+  missing-files      locked in ccmd-lock.yaml but its files are missing
+  constraint-change  ccmd.yaml no longer accepts the locked version
+  orphan             installed but no longer listed in ccmd.yaml
+
+With --out, the plan is written to a JSON file that can be reviewed, for example in
+a pull request, and applied exactly with 'ccmd sync --plan <file>'. Applying fails
+without changing anything when ccmd.yaml or the installed commands changed since the
+plan was made.
+
+Examples:
+  # Show what a sync would do
+  ccmd plan
+
+  # Save the plan for review, then apply it
+  ccmd plan --out plan.json
+  ccmd sync --plan plan.json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cwd, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+
+			plan, err := core.ComputePlan(cmd.Context(), core.PlanOptions{
+				ProjectPath: cwd,
+				Profile:     profile,
+				Refresh:     refresh,
+			})
+			if err != nil {
+				return err
+			}
+
+			if out != "" {
+				if err := core.WritePlan(out, plan); err != nil {
+					return err
+				}
+			}
+
+			if jsonFormat {
+				data, err := json.MarshalIndent(plan, "", "  ")
+				if err != nil {
+					return err
+				}
+				fmt.Println(string(data))
+				return nil
+			}
+
+			printPlan(plan)
+			if out != "" {
+				output.PrintInfof("\nPlan written to %s; apply it with 'ccmd sync --plan %s'", out, out)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&out, "out", "o", "", "Write the plan to this JSON file")
+	cmd.Flags().StringVar(&profile, "profile", "", "Plan the shared commands plus this ccmd.yaml profile")
+	cmd.Flags().BoolVar(&refresh, "refresh", false, "List remote tags again instead of using the tag cache")
+	cmd.Flags().BoolVar(&jsonFormat, "json", false, "Output the plan in JSON format")
+
+	return cmd
+}
+
+// printPlan lists the actions of a plan with their reasons
+func printPlan(plan *core.Plan) {
+	if plan.Empty() {
+		output.PrintInfof("✓ Commands are already in sync with ccmd.yaml")
+		return
+	}
+
+	symbols := map[string]string{core.PlanInstall: "+", core.PlanUpdate: "~", core.PlanRemove: "-"}
+	for _, action := range plan.Actions {
+		output.Printf("  %s %s %s", symbols[action.Action], action.Action, action.Summary())
+		output.Printf("      %s: %s", action.Reason, action.Detail)
+	}
+	output.PrintInfof("\nPlan: %d to install, %d to update, %d to remove",
+		plan.Count(core.PlanInstall), plan.Count(core.PlanUpdate), plan.Count(core.PlanRemove))
+}
@@ -0,0 +1,32 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package plan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCommand(t *testing.T) {
+	cmd := NewCommand()
+
+	assert.Equal(t, "plan", cmd.Use)
+	assert.NotEmpty(t, cmd.Short)
+	assert.NotEmpty(t, cmd.Long)
+
+	outFlag := cmd.Flags().Lookup("out")
+	assert.NotNil(t, outFlag)
+	assert.Equal(t, "o", outFlag.Shorthand)
+
+	for _, name := range []string{"profile", "refresh", "json"} {
+		assert.NotNil(t, cmd.Flags().Lookup(name), name)
+	}
+}
@@ -0,0 +1,147 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package regen
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gifflet/ccmd/core"
+	"github.com/gifflet/ccmd/pkg/output"
+)
+
+// NewCommand creates a new regen command.
+func NewCommand() *cobra.Command {
+	var keepEdits, discardEdits bool
+
+	cmd := &cobra.Command{
+		Use:   "regen [command-name]",
+		Short: "Regenerate standalone command files",
+		Long: `Regenerate the standalone .claude/commands/<name>.md files.
+
+Each standalone file is rebuilt from the command's index.md and the header
+fields (version, author, repository) in its ccmd.yaml. Files that are already
+current are left untouched. Without a name, every installed command is checked.
+With targets configured in ccmd.yaml, the file of every target is rebuilt.
+
+Standalone files edited by hand are detected from the checksums recorded in
+ccmd-lock.yaml and left alone. In a terminal, regen asks for each command with
+edits whether to keep them as a local override, which regen, update and sync
+leave alone from then on, or to regenerate the file from the source.
+--keep-edits and --discard-edits decide for all of them; --discard-edits also
+regenerates earlier overrides.
+
+'ccmd update' and 'ccmd sync' also regenerate stale files automatically.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var name string
+			if len(args) > 0 {
+				name = args[0]
+			}
+			return runRegen(name, keepEdits, discardEdits)
+		},
+	}
+
+	cmd.Flags().BoolVar(&keepEdits, "keep-edits", false, "Keep standalone files edited by hand as local overrides")
+	cmd.Flags().BoolVar(&discardEdits, "discard-edits", false, "Regenerate standalone files edited by hand and local overrides")
+	cmd.MarkFlagsMutuallyExclusive("keep-edits", "discard-edits")
+
+	return cmd
+}
+
+func runRegen(name string, keepEdits, discardEdits bool) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	opts := core.RegenOptions{Name: name, ProjectPath: cwd, KeepEdits: keepEdits, DiscardEdits: discardEdits}
+	result, err := core.Regen(opts)
+	if err != nil {
+		return fmt.Errorf("failed to regenerate: %w", err)
+	}
+	printResult(result, keepEdits)
+
+	if !keepEdits && !discardEdits && len(result.Edited) > 0 {
+		if !output.IsTerminal(os.Stdin) {
+			return fmt.Errorf("%d standalone file(s) edited by hand, use --keep-edits or --discard-edits", len(result.Edited))
+		}
+		if err := resolveEdits(cwd, result.Edited); err != nil {
+			return err
+		}
+	}
+
+	if len(result.Failed) > 0 {
+		return fmt.Errorf("%d command(s) failed to regenerate", len(result.Failed))
+	}
+
+	return nil
+}
+
+// printResult reports the regenerated and failed commands, and the files edited by hand
+func printResult(result *core.RegenResult, kept bool) {
+	for _, regenerated := range result.Regenerated {
+		output.PrintSuccessf("Regenerated %s.md", regenerated)
+	}
+	for _, failure := range result.Failed {
+		output.PrintErrorf("Failed to regenerate %s.md: %v", failure.Command, failure.Error)
+	}
+	for _, edit := range result.Edited {
+		if kept {
+			output.PrintSuccessf("Kept %s as a local override", edit.File)
+		} else {
+			output.PrintWarningf("%s was edited by hand", edit.File)
+		}
+	}
+
+	if len(result.Regenerated) == 0 && len(result.Failed) == 0 && len(result.Edited) == 0 {
+		output.PrintInfof("All standalone files are up to date.")
+	}
+}
+
+// resolveEdits asks, per command, whether its standalone files edited by hand are kept
+// as local overrides or regenerated
+func resolveEdits(projectPath string, edits []core.StandaloneEdit) error {
+	var commands []string
+	for _, edit := range edits {
+		if !slices.Contains(commands, edit.Command) {
+			commands = append(commands, edit.Command)
+		}
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	for _, name := range commands {
+		output.Printf("Keep the edits to %s as a local override, or regenerate from source? [k/r/S]: ", name)
+		response, _ := reader.ReadString('\n')
+
+		opts := core.RegenOptions{Name: name, ProjectPath: projectPath}
+		switch strings.ToLower(strings.TrimSpace(response)) {
+		case "k", "keep":
+			opts.KeepEdits = true
+		case "r", "regenerate":
+			opts.DiscardEdits = true
+		default:
+			output.PrintInfof("Skipped %s", name)
+			continue
+		}
+
+		result, err := core.Regen(opts)
+		if err != nil {
+			return fmt.Errorf("failed to regenerate: %w", err)
+		}
+		printResult(result, opts.KeepEdits)
+	}
+	return nil
+}
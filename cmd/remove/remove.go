@@ -23,22 +23,46 @@ import (
 // NewCommand creates a new remove command.
 func NewCommand() *cobra.Command {
 	var (
-		force bool
-		save  bool
+		force  bool
+		save   bool
+		dryRun bool
+		all    bool
 	)
 
 	cmd := &cobra.Command{
-		Use:   "remove <command-name>",
-		Short: "Remove an installed command",
-		Long:  `Remove an installed command and clean up all associated files.`,
-		Args:  cobra.ExactArgs(1),
+		Use:   "remove <command-name|pattern>...",
+		Short: "Remove installed commands",
+		Long: `Remove installed commands and clean up all associated files.
+
+Several names can be given, as well as glob patterns such as "acme-*" (quote them
+so the shell does not expand them), and --all removes every command and plugin.
+The matching commands are listed and confirmed once. Removing several is all or
+nothing: if one fails, the others are put back, and ccmd.yaml and ccmd-lock.yaml
+are written once at the end.
+
+Removed files are moved to .claude/.trash and can be brought back with
+'ccmd restore <command-name>' for a limited time.`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if all {
+				return cobra.NoArgs(cmd, args)
+			}
+			return cobra.MinimumNArgs(1)(cmd, args)
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if all || len(args) > 1 || core.IsRemovePattern(args[0]) {
+				return runRemoveMany(args, all, force, save, dryRun)
+			}
+			if dryRun {
+				return runRemoveDryRun(args[0], save)
+			}
 			return runRemove(args[0], force, save)
 		},
 	}
 
 	cmd.Flags().BoolVarP(&force, "force", "f", false, "Force removal without confirmation")
 	cmd.Flags().BoolVarP(&save, "save", "s", false, "Update ccmd.yaml and ccmd-lock.yaml files")
+	cmd.Flags().BoolVarP(&dryRun, "dry-run", "n", false, "Show what would be removed without making changes")
+	cmd.Flags().BoolVar(&all, "all", false, "Remove every installed command and plugin")
 
 	return cmd
 }
@@ -104,6 +128,71 @@ func runRemove(commandName string, force, save bool) error {
 	return nil
 }
 
+// runRemoveMany removes the commands and plugins matching several names or patterns, or
+// all of them, after a single confirmation
+func runRemoveMany(patterns []string, all, force, save, dryRun bool) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	names, err := core.ResolveRemoveNames(cwd, patterns, all)
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		output.PrintInfof("No commands or plugins installed")
+		return nil
+	}
+
+	if !force && !dryRun {
+		output.PrintInfof("Matching commands and plugins:")
+		for _, name := range names {
+			output.PrintInfof("  %s", name)
+		}
+
+		output.PrintWarningf("\nThis will remove %d command(s) and plugin(s) and all their files.", len(names))
+		output.Printf("Are you sure you want to continue? [y/N]: ")
+
+		var response string
+		_, _ = fmt.Scanln(&response)
+		if !isConfirmation(response) {
+			output.PrintInfof("Removal canceled")
+			return nil
+		}
+	}
+
+	// Remove the names resolved and confirmed above, even if more would match now
+	removeOpts := core.RemoveOptions{
+		Names:       names,
+		Force:       force,
+		UpdateFiles: save,
+		DryRun:      dryRun,
+	}
+	if err := core.Remove(removeOpts); err != nil {
+		return fmt.Errorf("failed to remove commands: %w", err)
+	}
+
+	if save && !dryRun {
+		output.PrintInfof("Updated ccmd.yaml and ccmd-lock.yaml")
+	}
+	return nil
+}
+
+func runRemoveDryRun(commandName string, save bool) error {
+	removeOpts := core.RemoveOptions{
+		Name:        commandName,
+		UpdateFiles: save,
+		DryRun:      true,
+	}
+
+	if err := core.Remove(removeOpts); err != nil {
+		return fmt.Errorf("failed to remove command: %w", err)
+	}
+
+	return nil
+}
+
 func isConfirmation(response string) bool {
 	response = strings.ToLower(strings.TrimSpace(response))
 	return response == "y" || response == "yes"
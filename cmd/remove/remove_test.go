@@ -416,3 +416,16 @@ func TestExtractRepoFromSource(t *testing.T) {
 	}
 }
 */
+
+func TestNewCommandArgs(t *testing.T) {
+	cmd := NewCommand()
+	assert.Equal(t, "remove <command-name|pattern>...", cmd.Use)
+	assert.NotNil(t, cmd.Flags().Lookup("all"))
+
+	assert.Error(t, cmd.Args(cmd, nil))
+	assert.NoError(t, cmd.Args(cmd, []string{"a", "acme-*"}))
+
+	require.NoError(t, cmd.Flags().Set("all", "true"))
+	assert.NoError(t, cmd.Args(cmd, nil))
+	assert.Error(t, cmd.Args(cmd, []string{"a"}))
+}
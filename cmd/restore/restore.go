@@ -0,0 +1,80 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package restore
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gifflet/ccmd/core"
+	"github.com/gifflet/ccmd/pkg/output"
+)
+
+// NewCommand creates a new restore command.
+func NewCommand() *cobra.Command {
+	var list bool
+
+	cmd := &cobra.Command{
+		Use:   "restore [command-name]",
+		Short: "Restore a recently removed command",
+		Long: `Restore a command or plugin that was removed with 'ccmd remove' or 'ccmd sync'.
+
+Removed files are kept in .claude/.trash for 7 days. Restoring moves the most
+recent copy back into place and re-adds its ccmd-lock.yaml entry (and its
+ccmd.yaml entry when it was removed with --save).`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if list || len(args) == 0 {
+				return runList()
+			}
+			return runRestore(args[0])
+		},
+	}
+
+	cmd.Flags().BoolVarP(&list, "list", "l", false, "List restorable commands")
+
+	return cmd
+}
+
+func runRestore(name string) error {
+	if err := core.Restore(core.RestoreOptions{Name: name}); err != nil {
+		return fmt.Errorf("failed to restore: %w", err)
+	}
+
+	output.PrintSuccessf("%q has been restored", name)
+	return nil
+}
+
+func runList() error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	entries, err := core.ListTrash(cwd)
+	if err != nil {
+		return err
+	}
+
+	if len(entries) == 0 {
+		output.PrintInfof("Nothing to restore.")
+		return nil
+	}
+
+	output.PrintInfof("Restorable items:\n")
+	for _, entry := range entries {
+		output.Printf("  %-24s %-8s removed %s", entry.Name, entry.Type, entry.RemovedAt.Format(time.RFC3339))
+	}
+
+	return nil
+}
@@ -0,0 +1,39 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package restore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCommand(t *testing.T) {
+	cmd := NewCommand()
+
+	assert.Equal(t, "restore [command-name]", cmd.Use)
+	assert.NotEmpty(t, cmd.Short)
+	assert.NotEmpty(t, cmd.Long)
+	assert.NotNil(t, cmd.RunE)
+
+	listFlag := cmd.Flags().Lookup("list")
+	assert.NotNil(t, listFlag)
+	assert.Equal(t, "false", listFlag.DefValue)
+	assert.Equal(t, "l", listFlag.Shorthand)
+}
+
+func TestCommandArgs(t *testing.T) {
+	cmd := NewCommand()
+
+	cmd.SetArgs([]string{"cmd1", "cmd2"})
+	err := cmd.Execute()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "accepts at most 1 arg(s), received 2")
+}
@@ -0,0 +1,126 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package rollback
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gifflet/ccmd/core"
+	"github.com/gifflet/ccmd/pkg/output"
+)
+
+// NewCommand creates a new rollback command.
+func NewCommand() *cobra.Command {
+	var (
+		to             string
+		last           bool
+		list           bool
+		overwriteLocal bool
+		backup         bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "rollback [command-name]",
+		Short: "Reinstall a previous version of a command or of the whole project",
+		Long: `Reinstall a version of a command recorded in its history, at the commit it
+had then. Without --to, the version installed before the current one is restored;
+--to picks another by version, tag, branch or commit prefix, as 'ccmd history'
+lists them.
+
+ccmd.yaml and ccmd-lock.yaml are updated as they are by 'ccmd update', and the
+version rolled back from joins the history, so a second rollback undoes the first.
+A command whose installed files were edited is not overwritten unless --backup or
+--overwrite-local is given.
+
+Without a command name, the whole project is rolled back. Before each install,
+update, removal, sync or rollback, ccmd keeps a snapshot of ccmd.yaml and
+ccmd-lock.yaml under .claude/.snapshots (the last 20 are kept). --last restores the
+snapshot taken before the last change, undoing a whole batch update in one step;
+--to restores one by the ID --list shows, or the project as it was at a time given
+as a date, an RFC 3339 time or a duration ago such as 2h. Commands and plugins
+whose locked version differs are reinstalled and those the snapshot does not hold
+are moved to the trash. The state replaced is snapshotted too, so running
+'ccmd rollback --last' again redoes the change.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if list {
+				if len(args) > 0 || last || to != "" {
+					return fmt.Errorf("--list cannot be combined with a command name, --last or --to")
+				}
+				return listSnapshots()
+			}
+
+			if len(args) == 0 {
+				if !last && to == "" {
+					return fmt.Errorf("command name required (or use --last or --to <time> for the whole project)")
+				}
+				if last && to != "" {
+					return fmt.Errorf("--last cannot be combined with --to")
+				}
+				snapshot, err := core.RestoreSnapshot(cmd.Context(), core.RestoreSnapshotOptions{
+					To:             to,
+					OverwriteLocal: overwriteLocal,
+					Backup:         backup,
+				})
+				if err != nil {
+					return err
+				}
+				output.PrintSuccessf("Rolled the project back to before the %s of %s", snapshot.Operation, formatTime(snapshot))
+				return nil
+			}
+
+			if last {
+				return fmt.Errorf("--last rolls back the whole project and takes no command name")
+			}
+			target, err := core.Rollback(cmd.Context(), core.RollbackOptions{
+				Name:           args[0],
+				To:             to,
+				OverwriteLocal: overwriteLocal,
+				Backup:         backup,
+			})
+			if err != nil {
+				return err
+			}
+			output.PrintSuccessf("Rolled back %s to %s", args[0], target.Ref())
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&to, "to", "", "Version to roll a command back to, or snapshot ID or time to roll the project back to")
+	cmd.Flags().BoolVar(&last, "last", false, "Undo the last change to the project")
+	cmd.Flags().BoolVar(&list, "list", false, "List the project snapshots")
+	cmd.Flags().BoolVar(&overwriteLocal, "overwrite-local", false, "Discard local modifications to installed files")
+	cmd.Flags().BoolVar(&backup, "backup", false, "Copy locally modified files to .claude/.backups before rolling back")
+
+	return cmd
+}
+
+// listSnapshots prints the project snapshots, newest first
+func listSnapshots() error {
+	snapshots, err := core.ListSnapshots(".")
+	if err != nil {
+		return err
+	}
+	if len(snapshots) == 0 {
+		output.PrintInfof("No snapshots yet")
+		return nil
+	}
+
+	for _, snapshot := range snapshots {
+		output.Printf("%s  %s  before %s", snapshot.ID, formatTime(&snapshot), snapshot.Operation)
+	}
+	return nil
+}
+
+func formatTime(snapshot *core.Snapshot) string {
+	return snapshot.CreatedAt.Local().Format("2006-01-02 15:04:05")
+}
@@ -0,0 +1,48 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package rollback
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCommand(t *testing.T) {
+	cmd := NewCommand()
+
+	assert.Equal(t, "rollback [command-name]", cmd.Use)
+	assert.NotEmpty(t, cmd.Short)
+	assert.NotEmpty(t, cmd.Long)
+	assert.NoError(t, cmd.Args(cmd, []string{}))
+	assert.NoError(t, cmd.Args(cmd, []string{"tool"}))
+	assert.Error(t, cmd.Args(cmd, []string{"tool", "other"}))
+
+	for name, def := range map[string]string{"to": "", "last": "false", "list": "false", "overwrite-local": "false", "backup": "false"} {
+		flag := cmd.Flags().Lookup(name)
+		assert.NotNil(t, flag, name)
+		assert.Equal(t, def, flag.DefValue, name)
+	}
+}
+
+func TestRollbackFlagConflicts(t *testing.T) {
+	for _, args := range [][]string{
+		{},
+		{"--last", "--to", "2h"},
+		{"tool", "--last"},
+		{"--list", "--last"},
+	} {
+		cmd := NewCommand()
+		cmd.SetArgs(args)
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
+		assert.Error(t, cmd.Execute(), "%v", args)
+	}
+}
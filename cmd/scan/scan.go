@@ -0,0 +1,134 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package scan
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gifflet/ccmd/core"
+	"github.com/gifflet/ccmd/pkg/output"
+)
+
+// NewCommand creates a new scan command.
+func NewCommand() *cobra.Command {
+	var (
+		jsonFormat bool
+		strict     bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "scan [command-name]",
+		Short: "Scan installed commands for suspicious content",
+		Long: `Scan installed commands and plugins for suspicious content.
+
+Command prompts are instructions an agent follows, so their content is part of
+the supply chain. The scan looks for instructions that send data to remote URLs
+or request capture services, decode and run hidden code, read secret files such
+as .env or SSH keys, or dump the environment, and for invisible characters and
+long encoded blobs.
+
+Installs run the same scan on incoming content. With scan.policy set to warn,
+the default, findings are printed; with block, error findings refuse the
+install; off disables the install-time scan. scan.rules_file names a YAML
+ruleset that adds rules and changes the severity of built-in ones.
+
+The command fails when any error is found, or any warning with --strict.`,
+		Example: `  # Scan every installed command and plugin
+  ccmd scan
+
+  # Scan one command, failing on warnings too
+  ccmd scan code-review --strict`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var name string
+			if len(args) > 0 {
+				name = args[0]
+			}
+			return runScan(name, jsonFormat, strict)
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonFormat, "json", false, "Output findings in JSON format")
+	cmd.Flags().BoolVar(&strict, "strict", false, "Treat warnings as errors")
+
+	return cmd
+}
+
+func runScan(name string, jsonFormat, strict bool) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	reports, err := core.Scan(core.ScanOptions{ProjectPath: cwd, Name: name})
+	if err != nil {
+		return fmt.Errorf("scan failed: %w", err)
+	}
+
+	if jsonFormat {
+		data, err := json.MarshalIndent(reports, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+	} else {
+		printReports(reports)
+	}
+
+	errorCount, warningCount := 0, 0
+	for _, report := range reports {
+		errorCount += report.Errors
+		warningCount += report.Warnings
+	}
+	if errorCount > 0 || (strict && warningCount > 0) {
+		return fmt.Errorf("%d error(s), %d warning(s)", errorCount, warningCount)
+	}
+
+	return nil
+}
+
+func printReports(reports []*core.ScanReport) {
+	if len(reports) == 0 {
+		output.PrintInfof("No commands installed.")
+		return
+	}
+
+	errorCount, warningCount := 0, 0
+	for _, report := range reports {
+		errorCount += report.Errors
+		warningCount += report.Warnings
+		if len(report.Findings) == 0 {
+			continue
+		}
+
+		output.PrintInfof("%s (%s):", report.Name, report.Type)
+		for _, d := range report.Findings {
+			switch d.Severity {
+			case core.SeverityError:
+				output.PrintErrorf("  %s", d)
+			case core.SeverityWarning:
+				output.PrintWarningf("  %s", d)
+			default:
+				output.PrintInfof("  %s", d)
+			}
+		}
+	}
+
+	if errorCount == 0 && warningCount == 0 {
+		output.PrintSuccessf("No suspicious content found in %d installed command(s)", len(reports))
+		return
+	}
+
+	output.PrintInfof("\n%d error(s), %d warning(s)", errorCount, warningCount)
+}
@@ -0,0 +1,34 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package scan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCommand(t *testing.T) {
+	cmd := NewCommand()
+
+	assert.Equal(t, "scan [command-name]", cmd.Use)
+	assert.NotEmpty(t, cmd.Short)
+	assert.NotEmpty(t, cmd.Long)
+	assert.NoError(t, cmd.Args(cmd, []string{}))
+	assert.NoError(t, cmd.Args(cmd, []string{"name"}))
+	assert.Error(t, cmd.Args(cmd, []string{"a", "b"}))
+
+	for _, name := range []string{"json", "strict"} {
+		flag := cmd.Flags().Lookup(name)
+		if assert.NotNil(t, flag, name) {
+			assert.Equal(t, "false", flag.DefValue)
+		}
+	}
+}
@@ -10,21 +10,34 @@
 package search
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"strconv"
 	"strings"
 
 	"github.com/spf13/cobra"
 
 	"github.com/gifflet/ccmd/core"
+	"github.com/gifflet/ccmd/pkg/config"
 	"github.com/gifflet/ccmd/pkg/output"
 )
 
 // NewCommand creates a new search command.
 func NewCommand() *cobra.Command {
 	var (
-		tags   []string
-		author string
-		all    bool
+		tags     []string
+		author   string
+		all      bool
+		remote   bool
+		catalogs []string
+		noGitHub bool
+		limit    int
+		details  bool
+		csv      bool
+		tsv      bool
+		content  string
+		noIndex  bool
 	)
 
 	cmd := &cobra.Command{
@@ -32,83 +45,262 @@ func NewCommand() *cobra.Command {
 		Short: "Search for installed commands",
 		Long: `Search for installed commands by keyword, tags, or author.
 		
-This command searches through locally installed commands.`,
+By default this command searches through locally installed commands.
+
+With --remote it queries the catalogs listed in the 'catalogs' setting (see
+'ccmd config'), the taps added with 'ccmd tap add' and the GitHub search API for repositories tagged with the
+ccmd-command topic. When GITHUB_TOKEN is set or a token was stored with 'ccmd login',
+repositories with a ccmd.yaml at their root are found as well. Results are merged, ranked and marked when the
+command is already installed.
+
+With --details the version, description, tags and first line of the prompt of each result
+are read from its ccmd.yaml and index.md through the forge's raw content endpoint, without
+cloning. Only GitHub, GitLab and Bitbucket repositories have details.
+
+With --content it searches the markdown files of installed commands, index.md and
+its partials, for a phrase instead, and shows each matching line with its file
+and line number. A keyword, --tags or --author narrow the commands searched.
+
+Results are shown as a table; --csv and --tsv print them for scripts and
+spreadsheets, without truncating cells. Installed commands are read from the
+index in .claude/.ccmd-index.json; --no-index reads every command instead.
+
+Examples:
+  # Find installed commands about reviews
+  ccmd search review
+
+  # Find which installed commands mention rate limits
+  ccmd search --content "rate limit"`,
 		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			var keyword string
 			if len(args) > 0 {
 				keyword = args[0]
 			}
-			return runSearch(keyword, tags, author, all)
+			format := output.TableFormat(csv, tsv)
+			if cmd.Flags().Changed("content") {
+				return runContentSearch(content, core.SearchOptions{Keyword: keyword, Tags: tags, Author: author, NoIndex: noIndex}, format)
+			}
+			if remote {
+				return runRemoteSearch(cmd.Context(), keyword, tags, catalogs, noGitHub, limit, details, format)
+			}
+			return runSearch(core.SearchOptions{Keyword: keyword, Tags: tags, Author: author, ShowAll: all, NoIndex: noIndex}, format)
 		},
 	}
 
 	cmd.Flags().StringSliceVarP(&tags, "tags", "t", []string{}, "Filter by tags (comma-separated)")
 	cmd.Flags().StringVarP(&author, "author", "a", "", "Filter by author")
 	cmd.Flags().BoolVar(&all, "all", false, "Show all commands (ignore keyword)")
+	cmd.Flags().BoolVarP(&remote, "remote", "r", false, "Search catalogs and GitHub instead of installed commands")
+	cmd.Flags().StringSliceVar(&catalogs, "catalog", []string{}, "Additional catalog URL or file to search (with --remote)")
+	cmd.Flags().BoolVar(&noGitHub, "no-github", false, "Skip the GitHub search API (with --remote)")
+	cmd.Flags().IntVar(&limit, "limit", 20, "Maximum number of remote results")
+	cmd.Flags().BoolVar(&details, "details", false, "Read version and details of each result from its repository (with --remote)")
+	cmd.Flags().BoolVar(&csv, "csv", false, "Print the results as comma-separated values")
+	cmd.Flags().BoolVar(&tsv, "tsv", false, "Print the results as tab-separated values")
+	cmd.Flags().StringVar(&content, "content", "", "Search the prompts of installed commands for this text")
+	cmd.Flags().BoolVar(&noIndex, "no-index", false, "Read every installed command instead of the index")
+	cmd.MarkFlagsMutuallyExclusive("csv", "tsv")
+	cmd.MarkFlagsMutuallyExclusive("content", "remote")
 
 	return cmd
 }
 
-func runSearch(keyword string, tags []string, author string, showAll bool) error {
+func runSearch(opts core.SearchOptions, format string) error {
 	// Get search results
-	opts := core.SearchOptions{
-		Keyword: keyword,
-		Tags:    tags,
-		Author:  author,
-		ShowAll: showAll,
-	}
-
 	results, err := core.Search(opts)
 	if err != nil {
 		return fmt.Errorf("search failed: %w", err)
 	}
 
+	table := output.NewTable(
+		output.Column{Header: "NAME", MaxWidth: 30},
+		output.Column{Header: "VERSION", MaxWidth: 20},
+		output.Column{Header: "AUTHOR", MaxWidth: 20},
+		output.Column{Header: "TAGS", MaxWidth: 24},
+		output.Column{Header: "DESCRIPTION", MaxWidth: 50},
+	)
+	for _, result := range results {
+		table.AddRow(result.Name, orDash(result.Version), orDash(result.Author),
+			orDash(strings.Join(result.Tags, ",")), orDash(result.Description))
+	}
+	if format != output.FormatTable {
+		return table.Print(format)
+	}
+
 	// Display results
 	if len(results) == 0 {
 		output.PrintInfof("No commands found matching your criteria.")
-		if !showAll && keyword == "" && len(tags) == 0 && author == "" {
+		if !opts.ShowAll && opts.Keyword == "" && len(opts.Tags) == 0 && opts.Author == "" {
 			output.PrintInfof("\nTip: Use 'ccmd search --all' to list all installed commands.")
 		}
 		return nil
 	}
 
 	output.PrintSuccessf("Found %d command(s):\n", len(results))
-
-	for _, cmd := range results {
-		displayCommand(&cmd)
+	if err := table.Print(format); err != nil {
+		return err
 	}
 
 	if len(results) >= 10 {
-		output.PrintInfof("\n💡 Note: Command registry search is coming soon for discovering more commands!")
+		output.PrintInfof("\n💡 Tip: Use 'ccmd search --remote <keyword>' to discover more commands.")
 	}
 
 	return nil
 }
 
-func displayCommand(cmd *core.SearchResult) {
-	// Display command name and version
-	output.PrintInfof("📦 %s (v%s)", cmd.Name, cmd.Version)
+// snippetWidth is about how many characters of a matching line are shown
+const snippetWidth = 80
+
+func runContentSearch(text string, filter core.SearchOptions, format string) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	// A keyword, tags or author narrow the commands searched
+	var names []string
+	if filter.Keyword != "" || len(filter.Tags) > 0 || filter.Author != "" {
+		results, err := core.Search(filter)
+		if err != nil {
+			return fmt.Errorf("search failed: %w", err)
+		}
+		if len(results) == 0 {
+			output.PrintInfof("No commands found matching your criteria.")
+			return nil
+		}
+		for _, result := range results {
+			names = append(names, result.Name)
+		}
+	}
 
-	// Display description if available
-	if cmd.Description != "" {
-		output.PrintInfof("   %s", cmd.Description)
+	matches, err := core.SearchContent(core.ContentSearchOptions{ProjectPath: cwd, Text: text, Names: names})
+	if err != nil {
+		return fmt.Errorf("search failed: %w", err)
 	}
 
-	// Display author if available
-	if cmd.Author != "" {
-		output.PrintInfof("   Author: %s", cmd.Author)
+	if format != output.FormatTable {
+		table := output.NewTable(
+			output.Column{Header: "NAME"},
+			output.Column{Header: "FILE"},
+			output.Column{Header: "LINE", Right: true},
+			output.Column{Header: "TEXT"},
+		)
+		for _, match := range matches {
+			table.AddRow(match.Name, match.File, strconv.Itoa(match.Line), match.Text)
+		}
+		return table.Print(format)
 	}
 
-	// Display tags if available
-	if len(cmd.Tags) > 0 {
-		output.PrintInfof("   Tags: %s", strings.Join(cmd.Tags, ", "))
+	if len(matches) == 0 {
+		output.PrintInfof("No installed command contains %q.", text)
+		return nil
 	}
 
-	// Display repository
-	if cmd.Repository != "" {
-		output.PrintInfof("   Repository: %s", cmd.Repository)
+	commands := 0
+	for i, match := range matches {
+		if i == 0 || matches[i-1].Name != match.Name {
+			commands++
+		}
 	}
+	output.PrintSuccessf("Found %d match(es) in %d command(s):", len(matches), commands)
 
-	output.PrintInfof("") // Empty line for spacing
+	for i, match := range matches {
+		if i == 0 || matches[i-1].Name != match.Name {
+			output.Printf("\n%s", output.Bold(match.Name))
+		}
+		before, hit, after := match.Snippet(snippetWidth)
+		output.Printf("  %s:%d: %s%s%s", match.File, match.Line, before, output.Warning(hit), after)
+	}
+	return nil
+}
+
+func runRemoteSearch(ctx context.Context, keyword string, tags, catalogs []string, noGitHub bool, limit int,
+	details bool, format string) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	settings, err := config.Load(cwd)
+	if err != nil {
+		return err
+	}
+	taps, err := core.ListTaps(cwd)
+	if err != nil {
+		return err
+	}
+
+	spinner := output.NewSpinner("Searching remote sources...")
+	spinner.Start()
+	report, err := core.SearchRemote(ctx, core.RemoteSearchOptions{
+		Keyword:     keyword,
+		Tags:        tags,
+		Catalogs:    append(settings.Catalogs, catalogs...),
+		Taps:        taps,
+		NoGitHub:    noGitHub,
+		Limit:       limit,
+		ProjectPath: cwd,
+		Details:     details,
+	})
+	spinner.Stop()
+	if err != nil {
+		return fmt.Errorf("search failed: %w", err)
+	}
+
+	for _, warning := range report.Warnings {
+		output.PrintWarningf("Skipped %s", warning)
+	}
+
+	table := remoteResultsTable(report.Results, details)
+	if format != output.FormatTable {
+		return table.Print(format)
+	}
+
+	if len(report.Results) == 0 {
+		output.PrintInfof("No remote commands found matching your criteria.")
+		return nil
+	}
+
+	output.PrintSuccessf("Found %d command(s):\n", len(report.Results))
+	return table.Print(format)
+}
+
+// remoteResultsTable lists remote results with the installed version of those already
+// installed. With details, the first line of each prompt is shown as well.
+func remoteResultsTable(results []core.RemoteSearchResult, details bool) *output.Table {
+	columns := []output.Column{
+		{Header: "NAME", MaxWidth: 30},
+		{Header: "VERSION", MaxWidth: 20},
+		{Header: "STARS", Right: true},
+		{Header: "INSTALLED", MaxWidth: 20},
+		{Header: "REPOSITORY", MaxWidth: 50},
+		{Header: "SOURCES", MaxWidth: 24},
+		{Header: "DESCRIPTION", MaxWidth: 50},
+	}
+	if details {
+		columns = append(columns, output.Column{Header: "PROMPT", MaxWidth: 50})
+	}
+	table := output.NewTable(columns...)
+
+	for _, result := range results {
+		stars, installed := "-", "-"
+		if result.Stars > 0 {
+			stars = strconv.Itoa(result.Stars)
+		}
+		if result.Installed {
+			installed = orDash(result.InstalledVersion)
+		}
+		table.AddRow(result.Name, orDash(result.Version), stars, installed, result.Repository,
+			strings.Join(result.Sources, ","), orDash(result.Description), orDash(result.Summary))
+	}
+	return table
+}
+
+// orDash returns s, or "-" when it is empty
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
 }
@@ -29,6 +29,15 @@ func TestNewCommand(t *testing.T) {
 	assert.NotNil(t, cmd.Flags().Lookup("tags"))
 	assert.NotNil(t, cmd.Flags().Lookup("author"))
 	assert.NotNil(t, cmd.Flags().Lookup("all"))
+	assert.NotNil(t, cmd.Flags().Lookup("remote"))
+	assert.NotNil(t, cmd.Flags().Lookup("catalog"))
+	assert.NotNil(t, cmd.Flags().Lookup("no-github"))
+	assert.Equal(t, "20", cmd.Flags().Lookup("limit").DefValue)
+	assert.Equal(t, "false", cmd.Flags().Lookup("details").DefValue)
+	assert.NotNil(t, cmd.Flags().Lookup("csv"))
+	assert.NotNil(t, cmd.Flags().Lookup("tsv"))
+	assert.NotNil(t, cmd.Flags().Lookup("content"))
+	assert.NotNil(t, cmd.Flags().Lookup("no-index"))
 
 	// Check that it has Args function
 	assert.NotNil(t, cmd.Args)
@@ -0,0 +1,158 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package serve
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gifflet/ccmd/pkg/ccmd"
+	"github.com/gifflet/ccmd/pkg/output"
+)
+
+// TokenEnv holds the API token when --token is not given
+const TokenEnv = "CCMD_SERVE_TOKEN"
+
+// NewCommand creates a new serve command.
+func NewCommand() *cobra.Command {
+	var (
+		listen string
+		token  string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve an HTTP API to list, install, remove and sync commands",
+		Long: `Run a long-lived HTTP server that manages the commands of the current project.
+
+IDE plugins and fleet tooling can drive ccmd through JSON endpoints instead of
+running the CLI:
+
+  GET    /healthz               Liveness check (no token required)
+  GET    /v1/commands           List installed commands and plugins
+  POST   /v1/commands           Install: {"repository", "version", "name", "rename",
+                                "force", "save_strategy", "profile"}
+  DELETE /v1/commands/{name}    Remove; add ?save=true to also update ccmd.yaml
+  POST   /v1/sync               Sync with ccmd.yaml: {"dry_run", "prune", "profile", "refresh"}
+  POST   /v1/update             Update: {"name", "check_only", "force"}
+
+Requests must send "Authorization: Bearer <token>" with the token from --token or
+the CCMD_SERVE_TOKEN environment variable. Without either, a random token is
+generated and printed at startup. Request bodies must be sent as application/json.
+On a loopback address, only requests addressed to localhost are served. Operations
+run one at a time.
+
+Examples:
+  # Serve on localhost with a generated token
+  ccmd serve
+
+  # Serve on all interfaces
+  CCMD_SERVE_TOKEN=secret ccmd serve --listen :7777`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if token == "" {
+				token = os.Getenv(TokenEnv)
+			}
+			return runServe(cmd.Context(), listen, token)
+		},
+	}
+
+	cmd.Flags().StringVar(&listen, "listen", "127.0.0.1:7777", "Address to listen on")
+	cmd.Flags().StringVar(&token, "token", "", "Bearer token required by API requests (default $"+TokenEnv+", or a generated one)")
+
+	return cmd
+}
+
+func runServe(ctx context.Context, listen, token string) error {
+	generated := token == ""
+	if generated {
+		var err error
+		if token, err = generateToken(); err != nil {
+			return err
+		}
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	client := ccmd.New(cwd)
+	client.Log = os.Stderr
+
+	listener, err := net.Listen("tcp", listen)
+	if err != nil {
+		return err
+	}
+
+	// On loopback, requests addressed to other host names come from pages that
+	// rebound their DNS name to this machine
+	var hosts []string
+	if isLoopback(listen) {
+		hosts = loopbackHosts
+	}
+
+	server := &http.Server{
+		Handler:           newHandler(client, token, hosts),
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext:       func(net.Listener) context.Context { return ctx },
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	output.PrintInfof("Serving the ccmd API for %s on http://%s", cwd, listener.Addr())
+	if generated {
+		output.Printf("API token: %s", token)
+	}
+	if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	output.PrintInfof("Server stopped")
+	return nil
+}
+
+// loopbackHosts are the host names accepted by a server listening on a loopback address
+var loopbackHosts = []string{"localhost", "127.0.0.1", "::1"}
+
+// generateToken returns a random token for a server started without one
+func generateToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// isLoopback reports whether a listen address only accepts local connections
+func isLoopback(listen string) bool {
+	host, _, err := net.SplitHostPort(listen)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
@@ -0,0 +1,46 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package serve
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCommand(t *testing.T) {
+	cmd := NewCommand()
+
+	assert.Equal(t, "serve", cmd.Use)
+	assert.NotEmpty(t, cmd.Short)
+	assert.NotEmpty(t, cmd.Long)
+	assert.Equal(t, "127.0.0.1:7777", cmd.Flags().Lookup("listen").DefValue)
+	assert.NotNil(t, cmd.Flags().Lookup("token"))
+	assert.Error(t, cmd.Args(cmd, []string{"extra"}))
+}
+
+func TestIsLoopback(t *testing.T) {
+	assert.True(t, isLoopback("127.0.0.1:7777"))
+	assert.True(t, isLoopback("localhost:7777"))
+	assert.True(t, isLoopback("[::1]:7777"))
+	assert.False(t, isLoopback(":7777"))
+	assert.False(t, isLoopback("0.0.0.0:7777"))
+	assert.False(t, isLoopback("10.0.0.5:7777"))
+}
+
+func TestGenerateToken(t *testing.T) {
+	first, err := generateToken()
+	require.NoError(t, err)
+	second, err := generateToken()
+	require.NoError(t, err)
+	assert.Len(t, first, 64)
+	assert.NotEqual(t, first, second)
+}
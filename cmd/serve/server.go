@@ -0,0 +1,319 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package serve
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/gifflet/ccmd/pkg/ccmd"
+)
+
+// maxBodySize limits request bodies; every request is a small JSON object
+const maxBodySize = 1 << 20
+
+// installRequest is the body of POST /v1/commands
+type installRequest struct {
+	Repository   string `json:"repository"`
+	Version      string `json:"version,omitempty"`
+	Name         string `json:"name,omitempty"`
+	Rename       string `json:"rename,omitempty"`
+	As           string `json:"as,omitempty"`
+	Force        bool   `json:"force,omitempty"`
+	SaveStrategy string `json:"save_strategy,omitempty"`
+	Profile      string `json:"profile,omitempty"`
+	Pre          bool   `json:"pre,omitempty"`
+
+	OverwriteLocal bool `json:"overwrite_local,omitempty"`
+	Backup         bool `json:"backup,omitempty"`
+}
+
+// syncRequest is the body of POST /v1/sync
+type syncRequest struct {
+	DryRun  bool   `json:"dry_run,omitempty"`
+	Prune   bool   `json:"prune,omitempty"`
+	Profile string `json:"profile,omitempty"`
+	Refresh bool   `json:"refresh,omitempty"`
+	Frozen  bool   `json:"frozen,omitempty"`
+}
+
+// updateRequest is the body of POST /v1/update; an empty name updates every command
+type updateRequest struct {
+	Name      string `json:"name,omitempty"`
+	CheckOnly bool   `json:"check_only,omitempty"`
+	Force     bool   `json:"force,omitempty"`
+
+	OverwriteLocal bool `json:"overwrite_local,omitempty"`
+	Backup         bool `json:"backup,omitempty"`
+}
+
+type commandResponse struct {
+	Name        string   `json:"name"`
+	Type        string   `json:"type"`
+	Version     string   `json:"version"`
+	Description string   `json:"description,omitempty"`
+	Author      string   `json:"author,omitempty"`
+	Repository  string   `json:"repository,omitempty"`
+	Resolved    string   `json:"resolved,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	InstalledAt string   `json:"installed_at,omitempty"`
+	UpdatedAt   string   `json:"updated_at,omitempty"`
+	Broken      bool     `json:"broken,omitempty"`
+	Problem     string   `json:"problem,omitempty"`
+}
+
+type failureResponse struct {
+	Name      string `json:"name"`
+	Operation string `json:"operation"`
+	Error     string `json:"error"`
+}
+
+type syncResponse struct {
+	Installed []string          `json:"installed"`
+	Removed   []string          `json:"removed"`
+	Pruned    []string          `json:"pruned"`
+	Failed    []failureResponse `json:"failed"`
+}
+
+type updateResponse struct {
+	Updated int `json:"updated"`
+	Failed  int `json:"failed"`
+	Checked int `json:"checked"`
+	Skipped int `json:"skipped"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// newHandler returns the HTTP API for a project. Every endpoint except /healthz requires
+// an "Authorization: Bearer <token>" header. When hosts is set, requests addressed to
+// other host names are refused.
+func newHandler(client *ccmd.Client, token string, hosts []string) http.Handler {
+	api := http.NewServeMux()
+
+	api.HandleFunc("GET /v1/commands", func(w http.ResponseWriter, r *http.Request) {
+		commands, err := client.List(r.Context())
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		response := make([]commandResponse, 0, len(commands))
+		for i := range commands {
+			response = append(response, toResponse(&commands[i]))
+		}
+		writeJSON(w, http.StatusOK, response)
+	})
+
+	api.HandleFunc("POST /v1/commands", func(w http.ResponseWriter, r *http.Request) {
+		var req installRequest
+		if !readJSON(w, r, &req) {
+			return
+		}
+		if req.Repository == "" {
+			writeJSON(w, http.StatusBadRequest, errorResponse{Error: "repository is required"})
+			return
+		}
+
+		cmd, err := client.Install(r.Context(), ccmd.InstallOptions(req))
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusCreated, toResponse(cmd))
+	})
+
+	api.HandleFunc("DELETE /v1/commands/{name}", func(w http.ResponseWriter, r *http.Request) {
+		save := r.URL.Query().Get("save") == "true"
+		if err := client.Remove(r.Context(), r.PathValue("name"), ccmd.RemoveOptions{Save: save}); err != nil {
+			writeError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	api.HandleFunc("POST /v1/sync", func(w http.ResponseWriter, r *http.Request) {
+		var req syncRequest
+		if !readJSON(w, r, &req) {
+			return
+		}
+
+		result, err := client.Sync(r.Context(), ccmd.SyncOptions(req))
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		response := syncResponse{
+			Installed: result.Installed,
+			Removed:   result.Removed,
+			Pruned:    result.Pruned,
+			Failed:    make([]failureResponse, 0, len(result.Failed)),
+		}
+		for _, failure := range result.Failed {
+			response.Failed = append(response.Failed, failureResponse{
+				Name:      failure.Name,
+				Operation: failure.Operation,
+				Error:     failure.Err.Error(),
+			})
+		}
+		writeJSON(w, http.StatusOK, response)
+	})
+
+	api.HandleFunc("POST /v1/update", func(w http.ResponseWriter, r *http.Request) {
+		var req updateRequest
+		if !readJSON(w, r, &req) {
+			return
+		}
+
+		result, err := client.Update(r.Context(), ccmd.UpdateOptions{
+			Name:      req.Name,
+			CheckOnly: req.CheckOnly,
+			Force:     req.Force,
+
+			OverwriteLocal: req.OverwriteLocal,
+			Backup:         req.Backup,
+		})
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, updateResponse(*result))
+	})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	})
+	mux.Handle("/v1/", requireToken(token, oneAtATime(api)))
+
+	return checkOrigin(hosts, mux)
+}
+
+// requireToken rejects requests without the bearer token
+func requireToken(token string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		given, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || token == "" || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="ccmd"`)
+			writeJSON(w, http.StatusUnauthorized, errorResponse{Error: "missing or invalid token"})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// oneAtATime runs the requests of next one after another, as they all change the same
+// project
+func oneAtATime(next http.Handler) http.Handler {
+	var mu sync.Mutex
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// checkOrigin rejects requests addressed to a host name outside hosts, when set, and
+// requests a browser sends on behalf of a page from another origin.
+func checkOrigin(hosts []string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := hostName(r.Host)
+		if len(hosts) > 0 && !slices.Contains(hosts, host) {
+			writeJSON(w, http.StatusForbidden, errorResponse{Error: "host " + r.Host + " is not allowed"})
+			return
+		}
+		if origin := r.Header.Get("Origin"); origin != "" {
+			u, err := url.Parse(origin)
+			if err != nil || !strings.EqualFold(u.Host, r.Host) {
+				writeJSON(w, http.StatusForbidden, errorResponse{Error: "origin " + origin + " is not allowed"})
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// hostName strips the port from a Host header
+func hostName(host string) string {
+	if name, _, err := net.SplitHostPort(host); err == nil {
+		return name
+	}
+	return strings.Trim(host, "[]")
+}
+
+func toResponse(cmd *ccmd.Command) commandResponse {
+	return commandResponse{
+		Name:        cmd.Name,
+		Type:        cmd.Type,
+		Version:     cmd.Version,
+		Description: cmd.Description,
+		Author:      cmd.Author,
+		Repository:  cmd.Repository,
+		Resolved:    cmd.Resolved,
+		Tags:        cmd.Tags,
+		InstalledAt: cmd.InstalledAt,
+		UpdatedAt:   cmd.UpdatedAt,
+		Broken:      cmd.Broken,
+		Problem:     cmd.Problem,
+	}
+}
+
+// readJSON decodes the request body into v. An empty body leaves v unchanged; other
+// bodies must be sent as application/json.
+func readJSON(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if r.ContentLength != 0 {
+		mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if err != nil || mediaType != "application/json" {
+			writeJSON(w, http.StatusUnsupportedMediaType, errorResponse{Error: "request body must be application/json"})
+			return false
+		}
+	}
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil && err != io.EOF {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: "invalid request body: " + err.Error()})
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError maps ccmd errors to HTTP status codes
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, ccmd.ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, ccmd.ErrAlreadyExists), errors.Is(err, ccmd.ErrConflict):
+		status = http.StatusConflict
+	case errors.Is(err, ccmd.ErrInvalidInput):
+		status = http.StatusBadRequest
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		status = http.StatusServiceUnavailable
+	}
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
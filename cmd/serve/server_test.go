@@ -0,0 +1,185 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package serve
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gifflet/ccmd/pkg/ccmd"
+)
+
+const testLock = `version: "1.0"
+lockfileVersion: 1
+commands:
+  hello:
+    name: hello
+    version: 1.0.0
+    source: https://github.com/test/hello.git
+    resolved: https://github.com/test/hello.git@1.0.0
+    commit: abc123
+    installed_at: 2025-01-01T00:00:00Z
+    updated_at: 2025-01-01T00:00:00Z
+`
+
+func setupServer(t *testing.T, token string) *httptest.Server {
+	t.Helper()
+
+	dir := t.TempDir()
+	commandDir := filepath.Join(dir, ".claude", "commands", "hello")
+	require.NoError(t, os.MkdirAll(commandDir, 0o755))
+
+	files := map[string]string{
+		filepath.Join(dir, "ccmd.yaml"):      "commands:\n  - test/hello@1.0.0\n",
+		filepath.Join(dir, "ccmd-lock.yaml"): testLock,
+		filepath.Join(commandDir, "ccmd.yaml"): "name: hello\nversion: 1.0.0\ndescription: Says hello\n" +
+			"author: tester\nrepository: https://github.com/test/hello.git\nentry: index.md\n",
+		filepath.Join(commandDir, "index.md"): "Say hello",
+	}
+	for path, content := range files {
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+
+	server := httptest.NewServer(newHandler(ccmd.New(dir), token, loopbackHosts))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func request(t *testing.T, method, url, token, body string) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	require.NoError(t, err)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = resp.Body.Close() })
+	return resp
+}
+
+func TestServerAuthentication(t *testing.T) {
+	server := setupServer(t, "secret")
+
+	resp := request(t, http.MethodGet, server.URL+"/v1/commands", "", "")
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	resp = request(t, http.MethodGet, server.URL+"/v1/commands", "wrong", "")
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	resp = request(t, http.MethodGet, server.URL+"/v1/commands", "secret", "")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp = request(t, http.MethodGet, server.URL+"/healthz", "", "")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestServerListAndRemove(t *testing.T) {
+	server := setupServer(t, "secret")
+
+	resp := request(t, http.MethodGet, server.URL+"/v1/commands", "secret", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var commands []map[string]interface{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&commands))
+	require.Len(t, commands, 1)
+	assert.Equal(t, "hello", commands[0]["name"])
+	assert.Equal(t, "Says hello", commands[0]["description"])
+
+	resp = request(t, http.MethodDelete, server.URL+"/v1/commands/missing", "secret", "")
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	resp = request(t, http.MethodDelete, server.URL+"/v1/commands/hello?save=true", "secret", "")
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	resp = request(t, http.MethodGet, server.URL+"/v1/commands", "secret", "")
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&commands))
+	assert.Empty(t, commands)
+}
+
+func TestServerSyncDryRun(t *testing.T) {
+	server := setupServer(t, "secret")
+
+	resp := request(t, http.MethodPost, server.URL+"/v1/sync", "secret", `{"dry_run": true}`)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var result syncResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	assert.Empty(t, result.Installed)
+	assert.Empty(t, result.Removed)
+}
+
+func TestServerRejectsBadRequests(t *testing.T) {
+	server := setupServer(t, "secret")
+
+	resp := request(t, http.MethodPost, server.URL+"/v1/commands", "secret", `{}`)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp = request(t, http.MethodPost, server.URL+"/v1/commands", "secret", `{"repo": "test/hello"}`)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp = request(t, http.MethodPost, server.URL+"/v1/sync", "secret", `not json`)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp = request(t, http.MethodGet, server.URL+"/v1/sync", "secret", "")
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+}
+
+func TestServerRejectsCrossSiteRequests(t *testing.T) {
+	server := setupServer(t, "secret")
+
+	send := func(mutate func(*http.Request)) *http.Response {
+		req, err := http.NewRequest(http.MethodPost, server.URL+"/v1/sync", strings.NewReader(`{"dry_run": true}`))
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer secret")
+		req.Header.Set("Content-Type", "application/json")
+		mutate(req)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = resp.Body.Close() })
+		return resp
+	}
+
+	resp := send(func(req *http.Request) { req.Header.Set("Content-Type", "text/plain") })
+	assert.Equal(t, http.StatusUnsupportedMediaType, resp.StatusCode)
+
+	// A page whose DNS name was rebound to the loopback address
+	resp = send(func(req *http.Request) { req.Host = "attacker.example:7777" })
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	resp = send(func(req *http.Request) { req.Header.Set("Origin", "https://attacker.example") })
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	resp = send(func(req *http.Request) { req.Header.Set("Origin", server.URL) })
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp = send(func(req *http.Request) { req.Host = strings.Replace(req.Host, "127.0.0.1", "localhost", 1) })
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestServerRequiresToken(t *testing.T) {
+	server := setupServer(t, "")
+
+	resp := request(t, http.MethodGet, server.URL+"/v1/commands", "", "")
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
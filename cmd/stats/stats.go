@@ -0,0 +1,130 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package stats
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gifflet/ccmd/core"
+	"github.com/gifflet/ccmd/pkg/output"
+)
+
+// NewCommand creates a new stats command.
+func NewCommand() *cobra.Command {
+	var jsonFormat bool
+
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Show local usage statistics for installed commands",
+		Long: `Show locally recorded statistics for installed commands and plugins.
+
+Reports install and update counts, the most recently updated commands, the time
+since the last sync, and commands that track a branch but have not been updated
+for a while. The data comes from ccmd-lock.yaml and the statistics kept under
+cache_dir; nothing is sent over the network.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runStats(jsonFormat)
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonFormat, "json", false, "Output in JSON format")
+
+	return cmd
+}
+
+func runStats(jsonFormat bool) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	report, err := core.Stats(cwd)
+	if err != nil {
+		return fmt.Errorf("failed to collect stats: %w", err)
+	}
+
+	if jsonFormat {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	printStats(report, time.Now())
+	return nil
+}
+
+func printStats(report *core.StatsReport, now time.Time) {
+	if len(report.Commands) == 0 {
+		output.PrintInfof("No commands or plugins installed yet.")
+		return
+	}
+
+	if report.LastSync.IsZero() {
+		output.PrintInfof("Last sync: never")
+	} else {
+		output.PrintInfof("Last sync: %s (%s ago)", report.LastSync.Format("2006-01-02 15:04"),
+			formatDuration(now.Sub(report.LastSync)))
+	}
+
+	output.PrintInfof("\nMost recently updated:\n")
+
+	header := fmt.Sprintf("%-24s %-8s %-12s %-10s %-9s %-8s", "NAME", "TYPE", "REF", "UPDATED", "INSTALLS", "UPDATES")
+	output.Printf(header)
+	output.Printf(strings.Repeat("-", len(header)))
+
+	var stale []core.CommandStats
+	for _, cmd := range report.Commands {
+		ref := cmd.Ref
+		if ref == "" {
+			ref = "-"
+		}
+		if len(ref) > 12 {
+			ref = ref[:9] + "..."
+		}
+		output.Printf("%-24s %-8s %-12s %-10s %-9d %-8d",
+			cmd.Name, cmd.Type, ref, formatDuration(now.Sub(cmd.UpdatedAt))+" ago",
+			cmd.InstallCount, cmd.UpdateCount)
+
+		if cmd.Stale {
+			stale = append(stale, cmd)
+		}
+	}
+
+	if len(stale) > 0 {
+		output.PrintWarningf("\nStale commands tracking a branch (not updated in %s):", formatDuration(core.StaleAfter))
+		for _, cmd := range stale {
+			output.PrintWarningf("  %s @ %s, last updated %s", cmd.Name, cmd.Ref, cmd.UpdatedAt.Format("2006-01-02"))
+		}
+		output.PrintInfof("Run 'ccmd update <name>' to refresh them.")
+	}
+}
+
+func formatDuration(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "<1m"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
@@ -0,0 +1,45 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCommand(t *testing.T) {
+	cmd := NewCommand()
+
+	assert.Equal(t, "stats", cmd.Use)
+	assert.NotEmpty(t, cmd.Short)
+	assert.NotEmpty(t, cmd.Long)
+
+	jsonFlag := cmd.Flags().Lookup("json")
+	assert.NotNil(t, jsonFlag)
+	assert.Equal(t, "false", jsonFlag.DefValue)
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		input    time.Duration
+		expected string
+	}{
+		{30 * time.Second, "<1m"},
+		{5 * time.Minute, "5m"},
+		{3 * time.Hour, "3h"},
+		{72 * time.Hour, "3d"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, formatDuration(tt.input))
+	}
+}
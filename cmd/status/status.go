@@ -0,0 +1,155 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package status
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gifflet/ccmd/core"
+	"github.com/gifflet/ccmd/pkg/i18n"
+	"github.com/gifflet/ccmd/pkg/output"
+)
+
+// NewCommand creates a new status command.
+func NewCommand() *cobra.Command {
+	var jsonFormat, short, check, noIndex bool
+
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show an overview of the installed commands",
+		Long: `Show a one-screen overview of the project: installed commands and plugins,
+entries out of sync with ccmd.yaml, commands with broken structures, available
+updates, the last sync time and the size of the cache.
+
+Only local data is read (ccmd-lock.yaml, the installed files and the cached tag
+lists), so it is fast enough for shell prompts and git hooks. Available updates
+are as recent as the tag cache, which sync and update refresh.
+
+Use --short for a single line and --check to exit with an error when the
+installation is out of sync or broken. Structure checks are read from the index
+in .claude/.ccmd-index.json; --no-index checks every command again.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if jsonFormat && short {
+				return fmt.Errorf("--json and --short cannot be used together")
+			}
+			return runStatus(core.StatusOptions{NoIndex: noIndex}, jsonFormat, short, check)
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonFormat, "json", false, "Output in JSON format")
+	cmd.Flags().BoolVar(&short, "short", false, "Print a single summary line")
+	cmd.Flags().BoolVar(&check, "check", false, "Exit with an error when out of sync or broken")
+	cmd.Flags().BoolVar(&noIndex, "no-index", false, "Check every command instead of reading the index")
+
+	return cmd
+}
+
+func runStatus(opts core.StatusOptions, jsonFormat, short, check bool) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	opts.ProjectPath = cwd
+
+	report, err := core.Status(opts)
+	if err != nil {
+		return fmt.Errorf("failed to collect status: %w", err)
+	}
+
+	switch {
+	case jsonFormat:
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+	case short:
+		output.Printf("%s", shortStatus(report))
+	default:
+		printStatus(report)
+	}
+
+	if check && !report.Clean() {
+		return fmt.Errorf("%d out of sync, %d broken", len(report.Drift), len(report.Broken))
+	}
+	return nil
+}
+
+// shortStatus summarizes the report in one line, leaving out what is fine
+func shortStatus(report *core.StatusReport) string {
+	parts := []string{i18n.Sprintf("%d installed", report.Installed)}
+	if n := len(report.Drift); n > 0 {
+		parts = append(parts, i18n.Sprintf("%d out of sync", n))
+	}
+	if n := brokenCommands(report); n > 0 {
+		parts = append(parts, i18n.Sprintf("%d broken", n))
+	}
+	if n := len(report.Updates); n > 0 {
+		parts = append(parts, i18n.Sprintf("%d update(s)", n))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// brokenCommands counts the commands with structure problems
+func brokenCommands(report *core.StatusReport) int {
+	names := make(map[string]bool)
+	for _, issue := range report.Broken {
+		names[issue.Name] = true
+	}
+	return len(names)
+}
+
+func printStatus(report *core.StatusReport) {
+	output.Printf("%s %d command(s), %d plugin(s)", output.Label(i18n.T("Installed:")), report.Installed, report.Plugins)
+
+	if report.LastSync.IsZero() {
+		output.Printf("%s never", output.Label(i18n.T("Last sync:")))
+	} else {
+		output.Printf("%s %s", output.Label(i18n.T("Last sync:")), report.LastSync.Local().Format("2006-01-02 15:04"))
+	}
+	output.Printf("%s %s", output.Label(i18n.T("Cache:")), core.FormatSize(report.CacheSize))
+
+	if len(report.Drift) == 0 {
+		output.Printf("%s %s", output.Label(i18n.T("Sync:")), output.Success(i18n.Sprintf("in sync with %s", core.ConfigFileName)))
+	} else {
+		output.Printf("%s %s", output.Label(i18n.T("Sync:")), output.Warning(i18n.Sprintf("%d out of sync", len(report.Drift))))
+		for _, drift := range report.Drift {
+			output.Printf("  %s: %s", drift.Name, drift.Reason)
+		}
+	}
+
+	if len(report.Broken) == 0 {
+		output.Printf("%s %s", output.Label(i18n.T("Structure:")), output.Success(i18n.T("healthy")))
+	} else {
+		output.Printf("%s %s", output.Label(i18n.T("Structure:")), output.Error(i18n.Sprintf("%d broken", brokenCommands(report))))
+		for _, issue := range report.Broken {
+			output.Printf("  %s: %s", issue.Name, issue.Reason)
+		}
+	}
+
+	if len(report.Updates) == 0 {
+		output.Printf("%s none known", output.Label(i18n.T("Updates:")))
+	} else {
+		output.Printf("%s %d available", output.Label(i18n.T("Updates:")), len(report.Updates))
+		for _, update := range report.Updates {
+			output.Printf("  %s: %s → %s", update.Name, update.Current, update.Latest)
+		}
+	}
+
+	if !report.Clean() {
+		output.PrintInfof("\nRun 'ccmd sync' to bring the installation in line with %s.", core.ConfigFileName)
+	}
+}
@@ -0,0 +1,44 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package status
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gifflet/ccmd/core"
+)
+
+func TestNewCommand(t *testing.T) {
+	cmd := NewCommand()
+
+	assert.Equal(t, "status", cmd.Use)
+	assert.NotEmpty(t, cmd.Short)
+	assert.NotEmpty(t, cmd.Long)
+
+	for _, name := range []string{"json", "short", "check", "no-index"} {
+		flag := cmd.Flags().Lookup(name)
+		assert.NotNil(t, flag, name)
+		assert.Equal(t, "false", flag.DefValue, name)
+	}
+}
+
+func TestShortStatus(t *testing.T) {
+	assert.Equal(t, "3 installed", shortStatus(&core.StatusReport{Installed: 3}))
+
+	report := &core.StatusReport{
+		Installed: 3,
+		Drift:     []core.StatusIssue{{Name: "acme/a", Reason: "missing from ccmd-lock.yaml"}},
+		Broken:    []core.StatusIssue{{Name: "b", Reason: "ccmd.yaml is missing"}, {Name: "b", Reason: "Command directory is missing"}},
+		Updates:   []core.StatusUpdate{{Name: "c", Current: "v1.0.0", Latest: "v1.1.0"}},
+	}
+	assert.Equal(t, "3 installed, 1 out of sync, 1 broken, 1 update(s)", shortStatus(report))
+}
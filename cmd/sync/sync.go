@@ -11,7 +11,11 @@ package sync
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"os"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/spf13/cobra"
 
@@ -22,8 +26,17 @@ import (
 // NewCommand creates the sync command
 func NewCommand() *cobra.Command {
 	var (
-		dryRun bool
-		force  bool
+		dryRun  bool
+		force   bool
+		prune   bool
+		profile string
+		refresh bool
+		frozen  bool
+		plan    string
+		undo    bool
+		adopt   bool
+		pruneEp bool
+		asJSON  bool
 	)
 
 	cmd := &cobra.Command{
@@ -34,19 +47,96 @@ func NewCommand() *cobra.Command {
 This command will:
 - Install commands listed in ccmd.yaml but not installed
 - Remove commands installed but not in ccmd.yaml
-- Update ccmd-lock.yaml to reflect current state`,
+- Update ccmd-lock.yaml to reflect current state
+
+Command directories and generated .md files under .claude/commands that
+ccmd-lock.yaml does not track (left behind by failed installs, renames or manual
+changes) are reported as orphans. Use --prune to move them to the trash; they can
+be recovered with 'ccmd restore <name>'. Hand-written .md commands are never touched.
+
+Commands listed under ccmd.yaml profiles are kept but not installed by a plain sync.
+With --profile, the project is synced to the shared commands plus that profile's
+commands, and commands belonging only to other profiles are removed.
+
+Remote tags needed to resolve versions are listed in parallel before installing and
+cached under the cache directory for tag_cache_ttl seconds (10 minutes by default),
+so repeated syncs do not contact every remote again. Use --refresh to bypass the cache.
+
+With --frozen (or --locked), meant for CI, commands are installed exactly as
+ccmd-lock.yaml records them and neither ccmd.yaml nor ccmd-lock.yaml is written.
+The sync fails without changing anything when ccmd.yaml lists a command the lock
+file is missing, requests a version the locked one does not satisfy, or when the
+lock file holds commands ccmd.yaml no longer lists.
+
+With --plan, the changes of a plan written by 'ccmd plan --out <file>' are made
+exactly, at the versions and commits it records. The sync fails without changing
+anything when ccmd.yaml or the installed commands changed since the plan was made.
+
+Commands installed with 'ccmd install --no-save' are ephemeral: ccmd.yaml does not
+list them and sync neither removes nor reinstalls them. Sync lists them and, in a
+terminal, offers to adopt or prune them. --adopt records them in ccmd.yaml at their
+locked version; --prune-ephemeral moves them to the trash.
+
+With --dry-run, the changes are shown as the plan 'ccmd plan' computes: every
+command with its action (install, update, remove or keep), its current and target
+versions, its source and the reason. --json prints the plan document 'ccmd plan
+--json' emits instead, for scripts. A dry run with --frozen lists the commands
+only, as versions then come from ccmd-lock.yaml.
+
+With --rollback, the last change to ccmd.yaml and ccmd-lock.yaml is undone and the
+installed commands are synced back to it, as 'ccmd rollback --last' does.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runSync(dryRun, force)
+			if undo {
+				if plan != "" || frozen || dryRun {
+					return fmt.Errorf("--rollback cannot be combined with --plan, --frozen or --dry-run")
+				}
+				snapshot, err := core.RestoreSnapshot(cmd.Context(), core.RestoreSnapshotOptions{})
+				if err != nil {
+					return err
+				}
+				output.PrintSuccessf("Rolled the project back to before the %s of %s", snapshot.Operation,
+					snapshot.CreatedAt.Local().Format("2006-01-02 15:04:05"))
+				return nil
+			}
+			if asJSON && (!dryRun || frozen || plan != "") {
+				return fmt.Errorf("--json needs --dry-run and cannot be combined with --frozen or --plan")
+			}
+			if plan != "" {
+				if frozen || refresh {
+					return fmt.Errorf("--plan cannot be combined with --frozen or --refresh")
+				}
+				return runPlan(cmd.Context(), plan, profile, dryRun, force, prune)
+			}
+			if adopt && pruneEp {
+				return fmt.Errorf("--adopt cannot be combined with --prune-ephemeral")
+			}
+			if frozen && (adopt || pruneEp) {
+				return fmt.Errorf("--frozen writes neither ccmd.yaml nor ccmd-lock.yaml and cannot adopt or prune ephemeral commands")
+			}
+			if dryRun && !frozen {
+				return runDryRun(cmd.Context(), profile, refresh, prune, adopt, pruneEp, asJSON)
+			}
+			return runSync(cmd.Context(), profile, dryRun, force, prune, refresh, frozen, adopt, pruneEp)
 		},
 	}
 
 	cmd.Flags().BoolVarP(&dryRun, "dry-run", "n", false, "Show what would be done without making changes")
 	cmd.Flags().BoolVarP(&force, "force", "f", false, "Force sync without confirmation")
+	cmd.Flags().BoolVar(&prune, "prune", false, "Move orphaned command files to the trash")
+	cmd.Flags().StringVar(&profile, "profile", "", "Sync the shared commands plus this ccmd.yaml profile")
+	cmd.Flags().BoolVar(&refresh, "refresh", false, "List remote tags again instead of using the tag cache")
+	cmd.Flags().BoolVar(&frozen, "frozen", false, "Install from ccmd-lock.yaml without writing it; fail if it is out of date")
+	cmd.Flags().BoolVar(&frozen, "locked", false, "Same as --frozen")
+	cmd.Flags().StringVar(&plan, "plan", "", "Apply exactly the plan in this file, written by 'ccmd plan --out'")
+	cmd.Flags().BoolVar(&undo, "rollback", false, "Undo the last change to ccmd.yaml and ccmd-lock.yaml")
+	cmd.Flags().BoolVar(&adopt, "adopt", false, "Record commands installed with --no-save in ccmd.yaml")
+	cmd.Flags().BoolVar(&pruneEp, "prune-ephemeral", false, "Move commands installed with --no-save to the trash")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "With --dry-run, print the sync plan as JSON")
 
 	return cmd
 }
 
-func runSync(dryRun, force bool) error {
+func runSync(ctx context.Context, profile string, dryRun, force, prune, refresh, frozen, adopt, pruneEphemeral bool) error {
 	// Get current directory
 	cwd, err := os.Getwd()
 	if err != nil {
@@ -54,18 +144,38 @@ func runSync(dryRun, force bool) error {
 	}
 
 	// Analyze what needs to be done
-	analysis, err := core.AnalyzeSync(cwd)
+	analysis, err := core.AnalyzeSync(cwd, profile)
 	if err != nil {
 		return err
 	}
 
+	if frozen {
+		if err := core.VerifyLock(cwd, profile); err != nil {
+			return err
+		}
+	}
+
+	orphans, err := core.FindOrphans(cwd)
+	if err != nil {
+		return err
+	}
+
+	for _, spec := range analysis.Skipped {
+		output.PrintVerbosef("Skipping %s: its condition does not hold on this machine", spec)
+	}
+
 	// Show analysis
-	if analysis.InSync {
+	if analysis.InSync && len(orphans) == 0 && len(analysis.Ephemeral) == 0 {
 		output.PrintInfof("✓ Commands are already in sync with ccmd.yaml")
 		return nil
 	}
 
-	output.PrintInfof("=== Sync Analysis ===")
+	if analysis.InSync {
+		output.PrintInfof("✓ Commands are already in sync with ccmd.yaml")
+	} else {
+		output.PrintInfof("=== Sync Analysis ===")
+	}
+
 	if len(analysis.ToInstall) > 0 {
 		output.PrintInfof("\nCommands to install:")
 		for _, cmd := range analysis.ToInstall {
@@ -80,24 +190,159 @@ func runSync(dryRun, force bool) error {
 		}
 	}
 
+	printEphemeral(analysis.Ephemeral, adopt, pruneEphemeral)
+	printOrphans(orphans, prune)
+
 	if dryRun {
 		output.PrintInfof("\n(dry-run mode - no changes made)")
 		return nil
 	}
 
+	if len(analysis.Ephemeral) > 0 && !adopt && !pruneEphemeral && !frozen && !force && output.IsTerminal(os.Stdin) {
+		adopt, pruneEphemeral = promptEphemeral()
+	}
+
+	if analysis.InSync && !prune && !adopt && !pruneEphemeral {
+		return nil
+	}
+
 	// Execute sync
 	opts := core.SyncOptions{
 		ProjectPath: cwd,
 		DryRun:      dryRun,
 		Force:       force,
+		Prune:       prune,
+		Profile:     profile,
+		Refresh:     refresh,
+		Frozen:      frozen,
+
+		AdoptEphemeral: adopt,
+		PruneEphemeral: pruneEphemeral,
+	}
+
+	result, err := core.Sync(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	return printResult(result, frozen)
+}
+
+// runDryRun shows the plan a sync would carry out, as 'ccmd plan' computes it, with the
+// commands it keeps
+func runDryRun(ctx context.Context, profile string, refresh, prune, adopt, pruneEphemeral, asJSON bool) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
 	}
 
-	result, err := core.Sync(context.Background(), opts)
+	plan, err := core.ComputePlan(ctx, core.PlanOptions{ProjectPath: cwd, Profile: profile, Refresh: refresh})
 	if err != nil {
 		return err
 	}
+	if asJSON {
+		data, err := json.MarshalIndent(plan, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
 
-	// Show results
+	kept, err := core.KeptCommands(cwd, plan)
+	if err != nil {
+		return err
+	}
+	orphans, err := core.FindOrphans(cwd)
+	if err != nil {
+		return err
+	}
+
+	if plan.Empty() {
+		output.PrintInfof("✓ Commands are already in sync with ccmd.yaml")
+	} else {
+		output.PrintInfof("=== Sync Plan ===")
+	}
+	if actions := append(append([]core.PlanAction{}, plan.Actions...), kept...); len(actions) > 0 {
+		output.Printf("")
+		printActions(actions)
+		output.PrintInfof("\nPlan: %d to install, %d to update, %d to remove, %d to keep",
+			plan.Count(core.PlanInstall), plan.Count(core.PlanUpdate), plan.Count(core.PlanRemove), len(kept))
+	}
+
+	var ephemeral []string
+	for _, action := range kept {
+		if action.Reason == core.PlanEphemeral {
+			ephemeral = append(ephemeral, action.Name)
+		}
+	}
+	if adopt || pruneEphemeral {
+		printEphemeral(ephemeral, adopt, pruneEphemeral)
+	}
+	printOrphans(orphans, prune)
+
+	output.PrintInfof("\n(dry-run mode - no changes made)")
+	return nil
+}
+
+// printActions lists planned and kept commands in columns, each with its reason
+func printActions(actions []core.PlanAction) {
+	symbols := map[string]string{core.PlanInstall: "+", core.PlanUpdate: "~", core.PlanRemove: "-", core.PlanKeep: "="}
+
+	nameWidth, changeWidth := len("NAME"), len("VERSION")
+	for _, action := range actions {
+		nameWidth = max(nameWidth, utf8.RuneCountInString(action.Name))
+		changeWidth = max(changeWidth, utf8.RuneCountInString(action.Change()))
+	}
+
+	output.Printf("  %-9s  %-*s  %-*s  %s", "ACTION", nameWidth, "NAME", changeWidth, "VERSION", "SOURCE")
+	for _, action := range actions {
+		output.Printf("  %s %-7s  %-*s  %-*s  %s", symbols[action.Action], action.Action,
+			nameWidth, action.Name, changeWidth, action.Change(), action.Repository)
+		output.Printf("      %s: %s", action.Reason, action.Detail)
+	}
+}
+
+// runPlan applies a plan written by 'ccmd plan'
+func runPlan(ctx context.Context, path, profile string, dryRun, force, prune bool) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	plan, err := core.ReadPlan(path)
+	if err != nil {
+		return err
+	}
+
+	opts := core.SyncOptions{
+		ProjectPath: cwd,
+		DryRun:      dryRun,
+		Force:       force,
+		Prune:       prune,
+		Profile:     profile,
+		Plan:        plan,
+	}
+
+	output.PrintInfof("=== Plan %s ===", path)
+	for _, action := range plan.Actions {
+		output.Printf("  %s %s", action.Action, action.Summary())
+	}
+
+	result, err := core.Sync(ctx, opts)
+	if err != nil {
+		return err
+	}
+	if dryRun {
+		output.PrintInfof("\n(dry-run mode - the plan still applies, no changes made)")
+		return nil
+	}
+	return printResult(result, true)
+}
+
+// printResult shows what a sync changed. With strict set, failed operations make the
+// sync fail instead of only being reported.
+func printResult(result *core.SyncResult, strict bool) error {
 	if len(result.Installed) > 0 {
 		output.PrintInfof("\nInstalled commands:")
 		for _, cmd := range result.Installed {
@@ -105,25 +350,102 @@ func runSync(dryRun, force bool) error {
 		}
 	}
 
+	if len(result.Updated) > 0 {
+		output.PrintInfof("\nUpdated commands:")
+		for _, name := range result.Updated {
+			output.PrintSuccessf("  ✓ %s", name)
+		}
+	}
+
+	if len(result.Adopted) > 0 {
+		output.PrintInfof("\nAdopted into ccmd.yaml:")
+		for _, name := range result.Adopted {
+			output.PrintSuccessf("  ✓ %s", name)
+		}
+	}
+
 	if len(result.Removed) > 0 {
 		output.PrintInfof("\nRemoved commands:")
 		for _, name := range result.Removed {
 			output.PrintSuccessf("  ✓ %s", name)
 		}
+		output.PrintInfof("Removed commands can be recovered with 'ccmd restore <name>'")
 	}
 
-	if len(result.Failed) > 0 {
-		output.PrintErrorf("\nFailed operations:")
-		for _, failure := range result.Failed {
-			output.PrintErrorf("  ✗ %s %s: %v", failure.Operation, failure.Command, failure.Error)
+	if len(result.Pruned) > 0 {
+		output.PrintInfof("\nPruned orphaned files:")
+		for _, name := range result.Pruned {
+			output.PrintSuccessf("  ✓ %s", name)
 		}
+		output.PrintInfof("Pruned files can be recovered with 'ccmd restore <name>'")
 	}
 
 	if len(result.Failed) == 0 {
 		output.PrintSuccessf("\n✓ Sync completed successfully")
+	} else if strict {
+		return result.Err()
 	} else {
-		output.PrintWarningf("\n⚠ Sync completed with %d error(s)", len(result.Failed))
+		output.PrintFailures(result.Err())
+		output.PrintWarningf("⚠ Sync completed with %d error(s)", len(result.Failed))
 	}
 
 	return nil
 }
+
+// printEphemeral lists the commands installed with --no-save and what the sync does with them
+func printEphemeral(names []string, adopt, prune bool) {
+	if len(names) == 0 {
+		return
+	}
+
+	switch {
+	case adopt:
+		output.PrintInfof("\nEphemeral commands to adopt into ccmd.yaml:")
+	case prune:
+		output.PrintInfof("\nEphemeral commands to remove:")
+	default:
+		output.PrintInfof("\nEphemeral commands (installed with --no-save, not in ccmd.yaml):")
+	}
+	for _, name := range names {
+		output.Printf("  ~ %s", name)
+	}
+	if !adopt && !prune {
+		output.PrintInfof("Run 'ccmd sync --adopt' to save them or 'ccmd sync --prune-ephemeral' to remove them")
+	}
+}
+
+// promptEphemeral asks whether to adopt, prune or keep the ephemeral commands
+func promptEphemeral() (adopt, prune bool) {
+	output.Printf("Adopt them into ccmd.yaml, prune them, or keep them? [a/p/K]: ")
+
+	var response string
+	_, _ = fmt.Scanln(&response)
+	switch strings.ToLower(strings.TrimSpace(response)) {
+	case "a", "adopt":
+		return true, false
+	case "p", "prune":
+		return false, true
+	}
+	return false, false
+}
+
+// printOrphans lists untracked command files found before syncing
+func printOrphans(orphans []core.Orphan, prune bool) {
+	if len(orphans) == 0 {
+		return
+	}
+
+	if prune {
+		output.PrintInfof("\nOrphaned files to prune:")
+	} else {
+		output.PrintInfof("\nOrphaned files (not tracked by ccmd-lock.yaml):")
+	}
+	for _, orphan := range orphans {
+		for _, path := range orphan.Paths {
+			output.Printf("  ? %s", path)
+		}
+	}
+	if !prune {
+		output.PrintInfof("Run 'ccmd sync --prune' to move them to the trash")
+	}
+}
@@ -31,6 +31,28 @@ func TestNewCommand(t *testing.T) {
 	assert.NotNil(t, forceFlag)
 	assert.Equal(t, "false", forceFlag.DefValue)
 	assert.Equal(t, "f", forceFlag.Shorthand)
+
+	pruneFlag := cmd.Flags().Lookup("prune")
+	assert.NotNil(t, pruneFlag)
+	assert.Equal(t, "false", pruneFlag.DefValue)
+
+	profileFlag := cmd.Flags().Lookup("profile")
+	assert.NotNil(t, profileFlag)
+	assert.Equal(t, "", profileFlag.DefValue)
+
+	refreshFlag := cmd.Flags().Lookup("refresh")
+	assert.NotNil(t, refreshFlag)
+	assert.Equal(t, "false", refreshFlag.DefValue)
+
+	planFlag := cmd.Flags().Lookup("plan")
+	assert.NotNil(t, planFlag)
+	assert.Equal(t, "", planFlag.DefValue)
+
+	for _, name := range []string{"frozen", "locked", "rollback", "adopt", "prune-ephemeral", "json"} {
+		flag := cmd.Flags().Lookup(name)
+		assert.NotNil(t, flag, name)
+		assert.Equal(t, "false", flag.DefValue)
+	}
 }
 
 // Note: Full integration tests for sync command would require
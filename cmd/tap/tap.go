@@ -0,0 +1,129 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package tap
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gifflet/ccmd/core"
+	"github.com/gifflet/ccmd/pkg/output"
+)
+
+// NewCommand creates a new tap command.
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tap",
+		Short: "Add, list and remove third-party catalogs",
+		Long: `Manage taps: named third-party catalogs of commands, stored in the user config.
+
+A tap is a catalog in the format of the 'catalogs' setting, a YAML or JSON file
+served over HTTP(S) or read from disk. Catalogs of taps are cached in
+<cache_dir>/taps and refreshed after tag_cache_ttl seconds.
+
+Taps are searched by 'ccmd search --remote' and 'ccmd browse', and
+'ccmd install <tap>/<command>' installs the repository the tap lists for a
+command. The repository is what ccmd.yaml records.`,
+		Example: `  # Add a community catalog
+  ccmd tap add acme https://acme.example.com/ccmd/catalog.yaml
+
+  # Install a command it lists
+  ccmd install acme/review
+
+  # Show and remove taps
+  ccmd tap list
+  ccmd tap remove acme`,
+	}
+
+	cmd.AddCommand(newAddCommand())
+	cmd.AddCommand(newListCommand())
+	cmd.AddCommand(newRemoveCommand())
+
+	return cmd
+}
+
+func newAddCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "add <name> <url>",
+		Short: "Add a catalog as a tap",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runAdd(cmd.Context(), args[0], args[1])
+		},
+	}
+}
+
+func newListCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List the configured taps",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runList()
+		},
+	}
+}
+
+func newRemoveCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "remove <name>",
+		Short: "Remove a tap and its cached catalog",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRemove(args[0])
+		},
+	}
+}
+
+func runAdd(ctx context.Context, name, url string) error {
+	spinner := output.NewSpinner(fmt.Sprintf("Fetching catalog of %s...", name))
+	spinner.Start()
+	count, err := core.AddTap(ctx, name, url)
+	spinner.Stop()
+	if err != nil {
+		return err
+	}
+
+	output.PrintSuccessf("Tapped %s (%d command(s))", name, count)
+	output.PrintInfof("Install its commands with 'ccmd install %s/<command>'", name)
+	return nil
+}
+
+func runList() error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	taps, err := core.ListTaps(cwd)
+	if err != nil {
+		return err
+	}
+	if len(taps) == 0 {
+		output.PrintInfof("No taps configured. Add one with 'ccmd tap add <name> <url>'.")
+		return nil
+	}
+
+	for _, tap := range taps {
+		output.Printf("%-20s %s", tap.Name, tap.URL)
+	}
+	return nil
+}
+
+func runRemove(name string) error {
+	if err := core.RemoveTap(name); err != nil {
+		return err
+	}
+	output.PrintSuccessf("Removed tap %s", name)
+	return nil
+}
@@ -0,0 +1,40 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package tap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCommand(t *testing.T) {
+	cmd := NewCommand()
+
+	assert.Equal(t, "tap", cmd.Use)
+	assert.NotEmpty(t, cmd.Short)
+	assert.NotEmpty(t, cmd.Long)
+
+	names := make([]string, 0, len(cmd.Commands()))
+	for _, sub := range cmd.Commands() {
+		names = append(names, sub.Name())
+	}
+	assert.ElementsMatch(t, []string{"add", "list", "remove"}, names)
+
+	add, _, err := cmd.Find([]string{"add"})
+	require.NoError(t, err)
+	assert.Error(t, add.Args(add, []string{"acme"}))
+	assert.NoError(t, add.Args(add, []string{"acme", "https://example.com/catalog.yaml"}))
+
+	remove, _, err := cmd.Find([]string{"remove"})
+	require.NoError(t, err)
+	assert.Error(t, remove.Args(remove, []string{}))
+}
@@ -0,0 +1,74 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package track
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gifflet/ccmd/core"
+	"github.com/gifflet/ccmd/pkg/output"
+)
+
+// NewCommand creates a new track command.
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "track <dir>...",
+		Short: "Track commands that live in the project as local commands",
+		Long: `Record command directories that live in the project itself, such as
+.claude/commands/team-style committed with the repository, in ccmd-lock.yaml with
+source local. Each argument is a directory under .claude/commands or a command name.
+
+Local commands are listed by 'ccmd list', checked by 'ccmd status' and 'ccmd verify',
+and kept by 'ccmd sync'. ccmd.yaml does not list them, and sync and update never
+contact a remote for them. A directory needs an index.md; a ccmd.yaml naming it as
+the entry is written when the directory has none.
+
+Tracking a local command again records its current files, after editing it.
+
+Examples:
+  # Track a directory committed with the project
+  ccmd track .claude/commands/team-style
+
+  # Record the files of a local command after editing it
+  ccmd track team-style`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTrack(args)
+		},
+	}
+
+	return cmd
+}
+
+func runTrack(dirs []string) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, dir := range dirs {
+		name, err := core.Track(core.TrackOptions{ProjectPath: cwd, Dir: dir})
+		if err != nil {
+			output.PrintErrorf("Failed to track %s: %v", dir, err)
+			failed++
+			continue
+		}
+		output.PrintSuccessf("Tracked %s as a local command", name)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d directories could not be tracked", failed, len(dirs))
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package unpin
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/gifflet/ccmd/core"
+	"github.com/gifflet/ccmd/pkg/output"
+)
+
+// NewCommand creates a new unpin command.
+func NewCommand() *cobra.Command {
+	var to string
+
+	cmd := &cobra.Command{
+		Use:   "unpin <command-name>...",
+		Short: "Let pinned commands follow updates again",
+		Long: `Let commands pinned with 'ccmd pin' follow updates again. The ccmd.yaml entry
+of each command gets back the version it requested before it was pinned, and
+the pin is cleared from ccmd-lock.yaml. Commands pinned by hand, with a full
+commit in ccmd.yaml, are unpinned too; they get a range for the installed version
+made with the save strategy (^1.2.0 by default).
+
+--to sets the version or range to request instead. Nothing is reinstalled; run
+'ccmd update' to move to the newest version the entry allows.`,
+		Example: `  # Restore the version requested before 'ccmd pin'
+  ccmd unpin review
+
+  # Follow a new range
+  ccmd unpin review --to "^2.0.0"`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			for _, name := range args {
+				result, err := core.Unpin(".", core.UnpinOptions{Name: name, To: to})
+				if err != nil {
+					return err
+				}
+				switch {
+				case result.Unchanged:
+					output.PrintInfof("%s is not pinned", name)
+				case result.Version == "":
+					output.PrintSuccessf("Unpinned %s", name)
+				default:
+					output.PrintSuccessf("Unpinned %s; ccmd.yaml requests %s", name, result.Version)
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&to, "to", "", "Version or range ccmd.yaml requests after unpinning")
+
+	return cmd
+}
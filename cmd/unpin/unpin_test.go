@@ -0,0 +1,29 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package unpin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCommand(t *testing.T) {
+	cmd := NewCommand()
+
+	assert.Equal(t, "unpin <command-name>...", cmd.Use)
+	assert.NotEmpty(t, cmd.Short)
+	assert.Error(t, cmd.Args(cmd, []string{}))
+	assert.NoError(t, cmd.Args(cmd, []string{"review"}))
+
+	to := cmd.Flags().Lookup("to")
+	assert.NotNil(t, to)
+	assert.Empty(t, to.DefValue)
+}
@@ -10,11 +10,14 @@
 package update
 
 import (
-	"context"
+	"fmt"
+	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 
 	"github.com/gifflet/ccmd/core"
+	"github.com/gifflet/ccmd/pkg/output"
 )
 
 // NewCommand creates the update command
@@ -23,6 +26,18 @@ func NewCommand() *cobra.Command {
 		all       bool
 		checkOnly bool
 		force     bool
+		yes       bool
+
+		overwriteLocal bool
+		backup         bool
+
+		saveExact bool
+		saveCaret bool
+		saveTilde bool
+		pre       bool
+
+		csv bool
+		tsv bool
 	)
 
 	cmd := &cobra.Command{
@@ -30,22 +45,57 @@ func NewCommand() *cobra.Command {
 		Short: "Update installed commands to their latest versions",
 		Long: `Update installed commands to their latest versions.
 
-With --all flag, it updates all installed commands.`,
+With --all flag, it updates all installed commands.
+
+When ccmd.yaml records a version range (for example ^1.2.0), the newest tag that
+satisfies it is installed. Before each update the version change and, when the
+repository has a CHANGELOG, the entries between the two versions are shown and
+confirmation is requested. The prompt shows how the command's index.md changes:
+the sections added (+), removed (-) and changed (~), with the lines added and
+removed, since the prompt text matters more than the version number. Use --yes
+to skip the prompt and the comparison; they are also skipped when stdin is not a
+terminal. The command files, ccmd.yaml and ccmd-lock.yaml are
+restored together if an update fails.
+
+The --save-exact, --save-caret and --save-tilde flags rewrite the ccmd.yaml
+constraint from the updated version. Without them, existing constraints are kept.
+
+Prerelease tags are only update targets for ccmd.yaml entries that set
+"channel: beta", or for every command with --pre. --check shows the channel of
+each command. With --all, --check prints a table of the commands with their
+current and target versions and an UPDATE or OK status; --csv and --tsv print it
+for scripts and spreadsheets.
+
+A command whose installed files were edited since install is not overwritten.
+Review the changes with 'ccmd diff', then pass --backup to keep a copy in
+.claude/.backups or --overwrite-local to discard them.`,
 		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			var name string
 			if len(args) > 0 {
 				name = args[0]
 			}
+			if (csv || tsv) && !(all && checkOnly) {
+				return fmt.Errorf("--csv and --tsv require --all and --check")
+			}
 
 			opts := core.UpdateOptions{
-				Name:      name,
-				All:       all,
-				CheckOnly: checkOnly,
-				Force:     force,
+				Name:         name,
+				All:          all,
+				CheckOnly:    checkOnly,
+				Force:        force,
+				SaveStrategy: saveStrategy(saveExact, saveCaret, saveTilde),
+				Pre:          pre,
+				Format:       output.TableFormat(csv, tsv),
+
+				OverwriteLocal: overwriteLocal,
+				Backup:         backup,
+			}
+			if !yes && output.IsTerminal(os.Stdin) {
+				opts.Confirm = promptConfirm
 			}
 
-			_, err := core.Update(context.Background(), opts)
+			_, err := core.Update(cmd.Context(), opts)
 			return err
 		},
 	}
@@ -53,6 +103,82 @@ With --all flag, it updates all installed commands.`,
 	cmd.Flags().BoolVarP(&all, "all", "a", false, "Update all installed commands")
 	cmd.Flags().BoolVarP(&checkOnly, "check", "c", false, "Only check for updates without installing")
 	cmd.Flags().BoolVarP(&force, "force", "f", false, "Force update even if version appears current")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Apply updates without asking for confirmation")
+	cmd.Flags().BoolVar(&overwriteLocal, "overwrite-local", false, "Discard local modifications to installed files")
+	cmd.Flags().BoolVar(&backup, "backup", false, "Copy locally modified files to .claude/.backups before updating")
+	cmd.Flags().BoolVar(&saveExact, "save-exact", false, "Record the exact installed version in ccmd.yaml")
+	cmd.Flags().BoolVar(&saveCaret, "save-caret", false, "Record a ^ constraint allowing minor and patch updates")
+	cmd.Flags().BoolVar(&saveTilde, "save-tilde", false, "Record a ~ constraint allowing patch updates")
+	cmd.Flags().BoolVar(&pre, "pre", false, "Allow updates to prerelease tags")
+	cmd.Flags().BoolVar(&csv, "csv", false, "With --all --check, print the result as comma-separated values")
+	cmd.Flags().BoolVar(&tsv, "tsv", false, "With --all --check, print the result as tab-separated values")
+	cmd.MarkFlagsMutuallyExclusive("save-exact", "save-caret", "save-tilde")
+	cmd.MarkFlagsMutuallyExclusive("csv", "tsv")
 
 	return cmd
 }
+
+// saveStrategy maps the --save-* flags to a core save strategy
+func saveStrategy(exact, caret, tilde bool) string {
+	switch {
+	case exact:
+		return core.SaveExact
+	case caret:
+		return core.SaveCaret
+	case tilde:
+		return core.SaveTilde
+	default:
+		return ""
+	}
+}
+
+// maxPromptSections caps the sections listed in the summary of prompt changes
+const maxPromptSections = 15
+
+// promptConfirm shows how the prompt changes and asks whether a pending update should be installed
+func promptConfirm(plan *core.UpdatePlan) bool {
+	printPromptDiff(plan.PromptDiff)
+	output.Printf("Update %s? [y/N]: ", plan.Name)
+
+	var response string
+	_, _ = fmt.Scanln(&response)
+	response = strings.ToLower(strings.TrimSpace(response))
+	return response == "y" || response == "yes"
+}
+
+// printPromptDiff summarizes the changes to the entry file: sections added (+), removed
+// (-) and changed (~), with the lines added and removed
+func printPromptDiff(diff *core.PromptDiff) {
+	if diff == nil {
+		return
+	}
+	if diff.Unchanged() {
+		output.PrintInfof("  Prompt: %s is unchanged", diff.File)
+		return
+	}
+
+	output.PrintInfof("  Prompt: %s %s", diff.File, lineCounts(diff.Added, diff.Removed))
+	for i, section := range diff.Sections {
+		if i == maxPromptSections {
+			output.Printf("    ... and %d more section(s)", len(diff.Sections)-i)
+			break
+		}
+		heading := section.Heading
+		if heading == "" {
+			heading = "(text before the first heading)"
+		}
+		switch section.Status {
+		case core.DiffAdded:
+			output.Printf("    %s %s", output.Success("+ "+heading), lineCounts(section.Added, 0))
+		case core.DiffRemoved:
+			output.Printf("    %s %s", output.Error("- "+heading), lineCounts(0, section.Removed))
+		default:
+			output.Printf("    %s %s", output.Warning("~ "+heading), lineCounts(section.Added, section.Removed))
+		}
+	}
+}
+
+// lineCounts formats "(+3 -1)" in the diff colors
+func lineCounts(added, removed int) string {
+	return fmt.Sprintf("(%s %s)", output.Success(fmt.Sprintf("+%d", added)), output.Error(fmt.Sprintf("-%d", removed)))
+}
@@ -10,9 +10,13 @@
 package update
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/gifflet/ccmd/core"
+	"github.com/gifflet/ccmd/pkg/output"
 )
 
 func TestNewCommand(t *testing.T) {
@@ -35,4 +39,37 @@ func TestNewCommand(t *testing.T) {
 	forceFlag := cmd.Flag("force")
 	assert.NotNil(t, forceFlag)
 	assert.Equal(t, "f", forceFlag.Shorthand)
+
+	yesFlag := cmd.Flag("yes")
+	assert.NotNil(t, yesFlag)
+	assert.Equal(t, "y", yesFlag.Shorthand)
+
+	for _, flag := range []string{"save-exact", "save-caret", "save-tilde", "overwrite-local", "backup", "pre"} {
+		assert.NotNil(t, cmd.Flag(flag), flag)
+	}
+}
+
+func TestPrintPromptDiff(t *testing.T) {
+	var buf bytes.Buffer
+	restore := output.SetOutput(&buf, &buf)
+	defer restore()
+
+	printPromptDiff(&core.PromptDiff{File: "index.md", Added: 4, Removed: 2, Sections: []core.SectionChange{
+		{Heading: "## Steps", Status: core.DiffModified, Added: 1, Removed: 1},
+		{Heading: "## Output", Status: core.DiffAdded, Added: 3},
+		{Heading: "", Status: core.DiffRemoved, Removed: 1},
+	}})
+	printed := buf.String()
+	assert.Contains(t, printed, "Prompt: index.md (+4 -2)")
+	assert.Contains(t, printed, "~ ## Steps (+1 -1)")
+	assert.Contains(t, printed, "+ ## Output (+3 -0)")
+	assert.Contains(t, printed, "- (text before the first heading) (+0 -1)")
+
+	buf.Reset()
+	printPromptDiff(&core.PromptDiff{File: "index.md"})
+	assert.Contains(t, buf.String(), "index.md is unchanged")
+
+	buf.Reset()
+	printPromptDiff(nil)
+	assert.Empty(t, buf.String())
 }
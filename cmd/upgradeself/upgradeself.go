@@ -0,0 +1,81 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package upgradeself
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gifflet/ccmd/core"
+	"github.com/gifflet/ccmd/pkg/output"
+)
+
+// NewCommand creates a new upgrade-self command for a binary of the given version.
+func NewCommand(version string) *cobra.Command {
+	var checkOnly, force bool
+
+	cmd := &cobra.Command{
+		Use:   "upgrade-self",
+		Short: "Upgrade ccmd to the latest release",
+		Long: `Check the latest ccmd release on GitHub and, when it is newer than the running
+version, replace the ccmd executable with it.
+
+The archive for this platform is downloaded from the release, checked against
+the release's checksums.txt and unpacked. The new binary must run before it is
+moved over the old one, so a failed upgrade leaves the installed version
+untouched. ccmd needs write access to the directory of its executable; installs
+managed by a package manager such as npm or Homebrew should be upgraded with it.
+
+Development builds are only replaced with --force.`,
+		Example: `  # Check for a newer release
+  ccmd upgrade-self --check-only
+
+  # Upgrade to the latest release
+  ccmd upgrade-self`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runUpgradeSelf(cmd.Context(), version, checkOnly, force)
+		},
+	}
+
+	cmd.Flags().BoolVar(&checkOnly, "check-only", false, "Only report whether a newer release exists")
+	cmd.Flags().BoolVar(&force, "force", false, "Install the latest release even if it is not newer")
+
+	return cmd
+}
+
+func runUpgradeSelf(ctx context.Context, version string, checkOnly, force bool) error {
+	spinner := output.NewSpinner("Checking latest release...")
+	spinner.Start()
+	result, err := core.SelfUpgrade(ctx, core.SelfUpgradeOptions{
+		CurrentVersion: version,
+		CheckOnly:      checkOnly,
+		Force:          force,
+	})
+	spinner.Stop()
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case result.Upgraded:
+		output.PrintSuccessf("Upgraded ccmd %s to %s (%s)", result.CurrentVersion, result.LatestVersion, result.Path)
+	case result.UpdateAvailable:
+		output.PrintInfof("ccmd %s is available (current: %s)", result.LatestVersion, result.CurrentVersion)
+		if result.ReleaseURL != "" {
+			output.PrintInfof("Release notes: %s", result.ReleaseURL)
+		}
+		output.PrintInfof("Run 'ccmd upgrade-self' to upgrade")
+	default:
+		output.PrintSuccessf("ccmd %s is the latest release", result.CurrentVersion)
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package upgradeself
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCommand(t *testing.T) {
+	cmd := NewCommand("1.0.0")
+
+	assert.Equal(t, "upgrade-self", cmd.Use)
+	assert.NotEmpty(t, cmd.Short)
+	assert.NotEmpty(t, cmd.Long)
+	assert.NotNil(t, cmd.Flags().Lookup("check-only"))
+	assert.NotNil(t, cmd.Flags().Lookup("force"))
+	assert.Error(t, cmd.Args(cmd, []string{"extra"}))
+}
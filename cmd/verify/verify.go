@@ -0,0 +1,79 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package verify
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gifflet/ccmd/core"
+	"github.com/gifflet/ccmd/pkg/output"
+)
+
+// NewCommand creates a new verify command.
+func NewCommand() *cobra.Command {
+	var jsonFormat bool
+
+	cmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Check installed commands against the lock file",
+		Long: `Check that every command and plugin recorded in ccmd-lock.yaml is installed
+correctly. Missing directories, missing ccmd.yaml or index.md files, stale
+standalone .md files or ones edited by hand, and git installs whose recorded commit is not a full SHA,
+not the installed commit or not the commit of the locked tag are reported.
+Nothing is modified.
+
+Exits with an error when any problem is found, so it can be used in CI.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runVerify(jsonFormat)
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonFormat, "json", false, "Output the report in JSON format")
+
+	return cmd
+}
+
+func runVerify(jsonFormat bool) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	report, err := core.Verify(core.VerifyOptions{ProjectPath: cwd})
+	if err != nil {
+		return fmt.Errorf("verify failed: %w", err)
+	}
+
+	if jsonFormat {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+	} else {
+		for _, issue := range report.Issues {
+			output.PrintErrorf("%s %s: %s", issue.Type, issue.Name, issue.Problem)
+		}
+		if report.OK() {
+			output.PrintSuccessf("Verified %d installed command(s) and plugin(s)", report.Checked)
+		}
+	}
+
+	if !report.OK() {
+		return fmt.Errorf("%d problem(s) found", len(report.Issues))
+	}
+
+	return nil
+}
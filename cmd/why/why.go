@@ -0,0 +1,87 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package why
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gifflet/ccmd/core"
+	"github.com/gifflet/ccmd/pkg/output"
+)
+
+// NewCommand creates a new why command.
+func NewCommand() *cobra.Command {
+	var jsonFormat bool
+
+	cmd := &cobra.Command{
+		Use:   "why <command-name>",
+		Short: "Explain why a command is installed",
+		Long: `Explain why a command or plugin is installed.
+
+A command is either a direct entry in the project's ccmd.yaml, a dependency listed
+in the ccmd.yaml of another installed command, or orphaned: installed but no longer
+referenced. The requesting constraint and the resolved version chain (constraint,
+resolved tag or branch, commit) are shown.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runWhy(args[0], jsonFormat)
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonFormat, "json", false, "Output the report in JSON format")
+
+	return cmd
+}
+
+func runWhy(name string, jsonFormat bool) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	report, err := core.Why(core.WhyOptions{Name: name, ProjectPath: cwd})
+	if err != nil {
+		return err
+	}
+
+	if jsonFormat {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	output.Printf("%s %s (%s)", report.Type, report.Name, report.Repository)
+
+	switch report.Status {
+	case core.WhyOrphaned:
+		output.PrintWarningf("Orphaned: not referenced by ccmd.yaml or any installed command")
+		output.PrintInfof("Remove it with 'ccmd remove %s'", report.Name)
+	default:
+		for _, ref := range report.References {
+			if ref.Kind == core.WhyDirect {
+				output.Printf("  listed in ccmd.yaml as %s", ref.Spec)
+			} else {
+				output.Printf("  required by %s as %s", ref.RequiredBy, ref.Spec)
+			}
+		}
+	}
+
+	output.Printf("  version chain: %s", strings.Join(report.Chain, " → "))
+
+	return nil
+}
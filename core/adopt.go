@@ -0,0 +1,334 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gifflet/ccmd/internal/fs"
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+// Where the source of an untracked command was found
+const (
+	AdoptFromMetadata   = "ccmd.yaml"  // the repository field of the command's ccmd.yaml
+	AdoptFromGitRemote  = "git remote" // the origin remote of a git checkout
+	AdoptFromStandalone = "header"     // the header of a <name>.md generated by ccmd
+)
+
+// AdoptCandidate is a command under .claude/commands that no lock entry tracks, such as
+// a prompt file copied by hand, with the source ccmd could infer for it
+type AdoptCandidate struct {
+	Name  string   `json:"name"`
+	Paths []string `json:"paths"` // relative to the project root
+	// Repository is empty when no source was found; the command can only be adopted
+	// as local-only
+	Repository string `json:"repository,omitempty"`
+	Version    string `json:"version,omitempty"`
+	Commit     string `json:"commit,omitempty"`
+	Origin     string `json:"origin,omitempty"` // where Repository was found
+	dir        string // the command directory, "" for a lone .md file
+	file       string // the standalone .md file, "" without one
+}
+
+// AdoptOptions represents options for adopting an untracked command
+type AdoptOptions struct {
+	ProjectPath string
+	// LocalOnly tracks the command with source local even when its source is known.
+	// ccmd.yaml does not list local-only commands, and sync and update leave them alone.
+	LocalOnly bool
+}
+
+var (
+	standaloneVersionLine    = regexp.MustCompile(`(?m)^\*\*Version:\*\* (.*)$`)
+	standaloneRepositoryLine = regexp.MustCompile(`(?m)^\*\*Repository:\*\* (.*)$`)
+)
+
+// FindAdoptable lists the command directories and .md files under .claude/commands that no
+// lock entry tracks, sorted by name, with the source inferred for each
+func FindAdoptable(projectPath string) ([]AdoptCandidate, error) {
+	projectRoot, err := findProjectRootFrom(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	tracked, err := trackedCommandNames(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	found := make(map[string]*AdoptCandidate)
+	commandsDir := projectCommandsDir(projectRoot)
+	entries, err := os.ReadDir(commandsDir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, errors.FileError("read commands directory", commandsDir, err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") && !tracked[entry.Name()] {
+			dir := filepath.Join(commandsDir, entry.Name())
+			found[entry.Name()] = &AdoptCandidate{
+				Name:  entry.Name(),
+				Paths: []string{projectRelative(projectRoot, dir)},
+				dir:   dir,
+			}
+		}
+	}
+
+	standaloneDir := filepath.Join(projectRoot, targetAdapters[TargetClaude].defaultPath)
+	if entries, err = os.ReadDir(standaloneDir); err != nil {
+		entries = nil
+	}
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), ".md")
+		if entry.IsDir() || name == entry.Name() || tracked[name] || fs.ValidateName(name) != nil {
+			continue
+		}
+		path := filepath.Join(standaloneDir, entry.Name())
+		candidate := found[name]
+		if candidate == nil {
+			candidate = &AdoptCandidate{Name: name}
+			found[name] = candidate
+		}
+		candidate.Paths = append(candidate.Paths, projectRelative(projectRoot, path))
+		candidate.file = path
+	}
+
+	candidates := make([]AdoptCandidate, 0, len(found))
+	for _, candidate := range found {
+		candidate.inferSource()
+		candidates = append(candidates, *candidate)
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].Name < candidates[j].Name })
+	return candidates, nil
+}
+
+// trackedCommandNames returns the names under .claude/commands the lock file accounts
+// for. Nested commands are tracked by their owner directory and their standalone file.
+func trackedCommandNames(projectRoot string) (map[string]bool, error) {
+	tracked := make(map[string]bool)
+	lockPath := lockFilePath(projectRoot)
+	if !fileExists(lockPath) {
+		return tracked, nil
+	}
+	lockFile, err := ReadLockFile(lockPath)
+	if err != nil {
+		return nil, err
+	}
+	fileName := layoutSettings(projectRoot).FileName
+	for name := range lockFile.Commands {
+		tracked[name] = true
+		if owner, _ := splitQualifiedName(name); owner != "" {
+			tracked[owner] = true
+			tracked[standaloneBase(name, fileName)] = true
+		}
+	}
+	return tracked, nil
+}
+
+// inferSource looks for the source of the candidate: the origin remote of a git
+// checkout, then the repository of its ccmd.yaml, then the header of a generated .md
+func (c *AdoptCandidate) inferSource() {
+	if c.dir != "" {
+		if remote, commit, tag := gitCheckoutSource(c.dir); remote != "" {
+			c.Repository, c.Commit, c.Version, c.Origin = remote, commit, tag, AdoptFromGitRemote
+			return
+		}
+		if metadata, err := readCommandMetadata(filepath.Join(c.dir, "ccmd.yaml")); err == nil && metadata.Repository != "" {
+			c.Repository, c.Version, c.Origin = metadata.Repository, metadata.Version, AdoptFromMetadata
+			return
+		}
+	}
+	if c.file != "" && isGeneratedStandalone(c.file, c.Name) {
+		data, err := os.ReadFile(c.file)
+		if err != nil {
+			return
+		}
+		data = fs.NormalizeNewlines(data)
+		if m := standaloneRepositoryLine.FindSubmatch(data); m != nil && len(bytes.TrimSpace(m[1])) > 0 {
+			c.Repository, c.Origin = string(bytes.TrimSpace(m[1])), AdoptFromStandalone
+			if m := standaloneVersionLine.FindSubmatch(data); m != nil {
+				c.Version = string(bytes.TrimSpace(m[1]))
+			}
+		}
+	}
+}
+
+// gitCheckoutSource returns the origin remote, the commit and the tag at HEAD of a
+// directory that is the root of a git checkout, or empty strings
+func gitCheckoutSource(dir string) (remote, commit, tag string) {
+	if !dirExists(filepath.Join(dir, ".git")) {
+		return "", "", ""
+	}
+	git, err := getGitPath()
+	if err != nil {
+		return "", "", ""
+	}
+	out, err := exec.Command(git, "-C", dir, "config", "--get", "remote.origin.url").Output()
+	if err != nil {
+		return "", "", ""
+	}
+	remote = strings.TrimSpace(string(out))
+	commit, _ = gitGetCurrentCommit(dir)
+	if out, err := exec.Command(git, "-C", dir, "describe", "--tags", "--exact-match", "HEAD").Output(); err == nil {
+		tag = strings.TrimSpace(string(out))
+	}
+	return remote, commit, tag
+}
+
+// Adopt brings an untracked command under ccmd management. With a known source, ccmd.yaml
+// lists it and the lock file records the files as they are; otherwise, or with
+// LocalOnly, it is tracked as a local-only command, as Track does. A lone .md file gets a
+// command directory, and the file is kept as a local override of the generated one.
+func Adopt(candidate AdoptCandidate, opts AdoptOptions) error {
+	projectRoot, err := findProjectRootFrom(opts.ProjectPath)
+	if err != nil {
+		return err
+	}
+
+	if opts.LocalOnly || candidate.Repository == "" {
+		commandDir := candidate.dir
+		if commandDir == "" {
+			commandDir = installedCommandDir(projectRoot, candidate.Name)
+			if err := adoptStandaloneFile(candidate.file, commandDir); err != nil {
+				return err
+			}
+		}
+		return trackLocal(projectRoot, candidate.Name, commandDir)
+	}
+
+	resolved := formatCommandSpec(candidate.Repository, candidate.Version, "")
+	repo, version := adoptedSpec(projectRoot, candidate.Repository, resolved)
+	if candidate.dir == "" {
+		// A generated file whose directory is gone: sync installs the command again
+		return addToConfig(projectRoot, candidate.Name, repo, version)
+	}
+
+	metadata, err := readCommandMetadata(filepath.Join(candidate.dir, "ccmd.yaml"))
+	if err != nil {
+		metadata = &ProjectConfig{}
+	}
+	if metadata.Name == "" {
+		metadata.Name = candidate.Name
+	}
+	if metadata.Repository == "" {
+		metadata.Repository = candidate.Repository
+	}
+	records, err := adoptStandaloneRecords(projectRoot, candidate.dir, metadata, nil)
+	if err != nil {
+		return err
+	}
+
+	entry := &LockCommand{
+		Name:       candidate.Name,
+		Version:    candidate.Version,
+		Source:     candidate.Repository,
+		Resolved:   resolved,
+		Commit:     candidate.Commit,
+		License:    metadata.License,
+		Standalone: records,
+	}
+	if entry.Version == "" {
+		entry.Version = metadata.Version
+	}
+	if entry.Commit == "" {
+		// Sync does not compare versions it cannot tie to a commit
+		entry.Commit = "unknown"
+	}
+	if err := recordAdopted(projectRoot, candidate.dir, entry); err != nil {
+		return err
+	}
+	return addToConfig(projectRoot, candidate.Name, repo, version)
+}
+
+// adoptStandaloneFile turns a lone .md command into a command directory whose index.md
+// is a copy of the file
+func adoptStandaloneFile(file, commandDir string) error {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return errors.FileError("read", file, err)
+	}
+	if err := os.MkdirAll(commandDir, 0755); err != nil {
+		return errors.FileError("create command directory", commandDir, err)
+	}
+	if err := os.WriteFile(filepath.Join(commandDir, "index.md"), content, 0644); err != nil {
+		return errors.FileError("write", filepath.Join(commandDir, "index.md"), err)
+	}
+	return nil
+}
+
+// adoptStandaloneRecords writes the standalone files an adopted command is missing and
+// updates stale ones. Existing files without a record that differ from what ccmd would
+// generate are kept as local overrides.
+func adoptStandaloneRecords(projectRoot, commandDir string, metadata *ProjectConfig,
+	records map[string]*LockStandalone) (map[string]*LockStandalone, error) {
+	targets, err := projectTargets(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	if records == nil {
+		records = make(map[string]*LockStandalone)
+	}
+	for _, target := range targets {
+		existing, err := os.ReadFile(target.file(projectRoot, metadata.Name))
+		if err != nil || records[target.key(metadata.Name)] != nil {
+			continue
+		}
+		content, err := target.render(commandDir, metadata)
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(existing, content) {
+			records[target.key(metadata.Name)] = &LockStandalone{Checksum: standaloneChecksum(existing), Override: true}
+		}
+	}
+
+	if _, _, err := writeTargets(projectRoot, commandDir, targets, metadata, records, leaveEdits); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// recordAdopted adds the lock entry of an adopted command, with the checksum and size of
+// its files as they are
+func recordAdopted(projectRoot, commandDir string, entry *LockCommand) error {
+	checksum, err := contentChecksum(commandDir)
+	if err != nil {
+		return errors.FileError("checksum", commandDir, err)
+	}
+	size, _ := dirSize(commandDir)
+	now := time.Now()
+	entry.Checksum = checksum
+	entry.FileSize = size
+	entry.InstalledAt = now
+	entry.UpdatedAt = now
+
+	lockPath := lockFilePath(projectRoot)
+	lockFile, err := readOrCreateLockFile(lockPath)
+	if err != nil {
+		return err
+	}
+	if _, exists := lockFile.Commands[entry.Name]; exists {
+		return errors.AlreadyExists(fmt.Sprintf("lock entry for %q", entry.Name))
+	}
+	lockFile.Commands[entry.Name] = entry
+	if err := WriteLockFile(lockPath, lockFile); err != nil {
+		return err
+	}
+	recordInstallStats(projectRoot, "command", entry.Name, "")
+	return nil
+}
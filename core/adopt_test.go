@@ -0,0 +1,81 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdopt(t *testing.T) {
+	repo, _ := writeCommandRepo(t)
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	writeConfig(t, []string{})
+
+	commandsDir := filepath.Join(".claude", "commands")
+	require.NoError(t, os.MkdirAll(commandsDir, 0755))
+	handWritten := []byte("Review the staged changes.\n")
+	require.NoError(t, os.WriteFile(filepath.Join(commandsDir, "review.md"), handWritten, 0644))
+	out, err := exec.Command("git", "clone", "--quiet", repo, filepath.Join(commandsDir, "tool")).CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	candidates, err := FindAdoptable(".")
+	require.NoError(t, err)
+	require.Len(t, candidates, 2)
+	review, tool := candidates[0], candidates[1]
+	assert.Equal(t, "review", review.Name)
+	assert.Empty(t, review.Repository)
+	assert.Equal(t, repo, tool.Repository)
+	assert.Equal(t, "v1.0.0", tool.Version)
+	assert.Equal(t, AdoptFromGitRemote, tool.Origin)
+
+	// Without a source the command is local-only, and its file is kept as written
+	require.NoError(t, Adopt(review, AdoptOptions{ProjectPath: "."}))
+	locked := readLockFile(t).Commands["review"]
+	require.NotNil(t, locked)
+	assert.Equal(t, LocalSource, locked.Source)
+	assert.FileExists(t, filepath.Join(commandsDir, "review", "index.md"))
+	data, err := os.ReadFile(filepath.Join(commandsDir, "review.md"))
+	require.NoError(t, err)
+	assert.Equal(t, handWritten, data)
+
+	result, err := Regen(RegenOptions{ProjectPath: "."})
+	require.NoError(t, err)
+	assert.Empty(t, result.Failed)
+
+	// With a source, ccmd.yaml lists it
+	require.NoError(t, Adopt(tool, AdoptOptions{ProjectPath: "."}))
+	locked = readLockFile(t).Commands["tool"]
+	require.NotNil(t, locked)
+	assert.Equal(t, tool.Commit, locked.Commit)
+	assert.FileExists(t, filepath.Join(commandsDir, "tool.md"))
+	data, err = os.ReadFile(ConfigFileName)
+	require.NoError(t, err)
+	assert.True(t, strings.Contains(string(data), repo+"@"), string(data))
+
+	// Sync neither removes the local-only command nor reinstalls the adopted one
+	analysis, err := AnalyzeSync(".", "")
+	require.NoError(t, err)
+	assert.True(t, analysis.InSync)
+	drift, err := CheckLockDrift(".", "")
+	require.NoError(t, err)
+	assert.Empty(t, drift)
+
+	candidates, err = FindAdoptable(".")
+	require.NoError(t, err)
+	assert.Empty(t, candidates)
+}
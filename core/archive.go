@@ -0,0 +1,416 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gifflet/ccmd/internal/fs"
+	"github.com/gifflet/ccmd/pkg/errors"
+	"github.com/gifflet/ccmd/pkg/output"
+)
+
+const (
+	// MaxArchiveSize limits the size of a downloaded archive and of its extracted content
+	MaxArchiveSize = 100 << 20
+
+	archiveDownloadTimeout = 5 * time.Minute
+)
+
+var (
+	archiveExtensions = []string{".tar.gz", ".tgz", ".tar", ".zip"}
+
+	// GitHub: /owner/repo/archive/refs/tags/v1.0.0.tar.gz, GitLab: /group/project/-/archive/v1.0.0/project-v1.0.0.zip
+	githubArchivePath = regexp.MustCompile(`^/([^/]+)/([^/]+)/archive/(?:refs/(?:tags|heads)/)?(.+?)(?:\.tar\.gz|\.tgz|\.zip)$`)
+	gitlabArchivePath = regexp.MustCompile(`^/(.+?)/-/archive/([^/]+)/[^/]+$`)
+	// ccmd pack: name-1.2.0.ccmd.tgz
+	packArtifactName = regexp.MustCompile(`^(.+?)-v?\d+\.\d+\.\d+[^/]*\.ccmd$`)
+)
+
+// IsArchiveSource reports whether an install source is a .tar.gz, .tgz, .tar or .zip
+// archive URL or file rather than a Git repository
+func IsArchiveSource(source string) bool {
+	lower := strings.ToLower(source)
+	if idx := strings.IndexAny(lower, "?#"); idx != -1 {
+		lower = lower[:idx]
+	}
+	for _, ext := range archiveExtensions {
+		if strings.HasSuffix(lower, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// archiveRepoPath returns the owner/repo identity of an archive source, so different
+// versions of the same repository are recognized as one installation
+func archiveRepoPath(source string) string {
+	p := source
+	if idx := strings.Index(p, "://"); idx != -1 {
+		p = p[idx+3:]
+		if slash := strings.Index(p, "/"); slash != -1 {
+			p = p[slash:]
+		}
+	}
+	if idx := strings.IndexAny(p, "?#"); idx != -1 {
+		p = p[:idx]
+	}
+
+	if m := githubArchivePath.FindStringSubmatch(p); m != nil {
+		return m[1] + "/" + m[2]
+	}
+	if m := gitlabArchivePath.FindStringSubmatch(p); m != nil {
+		return m[1]
+	}
+
+	base := path.Base(filepath.ToSlash(p))
+	for _, ext := range archiveExtensions {
+		if strings.HasSuffix(strings.ToLower(base), ext) {
+			base = base[:len(base)-len(ext)]
+			break
+		}
+	}
+	if m := packArtifactName.FindStringSubmatch(base); m != nil {
+		base = m[1]
+	}
+	return path.Join(path.Base(path.Dir(filepath.ToSlash(p))), base)
+}
+
+// fetchArchive downloads or opens an archive, checks it against the expected checksum
+// and extracts it into dest. It returns the directory holding the repository content and
+// the archive digest as sha256:<hex>.
+func fetchArchive(ctx context.Context, source, checksum, dest string) (string, string, error) {
+	data, err := readArchive(ctx, source)
+	if err != nil {
+		return "", "", err
+	}
+
+	sum := sha256.Sum256(data)
+	digest := "sha256:" + hex.EncodeToString(sum[:])
+	if err := verifyArchiveChecksum(digest, checksum); err != nil {
+		return "", "", err
+	}
+
+	if err := extractArchive(data, source, dest); err != nil {
+		return "", "", err
+	}
+
+	root, err := archiveContentRoot(dest)
+	if err != nil {
+		return "", "", err
+	}
+
+	if err := verifyPackManifest(root); err != nil {
+		return "", "", err
+	}
+
+	return root, digest, nil
+}
+
+func readArchive(ctx context.Context, source string) ([]byte, error) {
+	if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") {
+		localPath := strings.TrimPrefix(source, "file://")
+		info, err := os.Stat(localPath)
+		if err != nil {
+			return nil, errors.FileError("open archive", localPath, err)
+		}
+		if info.Size() > MaxArchiveSize {
+			return nil, errors.InvalidInput(fmt.Sprintf("archive is larger than %s", FormatSize(MaxArchiveSize)))
+		}
+		data, err := os.ReadFile(localPath)
+		if err != nil {
+			return nil, errors.FileError("read archive", localPath, err)
+		}
+		return data, nil
+	}
+
+	return download(ctx, source, "archive", MaxArchiveSize)
+}
+
+// download fetches an http(s) URL of at most limit bytes, retrying transient failures;
+// what names the file in errors
+func download(ctx context.Context, source, what string, limit int64) ([]byte, error) {
+	if mirrored := mirrorURL(source); mirrored != source {
+		output.PrintVerbosef("Downloading %s from mirror %s", source, mirrored)
+		source = mirrored
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, http.NoBody)
+	if err != nil {
+		return nil, errors.InvalidInput(fmt.Sprintf("invalid %s URL %q", what, source))
+	}
+	authorizeRequest(req)
+
+	client, err := newHTTPClient(archiveDownloadTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	var data []byte
+	err = withRetry(ctx, "Download of "+source, func() error {
+		resp, err := client.Do(req)
+		if err != nil {
+			return fmt.Errorf("download %s: %w", what, err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return checkHTTPStatus(resp, fmt.Errorf("download %s: %s returned %s", what, source, resp.Status))
+		}
+
+		data, err = io.ReadAll(io.LimitReader(resp.Body, limit+1))
+		if err != nil {
+			return fmt.Errorf("download %s: %w", what, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, errors.InvalidInput(fmt.Sprintf("%s is larger than %s", what, FormatSize(limit)))
+	}
+
+	return data, nil
+}
+
+// verifyArchiveChecksum compares a sha256:<hex> digest with an expected checksum given as
+// sha256:<hex> or bare hex. An empty expected checksum accepts any archive.
+func verifyArchiveChecksum(digest, expected string) error {
+	if expected == "" {
+		return nil
+	}
+
+	want := strings.ToLower(strings.TrimSpace(expected))
+	if !strings.Contains(want, ":") {
+		want = "sha256:" + want
+	}
+	if !strings.HasPrefix(want, "sha256:") {
+		return errors.InvalidInput(fmt.Sprintf("unsupported checksum %q (expected sha256:<hex>)", expected))
+	}
+
+	if want != digest {
+		return errors.InvalidInput(fmt.Sprintf("archive checksum mismatch: expected %s, got %s", want, digest))
+	}
+	return nil
+}
+
+// extractArchive unpacks a tar, gzipped tar or zip archive, detected from its content
+func extractArchive(data []byte, source, dest string) error {
+	switch {
+	case bytes.HasPrefix(data, []byte("PK\x03\x04")):
+		return extractZip(data, dest)
+	case bytes.HasPrefix(data, []byte{0x1f, 0x8b}):
+		gz, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return errors.InvalidInput(fmt.Sprintf("invalid gzip archive %s: %v", source, err))
+		}
+		defer gz.Close()
+		return extractTar(gz, dest)
+	default:
+		return extractTar(bytes.NewReader(data), dest)
+	}
+}
+
+func extractTar(r io.Reader, dest string) error {
+	tr := tar.NewReader(bufio.NewReader(r))
+	var total int64
+
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.InvalidInput(fmt.Sprintf("invalid tar archive: %v", err))
+		}
+
+		target, err := archiveEntryPath(dest, header.Name)
+		if err != nil {
+			return err
+		}
+		if target == "" {
+			continue
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return errors.FileError("create directory", target, err)
+			}
+		case tar.TypeReg:
+			total += header.Size
+			if total > MaxArchiveSize {
+				return errors.InvalidInput(fmt.Sprintf("archive content is larger than %s", FormatSize(MaxArchiveSize)))
+			}
+			if err := writeArchiveFile(target, tr, os.FileMode(header.Mode)); err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			return archiveSymlinkError(header.Name)
+		default:
+			// Hard links, devices and pax metadata are not needed for command repositories
+		}
+	}
+}
+
+func extractZip(data []byte, dest string) error {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return errors.InvalidInput(fmt.Sprintf("invalid zip archive: %v", err))
+	}
+
+	var total uint64
+	for _, file := range zr.File {
+		target, err := archiveEntryPath(dest, file.Name)
+		if err != nil {
+			return err
+		}
+		if target == "" {
+			continue
+		}
+
+		if file.FileInfo().IsDir() {
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return errors.FileError("create directory", target, err)
+			}
+			continue
+		}
+
+		total += file.UncompressedSize64
+		if total > MaxArchiveSize {
+			return errors.InvalidInput(fmt.Sprintf("archive content is larger than %s", FormatSize(MaxArchiveSize)))
+		}
+
+		if file.Mode()&os.ModeSymlink != 0 {
+			return archiveSymlinkError(file.Name)
+		}
+
+		rc, err := file.Open()
+		if err != nil {
+			return errors.InvalidInput(fmt.Sprintf("invalid zip entry %s: %v", file.Name, err))
+		}
+		err = writeArchiveFile(target, rc, file.Mode())
+		rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// archiveEntryPath resolves an entry name inside dest, rejecting absolute paths and
+// entries that escape it. It returns "" for entries that should be skipped.
+func archiveEntryPath(dest, name string) (string, error) {
+	name = filepath.FromSlash(strings.TrimPrefix(name, "./"))
+	if name == "" || name == "." {
+		return "", nil
+	}
+	if filepath.IsAbs(name) || strings.HasPrefix(name, string(filepath.Separator)) {
+		return "", errors.InvalidInput(fmt.Sprintf("archive entry %q has an absolute path", name))
+	}
+
+	target := filepath.Join(dest, name)
+	if rel, err := filepath.Rel(dest, target); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", errors.InvalidInput(fmt.Sprintf("archive entry %q points outside the archive", name))
+	}
+
+	return target, nil
+}
+
+func writeArchiveFile(target string, r io.Reader, mode os.FileMode) error {
+	target = fs.LongPath(target)
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return errors.FileError("create directory", filepath.Dir(target), err)
+	}
+
+	perm := os.FileMode(0644)
+	if mode&0111 != 0 {
+		perm = 0755
+	}
+
+	f, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, perm)
+	if err != nil {
+		return errors.FileError("create file", target, err)
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(f, io.LimitReader(r, MaxArchiveSize)); err != nil {
+		return errors.FileError("write file", target, err)
+	}
+	return nil
+}
+
+// archiveSymlinkError refuses a symbolic link entry. Checking where a link points is
+// not enough: links through other links, such as d -> . and d/e -> .., escape the
+// extraction directory while every path still looks contained.
+func archiveSymlinkError(name string) error {
+	return errors.InvalidInput(fmt.Sprintf("archive entry %q is a symbolic link, which archives may not contain", name))
+}
+
+// archiveContentRoot returns the directory holding ccmd.yaml or index.md. Release
+// archives usually wrap the repository in a single top-level directory such as repo-1.0.0/.
+func archiveContentRoot(dir string) (string, error) {
+	for {
+		if fileExists(filepath.Join(dir, ConfigFileName)) || fileExists(filepath.Join(dir, defaultEntry)) {
+			return dir, nil
+		}
+
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return "", errors.FileError("read archive content", dir, err)
+		}
+		if len(entries) != 1 || !entries[0].IsDir() {
+			return "", errors.InvalidInput("archive does not contain ccmd.yaml or index.md")
+		}
+		dir = filepath.Join(dir, entries[0].Name())
+	}
+}
+
+// recordLockCommit stores the commit of an install in the lock file: the full SHA of a
+// verified git checkout, or the checksum of a downloaded archive or markdown file, since
+// such installs have no git history. Markdown installs are also
+// resolved to the URL and checksum, as the URL may serve other content later.
+func recordLockCommit(projectRoot, name string, isPlugin bool, commit string) error {
+	lockPath := lockFilePath(projectRoot)
+	lockFile, err := ReadLockFile(lockPath)
+	if err != nil {
+		return err
+	}
+
+	if isPlugin {
+		if plugin, ok := lockFile.Plugins[name]; ok {
+			plugin.Commit = commit
+		}
+	} else if cmd, ok := lockFile.Commands[name]; ok {
+		cmd.Commit = commit
+		if IsMarkdownSource(cmd.Source) {
+			cmd.Resolved = cmd.Source + "@" + commit
+		}
+	}
+
+	return WriteLockFile(lockPath, lockFile)
+}
@@ -0,0 +1,222 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const archiveMetadata = `name: hello
+version: 1.0.0
+description: Says hello
+author: tester
+repository: https://github.com/acme/hello
+entry: index.md
+`
+
+func buildTarGz(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+	return buf.Bytes()
+}
+
+func buildZip(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func TestIsArchiveSource(t *testing.T) {
+	assert.True(t, IsArchiveSource("https://github.com/acme/hello/archive/refs/tags/v1.0.0.tar.gz"))
+	assert.True(t, IsArchiveSource("./dist/hello.ZIP"))
+	assert.True(t, IsArchiveSource("https://example.com/hello.tgz?token=abc"))
+	assert.False(t, IsArchiveSource("github.com/acme/hello"))
+	assert.False(t, IsArchiveSource("https://github.com/acme/hello.git"))
+}
+
+func TestArchiveRepoPath(t *testing.T) {
+	assert.Equal(t, "acme/hello", ExtractRepoPath("https://github.com/acme/hello/archive/refs/tags/v1.0.0.tar.gz"))
+	assert.Equal(t, "acme/hello", ExtractRepoPath("https://github.com/acme/hello/archive/v2.0.0.zip"))
+	assert.Equal(t, "group/sub/hello", ExtractRepoPath("https://gitlab.com/group/sub/hello/-/archive/v1.0.0/hello-v1.0.0.tar.gz"))
+	assert.Equal(t, "dist/hello-1.0.0", ExtractRepoPath("./dist/hello-1.0.0.tar.gz"))
+	assert.Equal(t, "./dist/hello.tgz", NormalizeRepositoryURL("./dist/hello.tgz"))
+}
+
+func TestInstallFromArchive(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	writeConfig(t, []string{})
+
+	data := buildTarGz(t, map[string]string{
+		"hello-1.0.0/ccmd.yaml": archiveMetadata,
+		"hello-1.0.0/index.md":  "Say hello",
+	})
+	require.NoError(t, os.WriteFile("hello.tar.gz", data, 0644))
+	sum := sha256.Sum256(data)
+	digest := "sha256:" + hex.EncodeToString(sum[:])
+
+	name, isPlugin, err := Install(context.Background(), InstallOptions{Repository: "hello.tar.gz", Checksum: digest})
+	require.NoError(t, err)
+	assert.Equal(t, "hello", name)
+	assert.False(t, isPlugin)
+
+	assert.FileExists(t, filepath.Join(".claude", "commands", "hello", "index.md"))
+	assert.FileExists(t, filepath.Join(".claude", "commands", "hello.md"))
+
+	lock := readLockFile(t)
+	require.Contains(t, lock.Commands, "hello")
+	assert.Equal(t, digest, lock.Commands["hello"].Commit)
+	assert.Equal(t, "hello.tar.gz", lock.Commands["hello"].Source)
+
+	cfg, err := LoadProjectConfig(".")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"hello.tar.gz"}, cfg.Commands)
+}
+
+func TestInstallFromArchiveURL(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	writeConfig(t, []string{})
+
+	data := buildZip(t, map[string]string{
+		"ccmd.yaml": archiveMetadata,
+		"index.md":  "Say hello",
+	})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(data)
+	}))
+	defer server.Close()
+
+	// --from-archive accepts URLs without an archive extension
+	name, _, err := Install(context.Background(), InstallOptions{Repository: server.URL + "/download", Archive: true})
+	require.NoError(t, err)
+	assert.Equal(t, "hello", name)
+	assert.FileExists(t, filepath.Join(".claude", "commands", "hello", "ccmd.yaml"))
+}
+
+func TestInstallFromArchiveChecksumMismatch(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+
+	data := buildTarGz(t, map[string]string{"ccmd.yaml": archiveMetadata, "index.md": "Say hello"})
+	require.NoError(t, os.WriteFile("hello.tgz", data, 0644))
+
+	_, _, err := Install(context.Background(), InstallOptions{Repository: "hello.tgz", Checksum: "sha256:00"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "checksum mismatch")
+	assert.NoDirExists(t, filepath.Join(".claude", "commands", "hello"))
+}
+
+func TestExtractArchiveRejectsTraversal(t *testing.T) {
+	dest := t.TempDir()
+
+	err := extractArchive(buildZip(t, map[string]string{"../evil.txt": "x"}), "evil.zip", dest)
+	assert.Error(t, err)
+
+	err = extractArchive(buildTarGz(t, map[string]string{"a/../../evil.txt": "x"}), "evil.tar.gz", dest)
+	assert.Error(t, err)
+	assert.NoFileExists(t, filepath.Join(filepath.Dir(dest), "evil.txt"))
+}
+
+func TestExtractArchiveRejectsChainedLinks(t *testing.T) {
+	// d -> . and d/e -> .. look contained as paths, but e/pwned.txt lands beside dest
+	var tarData bytes.Buffer
+	tw := tar.NewWriter(&tarData)
+	for _, link := range [][2]string{{"d", "."}, {"d/e", ".."}} {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: link[0], Linkname: link[1], Typeflag: tar.TypeSymlink, Mode: 0777}))
+	}
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "e/pwned.txt", Mode: 0644, Size: 1, Typeflag: tar.TypeReg}))
+	_, err := tw.Write([]byte("x"))
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+
+	var zipData bytes.Buffer
+	zw := zip.NewWriter(&zipData)
+	for _, link := range [][2]string{{"d", "."}, {"d/e", ".."}} {
+		header := &zip.FileHeader{Name: link[0]}
+		header.SetMode(os.ModeSymlink | 0777)
+		w, err := zw.CreateHeader(header)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(link[1]))
+		require.NoError(t, err)
+	}
+	w, err := zw.Create("e/pwned.txt")
+	require.NoError(t, err)
+	_, err = w.Write([]byte("x"))
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+
+	for name, data := range map[string][]byte{"links.tar": tarData.Bytes(), "links.zip": zipData.Bytes()} {
+		parent := t.TempDir()
+		dest := filepath.Join(parent, "dest")
+		require.NoError(t, os.Mkdir(dest, 0755))
+
+		err := extractArchive(data, name, dest)
+		assert.ErrorContains(t, err, "symbolic link", name)
+		assert.NoFileExists(t, filepath.Join(parent, "pwned.txt"), name)
+	}
+}
+
+func TestCopyDirectorySkipsLinks(t *testing.T) {
+	outside := filepath.Join(t.TempDir(), "secret")
+	require.NoError(t, os.WriteFile(outside, []byte("secret"), 0644))
+	src := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(src, "index.md"), []byte("# Hi\n"), 0644))
+	if err := os.Symlink(outside, filepath.Join(src, "leak")); err != nil {
+		t.Skip("symlinks not supported")
+	}
+
+	dst := filepath.Join(t.TempDir(), "copy")
+	require.NoError(t, copyDirectory(context.Background(), src, dst))
+	assert.FileExists(t, filepath.Join(dst, "index.md"))
+	_, err := os.Lstat(filepath.Join(dst, "leak"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestArchiveContentRootRequiresMetadata(t *testing.T) {
+	dest := t.TempDir()
+	require.NoError(t, extractArchive(buildTarGz(t, map[string]string{"repo/README.md": "x"}), "repo.tar.gz", dest))
+
+	_, err := archiveContentRoot(dest)
+	assert.Error(t, err)
+}
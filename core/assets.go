@@ -0,0 +1,138 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/gifflet/ccmd/pkg/errors"
+	"github.com/gifflet/ccmd/pkg/output"
+)
+
+// Asset maps platforms, written as GOOS/GOARCH such as linux/amd64, to the release
+// asset installed for them
+type Asset map[string]AssetSource
+
+// AssetSource is a release asset of one platform
+type AssetSource struct {
+	URL string `yaml:"url" json:"url"`
+	// Checksum is the sha256 of the asset, as sha256:<hex> or bare hex
+	Checksum string `yaml:"checksum" json:"checksum"`
+}
+
+// LockAsset records the release asset installed for a helper binary
+type LockAsset struct {
+	Platform string `yaml:"platform"`
+	URL      string `yaml:"url"`
+	Checksum string `yaml:"checksum"`
+}
+
+var (
+	platformPattern = regexp.MustCompile(`^[a-z0-9]+/[a-z0-9]+$`)
+	sha256Pattern   = regexp.MustCompile(`^(sha256:)?[0-9a-fA-F]{64}$`)
+)
+
+// currentPlatform is the GOOS/GOARCH key of assets for this machine
+func currentPlatform() string {
+	return runtime.GOOS + "/" + runtime.GOARCH
+}
+
+// validateAssets checks the paths, platforms, URLs and checksums of the assets section
+func validateAssets(assets map[string]Asset) error {
+	for _, path := range sortedAssetPaths(assets) {
+		if err := validateExecPath(path); err != nil {
+			return errors.InvalidInput(fmt.Sprintf("asset %q must be a path inside the command directory", path))
+		}
+		if len(assets[path]) == 0 {
+			return errors.InvalidInput(fmt.Sprintf("asset %q lists no platforms", path))
+		}
+		for platform, source := range assets[path] {
+			if !platformPattern.MatchString(platform) {
+				return errors.InvalidInput(fmt.Sprintf("asset %q: platform %q is not GOOS/GOARCH, such as linux/amd64", path, platform))
+			}
+			if u, err := url.Parse(source.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return errors.InvalidInput(fmt.Sprintf("asset %q: %s has no http(s) url", path, platform))
+			}
+			if !sha256Pattern.MatchString(strings.TrimSpace(source.Checksum)) {
+				return errors.InvalidInput(fmt.Sprintf("asset %q: %s needs a checksum (sha256:<hex>)", path, platform))
+			}
+		}
+	}
+	return nil
+}
+
+// installAssets downloads the asset of this platform for every helper binary into the
+// command directory, verifies its checksum and makes it executable. Binaries without
+// an asset for this platform are skipped. It returns what was installed, by path.
+func installAssets(ctx context.Context, destDir string, assets map[string]Asset) (map[string]*LockAsset, error) {
+	platform := currentPlatform()
+	installed := make(map[string]*LockAsset)
+	for _, path := range sortedAssetPaths(assets) {
+		source, ok := assets[path][platform]
+		if !ok {
+			output.PrintVerbosef("Skipping asset %s: no build for %s", path, platform)
+			continue
+		}
+
+		data, err := download(ctx, source.URL, "asset "+path, MaxArchiveSize)
+		if err != nil {
+			return nil, err
+		}
+		sum := sha256.Sum256(data)
+		digest := "sha256:" + hex.EncodeToString(sum[:])
+		if err := verifyArchiveChecksum(digest, source.Checksum); err != nil {
+			return nil, fmt.Errorf("asset %s: %w", path, err)
+		}
+
+		// The file is renamed over the target, so a link the repository placed there
+		// is replaced rather than followed
+		target := filepath.Join(destDir, filepath.FromSlash(path))
+		if err := writeFileAtomic(target, data, 0755); err != nil {
+			return nil, err
+		}
+		output.PrintVerbosef("Installed asset %s for %s (%d bytes)", path, platform, len(data))
+		installed[path] = &LockAsset{Platform: platform, URL: source.URL, Checksum: digest}
+	}
+	return installed, nil
+}
+
+// recordAssets stores the assets installed for a command in the lock file
+func recordAssets(projectRoot, name string, assets map[string]*LockAsset) error {
+	lockPath := lockFilePath(projectRoot)
+	lockFile, err := ReadLockFile(lockPath)
+	if err != nil {
+		return err
+	}
+	cmd, ok := lockFile.Commands[name]
+	if !ok {
+		return nil
+	}
+
+	cmd.Assets = assets
+	return WriteLockFile(lockPath, lockFile)
+}
+
+func sortedAssetPaths(assets map[string]Asset) []string {
+	paths := make([]string, 0, len(assets))
+	for path := range assets {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
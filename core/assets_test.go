@@ -0,0 +1,138 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateAssets(t *testing.T) {
+	checksum := "sha256:" + strings.Repeat("ab", 32)
+	valid := Asset{"linux/amd64": {URL: "https://example.com/scan", Checksum: checksum}}
+	assert.NoError(t, validateAssets(map[string]Asset{"bin/scan": valid}))
+	assert.NoError(t, validateAssets(nil))
+
+	for name, assets := range map[string]map[string]Asset{
+		"outside":     {"../scan": valid},
+		"no platform": {"bin/scan": {}},
+		"platform":    {"bin/scan": {"linux": {URL: "https://example.com/scan", Checksum: checksum}}},
+		"url":         {"bin/scan": {"linux/amd64": {URL: "file:///tmp/scan", Checksum: checksum}}},
+		"checksum":    {"bin/scan": {"linux/amd64": {URL: "https://example.com/scan", Checksum: "md5:abc"}}},
+	} {
+		assert.Error(t, validateAssets(assets), name)
+	}
+}
+
+func TestInstallAssets(t *testing.T) {
+	binary := []byte("#!/bin/sh\necho scan\n")
+	sum := sha256.Sum256(binary)
+	checksum := "sha256:" + hex.EncodeToString(sum[:])
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(binary)
+	}))
+	defer server.Close()
+
+	repo, _ := writeCommandRepo(t)
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	dir := strings.TrimPrefix(repo, "file://")
+	metadata := fmt.Sprintf("name: tool\nversion: 1.0.0\ndescription: A tool\nauthor: acme\n"+
+		"repository: https://github.com/acme/tool\nentry: index.md\nexec: bin/scan\nassets:\n"+
+		"  bin/scan:\n    %s:\n      url: %s/scan\n      checksum: %s\n"+
+		"  bin/other:\n    plan9/mips:\n      url: %s/other\n      checksum: %s\n",
+		currentPlatform(), server.URL, checksum, server.URL, checksum)
+	commit := func() {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, ConfigFileName), []byte(metadata), 0644))
+		for _, args := range [][]string{{"commit", "--quiet", "--all", "-m", "assets"}, {"tag", "--force", "v1.0.0"}} {
+			out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+			require.NoError(t, err, string(out))
+		}
+	}
+	commit()
+
+	_, _, err := Install(ctx, InstallOptions{Repository: repo, Version: "v1.0.0"})
+	require.NoError(t, err)
+
+	path := filepath.Join(".claude", "commands", "tool", "bin", "scan")
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, binary, data)
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.NotZero(t, info.Mode()&0111)
+	}
+	assert.NoFileExists(t, filepath.Join(".claude", "commands", "tool", "bin", "other"))
+
+	lockFile, err := ReadLockFile(lockFilePath("."))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]*LockAsset{
+		"bin/scan": {Platform: currentPlatform(), URL: server.URL + "/scan", Checksum: checksum},
+	}, lockFile.Commands["tool"].Assets)
+
+	// A checksum that does not match fails the install and leaves nothing behind
+	metadata = strings.ReplaceAll(metadata, checksum, "sha256:"+strings.Repeat("0", 64))
+	commit()
+	_, _, err = Install(ctx, InstallOptions{Repository: repo, Version: "v1.0.0", Force: true})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "asset bin/scan: ")
+	assert.Contains(t, err.Error(), "checksum mismatch")
+	assert.NoDirExists(t, filepath.Join(".claude", "commands", "tool"))
+}
+
+func TestInstallAssetsReplacesLinks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symbolic links need privileges on Windows")
+	}
+	binary := []byte("#!/bin/sh\necho scan\n")
+	sum := sha256.Sum256(binary)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(binary)
+	}))
+	defer server.Close()
+	cleanup := setupTestDir(t)
+	defer cleanup()
+
+	// A link at the asset path must not lead the download outside the command directory
+	outside := filepath.Join(t.TempDir(), "victim")
+	require.NoError(t, os.WriteFile(outside, []byte("keep"), 0644))
+	destDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(destDir, "bin"), 0755))
+	require.NoError(t, os.Symlink(outside, filepath.Join(destDir, "bin", "scan")))
+
+	_, err := installAssets(context.Background(), destDir, map[string]Asset{
+		"bin/scan": {currentPlatform(): {URL: server.URL + "/scan", Checksum: hex.EncodeToString(sum[:])}},
+	})
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(outside)
+	require.NoError(t, err)
+	assert.Equal(t, "keep", string(data))
+	info, err := os.Lstat(filepath.Join(destDir, "bin", "scan"))
+	require.NoError(t, err)
+	assert.True(t, info.Mode().IsRegular())
+	assert.NotZero(t, info.Mode()&0111)
+}
@@ -0,0 +1,304 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gifflet/ccmd/pkg/errors"
+	"github.com/gifflet/ccmd/pkg/logger"
+)
+
+const (
+	// AuditLogName is the audit log file inside the project's .claude directory
+	AuditLogName = "ccmd-audit.log"
+	// AuditMaxSize is the size at which the audit log is rotated
+	AuditMaxSize = 1 << 20
+	// AuditMaxBackups is the number of rotated logs kept (ccmd-audit.log.1 is the newest)
+	AuditMaxBackups = 3
+	// AuditActorEnv overrides the actor recorded in audit events
+	AuditActorEnv = "CCMD_ACTOR"
+)
+
+// Audited operations
+const (
+	AuditInstall  = "install"
+	AuditUpdate   = "update"
+	AuditRollback = "rollback"
+	AuditRemove   = "remove"
+	AuditSync     = "sync"
+	AuditVerify   = "verify"
+	AuditPin      = "pin"
+	AuditUnpin    = "unpin"
+)
+
+// Audit results
+const (
+	AuditSuccess = "success"
+	AuditFailure = "failure"
+)
+
+// AuditEvent is one line of the audit log
+type AuditEvent struct {
+	Time            time.Time `json:"time"`
+	Action          string    `json:"action"`
+	Actor           string    `json:"actor"`
+	Name            string    `json:"name,omitempty"`
+	Type            string    `json:"type,omitempty"`
+	Repository      string    `json:"repository,omitempty"`
+	Version         string    `json:"version,omitempty"`
+	PreviousVersion string    `json:"previous_version,omitempty"`
+	Commit          string    `json:"commit,omitempty"`
+	Result          string    `json:"result"`
+	Error           string    `json:"error,omitempty"`
+	Details         string    `json:"details,omitempty"`
+}
+
+// AuditQuery filters the events returned by ReadAudit
+type AuditQuery struct {
+	ProjectPath string
+	Name        string    // Command or plugin name (empty for all)
+	Action      string    // Operation (empty for all)
+	Since       time.Time // Zero means no lower bound
+	Until       time.Time // Zero means no upper bound
+}
+
+func auditLogPath(projectRoot string) string {
+	return filepath.Join(projectRoot, ".claude", AuditLogName)
+}
+
+// recordAudit appends an event to the project's audit log. Failures are logged and
+// never abort the audited operation.
+func recordAudit(projectRoot string, event AuditEvent) {
+	if event.Time.IsZero() {
+		event.Time = time.Now().UTC()
+	}
+	if event.Actor == "" {
+		event.Actor = auditActor(projectRoot)
+	}
+	if event.Result == "" {
+		event.Result = AuditSuccess
+	}
+	logger.WithFields(logger.Fields{
+		"action": event.Action, "name": event.Name, "repository": event.Repository,
+		"version": event.Version, "result": event.Result, "error": event.Error,
+	}).Debug("Operation recorded")
+
+	if err := appendAuditEvent(auditLogPath(projectRoot), event); err != nil {
+		logger.WithError(err).Warn("Failed to write audit log")
+	}
+}
+
+// auditResult fills the result and error of an event from an operation's error
+func auditResult(event AuditEvent, err error) AuditEvent {
+	if err != nil {
+		event.Result = AuditFailure
+		event.Error = err.Error()
+	}
+	return event
+}
+
+// auditLockEntry fills the event with what the lock file records for a command or plugin
+func auditLockEntry(projectRoot string, event AuditEvent) AuditEvent {
+	lockFile, err := ReadLockFile(lockFilePath(projectRoot))
+	if err != nil {
+		return event
+	}
+
+	if cmd, ok := lockFile.Commands[event.Name]; ok {
+		event.Type = "command"
+		event.Repository, event.Version, event.Commit = cmd.Source, cmd.Version, cmd.Commit
+	} else if plugin, ok := lockFile.Plugins[event.Name]; ok {
+		event.Type = "plugin"
+		event.Repository, event.Version, event.Commit = plugin.Source, plugin.Version, plugin.Commit
+	}
+
+	return event
+}
+
+// auditActor identifies who ran the operation: $CCMD_ACTOR, the git user email or the OS user
+func auditActor(projectRoot string) string {
+	if actor := os.Getenv(AuditActorEnv); actor != "" {
+		return actor
+	}
+
+	if gitPath, err := getGitPath(); err == nil {
+		cmd := exec.Command(gitPath, "config", "user.email")
+		cmd.Dir = projectRoot
+		if out, err := cmd.Output(); err == nil {
+			if email := strings.TrimSpace(string(out)); email != "" {
+				return email
+			}
+		}
+	}
+
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return "unknown"
+}
+
+func appendAuditEvent(path string, event AuditEvent) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return errors.FileError("create audit directory", filepath.Dir(path), err)
+	}
+
+	if err := rotateAuditLog(path); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return errors.FileError("open audit log", path, err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return errors.FileError("write audit log", path, err)
+	}
+
+	return nil
+}
+
+// rotateAuditLog shifts ccmd-audit.log to ccmd-audit.log.1 and so on once it reaches
+// AuditMaxSize, dropping the oldest backup
+func rotateAuditLog(path string) error {
+	info, err := os.Stat(path)
+	if err != nil || info.Size() < AuditMaxSize {
+		return nil
+	}
+
+	for i := AuditMaxBackups - 1; i >= 1; i-- {
+		from := fmt.Sprintf("%s.%d", path, i)
+		if fileExists(from) {
+			if err := os.Rename(from, fmt.Sprintf("%s.%d", path, i+1)); err != nil {
+				return errors.FileError("rotate audit log", from, err)
+			}
+		}
+	}
+
+	if err := os.Rename(path, path+".1"); err != nil {
+		return errors.FileError("rotate audit log", path, err)
+	}
+
+	return nil
+}
+
+// ReadAudit returns the audit events matching the query, oldest first, including
+// events from rotated logs
+func ReadAudit(query AuditQuery) ([]AuditEvent, error) {
+	projectRoot, err := findProjectRootFrom(query.ProjectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	path := auditLogPath(projectRoot)
+	files := make([]string, 0, AuditMaxBackups+1)
+	for i := AuditMaxBackups; i >= 1; i-- {
+		files = append(files, fmt.Sprintf("%s.%d", path, i))
+	}
+	files = append(files, path)
+
+	events := []AuditEvent{}
+	for _, file := range files {
+		if !fileExists(file) {
+			continue
+		}
+
+		found, err := readAuditFile(file, query)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, found...)
+	}
+
+	return events, nil
+}
+
+func readAuditFile(path string, query AuditQuery) ([]AuditEvent, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.FileError("open audit log", path, err)
+	}
+	defer f.Close()
+
+	var events []AuditEvent
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), AuditMaxSize)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var event AuditEvent
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			// Skip lines that were truncated or edited by hand
+			continue
+		}
+		if query.matches(event) {
+			events = append(events, event)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.FileError("read audit log", path, err)
+	}
+
+	return events, nil
+}
+
+func (q AuditQuery) matches(event AuditEvent) bool {
+	switch {
+	case q.Name != "" && event.Name != q.Name:
+		return false
+	case q.Action != "" && event.Action != q.Action:
+		return false
+	case !q.Since.IsZero() && event.Time.Before(q.Since):
+		return false
+	case !q.Until.IsZero() && event.Time.After(q.Until):
+		return false
+	}
+	return true
+}
+
+// ParseAuditTime parses a time filter: a duration before now such as 24h or 7d,
+// a date (2006-01-02) or an RFC 3339 timestamp
+func ParseAuditTime(value string, now time.Time) (time.Time, error) {
+	if strings.HasSuffix(value, "d") {
+		if days, err := strconv.Atoi(strings.TrimSuffix(value, "d")); err == nil && days >= 0 {
+			return now.AddDate(0, 0, -days), nil
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	return time.Time{}, errors.InvalidInput(fmt.Sprintf("invalid time %q (use a duration like 24h or 7d, a date or an RFC 3339 time)", value))
+}
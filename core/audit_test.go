@@ -0,0 +1,124 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoveRecordsAuditEvent(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	t.Setenv(AuditActorEnv, "alice@example.com")
+
+	writeConfig(t, []string{"test/hello@1.0.0"})
+	setupRegenCommand(t, "hello")
+
+	require.NoError(t, Remove(RemoveOptions{Name: "hello", UpdateFiles: true}))
+	assert.Error(t, Remove(RemoveOptions{Name: "missing"}))
+
+	events, err := ReadAudit(AuditQuery{})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+
+	event := events[0]
+	assert.Equal(t, AuditRemove, event.Action)
+	assert.Equal(t, "alice@example.com", event.Actor)
+	assert.Equal(t, "hello", event.Name)
+	assert.Equal(t, "command", event.Type)
+	assert.Equal(t, "1.0.0", event.Version)
+	assert.Equal(t, "https://github.com/test/hello.git", event.Repository)
+	assert.Equal(t, AuditSuccess, event.Result)
+	assert.WithinDuration(t, time.Now(), event.Time, time.Minute)
+}
+
+func TestReadAuditFilters(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(AuditActorEnv, "bob")
+
+	base := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	recordAudit(dir, AuditEvent{Time: base, Action: AuditInstall, Name: "alpha"})
+	recordAudit(dir, AuditEvent{Time: base.Add(24 * time.Hour), Action: AuditUpdate, Name: "alpha"})
+	recordAudit(dir, AuditEvent{Time: base.Add(48 * time.Hour), Action: AuditInstall, Name: "beta", Result: AuditFailure})
+
+	events, err := ReadAudit(AuditQuery{ProjectPath: dir, Name: "alpha"})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, AuditInstall, events[0].Action)
+	assert.Equal(t, "bob", events[0].Actor)
+
+	events, err = ReadAudit(AuditQuery{ProjectPath: dir, Action: AuditInstall})
+	require.NoError(t, err)
+	assert.Len(t, events, 2)
+
+	events, err = ReadAudit(AuditQuery{ProjectPath: dir, Since: base.Add(time.Hour), Until: base.Add(25 * time.Hour)})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, AuditUpdate, events[0].Action)
+}
+
+func TestAuditLogRotation(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(AuditActorEnv, "ci")
+
+	path := auditLogPath(dir)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+
+	// Fill the log past the rotation size, one older backup already exists
+	require.NoError(t, os.WriteFile(path+".1", []byte(`{"action":"install","name":"old","result":"success"}`+"\n"), 0644))
+	filler := `{"action":"sync","result":"success","details":"` + strings.Repeat("x", 1000) + `"}` + "\n"
+	require.NoError(t, os.WriteFile(path, []byte(strings.Repeat(filler, AuditMaxSize/len(filler)+1)), 0644))
+
+	recordAudit(dir, AuditEvent{Action: AuditVerify})
+
+	assert.FileExists(t, path+".1")
+	assert.FileExists(t, path+".2")
+	assert.NoFileExists(t, path+".3")
+
+	events, err := ReadAudit(AuditQuery{ProjectPath: dir, Action: AuditInstall})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "old", events[0].Name)
+
+	events, err = ReadAudit(AuditQuery{ProjectPath: dir, Action: AuditVerify})
+	require.NoError(t, err)
+	assert.Len(t, events, 1)
+}
+
+func TestParseAuditTime(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	got, err := ParseAuditTime("24h", now)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(-24*time.Hour), got)
+
+	got, err = ParseAuditTime("7d", now)
+	require.NoError(t, err)
+	assert.Equal(t, now.AddDate(0, 0, -7), got)
+
+	got, err = ParseAuditTime("2025-03-01T08:00:00Z", now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 3, 1, 8, 0, 0, 0, time.UTC), got)
+
+	got, err = ParseAuditTime("2025-03-01", now)
+	require.NoError(t, err)
+	assert.Equal(t, 2025, got.Year())
+	assert.Equal(t, time.March, got.Month())
+
+	_, err = ParseAuditTime("yesterday", now)
+	assert.Error(t, err)
+}
@@ -0,0 +1,142 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/gifflet/ccmd/pkg/errors"
+	"github.com/gifflet/ccmd/pkg/output"
+)
+
+// BatchInstallOptions represents options for installing a list of repositories
+type BatchInstallOptions struct {
+	// Specs are entries as in ccmd.yaml: owner/repo, owner/repo@version or
+	// "owner/repo@version as name"
+	Specs []string
+	// Install is applied to every spec; its Repository, Version and As come from the spec
+	Install InstallOptions
+	// All installs every command of multi-command repositories instead of failing
+	All bool
+}
+
+// BatchResult holds the outcome of a batch install
+type BatchResult struct {
+	Installed []string          // specs installed
+	Skipped   []string          // specs already installed, left alone without Force
+	Failed    []*errors.Failure // specs that failed, named by the spec
+}
+
+// Err returns the failed specs as a batch error, or nil when none failed
+func (r *BatchResult) Err() error {
+	total := len(r.Installed) + len(r.Skipped) + len(r.Failed)
+	return errors.Batch(fmt.Sprintf("failed to install %d of %d repositories", len(r.Failed), total), r.Failed)
+}
+
+// ParseSpecList parses a list of repository specs: a YAML or JSON array of strings, or
+// one spec per line with blank lines and # comments ignored. Repeated specs are
+// listed once.
+func ParseSpecList(data []byte) ([]string, error) {
+	var specs []string
+	if isYAMLList(string(data)) {
+		if err := yaml.Unmarshal(data, &specs); err != nil {
+			return nil, errors.InvalidInput(fmt.Sprintf("invalid list of repositories: %v", err))
+		}
+	} else {
+		specs = strings.Split(string(data), "\n")
+	}
+
+	var unique []string
+	seen := make(map[string]bool)
+	for _, spec := range specs {
+		spec = strings.TrimSpace(spec)
+		if spec == "" || strings.HasPrefix(spec, "#") || seen[spec] {
+			continue
+		}
+		seen[spec] = true
+		unique = append(unique, spec)
+	}
+	if len(unique) == 0 {
+		return nil, errors.InvalidInput("the list of repositories is empty")
+	}
+	return unique, nil
+}
+
+// isYAMLList reports whether the first line that is not blank or a comment opens a
+// YAML or JSON array. Specs never start with "[" or "- ".
+func isYAMLList(data string) bool {
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		return strings.HasPrefix(line, "[") || line == "-" || strings.HasPrefix(line, "- ")
+	}
+	return false
+}
+
+// InstallBatch installs a list of repositories. The versions of all of them are
+// resolved up front in parallel, as for a sync, then they are installed one by one.
+// A failed install does not stop the others; the result tells what happened to each.
+func InstallBatch(ctx context.Context, opts BatchInstallOptions) (*BatchResult, error) {
+	if len(opts.Specs) == 0 {
+		return nil, errors.InvalidInput("no repositories to install")
+	}
+	if opts.Install.Name != "" || opts.Install.As != "" || opts.Install.Rename != "" {
+		return nil, errors.InvalidInput("--name, --as and --rename select a single command; use \"owner/repo as name\" entries instead")
+	}
+
+	projectRoot, _ := findProjectRootFrom(opts.Install.ProjectPath)
+	ctx = withProjectRoot(ctx, projectRoot)
+
+	commands := make([]ConfigCommand, len(opts.Specs))
+	for i, spec := range opts.Specs {
+		repo, version, _ := ParseInstanceSpec(spec)
+		commands[i] = ConfigCommand{Repo: repo, Version: version}
+	}
+	tags := newTagCache(projectRoot, false)
+	tags.prefetch(ctx, tagRepositories(projectRoot, commands), configuredJobs(projectRoot))
+
+	result := &BatchResult{}
+	for i, spec := range opts.Specs {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		installOpts := opts.Install
+		installOpts.Repository, installOpts.Version, installOpts.As = ParseInstanceSpec(spec)
+		installOpts.tags = tags
+
+		output.PrintInfof("[%d/%d] Installing %s...", i+1, len(opts.Specs), spec)
+		_, _, err := Install(ctx, installOpts)
+		var multi *MultiCommandError
+		if opts.All && stderrors.As(err, &multi) {
+			_, err = InstallRepositoryCommands(ctx, installOpts, multi, multi.Commands)
+		}
+		switch {
+		case err == nil:
+			result.Installed = append(result.Installed, spec)
+		case ctx.Err() != nil:
+			return result, ctx.Err()
+		case stderrors.Is(err, errors.ErrAlreadyExists) && !installOpts.Force:
+			result.Skipped = append(result.Skipped, spec)
+		default:
+			result.Failed = append(result.Failed, &errors.Failure{
+				Name: spec, Repo: installOpts.Repository, Stage: "install", Err: err,
+			})
+		}
+	}
+	return result, nil
+}
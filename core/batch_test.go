@@ -0,0 +1,65 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+func TestParseSpecList(t *testing.T) {
+	for name, data := range map[string]string{
+		"lines": "# bootstrap\nacme/review\n\n  acme/deploy@^2.0.0 \nacme/review\nacme/deploy@v1 as deploy-v1\n",
+		"yaml":  "# bootstrap\n- acme/review\n- acme/deploy@^2.0.0\n- acme/deploy@v1 as deploy-v1\n",
+		"json":  `["acme/review", "acme/deploy@^2.0.0", "acme/deploy@v1 as deploy-v1", "acme/review"]`,
+	} {
+		specs, err := ParseSpecList([]byte(data))
+		require.NoError(t, err, name)
+		assert.Equal(t, []string{"acme/review", "acme/deploy@^2.0.0", "acme/deploy@v1 as deploy-v1"}, specs, name)
+	}
+
+	for _, data := range []string{"", "# nothing\n\n", "[]", "- [nested]\n", "[acme/review"} {
+		_, err := ParseSpecList([]byte(data))
+		assert.ErrorIs(t, err, errors.ErrInvalidInput, data)
+	}
+}
+
+func TestInstallBatch(t *testing.T) {
+	repo, _ := writeCommandRepo(t)
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	ctx := context.Background()
+	missing := "file://" + filepath.Join(t.TempDir(), "missing")
+
+	result, err := InstallBatch(ctx, BatchInstallOptions{Specs: []string{repo + "@^1.0.0", missing}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{repo + "@^1.0.0"}, result.Installed)
+	require.Len(t, result.Failed, 1)
+	assert.Equal(t, missing, result.Failed[0].Name)
+	assert.Equal(t, "failed to install 1 of 2 repositories", result.Err().Error())
+	assert.FileExists(t, filepath.Join(".claude", "commands", "tool.md"))
+
+	result, err = InstallBatch(ctx, BatchInstallOptions{Specs: []string{repo, repo + "@v1.0.0 as tool-v1"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{repo}, result.Skipped)
+	assert.Equal(t, []string{repo + "@v1.0.0 as tool-v1"}, result.Installed)
+	assert.Empty(t, result.Failed)
+
+	_, err = InstallBatch(ctx, BatchInstallOptions{Specs: []string{repo}, Install: InstallOptions{Name: "x"}})
+	assert.ErrorIs(t, err, errors.ErrInvalidInput)
+	_, err = InstallBatch(ctx, BatchInstallOptions{})
+	assert.ErrorIs(t, err, errors.ErrInvalidInput)
+}
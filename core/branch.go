@@ -0,0 +1,35 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+// recordBranch records the branch a command was installed from. Its resolved version
+// names the branch, so updates follow the branch head even when the default branch could
+// not be read from the checkout.
+func recordBranch(projectRoot, name, branch string) error {
+	lockPath := lockFilePath(projectRoot)
+	lockFile, err := ReadLockFile(lockPath)
+	if err != nil {
+		return err
+	}
+
+	cmd, ok := lockFile.Commands[name]
+	if !ok {
+		return nil
+	}
+	cmd.Branch = branch
+	cmd.Resolved = cmd.Source + "@" + branch
+
+	return WriteLockFile(lockPath, lockFile)
+}
+
+// BranchLabel marks the version of a command that tracks a branch, as "main (branch)"
+func BranchLabel(branch string) string {
+	return branch + " (branch)"
+}
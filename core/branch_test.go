@@ -0,0 +1,77 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstallTracksBranch(t *testing.T) {
+	repo, _ := writeCommandRepo(t)
+	dir := strings.TrimPrefix(repo, "file://")
+	git := func(args ...string) {
+		out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	// A repository without tags whose default branch is not main
+	git("tag", "--delete", "v1.0.0")
+	git("branch", "--move", "main", "trunk")
+
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	_, _, err := Install(ctx, InstallOptions{Repository: repo})
+	require.NoError(t, err)
+	locked := readLockFile(t).Commands["tool"]
+	require.NotNil(t, locked)
+	assert.Equal(t, "trunk", locked.Branch)
+	assert.Equal(t, repo+"@trunk", locked.Resolved)
+
+	cmd, err := GetCommandInfo("tool", "")
+	require.NoError(t, err)
+	assert.Equal(t, "trunk", cmd.Branch)
+	plan, needsUpdate := planUpdate(ctx, ".", *cmd, false, false)
+	assert.False(t, needsUpdate)
+	assert.Equal(t, " (trunk (branch))", versionChange(plan))
+
+	// The command stays on the branch when the repository gets its first tag
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "index.md"), []byte("# Tool v2\n"), 0644))
+	git("commit", "--quiet", "--all", "-m", "v2")
+	git("tag", "v2.0.0")
+	head, err := gitGetCurrentCommit(dir)
+	require.NoError(t, err)
+
+	plan, needsUpdate = planUpdate(ctx, ".", *cmd, false, false)
+	assert.True(t, needsUpdate)
+	assert.Equal(t, "trunk", plan.TargetVersion)
+	assert.Equal(t, "new commits on trunk", plan.Reason)
+
+	_, err = Update(ctx, UpdateOptions{Name: "tool"})
+	require.NoError(t, err)
+	locked = readLockFile(t).Commands["tool"]
+	assert.Equal(t, "trunk", locked.Branch)
+	assert.Equal(t, head, locked.Commit)
+
+	// Installing a tag stops tracking the branch
+	_, _, err = Install(ctx, InstallOptions{Repository: repo, Version: "v2.0.0", Force: true})
+	require.NoError(t, err)
+	locked = readLockFile(t).Commands["tool"]
+	assert.Empty(t, locked.Branch)
+	assert.Equal(t, repo+"@v2.0.0", locked.Resolved)
+}
@@ -0,0 +1,96 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/gifflet/ccmd/internal/fs"
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+// maxChangelogLines caps the excerpt shown before an update
+const maxChangelogLines = 40
+
+var (
+	changelogFileNames = []string{"CHANGELOG.md", "CHANGELOG", "CHANGES.md", "HISTORY.md", "RELEASE_NOTES.md"}
+
+	// changelogHeading matches "## [1.2.0] - 2025-01-01", "## v1.2.0" and similar headings
+	changelogHeading = regexp.MustCompile(`^#{1,3}\s+\[?(v?\d+\.\d+\.\d+[0-9A-Za-z.+-]*)\]?`)
+)
+
+// fetchChangelog returns the changelog published at a tag of a repository
+func fetchChangelog(ctx context.Context, projectRoot, repoURL, tag string) (string, error) {
+	tempDir, removeTempDir, err := newTempDir(projectRoot, "changelog")
+	if err != nil {
+		return "", err
+	}
+	defer removeTempDir()
+
+	repo, command := splitRepositoryCommand(repoURL)
+	if err := newGitClient(projectRoot).Checkout(ctx, repo, tag, tempDir, CloneOptions{}); err != nil {
+		return "", errors.GitError("clone", err)
+	}
+
+	// A command of a multi-command repository may keep its own changelog
+	dirs := []string{tempDir}
+	if command != "" {
+		dirs = []string{filepath.Join(tempDir, RepositoryCommandsDir, command), tempDir}
+	}
+	for _, dir := range dirs {
+		for _, name := range changelogFileNames {
+			data, err := os.ReadFile(filepath.Join(dir, name))
+			if err == nil {
+				return string(fs.NormalizeNewlines(data)), nil
+			}
+		}
+	}
+
+	return "", errors.NotFound("changelog")
+}
+
+// changelogExcerpt returns the changelog sections for versions after from, up to and
+// including to. It returns "" when from or to is not a semantic version.
+func changelogExcerpt(content, from, to string) string {
+	fromVersion, err := ParseSemver(from)
+	if err != nil {
+		return ""
+	}
+	toVersion, err := ParseSemver(to)
+	if err != nil {
+		return ""
+	}
+
+	var lines []string
+	include := false
+	for _, line := range strings.Split(content, "\n") {
+		if match := changelogHeading.FindStringSubmatch(line); match != nil {
+			v, err := ParseSemver(match[1])
+			include = err == nil && v.Compare(fromVersion) > 0 && v.Compare(toVersion) <= 0
+		}
+		if include {
+			lines = append(lines, line)
+		}
+	}
+
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	if len(lines) > maxChangelogLines {
+		lines = append(lines[:maxChangelogLines], "...")
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,304 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gifflet/ccmd/pkg/config"
+	"github.com/gifflet/ccmd/pkg/errors"
+	"github.com/gifflet/ccmd/pkg/repospec"
+)
+
+// Checks run by CI, in order
+const (
+	// CICheckLock compares ccmd.yaml with ccmd-lock.yaml
+	CICheckLock = "lock"
+	// CICheckSync installs the locked commands without writing either file
+	CICheckSync = "sync"
+	// CICheckIntegrity compares installed files with the lock file checksums
+	CICheckIntegrity = "integrity"
+	// CICheckPolicy applies the allowed_hosts, size_limit_mb and allowed_licenses settings
+	CICheckPolicy = "policy"
+)
+
+// CIOptions represents options for a CI run
+type CIOptions struct {
+	ProjectPath string
+	Profile     string // Also install the commands of this ccmd.yaml profile
+}
+
+// CIFinding is one problem found by a CI run. File and Line point at the project file
+// the problem is reported on, for annotations.
+type CIFinding struct {
+	Check   string `json:"check"`
+	Name    string `json:"name"`
+	File    string `json:"file"`
+	Line    int    `json:"line,omitempty"`
+	Message string `json:"message"`
+}
+
+// CIReport is the outcome of a CI run
+type CIReport struct {
+	Installed []string    `json:"installed"`
+	Removed   []string    `json:"removed"`
+	Checked   int         `json:"checked"`
+	Findings  []CIFinding `json:"findings"`
+}
+
+// OK reports whether no problems were found
+func (r *CIReport) OK() bool {
+	return len(r.Findings) == 0
+}
+
+// Count returns the number of findings of a check
+func (r *CIReport) Count(check string) int {
+	n := 0
+	for _, finding := range r.Findings {
+		if finding.Check == check {
+			n++
+		}
+	}
+	return n
+}
+
+// CI runs the checks a pipeline needs in one step: ccmd-lock.yaml must match ccmd.yaml,
+// the locked commands are installed without writing either file, installed files must
+// match their lock file checksums, and sources must satisfy the allowed_hosts and
+// size_limit_mb settings. Problems are returned as findings; the error is reserved for
+// failures that stop the run. Lock file drift skips the remaining checks.
+func CI(ctx context.Context, opts CIOptions) (*CIReport, error) {
+	projectRoot, err := findProjectRootFrom(opts.ProjectPath)
+	if err != nil {
+		return nil, err
+	}
+	if !ProjectConfigExists(projectRoot) {
+		return nil, errors.NotFound("ccmd.yaml not found in project")
+	}
+
+	report := &CIReport{Installed: []string{}, Removed: []string{}, Findings: []CIFinding{}}
+	lines := configLines(projectRoot)
+	configPath, lockRel := ciFiles(projectRoot)
+
+	drift, err := CheckLockDrift(projectRoot, opts.Profile)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range drift {
+		finding := CIFinding{Check: CICheckLock, Name: d.Repository, File: lockRel, Message: d.Reason}
+		if line := lines.find(d.Repository); line > 0 {
+			finding.File, finding.Line = configPath, line
+		}
+		report.Findings = append(report.Findings, finding)
+	}
+	if len(drift) > 0 {
+		return report, nil
+	}
+
+	synced, err := Sync(ctx, SyncOptions{ProjectPath: projectRoot, Profile: opts.Profile, Force: true, Frozen: true})
+	if err != nil {
+		return report, err
+	}
+	report.Installed = append(report.Installed, synced.Installed...)
+	report.Removed = append(report.Removed, synced.Removed...)
+	for _, failure := range synced.Failed {
+		report.Findings = append(report.Findings, CIFinding{
+			Check:   CICheckSync,
+			Name:    failure.Command,
+			File:    configPath,
+			Line:    lines.find(failure.Command),
+			Message: fmt.Sprintf("%s failed: %v", failure.Operation, failure.Error),
+		})
+	}
+
+	verified, err := Verify(VerifyOptions{ProjectPath: projectRoot})
+	if err != nil {
+		return report, err
+	}
+	report.Checked = verified.Checked
+	for _, issue := range verified.Issues {
+		report.Findings = append(report.Findings, CIFinding{
+			Check:   CICheckIntegrity,
+			Name:    issue.Name,
+			File:    installedPath(projectRoot, issue.Type, issue.Name),
+			Message: issue.Problem,
+		})
+	}
+
+	lockPath := lockFilePath(projectRoot)
+	if !fileExists(lockPath) {
+		return report, nil
+	}
+	lockFile, err := ReadLockFile(lockPath)
+	if err != nil {
+		return report, err
+	}
+	names := make([]string, 0, len(lockFile.Commands))
+	for name := range lockFile.Commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if changed, err := hasLocalChanges(projectRoot, name); err == nil && changed {
+			report.Findings = append(report.Findings, CIFinding{
+				Check:   CICheckIntegrity,
+				Name:    name,
+				File:    installedPath(projectRoot, "command", name),
+				Message: "installed files do not match the checksum in " + LockFileName,
+			})
+		}
+	}
+
+	report.Findings = append(report.Findings, policyFindings(projectRoot, lockFile, names, lines)...)
+	return report, nil
+}
+
+// policyFindings checks the locked sources against the allowed_hosts, size_limit_mb and
+// allowed_licenses settings. Local archives and files are not subject to allowed_hosts.
+func policyFindings(projectRoot string, lockFile *LockFile, names []string, lines configFile) []CIFinding {
+	settings, err := config.Load(projectRoot)
+	if err != nil {
+		return nil
+	}
+
+	allowed := make([]string, len(settings.AllowedHosts))
+	for i, host := range settings.AllowedHosts {
+		allowed[i] = strings.ToLower(strings.TrimSpace(host))
+	}
+	policy := repospec.AllowHosts(allowed...)
+	configPath, _ := ciFiles(projectRoot)
+	var findings []CIFinding
+	checkHost := func(name, source string) {
+		if len(allowed) == 0 {
+			return
+		}
+		spec, err := repospec.Parse(source)
+		if err != nil || !spec.Remote() || (spec.Form != repospec.FormURL && spec.Form != repospec.FormSCP) {
+			return
+		}
+		if host := spec.Host; policy(host) != nil {
+			findings = append(findings, CIFinding{
+				Check:   CICheckPolicy,
+				Name:    name,
+				File:    configPath,
+				Line:    lines.find(source),
+				Message: fmt.Sprintf("source host %s is not in allowed_hosts (%s)", host, strings.Join(allowed, ", ")),
+			})
+		}
+	}
+
+	licenses, _, _ := licensePolicy(projectRoot)
+	checkLicense := func(name, source, license string) {
+		if len(licenses) == 0 {
+			return
+		}
+		if problem := licenseProblem(license, licenses); problem != "" {
+			findings = append(findings, CIFinding{
+				Check:   CICheckPolicy,
+				Name:    name,
+				File:    configPath,
+				Line:    lines.find(source),
+				Message: problem,
+			})
+		}
+	}
+
+	for _, name := range names {
+		cmd := lockFile.Commands[name]
+		checkHost(name, cmd.Source)
+		checkLicense(name, cmd.Source, cmd.License)
+
+		if settings.SizeLimitMB <= 0 {
+			continue
+		}
+		size, err := dirSize(installedCommandDir(projectRoot, name))
+		if limit := int64(settings.SizeLimitMB) << 20; err == nil && size > limit {
+			findings = append(findings, CIFinding{
+				Check:   CICheckPolicy,
+				Name:    name,
+				File:    configPath,
+				Line:    lines.find(cmd.Source),
+				Message: fmt.Sprintf("installed size %s is above size_limit_mb (%s)", FormatSize(size), FormatSize(limit)),
+			})
+		}
+	}
+
+	plugins := make([]string, 0, len(lockFile.Plugins))
+	for name := range lockFile.Plugins {
+		plugins = append(plugins, name)
+	}
+	sort.Strings(plugins)
+	for _, name := range plugins {
+		plugin := lockFile.Plugins[name]
+		checkHost(name, plugin.Source)
+		checkLicense(name, plugin.Source, plugin.License)
+	}
+
+	return findings
+}
+
+// installedPath returns the project-relative directory of an installed command or plugin
+func installedPath(projectRoot, kind, name string) string {
+	dir := installedCommandDir(projectRoot, name)
+	if kind == "plugin" {
+		dir = filepath.Join(projectRoot, ".claude", "plugins", name)
+	}
+	return filepath.ToSlash(projectRelative(projectRoot, dir))
+}
+
+// ciFiles returns the paths of ccmd.yaml and ccmd-lock.yaml relative to the project
+// root, as findings report them
+func ciFiles(projectRoot string) (configPath, lockPath string) {
+	paths := projectPaths(projectRoot)
+	return filepath.ToSlash(projectRelative(projectRoot, paths.Config)),
+		filepath.ToSlash(projectRelative(projectRoot, paths.Lock))
+}
+
+// configFile holds the lines of ccmd.yaml, to point findings at the entry they concern
+type configFile []string
+
+func configLines(projectRoot string) configFile {
+	data, err := os.ReadFile(configFilePath(projectRoot))
+	if err != nil {
+		return nil
+	}
+
+	var lines configFile
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return lines
+}
+
+// find returns the 1-based line of the list entry for a repository, or 0
+func (f configFile) find(repo string) int {
+	target := ExtractRepoPath(repo)
+	for i, line := range f {
+		entry, ok := strings.CutPrefix(strings.TrimSpace(line), "- ")
+		if !ok {
+			continue
+		}
+		spec, _, _ := strings.Cut(entry, " #")
+		spec = strings.Trim(strings.TrimSpace(spec), `"'`)
+		if specRepo, _ := ParseCommandSpec(spec); spec != "" && ExtractRepoPath(specRepo) == target {
+			return i + 1
+		}
+	}
+	return 0
+}
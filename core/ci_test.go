@@ -0,0 +1,85 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCI(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	writeConfig(t, []string{})
+
+	src := writeLintRepo(t, map[string]string{"index.md": frontMatterIndex})
+	_, err := Pack(PackOptions{Path: src, OutputDir: "dist"})
+	require.NoError(t, err)
+	_, _, err = Install(context.Background(), InstallOptions{Repository: "dist/hello-1.0.0.ccmd.tgz"})
+	require.NoError(t, err)
+	require.NoError(t, os.RemoveAll(".claude"))
+
+	report, err := CI(context.Background(), CIOptions{ProjectPath: "."})
+	require.NoError(t, err)
+	assert.True(t, report.OK(), "%v", report.Findings)
+	assert.Equal(t, []string{"dist/hello-1.0.0.ccmd.tgz"}, report.Installed)
+	assert.Equal(t, 1, report.Checked)
+
+	// Edited files fail the integrity check
+	indexPath := filepath.Join(".claude", "commands", "hello", "index.md")
+	require.NoError(t, os.WriteFile(indexPath, []byte("Edited\n"), 0644))
+	report, err = CI(context.Background(), CIOptions{ProjectPath: "."})
+	require.NoError(t, err)
+	require.Equal(t, 1, report.Count(CICheckIntegrity), "%v", report.Findings)
+	assert.Equal(t, ".claude/commands/hello", report.Findings[0].File)
+
+	// Lock file drift is reported on the ccmd.yaml entry and skips the other checks
+	writeConfig(t, []string{"dist/hello-1.0.0.ccmd.tgz", "acme/tool@v1.0.0"})
+	report, err = CI(context.Background(), CIOptions{ProjectPath: "."})
+	require.NoError(t, err)
+	assert.Equal(t, []CIFinding{{
+		Check: CICheckLock, Name: "acme/tool", File: ConfigFileName, Line: 3, Message: "missing from ccmd-lock.yaml",
+	}}, report.Findings)
+}
+
+func TestCIPolicy(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	t.Setenv("CCMD_ALLOWED_HOSTS", "github.com")
+
+	writeConfig(t, []string{"github.com/acme/allowed", "gitlab.com/acme/denied"})
+	lockFile := createBasicLockFile()
+	lockFile.Commands["allowed"] = createTestLockCommand("allowed", "1.0.0", "https://github.com/acme/allowed.git")
+	lockFile.Commands["denied"] = createTestLockCommand("denied", "1.0.0", "https://gitlab.com/acme/denied.git")
+	writeLockFile(t, lockFile)
+	createCommandStructure(t, "allowed")
+	createCommandStructure(t, "denied")
+
+	findings := policyFindings(".", lockFile, []string{"allowed", "denied"}, configLines("."))
+	require.Len(t, findings, 1)
+	assert.Equal(t, "denied", findings[0].Name)
+	assert.Equal(t, 3, findings[0].Line)
+	assert.Contains(t, findings[0].Message, "gitlab.com is not in allowed_hosts")
+
+	t.Setenv("CCMD_ALLOWED_LICENSES", "MIT")
+	lockFile.Commands["allowed"].License = "MIT"
+	lockFile.Commands["denied"].License = "GPL-3.0"
+	findings = policyFindings(".", lockFile, []string{"allowed", "denied"}, configLines("."))
+	require.Len(t, findings, 2)
+	assert.Equal(t, "license GPL-3.0 is not in allowed_licenses (MIT)", findings[1].Message)
+
+	t.Setenv("CCMD_LICENSE_POLICY", "off")
+	assert.Len(t, policyFindings(".", lockFile, []string{"allowed", "denied"}, configLines(".")), 1)
+}
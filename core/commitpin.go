@@ -0,0 +1,96 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+// Prefixes of commit pins that name the hash algorithm of the repository, as in
+// owner/repo@sha1:<40 hex digits> or owner/repo@sha256:<64 hex digits>
+const (
+	CommitPinSHA1   = "sha1:"
+	CommitPinSHA256 = "sha256:"
+)
+
+// commitPinLengths maps each pin prefix to the length of the hashes it takes
+var commitPinLengths = map[string]int{
+	CommitPinSHA1:   40,
+	CommitPinSHA256: 64,
+}
+
+// parseCommitPin returns the commit hash of a version that pins a commit, either a
+// full hash or one with an algorithm prefix. Prefixed pins with a malformed hash are
+// an error rather than a branch name.
+func parseCommitPin(version string) (commit string, pinned bool, err error) {
+	for prefix, length := range commitPinLengths {
+		hash, ok := strings.CutPrefix(version, prefix)
+		if !ok {
+			continue
+		}
+		if len(hash) != length || !isCommitHash(hash) {
+			return "", false, errors.InvalidInput(fmt.Sprintf(
+				"commit pin %q must be %s followed by %d lowercase hex digits", version, prefix, length))
+		}
+		return hash, true, nil
+	}
+	if isFullCommitHash(version) {
+		return version, true, nil
+	}
+	return "", false, nil
+}
+
+// commitPinHash returns the plain hash of a prefixed commit pin, and any other version
+// unchanged
+func commitPinHash(version string) string {
+	if commit, pinned, err := parseCommitPin(version); err == nil && pinned {
+		return commit
+	}
+	return version
+}
+
+// CommitNotFoundError reports a pinned commit that no branch or tag of the repository
+// contains anymore, typically because it was force-pushed away or deleted upstream
+type CommitNotFoundError struct {
+	Repository string
+	Commit     string
+}
+
+// Error explains that the commit is gone and how to move on
+func (e *CommitNotFoundError) Error() string {
+	return fmt.Sprintf("commit %s no longer exists in %s (the branch or tag holding it was force-pushed or deleted); "+
+		"pin a commit that exists upstream", e.Commit, e.Repository)
+}
+
+// Is makes CommitNotFoundError match errors.ErrNotFound
+func (e *CommitNotFoundError) Is(target error) bool {
+	return target == errors.ErrNotFound
+}
+
+// commitReachable reports whether a branch or tag of the repository at dir contains
+// commit. Clones and fetches can carry objects no ref reaches anymore, so having the
+// object alone does not prove the commit still exists upstream.
+func commitReachable(ctx context.Context, git, dir, commit string, refs ...string) (bool, error) {
+	args := append([]string{"-C", dir, "for-each-ref", "--count=1", "--contains", commit}, refs...)
+	out, err := exec.CommandContext(ctx, git, args...).Output()
+	if ctx.Err() != nil {
+		return false, ctx.Err()
+	}
+	if err != nil {
+		// for-each-ref fails on objects it does not have
+		return false, nil
+	}
+	return strings.TrimSpace(string(out)) != "", nil
+}
@@ -0,0 +1,141 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gifflet/ccmd/pkg/config"
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+func TestParseCommitPin(t *testing.T) {
+	sha1 := "a76c96359914b84ed1bcdbc11df03e6313e09ecf"
+	sha256 := strings.Repeat("0123456789abcdef", 4)
+
+	tests := []struct {
+		version string
+		commit  string
+		pinned  bool
+	}{
+		{sha1, sha1, true},
+		{"sha1:" + sha1, sha1, true},
+		{sha256, sha256, true},
+		{"sha256:" + sha256, sha256, true},
+		{"a76c963", "", false},
+		{"v1.0.0", "", false},
+		{"main", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		commit, pinned, err := parseCommitPin(tt.version)
+		require.NoError(t, err, tt.version)
+		assert.Equal(t, tt.commit, commit, tt.version)
+		assert.Equal(t, tt.pinned, pinned, tt.version)
+	}
+
+	for _, version := range []string{"sha1:a76c963", "sha256:" + sha1, "sha1:" + strings.ToUpper(sha1), "sha1:"} {
+		_, _, err := parseCommitPin(version)
+		assert.ErrorIs(t, err, errors.ErrInvalidInput, version)
+	}
+
+	assert.Equal(t, sha1, commitPinHash("sha1:"+sha1))
+	assert.Equal(t, "v1.0.0", commitPinHash("v1.0.0"))
+}
+
+func TestInstallCommitPin(t *testing.T) {
+	repo, release := writeCommandRepo(t)
+	dir := strings.TrimPrefix(repo, "file://")
+	git := func(args ...string) {
+		out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	git("commit", "--quiet", "--allow-empty", "-m", "unreleased")
+	pinned, err := gitGetCurrentCommit(dir)
+	require.NoError(t, err)
+	release("v1.1.0")
+
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	ctx := context.Background()
+	t.Setenv(config.ConfigEnv, filepath.Join(t.TempDir(), "config.yaml"))
+
+	_, _, err = Install(ctx, InstallOptions{Repository: repo + "@sha1:" + pinned[:12]})
+	assert.ErrorIs(t, err, errors.ErrInvalidInput)
+
+	_, _, err = Install(ctx, InstallOptions{Repository: repo + "@sha1:" + pinned})
+	require.NoError(t, err)
+	assert.Equal(t, pinned, readLockFile(t).Commands["tool"].Commit)
+	data, err := os.ReadFile(ConfigFileName)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), repo+"@sha1:"+pinned, "the pin is kept as written")
+
+	// A pinned command is not updated, even when forced
+	result, err := Update(ctx, UpdateOptions{Name: "tool", Force: true})
+	require.NoError(t, err)
+	assert.Zero(t, result.UpdatedCount)
+	assert.Equal(t, pinned, readLockFile(t).Commands["tool"].Commit)
+
+	// Changing the pin in ccmd.yaml moves the command to the new commit
+	updated, err := gitGetCurrentCommit(dir)
+	require.NoError(t, err)
+	writeConfig(t, []string{repo + "@" + updated})
+	plan, err := ComputePlan(ctx, PlanOptions{ProjectPath: "."})
+	require.NoError(t, err)
+	require.Len(t, plan.Actions, 1)
+	assert.Equal(t, PlanUpdate, plan.Actions[0].Action)
+	_, err = Sync(ctx, SyncOptions{ProjectPath: ".", Plan: plan})
+	require.NoError(t, err)
+	assert.Equal(t, updated, readLockFile(t).Commands["tool"].Commit)
+
+	// ...as does installing another commit
+	_, _, err = Install(ctx, InstallOptions{Repository: repo + "@" + pinned, Force: true})
+	require.NoError(t, err)
+	assert.Equal(t, pinned, readLockFile(t).Commands["tool"].Commit)
+}
+
+func TestInstallCommitPinForcePushed(t *testing.T) {
+	repo, _ := writeCommandRepo(t)
+	dir := strings.TrimPrefix(repo, "file://")
+	git := func(args ...string) {
+		out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	git("commit", "--quiet", "--allow-empty", "-m", "rewritten")
+	pinned, err := gitGetCurrentCommit(dir)
+	require.NoError(t, err)
+	git("reset", "--quiet", "--hard", "HEAD~1")
+
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	for name, cacheDir := range map[string]string{"clone": "", "cache": t.TempDir()} {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv(config.ConfigEnv, filepath.Join(t.TempDir(), "config.yaml"))
+			t.Setenv("CCMD_CACHE_DIR", cacheDir)
+
+			_, _, err := Install(ctx, InstallOptions{Repository: repo + "@" + pinned})
+			var missing *CommitNotFoundError
+			require.ErrorAs(t, err, &missing)
+			assert.ErrorIs(t, err, errors.ErrNotFound)
+			assert.Equal(t, pinned, missing.Commit)
+			assert.Contains(t, err.Error(), "force-pushed")
+		})
+	}
+}
@@ -0,0 +1,357 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+// knownOS lists the GOOS values accepted in the os condition of an entry
+var knownOS = map[string]bool{
+	"aix": true, "android": true, "darwin": true, "dragonfly": true, "freebsd": true,
+	"illumos": true, "ios": true, "linux": true, "netbsd": true, "openbsd": true,
+	"plan9": true, "solaris": true, "windows": true,
+}
+
+// EntryCondition limits a commands, plugins or profile entry of ccmd.yaml to some
+// operating systems or environments. Install and sync treat an entry whose condition
+// does not hold as absent. It also carries the update channel of the entry, which does
+// not affect whether it applies.
+type EntryCondition struct {
+	// OS lists the operating systems, as GOOS values, the entry applies to
+	OS []string `yaml:"os,omitempty" json:"os,omitempty"`
+	// When is an expression over environment variables, such as env.TEAM == "backend"
+	When string `yaml:"when,omitempty" json:"when,omitempty"`
+	// Channel is the update channel of the entry: stable (default) or beta
+	Channel string `yaml:"channel,omitempty" json:"channel,omitempty"`
+}
+
+// Matches reports whether the condition holds on this machine. A condition with an
+// invalid expression never holds.
+func (c *EntryCondition) Matches() bool {
+	ok, err := c.matches(runtime.GOOS, os.Getenv)
+	return err == nil && ok
+}
+
+func (c *EntryCondition) matches(goos string, getenv func(string) string) (bool, error) {
+	if c == nil {
+		return true, nil
+	}
+	if len(c.OS) > 0 && !containsFold(c.OS, goos) {
+		return false, nil
+	}
+	if strings.TrimSpace(c.When) == "" {
+		return true, nil
+	}
+	return evalWhen(c.When, getenv)
+}
+
+// evalWhen evaluates a when expression. Terms are env.NAME (set and not empty),
+// env.NAME == "value" and env.NAME != "value", optionally negated with !, and combine
+// with && and ||; && binds tighter.
+func evalWhen(expr string, getenv func(string) string) (bool, error) {
+	tokens, err := tokenizeWhen(expr)
+	if err != nil {
+		return false, err
+	}
+
+	pos := 0
+	next := func() string {
+		if pos < len(tokens) {
+			pos++
+			return tokens[pos-1]
+		}
+		return ""
+	}
+	peek := func() string {
+		if pos < len(tokens) {
+			return tokens[pos]
+		}
+		return ""
+	}
+
+	term := func() (bool, error) {
+		negate := false
+		for peek() == "!" {
+			next()
+			negate = !negate
+		}
+		name, ok := strings.CutPrefix(next(), "env.")
+		if !ok || !isEnvName(name) {
+			return false, errors.InvalidInput(fmt.Sprintf("invalid condition %q: expected env.NAME", expr))
+		}
+		value := getenv(name)
+		result := value != ""
+		if op := peek(); op == "==" || op == "!=" {
+			next()
+			literal := next()
+			if len(literal) < 2 || (literal[0] != '"' && literal[0] != '\'') {
+				return false, errors.InvalidInput(fmt.Sprintf("invalid condition %q: expected a quoted value after %s", expr, op))
+			}
+			result = (value == literal[1:len(literal)-1]) == (op == "==")
+		}
+		return result != negate, nil
+	}
+
+	result := false
+	for {
+		all := true
+		for {
+			ok, err := term()
+			if err != nil {
+				return false, err
+			}
+			all = all && ok
+			if peek() != "&&" {
+				break
+			}
+			next()
+		}
+		result = result || all
+		if peek() != "||" {
+			break
+		}
+		next()
+	}
+	if pos != len(tokens) {
+		return false, errors.InvalidInput(fmt.Sprintf("invalid condition %q: unexpected %s", expr, peek()))
+	}
+	return result, nil
+}
+
+// tokenizeWhen splits a when expression into operators, quoted strings and words
+func tokenizeWhen(expr string) ([]string, error) {
+	var tokens []string
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t':
+			i++
+		case strings.HasPrefix(expr[i:], "&&"), strings.HasPrefix(expr[i:], "||"),
+			strings.HasPrefix(expr[i:], "=="), strings.HasPrefix(expr[i:], "!="):
+			tokens = append(tokens, expr[i:i+2])
+			i += 2
+		case c == '!':
+			tokens = append(tokens, "!")
+			i++
+		case c == '"' || c == '\'':
+			end := strings.IndexByte(expr[i+1:], c)
+			if end == -1 {
+				return nil, errors.InvalidInput(fmt.Sprintf("invalid condition %q: unterminated string", expr))
+			}
+			tokens = append(tokens, expr[i:i+end+2])
+			i += end + 2
+		default:
+			j := i
+			for j < len(expr) && strings.IndexByte(" \t&|=!\"'", expr[j]) == -1 {
+				j++
+			}
+			tokens = append(tokens, expr[i:j])
+			i = j
+		}
+	}
+	if len(tokens) == 0 {
+		return nil, errors.InvalidInput("empty condition")
+	}
+	return tokens, nil
+}
+
+func isEnvName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		if r != '_' && !(r >= 'A' && r <= 'Z') && !(r >= 'a' && r <= 'z') && !(i > 0 && r >= '0' && r <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// validate checks the os values, the when expression and the channel of a condition
+func (c *EntryCondition) validate() error {
+	if err := ValidateChannel(c.Channel); err != nil {
+		return err
+	}
+	for _, goos := range c.OS {
+		if !knownOS[strings.ToLower(goos)] {
+			return errors.InvalidInput(fmt.Sprintf("unknown os %q (use GOOS names such as darwin, linux or windows)", goos))
+		}
+	}
+	if strings.TrimSpace(c.When) != "" {
+		if _, err := evalWhen(c.When, func(string) string { return "" }); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// conditionFor returns the condition of an entry, or nil when it has none
+func (pc *ProjectConfig) conditionFor(spec string) *EntryCondition {
+	return pc.Conditions[specKey(spec)]
+}
+
+// applies reports whether an entry is active on this machine
+func (pc *ProjectConfig) applies(spec string) bool {
+	cond := pc.conditionFor(spec)
+	return cond == nil || cond.Matches()
+}
+
+// activeSpecs returns the entries of a list whose conditions hold
+func (pc *ProjectConfig) activeSpecs(specs []string) []string {
+	if len(pc.Conditions) == 0 {
+		return specs
+	}
+	active := make([]string, 0, len(specs))
+	for _, spec := range specs {
+		if pc.applies(spec) {
+			active = append(active, spec)
+		}
+	}
+	return active
+}
+
+// ActivePlugins returns the plugins whose conditions hold on this machine
+func (pc *ProjectConfig) ActivePlugins() []string {
+	return pc.activeSpecs(pc.Plugins)
+}
+
+// specLists returns the commands, plugins and profile lists of a ccmd.yaml document
+func specLists(root *yaml.Node) []*yaml.Node {
+	doc := documentMapping(root)
+	if doc == nil {
+		return nil
+	}
+	var lists []*yaml.Node
+	for i := 0; i+1 < len(doc.Content); i += 2 {
+		key, value := doc.Content[i], doc.Content[i+1]
+		switch key.Value {
+		case "commands", "plugins":
+			lists = append(lists, value)
+		case "profiles":
+			if value.Kind == yaml.MappingNode {
+				for j := 1; j < len(value.Content); j += 2 {
+					lists = append(lists, value.Content[j])
+				}
+			}
+		}
+	}
+	return lists
+}
+
+// extractConditions replaces the entries written as mappings that have a repo with
+// their spec, so the document decodes into plain spec lists, and returns their
+// conditions by specKey. The error reports the first invalid condition.
+func extractConditions(root *yaml.Node) (map[string]*EntryCondition, error) {
+	var conditions map[string]*EntryCondition
+	var firstErr error
+	for _, list := range specLists(root) {
+		if list.Kind != yaml.SequenceNode {
+			continue
+		}
+		for _, item := range list.Content {
+			if item.Kind != yaml.MappingNode {
+				continue
+			}
+			var repo *yaml.Node
+			for i := 0; i+1 < len(item.Content); i += 2 {
+				if item.Content[i].Value == "repo" && item.Content[i+1].Kind == yaml.ScalarNode {
+					repo = item.Content[i+1]
+				}
+			}
+			if repo == nil || strings.TrimSpace(repo.Value) == "" {
+				continue
+			}
+
+			cond := &EntryCondition{}
+			err := item.Decode(cond)
+			if err == nil {
+				err = cond.validate()
+			}
+			if err != nil && firstErr == nil {
+				firstErr = fmt.Errorf("line %d: %w", item.Line, err)
+			}
+
+			*item = yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: repo.Value, Line: item.Line, Column: item.Column}
+			if len(cond.OS) > 0 || cond.When != "" || cond.Channel != "" {
+				if conditions == nil {
+					conditions = make(map[string]*EntryCondition)
+				}
+				conditions[specKey(repo.Value)] = cond
+			}
+		}
+	}
+	return conditions, firstErr
+}
+
+// decodeProjectConfig parses ccmd.yaml, including entries written with conditions
+func decodeProjectConfig(data []byte, config *ProjectConfig) error {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return err
+	}
+	if root.Kind == 0 {
+		return nil
+	}
+	conditions, err := extractConditions(&root)
+	if err != nil {
+		return err
+	}
+	if err := root.Decode(config); err != nil {
+		return err
+	}
+	config.Conditions = conditions
+	return nil
+}
+
+// encodeProjectConfig renders ccmd.yaml, writing entries with conditions as mappings
+func encodeProjectConfig(config *ProjectConfig) ([]byte, error) {
+	if len(config.Conditions) == 0 {
+		return yaml.Marshal(config)
+	}
+
+	var root yaml.Node
+	if err := root.Encode(config); err != nil {
+		return nil, err
+	}
+	// Encode returns the mapping itself; specLists expects a document
+	doc := &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{&root}}
+	for _, list := range specLists(doc) {
+		for _, item := range list.Content {
+			cond := config.conditionFor(item.Value)
+			if item.Kind != yaml.ScalarNode || cond == nil {
+				continue
+			}
+			entry := struct {
+				Repo           string `yaml:"repo"`
+				EntryCondition `yaml:",inline"`
+			}{item.Value, *cond}
+			var mapping yaml.Node
+			if err := mapping.Encode(entry); err != nil {
+				return nil, err
+			}
+			// Short os lists read best inline: os: [darwin, linux]
+			for i := 1; i < len(mapping.Content); i += 2 {
+				if mapping.Content[i].Kind == yaml.SequenceNode {
+					mapping.Content[i].Style = yaml.FlowStyle
+				}
+			}
+			*item = mapping
+		}
+	}
+	return yaml.Marshal(doc)
+}
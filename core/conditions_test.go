@@ -0,0 +1,123 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvalWhen(t *testing.T) {
+	env := map[string]string{"TEAM": "backend", "CI": "true"}
+	getenv := func(name string) string { return env[name] }
+
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{`env.TEAM == "backend"`, true},
+		{`env.TEAM == 'frontend'`, false},
+		{`env.TEAM != "frontend"`, true},
+		{`env.CI`, true},
+		{`!env.CI`, false},
+		{`env.MISSING`, false},
+		{`env.TEAM == "frontend" || env.CI`, true},
+		{`env.TEAM == "backend" && !env.CI`, false},
+		{`env.MISSING && env.CI || env.TEAM == "backend"`, true},
+	}
+	for _, tt := range tests {
+		got, err := evalWhen(tt.expr, getenv)
+		require.NoError(t, err, tt.expr)
+		assert.Equal(t, tt.want, got, tt.expr)
+	}
+
+	for _, expr := range []string{``, `TEAM == "backend"`, `env.TEAM == backend`, `env.TEAM == "backend`, `env.TEAM env.CI`, `env.TEAM &&`} {
+		_, err := evalWhen(expr, getenv)
+		assert.Error(t, err, expr)
+	}
+}
+
+func TestConditionalEntries(t *testing.T) {
+	other := "windows"
+	if runtime.GOOS == "windows" {
+		other = "linux"
+	}
+	t.Setenv("CCMD_TEST_TEAM", "backend")
+
+	dir := t.TempDir()
+	data := "commands:\n" +
+		"  - owner/shared@v1.0.0\n" +
+		"  - repo: owner/native@v1.0.0\n" +
+		"    os: [" + runtime.GOOS + "]\n" +
+		"  - repo: owner/elsewhere@v1.0.0\n" +
+		"    os: [" + other + "]\n" +
+		"  - repo: owner/backend\n" +
+		"    when: env.CCMD_TEST_TEAM == \"backend\"\n" +
+		"plugins:\n" +
+		"  - repo: owner/frontend-plugin\n" +
+		"    when: env.CCMD_TEST_TEAM == \"frontend\"\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ConfigFileName), []byte(data), 0644))
+	assert.Empty(t, checkConfigData(ConfigFileName, []byte(data)))
+
+	config, err := LoadProjectConfig(dir)
+	require.NoError(t, err)
+	assert.Len(t, config.Commands, 4)
+	assert.Len(t, config.Conditions, 4)
+
+	commands, err := config.CommandsForProfile("")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"owner/shared@v1.0.0", "owner/native@v1.0.0", "owner/backend"}, commands)
+	assert.Empty(t, config.ActivePlugins())
+
+	// Conditions survive rewrites of ccmd.yaml, also for entries whose version changed
+	config.Commands[1] = "owner/native@v2.0.0"
+	require.NoError(t, SaveProjectConfig(dir, config))
+	written, err := os.ReadFile(filepath.Join(dir, ConfigFileName))
+	require.NoError(t, err)
+	assert.Contains(t, string(written), "- repo: owner/native@v2.0.0\n      os: ["+runtime.GOOS+"]")
+
+	reloaded, err := LoadProjectConfig(dir)
+	require.NoError(t, err)
+	assert.Equal(t, config.Commands, reloaded.Commands)
+	assert.Equal(t, config.Conditions, reloaded.Conditions)
+}
+
+func TestCheckConditionalEntries(t *testing.T) {
+	data := "commands:\n" +
+		"  - repo: owner/a\n" +
+		"    os: [macos]\n" +
+		"  - repo: owner/b\n" +
+		"    when: TEAM == backend\n" +
+		"  - repo: owner/c\n" +
+		"    platform: linux\n" +
+		"  - repo: owner/d\n" +
+		"    channel: nightly\n"
+
+	problems := checkConfigData(ConfigFileName, []byte(data))
+	require.Len(t, problems, 4)
+	assert.Equal(t, 3, problems[0].Line)
+	assert.Contains(t, problems[0].Message, `unknown os "macos"`)
+	assert.Equal(t, 5, problems[1].Line)
+	assert.Contains(t, problems[1].Message, "expected env.NAME")
+	assert.Equal(t, 7, problems[2].Line)
+	assert.Contains(t, problems[2].Message, `unknown field "platform"`)
+	assert.Equal(t, 9, problems[3].Line)
+	assert.Contains(t, problems[3].Message, "channel must be stable or beta")
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ConfigFileName), []byte(data), 0644))
+	_, err := LoadProjectConfig(dir)
+	assert.Error(t, err, "invalid conditions fail to load")
+}
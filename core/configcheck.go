@@ -0,0 +1,372 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	stderrors "errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/gifflet/ccmd/pkg/errors"
+	"github.com/gifflet/ccmd/pkg/repospec"
+)
+
+var (
+	// yamlSyntaxError matches the position of a yaml.v3 syntax error
+	yamlSyntaxError = regexp.MustCompile(`^yaml: line (\d+): (.+)$`)
+	// yamlTypeError matches one entry of a yaml.v3 TypeError
+	yamlTypeError = regexp.MustCompile(`^line (\d+): (.+)$`)
+)
+
+// ConfigError is a problem in ccmd.yaml at a line and column, both 1-based. Line is 0
+// when the YAML parser reports no position.
+type ConfigError struct {
+	File    string `json:"file"`
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+	Message string `json:"message"`
+	// Source is the text of the line, for Snippet
+	Source string `json:"-"`
+}
+
+// Error formats the problem as file:line:column: message
+func (e *ConfigError) Error() string {
+	switch {
+	case e.Line == 0:
+		return fmt.Sprintf("%s: %s", e.File, e.Message)
+	case e.Column == 0:
+		return fmt.Sprintf("%s:%d: %s", e.File, e.Line, e.Message)
+	}
+	return fmt.Sprintf("%s:%d:%d: %s", e.File, e.Line, e.Column, e.Message)
+}
+
+// Snippet returns the offending line with a caret under the column, or "" without a
+// position
+func (e *ConfigError) Snippet() string {
+	if e.Line == 0 {
+		return ""
+	}
+	gutter := strconv.Itoa(e.Line)
+	column := e.Column
+	if column < 1 {
+		column = len(e.Source) - len(strings.TrimLeft(e.Source, " \t")) + 1
+	}
+	// Tabs stay tabs so the caret lines up with the source
+	pad := strings.Map(func(r rune) rune {
+		if r == '\t' {
+			return r
+		}
+		return ' '
+	}, e.Source[:min(column-1, len(e.Source))])
+	return fmt.Sprintf(" %s | %s\n %s | %s^", gutter, e.Source, strings.Repeat(" ", len(gutter)), pad)
+}
+
+// ConfigErrors lists every problem found in ccmd.yaml, in file order. It matches
+// errors.ErrInvalidInput.
+type ConfigErrors []*ConfigError
+
+// Error lists the problems with their snippets
+func (e ConfigErrors) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d problem(s) in %s:", len(e), ConfigFileName)
+	for _, problem := range e {
+		b.WriteString("\n" + problem.Error())
+		if snippet := problem.Snippet(); snippet != "" {
+			b.WriteString("\n" + snippet)
+		}
+	}
+	return b.String()
+}
+
+// Is makes ConfigErrors match errors.ErrInvalidInput
+func (e ConfigErrors) Is(target error) bool {
+	return target == errors.ErrInvalidInput
+}
+
+// CheckProjectConfig parses ccmd.yaml and returns every syntax error, value of the
+// wrong type and invalid entry of commands, plugins and profiles it finds
+func CheckProjectConfig(projectPath string) (ConfigErrors, error) {
+	configPath := configFilePath(projectPath)
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, errors.FileError("read config", configPath, err)
+	}
+	return checkConfigData(ConfigFileName, data), nil
+}
+
+// checkConfigData returns the problems of a ccmd.yaml document, sorted by position
+func checkConfigData(file string, data []byte) ConfigErrors {
+	lines := strings.Split(string(data), "\n")
+	var problems ConfigErrors
+	report := func(line, column int, format string, args ...interface{}) {
+		source := ""
+		if line > 0 && line <= len(lines) {
+			source = strings.TrimRight(lines[line-1], "\r")
+		}
+		problems = append(problems, &ConfigError{
+			File:    file,
+			Line:    line,
+			Column:  column,
+			Message: fmt.Sprintf(format, args...),
+			Source:  source,
+		})
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		if m := yamlSyntaxError.FindStringSubmatch(err.Error()); m != nil {
+			line, _ := strconv.Atoi(m[1])
+			report(line, 0, "%s", m[2])
+		} else {
+			report(0, 0, "%s", strings.TrimPrefix(err.Error(), "yaml: "))
+		}
+		return problems
+	}
+
+	reported := make(map[int]bool)
+	if doc := documentMapping(&root); doc != nil {
+		for i := 0; i+1 < len(doc.Content); i += 2 {
+			key, value := doc.Content[i], doc.Content[i+1]
+			switch key.Value {
+			case "extends":
+				if value.Kind != yaml.ScalarNode {
+					report(value.Line, value.Column, "extends must name a single base configuration")
+				} else if _, err := parseBaseRef(value.Value); err != nil {
+					report(value.Line, value.Column, "%s", inputMessage(err))
+				}
+			case "commands":
+				checkSpecList(value, "commands", true, report)
+			case "plugins":
+				checkSpecList(value, "plugins", false, report)
+			case "profiles":
+				if value.Kind != yaml.MappingNode {
+					if value.Tag != "!!null" {
+						report(value.Line, value.Column, "profiles must map profile names to lists of commands")
+					}
+					continue
+				}
+				for j := 0; j+1 < len(value.Content); j += 2 {
+					checkSpecList(value.Content[j+1], "profile "+value.Content[j].Value, true, report)
+				}
+			}
+		}
+	}
+	for _, problem := range problems {
+		reported[problem.Line] = true
+	}
+
+	// Values of the wrong type elsewhere are reported by the decoder with their line.
+	// Entries with conditions decode as their spec.
+	_, _ = extractConditions(&root)
+	var config ProjectConfig
+	var typeErr *yaml.TypeError
+	if err := root.Decode(&config); stderrors.As(err, &typeErr) {
+		for _, msg := range typeErr.Errors {
+			m := yamlTypeError.FindStringSubmatch(msg)
+			if m == nil {
+				report(0, 0, "%s", msg)
+				continue
+			}
+			line, _ := strconv.Atoi(m[1])
+			if !reported[line] {
+				report(line, nodeColumn(&root, line), "%s", m[2])
+			}
+		}
+	} else if err != nil {
+		report(0, 0, "%v", err)
+	}
+
+	sort.SliceStable(problems, func(i, j int) bool {
+		if problems[i].Line != problems[j].Line {
+			return problems[i].Line < problems[j].Line
+		}
+		return problems[i].Column < problems[j].Column
+	})
+	return problems
+}
+
+// checkSpecList reports the entries of a commands, plugins or profile list that are not
+// valid specs, and entries listed twice. Instance names are allowed in command lists.
+func checkSpecList(list *yaml.Node, section string, instances bool, report func(line, column int, format string, args ...interface{})) {
+	if list.Kind != yaml.SequenceNode {
+		if list.Tag != "!!null" {
+			report(list.Line, list.Column, "%s must be a list of repository specs", section)
+		}
+		return
+	}
+
+	seen := make(map[string]int)
+	for _, item := range list.Content {
+		if item.Kind == yaml.MappingNode {
+			if item = checkConditionalEntry(item, section, report); item == nil {
+				continue
+			}
+		}
+		if item.Kind != yaml.ScalarNode || item.Tag == "!!null" {
+			report(item.Line, item.Column, "%s entries must be repository specs such as owner/repo@v1.0.0", section)
+			continue
+		}
+
+		offset, msg := checkSpec(item.Value, instances)
+		if msg != "" {
+			column := item.Column + offset
+			if item.Style&(yaml.DoubleQuotedStyle|yaml.SingleQuotedStyle) != 0 {
+				column++
+			}
+			report(item.Line, column, "%s", msg)
+			continue
+		}
+
+		key := specKey(item.Value)
+		if line, ok := seen[key]; ok {
+			report(item.Line, item.Column, "%q is already listed in %s on line %d", item.Value, section, line)
+			continue
+		}
+		seen[key] = item.Line
+	}
+}
+
+// checkConditionalEntry reports problems of an entry written as a mapping with repo, os,
+// when and channel. It returns the repo node, or nil when the entry has none.
+func checkConditionalEntry(entry *yaml.Node, section string, report func(line, column int, format string, args ...interface{})) *yaml.Node {
+	var repo *yaml.Node
+	var cond EntryCondition
+	for i := 0; i+1 < len(entry.Content); i += 2 {
+		key, value := entry.Content[i], entry.Content[i+1]
+		switch key.Value {
+		case "repo":
+			repo = value
+		case "os":
+			if value.Kind != yaml.SequenceNode {
+				report(value.Line, value.Column, "os must be a list such as [darwin, linux]")
+				continue
+			}
+			for _, goos := range value.Content {
+				if err := (&EntryCondition{OS: []string{goos.Value}}).validate(); err != nil {
+					report(goos.Line, goos.Column, "%s", inputMessage(err))
+				}
+			}
+		case "when":
+			cond.When = value.Value
+			if value.Kind != yaml.ScalarNode {
+				report(value.Line, value.Column, "when must be an expression such as env.TEAM == \"backend\"")
+			} else if err := cond.validate(); err != nil {
+				report(value.Line, value.Column, "%s", inputMessage(err))
+			}
+		case "channel":
+			if err := ValidateChannel(value.Value); err != nil || value.Kind != yaml.ScalarNode {
+				report(value.Line, value.Column, "channel must be stable or beta")
+			}
+		default:
+			report(key.Line, key.Column, "unknown field %q (entries accept repo, os, when and channel)", key.Value)
+		}
+	}
+
+	if repo == nil {
+		report(entry.Line, entry.Column, "%s entries written as mappings need a repo", section)
+	}
+	return repo
+}
+
+// checkSpec validates a command or plugin spec. It returns the byte offset of the
+// problem within the spec and a message, or "" when the spec is valid.
+func checkSpec(spec string, instances bool) (int, string) {
+	if strings.TrimSpace(spec) == "" {
+		return 0, "empty repository spec"
+	}
+	if len(spec) > repospec.MaxLength {
+		return 0, fmt.Sprintf("spec is %d bytes long; the limit is %d", len(spec), repospec.MaxLength)
+	}
+
+	body, instance, hasInstance := strings.Cut(spec, " as ")
+	if hasInstance {
+		if !instances {
+			return len(body) + 1, "plugins cannot be installed side by side with 'as'"
+		}
+		instance = strings.TrimSpace(instance)
+		if err := validateCommandName(instance); err != nil {
+			return len(body) + 4, "invalid instance name: " + inputMessage(err)
+		}
+	}
+
+	if isDownloadSource(body) {
+		if i := strings.IndexAny(body, " \t"); i >= 0 {
+			return i, fmt.Sprintf("unexpected space in %q", body)
+		}
+		return 0, ""
+	}
+
+	// Version constraints may contain spaces, repositories may not
+	repo, version := ParseRepositorySpec(body)
+	switch {
+	case strings.TrimSpace(repo) == "":
+		return 0, "missing repository"
+	case strings.IndexFunc(repo, unicode.IsSpace) >= 0:
+		return strings.IndexFunc(repo, unicode.IsSpace), fmt.Sprintf("unexpected space in repository %q", repo)
+	case strings.HasSuffix(body, "@"):
+		return len(body) - 1, "missing version after '@'"
+	case IsConstraint(version):
+		if _, err := ParseConstraint(version); err != nil {
+			return len(repo) + 1, inputMessage(err)
+		}
+	case strings.IndexFunc(version, unicode.IsSpace) >= 0:
+		return len(repo) + 1, fmt.Sprintf("unexpected space in version %q", version)
+	}
+	if _, _, err := parseCommitPin(version); err != nil {
+		return len(repo) + 1, inputMessage(err)
+	}
+
+	// What ParseRepositorySpec leaves whole did not parse
+	parsed, err := repospec.Parse(body)
+	if err != nil {
+		offset := 0
+		if i := repospec.NonASCIIIndex(body); i >= 0 {
+			offset = i
+		}
+		return offset, inputMessage(err)
+	}
+	if parsed.Remote() && strings.HasSuffix(parsed.Source, "/") {
+		return len(parsed.Source) - 1, fmt.Sprintf("trailing '/' after repository %q", strings.TrimRight(parsed.Source, "/"))
+	}
+	return 0, ""
+}
+
+// inputMessage returns the message of an invalid input error without its prefix
+func inputMessage(err error) string {
+	return strings.TrimPrefix(err.Error(), errors.ErrInvalidInput.Error()+": ")
+}
+
+// documentMapping returns the top-level mapping of a document, or nil
+func documentMapping(root *yaml.Node) *yaml.Node {
+	if root.Kind == yaml.DocumentNode && len(root.Content) > 0 && root.Content[0].Kind == yaml.MappingNode {
+		return root.Content[0]
+	}
+	return nil
+}
+
+// nodeColumn returns the column of the first node on a line, or 0
+func nodeColumn(node *yaml.Node, line int) int {
+	if node.Line == line && node.Kind != yaml.DocumentNode {
+		return node.Column
+	}
+	for _, child := range node.Content {
+		if column := nodeColumn(child, line); column > 0 {
+			return column
+		}
+	}
+	return 0
+}
@@ -0,0 +1,134 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"context"
+	stderrors "errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gifflet/ccmd/pkg/errors"
+	"github.com/gifflet/ccmd/pkg/repospec"
+)
+
+func TestCheckConfigData(t *testing.T) {
+	t.Run("reports every invalid entry with its position", func(t *testing.T) {
+		data := "commands:\n" +
+			"  - owner/good@v1.0.0\n" +
+			"  - owner/bad@\n" +
+			"  - \"owner/range@>=1.0 <x\"\n" +
+			"  - owner/good@v1.0.0\n" +
+			"  - os: [linux]\n" +
+			"profiles:\n" +
+			"  docs:\n" +
+			"    - owner/docs as bad/name\n" +
+			"plugins:\n" +
+			"  - owner/plugin as copy\n"
+
+		problems := checkConfigData(ConfigFileName, []byte(data))
+		require.Len(t, problems, 6)
+
+		positions := make([][2]int, len(problems))
+		for i, p := range problems {
+			positions[i] = [2]int{p.Line, p.Column}
+		}
+		assert.Equal(t, [][2]int{{3, 14}, {4, 18}, {5, 5}, {6, 5}, {9, 21}, {11, 18}}, positions)
+
+		assert.Equal(t, "ccmd.yaml:3:14: missing version after '@'", problems[0].Error())
+		assert.Equal(t, " 3 |   - owner/bad@\n   |              ^", problems[0].Snippet())
+		assert.Contains(t, problems[2].Message, "already listed in commands on line 2")
+		assert.Contains(t, problems[3].Message, "need a repo")
+	})
+
+	t.Run("reports syntax errors with their line", func(t *testing.T) {
+		problems := checkConfigData(ConfigFileName, []byte("commands:\n  - owner/a\n bad: [\n"))
+		require.Len(t, problems, 1)
+		assert.NotZero(t, problems[0].Line)
+		assert.NotEmpty(t, problems[0].Snippet())
+	})
+
+	t.Run("reports values of the wrong type", func(t *testing.T) {
+		problems := checkConfigData(ConfigFileName, []byte("commands:\n  - owner/a\ntags:\n  nested: true\n"))
+		require.Len(t, problems, 1)
+		assert.Equal(t, 4, problems[0].Line)
+		assert.Equal(t, 3, problems[0].Column)
+	})
+
+	t.Run("reports malformed commit pins", func(t *testing.T) {
+		problems := checkConfigData(ConfigFileName, []byte("commands:\n  - owner/a@sha1:a76c963\n"))
+		require.Len(t, problems, 1)
+		assert.Equal(t, 13, problems[0].Column)
+		assert.Contains(t, problems[0].Message, "40 lowercase hex digits")
+	})
+
+	t.Run("reports look-alike characters, trailing slashes and long specs", func(t *testing.T) {
+		data := "commands:\n  - owner/a＠v1.0.0\n  - owner/b/@v1.0.0\n  - \"owner/c\u00a0\"\n  - owner/" +
+			strings.Repeat("d", repospec.MaxLength) + "\n"
+		problems := checkConfigData(ConfigFileName, []byte(data))
+		require.Len(t, problems, 4)
+
+		assert.Equal(t, 12, problems[0].Column)
+		assert.Contains(t, problems[0].Message, "use an ASCII '@'")
+		assert.Equal(t, 12, problems[1].Column)
+		assert.Equal(t, `trailing '/' after repository "owner/b"`, problems[1].Message)
+		assert.Contains(t, problems[2].Message, "unexpected space")
+		assert.Contains(t, problems[3].Message, "the limit is")
+
+		cleanup := setupTestDir(t)
+		defer cleanup()
+		_, _, err := Install(context.Background(), InstallOptions{Repository: "owner/a＠v1.0.0"})
+		assert.ErrorIs(t, err, errors.ErrInvalidInput)
+	})
+
+	t.Run("accepts valid specs", func(t *testing.T) {
+		data := "commands:\n  - owner/a@^1.2.0\n  - owner/a@v2 as a-v2\n  - git@github.com:owner/b.git@v1.0.0\n" +
+			"  - https://example.com/c.tar.gz\n  - owner/d@>=1.0.0 <2.0.0\n" +
+			"  - owner/e@sha1:a76c96359914b84ed1bcdbc11df03e6313e09ecf\n"
+		assert.Empty(t, checkConfigData(ConfigFileName, []byte(data)))
+	})
+}
+
+func TestLoadProjectConfigErrors(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ConfigFileName), []byte("commands:\n  - owner/a\n  - [unclosed\n"), 0644))
+
+	_, err := LoadProjectConfig(dir)
+	require.Error(t, err)
+	assert.True(t, stderrors.Is(err, errors.ErrInvalidInput))
+	assert.Contains(t, err.Error(), "ccmd.yaml:")
+	assert.Contains(t, err.Error(), "^")
+
+	var problems ConfigErrors
+	require.True(t, stderrors.As(err, &problems))
+	assert.Len(t, problems, 1)
+}
+
+func TestLintProjectConfig(t *testing.T) {
+	dir := writeLintRepo(t, map[string]string{
+		"ccmd.yaml": "commands:\n  - owner/a@\n  - owner/b as \"\"\n",
+	})
+
+	report, err := Lint(dir)
+	require.NoError(t, err)
+	require.Len(t, report.Diagnostics, 2)
+	assert.Equal(t, 2, report.Errors)
+	for _, d := range report.Diagnostics {
+		assert.Equal(t, "config", d.Rule)
+		assert.NotZero(t, d.Column)
+		assert.NotEmpty(t, d.Snippet)
+	}
+	assert.Equal(t, "ccmd.yaml:2:12: error [config] missing version after '@'", report.Diagnostics[0].String())
+}
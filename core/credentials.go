@@ -0,0 +1,442 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gifflet/ccmd/pkg/config"
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+// Credential stores
+const (
+	// CredentialStoreAuto uses the OS keychain when one is available, else the encrypted file
+	CredentialStoreAuto = "auto"
+	// CredentialStoreKeychain keeps tokens in the macOS keychain or the Secret Service on Linux
+	CredentialStoreKeychain = "keychain"
+	// CredentialStoreFile keeps tokens AES-GCM encrypted in the user config directory
+	CredentialStoreFile = "file"
+)
+
+const (
+	// DefaultTokenUsername is the user name sent with a token over HTTPS. GitHub and
+	// GitLab accept any user name with a personal access token.
+	DefaultTokenUsername = "x-access-token"
+
+	credentialsFileName = "credentials.json"
+	credentialsKeyName  = "credentials.key"
+)
+
+// LoginOptions represents options for storing a forge token
+type LoginOptions struct {
+	Host     string // Forge host, e.g. github.com
+	Token    string
+	Username string // User name sent with the token (default: x-access-token)
+	Store    string // auto, keychain or file (default: auto)
+}
+
+// Credential describes a stored token without revealing it
+type Credential struct {
+	Host     string `json:"host"`
+	Username string `json:"username"`
+	Store    string `json:"store"`
+}
+
+// credentialsFile is the index of stored tokens. Tokens of the file store are kept
+// encrypted in Secret; keychain tokens are only referenced.
+type credentialsFile struct {
+	Hosts map[string]*storedCredential `json:"hosts"`
+}
+
+type storedCredential struct {
+	Username string `json:"username"`
+	Store    string `json:"store"`
+	Secret   string `json:"secret,omitempty"`
+}
+
+// hostCredential is a token ready to be sent to a host
+type hostCredential struct {
+	username string
+	token    string
+}
+
+var (
+	credentialCacheMu sync.Mutex
+	credentialCache   map[string]hostCredential // loaded once per process, nil until then
+)
+
+// Login stores a token for a forge host. git commands and forge API requests of later
+// runs send it to that host, so tokens no longer need to be exported in the shell.
+func Login(opts LoginOptions) (*Credential, error) {
+	host, err := normalizeCredentialHost(opts.Host)
+	if err != nil {
+		return nil, err
+	}
+	token := strings.TrimSpace(opts.Token)
+	if token == "" {
+		return nil, errors.InvalidInput("token is required")
+	}
+	username := opts.Username
+	if username == "" {
+		username = DefaultTokenUsername
+	}
+
+	store, err := resolveCredentialStore(opts.Store)
+	if err != nil {
+		return nil, err
+	}
+
+	index, err := readCredentialsFile()
+	if err != nil {
+		return nil, err
+	}
+	if previous, ok := index.Hosts[host]; ok && previous.Store == CredentialStoreKeychain && store != CredentialStoreKeychain {
+		if kc := systemKeychain(); kc != nil {
+			_ = kc.remove(host)
+		}
+	}
+
+	entry := &storedCredential{Username: username, Store: store}
+	switch store {
+	case CredentialStoreKeychain:
+		if err := systemKeychain().set(host, token); err != nil {
+			return nil, errors.FileError("store token in keychain", host, err)
+		}
+	case CredentialStoreFile:
+		if entry.Secret, err = encryptToken(host, token); err != nil {
+			return nil, err
+		}
+	}
+
+	index.Hosts[host] = entry
+	if err := writeCredentialsFile(index); err != nil {
+		return nil, err
+	}
+	resetCredentialCache()
+
+	return &Credential{Host: host, Username: username, Store: store}, nil
+}
+
+// Logout removes the stored token of a host
+func Logout(host string) error {
+	host, err := normalizeCredentialHost(host)
+	if err != nil {
+		return err
+	}
+
+	index, err := readCredentialsFile()
+	if err != nil {
+		return err
+	}
+	entry, ok := index.Hosts[host]
+	if !ok {
+		return errors.NotFound(fmt.Sprintf("credentials for %s", host))
+	}
+
+	if entry.Store == CredentialStoreKeychain {
+		if kc := systemKeychain(); kc != nil {
+			if err := kc.remove(host); err != nil {
+				return errors.FileError("remove token from keychain", host, err)
+			}
+		}
+	}
+
+	delete(index.Hosts, host)
+	if err := writeCredentialsFile(index); err != nil {
+		return err
+	}
+	resetCredentialCache()
+	return nil
+}
+
+// ListCredentials returns the hosts with a stored token, sorted by host
+func ListCredentials() ([]Credential, error) {
+	index, err := readCredentialsFile()
+	if err != nil {
+		return nil, err
+	}
+
+	credentials := make([]Credential, 0, len(index.Hosts))
+	for host, entry := range index.Hosts {
+		credentials = append(credentials, Credential{Host: host, Username: entry.Username, Store: entry.Store})
+	}
+	sort.Slice(credentials, func(i, j int) bool { return credentials[i].Host < credentials[j].Host })
+	return credentials, nil
+}
+
+// normalizeCredentialHost accepts a host name or URL and returns the lowercase host
+func normalizeCredentialHost(host string) (string, error) {
+	host = strings.TrimSpace(host)
+	if idx := strings.Index(host, "://"); idx != -1 {
+		host = host[idx+3:]
+	}
+	if idx := strings.IndexAny(host, "/?#"); idx != -1 {
+		host = host[:idx]
+	}
+	host = strings.ToLower(host)
+	if host == "" || strings.ContainsAny(host, " \t@") {
+		return "", errors.InvalidInput(fmt.Sprintf("invalid host %q", host))
+	}
+	return host, nil
+}
+
+// resolveCredentialStore turns the requested store into keychain or file
+func resolveCredentialStore(store string) (string, error) {
+	switch store {
+	case "", CredentialStoreAuto:
+		if systemKeychain() != nil {
+			return CredentialStoreKeychain, nil
+		}
+		return CredentialStoreFile, nil
+	case CredentialStoreKeychain:
+		if systemKeychain() == nil {
+			return "", errors.InvalidInput("no OS keychain available; use --store file")
+		}
+		return store, nil
+	case CredentialStoreFile:
+		return store, nil
+	default:
+		return "", errors.InvalidInput(fmt.Sprintf("unknown credential store %q (valid: auto, keychain, file)", store))
+	}
+}
+
+// credentialsDir returns the directory of the credentials index and key, next to the
+// user config file
+func credentialsDir() (string, error) {
+	path, err := config.UserConfigPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Dir(path), nil
+}
+
+func readCredentialsFile() (*credentialsFile, error) {
+	index := &credentialsFile{Hosts: make(map[string]*storedCredential)}
+
+	dir, err := credentialsDir()
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(dir, credentialsFileName)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return index, nil
+	}
+	if err != nil {
+		return nil, errors.FileError("read credentials", path, err)
+	}
+	if err := json.Unmarshal(data, index); err != nil {
+		return nil, errors.FileError("parse credentials", path, err)
+	}
+	if index.Hosts == nil {
+		index.Hosts = make(map[string]*storedCredential)
+	}
+	return index, nil
+}
+
+func writeCredentialsFile(index *credentialsFile) error {
+	dir, err := credentialsDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return errors.FileError("create config directory", dir, err)
+	}
+
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return errors.FileError("marshal credentials", credentialsFileName, err)
+	}
+	return writeFileAtomic(filepath.Join(dir, credentialsFileName), append(data, '\n'), 0600)
+}
+
+// credentialsKey returns the key of the file store, creating it on first use. The key
+// sits next to the tokens, so the encryption keeps them out of backups, search results
+// and screen shares rather than from someone who can read the user's files.
+func credentialsKey(create bool) ([]byte, error) {
+	dir, err := credentialsDir()
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(dir, credentialsKeyName)
+
+	key, err := os.ReadFile(path)
+	if err == nil && len(key) == 32 {
+		return key, nil
+	}
+	if err != nil && !os.IsNotExist(err) {
+		return nil, errors.FileError("read credentials key", path, err)
+	}
+	if !create {
+		return nil, errors.NotFound("credentials key")
+	}
+
+	key = make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, errors.FileError("generate credentials key", path, err)
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, errors.FileError("create config directory", dir, err)
+	}
+	if err := writeFileAtomic(path, key, 0600); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// encryptToken seals a token with AES-256-GCM, bound to its host
+func encryptToken(host, token string) (string, error) {
+	key, err := credentialsKey(true)
+	if err != nil {
+		return "", err
+	}
+	gcm, err := newCredentialsCipher(key)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", errors.FileError("encrypt token", host, err)
+	}
+	sealed := gcm.Seal(nonce, nonce, []byte(token), []byte(host))
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func decryptToken(host, secret string) (string, error) {
+	key, err := credentialsKey(false)
+	if err != nil {
+		return "", err
+	}
+	gcm, err := newCredentialsCipher(key)
+	if err != nil {
+		return "", err
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(secret)
+	if err != nil || len(sealed) < gcm.NonceSize() {
+		return "", errors.InvalidInput(fmt.Sprintf("corrupt token for %s", host))
+	}
+	token, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], []byte(host))
+	if err != nil {
+		return "", errors.InvalidInput(fmt.Sprintf("cannot decrypt token for %s; run 'ccmd login %s' again", host, host))
+	}
+	return string(token), nil
+}
+
+func newCredentialsCipher(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.InvalidInput(fmt.Sprintf("invalid credentials key: %v", err))
+	}
+	return cipher.NewGCM(block)
+}
+
+// storedCredentials returns the usable tokens of every host, loading them once per
+// process. Tokens that cannot be read are skipped, leaving the host unauthenticated.
+func storedCredentials() map[string]hostCredential {
+	credentialCacheMu.Lock()
+	defer credentialCacheMu.Unlock()
+	if credentialCache != nil {
+		return credentialCache
+	}
+
+	credentialCache = make(map[string]hostCredential)
+	index, err := readCredentialsFile()
+	if err != nil {
+		return credentialCache
+	}
+
+	for host, entry := range index.Hosts {
+		if token, err := loadToken(host, entry); err == nil && token != "" {
+			credentialCache[host] = hostCredential{username: entry.Username, token: token}
+		}
+	}
+	return credentialCache
+}
+
+func loadToken(host string, entry *storedCredential) (string, error) {
+	switch entry.Store {
+	case CredentialStoreKeychain:
+		if kc := systemKeychain(); kc != nil {
+			return kc.get(host)
+		}
+		return "", errors.NotFound("OS keychain")
+	case CredentialStoreFile:
+		return decryptToken(host, entry.Secret)
+	default:
+		return "", errors.InvalidInput(fmt.Sprintf("unknown credential store %q", entry.Store))
+	}
+}
+
+func resetCredentialCache() {
+	credentialCacheMu.Lock()
+	defer credentialCacheMu.Unlock()
+	credentialCache = nil
+}
+
+// hostToken returns the stored token of a host. API hosts such as api.github.com use
+// the token of their forge.
+func hostToken(host string) (hostCredential, bool) {
+	credentials := storedCredentials()
+	host = strings.ToLower(host)
+	if cred, ok := credentials[host]; ok {
+		return cred, true
+	}
+	cred, ok := credentials[strings.TrimPrefix(host, "api.")]
+	return cred, ok
+}
+
+// authorizeRequest sends the stored token of the request host as a bearer token, unless
+// the request is already authorized
+func authorizeRequest(req *http.Request) {
+	if req.Header.Get("Authorization") != "" || req.URL.Scheme != "https" {
+		return
+	}
+	if cred, ok := hostToken(req.URL.Hostname()); ok {
+		req.Header.Set("Authorization", "Bearer "+cred.token)
+	}
+}
+
+// gitCredentialEnv adds the stored tokens to a git environment as per-host HTTP
+// headers, passed through GIT_CONFIG_COUNT so they never reach a config file or the
+// command line
+func gitCredentialEnv(env []string) []string {
+	credentials := storedCredentials()
+	if len(credentials) == 0 {
+		return env
+	}
+
+	hosts := make([]string, 0, len(credentials))
+	for host := range credentials {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+
+	entries := make([][2]string, 0, len(hosts))
+	for _, host := range hosts {
+		cred := credentials[host]
+		basic := base64.StdEncoding.EncodeToString([]byte(cred.username + ":" + cred.token))
+		entries = append(entries, [2]string{"http.https://" + host + "/.extraHeader", "Authorization: Basic " + basic})
+	}
+	return appendGitConfig(env, entries)
+}
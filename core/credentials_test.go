@@ -0,0 +1,158 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"encoding/base64"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gifflet/ccmd/pkg/config"
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+// fakeKeychain keeps secrets in memory
+type fakeKeychain map[string]string
+
+func (k fakeKeychain) set(host, token string) error { k[host] = token; return nil }
+func (k fakeKeychain) remove(host string) error     { delete(k, host); return nil }
+func (k fakeKeychain) get(host string) (string, error) {
+	if token, ok := k[host]; ok {
+		return token, nil
+	}
+	return "", errors.NotFound(host)
+}
+
+// setupCredentials points the credential store at a temporary config directory and
+// replaces the OS keychain with kc (nil for none)
+func setupCredentials(t *testing.T, kc keychain) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	t.Setenv(config.ConfigEnv, filepath.Join(dir, "config.yaml"))
+
+	previous := systemKeychain
+	systemKeychain = func() keychain { return kc }
+	resetCredentialCache()
+	t.Cleanup(func() {
+		systemKeychain = previous
+		resetCredentialCache()
+	})
+	return dir
+}
+
+func TestLoginFileStore(t *testing.T) {
+	dir := setupCredentials(t, nil)
+
+	cred, err := Login(LoginOptions{Host: "https://GitHub.com/", Token: "ghp_secret\n"})
+	require.NoError(t, err)
+	assert.Equal(t, Credential{Host: "github.com", Username: DefaultTokenUsername, Store: CredentialStoreFile}, *cred)
+
+	data, err := os.ReadFile(filepath.Join(dir, credentialsFileName))
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "ghp_secret", "tokens are stored encrypted")
+
+	info, err := os.Stat(filepath.Join(dir, credentialsKeyName))
+	require.NoError(t, err)
+	if filepath.Separator == '/' {
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	}
+
+	stored, ok := hostToken("github.com")
+	require.True(t, ok)
+	assert.Equal(t, "ghp_secret", stored.token)
+	_, ok = hostToken("api.github.com")
+	assert.True(t, ok, "API hosts use the token of their forge")
+
+	list, err := ListCredentials()
+	require.NoError(t, err)
+	assert.Len(t, list, 1)
+
+	require.NoError(t, Logout("github.com"))
+	_, ok = hostToken("github.com")
+	assert.False(t, ok)
+	assert.ErrorIs(t, Logout("github.com"), errors.ErrNotFound)
+}
+
+func TestLoginKeychainStore(t *testing.T) {
+	kc := fakeKeychain{}
+	dir := setupCredentials(t, kc)
+
+	cred, err := Login(LoginOptions{Host: "gitlab.example.com", Token: "glpat-1", Username: "oauth2"})
+	require.NoError(t, err)
+	assert.Equal(t, CredentialStoreKeychain, cred.Store)
+	assert.Equal(t, "glpat-1", kc["gitlab.example.com"])
+
+	data, err := os.ReadFile(filepath.Join(dir, credentialsFileName))
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "glpat-1")
+
+	// Moving the token to the file store removes it from the keychain
+	_, err = Login(LoginOptions{Host: "gitlab.example.com", Token: "glpat-2", Store: CredentialStoreFile})
+	require.NoError(t, err)
+	assert.NotContains(t, kc, "gitlab.example.com")
+}
+
+func TestLoginValidation(t *testing.T) {
+	setupCredentials(t, nil)
+
+	_, err := Login(LoginOptions{Host: "github.com"})
+	assert.ErrorIs(t, err, errors.ErrInvalidInput)
+	_, err = Login(LoginOptions{Host: "", Token: "x"})
+	assert.ErrorIs(t, err, errors.ErrInvalidInput)
+	_, err = Login(LoginOptions{Host: "github.com", Token: "x", Store: CredentialStoreKeychain})
+	assert.ErrorIs(t, err, errors.ErrInvalidInput, "no keychain available")
+	_, err = Login(LoginOptions{Host: "github.com", Token: "x", Store: "vault"})
+	assert.ErrorIs(t, err, errors.ErrInvalidInput)
+}
+
+func TestGitCredentialEnv(t *testing.T) {
+	setupCredentials(t, nil)
+	t.Setenv("GIT_CONFIG_COUNT", "1")
+
+	assert.Equal(t, []string{"A=1"}, gitCredentialEnv([]string{"A=1"}))
+
+	_, err := Login(LoginOptions{Host: "github.com", Token: "ghp_secret"})
+	require.NoError(t, err)
+
+	basic := base64.StdEncoding.EncodeToString([]byte("x-access-token:ghp_secret"))
+	assert.Equal(t, []string{
+		"A=1",
+		"GIT_CONFIG_KEY_1=http.https://github.com/.extraHeader",
+		"GIT_CONFIG_VALUE_1=Authorization: Basic " + basic,
+		"GIT_CONFIG_COUNT=2",
+	}, gitCredentialEnv([]string{"A=1"}))
+}
+
+func TestAuthorizeRequest(t *testing.T) {
+	setupCredentials(t, nil)
+	_, err := Login(LoginOptions{Host: "example.com", Token: "secret"})
+	require.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodGet, "https://example.com/prompt.md", http.NoBody)
+	require.NoError(t, err)
+	authorizeRequest(req)
+	assert.Equal(t, "Bearer secret", req.Header.Get("Authorization"))
+
+	req, err = http.NewRequest(http.MethodGet, "http://example.com/prompt.md", http.NoBody)
+	require.NoError(t, err)
+	authorizeRequest(req)
+	assert.Empty(t, req.Header.Get("Authorization"), "tokens are only sent over HTTPS")
+
+	req, err = http.NewRequest(http.MethodGet, "https://other.com/prompt.md", http.NoBody)
+	require.NoError(t, err)
+	authorizeRequest(req)
+	assert.Empty(t, req.Header.Get("Authorization"))
+}
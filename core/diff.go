@@ -0,0 +1,290 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+// File states reported by Diff, relative to the source
+const (
+	DiffModified = "modified" // changed locally
+	DiffAdded    = "added"    // only in the installed copy
+	DiffRemoved  = "removed"  // only in the source
+)
+
+// DiffOptions represents options for comparing an installed command with its source
+type DiffOptions struct {
+	Name        string
+	Version     string // tag, branch or commit to compare against; defaults to the locked commit
+	ProjectPath string
+}
+
+// FileDiff describes the differences in one file
+type FileDiff struct {
+	Path    string `json:"path"`
+	Status  string `json:"status"` // DiffModified, DiffAdded or DiffRemoved
+	Added   int    `json:"added"`
+	Removed int    `json:"removed"`
+	Patch   string `json:"patch,omitempty"` // unified diff from the source to the installed file
+}
+
+// DiffReport lists the differences between an installed command and its source
+type DiffReport struct {
+	Name       string     `json:"name"`
+	Repository string     `json:"repository"`
+	Ref        string     `json:"ref"` // version the installed copy was compared against
+	Files      []FileDiff `json:"files"`
+}
+
+// Modified reports whether the installed copy differs from the source
+func (r *DiffReport) Modified() bool {
+	return len(r.Files) > 0
+}
+
+// Diff fetches a command's source at its locked commit (or at opts.Version) and compares
+// it with the installed copy, so local edits can be found before update or install
+// --force overwrites them. The ccmd.yaml rewritten at install time is compared in the
+// same form, so only real edits show up.
+func Diff(ctx context.Context, opts DiffOptions) (*DiffReport, error) {
+	if opts.Name == "" {
+		return nil, errors.InvalidInput("command name is required")
+	}
+
+	projectRoot, err := findProjectRootFrom(opts.ProjectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	lockPath := lockFilePath(projectRoot)
+	if !fileExists(lockPath) {
+		return nil, errors.NotFound(fmt.Sprintf("command %q", opts.Name))
+	}
+
+	lockFile, err := ReadLockFile(lockPath)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, ok := lockFile.Commands[opts.Name]
+	if !ok {
+		return nil, errors.NotFound(fmt.Sprintf("command %q", opts.Name))
+	}
+	if entry.Local() {
+		return nil, errors.InvalidInput(fmt.Sprintf("command %q is local and has no source to compare with", opts.Name))
+	}
+
+	installedDir := installedCommandDir(projectRoot, opts.Name)
+	if !dirExists(installedDir) {
+		return nil, errors.NotFound(fmt.Sprintf("installed files of command %q", opts.Name))
+	}
+
+	tempDir, removeTempDir, err := newTempDir(projectRoot, "diff")
+	if err != nil {
+		return nil, err
+	}
+	defer removeTempDir()
+
+	report := &DiffReport{Name: opts.Name, Repository: entry.Source, Files: []FileDiff{}}
+
+	sourceDir := tempDir
+	if isDownloadSource(entry.Source) && opts.Version != "" {
+		return nil, errors.InvalidInput("archive and markdown installs have no versions to compare against; omit --version")
+	}
+	switch {
+	case IsMarkdownSource(entry.Source):
+		// Compared with what the URL serves now, not the recorded checksum
+		digest, err := fetchMarkdown(ctx, entry.Source, "", tempDir)
+		if err != nil {
+			return nil, err
+		}
+		report.Ref = digest
+	case IsArchiveSource(entry.Source):
+		root, digest, err := fetchArchive(ctx, entry.Source, archiveChecksum(entry.Commit), tempDir)
+		if err != nil {
+			return nil, err
+		}
+		sourceDir = root
+		report.Ref = digest
+	default:
+		report.Ref = diffRef(entry, opts.Version)
+		repo, command := splitRepositoryCommand(entry.Source)
+		if err := newGitClient(projectRoot).Checkout(ctx, repo, report.Ref, tempDir, CloneOptions{}); err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			return nil, errors.GitError("clone", err)
+		}
+		// Commands installed with their submodules are compared with them
+		if len(entry.Submodules) > 0 && hasSubmodules(tempDir) {
+			if err := gitUpdateSubmodules(ctx, tempDir); err != nil {
+				return nil, errors.GitError("submodule update", err)
+			}
+		}
+		if command != "" {
+			if sourceDir, err = repositoryCommandDir(tempDir, repo, command); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	sourceFiles, err := diffFileList(sourceDir)
+	if err != nil {
+		return nil, err
+	}
+	// Install writes a ccmd.yaml even when the source keeps its metadata in front matter
+	if sources, err := loadMetadataSources(sourceDir); err == nil && sources.found() {
+		sourceFiles["ccmd.yaml"] = true
+	}
+	installedFiles, err := diffFileList(installedDir)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make(map[string]bool)
+	for path := range sourceFiles {
+		paths[path] = true
+	}
+	for path := range installedFiles {
+		paths[path] = true
+	}
+
+	sorted := make([]string, 0, len(paths))
+	for path := range paths {
+		sorted = append(sorted, path)
+	}
+	sort.Strings(sorted)
+
+	for _, path := range sorted {
+		var source, installed []byte
+		if sourceFiles[path] {
+			if path == "ccmd.yaml" {
+				source = installedMetadata(sourceDir, opts.Name, entry.Source)
+			} else if source, err = os.ReadFile(filepath.Join(sourceDir, path)); err != nil {
+				return nil, errors.FileError("read source file", path, err)
+			}
+		}
+		if installedFiles[path] {
+			if installed, err = os.ReadFile(filepath.Join(installedDir, path)); err != nil {
+				return nil, errors.FileError("read installed file", path, err)
+			}
+		}
+
+		slashPath := filepath.ToSlash(path)
+		fromName, toName := "a/"+slashPath, "b/"+slashPath
+		status := DiffModified
+		switch {
+		case !installedFiles[path]:
+			status, toName = DiffRemoved, "/dev/null"
+		case !sourceFiles[path]:
+			status, fromName = DiffAdded, "/dev/null"
+		}
+
+		patch, added, removed := unifiedDiff(fromName, toName, source, installed)
+		if patch == "" && status == DiffModified {
+			continue
+		}
+		report.Files = append(report.Files, FileDiff{
+			Path:    slashPath,
+			Status:  status,
+			Added:   added,
+			Removed: removed,
+			Patch:   patch,
+		})
+	}
+
+	return report, nil
+}
+
+// diffRef picks the version to fetch: the requested one, else the locked commit, else
+// the resolved ref or version
+func diffRef(entry *LockCommand, version string) string {
+	if version != "" {
+		return version
+	}
+	if isCommitHash(entry.Commit) {
+		return entry.Commit
+	}
+	if _, ref := ParseRepositorySpec(entry.Resolved); ref != "" {
+		return ref
+	}
+	return entry.Version
+}
+
+// archiveChecksum returns the digest recorded as the commit of an archive install
+func archiveChecksum(commit string) string {
+	if strings.HasPrefix(commit, "sha256:") {
+		return commit
+	}
+	return ""
+}
+
+// diffFileList returns the regular files under dir relative to it, skipping .git and the
+// .git files of submodules
+func diffFileList(dir string) (map[string]bool, error) {
+	files := make(map[string]bool)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() || d.Name() == ".git" {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		files[rel] = true
+		return nil
+	})
+	if err != nil {
+		return nil, errors.FileError("list files", dir, err)
+	}
+	return files, nil
+}
+
+// installedMetadata renders a source's metadata the way install writes ccmd.yaml, with
+// the installed name and repository. Unparseable metadata is compared as is.
+func installedMetadata(sourceDir, name, repository string) []byte {
+	data, _ := os.ReadFile(filepath.Join(sourceDir, ConfigFileName))
+
+	sources, err := loadMetadataSources(sourceDir)
+	if err != nil {
+		return data
+	}
+	metadata, err := sources.decode()
+	if err != nil {
+		return data
+	}
+	metadata.Name = name
+	metadata.Repository = repository
+
+	rendered, err := yaml.Marshal(metadata)
+	if err != nil {
+		return data
+	}
+	return rendered
+}
@@ -0,0 +1,102 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	from := []byte("one\ntwo\nthree\nfour\nfive\nsix\nseven\neight\nnine\nten\n")
+	to := []byte("one\n2\nthree\nfour\nfive\nsix\nseven\neight\nnine\nten\neleven\n")
+
+	diff, added, removed := unifiedDiff("a/index.md", "b/index.md", from, to)
+	assert.Equal(t, 2, added)
+	assert.Equal(t, 1, removed)
+	assert.Equal(t, `--- a/index.md
++++ b/index.md
+@@ -1,5 +1,5 @@
+ one
+-two
++2
+ three
+ four
+ five
+@@ -8,3 +8,4 @@
+ eight
+ nine
+ ten
++eleven
+`, diff)
+
+	diff, _, _ = unifiedDiff("a/x", "b/x", from, from)
+	assert.Empty(t, diff)
+
+	diff, added, removed = unifiedDiff("/dev/null", "b/new.md", nil, []byte("hello\n"))
+	assert.Equal(t, "--- /dev/null\n+++ b/new.md\n@@ -0,0 +1,1 @@\n+hello\n", diff)
+	assert.Equal(t, 1, added)
+	assert.Equal(t, 0, removed)
+
+	diff, _, _ = unifiedDiff("a/logo.png", "b/logo.png", []byte("\x00a"), []byte("\x00b"))
+	assert.Equal(t, "Binary files a/logo.png and b/logo.png differ\n", diff)
+}
+
+func TestDiff(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	writeConfig(t, []string{})
+
+	data := buildTarGz(t, map[string]string{
+		"ccmd.yaml":     archiveMetadata,
+		"index.md":      "Say hello\nPolitely\n",
+		"docs/notes.md": "notes\n",
+	})
+	require.NoError(t, os.WriteFile("hello.tgz", data, 0644))
+
+	name, _, err := Install(context.Background(), InstallOptions{Repository: "hello.tgz"})
+	require.NoError(t, err)
+
+	report, err := Diff(context.Background(), DiffOptions{Name: name})
+	require.NoError(t, err)
+	assert.False(t, report.Modified(), "a fresh install matches its source: %+v", report.Files)
+	assert.True(t, strings.HasPrefix(report.Ref, "sha256:"))
+
+	commandDir := filepath.Join(".claude", "commands", name)
+	require.NoError(t, os.WriteFile(filepath.Join(commandDir, "index.md"), []byte("Say hello\nLoudly\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(commandDir, "local.md"), []byte("mine\n"), 0644))
+	require.NoError(t, os.Remove(filepath.Join(commandDir, "docs", "notes.md")))
+
+	report, err = Diff(context.Background(), DiffOptions{Name: name})
+	require.NoError(t, err)
+	require.Len(t, report.Files, 3)
+
+	assert.Equal(t, FileDiff{Path: "docs/notes.md", Status: DiffRemoved, Removed: 1,
+		Patch: "--- a/docs/notes.md\n+++ /dev/null\n@@ -1,1 +0,0 @@\n-notes\n"}, report.Files[0])
+	assert.Equal(t, "index.md", report.Files[1].Path)
+	assert.Equal(t, DiffModified, report.Files[1].Status)
+	assert.Contains(t, report.Files[1].Patch, "-Politely\n+Loudly\n")
+	assert.Equal(t, "local.md", report.Files[2].Path)
+	assert.Equal(t, DiffAdded, report.Files[2].Status)
+
+	_, err = Diff(context.Background(), DiffOptions{Name: name, Version: "v2.0.0"})
+	assert.ErrorIs(t, err, errors.ErrInvalidInput)
+
+	_, err = Diff(context.Background(), DiffOptions{Name: "missing"})
+	assert.ErrorIs(t, err, errors.ErrNotFound)
+}
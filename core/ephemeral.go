@@ -0,0 +1,109 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"fmt"
+
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+// recordEphemeral marks the lock entry of a command or plugin installed with --no-save,
+// and clears the mark once an install saves it to ccmd.yaml
+func recordEphemeral(projectRoot, name string, isPlugin, ephemeral bool) error {
+	lockPath := lockFilePath(projectRoot)
+	lockFile, err := ReadLockFile(lockPath)
+	if err != nil {
+		return err
+	}
+
+	if isPlugin {
+		plugin, ok := lockFile.Plugins[name]
+		if !ok || plugin.Ephemeral == ephemeral {
+			return nil
+		}
+		plugin.Ephemeral = ephemeral
+	} else {
+		cmd, ok := lockFile.Commands[name]
+		if !ok || cmd.Ephemeral == ephemeral {
+			return nil
+		}
+		cmd.Ephemeral = ephemeral
+	}
+
+	return WriteLockFile(lockPath, lockFile)
+}
+
+// listedInConfig reports whether ccmd.yaml lists a repository, or a side-by-side instance
+// of it, among its commands, plugins or profiles. Installs with --no-save of a listed
+// repository are not ephemeral.
+func listedInConfig(projectRoot, source, instance string) bool {
+	if !ProjectConfigExists(projectRoot) {
+		return false
+	}
+	cfg, err := LoadProjectConfig(projectRoot)
+	if err != nil {
+		return false
+	}
+
+	key := instanceKey(ExtractRepoPath(source), instance)
+	for _, spec := range append(append([]string{}, cfg.Commands...), cfg.Plugins...) {
+		if specKey(spec) == key {
+			return true
+		}
+	}
+	return cfg.inAnyProfile(key)
+}
+
+// AdoptEphemeral records an ephemeral command or plugin in ccmd.yaml at its locked
+// version, as an install without --no-save would have, and clears its ephemeral mark
+func AdoptEphemeral(projectPath, name string) error {
+	projectRoot, err := findProjectRootFrom(projectPath)
+	if err != nil {
+		return err
+	}
+	lockFile, err := ReadLockFile(lockFilePath(projectRoot))
+	if err != nil {
+		return err
+	}
+
+	if plugin, ok := lockFile.Plugins[name]; ok && plugin.Ephemeral {
+		repo, version := adoptedSpec(projectRoot, plugin.Source, plugin.Resolved)
+		if err := addPluginToConfig(projectRoot, name, repo, version); err != nil {
+			return err
+		}
+		return recordEphemeral(projectRoot, name, true, false)
+	}
+
+	cmd, ok := lockFile.Commands[name]
+	if !ok || !cmd.Ephemeral {
+		return errors.NotFound(fmt.Sprintf("ephemeral command %q", name))
+	}
+	repo, version := adoptedSpec(projectRoot, cmd.Source, cmd.Resolved)
+	if cmd.Instance {
+		err = addToProfile(projectRoot, "", formatCommandSpec(repo, version, name))
+	} else {
+		err = addToConfig(projectRoot, name, repo, version)
+	}
+	if err != nil {
+		return err
+	}
+	return recordEphemeral(projectRoot, name, false, false)
+}
+
+// adoptedSpec returns the repository and version ccmd.yaml records for a locked entry.
+// Semver tags are saved with the configured save strategy.
+func adoptedSpec(projectRoot, source, resolved string) (repo, version string) {
+	if isDownloadSource(source) {
+		return source, ""
+	}
+	_, ref := ParseRepositorySpec(resolved)
+	return configSource(projectRoot, source), constraintFor(defaultSaveStrategy(projectRoot), ref)
+}
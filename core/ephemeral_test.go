@@ -0,0 +1,95 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gifflet/ccmd/pkg/config"
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+func TestInstallNoSave(t *testing.T) {
+	repo, _ := writeCommandRepo(t)
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	ctx := context.Background()
+	t.Setenv(config.ConfigEnv, filepath.Join(t.TempDir(), "config.yaml"))
+	writeConfig(t, []string{})
+
+	_, _, err := Install(ctx, InstallOptions{Repository: repo + "@v1.0.0", NoSave: true, Profile: "docs"})
+	assert.ErrorIs(t, err, errors.ErrInvalidInput)
+
+	_, _, err = Install(ctx, InstallOptions{Repository: repo + "@v1.0.0", NoSave: true})
+	require.NoError(t, err)
+	data, err := os.ReadFile(ConfigFileName)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), repo)
+	assert.True(t, readLockFile(t).Commands["tool"].Ephemeral)
+
+	cmd, err := GetCommandInfo("tool", ".")
+	require.NoError(t, err)
+	assert.True(t, cmd.Ephemeral)
+
+	// Ephemeral commands are not drift, and sync leaves them alone unless asked
+	drift, err := CheckLockDrift(".", "")
+	require.NoError(t, err)
+	assert.Empty(t, drift)
+
+	analysis, err := AnalyzeSync(".", "")
+	require.NoError(t, err)
+	assert.Empty(t, analysis.ToRemove)
+	assert.Equal(t, []string{"tool"}, analysis.Ephemeral)
+	assert.True(t, analysis.InSync)
+
+	result, err := Sync(ctx, SyncOptions{ProjectPath: "."})
+	require.NoError(t, err)
+	assert.Empty(t, result.Removed)
+	assert.DirExists(t, filepath.Join(".claude", "commands", "tool"))
+
+	// Adopting records the locked version with the save strategy
+	result, err = Sync(ctx, SyncOptions{ProjectPath: ".", AdoptEphemeral: true})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tool"}, result.Adopted)
+	data, err = os.ReadFile(ConfigFileName)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), repo+"@^1.0.0")
+	assert.False(t, readLockFile(t).Commands["tool"].Ephemeral)
+
+	// Reinstalling a listed command with --no-save does not make it ephemeral
+	_, _, err = Install(ctx, InstallOptions{Repository: repo + "@v1.0.0", NoSave: true, Force: true})
+	require.NoError(t, err)
+	assert.False(t, readLockFile(t).Commands["tool"].Ephemeral)
+}
+
+func TestPruneEphemeral(t *testing.T) {
+	repo, _ := writeCommandRepo(t)
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	ctx := context.Background()
+	t.Setenv(config.ConfigEnv, filepath.Join(t.TempDir(), "config.yaml"))
+	writeConfig(t, []string{})
+
+	_, _, err := Install(ctx, InstallOptions{Repository: repo + "@v1.0.0", NoSave: true})
+	require.NoError(t, err)
+	assert.ErrorIs(t, AdoptEphemeral(".", "missing"), errors.ErrNotFound)
+
+	result, err := Sync(ctx, SyncOptions{ProjectPath: ".", PruneEphemeral: true})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tool"}, result.Removed)
+	assert.NoDirExists(t, filepath.Join(".claude", "commands", "tool"))
+	assert.NotContains(t, readLockFile(t).Commands, "tool")
+}
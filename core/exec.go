@@ -0,0 +1,219 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+// DefaultExecTimeout is how long Exec lets a script run without ExecOptions.Timeout
+const DefaultExecTimeout = 5 * time.Minute
+
+// execEnvAllowlist holds the environment variables a script inherits from ccmd. Tokens
+// and other secrets are passed only when named with ExecOptions.Env.
+var execEnvAllowlist = []string{
+	"PATH", "HOME", "USER", "LOGNAME", "SHELL", "TERM", "LANG", "LC_ALL", "LC_CTYPE", "TZ", "TMPDIR",
+	// Windows
+	"SYSTEMROOT", "COMSPEC", "PATHEXT", "TEMP", "TMP", "USERPROFILE", "APPDATA", "LOCALAPPDATA",
+}
+
+// ExecOptions represents options for running the script of an installed command
+type ExecOptions struct {
+	ProjectPath string
+	Name        string
+	Args        []string
+	// Env passes more variables to the script: NAME passes the variable of the same name
+	// through, NAME=value sets it
+	Env []string
+	// Timeout stops the script after this long; 0 means DefaultExecTimeout and a
+	// negative value never stops it
+	Timeout time.Duration
+	// Sandbox runs the script in the sandbox even when sandbox.enabled is off
+	Sandbox bool
+
+	Stdin          io.Reader
+	Stdout, Stderr io.Writer
+}
+
+// ExecExitError reports a script that exited with a non-zero status
+type ExecExitError struct {
+	Name string
+	Code int
+}
+
+// Error names the command and the exit status
+func (e *ExecExitError) Error() string {
+	return fmt.Sprintf("script of %s exited with status %d", e.Name, e.Code)
+}
+
+// ExitCode returns the exit status of the script, so ccmd can exit with it
+func (e *ExecExitError) ExitCode() int {
+	return e.Code
+}
+
+// Exec runs the script named by the exec field of an installed command's ccmd.yaml,
+// relative to the command directory, with Args. The script runs in the project root
+// with the variables of execEnvAllowlist and ExecOptions.Env only, plus CCMD_COMMAND,
+// CCMD_COMMAND_DIR, CCMD_COMMAND_VERSION and CCMD_PROJECT_ROOT, and is stopped after
+// the timeout. With sandbox.enabled or ExecOptions.Sandbox, it runs in the sandbox.
+func Exec(ctx context.Context, opts ExecOptions) error {
+	projectRoot, err := findProjectRootFrom(opts.ProjectPath)
+	if err != nil {
+		return err
+	}
+
+	lockFile, err := ReadLockFile(lockFilePath(projectRoot))
+	if err != nil {
+		return err
+	}
+	if _, ok := lockFile.Commands[opts.Name]; !ok {
+		return errors.NotFound(fmt.Sprintf("command %q", opts.Name))
+	}
+
+	commandDir := installedCommandDir(projectRoot, opts.Name)
+	metadata, err := readCommandMetadata(filepath.Join(commandDir, ConfigFileName))
+	if err != nil {
+		return err
+	}
+	if metadata.Exec == "" {
+		return errors.InvalidInput(fmt.Sprintf("command %q has no exec script in its ccmd.yaml", opts.Name))
+	}
+	script, err := execScriptPath(commandDir, metadata.Exec)
+	if err != nil {
+		return err
+	}
+
+	env, err := execEnv(opts.Env)
+	if err != nil {
+		return err
+	}
+	absRoot, _ := filepath.Abs(projectRoot)
+	env = append(env,
+		"CCMD_COMMAND="+opts.Name,
+		"CCMD_COMMAND_DIR="+filepath.Dir(script),
+		"CCMD_COMMAND_VERSION="+metadata.Version,
+		"CCMD_PROJECT_ROOT="+absRoot,
+	)
+
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = DefaultExecTimeout
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	name, args := script, opts.Args
+	settings, err := sandboxSettings(projectRoot)
+	if err != nil {
+		return err
+	}
+	if settings.Enabled || opts.Sandbox {
+		box, err := newSandbox(ctx, projectRoot, settings)
+		if err != nil {
+			return err
+		}
+		defer box.cleanup()
+		name, args = box.command(script, opts.Args)
+		env = box.env(env)
+	}
+
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = absRoot
+	cmd.Env = env
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = opts.Stdin, opts.Stdout, opts.Stderr
+	// Give the script a moment to exit on its own once it is interrupted
+	cmd.WaitDelay = 5 * time.Second
+
+	err = cmd.Run()
+	switch {
+	case err == nil:
+		return nil
+	case stderrors.Is(ctx.Err(), context.DeadlineExceeded):
+		return fmt.Errorf("script of %s timed out after %s", opts.Name, timeout)
+	case ctx.Err() != nil:
+		return ctx.Err()
+	}
+	var exitErr *exec.ExitError
+	if stderrors.As(err, &exitErr) {
+		if exitErr.ExitCode() < 0 {
+			// Killed by a signal, such as the one sent when a CPU limit is reached
+			return fmt.Errorf("script of %s was terminated: %s", opts.Name, exitErr)
+		}
+		return &ExecExitError{Name: opts.Name, Code: exitErr.ExitCode()}
+	}
+	if stderrors.Is(err, os.ErrPermission) {
+		return errors.FileError("run", script, fmt.Errorf("%w; the script must be executable (chmod +x)", err))
+	}
+	return errors.FileError("run", script, err)
+}
+
+// execScriptPath resolves the exec field of a command against its directory. The script
+// must be a file inside the directory.
+func execScriptPath(commandDir, script string) (string, error) {
+	if err := validateExecPath(script); err != nil {
+		return "", err
+	}
+	abs, err := filepath.Abs(filepath.Join(commandDir, filepath.FromSlash(script)))
+	if err != nil {
+		return "", errors.FileError("resolve", script, err)
+	}
+	if !fileExists(abs) {
+		return "", errors.NotFound(fmt.Sprintf("exec script %s", script))
+	}
+	return abs, nil
+}
+
+// validateExecPath checks that an exec field is a relative path that stays inside the
+// command directory
+func validateExecPath(script string) error {
+	clean := filepath.ToSlash(filepath.Clean(filepath.FromSlash(script)))
+	if filepath.IsAbs(script) || strings.HasPrefix(script, "/") || clean == ".." || strings.HasPrefix(clean, "../") {
+		return errors.InvalidInput(fmt.Sprintf("exec %q must be a path inside the command directory", script))
+	}
+	return nil
+}
+
+// execEnv returns the allowlisted variables of the environment, and those named or set
+// by extra
+func execEnv(extra []string) ([]string, error) {
+	var env []string
+	for _, name := range execEnvAllowlist {
+		if value, ok := os.LookupEnv(name); ok {
+			env = append(env, name+"="+value)
+		}
+	}
+	for _, entry := range extra {
+		name, value, set := strings.Cut(entry, "=")
+		if name == "" || strings.ContainsAny(name, " \t") {
+			return nil, errors.InvalidInput(fmt.Sprintf("invalid environment variable %q (expected NAME or NAME=value)", entry))
+		}
+		if !set {
+			var ok bool
+			if value, ok = os.LookupEnv(name); !ok {
+				continue
+			}
+		}
+		env = append(env, name+"="+value)
+	}
+	return env, nil
+}
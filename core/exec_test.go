@@ -0,0 +1,119 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"bytes"
+	"context"
+	stderrors "errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+// writeExecCommand installs a command "tool" whose ccmd.yaml runs script with exec
+func writeExecCommand(t *testing.T, exec, script string) {
+	writeConfig(t, []string{"acme/tool@v1.0.0"})
+	lockFile := createBasicLockFile()
+	lockFile.Commands["tool"] = createTestLockCommand("tool", "v1.0.0", "https://github.com/acme/tool.git")
+	writeLockFile(t, lockFile)
+
+	dir := filepath.Join(".claude", "commands", "tool")
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "bin"), 0755))
+	metadata := "name: tool\nversion: 1.0.0\ndescription: Tool\nauthor: Acme\nrepository: acme/tool\nentry: index.md\nexec: " + exec + "\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ConfigFileName), []byte(metadata), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "index.md"), []byte("# tool\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bin", "run.sh"), []byte(script), 0755))
+}
+
+func TestExec(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell scripts")
+	}
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	writeExecCommand(t, "bin/run.sh", `#!/bin/sh
+echo "args: $*"
+echo "cwd: $(pwd)"
+echo "command: $CCMD_COMMAND $CCMD_COMMAND_VERSION"
+echo "secret: ${EXEC_SECRET:-unset}"
+echo "mode: ${EXEC_MODE:-unset}"
+exit ${EXEC_STATUS:-0}
+`)
+	t.Setenv("EXEC_SECRET", "s3cr3t")
+	root, err := os.Getwd()
+	require.NoError(t, err)
+	root, err = filepath.EvalSymlinks(root)
+	require.NoError(t, err)
+
+	var stdout bytes.Buffer
+	require.NoError(t, Exec(ctx, ExecOptions{ProjectPath: ".", Name: "tool", Args: []string{"a", "b c"}, Stdout: &stdout}))
+	assert.Contains(t, stdout.String(), "args: a b c\n")
+	assert.Contains(t, stdout.String(), "cwd: "+root+"\n")
+	assert.Contains(t, stdout.String(), "command: tool 1.0.0\n")
+	assert.Contains(t, stdout.String(), "secret: unset\n")
+
+	// Variables are passed only when named
+	stdout.Reset()
+	require.NoError(t, Exec(ctx, ExecOptions{ProjectPath: ".", Name: "tool", Env: []string{"EXEC_SECRET", "EXEC_MODE=ci"}, Stdout: &stdout}))
+	assert.Contains(t, stdout.String(), "secret: s3cr3t\n")
+	assert.Contains(t, stdout.String(), "mode: ci\n")
+
+	err = Exec(ctx, ExecOptions{ProjectPath: ".", Name: "tool", Env: []string{"EXEC_STATUS=3"}, Stdout: &stdout})
+	var exitErr *ExecExitError
+	require.True(t, stderrors.As(err, &exitErr), err)
+	assert.Equal(t, 3, exitErr.ExitCode())
+
+	err = Exec(ctx, ExecOptions{ProjectPath: ".", Name: "tool", Env: []string{"=x"}})
+	assert.ErrorIs(t, err, errors.ErrInvalidInput)
+
+	err = Exec(ctx, ExecOptions{ProjectPath: ".", Name: "missing"})
+	assert.ErrorIs(t, err, errors.ErrNotFound)
+}
+
+func TestExecTimeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell scripts")
+	}
+	cleanup := setupTestDir(t)
+	defer cleanup()
+
+	writeExecCommand(t, "bin/run.sh", "#!/bin/sh\nexec sleep 10\n")
+	start := time.Now()
+	err := Exec(context.Background(), ExecOptions{ProjectPath: ".", Name: "tool", Timeout: 200 * time.Millisecond})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "timed out after 200ms")
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestExecPathOutsideCommand(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+
+	writeExecCommand(t, "../other/run.sh", "#!/bin/sh\n")
+	err := Exec(context.Background(), ExecOptions{ProjectPath: ".", Name: "tool"})
+	assert.ErrorIs(t, err, errors.ErrInvalidInput)
+
+	for _, script := range []string{"/bin/sh", "..", "bin/../../x"} {
+		assert.Error(t, validateExecPath(script), script)
+	}
+	for _, script := range []string{"run.sh", "bin/run.sh", "./bin/run.sh"} {
+		assert.NoError(t, validateExecPath(script), script)
+	}
+}
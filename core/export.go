@@ -0,0 +1,122 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+// ExportOptions represents options for exporting installed commands to other tools
+type ExportOptions struct {
+	ProjectPath string
+	Name        string   // Command name (empty for all)
+	Targets     []string // Target specs written as type or type:path
+	Save        bool     // Add the targets to ccmd.yaml so install, update and sync keep them current
+}
+
+// ExportResult lists the files written by Export
+type ExportResult struct {
+	Files  []string // project-relative paths of the exported files
+	Failed []SyncError
+}
+
+// Export writes the standalone files of installed commands in the given targets, e.g.
+// Cursor rules, without changing the project's configured targets unless opts.Save is set
+func Export(opts ExportOptions) (*ExportResult, error) {
+	if len(opts.Targets) == 0 {
+		return nil, errors.InvalidInput(fmt.Sprintf("at least one target is required (valid: %s)", strings.Join(TargetTypes(), ", ")))
+	}
+
+	targets := make([]Target, 0, len(opts.Targets))
+	for _, spec := range opts.Targets {
+		target, err := ParseTarget(spec)
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, target)
+	}
+
+	projectRoot, err := findProjectRootFrom(opts.ProjectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	names, err := installedCommandNames(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Name != "" {
+		if !slices.Contains(names, opts.Name) {
+			return nil, errors.NotFound(fmt.Sprintf("command %q", opts.Name))
+		}
+		names = []string{opts.Name}
+	}
+
+	result := &ExportResult{Files: []string{}, Failed: []SyncError{}}
+	for _, name := range names {
+		if _, _, err := regenerateStandaloneDoc(projectRoot, name, targets, map[string]*LockStandalone{}, discardEdits); err != nil {
+			result.Failed = append(result.Failed, SyncError{Command: name, Operation: "export", Error: err})
+			continue
+		}
+		for _, target := range targets {
+			rel, err := filepath.Rel(projectRoot, target.file(projectRoot, name))
+			if err != nil {
+				rel = target.file(projectRoot, name)
+			}
+			result.Files = append(result.Files, filepath.ToSlash(rel))
+		}
+	}
+
+	if opts.Save {
+		if err := saveTargets(projectRoot, targets); err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
+
+// saveTargets adds targets to ccmd.yaml. Targets in ccmd.yaml replace the targets
+// setting, so the targets in effect are recorded as well.
+func saveTargets(projectRoot string, targets []Target) error {
+	current, err := projectTargets(projectRoot)
+	if err != nil {
+		return err
+	}
+
+	config := &ProjectConfig{}
+	if ProjectConfigExists(projectRoot) {
+		if config, err = LoadProjectConfig(projectRoot); err != nil {
+			return err
+		}
+	}
+
+	merged := append([]Target{}, current...)
+	for _, target := range targets {
+		found := false
+		for _, existing := range merged {
+			if existing.dir() == target.dir() {
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged = append(merged, target)
+		}
+	}
+	config.Targets = merged
+
+	return SaveProjectConfig(projectRoot, config)
+}
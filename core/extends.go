@@ -0,0 +1,334 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/gifflet/ccmd/pkg/config"
+	"github.com/gifflet/ccmd/pkg/errors"
+	"github.com/gifflet/ccmd/pkg/output"
+)
+
+// baseCacheDir is the directory under the configured cache_dir holding fetched base
+// configurations
+const baseCacheDir = "bases"
+
+// baseRef is the parsed extends value of a ccmd.yaml: a file in a git repository at an
+// optional version, or a local file
+type baseRef struct {
+	Repo    string // repository spec, empty for a local file
+	File    string // path of the file in the repository, or the local path
+	Version string // tag, branch or commit; empty for the default branch
+}
+
+// parseBaseRef parses an extends value. Remote bases are written as
+// host.tld/owner/repo/path/ccmd.yaml@version or owner/repo/path@version; '//' separates
+// the repository from the file for other URLs, as in file:///srv/base.git//ccmd.yaml.
+// The file defaults to ccmd.yaml. Values starting with '.' or '/' are local files,
+// relative to the directory of ccmd.yaml.
+func parseBaseRef(value string) (*baseRef, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, errors.InvalidInput("extends is empty")
+	}
+	if strings.HasPrefix(value, ".") || filepath.IsAbs(value) {
+		return &baseRef{File: value}, nil
+	}
+
+	ref := &baseRef{}
+	if i := strings.LastIndex(value, "@"); i > strings.LastIndex(value, "/") {
+		value, ref.Version = value[:i], value[i+1:]
+		if ref.Version == "" {
+			return nil, errors.InvalidInput(fmt.Sprintf("missing version after '@' in extends %q", value))
+		}
+	}
+
+	scheme := 0
+	if i := strings.Index(value, "://"); i >= 0 {
+		scheme = i + len("://")
+	}
+	if i := strings.Index(value[scheme:], "//"); i >= 0 {
+		ref.Repo, ref.File = value[:scheme+i], value[scheme+i+2:]
+	} else if scheme > 0 {
+		return nil, errors.InvalidInput(fmt.Sprintf("extends %q must separate the repository from the file with '//'", value))
+	} else {
+		segments := strings.Split(value, "/")
+		n := 2
+		if strings.Contains(segments[0], ".") {
+			n = 3
+		}
+		if len(segments) < n {
+			return nil, errors.InvalidInput(fmt.Sprintf("extends %q does not name a repository", value))
+		}
+		ref.Repo, ref.File = strings.Join(segments[:n], "/"), strings.Join(segments[n:], "/")
+	}
+
+	if ref.File == "" {
+		ref.File = ConfigFileName
+	}
+	ref.File = path.Clean(ref.File)
+	if path.IsAbs(ref.File) || ref.File == ".." || strings.HasPrefix(ref.File, "../") {
+		return nil, errors.InvalidInput(fmt.Sprintf("file %q of extends is outside the repository", ref.File))
+	}
+	return ref, nil
+}
+
+// String returns the extends value the reference was parsed from, in canonical form
+func (r *baseRef) String() string {
+	if r.Repo == "" {
+		return r.File
+	}
+	spec := r.Repo + "//" + r.File
+	if r.Version != "" {
+		spec += "@" + r.Version
+	}
+	return spec
+}
+
+// cachePath returns the cached copy of a remote base, or "" without a cache_dir
+func (r *baseRef) cachePath(projectRoot string) string {
+	settings, err := config.Load(projectRoot)
+	if err != nil || settings.CacheDir == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(r.String()))
+	return filepath.Join(settings.CacheDir, baseCacheDir, hex.EncodeToString(sum[:8])+".yaml")
+}
+
+// fetch checks the repository of a remote base out at its version and returns the file.
+// The repository is resolved with the hosts of the configuration that extends the base,
+// since the merged configuration is not known yet.
+func (r *baseRef) fetch(ctx context.Context, projectRoot string, local *ProjectConfig) ([]byte, error) {
+	tempDir, removeTempDir, err := newTempDir(projectRoot, "base")
+	if err != nil {
+		return nil, err
+	}
+	defer removeTempDir()
+
+	output.PrintVerbosef("Fetching base configuration %s", r)
+	if err := newGitClient(projectRoot).Checkout(ctx, projectHostResolver(projectRoot, local).normalize(r.Repo), r.Version, tempDir, CloneOptions{}); err != nil {
+		return nil, errors.GitError("clone", err)
+	}
+	data, err := os.ReadFile(filepath.Join(tempDir, filepath.FromSlash(r.File)))
+	if err != nil {
+		return nil, errors.NotFound(fmt.Sprintf("%s in %s", r.File, r.Repo))
+	}
+
+	if cache := r.cachePath(projectRoot); cache != "" {
+		if err := os.MkdirAll(filepath.Dir(cache), 0755); err == nil {
+			_ = writeFileAtomic(cache, data, 0644)
+		}
+	}
+	return data, nil
+}
+
+// read returns the base: a local file, the cached copy of a remote base, or the remote
+// base fetched now when it is not cached yet
+func (r *baseRef) read(ctx context.Context, projectRoot, configDir string, local *ProjectConfig) ([]byte, error) {
+	if r.Repo == "" {
+		file := r.File
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(configDir, file)
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, errors.FileError("read base config", file, err)
+		}
+		return data, nil
+	}
+
+	if cache := r.cachePath(projectRoot); cache != "" {
+		if data, err := os.ReadFile(cache); err == nil {
+			return data, nil
+		}
+	}
+	return r.fetch(ctx, projectRoot, local)
+}
+
+// loadBaseConfig reads and parses the base a project configuration extends
+func loadBaseConfig(ctx context.Context, projectRoot, configDir string, local *ProjectConfig) (*ProjectConfig, error) {
+	ref, err := parseBaseRef(local.Extends)
+	if err != nil {
+		return nil, err
+	}
+	data, err := ref.read(ctx, projectRoot, configDir, local)
+	if err != nil {
+		return nil, err
+	}
+
+	var base ProjectConfig
+	if err := decodeProjectConfig(data, &base); err != nil {
+		return nil, errors.InvalidInput(fmt.Sprintf("invalid base configuration %s: %v", ref, err))
+	}
+	if base.Extends != "" {
+		return nil, errors.InvalidInput(fmt.Sprintf("base configuration %s extends %s; bases cannot extend other bases", ref, base.Extends))
+	}
+	return &base, nil
+}
+
+// refreshBaseConfig fetches the remote base of the project's ccmd.yaml again. When the
+// remote cannot be reached, the cached copy is kept with a warning.
+func refreshBaseConfig(ctx context.Context, projectRoot string) error {
+	configPath := configFilePath(projectRoot)
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil
+	}
+	var local ProjectConfig
+	if decodeProjectConfig(data, &local) != nil || local.Extends == "" {
+		return nil
+	}
+
+	ref, err := parseBaseRef(local.Extends)
+	if err != nil || ref.Repo == "" {
+		return err
+	}
+	if _, err := ref.fetch(ctx, projectRoot, &local); err != nil {
+		cache := ref.cachePath(projectRoot)
+		if cache == "" || !fileExists(cache) {
+			return err
+		}
+		output.PrintWarningf("Using the cached base configuration %s: %v", ref, err)
+		return nil
+	}
+	projectFiles.invalidate(configPath)
+	return nil
+}
+
+// mergeBase merges the base beneath the configuration: entries of the base come first,
+// unless the configuration lists the same repository, and settings the configuration
+// leaves unset are taken from the base. The base is kept so SaveProjectConfig writes
+// only the project's own entries.
+func (pc *ProjectConfig) mergeBase(base *ProjectConfig) {
+	var conditions map[string]*EntryCondition
+	keep := func(baseSpecs, specs []string) []string {
+		merged := make([]string, 0, len(baseSpecs)+len(specs))
+		local := make(map[string]bool, len(specs))
+		for _, spec := range specs {
+			local[specKey(spec)] = true
+		}
+		for _, spec := range baseSpecs {
+			key := specKey(spec)
+			if local[key] {
+				continue
+			}
+			merged = append(merged, spec)
+			if cond := base.Conditions[key]; cond != nil {
+				if conditions == nil {
+					conditions = make(map[string]*EntryCondition)
+				}
+				conditions[key] = cond
+			}
+		}
+		return append(merged, specs...)
+	}
+
+	pc.Commands = keep(base.Commands, pc.Commands)
+	pc.Plugins = keep(base.Plugins, pc.Plugins)
+	for name, specs := range base.Profiles {
+		if pc.Profiles == nil {
+			pc.Profiles = make(map[string][]string)
+		}
+		pc.Profiles[name] = keep(specs, pc.Profiles[name])
+	}
+	for key, cond := range conditions {
+		if _, ok := pc.Conditions[key]; !ok {
+			if pc.Conditions == nil {
+				pc.Conditions = make(map[string]*EntryCondition)
+			}
+			pc.Conditions[key] = cond
+		}
+	}
+
+	if pc.DefaultHost == "" {
+		pc.DefaultHost = base.DefaultHost
+	}
+	for host, settings := range base.Hosts {
+		if _, ok := pc.Hosts[host]; !ok {
+			if pc.Hosts == nil {
+				pc.Hosts = make(map[string]HostConfig)
+			}
+			pc.Hosts[host] = settings
+		}
+	}
+	if len(pc.Targets) == 0 {
+		pc.Targets = base.Targets
+	}
+	if pc.Files == nil {
+		pc.Files = base.Files
+	}
+	pc.Base = base
+}
+
+// withoutBase returns the configuration without what its base provides, as it is
+// written to ccmd.yaml
+func (pc *ProjectConfig) withoutBase() *ProjectConfig {
+	base := pc.Base
+	if base == nil {
+		return pc
+	}
+
+	local := *pc
+	local.Base = nil
+	drop := func(specs, baseSpecs []string) []string {
+		inherited := make(map[string]bool, len(baseSpecs))
+		for _, spec := range baseSpecs {
+			inherited[spec] = true
+		}
+		var kept []string
+		for _, spec := range specs {
+			if !inherited[spec] {
+				kept = append(kept, spec)
+			}
+		}
+		return kept
+	}
+
+	local.Commands = drop(pc.Commands, base.Commands)
+	local.Plugins = drop(pc.Plugins, base.Plugins)
+	if pc.Profiles != nil {
+		local.Profiles = make(map[string][]string, len(pc.Profiles))
+		for name, specs := range pc.Profiles {
+			kept := drop(specs, base.Profiles[name])
+			if _, inherited := base.Profiles[name]; inherited && len(kept) == 0 {
+				continue
+			}
+			local.Profiles[name] = kept
+		}
+	}
+
+	if local.DefaultHost == base.DefaultHost {
+		local.DefaultHost = ""
+	}
+	if pc.Hosts != nil {
+		local.Hosts = make(map[string]HostConfig, len(pc.Hosts))
+		for host, settings := range pc.Hosts {
+			if inherited, ok := base.Hosts[host]; !ok || !reflect.DeepEqual(inherited, settings) {
+				local.Hosts[host] = settings
+			}
+		}
+	}
+	if reflect.DeepEqual(local.Targets, base.Targets) {
+		local.Targets = nil
+	}
+	if reflect.DeepEqual(local.Files, base.Files) {
+		local.Files = nil
+	}
+	return &local
+}
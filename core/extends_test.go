@@ -0,0 +1,130 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBaseRef(t *testing.T) {
+	tests := []struct {
+		value string
+		want  baseRef
+	}{
+		{"github.com/acme/ccmd-base/ccmd.yaml@v2", baseRef{Repo: "github.com/acme/ccmd-base", File: "ccmd.yaml", Version: "v2"}},
+		{"acme/ccmd-base/teams/web.yaml", baseRef{Repo: "acme/ccmd-base", File: "teams/web.yaml"}},
+		{"acme/ccmd-base@main", baseRef{Repo: "acme/ccmd-base", File: "ccmd.yaml", Version: "main"}},
+		{"file:///srv/base.git//ccmd.yaml@v1.0.0", baseRef{Repo: "file:///srv/base.git", File: "ccmd.yaml", Version: "v1.0.0"}},
+		{"git@github.com:acme/base.git//base.yaml", baseRef{Repo: "git@github.com:acme/base.git", File: "base.yaml"}},
+		{"../shared/ccmd.yaml", baseRef{File: "../shared/ccmd.yaml"}},
+	}
+	for _, tt := range tests {
+		ref, err := parseBaseRef(tt.value)
+		require.NoError(t, err, tt.value)
+		assert.Equal(t, tt.want, *ref, tt.value)
+	}
+
+	for _, value := range []string{"", "acme", "acme/base@", "https://github.com/acme/base", "acme/base/../../etc/passwd"} {
+		_, err := parseBaseRef(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestLoadProjectConfigExtends(t *testing.T) {
+	dir := t.TempDir()
+	project := filepath.Join(dir, "project")
+	require.NoError(t, os.MkdirAll(project, 0755))
+	base := `commands:
+  - acme/lint
+  - acme/review@v1.0.0
+  - repo: acme/mac-only
+    os: [darwin]
+profiles:
+  qa:
+    - acme/e2e
+default_host: gitlab.com
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "base.yaml"), []byte(base), 0644))
+	local := "extends: ../base.yaml\ncommands:\n  - acme/review@v2.0.0\n  - acme/deploy\n"
+	require.NoError(t, os.WriteFile(filepath.Join(project, ConfigFileName), []byte(local), 0644))
+
+	config, err := LoadProjectConfig(project)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"acme/lint", "acme/mac-only", "acme/review@v2.0.0", "acme/deploy"}, config.Commands)
+	assert.Equal(t, []string{"acme/e2e"}, config.Profiles["qa"])
+	assert.Equal(t, "gitlab.com", config.DefaultHost)
+	require.NotNil(t, config.conditionFor("acme/mac-only"))
+
+	// Only the project's own entries are written back
+	config.Commands = append(config.Commands, "acme/docs")
+	require.NoError(t, SaveProjectConfig(project, config))
+	data, err := os.ReadFile(filepath.Join(project, ConfigFileName))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "extends: ../base.yaml\ncommands:\n    - acme/review@v2.0.0\n    - acme/deploy\n    - acme/docs\n")
+	assert.NotContains(t, string(data), "acme/lint")
+	assert.NotContains(t, string(data), "default_host")
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "base.yaml"), []byte("extends: ./other.yaml\n"), 0644))
+	_, err = LoadProjectConfig(project)
+	assert.ErrorContains(t, err, "bases cannot extend other bases")
+}
+
+func TestExtendsRemoteBase(t *testing.T) {
+	repo, _ := writeGitRepo(t)
+	t.Setenv("CCMD_CACHE_DIR", t.TempDir())
+	commit := func(commands string) {
+		require.NoError(t, os.WriteFile(filepath.Join(repo, "team.yaml"), []byte("commands:\n"+commands), 0644))
+		for _, args := range [][]string{{"add", "."}, {"commit", "--quiet", "-m", "base"}} {
+			out, err := exec.Command("git", append([]string{"-C", repo}, args...)...).CombinedOutput()
+			require.NoError(t, err, string(out))
+		}
+	}
+	commit("  - acme/lint\n")
+
+	project := t.TempDir()
+	local := "extends: file://" + repo + "//team.yaml@main\ncommands:\n  - acme/deploy\n"
+	require.NoError(t, os.WriteFile(filepath.Join(project, ConfigFileName), []byte(local), 0644))
+
+	config, err := LoadProjectConfig(project)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"acme/lint", "acme/deploy"}, config.Commands)
+
+	// The cached copy is used until the base is refreshed
+	commit("  - acme/lint\n  - acme/review\n")
+	projectFiles.invalidate(configFilePath(project))
+	config, err = LoadProjectConfig(project)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"acme/lint", "acme/deploy"}, config.Commands)
+
+	require.NoError(t, refreshBaseConfig(context.Background(), project))
+	config, err = LoadProjectConfig(project)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"acme/lint", "acme/review", "acme/deploy"}, config.Commands)
+
+	// An unreachable remote keeps the cached copy
+	require.NoError(t, os.RemoveAll(repo))
+	assert.NoError(t, refreshBaseConfig(context.Background(), project))
+}
+
+func TestCheckConfigExtends(t *testing.T) {
+	assert.Empty(t, checkConfigData(ConfigFileName, []byte("extends: acme/base@v2\n")))
+
+	problems := checkConfigData(ConfigFileName, []byte("extends: acme\n"))
+	require.Len(t, problems, 1)
+	assert.Equal(t, 1, problems[0].Line)
+	assert.Contains(t, problems[0].Message, "does not name a repository")
+}
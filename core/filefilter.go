@@ -0,0 +1,227 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/gifflet/ccmd/internal/fs"
+	"github.com/gifflet/ccmd/pkg/errors"
+	"github.com/gifflet/ccmd/pkg/telemetry"
+)
+
+// largeImageSize is the size above which images are left out of an install unless the
+// files filter includes them
+const largeImageSize = 1 << 20
+
+// defaultExcludes are left out of every install unless the files filter includes them:
+// CI configuration and tests are of no use to Claude Code
+var defaultExcludes = []string{".github/", "tests/", "test/"}
+
+// imageExtensions are the file types subject to largeImageSize
+var imageExtensions = map[string]bool{".png": true, ".jpg": true, ".jpeg": true, ".gif": true, ".webp": true}
+
+// FileFilter selects the files of a command repository that are installed. Patterns are
+// slash-separated globs relative to the repository root, as in .gitignore: a pattern
+// without a slash matches a name at any depth, a pattern with a slash is anchored to
+// the root and a trailing slash matches directories only.
+type FileFilter struct {
+	// Include limits the install to matching files; it also keeps files the default
+	// excludes would leave out
+	Include []string `yaml:"include,omitempty" json:"include,omitempty"`
+	// Exclude leaves matching files out
+	Exclude []string `yaml:"exclude,omitempty" json:"exclude,omitempty"`
+}
+
+// Validate checks that every pattern is a valid glob
+func (f *FileFilter) Validate() error {
+	if f == nil {
+		return nil
+	}
+	for _, pattern := range append(append([]string{}, f.Include...), f.Exclude...) {
+		if strings.TrimSpace(pattern) == "" {
+			return errors.InvalidInput("files: empty pattern")
+		}
+		if _, err := path.Match(strings.Trim(pattern, "/"), ""); err != nil {
+			return errors.InvalidInput(fmt.Sprintf("files: invalid pattern %q", pattern))
+		}
+	}
+	return nil
+}
+
+// keep reports whether a file, given by its slash-separated path relative to the
+// repository root, is installed. Excludes win over includes.
+func (f *FileFilter) keep(rel string, size int64) bool {
+	var include, exclude []string
+	if f != nil {
+		include, exclude = f.Include, f.Exclude
+	}
+
+	if matchesAnyPattern(exclude, rel) {
+		return false
+	}
+	if len(include) > 0 {
+		return matchesAnyPattern(include, rel)
+	}
+	if matchesAnyPattern(defaultExcludes, rel) {
+		return false
+	}
+	return !imageExtensions[strings.ToLower(path.Ext(rel))] || size <= largeImageSize
+}
+
+func matchesAnyPattern(patterns []string, rel string) bool {
+	for _, pattern := range patterns {
+		if matchPattern(pattern, rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPattern matches a file path against a filter pattern. A file also matches when
+// one of its parent directories does, so "docs" and "docs/" both cover docs/a.md.
+func matchPattern(pattern, rel string) bool {
+	dirOnly := strings.HasSuffix(pattern, "/")
+	anchored := strings.Contains(strings.TrimSuffix(pattern, "/"), "/")
+	pattern = strings.Trim(pattern, "/")
+
+	parts := strings.Split(rel, "/")
+	for i := 1; i <= len(parts); i++ {
+		if dirOnly && i == len(parts) {
+			break
+		}
+		candidate := parts[i-1]
+		if anchored {
+			candidate = strings.Join(parts[:i], "/")
+		}
+		if ok, _ := path.Match(pattern, candidate); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// copyCommandFiles copies the files of a command repository selected by its files filter
+// into dst and returns the slash-separated paths of the copied files, sorted. ccmd.yaml,
+// the entry file and the exec script are always copied; .git is copied as is but not
+// listed. skipped is the number of files the filter left out.
+func copyCommandFiles(ctx context.Context, src, dst string, metadata *ProjectConfig) (copied []string, skipped int, err error) {
+	entry := metadata.Entry
+	if entry == "" {
+		entry = defaultEntry
+	}
+	required := map[string]bool{ConfigFileName: true, filepath.ToSlash(entry): true}
+	if metadata.Exec != "" {
+		required[path.Clean(filepath.ToSlash(metadata.Exec))] = true
+	}
+
+	ctx, span := telemetry.Start(ctx, "copy")
+	var size int64
+	err = filepath.Walk(src, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		relPath, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+		rel := filepath.ToSlash(relPath)
+		dstPath := fs.LongPath(filepath.Join(dst, relPath))
+
+		if info.IsDir() {
+			if info.Name() == ".git" {
+				if err := copyDirectory(ctx, p, dstPath); err != nil {
+					return err
+				}
+				return filepath.SkipDir
+			}
+			return os.MkdirAll(dstPath, info.Mode())
+		}
+		if !info.Mode().IsRegular() {
+			// Links are not followed, they could point anywhere on the machine
+			return nil
+		}
+		if info.Name() == ".git" {
+			// The .git file of a submodule is kept like the repository's .git directory
+			return copyFile(p, dstPath, info.Mode())
+		}
+
+		if !required[rel] && !metadata.Files.keep(rel, info.Size()) {
+			skipped++
+			return nil
+		}
+		copied = append(copied, rel)
+		size += info.Size()
+		return copyFile(p, dstPath, info.Mode())
+	})
+	span.SetAttributes(telemetry.Int("files", int64(len(copied))), telemetry.Int("bytes", size))
+	span.End(err)
+	if err != nil {
+		return nil, 0, err
+	}
+	telemetry.Add(ctx, telemetry.MetricBytes, "By", size)
+
+	pruneEmptyDirs(dst)
+	sort.Strings(copied)
+	return copied, skipped, nil
+}
+
+// pruneEmptyDirs removes the directories below root that the files filter left empty
+func pruneEmptyDirs(root string) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		if entry.IsDir() && entry.Name() != ".git" {
+			dir := filepath.Join(root, entry.Name())
+			pruneEmptyDirs(dir)
+			removeIfEmpty(dir)
+		}
+	}
+}
+
+// recordInstalledFiles stores the effective file list of a filtered install in the lock
+// file, with the checksum of exactly these files
+func recordInstalledFiles(projectRoot, name string, files []string) error {
+	lockPath := lockFilePath(projectRoot)
+	lockFile, err := ReadLockFile(lockPath)
+	if err != nil {
+		return err
+	}
+	cmd, ok := lockFile.Commands[name]
+	if !ok {
+		return nil
+	}
+
+	if !slices.Contains(files, ConfigFileName) {
+		files = append(files, ConfigFileName)
+		sort.Strings(files)
+	}
+	checksum, err := checksumFiles(installedCommandDir(projectRoot, name), files)
+	if err != nil {
+		return err
+	}
+	cmd.Files = files
+	cmd.Checksum = checksum
+
+	return WriteLockFile(lockPath, lockFile)
+}
@@ -0,0 +1,112 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+func TestMatchPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"*.png", "img/logo.png", true},
+		{"*.png", "logo.png", true},
+		{"docs", "docs/guide/a.md", true},
+		{"docs/", "docs/a.md", true},
+		{"docs/", "docs", false},
+		{"docs/*.md", "docs/a.md", true},
+		{"docs/*.md", "src/docs/a.md", false},
+		{"/README.md", "README.md", true},
+		{"README.md", "sub/README.md", true},
+		{".github/", "src/index.md", false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, matchPattern(tt.pattern, tt.path), "%s ~ %s", tt.pattern, tt.path)
+	}
+}
+
+func TestFileFilterKeep(t *testing.T) {
+	var none *FileFilter
+	assert.True(t, none.keep("index.md", 10))
+	assert.False(t, none.keep(".github/workflows/ci.yml", 10))
+	assert.False(t, none.keep("tests/run.sh", 10))
+	assert.True(t, none.keep("logo.png", 1024))
+	assert.False(t, none.keep("logo.png", 2<<20))
+
+	filter := &FileFilter{Include: []string{"prompts/", "tests/fixture.md"}, Exclude: []string{"*.tmp"}}
+	assert.True(t, filter.keep("prompts/a.md", 10))
+	assert.False(t, filter.keep("prompts/a.tmp", 10))
+	assert.False(t, filter.keep("scripts/a.sh", 10))
+	assert.True(t, filter.keep("tests/fixture.md", 10), "includes override the default excludes")
+}
+
+func TestFileFilterValidate(t *testing.T) {
+	assert.NoError(t, (*FileFilter)(nil).Validate())
+	assert.NoError(t, (&FileFilter{Exclude: []string{"docs/", "*.png"}}).Validate())
+	assert.ErrorIs(t, (&FileFilter{Exclude: []string{"[a-"}}).Validate(), errors.ErrInvalidInput)
+	assert.ErrorIs(t, (&FileFilter{Include: []string{" "}}).Validate(), errors.ErrInvalidInput)
+}
+
+func TestInstallAppliesFileFilter(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	writeConfig(t, []string{})
+
+	data := buildTarGz(t, map[string]string{
+		"ccmd.yaml":           archiveMetadata + "files:\n  exclude: [\"*.log\"]\n",
+		"index.md":            "Say hello\n",
+		"lib/helper.md":       "help\n",
+		"lib/debug.log":       "noise\n",
+		".github/ci.yml":      "on: push\n",
+		"tests/hello_test.sh": "exit 0\n",
+		"logo.png":            strings.Repeat("x", 2<<20),
+	})
+	require.NoError(t, os.WriteFile("hello.tgz", data, 0644))
+
+	_, _, err := Install(context.Background(), InstallOptions{Repository: "hello.tgz"})
+	require.NoError(t, err)
+
+	dir := filepath.Join(".claude", "commands", "hello")
+	assert.FileExists(t, filepath.Join(dir, "lib", "helper.md"))
+	assert.NoFileExists(t, filepath.Join(dir, "lib", "debug.log"))
+	assert.NoFileExists(t, filepath.Join(dir, "logo.png"))
+	assert.NoDirExists(t, filepath.Join(dir, ".github"))
+	assert.NoDirExists(t, filepath.Join(dir, "tests"))
+
+	lock := readLockFile(t).Commands["hello"]
+	assert.Equal(t, []string{"ccmd.yaml", "index.md", "lib/helper.md"}, lock.Files)
+	changed, err := hasLocalChanges(".", "hello")
+	require.NoError(t, err)
+	assert.False(t, changed)
+
+	// Files outside the recorded list are not part of the checksum
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("mine"), 0644))
+	changed, err = hasLocalChanges(".", "hello")
+	require.NoError(t, err)
+	assert.False(t, changed)
+
+	require.NoError(t, os.Remove(filepath.Join(dir, "lib", "helper.md")))
+	changed, err = hasLocalChanges(".", "hello")
+	require.NoError(t, err)
+	assert.True(t, changed)
+}
@@ -0,0 +1,225 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+// FormatOptions contains options for formatting ccmd.yaml
+type FormatOptions struct {
+	ProjectPath string
+	Check       bool // report what would change without writing the file
+}
+
+// FormatResult describes the formatting of ccmd.yaml
+type FormatResult struct {
+	File    string   `json:"file"`
+	Changed bool     `json:"changed"`
+	Changes []string `json:"changes"`
+}
+
+// Format normalizes the commands, plugins and profiles of the project's ccmd.yaml:
+// entries are written the way install records them and sorted, and profiles are
+// sorted by name. Comments move with the entries they belong to. With Check the file
+// is left untouched.
+func Format(opts FormatOptions) (*FormatResult, error) {
+	projectRoot, err := findProjectRootFrom(opts.ProjectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	configPath := configFilePath(projectRoot)
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, errors.NotFound(ConfigFileName)
+		}
+		return nil, errors.FileError("read", configPath, err)
+	}
+
+	formatted, changes, err := formatConfigData(data)
+	if err != nil {
+		return nil, errors.InvalidInput(fmt.Sprintf("cannot format %s: %v", ConfigFileName, err))
+	}
+
+	result := &FormatResult{File: configPath, Changed: !bytes.Equal(data, formatted), Changes: changes}
+	if !result.Changed || opts.Check {
+		return result, nil
+	}
+
+	if err := writeFileAtomic(configPath, formatted, 0644); err != nil {
+		return nil, err
+	}
+	projectFiles.invalidate(configPath)
+	return result, nil
+}
+
+// formatConfigData formats the contents of a ccmd.yaml and describes the entries it
+// rewrote or moved. Layout-only changes, such as indentation, are not described.
+func formatConfigData(data []byte) ([]byte, []string, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, nil, err
+	}
+	if root.Kind == 0 {
+		return data, nil, nil
+	}
+
+	var changes []string
+	if doc := documentMapping(&root); doc != nil {
+		for i := 0; i+1 < len(doc.Content); i += 2 {
+			key, value := doc.Content[i], doc.Content[i+1]
+			switch key.Value {
+			case "commands", "plugins":
+				changes = append(changes, formatSpecList(value, key.Value)...)
+			case "profiles":
+				if value.Kind != yaml.MappingNode {
+					continue
+				}
+				for j := 0; j+1 < len(value.Content); j += 2 {
+					name := value.Content[j].Value
+					changes = append(changes, formatSpecList(value.Content[j+1], "profile "+name)...)
+				}
+				if sortMapping(value) {
+					changes = append(changes, "sorted profiles")
+				}
+			}
+		}
+	}
+
+	formatted, err := yaml.Marshal(&root)
+	if err != nil {
+		return nil, nil, err
+	}
+	return formatted, changes, nil
+}
+
+// formatSpecList rewrites each entry of a commands, plugins or profile list in its
+// canonical form and sorts the list
+func formatSpecList(list *yaml.Node, section string) []string {
+	if list.Kind != yaml.SequenceNode {
+		return nil
+	}
+
+	var changes []string
+	for _, item := range list.Content {
+		spec := specNode(item)
+		if spec == nil {
+			continue
+		}
+		canonical := canonicalSpec(spec.Value)
+		if canonical != spec.Value {
+			changes = append(changes, fmt.Sprintf("%s: %q is now %q", section, spec.Value, canonical))
+			spec.Value = canonical
+		}
+		spec.Style = 0
+
+		// A mapping with nothing but its repo is a plain entry
+		if item.Kind == yaml.MappingNode && len(item.Content) == 2 {
+			comments := [3]string{item.HeadComment, item.LineComment, item.FootComment}
+			*item = *spec
+			item.HeadComment = strings.TrimSpace(comments[0] + "\n" + item.HeadComment)
+			item.LineComment = strings.TrimSpace(comments[1] + " " + item.LineComment)
+			item.FootComment = strings.TrimSpace(comments[2] + "\n" + item.FootComment)
+			changes = append(changes, fmt.Sprintf("%s: %q is now a plain entry", section, canonical))
+		}
+	}
+
+	sorted := sort.SliceIsSorted(list.Content, func(i, j int) bool {
+		return specSortKey(list.Content[i]) < specSortKey(list.Content[j])
+	})
+	if !sorted {
+		sort.SliceStable(list.Content, func(i, j int) bool {
+			return specSortKey(list.Content[i]) < specSortKey(list.Content[j])
+		})
+		changes = append(changes, "sorted "+section)
+	}
+	return changes
+}
+
+// specNode returns the node holding the spec of an entry: the entry itself, or the repo
+// of an entry written as a mapping
+func specNode(item *yaml.Node) *yaml.Node {
+	switch item.Kind {
+	case yaml.ScalarNode:
+		return item
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(item.Content); i += 2 {
+			if item.Content[i].Value == "repo" && item.Content[i+1].Kind == yaml.ScalarNode {
+				return item.Content[i+1]
+			}
+		}
+	}
+	return nil
+}
+
+// specSortKey orders entries by repository, ignoring case, then by version and instance
+func specSortKey(item *yaml.Node) string {
+	spec := specNode(item)
+	if spec == nil {
+		return ""
+	}
+	return strings.ToLower(spec.Value)
+}
+
+// canonicalSpec returns a spec the way install records it: the repository as its
+// shorthand, with the case of the owner and repository kept, the version after a single
+// '@' with constraints spaced consistently, and a single space around 'as'. Specs that
+// do not parse and download URLs are only trimmed.
+func canonicalSpec(spec string) string {
+	spec = strings.TrimSpace(spec)
+	body, instance, hasInstance := strings.Cut(spec, " as ")
+	body = strings.TrimSpace(body)
+	if hasInstance {
+		instance = strings.TrimSpace(instance)
+	}
+
+	if _, problem := checkSpec(body, false); problem != "" || isDownloadSource(body) {
+		return formatCommandSpec(body, "", instance)
+	}
+
+	repo, version := ParseRepositorySpec(body)
+	if IsConstraint(version) {
+		alternatives := strings.Split(version, "||")
+		for i, alternative := range alternatives {
+			alternatives[i] = strings.Join(strings.Fields(alternative), " ")
+		}
+		version = strings.Join(alternatives, " || ")
+	}
+	projectRoot, _ := findProjectRoot()
+	return formatCommandSpec(configSource(projectRoot, repo), version, instance)
+}
+
+// sortMapping sorts the pairs of a mapping by key and reports whether they moved
+func sortMapping(mapping *yaml.Node) bool {
+	pairs := make([][2]*yaml.Node, 0, len(mapping.Content)/2)
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		pairs = append(pairs, [2]*yaml.Node{mapping.Content[i], mapping.Content[i+1]})
+	}
+	less := func(i, j int) bool { return pairs[i][0].Value < pairs[j][0].Value }
+	if sort.SliceIsSorted(pairs, less) {
+		return false
+	}
+	sort.SliceStable(pairs, less)
+	mapping.Content = mapping.Content[:0]
+	for _, pair := range pairs {
+		mapping.Content = append(mapping.Content, pair[0], pair[1])
+	}
+	return true
+}
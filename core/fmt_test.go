@@ -0,0 +1,123 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanonicalSpec(t *testing.T) {
+	for spec, want := range map[string]string{
+		"acme/tool":           "acme/tool",
+		"  Acme/Tool@v1.0.0 ": "Acme/Tool@v1.0.0",
+		"https://github.com/Acme/Tool.git@v1.0.0": "Acme/Tool@v1.0.0",
+		"gitlab.com/group/tool":                   "gitlab.com/group/tool",
+		"acme/tool@>=1.0.0    <2.0.0":             "acme/tool@>=1.0.0 <2.0.0",
+		"acme/tool@^1.0.0||^2.0.0":                "acme/tool@^1.0.0 || ^2.0.0",
+		"acme/tool@v1   as   tool-v1":             "acme/tool@v1 as tool-v1",
+		"https://example.com/tool.tar.gz":         "https://example.com/tool.tar.gz",
+		"not a spec":                              "not a spec",
+	} {
+		assert.Equal(t, want, canonicalSpec(spec), spec)
+	}
+}
+
+func TestFormatConfigData(t *testing.T) {
+	data := `# Shared commands
+commands:
+  # Deployment
+  - zeta/deploy@v2.0.0   # pinned
+  - https://github.com/Acme/Review.git@v1.0.0
+  - repo: acme/lint
+  - repo: acme/local
+    os: [linux]
+plugins:
+  - acme/b
+  - acme/a
+profiles:
+  qa:
+    - b/b
+    - a/a
+  backend:
+    - x/y
+`
+	formatted, changes, err := formatConfigData([]byte(data))
+	require.NoError(t, err)
+	assert.Equal(t, `# Shared commands
+commands:
+    - acme/lint
+    - repo: acme/local
+      os: [linux]
+    - Acme/Review@v1.0.0
+    # Deployment
+    - zeta/deploy@v2.0.0 # pinned
+plugins:
+    - acme/a
+    - acme/b
+profiles:
+    backend:
+        - x/y
+    qa:
+        - a/a
+        - b/b
+`, string(formatted))
+	assert.Equal(t, []string{
+		`commands: "https://github.com/Acme/Review.git@v1.0.0" is now "Acme/Review@v1.0.0"`,
+		`commands: "acme/lint" is now a plain entry`,
+		"sorted commands",
+		"sorted plugins",
+		"sorted profile qa",
+		"sorted profiles",
+	}, changes)
+
+	// Formatting is idempotent
+	again, changes, err := formatConfigData(formatted)
+	require.NoError(t, err)
+	assert.Equal(t, string(formatted), string(again))
+	assert.Empty(t, changes)
+
+	_, _, err = formatConfigData([]byte("commands: [unclosed"))
+	assert.Error(t, err)
+}
+
+func TestFormat(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, ConfigFileName)
+	unformatted := "commands:\n  - acme/b\n  - acme/a\n"
+	require.NoError(t, os.WriteFile(configPath, []byte(unformatted), 0644))
+
+	result, err := Format(FormatOptions{ProjectPath: dir, Check: true})
+	require.NoError(t, err)
+	assert.True(t, result.Changed)
+	assert.Equal(t, []string{"sorted commands"}, result.Changes)
+	data, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, unformatted, string(data), "--check must not write")
+
+	result, err = Format(FormatOptions{ProjectPath: dir})
+	require.NoError(t, err)
+	assert.True(t, result.Changed)
+	data, err = os.ReadFile(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, "commands:\n    - acme/a\n    - acme/b\n", string(data))
+
+	config, err := LoadProjectConfig(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"acme/a", "acme/b"}, config.Commands)
+
+	result, err = Format(FormatOptions{ProjectPath: dir, Check: true})
+	require.NoError(t, err)
+	assert.False(t, result.Changed)
+}
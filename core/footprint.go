@@ -0,0 +1,74 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+
+	"github.com/gifflet/ccmd/pkg/config"
+	"github.com/gifflet/ccmd/pkg/output"
+)
+
+// dirSize returns the total size in bytes of the regular files under dir, .git included,
+// which is what an installed command occupies on disk
+func dirSize(dir string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		size += info.Size()
+		return nil
+	})
+	return size, err
+}
+
+// warnInstallSize warns when a repository being installed is larger than the
+// size_limit_mb setting. Prompt repositories are small, so a large one usually
+// carries assets by accident.
+func warnInstallSize(projectRoot, repository, sourceDir string) {
+	settings, err := config.Load(projectRoot)
+	if err != nil || settings.SizeLimitMB <= 0 {
+		return
+	}
+
+	size, err := dirSize(sourceDir)
+	if err != nil {
+		return
+	}
+	if limit := int64(settings.SizeLimitMB) << 20; size > limit {
+		output.PrintWarningf("%s is %s, above the size limit of %s; check it for large files "+
+			"(change the limit with 'ccmd config set size_limit_mb <MB>')",
+			repository, FormatSize(size), FormatSize(limit))
+	}
+}
+
+// FormatSize renders a byte count with binary units, e.g. 1.5 KiB
+func FormatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
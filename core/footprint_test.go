@@ -0,0 +1,71 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gifflet/ccmd/pkg/output"
+)
+
+func TestDirSize(t *testing.T) {
+	dir := writeLintRepo(t, map[string]string{
+		"index.md":    "12345",
+		"docs/a.md":   "123",
+		".git/config": "12",
+	})
+
+	size, err := dirSize(dir)
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), size)
+}
+
+func TestFormatSize(t *testing.T) {
+	assert.Equal(t, "512 B", FormatSize(512))
+	assert.Equal(t, "1.5 KiB", FormatSize(1536))
+	assert.Equal(t, "50.0 MiB", FormatSize(50<<20))
+}
+
+func TestInstallRecordsFileSize(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	writeConfig(t, []string{})
+	t.Setenv("CCMD_SIZE_LIMIT_MB", "1")
+
+	data := buildTarGz(t, map[string]string{
+		"index.md":  frontMatterIndex,
+		"asset.bin": strings.Repeat("x", 2<<20),
+	})
+	require.NoError(t, os.WriteFile("hello.tgz", data, 0644))
+
+	var out bytes.Buffer
+	restore := output.SetOutput(&out, &out)
+	_, _, err := Install(context.Background(), InstallOptions{Repository: "hello.tgz"})
+	restore()
+	require.NoError(t, err)
+	assert.Contains(t, out.String(), "above the size limit of 1.0 MiB")
+
+	installed, err := dirSize(filepath.Join(".claude", "commands", "hello"))
+	require.NoError(t, err)
+	assert.Equal(t, installed, readLockFile(t).Commands["hello"].FileSize)
+
+	commands, err := List(ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, commands, 1)
+	assert.Equal(t, installed, commands[0].Size)
+}
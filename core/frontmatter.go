@@ -0,0 +1,191 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/gifflet/ccmd/internal/fs"
+	"github.com/gifflet/ccmd/pkg/errors"
+	"github.com/gifflet/ccmd/pkg/schema"
+)
+
+// defaultEntry is the entry file of a command when its metadata does not name one
+const defaultEntry = "index.md"
+
+// splitFrontMatter separates the YAML front matter at the top of a markdown document,
+// delimited by --- lines, from its body. ok is false when the document has no closed
+// front matter, in which case body is the whole document.
+func splitFrontMatter(data []byte) (front, body []byte, ok bool) {
+	data = fs.NormalizeNewlines(data)
+	if !bytes.HasPrefix(data, []byte("---\n")) {
+		return nil, data, false
+	}
+
+	rest := data[len("---\n"):]
+	offset := 0
+	for _, line := range bytes.SplitAfter(rest, []byte("\n")) {
+		if string(bytes.TrimRight(line, " \t\n")) == "---" {
+			return rest[:offset], rest[offset+len(line):], true
+		}
+		offset += len(line)
+	}
+
+	return nil, data, false
+}
+
+// metadataSources holds the metadata documents of a command repository. A repository
+// describes itself in ccmd.yaml, in the YAML front matter of its entry file, or in both.
+type metadataSources struct {
+	config      map[string]interface{} // ccmd.yaml, nil when the file does not exist
+	configData  []byte
+	frontMatter map[string]interface{} // schema keys found in the entry file's front matter
+	frontData   []byte
+	frontErr    error  // invalid front matter, only an error when there is no ccmd.yaml
+	entry       string // entry file the front matter was read from
+}
+
+// loadMetadataSources reads ccmd.yaml and the front matter of the entry file in dir.
+// Front matter keys that are not part of the metadata schema, such as the allowed-tools
+// read by Claude Code, are ignored. Only an invalid ccmd.yaml is an error.
+func loadMetadataSources(dir string) (*metadataSources, error) {
+	sources := &metadataSources{entry: defaultEntry}
+
+	configPath := filepath.Join(dir, ConfigFileName)
+	if fileExists(configPath) {
+		data, err := os.ReadFile(configPath)
+		if err != nil {
+			return nil, errors.FileError("read metadata", configPath, err)
+		}
+		config := map[string]interface{}{}
+		if err := yaml.Unmarshal(data, &config); err != nil {
+			return nil, errors.FileError("parse metadata", configPath, err)
+		}
+		sources.config, sources.configData = config, data
+		if entry, ok := config["entry"].(string); ok && entry != "" {
+			sources.entry = entry
+		}
+	}
+
+	entryPath := filepath.Join(dir, filepath.FromSlash(sources.entry))
+	data, err := os.ReadFile(entryPath)
+	if err != nil {
+		return sources, nil
+	}
+	front, _, ok := splitFrontMatter(data)
+	if !ok {
+		return sources, nil
+	}
+
+	all := map[string]interface{}{}
+	if err := yaml.Unmarshal(front, &all); err != nil {
+		sources.frontErr = errors.FileError("parse front matter", entryPath, err)
+		return sources, nil
+	}
+	sources.frontData = front
+	sources.frontMatter = map[string]interface{}{}
+	for _, key := range schema.Command().PropertyNames() {
+		if value, ok := all[key]; ok {
+			sources.frontMatter[key] = value
+		}
+	}
+
+	return sources, nil
+}
+
+// found reports whether the repository has metadata: a ccmd.yaml, or front matter that
+// names the command
+func (s *metadataSources) found() bool {
+	return s.config != nil || s.frontMatterNamesCommand()
+}
+
+func (s *metadataSources) frontMatterNamesCommand() bool {
+	_, hasName := s.frontMatter["name"]
+	_, hasVersion := s.frontMatter["version"]
+	return hasName || hasVersion
+}
+
+// merged combines both documents. Keys in ccmd.yaml take precedence over front matter,
+// and the entry defaults to the file holding the front matter when it names the command.
+func (s *metadataSources) merged() map[string]interface{} {
+	merged := make(map[string]interface{}, len(s.config)+len(s.frontMatter)+1)
+	for key, value := range s.frontMatter {
+		merged[key] = value
+	}
+	for key, value := range s.config {
+		merged[key] = value
+	}
+	if _, ok := merged["entry"]; !ok && s.frontMatterNamesCommand() {
+		merged["entry"] = s.entry
+	}
+	return merged
+}
+
+// decode converts the merged documents to a ProjectConfig without validating them
+func (s *metadataSources) decode() (*ProjectConfig, error) {
+	return decodeMetadata(s.merged())
+}
+
+func decodeMetadata(doc map[string]interface{}) (*ProjectConfig, error) {
+	data, err := yaml.Marshal(doc)
+	if err != nil {
+		return nil, errors.FileError("marshal metadata", "", err)
+	}
+
+	var metadata ProjectConfig
+	if err := yaml.Unmarshal(data, &metadata); err != nil {
+		return nil, errors.InvalidInput(fmt.Sprintf("invalid command metadata: %v", err))
+	}
+	return &metadata, nil
+}
+
+// readSourceMetadata reads the metadata of a command repository from ccmd.yaml, the
+// front matter of its entry file, or both, and validates it against the command schema
+func readSourceMetadata(dir string) (*ProjectConfig, error) {
+	sources, err := loadMetadataSources(dir)
+	if err != nil {
+		return nil, err
+	}
+	if !sources.found() {
+		if sources.frontErr != nil {
+			return nil, sources.frontErr
+		}
+		return nil, errors.NotFound(fmt.Sprintf("ccmd.yaml or %s front matter with a name not found in repository", sources.entry))
+	}
+
+	merged := sources.merged()
+	_, hasName := merged["name"]
+	_, hasVersion := merged["version"]
+	if hasName || hasVersion {
+		if violations := schema.Command().Validate(merged); len(violations) > 0 {
+			messages := make([]string, len(violations))
+			for i, v := range violations {
+				messages[i] = v.String()
+			}
+			return nil, errors.InvalidInput(fmt.Sprintf("invalid command metadata: %s", strings.Join(messages, "; ")))
+		}
+	}
+
+	metadata, err := sources.decode()
+	if err != nil {
+		return nil, err
+	}
+	if err := validateMetadata(metadata); err != nil {
+		return nil, err
+	}
+
+	return metadata, nil
+}
@@ -0,0 +1,133 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+const frontMatterIndex = `---
+name: hello
+version: 1.0.0
+description: Says hello
+author: tester
+repository: https://github.com/acme/hello
+allowed-tools: Bash(git status:*)
+---
+Say hello
+`
+
+func TestSplitFrontMatter(t *testing.T) {
+	front, body, ok := splitFrontMatter([]byte("---\r\nname: x\r\n---\r\nBody\r\n"))
+	require.True(t, ok)
+	assert.Equal(t, "name: x\n", string(front))
+	assert.Equal(t, "Body\n", string(body))
+
+	_, body, ok = splitFrontMatter([]byte("# Title\n---\n"))
+	assert.False(t, ok)
+	assert.Equal(t, "# Title\n---\n", string(body))
+
+	_, _, ok = splitFrontMatter([]byte("---\nname: x\n"))
+	assert.False(t, ok, "front matter must be closed")
+}
+
+func TestReadSourceMetadataFrontMatter(t *testing.T) {
+	dir := writeLintRepo(t, map[string]string{"index.md": frontMatterIndex})
+
+	metadata, err := readSourceMetadata(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", metadata.Name)
+	assert.Equal(t, "1.0.0", metadata.Version)
+	assert.Equal(t, "index.md", metadata.Entry)
+}
+
+func TestReadSourceMetadataPrecedence(t *testing.T) {
+	dir := writeLintRepo(t, map[string]string{
+		"ccmd.yaml": "name: hello\nversion: 2.0.0\nentry: index.md\n",
+		"index.md":  frontMatterIndex,
+	})
+
+	// ccmd.yaml wins, front matter fills in the rest
+	metadata, err := readSourceMetadata(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "2.0.0", metadata.Version)
+	assert.Equal(t, "Says hello", metadata.Description)
+	assert.Equal(t, "tester", metadata.Author)
+}
+
+func TestReadSourceMetadataErrors(t *testing.T) {
+	_, err := readSourceMetadata(writeLintRepo(t, map[string]string{
+		"index.md": "---\ndescription: Not a ccmd command\n---\nHello\n",
+	}))
+	assert.ErrorIs(t, err, errors.ErrNotFound)
+
+	_, err = readSourceMetadata(writeLintRepo(t, map[string]string{
+		"ccmd.yaml": archiveMetadata + "tags: review\ntype: script\n",
+		"index.md":  "Hello",
+	}))
+	require.ErrorIs(t, err, errors.ErrInvalidInput)
+	assert.Contains(t, err.Error(), "tags: expected array, got string")
+	assert.Contains(t, err.Error(), `type: must be one of "command", "plugin"`)
+
+	// Unparseable front matter is ignored when ccmd.yaml describes the command
+	metadata, err := readSourceMetadata(writeLintRepo(t, map[string]string{
+		"ccmd.yaml": archiveMetadata,
+		"index.md":  "---\n: [\n---\nHello\n",
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, "hello", metadata.Name)
+}
+
+func TestInstallFrontMatterOnly(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	writeConfig(t, []string{})
+
+	data := buildTarGz(t, map[string]string{"index.md": frontMatterIndex})
+	require.NoError(t, os.WriteFile("hello.tgz", data, 0644))
+
+	name, _, err := Install(context.Background(), InstallOptions{Repository: "hello.tgz"})
+	require.NoError(t, err)
+	assert.Equal(t, "hello", name)
+
+	metadata, err := readCommandMetadata(filepath.Join(".claude", "commands", "hello", "ccmd.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, "Says hello", metadata.Description)
+	assert.Equal(t, "index.md", metadata.Entry)
+	assert.Equal(t, "1.0.0", readLockFile(t).Commands["hello"].Version)
+}
+
+func TestLintFrontMatterMetadata(t *testing.T) {
+	report, err := Lint(writeLintRepo(t, map[string]string{"index.md": frontMatterIndex}))
+	require.NoError(t, err)
+	assert.Empty(t, report.Diagnostics)
+
+	report, err = Lint(writeLintRepo(t, map[string]string{
+		"index.md": "---\nname: hello\nversion: one\ntags: review\n---\nHello\n",
+	}))
+	require.NoError(t, err)
+
+	located := make(map[string]Diagnostic)
+	for _, d := range report.Diagnostics {
+		located[d.Rule] = d
+	}
+	assert.Equal(t, "index.md", located["schema"].File)
+	assert.Equal(t, 4, located["schema"].Line)
+	assert.Equal(t, "index.md", located["required-field"].File)
+	assert.NotContains(t, located, "metadata-missing")
+}
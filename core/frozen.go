@@ -0,0 +1,207 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+// LockDrift is a difference between ccmd.yaml and ccmd-lock.yaml found by a frozen install
+type LockDrift struct {
+	Repository string
+	Reason     string
+}
+
+// CheckLockDrift compares the commands and plugins of ccmd.yaml, plus those of a profile,
+// with ccmd-lock.yaml. Every entry must be locked at a version that satisfies ccmd.yaml
+// and the lock file must not hold entries ccmd.yaml no longer lists.
+func CheckLockDrift(projectPath, profile string) ([]LockDrift, error) {
+	config, err := LoadProjectConfig(projectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	commands, err := config.CommandsForProfile(profile)
+	if err != nil {
+		return nil, err
+	}
+
+	plugins := config.ActivePlugins()
+	lockPath := lockFilePath(projectPath)
+	if !fileExists(lockPath) {
+		if len(commands) == 0 && len(plugins) == 0 {
+			return nil, nil
+		}
+		return []LockDrift{{Repository: LockFileName, Reason: "not found"}}, nil
+	}
+	lockFile, err := ReadLockFile(lockPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var drift []LockDrift
+	listed := make(map[string]bool)
+	for _, spec := range commands {
+		repo, version, instance := ParseInstanceSpec(spec)
+		listed[specKey(spec)] = true
+		label := formatCommandSpec(repo, "", instance)
+		if _, cmd := lockedCommand(lockFile, repo, instance); cmd == nil {
+			drift = append(drift, LockDrift{Repository: label, Reason: "missing from " + LockFileName})
+		} else if reason := lockedVersionDrift(version, cmd.Resolved, cmd.Commit); reason != "" {
+			drift = append(drift, LockDrift{Repository: label, Reason: reason})
+		}
+	}
+	for _, spec := range plugins {
+		repo, version := ParseCommandSpec(spec)
+		listed[ExtractRepoPath(repo)] = true
+		if _, plugin := lockedPlugin(lockFile, repo); plugin == nil {
+			drift = append(drift, LockDrift{Repository: repo, Reason: "missing from " + LockFileName})
+		} else if reason := lockedVersionDrift(version, plugin.Resolved, plugin.Commit); reason != "" {
+			drift = append(drift, LockDrift{Repository: repo, Reason: reason})
+		}
+	}
+
+	// Commands of other profiles, and entries for other platforms or environments, stay
+	// locked while they are listed somewhere. Ephemeral and local-only commands are never
+	// listed.
+	for name, cmd := range lockFile.Commands {
+		key := lockKey(name, cmd)
+		if !cmd.Ephemeral && !cmd.Local() && !listed[key] && !config.inAnyProfile(key) && config.Conditions[key] == nil {
+			repo := cmd.Source
+			if cmd.Instance {
+				repo = formatCommandSpec(repo, "", name)
+			}
+			drift = append(drift, LockDrift{Repository: repo, Reason: "not listed in " + ConfigFileName})
+		}
+	}
+	for _, plugin := range lockFile.Plugins {
+		if key := ExtractRepoPath(plugin.Source); !plugin.Ephemeral && !listed[key] && config.Conditions[key] == nil {
+			drift = append(drift, LockDrift{Repository: plugin.Source, Reason: "not listed in " + ConfigFileName})
+		}
+	}
+
+	sort.Slice(drift, func(i, j int) bool { return drift[i].Repository < drift[j].Repository })
+	return drift, nil
+}
+
+// lockedVersionDrift explains why a locked entry does not match the version requested in
+// ccmd.yaml, or returns "" when it does
+func lockedVersionDrift(version, resolved, commit string) string {
+	if commit == "" || commit == "unknown" {
+		return LockFileName + " records no commit"
+	}
+	if version == "" {
+		return ""
+	}
+	version = commitPinHash(version)
+
+	_, locked := ParseRepositorySpec(resolved)
+	switch {
+	case locked == version:
+		return ""
+	case IsConstraint(version):
+		if c, err := ParseConstraint(version); err == nil {
+			if v, err := ParseSemver(locked); err == nil && c.Check(v) {
+				return ""
+			}
+		}
+	case isCommitHash(version) && strings.HasPrefix(commit, version):
+		return ""
+	default:
+		if want, err := ParseSemver(version); err == nil {
+			if got, err := ParseSemver(locked); err == nil && got.Compare(want) == 0 {
+				return ""
+			}
+		}
+	}
+
+	if locked == "" {
+		locked = "no version"
+	}
+	return fmt.Sprintf("%s requests %s, %s has %s", ConfigFileName, version, LockFileName, locked)
+}
+
+// VerifyLock returns a conflict error listing the drift between ccmd.yaml and
+// ccmd-lock.yaml, or nil when a frozen install can proceed
+func VerifyLock(projectPath, profile string) error {
+	drift, err := CheckLockDrift(projectPath, profile)
+	if err != nil || len(drift) == 0 {
+		return err
+	}
+
+	lines := make([]string, len(drift))
+	for i, d := range drift {
+		lines[i] = fmt.Sprintf("  %s: %s", d.Repository, d.Reason)
+	}
+	return errors.Conflict(fmt.Sprintf("%s and %s are out of sync:\n%s\nrun 'ccmd install' or 'ccmd sync' without --frozen to update the lock file",
+		ConfigFileName, LockFileName, strings.Join(lines, "\n")))
+}
+
+// frozenInstallOptions returns install options that reproduce a locked entry exactly
+func frozenInstallOptions(name, source, resolved, commit string) InstallOptions {
+	ref := ""
+	if !isDownloadSource(source) {
+		_, ref = ParseRepositorySpec(resolved)
+	}
+	return InstallOptions{
+		Repository: source,
+		Version:    ref,
+		Commit:     commit,
+		Rename:     name,
+		frozen:     true,
+	}
+}
+
+// lockedCommand returns the lock entry of a command repository and its name. With an
+// instance name it returns that side-by-side install instead of the regular one.
+func lockedCommand(lockFile *LockFile, repo, instance string) (string, *LockCommand) {
+	if lockFile == nil {
+		return "", nil
+	}
+	repoPath := ExtractRepoPath(repo)
+	if instance != "" {
+		if cmd, ok := lockFile.Commands[instance]; ok && cmd.Instance && ExtractRepoPath(cmd.Source) == repoPath {
+			return instance, cmd
+		}
+		return "", nil
+	}
+	for name, cmd := range lockFile.Commands {
+		if !cmd.Instance && ExtractRepoPath(cmd.Source) == repoPath {
+			return name, cmd
+		}
+	}
+	return "", nil
+}
+
+// lockKey returns the instanceKey of a lock entry
+func lockKey(name string, cmd *LockCommand) string {
+	if cmd.Instance {
+		return instanceKey(ExtractRepoPath(cmd.Source), name)
+	}
+	return ExtractRepoPath(cmd.Source)
+}
+
+// lockedPlugin returns the lock entry of a plugin repository and its name
+func lockedPlugin(lockFile *LockFile, repo string) (string, *LockPlugin) {
+	if lockFile == nil {
+		return "", nil
+	}
+	repoPath := ExtractRepoPath(repo)
+	for name, plugin := range lockFile.Plugins {
+		if ExtractRepoPath(plugin.Source) == repoPath {
+			return name, plugin
+		}
+	}
+	return "", nil
+}
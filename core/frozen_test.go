@@ -0,0 +1,162 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+func TestCheckLockDrift(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+
+	writeConfigMap(t, map[string]interface{}{
+		"commands": []string{"acme/pinned@v1.0.0", "acme/ranged@^1.2.0", "acme/missing"},
+		"profiles": map[string][]string{"docs": {"acme/writer"}},
+	})
+
+	lockFile := createBasicLockFile()
+	lockFile.Commands["pinned"] = createTestLockCommand("pinned", "1.0.0", "https://github.com/acme/pinned.git")
+	lockFile.Commands["pinned"].Resolved = "https://github.com/acme/pinned.git@v1.0.0"
+	lockFile.Commands["ranged"] = createTestLockCommand("ranged", "1.1.0", "https://github.com/acme/ranged.git")
+	lockFile.Commands["ranged"].Resolved = "https://github.com/acme/ranged.git@v1.1.0"
+	lockFile.Commands["writer"] = createTestLockCommand("writer", "1.0.0", "https://github.com/acme/writer.git")
+	lockFile.Commands["stale"] = createTestLockCommand("stale", "1.0.0", "https://github.com/acme/stale.git")
+	writeLockFile(t, lockFile)
+
+	drift, err := CheckLockDrift(".", "")
+	require.NoError(t, err)
+	assert.Equal(t, []LockDrift{
+		{Repository: "acme/missing", Reason: "missing from ccmd-lock.yaml"},
+		{Repository: "acme/ranged", Reason: "ccmd.yaml requests ^1.2.0, ccmd-lock.yaml has v1.1.0"},
+		{Repository: "https://github.com/acme/stale.git", Reason: "not listed in ccmd.yaml"},
+	}, drift)
+
+	_, err = CheckLockDrift(".", "frontend")
+	assert.ErrorIs(t, err, errors.ErrNotFound)
+}
+
+func TestLockedVersionDrift(t *testing.T) {
+	const source = "https://github.com/acme/tool.git"
+	const commit = "0123456789abcdef0123456789abcdef01234567"
+
+	tests := []struct {
+		version  string
+		resolved string
+		commit   string
+		drifted  bool
+	}{
+		{"", source + "@main", commit, false},
+		{"v1.2.0", source + "@v1.2.0", commit, false},
+		{"1.2.0", source + "@v1.2.0", commit, false},
+		{"v1.2.0", source + "@v1.3.0", commit, true},
+		{"^1.2.0", source + "@v1.4.1", commit, false},
+		{"~1.2.0", source + "@v1.3.0", commit, true},
+		{"0123456", source + "@0123456", commit, false},
+		{"main", source + "@main", "unknown", true},
+	}
+
+	for _, tt := range tests {
+		reason := lockedVersionDrift(tt.version, tt.resolved, tt.commit)
+		assert.Equal(t, tt.drifted, reason != "", "%s against %s: %s", tt.version, tt.resolved, reason)
+	}
+}
+
+func TestSyncFrozenFailsOnDrift(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+
+	writeConfig(t, []string{"acme/tool"})
+	writeLockFile(t, createBasicLockFile())
+	before, err := os.ReadFile(LockFileName)
+	require.NoError(t, err)
+
+	_, err = Sync(context.Background(), SyncOptions{ProjectPath: ".", Frozen: true})
+	require.ErrorIs(t, err, errors.ErrConflict)
+	assert.Contains(t, err.Error(), "acme/tool: missing from ccmd-lock.yaml")
+
+	after, err := os.ReadFile(LockFileName)
+	require.NoError(t, err)
+	assert.Equal(t, before, after)
+	assert.NoDirExists(t, filepath.Join(".claude", "commands", "tool"))
+}
+
+func TestInstallProfileFrozen(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	writeConfig(t, []string{})
+
+	src := writeLintRepo(t, map[string]string{"index.md": frontMatterIndex})
+	_, err := Pack(PackOptions{Path: src, OutputDir: "dist"})
+	require.NoError(t, err)
+	_, _, err = Install(context.Background(), InstallOptions{Repository: "dist/hello-1.0.0.ccmd.tgz"})
+	require.NoError(t, err)
+
+	config, err := os.ReadFile(ConfigFileName)
+	require.NoError(t, err)
+	lock, err := os.ReadFile(LockFileName)
+	require.NoError(t, err)
+	// A fresh checkout has ccmd.yaml and the lock file but no installed commands
+	require.NoError(t, os.RemoveAll(".claude"))
+
+	require.NoError(t, InstallProfile(context.Background(), ".", ConfigInstallOptions{Frozen: true}))
+	assert.FileExists(t, filepath.Join(".claude", "commands", "hello", "index.md"))
+
+	afterConfig, err := os.ReadFile(ConfigFileName)
+	require.NoError(t, err)
+	afterLock, err := os.ReadFile(LockFileName)
+	require.NoError(t, err)
+	assert.Equal(t, string(config), string(afterConfig))
+	assert.Equal(t, string(lock), string(afterLock))
+
+	// A lock entry ccmd.yaml no longer lists is drift
+	writeConfig(t, []string{})
+	err = InstallProfile(context.Background(), ".", ConfigInstallOptions{Frozen: true})
+	require.ErrorIs(t, err, errors.ErrConflict)
+	assert.Contains(t, err.Error(), "not listed in ccmd.yaml")
+}
+
+func TestCheckLockDriftInstances(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+
+	writeConfig(t, []string{"acme/tool@v2.0.0", "acme/tool@v1.0.0 as tool-v1"})
+
+	lockFile := createBasicLockFile()
+	lockFile.Commands["tool"] = createTestLockCommand("tool", "2.0.0", "https://github.com/acme/tool.git")
+	lockFile.Commands["tool"].Resolved = "https://github.com/acme/tool.git@v2.0.0"
+	writeLockFile(t, lockFile)
+
+	drift, err := CheckLockDrift(".", "")
+	require.NoError(t, err)
+	assert.Equal(t, []LockDrift{{Repository: "acme/tool as tool-v1", Reason: "missing from ccmd-lock.yaml"}}, drift)
+
+	lockFile.Commands["tool-v1"] = createTestLockCommand("tool-v1", "1.0.0", "https://github.com/acme/tool.git")
+	lockFile.Commands["tool-v1"].Resolved = "https://github.com/acme/tool.git@v1.0.0"
+	lockFile.Commands["tool-v1"].Instance = true
+	writeLockFile(t, lockFile)
+
+	drift, err = CheckLockDrift(".", "")
+	require.NoError(t, err)
+	assert.Empty(t, drift)
+
+	writeConfig(t, []string{"acme/tool@v2.0.0"})
+	drift, err = CheckLockDrift(".", "")
+	require.NoError(t, err)
+	assert.Equal(t, []LockDrift{{Repository: "https://github.com/acme/tool.git as tool-v1", Reason: "not listed in ccmd.yaml"}}, drift)
+}
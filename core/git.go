@@ -10,11 +10,20 @@
 package core
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
 	"sync"
+
+	"github.com/gifflet/ccmd/internal/fs"
+	"github.com/gifflet/ccmd/pkg/output"
+	"github.com/gifflet/ccmd/pkg/repospec"
 )
 
 var (
@@ -60,10 +69,11 @@ func getGitPath() (string, error) {
 	return gitPath, gitPathErr
 }
 
-// isCommitHash checks if a string looks like a git commit SHA-1 hash
+// isCommitHash checks if a string looks like a git commit hash: an abbreviated or full
+// SHA-1 hash, or a full SHA-256 hash
 func isCommitHash(s string) bool {
-	// Git commit hashes are hexadecimal strings of 7-40 characters
-	if len(s) < 7 || len(s) > 40 {
+	// Git commit hashes are hexadecimal strings of 7-40 characters, or 64 for SHA-256
+	if len(s) < 7 || (len(s) > 40 && len(s) != 64) {
 		return false
 	}
 	// Check if all characters are valid hexadecimal
@@ -74,8 +84,37 @@ func isCommitHash(s string) bool {
 	return matched
 }
 
-// gitClone clones a repository to the specified destination
-func gitClone(repo, dest, version string) error {
+// partialCloneFilter fetches commits and trees only; file contents are fetched for the
+// commit checked out
+const partialCloneFilter = "blob:none"
+
+// CloneOptions tune a checkout for repositories with a long history, large assets or
+// many commands
+type CloneOptions struct {
+	// Partial clones with --filter=blob:none. Servers without filter support send the
+	// whole repository instead.
+	Partial bool
+	// SparsePaths restricts the working tree to these directories and the files at the
+	// repository root (cone mode). Without git sparse-checkout support everything is
+	// checked out.
+	SparsePaths []string
+}
+
+// cloneArgs returns the options of git clone for opts
+func (opts CloneOptions) cloneArgs() []string {
+	var args []string
+	if opts.Partial {
+		args = append(args, "--filter="+partialCloneFilter)
+	}
+	if len(opts.SparsePaths) > 0 {
+		args = append(args, "--no-checkout")
+	}
+	return args
+}
+
+// gitClone clones a repository to the specified destination. Cancelling ctx kills git
+// and returns the context error.
+func gitClone(ctx context.Context, repo, dest, version string, opts CloneOptions) error {
 	git, err := getGitPath()
 	if err != nil {
 		return err
@@ -84,24 +123,26 @@ func gitClone(repo, dest, version string) error {
 	if version != "" && isCommitHash(version) {
 		// For commit hashes, we need to clone first then checkout
 		// Clone without depth limit to access all commits
-		cmd := exec.Command(git, "clone", repo, dest)
-		output, err := cmd.CombinedOutput()
-		if err != nil {
-			return fmt.Errorf("git clone failed: %w\nOutput: %s", err, string(output))
+		args := append(append([]string{"clone"}, opts.cloneArgs()...), repo, dest)
+		if err := runPartialClone(ctx, git, repo, dest, args); err != nil {
+			return err
 		}
 
-		// Checkout the specific commit
-		checkoutCmd := exec.Command(git, "-C", dest, "checkout", version)
-		checkoutOutput, checkoutErr := checkoutCmd.CombinedOutput()
-		if checkoutErr != nil {
-			return fmt.Errorf("git checkout failed: %w\nOutput: %s", checkoutErr, string(checkoutOutput))
+		// A commit no branch or tag contains was force-pushed away or deleted upstream
+		reachable, err := commitReachable(ctx, git, dest, version, "refs/remotes", "refs/tags")
+		if err != nil {
+			return err
+		}
+		if !reachable {
+			return &CommitNotFoundError{Repository: repo, Commit: version}
 		}
 
-		return nil
+		// Checkout the specific commit
+		return gitCheckout(ctx, git, repo, dest, version, opts)
 	}
 
 	// For branches and tags, use shallow clone
-	args := []string{"clone", "--depth", "1"}
+	args := append([]string{"clone", "--depth", "1"}, opts.cloneArgs()...)
 
 	if version != "" {
 		args = append(args, "--branch", version)
@@ -109,13 +150,149 @@ func gitClone(repo, dest, version string) error {
 
 	args = append(args, repo, dest)
 
-	cmd := exec.Command(git, args...)
-	output, err := cmd.CombinedOutput()
+	if err := runPartialClone(ctx, git, repo, dest, args); err != nil {
+		return err
+	}
+	if len(opts.SparsePaths) > 0 {
+		// The clone left the working tree empty; HEAD is the branch or tag
+		return gitCheckout(ctx, git, repo, dest, "HEAD", opts)
+	}
+	return nil
+}
+
+// runPartialClone runs git clone and, when a partial clone fails because of its filter,
+// clones again without it
+func runPartialClone(ctx context.Context, git, repo, dest string, args []string) error {
+	err := runClone(ctx, git, repo, dest, args)
+	filtered := slices.Index(args, "--filter="+partialCloneFilter)
+	if err == nil || ctx.Err() != nil || filtered == -1 || !strings.Contains(strings.ToLower(err.Error()), "filter") {
+		return err
+	}
+
+	output.PrintVerbosef("Partial clone of %s failed, cloning it whole: %v", repo, err)
+	if err := clearDir(dest); err != nil {
+		return err
+	}
+	return runClone(ctx, git, repo, dest, slices.Delete(slices.Clone(args), filtered, filtered+1))
+}
+
+// gitCheckout checks out ref in the clone at dest, limited to opts.SparsePaths when git
+// supports sparse checkouts. Partial clones fetch the file contents of ref here.
+func gitCheckout(ctx context.Context, git, repo, dest, ref string, opts CloneOptions) error {
+	if len(opts.SparsePaths) > 0 {
+		args := append([]string{"-C", dest, "sparse-checkout", "set", "--cone"}, opts.SparsePaths...)
+		if out, err := exec.CommandContext(ctx, git, args...).CombinedOutput(); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			output.PrintVerbosef("Sparse checkout is not available, checking out every file: %s", strings.TrimSpace(string(out)))
+		}
+	}
 
+	checkoutCmd := exec.CommandContext(ctx, git, "-C", dest, "checkout", "--quiet", ref)
+	if opts.Partial {
+		checkoutCmd.Env = gitNetworkEnv()
+	}
+	checkoutOutput, checkoutErr := checkoutCmd.CombinedOutput()
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if checkoutErr != nil {
+		if authErr := authenticationError(repo, string(checkoutOutput)); authErr != nil {
+			return authErr
+		}
+		return fmt.Errorf("git checkout failed: %w\nOutput: %s", checkoutErr, string(checkoutOutput))
+	}
+	return nil
+}
+
+// runClone runs git clone, retrying transient failures. Files a failed attempt left in
+// dest are removed before the next one. The transfer progress goes to the function set
+// on ctx by withCloneProgress.
+func runClone(ctx context.Context, git, repo, dest string, args []string) error {
+	progress := cloneProgressFrom(ctx)
+	if progress != nil {
+		args = append([]string{args[0], "--progress"}, args[1:]...)
+	}
+
+	attempt := 0
+	return withRetry(ctx, "Clone of "+repo, func() error {
+		attempt++
+		if attempt > 1 {
+			if err := clearDir(dest); err != nil {
+				return err
+			}
+		}
+
+		cmd := exec.CommandContext(ctx, git, args...)
+		cmd.Env = gitNetworkEnv()
+		var output []byte
+		var err error
+		if progress != nil {
+			writer := &gitProgressWriter{fn: progress}
+			cmd.Stdout = writer
+			cmd.Stderr = writer
+			err = cmd.Run()
+			output = writer.output.Bytes()
+		} else {
+			output, err = cmd.CombinedOutput()
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			if authErr := authenticationError(repo, string(output)); authErr != nil {
+				return authErr
+			}
+			return fmt.Errorf("git clone failed: %w\nOutput: %s", err, string(output))
+		}
+		return nil
+	})
+}
+
+// runGitRemote runs a git command that talks to a remote, such as ls-remote or fetch,
+// retrying transient failures
+func runGitRemote(ctx context.Context, what string, args ...string) ([]byte, error) {
+	git, err := getGitPath()
 	if err != nil {
-		return fmt.Errorf("git clone failed: %w\nOutput: %s", err, string(output))
+		return nil, err
 	}
 
+	var output []byte
+	err = withRetry(ctx, what, func() error {
+		cmd := exec.CommandContext(ctx, git, args...)
+		cmd.Env = gitNetworkEnv()
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		output, err = cmd.Output()
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			if authErr := authenticationError(remoteArg(args), stderr.String()); authErr != nil {
+				return authErr
+			}
+			return fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+		}
+		return nil
+	})
+	return output, err
+}
+
+// clearDir removes the content of dir, keeping dir itself
+func clearDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if err := fs.RemoveAll(filepath.Join(dir, entry.Name())); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -136,6 +313,39 @@ func gitGetCurrentCommit(repoPath string) (string, error) {
 	return strings.TrimSpace(string(output)), nil
 }
 
+// gitVerifyCheckout returns the full commit checked out in repoPath after checking that it
+// is the commit ref points to. Annotated tags are dereferenced to the commit they tag.
+func gitVerifyCheckout(repoPath, ref string) (string, error) {
+	head, err := gitGetCurrentCommit(repoPath)
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case ref == "":
+		return head, nil
+	case isCommitHash(ref):
+		if !strings.HasPrefix(head, ref) {
+			return "", fmt.Errorf("checked out commit %s instead of %s", head, ref)
+		}
+		return head, nil
+	}
+
+	target, err := gitGetRefCommit(repoPath, ref)
+	if err != nil {
+		return "", fmt.Errorf("ref %s not found in checkout: %w", ref, err)
+	}
+	if target != head {
+		return "", fmt.Errorf("checked out commit %s, but %s points to %s", head, ref, target)
+	}
+	return head, nil
+}
+
+// isFullCommitHash reports whether s is a complete SHA-1 or SHA-256 commit hash
+func isFullCommitHash(s string) bool {
+	return (len(s) == 40 || len(s) == 64) && isCommitHash(s)
+}
+
 // gitGetRefCommit returns the commit hash for a specific ref (tag, branch or commit)
 func gitGetRefCommit(repoPath, ref string) (string, error) {
 	git, err := getGitPath()
@@ -154,38 +364,40 @@ func gitGetRefCommit(repoPath, ref string) (string, error) {
 	return strings.TrimSpace(string(output)), nil
 }
 
-// gitGetRemoteRefCommit returns the commit hash for a remote ref (tag or branch)
-func gitGetRemoteRefCommit(repoPath, ref string) (string, error) {
-	git, err := getGitPath()
+// gitListRemoteTags returns the tag names published by a remote repository
+func gitListRemoteTags(ctx context.Context, repo string) ([]string, error) {
+	output, err := runGitRemote(ctx, "Tag listing of "+repo, "ls-remote", "--tags", "--refs", repo)
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
 	if err != nil {
-		return "", err
+		return nil, fmt.Errorf("failed to list remote tags: %w", err)
 	}
 
-	// Try as tag first
-	cmd := exec.Command(git, "-C", repoPath, "ls-remote", "origin", fmt.Sprintf("refs/tags/%s", ref))
-	output, err := cmd.Output()
-
-	if err == nil && len(output) > 0 {
-		parts := strings.Fields(string(output))
-		if len(parts) > 0 {
-			return parts[0], nil
+	var tags []string
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && strings.HasPrefix(fields[1], "refs/tags/") {
+			tags = append(tags, strings.TrimPrefix(fields[1], "refs/tags/"))
 		}
 	}
 
-	// Try as branch
-	cmd = exec.Command(git, "-C", repoPath, "ls-remote", "origin", fmt.Sprintf("refs/heads/%s", ref))
-	output, err = cmd.Output()
+	return tags, nil
+}
 
+// gitCurrentBranch returns the branch checked out in a repository. Checkouts of tags
+// and commits have no branch and return an error.
+func gitCurrentBranch(repoPath string) (string, error) {
+	git, err := getGitPath()
 	if err != nil {
-		return "", fmt.Errorf("failed to get remote ref commit: %w", err)
+		return "", err
 	}
 
-	parts := strings.Fields(string(output))
-	if len(parts) > 0 {
-		return parts[0], nil
+	output, err := exec.Command(git, "-C", repoPath, "symbolic-ref", "--quiet", "--short", "HEAD").Output()
+	if err != nil {
+		return "", fmt.Errorf("HEAD is not a branch: %w", err)
 	}
-
-	return "", fmt.Errorf("ref %s not found in remote", ref)
+	return strings.TrimSpace(string(output)), nil
 }
 
 // gitGetDefaultBranch returns the default branch name of a repository
@@ -218,28 +430,20 @@ func gitGetDefaultBranch(repoPath string) (string, error) {
 	return "", fmt.Errorf("could not determine default branch")
 }
 
-// ExtractRepoPath extracts the owner/repo path from a Git URL
+// ExtractRepoPath extracts the repository path from a Git URL or shorthand: owner/repo,
+// or the full group/subgroup/project path of nested groups, followed by //command for
+// one command of a multi-command repository. It identifies a repository across URL forms.
 func ExtractRepoPath(gitURL string) string {
-	// Remove protocol
-	if idx := strings.Index(gitURL, "://"); idx != -1 {
-		gitURL = gitURL[idx+3:]
+	if IsArchiveSource(gitURL) {
+		return archiveRepoPath(gitURL)
 	}
-
-	// Remove git@ prefix
-	gitURL = strings.TrimPrefix(gitURL, "git@")
-
-	// Replace : with / for SSH URLs
-	gitURL = strings.Replace(gitURL, ":", "/", 1)
-
-	// Remove .git suffix
-	gitURL = strings.TrimSuffix(gitURL, ".git")
-
-	// Extract path after domain
-	parts := strings.Split(gitURL, "/")
-	if len(parts) >= 3 {
-		// Return owner/repo
-		return strings.Join(parts[len(parts)-2:], "/")
+	if IsMarkdownSource(gitURL) {
+		return markdownRepoPath(gitURL)
 	}
 
-	return gitURL
+	spec, err := repospec.Parse(gitURL)
+	if err != nil {
+		return strings.TrimSuffix(strings.Trim(gitURL, "/"), ".git")
+	}
+	return spec.RepoPath()
 }
@@ -10,6 +10,9 @@
 package core
 
 import (
+	"context"
+	"os/exec"
+	"path/filepath"
 	"strings"
 	"sync"
 	"testing"
@@ -30,6 +33,7 @@ func TestIsCommitHash(t *testing.T) {
 		{"valid full hash", "a76c96359914b84ed1bcdbc11df03e6313e09ecf", true},
 		{"valid hash all numbers", "1234567", true},
 		{"valid hash mixed", "abc1234", true},
+		{"valid sha256 hash", "a76c96359914b84ed1bcdbc11df03e6313e09ecfa76c96359914b84ed1bcdbc1", true},
 
 		// Invalid cases
 		{"too short", "a76c96", false},
@@ -65,6 +69,10 @@ func TestExtractRepoPath(t *testing.T) {
 		{"shorthand format", "owner/repo", "owner/repo"},
 		{"URL with subdomain", "https://git.example.com/owner/repo.git", "owner/repo"},
 		{"URL with port", "https://github.com:443/owner/repo.git", "owner/repo"},
+		{"nested group", "https://gitlab.com/org/team/repo.git", "org/team/repo"},
+		{"SSH nested group", "git@gitlab.com:org/team/repo.git", "org/team/repo"},
+		{"URL with credentials", "https://token@github.com/owner/repo.git", "owner/repo"},
+		{"shorthand with version", "owner/repo@v1.0.0", "owner/repo"},
 	}
 
 	for _, tt := range tests {
@@ -110,3 +118,56 @@ func TestGetGitPath(t *testing.T) {
 	assert.Equal(t, path, path2, "cached path should be the same")
 	assert.Equal(t, err, err2, "cached error should be the same")
 }
+
+func TestGitVerifyCheckout(t *testing.T) {
+	repo, release := writeGitRepo(t)
+	first, err := gitGetCurrentCommit(repo)
+	require.NoError(t, err)
+
+	release("v1.1.0")
+	out, err := exec.Command("git", "-C", repo, "tag", "-a", "-m", "annotated", "v1.1.0-annotated").CombinedOutput()
+	require.NoError(t, err, string(out))
+	head, err := gitGetCurrentCommit(repo)
+	require.NoError(t, err)
+
+	for _, ref := range []string{"", "v1.1.0", "v1.1.0-annotated", head[:7]} {
+		commit, err := gitVerifyCheckout(repo, ref)
+		require.NoError(t, err, ref)
+		assert.Equal(t, head, commit)
+		assert.True(t, isFullCommitHash(commit))
+	}
+
+	_, err = gitVerifyCheckout(repo, "v1.0.0")
+	assert.ErrorContains(t, err, "v1.0.0 points to "+first)
+	_, err = gitVerifyCheckout(repo, first[:7])
+	assert.Error(t, err)
+}
+
+func TestGitClonePartial(t *testing.T) {
+	repo := writeMultiCommandRepo(t)
+	ctx := context.Background()
+	opts := CloneOptions{Partial: true, SparsePaths: []string{RepositoryCommandsDir + "/review"}}
+
+	// The repository does not allow filters, so the clone gets every blob
+	dest := filepath.Join(t.TempDir(), "clone")
+	require.NoError(t, gitClone(ctx, repo, dest, "v1.0.0", opts))
+	assert.FileExists(t, filepath.Join(dest, RepositoryCommandsDir, "review", "index.md"))
+	assert.FileExists(t, filepath.Join(dest, "README.md"))
+	assert.NoDirExists(t, filepath.Join(dest, RepositoryCommandsDir, "explain"))
+
+	out, err := exec.Command("git", "-C", strings.TrimPrefix(repo, "file://"), "config", "uploadpack.allowFilter", "true").CombinedOutput()
+	require.NoError(t, err, string(out))
+	commit, err := gitGetCurrentCommit(strings.TrimPrefix(repo, "file://"))
+	require.NoError(t, err)
+
+	for _, ref := range []string{"v1.0.0", commit} {
+		dest := filepath.Join(t.TempDir(), "clone")
+		require.NoError(t, gitClone(ctx, repo, dest, ref, opts), ref)
+		filter, err := exec.Command("git", "-C", dest, "config", "remote.origin.partialclonefilter").Output()
+		require.NoError(t, err, ref)
+		assert.Equal(t, partialCloneFilter, strings.TrimSpace(string(filter)))
+		assert.FileExists(t, filepath.Join(dest, RepositoryCommandsDir, "review", "index.md"), ref)
+		assert.NoDirExists(t, filepath.Join(dest, RepositoryCommandsDir, "explain"), ref)
+		assert.Equal(t, []string{"explain", "review"}, committedCommands(dest), ref)
+	}
+}
@@ -0,0 +1,183 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/gifflet/ccmd/pkg/errors"
+	"github.com/gifflet/ccmd/pkg/output"
+	"github.com/gifflet/ccmd/pkg/repospec"
+)
+
+// Authentication mechanisms a repository host can ask for
+const (
+	AuthHTTPS = "https" // a user name and password or token over HTTPS
+	AuthSSH   = "ssh"   // an SSH key, or a known host key
+)
+
+// AuthenticationRequiredError reports a repository whose host asked for credentials.
+// git runs without terminal prompts, so the request fails instead of waiting for input.
+type AuthenticationRequiredError struct {
+	Host       string // empty when git did not name it
+	Repository string
+	Mechanism  string // AuthHTTPS or AuthSSH
+}
+
+// Error names the host and the credentials it asked for
+func (e *AuthenticationRequiredError) Error() string {
+	what := "a token or password over HTTPS"
+	if e.Mechanism == AuthSSH {
+		what = "an SSH key"
+	}
+	source := e.Repository
+	if source == "" {
+		source = e.Host
+	}
+	return fmt.Sprintf("%s requires authentication with %s", source, what)
+}
+
+// Is makes AuthenticationRequiredError match errors.ErrAuthenticationRequired
+func (e *AuthenticationRequiredError) Is(target error) bool {
+	return target == errors.ErrAuthenticationRequired
+}
+
+// Hint tells which credentials to configure for the host
+func (e *AuthenticationRequiredError) Hint() errors.Hint {
+	host := e.Host
+	if host == "" {
+		host = "<host>"
+	}
+	if e.Mechanism == AuthSSH {
+		return errors.Hint{
+			Explanation: fmt.Sprintf("%s did not accept an SSH key, or its host key is not trusted yet.", host),
+			Suggestion: fmt.Sprintf("Load a key registered with %s using 'ssh-add', and run 'ssh -T git@%s' once "+
+				"to accept its host key.", host, host),
+		}
+	}
+	return errors.Hint{
+		Explanation: fmt.Sprintf("%s asked for a user name and password; ccmd does not prompt for them.", host),
+		Suggestion: fmt.Sprintf("Store a token with 'ccmd login %s', or configure a git credential helper "+
+			"for %s.", host, host),
+	}
+}
+
+// gitAuthFailures are the messages git and ssh print when a host wants credentials
+var gitAuthFailures = []struct {
+	match     string
+	mechanism string
+}{
+	{"terminal prompts disabled", AuthHTTPS},
+	{"could not read username", AuthHTTPS},
+	{"could not read password", AuthHTTPS},
+	{"authentication failed for", AuthHTTPS},
+	{"http basic: access denied", AuthHTTPS},
+	{"permission denied (publickey", AuthSSH},
+	{"host key verification failed", AuthSSH},
+	{"no supported authentication methods available", AuthSSH},
+}
+
+var (
+	// gitAuthURL matches the URL git quotes in HTTPS credential errors
+	gitAuthURL = regexp.MustCompile(`(?i)(?:https?|ssh)://(?:[^@/\s']+@)?([^/:\s']+)`)
+	// sshAuthHost matches the host of "git@host: Permission denied (publickey)"
+	sshAuthHost = regexp.MustCompile(`(?i)[\w.-]+@([\w.-]+): permission denied`)
+)
+
+// authenticationError returns an AuthenticationRequiredError when the output of a
+// failed git command shows the host asked for credentials, or nil. repo is the remote
+// the command talked to, or "" when unknown.
+func authenticationError(repo, output string) error {
+	message := strings.ToLower(output)
+	mechanism := ""
+	for _, failure := range gitAuthFailures {
+		if strings.Contains(message, failure.match) {
+			mechanism = failure.mechanism
+			break
+		}
+	}
+	if mechanism == "" {
+		return nil
+	}
+
+	host := ""
+	if spec, err := repospec.Parse(repo); err == nil && spec.Remote() {
+		host = spec.Host
+	} else if m := gitAuthURL.FindStringSubmatch(output); m != nil {
+		host = strings.ToLower(m[1])
+	} else if m := sshAuthHost.FindStringSubmatch(output); m != nil {
+		host = strings.ToLower(m[1])
+	}
+	return &AuthenticationRequiredError{Host: host, Repository: repo, Mechanism: mechanism}
+}
+
+// remoteArg returns the first argument of a git command naming a remote repository by
+// URL, or ""
+func remoteArg(args []string) string {
+	for _, arg := range args {
+		spec, err := repospec.Parse(arg)
+		if err == nil && (spec.Form == repospec.FormURL || spec.Form == repospec.FormSCP) && spec.Remote() {
+			return arg
+		}
+	}
+	return ""
+}
+
+// gitPromptEnv keeps git from asking for credentials on the terminal, where the prompt
+// would hang behind a spinner or in CI. A GIT_TERMINAL_PROMPT set by the user is kept.
+// Outside an interactive terminal ssh runs in batch mode as well, unless the user
+// configured the ssh command.
+func gitPromptEnv(env []string) []string {
+	if _, set := os.LookupEnv("GIT_TERMINAL_PROMPT"); !set {
+		env = append(env, "GIT_TERMINAL_PROMPT=0")
+	}
+	if _, set := os.LookupEnv("GCM_INTERACTIVE"); !set {
+		// Git Credential Manager opens its own prompts otherwise
+		env = append(env, "GCM_INTERACTIVE=never")
+	}
+	if !interactiveSession() && !customSSHCommand() {
+		env = append(env, "GIT_SSH_COMMAND=ssh -o BatchMode=yes")
+	}
+	return env
+}
+
+// interactiveSession reports whether a user at a terminal could answer a prompt
+func interactiveSession() bool {
+	if os.Getenv("CI") != "" {
+		return false
+	}
+	return output.IsTerminal(os.Stdin)
+}
+
+var (
+	sshCommandOnce sync.Once
+	sshCommandSet  bool
+)
+
+// customSSHCommand reports whether the environment or git config choose the ssh command
+func customSSHCommand() bool {
+	if os.Getenv("GIT_SSH_COMMAND") != "" || os.Getenv("GIT_SSH") != "" {
+		return true
+	}
+	sshCommandOnce.Do(func() {
+		git, err := getGitPath()
+		if err != nil {
+			return
+		}
+		out, err := exec.Command(git, "config", "--get", "core.sshCommand").Output()
+		sshCommandSet = err == nil && strings.TrimSpace(string(out)) != ""
+	})
+	return sshCommandSet
+}
@@ -0,0 +1,93 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"context"
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gifflet/ccmd/pkg/config"
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+func TestAuthenticationError(t *testing.T) {
+	tests := []struct {
+		repo, output    string
+		host, mechanism string
+	}{
+		{"https://github.com/acme/private.git",
+			"fatal: could not read Username for 'https://github.com': terminal prompts disabled", "github.com", AuthHTTPS},
+		{"", "fatal: Authentication failed for 'https://GitLab.com/acme/private.git/'", "gitlab.com", AuthHTTPS},
+		{"git@github.com:acme/private.git", "git@github.com: Permission denied (publickey).", "github.com", AuthSSH},
+		{"", "git@git.corp.com: Permission denied (publickey).", "git.corp.com", AuthSSH},
+		{"", "Host key verification failed.\nfatal: Could not read from remote repository.", "", AuthSSH},
+	}
+	for _, tt := range tests {
+		err := authenticationError(tt.repo, tt.output)
+		var auth *AuthenticationRequiredError
+		require.True(t, stderrors.As(err, &auth), tt.output)
+		assert.Equal(t, tt.host, auth.Host, tt.output)
+		assert.Equal(t, tt.mechanism, auth.Mechanism, tt.output)
+	}
+
+	assert.Nil(t, authenticationError("acme/tool", "fatal: repository 'https://github.com/acme/tool/' not found"))
+
+	// The CLI hint names the mechanism to configure, also through GitError
+	err := errors.GitError("clone", authenticationError("https://github.com/acme/private.git", "terminal prompts disabled"))
+	assert.ErrorIs(t, err, errors.ErrAuthenticationRequired)
+	assert.Equal(t, errors.CodeAuthenticationRequired, errors.Code(err))
+	assert.Contains(t, errors.HintFor(err).Suggestion, "ccmd login github.com")
+	ssh := errors.GitError("clone", authenticationError("git@github.com:acme/private.git", "Permission denied (publickey)"))
+	assert.Contains(t, errors.HintFor(ssh).Suggestion, "ssh-add")
+}
+
+func TestGitPromptEnv(t *testing.T) {
+	t.Setenv("GIT_TERMINAL_PROMPT", "")
+	os.Unsetenv("GIT_TERMINAL_PROMPT")
+	assert.Contains(t, gitPromptEnv(nil), "GIT_TERMINAL_PROMPT=0")
+
+	t.Setenv("GIT_TERMINAL_PROMPT", "1")
+	assert.NotContains(t, gitPromptEnv(nil), "GIT_TERMINAL_PROMPT=0")
+
+	t.Setenv("GIT_SSH_COMMAND", "ssh -i key")
+	assert.NotContains(t, gitPromptEnv(nil), "GIT_SSH_COMMAND=ssh -o BatchMode=yes")
+}
+
+func TestCloneFailsFastOnAuthentication(t *testing.T) {
+	t.Setenv(config.ConfigEnv, filepath.Join(t.TempDir(), "config.yaml"))
+	t.Setenv("GIT_TERMINAL_PROMPT", "")
+	os.Unsetenv("GIT_TERMINAL_PROMPT")
+	t.Setenv("GIT_ASKPASS", "")
+	t.Setenv("SSH_ASKPASS", "")
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("WWW-Authenticate", `Basic realm="git"`)
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	err := gitClone(context.Background(), server.URL+"/acme/private.git", filepath.Join(t.TempDir(), "repo"), "", CloneOptions{})
+	var auth *AuthenticationRequiredError
+	require.True(t, stderrors.As(err, &auth), "%v", err)
+	serverURL, _ := url.Parse(server.URL)
+	assert.Equal(t, serverURL.Hostname(), auth.Host)
+	assert.Equal(t, AuthHTTPS, auth.Mechanism)
+}
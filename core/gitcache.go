@@ -0,0 +1,322 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/gifflet/ccmd/internal/fs"
+	"github.com/gifflet/ccmd/pkg/config"
+	"github.com/gifflet/ccmd/pkg/output"
+	"github.com/gifflet/ccmd/pkg/telemetry"
+)
+
+// gitCacheDir is the directory under the configured cache_dir holding bare repositories
+const gitCacheDir = "repos"
+
+// GitClient gets the sources of git repositories
+type GitClient interface {
+	// Fetch brings the local copy of repo up to date with the branches and tags of its remote
+	Fetch(ctx context.Context, repo string) error
+	// ResolveRef returns the commit a tag, branch or commit of repo points to. An empty
+	// ref is the default branch.
+	ResolveRef(ctx context.Context, repo, ref string) (string, error)
+	// Checkout writes a working copy of repo at ref to dest, with origin set to repo
+	Checkout(ctx context.Context, repo, ref, dest string, opts CloneOptions) error
+}
+
+// newGitClient returns the git client configured for a project: repositories are kept as
+// bare repositories under cache_dir, so reinstalls and updates only fetch new objects.
+// Without a cache_dir every checkout is a fresh clone. With telemetry enabled, fetches
+// and checkouts are traced.
+func newGitClient(projectRoot string) GitClient {
+	var client GitClient = cloneClient{}
+	settings, err := config.Load(projectRoot)
+	if err == nil && settings.CacheDir != "" {
+		client = &cachedClient{dir: filepath.Join(settings.CacheDir, gitCacheDir)}
+	}
+	if telemetry.Enabled() {
+		_, cached := client.(*cachedClient)
+		return tracedGitClient{GitClient: client, cached: cached}
+	}
+	return client
+}
+
+// cloneClient clones from the remote on every checkout
+type cloneClient struct{}
+
+func (cloneClient) Fetch(ctx context.Context, repo string) error {
+	return nil
+}
+
+func (cloneClient) ResolveRef(ctx context.Context, repo, ref string) (string, error) {
+	if isCommitHash(ref) {
+		return ref, nil
+	}
+	if ref == "" {
+		ref = "HEAD"
+	}
+
+	// An annotated tag points to a tag object; its peeled ref^{} line has the commit
+	out, err := runGitRemote(ctx, "Remote lookup of "+ref, "ls-remote", repo, ref, ref+"^{}")
+	if ctx.Err() != nil {
+		return "", ctx.Err()
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get remote ref commit: %w", err)
+	}
+	commit := ""
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		if strings.HasSuffix(fields[1], "^{}") {
+			return fields[0], nil
+		}
+		if commit == "" {
+			commit = fields[0]
+		}
+	}
+	if commit != "" {
+		return commit, nil
+	}
+	return "", fmt.Errorf("ref %s not found in remote", ref)
+}
+
+func (cloneClient) Checkout(ctx context.Context, repo, ref, dest string, opts CloneOptions) error {
+	return gitClone(ctx, repo, dest, ref, opts)
+}
+
+// mirrorLocks serializes the use of each bare repository within the process
+var mirrorLocks sync.Map
+
+// cachedClient keeps a bare repository per remote under dir. Fetch runs an incremental
+// fetch of branches and tags, and checkouts clone from the bare repository.
+type cachedClient struct {
+	dir string
+}
+
+// path returns the bare repository of a remote
+func (c *cachedClient) path(repo string) string {
+	sum := sha256.Sum256([]byte(repo))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:8])+".git")
+}
+
+// lock holds the bare repository of a remote until the returned function is called
+func (c *cachedClient) lock(repo string) func() {
+	mu, _ := mirrorLocks.LoadOrStore(c.path(repo), &sync.Mutex{})
+	mu.(*sync.Mutex).Lock()
+	return mu.(*sync.Mutex).Unlock
+}
+
+func (c *cachedClient) Fetch(ctx context.Context, repo string) error {
+	defer c.lock(repo)()
+	return c.fetch(ctx, repo)
+}
+
+func (c *cachedClient) fetch(ctx context.Context, repo string) error {
+	path := c.path(repo)
+	if isBareRepository(path) {
+		output.PrintVerbosef("Fetching %s into %s", repo, path)
+		_, err := runGitRemote(ctx, "Fetch of "+repo,
+			"-C", path, "fetch", "--quiet", "--prune", "--tags", "--force", repo, "+refs/heads/*:refs/heads/*")
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			return fmt.Errorf("git fetch failed: %w", err)
+		}
+		return c.refreshHead(ctx, repo)
+	}
+
+	// A damaged copy is replaced, and a new one only appears once complete
+	if err := fs.RemoveAll(path); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return err
+	}
+	tempDir, err := os.MkdirTemp(c.dir, "clone-*")
+	if err != nil {
+		return err
+	}
+	defer fs.RemoveAll(tempDir)
+
+	git, err := getGitPath()
+	if err != nil {
+		return err
+	}
+	output.PrintVerbosef("Caching %s in %s", repo, path)
+	if err := runClone(ctx, git, repo, tempDir, []string{"clone", "--quiet", "--bare", repo, tempDir}); err != nil {
+		return err
+	}
+	return os.Rename(tempDir, path)
+}
+
+// refreshHead points HEAD of the bare repository at the default branch of the remote
+// again when the fetch pruned the branch it named, as after a rename of master to main
+func (c *cachedClient) refreshHead(ctx context.Context, repo string) error {
+	path := c.path(repo)
+	git, err := getGitPath()
+	if err != nil {
+		return err
+	}
+	if exec.CommandContext(ctx, git, "-C", path, "rev-parse", "--verify", "--quiet", "HEAD").Run() == nil {
+		return nil
+	}
+
+	refs, err := listRemoteRefs(ctx, repo)
+	if err != nil {
+		return err
+	}
+	if refs.head == "" {
+		return nil
+	}
+	output.PrintVerbosef("Default branch of %s is now %s", repo, refs.head)
+	if out, err := exec.CommandContext(ctx, git, "-C", path, "symbolic-ref", "HEAD", "refs/heads/"+refs.head).CombinedOutput(); err != nil {
+		return fmt.Errorf("git symbolic-ref failed: %w\nOutput: %s", err, string(out))
+	}
+	return nil
+}
+
+func (c *cachedClient) ResolveRef(ctx context.Context, repo, ref string) (string, error) {
+	defer c.lock(repo)()
+	return c.resolveRef(ctx, repo, ref)
+}
+
+func (c *cachedClient) resolveRef(ctx context.Context, repo, ref string) (string, error) {
+	path := c.path(repo)
+	if !isBareRepository(path) {
+		if err := c.fetch(ctx, repo); err != nil {
+			return "", err
+		}
+	}
+	if ref == "" {
+		ref = "HEAD"
+	}
+
+	git, err := getGitPath()
+	if err != nil {
+		return "", err
+	}
+	cmd := exec.CommandContext(ctx, git, "-C", path, "rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	out, err := cmd.Output()
+	if ctx.Err() != nil {
+		return "", ctx.Err()
+	}
+	if err != nil {
+		if isCommitHash(ref) {
+			return "", &CommitNotFoundError{Repository: repo, Commit: ref}
+		}
+		return "", fmt.Errorf("ref %s not found in %s", ref, repo)
+	}
+	commit := strings.TrimSpace(string(out))
+
+	// Pruned refs leave their commits in the bare repository until it is garbage collected
+	if isCommitHash(ref) {
+		reachable, err := commitReachable(ctx, git, path, commit, "refs/heads", "refs/tags")
+		if err != nil {
+			return "", err
+		}
+		if !reachable {
+			return "", &CommitNotFoundError{Repository: repo, Commit: ref}
+		}
+	}
+	return commit, nil
+}
+
+func (c *cachedClient) Checkout(ctx context.Context, repo, ref, dest string, opts CloneOptions) error {
+	defer c.lock(repo)()
+
+	if err := c.fetch(ctx, repo); err != nil {
+		return err
+	}
+	commit, err := c.resolveRef(ctx, repo, ref)
+	if err != nil {
+		return err
+	}
+
+	git, err := getGitPath()
+	if err != nil {
+		return err
+	}
+	run := func(args ...string) error {
+		cmd := exec.CommandContext(ctx, git, args...)
+		out, err := cmd.CombinedOutput()
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			return fmt.Errorf("git %s failed: %w\nOutput: %s", args[0], err, string(out))
+		}
+		return nil
+	}
+
+	// Branches and tags get the same shallow clone as a clone from the remote. The file://
+	// URL makes git honor --depth for the local bare repository. The cache holds every
+	// blob, so only the sparse checkout applies.
+	opts.Partial = false
+	source := fileURL(c.path(repo))
+	if isCommitHash(ref) {
+		if err := run("clone", "--quiet", "--no-checkout", source, dest); err != nil {
+			return err
+		}
+		if err := gitCheckout(ctx, git, repo, dest, commit, opts); err != nil {
+			return err
+		}
+	} else {
+		args := append([]string{"clone", "--quiet", "--depth", "1"}, opts.cloneArgs()...)
+		if ref != "" {
+			args = append(args, "--branch", ref)
+		}
+		if err := run(append(args, source, dest)...); err != nil {
+			return err
+		}
+		if len(opts.SparsePaths) > 0 {
+			if err := gitCheckout(ctx, git, repo, dest, "HEAD", opts); err != nil {
+				return err
+			}
+		}
+	}
+
+	return run("-C", dest, "remote", "set-url", "origin", repo)
+}
+
+// isBareRepository reports whether path holds a usable bare repository
+func isBareRepository(path string) bool {
+	if _, err := os.Stat(filepath.Join(path, "HEAD")); err != nil {
+		return false
+	}
+	git, err := getGitPath()
+	if err != nil {
+		return false
+	}
+	out, err := exec.Command(git, "-C", path, "rev-parse", "--is-bare-repository").Output()
+	return err == nil && strings.TrimSpace(string(out)) == "true"
+}
+
+// fileURL returns the file:// URL of a local path
+func fileURL(path string) string {
+	path = filepath.ToSlash(path)
+	if !strings.HasPrefix(path, "/") {
+		// Windows drive paths, C:/x becomes file:///C:/x
+		path = "/" + path
+	}
+	return "file://" + path
+}
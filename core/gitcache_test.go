@@ -0,0 +1,142 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeGitRepo creates a repository with one commit tagged v1.0.0 and returns a function
+// that commits and tags another version
+func writeGitRepo(t *testing.T) (string, func(version string)) {
+	if _, err := getGitPath(); err != nil {
+		t.Skip("git not available")
+	}
+	t.Setenv("GIT_AUTHOR_NAME", "test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	dir := t.TempDir()
+	git := func(args ...string) {
+		out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	git("init", "--quiet", "--initial-branch", "main")
+
+	release := func(version string) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "VERSION"), []byte(version), 0644))
+		git("add", "VERSION")
+		git("commit", "--quiet", "-m", version)
+		git("tag", version)
+	}
+	release("v1.0.0")
+	return dir, release
+}
+
+func TestCachedClientCheckout(t *testing.T) {
+	repo, release := writeGitRepo(t)
+	client := &cachedClient{dir: t.TempDir()}
+	ctx := context.Background()
+
+	dest := filepath.Join(t.TempDir(), "v1")
+	require.NoError(t, client.Checkout(ctx, repo, "v1.0.0", dest, CloneOptions{}))
+	assert.FileExists(t, filepath.Join(dest, "VERSION"))
+	assert.DirExists(t, client.path(repo))
+
+	origin, err := exec.Command("git", "-C", dest, "remote", "get-url", "origin").Output()
+	require.NoError(t, err)
+	assert.Equal(t, repo, strings.TrimSpace(string(origin)))
+
+	// A new release reaches the cached copy through an incremental fetch
+	release("v1.1.0")
+	dest = filepath.Join(t.TempDir(), "v2")
+	require.NoError(t, client.Checkout(ctx, repo, "v1.1.0", dest, CloneOptions{}))
+	data, err := os.ReadFile(filepath.Join(dest, "VERSION"))
+	require.NoError(t, err)
+	assert.Equal(t, "v1.1.0", string(data))
+
+	commit, err := client.ResolveRef(ctx, repo, "v1.0.0")
+	require.NoError(t, err)
+	head, err := client.ResolveRef(ctx, repo, "")
+	require.NoError(t, err)
+	assert.NotEqual(t, commit, head)
+
+	dest = filepath.Join(t.TempDir(), "pinned")
+	require.NoError(t, client.Checkout(ctx, repo, commit[:12], dest, CloneOptions{}))
+	current, err := gitGetCurrentCommit(dest)
+	require.NoError(t, err)
+	assert.Equal(t, commit, current)
+
+	_, err = client.ResolveRef(ctx, repo, "v9.9.9")
+	assert.Error(t, err)
+}
+
+func TestCachedClientFollowsRenamedDefaultBranch(t *testing.T) {
+	repo, release := writeGitRepo(t)
+	client := &cachedClient{dir: t.TempDir()}
+	ctx := context.Background()
+
+	require.NoError(t, client.Checkout(ctx, repo, "", filepath.Join(t.TempDir(), "main"), CloneOptions{}))
+
+	out, err := exec.Command("git", "-C", repo, "branch", "--move", "main", "trunk").CombinedOutput()
+	require.NoError(t, err, string(out))
+	release("v1.1.0")
+
+	// The fetch prunes main, which HEAD of the cached copy named
+	dest := filepath.Join(t.TempDir(), "trunk")
+	require.NoError(t, client.Checkout(ctx, repo, "", dest, CloneOptions{}))
+	data, err := os.ReadFile(filepath.Join(dest, "VERSION"))
+	require.NoError(t, err)
+	assert.Equal(t, "v1.1.0", string(data))
+	branch, err := gitCurrentBranch(dest)
+	require.NoError(t, err)
+	assert.Equal(t, "trunk", branch)
+}
+
+func TestCachedClientReplacesDamagedCopy(t *testing.T) {
+	repo, _ := writeGitRepo(t)
+	client := &cachedClient{dir: t.TempDir()}
+
+	require.NoError(t, os.MkdirAll(client.path(repo), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(client.path(repo), "HEAD"), []byte("garbage"), 0644))
+
+	require.NoError(t, client.Fetch(context.Background(), repo))
+	assert.True(t, isBareRepository(client.path(repo)))
+}
+
+func TestNewGitClient(t *testing.T) {
+	cacheDir := t.TempDir()
+	t.Setenv("CCMD_CACHE_DIR", cacheDir)
+
+	client, ok := newGitClient(t.TempDir()).(*cachedClient)
+	require.True(t, ok)
+	assert.Equal(t, filepath.Join(cacheDir, gitCacheDir), client.dir)
+}
+
+func TestCloneClientResolveRefPeelsTags(t *testing.T) {
+	repo, _ := writeGitRepo(t)
+	out, err := exec.Command("git", "-C", repo, "tag", "-a", "v2.0.0", "-m", "annotated").CombinedOutput()
+	require.NoError(t, err, string(out))
+	head, err := gitGetCurrentCommit(repo)
+	require.NoError(t, err)
+
+	commit, err := cloneClient{}.ResolveRef(context.Background(), repo, "v2.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, head, commit, "the commit, not the tag object")
+}
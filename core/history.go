@@ -0,0 +1,197 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gifflet/ccmd/pkg/errors"
+	"github.com/gifflet/ccmd/pkg/output"
+)
+
+// HistoryLimit is how many previous versions the lock file keeps for each command
+const HistoryLimit = 10
+
+// LockHistory records a version a command was installed at before it changed
+type LockHistory struct {
+	Version   string    `yaml:"version" json:"version"`
+	Resolved  string    `yaml:"resolved" json:"resolved"`
+	Commit    string    `yaml:"commit" json:"commit"`
+	UpdatedAt time.Time `yaml:"updated_at" json:"updated_at"`
+}
+
+// Ref returns the tag, branch or commit the entry was installed from
+func (h LockHistory) Ref() string {
+	if _, ref := ParseRepositorySpec(h.Resolved); ref != "" {
+		return ref
+	}
+	return h.Commit
+}
+
+// appendHistory returns the history of a lock entry about to be replaced by an install
+// of commit. The replaced version is added when the commit changes; reinstalls of the
+// same commit keep the history as it is.
+func appendHistory(existing *LockCommand, commit string) []LockHistory {
+	history := existing.History
+	if existing.Commit == "" || existing.Commit == commit {
+		return history
+	}
+
+	history = append(history, LockHistory{
+		Version:   existing.Version,
+		Resolved:  existing.Resolved,
+		Commit:    existing.Commit,
+		UpdatedAt: existing.UpdatedAt,
+	})
+	if len(history) > HistoryLimit {
+		history = history[len(history)-HistoryLimit:]
+	}
+	return history
+}
+
+// CommandHistory is the installed version of a command and the ones before it
+type CommandHistory struct {
+	Name     string        `json:"name"`
+	Current  LockHistory   `json:"current"`
+	Previous []LockHistory `json:"previous"` // newest first
+}
+
+// History returns the version history of an installed command from the lock file
+func History(name, projectPath string) (*CommandHistory, error) {
+	locked, err := lockedCommandEntry(name, projectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	history := &CommandHistory{
+		Name: name,
+		Current: LockHistory{
+			Version:   locked.Version,
+			Resolved:  locked.Resolved,
+			Commit:    locked.Commit,
+			UpdatedAt: locked.UpdatedAt,
+		},
+		Previous: make([]LockHistory, 0, len(locked.History)),
+	}
+	for i := len(locked.History) - 1; i >= 0; i-- {
+		history.Previous = append(history.Previous, locked.History[i])
+	}
+	return history, nil
+}
+
+// RollbackOptions represents options for rolling a command back
+type RollbackOptions struct {
+	Name string
+	// To selects the recorded version by version, tag, branch or commit prefix; empty
+	// means the version installed before the current one
+	To string
+
+	// OverwriteLocal and Backup handle local modifications as they do for updates
+	OverwriteLocal bool
+	Backup         bool
+}
+
+// Rollback reinstalls a version of a command recorded in its history, at the commit it
+// had then. The version rolled back from joins the history, so a second rollback
+// returns to it.
+func Rollback(ctx context.Context, opts RollbackOptions) (*LockHistory, error) {
+	projectRoot, err := findProjectRoot()
+	if err != nil {
+		return nil, err
+	}
+	defer snapshotBefore(projectRoot, AuditRollback)()
+
+	locked, err := lockedCommandEntry(opts.Name, projectRoot)
+	if err != nil {
+		return nil, err
+	}
+	target, err := rollbackTarget(opts.Name, locked.History, opts.To)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd, err := GetCommandInfo(opts.Name, projectRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	_, current := ParseRepositorySpec(locked.Resolved)
+	output.PrintInfof("Rolling back %s from %s to %s", opts.Name, displayRef(current, locked.Commit), displayRef(target.Ref(), target.Commit))
+	err = applyUpdate(ctx, projectRoot, *cmd, &UpdatePlan{
+		Name:           cmd.Name,
+		Repository:     cmd.Repository,
+		CurrentVersion: current,
+		TargetVersion:  target.Ref(),
+	}, UpdateOptions{
+		OverwriteLocal: opts.OverwriteLocal,
+		Backup:         opts.Backup,
+		commit:         target.Commit,
+		audit:          AuditRollback,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return target, nil
+}
+
+// lockedCommandEntry returns the lock entry of an installed command
+func lockedCommandEntry(name, projectPath string) (*LockCommand, error) {
+	projectRoot, err := findProjectRootFrom(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	lockPath := lockFilePath(projectRoot)
+	if !fileExists(lockPath) {
+		return nil, errors.NotFound(fmt.Sprintf("command %q", name))
+	}
+	lockFile, err := ReadLockFile(lockPath)
+	if err != nil {
+		return nil, err
+	}
+	locked := lockFile.Commands[name]
+	if locked == nil {
+		return nil, errors.NotFound(fmt.Sprintf("command %q", name))
+	}
+	return locked, nil
+}
+
+// rollbackTarget picks the history entry to roll back to: the newest one matching to,
+// or the newest one when to is empty
+func rollbackTarget(name string, history []LockHistory, to string) (*LockHistory, error) {
+	if len(history) == 0 {
+		return nil, errors.NotFound(fmt.Sprintf("previous version of %q; its history is empty", name))
+	}
+	if to == "" {
+		return &history[len(history)-1], nil
+	}
+
+	for i := len(history) - 1; i >= 0; i-- {
+		entry := history[i]
+		if entry.Version == to || entry.Ref() == to ||
+			(isCommitHash(to) && strings.HasPrefix(entry.Commit, to)) {
+			return &entry, nil
+		}
+	}
+	return nil, errors.NotFound(fmt.Sprintf("version %q in the history of %q; run 'ccmd history %s' to see it", to, name, name))
+}
+
+// displayRef shows a ref with its abbreviated commit when the ref is not the commit
+func displayRef(ref, commit string) string {
+	if ref == "" {
+		return fmt.Sprintf("%.7s", commit)
+	}
+	if commit == "" || strings.HasPrefix(commit, ref) {
+		return ref
+	}
+	return fmt.Sprintf("%s (%.7s)", ref, commit)
+}
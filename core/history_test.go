@@ -0,0 +1,97 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gifflet/ccmd/pkg/config"
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+func TestAppendHistory(t *testing.T) {
+	existing := &LockCommand{Version: "v1.0.0", Resolved: "acme/tool@v1.0.0", Commit: "aaa", UpdatedAt: time.Now()}
+
+	assert.Empty(t, appendHistory(existing, "aaa"), "reinstalls of the same commit are not history")
+
+	history := appendHistory(existing, "bbb")
+	require.Len(t, history, 1)
+	assert.Equal(t, "v1.0.0", history[0].Ref())
+
+	// The history is bounded, dropping the oldest versions
+	for i := 0; i < HistoryLimit+5; i++ {
+		existing.History = history
+		existing.Commit = fmt.Sprintf("c%d", i)
+		existing.Resolved = fmt.Sprintf("acme/tool@v1.%d.0", i)
+		history = appendHistory(existing, fmt.Sprintf("c%d", i+1))
+	}
+	require.Len(t, history, HistoryLimit)
+	assert.Equal(t, fmt.Sprintf("v1.%d.0", HistoryLimit+4), history[HistoryLimit-1].Ref())
+}
+
+func TestRollback(t *testing.T) {
+	repo, release := writeCommandRepo(t)
+	first, err := gitGetCurrentCommit(strings.TrimPrefix(repo, "file://"))
+	require.NoError(t, err)
+	second := release("v1.1.0")
+
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	ctx := context.Background()
+	t.Setenv(config.ConfigEnv, filepath.Join(t.TempDir(), "config.yaml"))
+
+	_, err = Rollback(ctx, RollbackOptions{Name: "tool"})
+	assert.ErrorIs(t, err, errors.ErrNotFound)
+
+	_, _, err = Install(ctx, InstallOptions{Repository: repo + "@v1.0.0"})
+	require.NoError(t, err)
+	_, err = Rollback(ctx, RollbackOptions{Name: "tool"})
+	assert.ErrorIs(t, err, errors.ErrNotFound, "nothing to roll back to yet")
+
+	_, _, err = Install(ctx, InstallOptions{Repository: repo + "@v1.1.0", Force: true})
+	require.NoError(t, err)
+	_, _, err = Install(ctx, InstallOptions{Repository: repo + "@v1.1.0", Force: true})
+	require.NoError(t, err)
+
+	history, err := History("tool", ".")
+	require.NoError(t, err)
+	assert.Equal(t, second, history.Current.Commit)
+	require.Len(t, history.Previous, 1)
+	assert.Equal(t, first, history.Previous[0].Commit)
+	assert.Equal(t, "v1.0.0", history.Previous[0].Ref())
+
+	_, err = Rollback(ctx, RollbackOptions{Name: "tool", To: "v9.0.0"})
+	assert.ErrorIs(t, err, errors.ErrNotFound)
+
+	target, err := Rollback(ctx, RollbackOptions{Name: "tool", To: first[:7]})
+	require.NoError(t, err)
+	assert.Equal(t, "v1.0.0", target.Ref())
+	locked := readLockFile(t).Commands["tool"]
+	assert.Equal(t, first, locked.Commit)
+	require.Len(t, locked.History, 2)
+	assert.Equal(t, second, locked.History[1].Commit)
+	data, err := os.ReadFile(ConfigFileName)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), repo+"@v1.0.0")
+
+	// Rolling back again undoes the rollback
+	_, err = Rollback(ctx, RollbackOptions{Name: "tool"})
+	require.NoError(t, err)
+	assert.Equal(t, second, readLockFile(t).Commands["tool"].Commit)
+}
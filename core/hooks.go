@@ -0,0 +1,222 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+// hookMarker identifies the git hooks written by ccmd, so they can be updated and
+// removed without touching hooks written by hand or by other tools
+const hookMarker = "# Installed by ccmd hooks"
+
+// hookBackupSuffix is appended to a hook that --force replaced, so uninstalling restores it
+const hookBackupSuffix = ".pre-ccmd"
+
+// hookFiles is replaced in hookBodies by ccmd.yaml and ccmd-lock.yaml relative to the
+// repository root, each quoted as a separate argument
+const hookFiles = "{files}"
+
+// hookBodies holds the script of each hook after the shared preamble
+var hookBodies = map[string]string{
+	// Runs after git pull and git merge
+	"post-merge": `git diff --quiet ORIG_HEAD HEAD -- {files} 2>/dev/null && exit 0
+cd "$root" && ccmd sync --frozen --quiet
+`,
+	// Runs after switching branches; $3 is 0 for checkouts of single files
+	"post-checkout": `[ "$3" = "1" ] || exit 0
+git diff --quiet "$1" "$2" -- {files} 2>/dev/null && exit 0
+cd "$root" && ccmd sync --frozen --quiet
+`,
+	// Refuses commits that change ccmd.yaml or the lock file without the other
+	"pre-commit": `git diff --cached --quiet -- {files} && exit 0
+cd "$root" && ccmd status --check --short
+`,
+}
+
+// HookNames lists the git hooks InstallHooks writes
+func HookNames() []string {
+	names := make([]string, 0, len(hookBodies))
+	for name := range hookBodies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// HookResult describes one hook written by InstallHooks
+type HookResult struct {
+	Name     string `json:"name"`
+	Path     string `json:"path"`
+	BackedUp string `json:"backed_up,omitempty"` // where a replaced hook was moved
+}
+
+// InstallHooks writes git hooks into the repository holding the project: after merges
+// and branch switches that change ccmd.yaml or ccmd-lock.yaml, commands are synced from
+// the lock file, and commits touching either file are checked for drift between them.
+// Hooks ccmd did not write are left alone unless force is set, in which case they are
+// kept next to the new hook with a .pre-ccmd suffix. A backup that already exists is
+// never overwritten.
+func InstallHooks(projectPath string, force bool) ([]HookResult, error) {
+	hooksDir, project, err := gitHooksDir(projectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	names := HookNames()
+	var foreign, backedUp []string
+	for _, name := range names {
+		path := filepath.Join(hooksDir, name)
+		if !isForeignHook(path) {
+			continue
+		}
+		foreign = append(foreign, name)
+		if _, err := os.Lstat(path + hookBackupSuffix); err == nil {
+			backedUp = append(backedUp, name+hookBackupSuffix)
+		}
+	}
+	if len(foreign) > 0 && !force {
+		return nil, errors.Conflict(fmt.Sprintf("%s already has %s hook(s) not written by ccmd; "+
+			"use --force to replace them (they are kept with a %s suffix)",
+			hooksDir, strings.Join(foreign, ", "), hookBackupSuffix))
+	}
+	// Replacing a hook would overwrite the backup of one replaced earlier
+	if len(backedUp) > 0 {
+		return nil, errors.Conflict(fmt.Sprintf("%s already has %s; move or remove them before "+
+			"replacing the hooks not written by ccmd", hooksDir, strings.Join(backedUp, ", ")))
+	}
+
+	if err := os.MkdirAll(hooksDir, 0755); err != nil {
+		return nil, errors.FileError("create hooks directory", hooksDir, err)
+	}
+
+	// The hooks watch ccmd.yaml and ccmd-lock.yaml where the paths settings put them
+	projectRoot, err := findProjectRootFrom(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	paths := projectPaths(projectRoot)
+	files := []string{projectRelative(projectRoot, paths.Config), projectRelative(projectRoot, paths.Lock)}
+
+	results := make([]HookResult, 0, len(names))
+	for _, name := range names {
+		path := filepath.Join(hooksDir, name)
+		result := HookResult{Name: name, Path: path}
+		if isForeignHook(path) {
+			result.BackedUp = path + hookBackupSuffix
+			if err := os.Rename(path, result.BackedUp); err != nil {
+				return results, errors.FileError("back up hook", path, err)
+			}
+		}
+		if err := writeFileAtomic(path, []byte(hookScript(name, project, files)), 0755); err != nil {
+			return results, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// UninstallHooks removes the hooks written by InstallHooks, restoring those it replaced,
+// and returns the names of the removed hooks
+func UninstallHooks(projectPath string) ([]string, error) {
+	hooksDir, _, err := gitHooksDir(projectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var removed []string
+	for _, name := range HookNames() {
+		path := filepath.Join(hooksDir, name)
+		if !isCcmdHook(path) {
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			return removed, errors.FileError("remove hook", path, err)
+		}
+		if fileExists(path + hookBackupSuffix) {
+			if err := os.Rename(path+hookBackupSuffix, path); err != nil {
+				return removed, errors.FileError("restore hook", path, err)
+			}
+		}
+		removed = append(removed, name)
+	}
+	return removed, nil
+}
+
+// hookScript returns the script of a hook for a project at the given path, relative to
+// the repository root, watching files given relative to the project. Paths are quoted,
+// so the shell never expands anything in them.
+func hookScript(name, project string, files []string) string {
+	root := `"$(git rev-parse --show-toplevel)"`
+	if project != "." {
+		root += shellQuote("/" + filepath.ToSlash(project))
+	}
+	quoted := make([]string, len(files))
+	for i, file := range files {
+		quoted[i] = shellQuote(filepath.ToSlash(filepath.Join(project, file)))
+	}
+	return fmt.Sprintf(`#!/bin/sh
+%s; remove with 'ccmd hooks uninstall'
+command -v ccmd >/dev/null 2>&1 || exit 0
+root=%s
+%s`, hookMarker, root, strings.ReplaceAll(hookBodies[name], hookFiles, strings.Join(quoted, " ")))
+}
+
+// shellQuote returns s as a single-quoted shell word, in which nothing is expanded
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// gitHooksDir returns the hooks directory of the repository holding the project, which
+// honors core.hooksPath, and the project's path relative to the repository root
+func gitHooksDir(projectPath string) (hooksDir, project string, err error) {
+	projectRoot, err := findProjectRootFrom(projectPath)
+	if err != nil {
+		return "", "", err
+	}
+	git, err := getGitPath()
+	if err != nil {
+		return "", "", errors.GitError("find git", err)
+	}
+
+	out, err := exec.Command(git, "-C", projectRoot, "rev-parse", "--git-path", "hooks", "--show-prefix").Output()
+	if err != nil {
+		return "", "", errors.InvalidInput(fmt.Sprintf("%s is not inside a git repository", projectRoot))
+	}
+	lines := strings.Split(strings.TrimRight(string(out), "\n"), "\n")
+	hooksDir = lines[0]
+	if !filepath.IsAbs(hooksDir) {
+		hooksDir = filepath.Join(projectRoot, hooksDir)
+	}
+
+	project = "."
+	if len(lines) > 1 && lines[1] != "" {
+		project = strings.TrimSuffix(lines[1], "/")
+	}
+	return hooksDir, project, nil
+}
+
+// isCcmdHook reports whether the hook at path was written by ccmd
+func isCcmdHook(path string) bool {
+	data, err := os.ReadFile(path)
+	return err == nil && strings.Contains(string(data), hookMarker)
+}
+
+// isForeignHook reports whether a hook ccmd did not write exists at path
+func isForeignHook(path string) bool {
+	return fileExists(path) && !isCcmdHook(path)
+}
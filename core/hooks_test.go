@@ -0,0 +1,139 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+func TestInstallHooks(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+
+	_, err := InstallHooks(".", false)
+	assert.ErrorIs(t, err, errors.ErrInvalidInput, "outside a git repository")
+
+	out, err := exec.Command("git", "init", "--quiet").CombinedOutput()
+	require.NoError(t, err, string(out))
+	writeConfig(t, []string{})
+	hooksDir := filepath.Join(".git", "hooks")
+
+	results, err := InstallHooks(".", false)
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	for _, name := range HookNames() {
+		path := filepath.Join(hooksDir, name)
+		info, err := os.Stat(path)
+		require.NoError(t, err, name)
+		assert.NotZero(t, info.Mode()&0100, "%s is executable", name)
+
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), "'ccmd.yaml' 'ccmd-lock.yaml'")
+		out, err := exec.Command("sh", "-n", path).CombinedOutput()
+		assert.NoError(t, err, "%s: %s", name, out)
+	}
+
+	// Installing again updates the hooks in place
+	_, err = InstallHooks(".", false)
+	require.NoError(t, err)
+
+	// Hooks written by others are only replaced with force, and are kept
+	preCommit := filepath.Join(hooksDir, "pre-commit")
+	require.NoError(t, os.WriteFile(preCommit, []byte("#!/bin/sh\nmake lint\n"), 0755))
+	_, err = InstallHooks(".", false)
+	assert.ErrorIs(t, err, errors.ErrConflict)
+	assert.Contains(t, err.Error(), "pre-commit")
+
+	results, err = InstallHooks(".", true)
+	require.NoError(t, err)
+	for _, result := range results {
+		if result.Name == "pre-commit" {
+			assert.Equal(t, preCommit+hookBackupSuffix, result.BackedUp)
+		} else {
+			assert.Empty(t, result.BackedUp)
+		}
+	}
+
+	// A backup left by an earlier --force is never overwritten
+	ccmdHook, err := os.ReadFile(preCommit)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(preCommit, []byte("#!/bin/sh\nmake test\n"), 0755))
+	_, err = InstallHooks(".", true)
+	require.ErrorIs(t, err, errors.ErrConflict)
+	assert.Contains(t, err.Error(), "pre-commit"+hookBackupSuffix)
+	require.NoError(t, os.WriteFile(preCommit, ccmdHook, 0755))
+
+	// Uninstalling restores the replaced hook
+	removed, err := UninstallHooks(".")
+	require.NoError(t, err)
+	assert.Equal(t, HookNames(), removed)
+	data, err := os.ReadFile(preCommit)
+	require.NoError(t, err)
+	assert.Equal(t, "#!/bin/sh\nmake lint\n", string(data))
+	assert.NoFileExists(t, filepath.Join(hooksDir, "post-merge"))
+}
+
+func TestInstallHooksSubdirectory(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+
+	out, err := exec.Command("git", "init", "--quiet").CombinedOutput()
+	require.NoError(t, err, string(out))
+	require.NoError(t, os.MkdirAll(filepath.Join("tools", "claude"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join("tools", "claude", ConfigFileName), []byte("commands: []\n"), 0644))
+
+	_, err = InstallHooks(filepath.Join("tools", "claude"), false)
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(filepath.Join(".git", "hooks", "post-merge"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `root="$(git rev-parse --show-toplevel)"'/tools/claude'`)
+	assert.Contains(t, string(data), "'tools/claude/ccmd.yaml' 'tools/claude/ccmd-lock.yaml'")
+}
+
+func TestInstallHooksQuotesPaths(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+
+	out, err := exec.Command("git", "init", "--quiet").CombinedOutput()
+	require.NoError(t, err, string(out))
+	project := "it's $(touch pwned)"
+	require.NoError(t, os.Mkdir(project, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(project, ConfigFileName), []byte("commands: []\n"), 0644))
+	_, err = InstallHooks(project, false)
+	require.NoError(t, err)
+
+	// A stand-in ccmd records where the hook ran it
+	bin := t.TempDir()
+	ran := filepath.Join(bin, "ran")
+	require.NoError(t, os.WriteFile(filepath.Join(bin, "ccmd"), []byte("#!/bin/sh\npwd > '"+ran+"'\n"), 0755))
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	out, err = exec.Command("git", "add", filepath.Join(project, ConfigFileName)).CombinedOutput()
+	require.NoError(t, err, string(out))
+	out, err = exec.Command("sh", filepath.Join(".git", "hooks", "pre-commit")).CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	assert.NoFileExists(t, "pwned")
+	data, err := os.ReadFile(ran)
+	require.NoError(t, err, "the hook saw the staged config file")
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(wd, project)+"\n", string(data))
+}
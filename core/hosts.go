@@ -0,0 +1,192 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gifflet/ccmd/pkg/config"
+	"github.com/gifflet/ccmd/pkg/repospec"
+)
+
+const (
+	// DefaultHost is the forge used for owner/repo shorthands when nothing else is configured
+	DefaultHost = "github.com"
+	// DefaultHostEnv overrides the default host for owner/repo shorthands
+	DefaultHostEnv = "CCMD_DEFAULT_HOST"
+
+	// HostAuthHTTPS clones over HTTPS (default)
+	HostAuthHTTPS = "https"
+	// HostAuthSSH clones over SSH using git@host:path URLs
+	HostAuthSSH = "ssh"
+)
+
+// builtinHostAliases maps shorthand prefixes such as "gitlab:group/project" to hosts
+var builtinHostAliases = map[string]string{
+	"github":    "github.com",
+	"gh":        "github.com",
+	"gitlab":    "gitlab.com",
+	"gl":        "gitlab.com",
+	"bitbucket": "bitbucket.org",
+	"bb":        "bitbucket.org",
+}
+
+// HostConfig holds per-host settings from the hosts section of ccmd.yaml
+type HostConfig struct {
+	DefaultBranch string   `yaml:"default_branch,omitempty" json:"default_branch,omitempty"`
+	Auth          string   `yaml:"auth,omitempty" json:"auth,omitempty"`
+	Aliases       []string `yaml:"aliases,omitempty" json:"aliases,omitempty"`
+}
+
+// hostResolver turns repository shorthands into clone URLs using host settings
+type hostResolver struct {
+	defaultHost string
+	hosts       map[string]HostConfig
+	aliases     map[string]string
+}
+
+func newHostResolver(defaultHost string, hosts map[string]HostConfig) *hostResolver {
+	if defaultHost == "" {
+		defaultHost = DefaultHost
+	}
+
+	aliases := make(map[string]string, len(builtinHostAliases))
+	for alias, host := range builtinHostAliases {
+		aliases[alias] = host
+	}
+	for host, cfg := range hosts {
+		for _, alias := range cfg.Aliases {
+			aliases[strings.ToLower(alias)] = host
+		}
+	}
+
+	return &hostResolver{
+		defaultHost: strings.ToLower(defaultHost),
+		hosts:       hosts,
+		aliases:     aliases,
+	}
+}
+
+// activeHostResolver builds the resolver of the project around the working directory
+func activeHostResolver() *hostResolver {
+	projectRoot, _ := findProjectRoot()
+	return hostResolverFor(projectRoot)
+}
+
+// hostResolverFor builds a resolver from the layered ccmd configuration, the ccmd.yaml of
+// the project at projectRoot and CCMD_DEFAULT_HOST, in increasing priority
+func hostResolverFor(projectRoot string) *hostResolver {
+	var cfg *ProjectConfig
+	if projectRoot != "" && ProjectConfigExists(projectRoot) {
+		cfg, _ = LoadProjectConfig(projectRoot)
+	}
+	return projectHostResolver(projectRoot, cfg)
+}
+
+// projectHostResolver builds a resolver from the layered ccmd configuration, a project
+// configuration, which may be nil, and CCMD_DEFAULT_HOST, in increasing priority
+func projectHostResolver(projectRoot string, cfg *ProjectConfig) *hostResolver {
+	var defaultHost string
+	var hosts map[string]HostConfig
+
+	if settings, err := config.Load(projectRoot); err == nil {
+		defaultHost = settings.DefaultHost
+	}
+
+	if cfg != nil {
+		if cfg.DefaultHost != "" {
+			defaultHost = cfg.DefaultHost
+		}
+		hosts = cfg.Hosts
+	}
+
+	if env := os.Getenv(DefaultHostEnv); env != "" {
+		defaultHost = env
+	}
+
+	return newHostResolver(defaultHost, hosts)
+}
+
+// split parses a shorthand spec, with its host resolved from an alias or the default
+// host. It returns ok=false for URLs, local paths and single names.
+func (r *hostResolver) split(spec string) (repospec.Spec, bool) {
+	s, err := repospec.ParseWith(spec, repospec.Options{DefaultHost: r.defaultHost, Aliases: r.aliases})
+	if err != nil || s.Form != repospec.FormShorthand {
+		return repospec.Spec{}, false
+	}
+	if s.Alias == "" && !strings.Contains(s.Path, "/") {
+		return repospec.Spec{}, false
+	}
+	return s, true
+}
+
+// normalize converts a repository spec into a clone URL. A version is kept after it.
+func (r *hostResolver) normalize(spec string) string {
+	s, ok := r.split(spec)
+	if !ok {
+		parsed, err := repospec.Parse(spec)
+		if err != nil || parsed.Form == repospec.FormShorthand || parsed.Form == repospec.FormLocal {
+			return spec
+		}
+		if !strings.HasSuffix(parsed.Source, ".git") && r.isKnownHost(parsed.Host) {
+			parsed.Source = strings.TrimSuffix(parsed.Source, "/") + ".git"
+		}
+		return parsed.String()
+	}
+
+	var url string
+	if r.hosts[s.Host].Auth == HostAuthSSH {
+		url = fmt.Sprintf("git@%s:%s.git", s.Host, s.Path)
+	} else {
+		url = fmt.Sprintf("https://%s/%s.git", s.Host, s.Path)
+	}
+	if s.Command != "" {
+		url += repospec.CommandSeparator + s.Command
+	}
+	if s.Version != "" {
+		url += repospec.VersionSeparator + s.Version
+	}
+	return url
+}
+
+// hostConfig returns the settings that apply to a spec or URL
+func (r *hostResolver) hostConfig(spec string) HostConfig {
+	if s, ok := r.split(spec); ok {
+		return r.hosts[s.Host]
+	}
+	return r.hosts[hostFromURL(spec)]
+}
+
+func (r *hostResolver) isKnownHost(host string) bool {
+	if host == "" {
+		return false
+	}
+	if _, ok := r.hosts[host]; ok {
+		return true
+	}
+	for _, known := range builtinHostAliases {
+		if host == known {
+			return true
+		}
+	}
+	return host == r.defaultHost
+}
+
+// hostFromURL extracts the lowercase host name from a repository URL, scp-like SSH
+// source or host.tld/owner/repo shorthand. Other shorthands have none.
+func hostFromURL(url string) string {
+	s, err := repospec.Parse(url)
+	if err != nil {
+		return ""
+	}
+	return s.Host
+}
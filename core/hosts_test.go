@@ -0,0 +1,106 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHostResolverNormalize(t *testing.T) {
+	resolver := newHostResolver("", map[string]HostConfig{
+		"git.corp.com": {Auth: HostAuthSSH, Aliases: []string{"corp"}},
+	})
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"github shorthand", "owner/repo", "https://github.com/owner/repo.git"},
+		{"gitlab prefix", "gitlab:group/project", "https://gitlab.com/group/project.git"},
+		{"gitlab nested group", "gl:group/sub/project", "https://gitlab.com/group/sub/project.git"},
+		{"bitbucket prefix", "bb:team/repo", "https://bitbucket.org/team/repo.git"},
+		{"host prefixed", "codeberg.org/owner/repo", "https://codeberg.org/owner/repo.git"},
+		{"configured ssh host", "git.corp.com/team/repo", "git@git.corp.com:team/repo.git"},
+		{"configured alias", "corp:team/repo", "git@git.corp.com:team/repo.git"},
+		{"https github without suffix", "https://github.com/owner/repo", "https://github.com/owner/repo.git"},
+		{"https gitlab without suffix", "https://gitlab.com/group/project", "https://gitlab.com/group/project.git"},
+		{"ssh url unchanged", "git@github.com:owner/repo.git", "git@github.com:owner/repo.git"},
+		{"unknown host unchanged", "https://example.com/owner/repo", "https://example.com/owner/repo"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, resolver.normalize(tt.input))
+		})
+	}
+}
+
+func TestHostResolverDefaultHost(t *testing.T) {
+	resolver := newHostResolver("gitlab.com", nil)
+	assert.Equal(t, "https://gitlab.com/owner/repo.git", resolver.normalize("owner/repo"))
+	assert.Equal(t, "https://github.com/owner/repo.git", resolver.normalize("github:owner/repo"))
+}
+
+func TestHostResolverHostConfig(t *testing.T) {
+	resolver := newHostResolver("", map[string]HostConfig{
+		"gitlab.com": {DefaultBranch: "develop"},
+	})
+
+	assert.Equal(t, "develop", resolver.hostConfig("gitlab:group/project").DefaultBranch)
+	assert.Equal(t, "develop", resolver.hostConfig("https://gitlab.com/group/project.git").DefaultBranch)
+	assert.Empty(t, resolver.hostConfig("owner/repo").DefaultBranch)
+}
+
+func TestNormalizeRepositoryURLUsesProjectConfig(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+
+	writeConfigMap(t, map[string]interface{}{
+		"default_host": "bitbucket.org",
+		"commands":     []string{},
+	})
+
+	assert.Equal(t, "https://bitbucket.org/team/repo.git", NormalizeRepositoryURL("team/repo"))
+
+	t.Setenv(DefaultHostEnv, "gitlab.com")
+	assert.Equal(t, "https://gitlab.com/team/repo.git", NormalizeRepositoryURL("team/repo"))
+	t.Setenv(DefaultHostEnv, "")
+
+	// A project elsewhere uses its own ccmd.yaml, whatever the working directory
+	other := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(other, ConfigFileName), []byte("default_host: gitlab.com\n"), 0644))
+	assert.Equal(t, "https://gitlab.com/team/repo.git", normalizeRepositoryURL(other, "team/repo"))
+}
+
+func TestConfigSource(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	t.Setenv(DefaultHostEnv, "")
+
+	assert.Equal(t, "owner/repo", configSource(".", "https://github.com/owner/repo.git"))
+	assert.Equal(t, "owner/repo//review", configSource(".", "git@github.com:owner/repo.git//review"))
+	assert.Equal(t, "gitlab.com/org/team/repo", configSource(".", "https://gitlab.com/org/team/repo.git"))
+	assert.Equal(t, "file:///srv/repo", configSource(".", "file:///srv/repo"))
+	assert.Equal(t, "https://git.corp.com:8443/team/repo.git", configSource(".", "https://git.corp.com:8443/team/repo.git"))
+}
+
+func TestHostFromURL(t *testing.T) {
+	assert.Equal(t, "github.com", hostFromURL("https://github.com/owner/repo.git"))
+	assert.Equal(t, "github.com", hostFromURL("https://github.com:443/owner/repo.git"))
+	assert.Equal(t, "gitlab.com", hostFromURL("git@gitlab.com:group/project.git"))
+	assert.Equal(t, "git.corp.com", hostFromURL("ssh://git@git.corp.com/team/repo.git"))
+	assert.Empty(t, hostFromURL("repo"))
+}
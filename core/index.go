@@ -0,0 +1,236 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/gifflet/ccmd/internal/fs"
+)
+
+// IndexFileName is the file under .claude caching what list, search and status read
+// from each installed command
+const IndexFileName = ".ccmd-index.json"
+
+// indexVersion changes with the layout of the index; indexes of another version are
+// rebuilt
+const indexVersion = 1
+
+// indexMu serializes the index updates of installs running in parallel
+var indexMu sync.Mutex
+
+// fileStamp identifies the state of a file or directory by its modification time and size.
+// A missing file has the zero stamp.
+type fileStamp struct {
+	ModTime time.Time `json:"mtime"`
+	Size    int64     `json:"size"`
+}
+
+func stampOf(path string) fileStamp {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileStamp{}
+	}
+	return fileStamp{ModTime: info.ModTime(), Size: info.Size()}
+}
+
+func (s fileStamp) equal(other fileStamp) bool {
+	return s.ModTime.Equal(other.ModTime) && s.Size == other.Size
+}
+
+// indexEntry is what the index remembers of one installed command or plugin, valid while
+// the stamps of its directory, ccmd.yaml and standalone markdown file are unchanged
+type indexEntry struct {
+	Dir      fileStamp `json:"dir"`
+	Metadata fileStamp `json:"metadata"`
+	Markdown fileStamp `json:"markdown"`
+
+	// Metadata read from ccmd.yaml; empty when it is missing or invalid
+	Version     string   `json:"version,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Author      string   `json:"author,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	License     string   `json:"license,omitempty"`
+	Homepage    string   `json:"homepage,omitempty"`
+	Entry       string   `json:"entry,omitempty"`
+
+	// Size is the measured size of the directory, 0 until it is needed
+	Size int64 `json:"size,omitempty"`
+	// Issues are the structure problems of a command, as reported by Status
+	Issues []string `json:"issues,omitempty"`
+}
+
+func (e *indexEntry) current(stamps *indexEntry) bool {
+	return e.Dir.equal(stamps.Dir) && e.Metadata.equal(stamps.Metadata) && e.Markdown.equal(stamps.Markdown)
+}
+
+// commandIndex caches the metadata, size and structure of installed commands, so listing
+// hundreds of commands does not parse hundreds of ccmd.yaml files. Entries are rebuilt
+// one by one as the files they were read from change.
+type commandIndex struct {
+	Version  int                    `json:"version"`
+	Commands map[string]*indexEntry `json:"commands"`
+	Plugins  map[string]*indexEntry `json:"plugins,omitempty"`
+
+	path  string
+	dirty bool
+}
+
+// indexFilePath returns the path of a project's index
+func indexFilePath(projectRoot string) string {
+	return filepath.Join(projectRoot, ".claude", IndexFileName)
+}
+
+// loadIndex reads the index of a project. With fresh, or when the index is missing,
+// unreadable or of another version, it starts empty so every entry is rebuilt.
+func loadIndex(projectRoot string, fresh bool) *commandIndex {
+	index := &commandIndex{
+		Version:  indexVersion,
+		Commands: make(map[string]*indexEntry),
+		Plugins:  make(map[string]*indexEntry),
+		path:     indexFilePath(projectRoot),
+		dirty:    fresh,
+	}
+	if fresh {
+		return index
+	}
+
+	data, err := os.ReadFile(index.path)
+	if err != nil {
+		return index
+	}
+	var stored commandIndex
+	if err := json.Unmarshal(data, &stored); err != nil || stored.Version != indexVersion {
+		index.dirty = true
+		return index
+	}
+	if stored.Commands != nil {
+		index.Commands = stored.Commands
+	}
+	if stored.Plugins != nil {
+		index.Plugins = stored.Plugins
+	}
+	return index
+}
+
+// command returns the entry of an installed command, rebuilding it when the command
+// changed since it was indexed
+func (x *commandIndex) command(projectRoot, name string) *indexEntry {
+	dir := installedCommandDir(projectRoot, name)
+	markdown := claudeFile(projectRoot, name)
+	metadataPath := filepath.Join(dir, "ccmd.yaml")
+
+	stamps := &indexEntry{Dir: stampOf(dir), Metadata: stampOf(metadataPath), Markdown: stampOf(markdown)}
+	if cached := x.Commands[name]; cached != nil && cached.current(stamps) {
+		return cached
+	}
+
+	stamps.readMetadata(metadataPath)
+	structure, _ := checkCommandStructure(dir, markdown, fs.OS{})
+	stamps.Issues = structure.Issues
+	x.Commands[name] = stamps
+	x.dirty = true
+	return stamps
+}
+
+// plugin returns the entry of an installed plugin, rebuilding it when the plugin changed
+// since it was indexed
+func (x *commandIndex) plugin(projectRoot, name string) *indexEntry {
+	dir := filepath.Join(projectRoot, ".claude", "plugins", name)
+	metadataPath := filepath.Join(dir, "ccmd.yaml")
+
+	stamps := &indexEntry{Dir: stampOf(dir), Metadata: stampOf(metadataPath)}
+	if cached := x.Plugins[name]; cached != nil && cached.current(stamps) {
+		return cached
+	}
+
+	stamps.readMetadata(metadataPath)
+	x.Plugins[name] = stamps
+	x.dirty = true
+	return stamps
+}
+
+// readMetadata fills the metadata fields of an entry from a ccmd.yaml
+func (e *indexEntry) readMetadata(path string) {
+	if e.Dir.ModTime.IsZero() {
+		return
+	}
+	metadata, err := readCommandMetadata(path)
+	if err != nil {
+		return
+	}
+	e.Version = metadata.Version
+	e.Description = metadata.Description
+	e.Author = metadata.Author
+	e.Tags = metadata.Tags
+	e.License = metadata.License
+	e.Homepage = metadata.Homepage
+	e.Entry = metadata.Entry
+}
+
+// size returns the size of the directory of an entry, measuring it the first time
+func (x *commandIndex) size(entry *indexEntry, dir string) int64 {
+	if entry.Size == 0 && !entry.Dir.ModTime.IsZero() {
+		if size, err := dirSize(dir); err == nil && size > 0 {
+			entry.Size = size
+			x.dirty = true
+		}
+	}
+	return entry.Size
+}
+
+// prune drops the entries of commands and plugins the lock file no longer lists
+func (x *commandIndex) prune(lockFile *LockFile) {
+	for name := range x.Commands {
+		if _, ok := lockFile.Commands[name]; !ok {
+			delete(x.Commands, name)
+			x.dirty = true
+		}
+	}
+	for name := range x.Plugins {
+		if _, ok := lockFile.Plugins[name]; !ok {
+			delete(x.Plugins, name)
+			x.dirty = true
+		}
+	}
+}
+
+// save writes the index back when entries changed. The index is only a cache, so it is
+// not written into a project without a .claude directory or in no-write mode, and
+// failures are ignored.
+func (x *commandIndex) save() {
+	if !x.dirty || !dirExists(filepath.Dir(x.path)) || checkNoWrite(x.path) != nil {
+		return
+	}
+	data, err := json.MarshalIndent(x, "", "  ")
+	if err != nil {
+		return
+	}
+	if err := writeFileAtomic(x.path, data, 0644); err == nil {
+		x.dirty = false
+	}
+}
+
+// indexCommand indexes a command that was just installed, so the next listing does not
+// have to read it
+func indexCommand(projectRoot, name string) {
+	indexMu.Lock()
+	defer indexMu.Unlock()
+
+	index := loadIndex(projectRoot, false)
+	// Stamps of a reinstall within the timestamp resolution can look unchanged
+	delete(index.Commands, name)
+	index.command(projectRoot, name)
+	index.save()
+}
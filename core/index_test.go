@@ -0,0 +1,140 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeIndexedCommand installs a command with a ccmd.yaml describing it
+func writeIndexedCommand(t *testing.T, name, description string) {
+	createCommandStructure(t, name)
+	metadata := "name: " + name + "\nversion: 1.0.0\ndescription: " + description +
+		"\nauthor: Acme\nrepository: github.com/acme/" + name + "\nentry: index.md\n"
+	require.NoError(t, os.WriteFile(filepath.Join(".claude", "commands", name, "ccmd.yaml"), []byte(metadata), 0644))
+}
+
+// readIndex reads the index of the current directory
+func readIndex(t *testing.T) *commandIndex {
+	data, err := os.ReadFile(filepath.Join(".claude", IndexFileName))
+	require.NoError(t, err)
+	var index commandIndex
+	require.NoError(t, json.Unmarshal(data, &index))
+	return &index
+}
+
+func TestListUsesIndex(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+
+	lockFile := createBasicLockFile()
+	lockFile.Commands["review"] = createTestLockCommand("review", "1.0.0", "github.com/acme/review")
+	lockFile.Commands["deploy"] = createTestLockCommand("deploy", "1.0.0", "github.com/acme/deploy")
+	writeLockFile(t, lockFile)
+	writeIndexedCommand(t, "review", "Review changes")
+	writeIndexedCommand(t, "deploy", "Deploy the app")
+
+	commands, err := List(ListOptions{ProjectPath: "."})
+	require.NoError(t, err)
+	require.Len(t, commands, 2)
+	assert.Equal(t, "Review changes", commands[1].Description)
+
+	index := readIndex(t)
+	require.Contains(t, index.Commands, "review")
+	assert.Equal(t, "Review changes", index.Commands["review"].Description)
+	assert.Equal(t, "Acme", index.Commands["review"].Author)
+
+	// Unchanged commands are served from the index
+	index.Commands["review"].Description = "From the index"
+	data, err := json.Marshal(index)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(".claude", IndexFileName), data, 0644))
+
+	commands, err = List(ListOptions{ProjectPath: "."})
+	require.NoError(t, err)
+	assert.Equal(t, "From the index", commands[1].Description)
+
+	// --no-index reads the command again and rewrites the index
+	commands, err = List(ListOptions{ProjectPath: ".", NoIndex: true})
+	require.NoError(t, err)
+	assert.Equal(t, "Review changes", commands[1].Description)
+	assert.Equal(t, "Review changes", readIndex(t).Commands["review"].Description)
+
+	// A changed ccmd.yaml invalidates its entry
+	writeIndexedCommand(t, "review", "Review pull requests in depth")
+	commands, err = List(ListOptions{ProjectPath: "."})
+	require.NoError(t, err)
+	assert.Equal(t, "Review pull requests in depth", commands[1].Description)
+
+	// Commands removed from the lock file are dropped
+	delete(lockFile.Commands, "deploy")
+	writeLockFile(t, lockFile)
+	_, err = List(ListOptions{ProjectPath: "."})
+	require.NoError(t, err)
+	assert.NotContains(t, readIndex(t).Commands, "deploy")
+}
+
+func TestInstallIndexesCommand(t *testing.T) {
+	repo, _ := writeCommandRepo(t)
+	cleanup := setupTestDir(t)
+	defer cleanup()
+
+	_, _, err := Install(context.Background(), InstallOptions{Repository: repo})
+	require.NoError(t, err)
+
+	entry := readIndex(t).Commands["tool"]
+	require.NotNil(t, entry)
+	assert.Equal(t, "A tool", entry.Description)
+	assert.Empty(t, entry.Issues)
+}
+
+func TestStatusUsesIndex(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+
+	lockFile := createBasicLockFile()
+	lockFile.Commands["review"] = createTestLockCommand("review", "1.0.0", "github.com/acme/review")
+	writeLockFile(t, lockFile)
+	writeIndexedCommand(t, "review", "Review changes")
+
+	report, err := Status(StatusOptions{ProjectPath: "."})
+	require.NoError(t, err)
+	assert.Empty(t, report.Broken)
+	assert.Empty(t, readIndex(t).Commands["review"].Issues)
+
+	// Removing the standalone markdown file invalidates the entry
+	require.NoError(t, os.Remove(filepath.Join(".claude", "commands", "review.md")))
+	report, err = Status(StatusOptions{ProjectPath: "."})
+	require.NoError(t, err)
+	require.Len(t, report.Broken, 1)
+	assert.Equal(t, "Standalone markdown file is missing", report.Broken[0].Reason)
+	assert.Equal(t, []string{"Standalone markdown file is missing"}, readIndex(t).Commands["review"].Issues)
+}
+
+func TestLoadIndexRebuildsOtherVersions(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".claude"), 0755))
+	stale := `{"version": 0, "commands": {"review": {"description": "old"}}}`
+	require.NoError(t, os.WriteFile(indexFilePath(dir), []byte(stale), 0644))
+
+	index := loadIndex(dir, false)
+	assert.Empty(t, index.Commands)
+	assert.True(t, index.dirty)
+
+	require.NoError(t, os.WriteFile(indexFilePath(dir), []byte("not json"), 0644))
+	assert.Empty(t, loadIndex(dir, false).Commands)
+}
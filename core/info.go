@@ -14,6 +14,7 @@ import (
 	"errors"
 	"fmt"
 	"path/filepath"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 
@@ -36,7 +37,15 @@ type CommandInfo struct {
 	InstalledAt string            `json:"installed_at"`
 	UpdatedAt   string            `json:"updated_at"`
 	Metadata    map[string]string `json:"metadata,omitempty"`
-	Structure   StructureInfo     `json:"structure"`
+	// Overrides lists the files in .claude/overrides/<name> applied to the standalone files
+	Overrides []string `json:"overrides,omitempty"`
+	// Dependencies lists the commands the command's ccmd.yaml declares
+	Dependencies []string `json:"dependencies,omitempty"`
+	// Size is the installed size in bytes, from the lock file or measured on disk
+	Size int64 `json:"size,omitempty"`
+	// Dir is the directory the command is installed in
+	Dir       string        `json:"dir"`
+	Structure StructureInfo `json:"structure"`
 }
 
 // StructureInfo contains information about command structure integrity
@@ -68,11 +77,14 @@ func GetCommandDetails(commandName, projectPath string, filesystem fs.FileSystem
 		return nil, fmt.Errorf("command '%s' is not installed", commandName)
 	}
 
-	// Get base directory
-	baseDir := filepath.Join(projectPath, ".claude")
+	projectRoot, err := findProjectRootFrom(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	commandDir := installedCommandDir(projectRoot, commandName)
 
 	// Check structure and get metadata
-	structureInfo, metadata := checkCommandStructure(commandName, baseDir, filesystem)
+	structureInfo, metadata := checkCommandStructure(commandDir, claudeFile(projectRoot, commandName), filesystem)
 
 	// Build command info
 	info := &CommandInfo{
@@ -82,6 +94,8 @@ func GetCommandDetails(commandName, projectPath string, filesystem fs.FileSystem
 		Repository:  lockInfo.Repository,
 		InstalledAt: lockInfo.InstalledAt,
 		UpdatedAt:   lockInfo.UpdatedAt,
+		Size:        lockInfo.Size,
+		Dir:         commandDir,
 		Metadata:    make(map[string]string),
 		Structure:   structureInfo,
 	}
@@ -95,9 +109,17 @@ func GetCommandDetails(commandName, projectPath string, filesystem fs.FileSystem
 		info.Homepage = metadata.Homepage
 		info.Tags = metadata.Tags
 		info.Entry = metadata.Entry
-	} else if lockInfo.Description != "" {
+		info.Dependencies = metadata.Commands
+	} else {
 		// Fallback to lock file metadata
 		info.Description = lockInfo.Description
+		info.License = lockInfo.License
+	}
+
+	for _, file := range []string{OverrideIndexFile, OverrideAppendFile} {
+		if _, err := filesystem.Stat(filepath.Join(projectRoot, ".claude", "overrides", commandName, file)); err == nil {
+			info.Overrides = append(info.Overrides, file)
+		}
 	}
 
 	return info, nil
@@ -112,9 +134,9 @@ func FormatCommandInfoJSON(info *CommandInfo) (string, error) {
 	return string(data), nil
 }
 
-// ReadCommandContentPreview reads a preview of the command's index.md file
-func ReadCommandContentPreview(commandName, baseDir string, filesystem fs.FileSystem, lines int) (string, int, error) {
-	indexPath := filepath.Join(baseDir, "commands", commandName, "index.md")
+// ReadCommandContentPreview reads a preview of the index.md of the command in commandDir
+func ReadCommandContentPreview(commandDir string, filesystem fs.FileSystem, lines int) (string, int, error) {
+	indexPath := filepath.Join(commandDir, "index.md")
 
 	content, err := filesystem.ReadFile(indexPath)
 	if err != nil {
@@ -137,7 +159,19 @@ func ReadCommandContentPreview(commandName, baseDir string, filesystem fs.FileSy
 	return preview, len(allLines), nil
 }
 
-func checkCommandStructure(commandName, baseDir string, filesystem fs.FileSystem) (StructureInfo, *ProjectConfig) {
+// ReadCommandDocument reads the index.md of the command in commandDir without its front
+// matter
+func ReadCommandDocument(commandDir string, filesystem fs.FileSystem) (string, error) {
+	content, err := filesystem.ReadFile(filepath.Join(commandDir, "index.md"))
+	if err != nil {
+		return "", err
+	}
+	_, body, _ := splitFrontMatter(content)
+	return strings.TrimLeft(string(body), "\n"), nil
+}
+
+// checkCommandStructure checks an installed command directory and its standalone file
+func checkCommandStructure(commandDir, markdownFile string, filesystem fs.FileSystem) (StructureInfo, *ProjectConfig) {
 	info := StructureInfo{
 		DirectoryExists: false,
 		MarkdownExists:  false,
@@ -147,8 +181,6 @@ func checkCommandStructure(commandName, baseDir string, filesystem fs.FileSystem
 		Issues:          []string{},
 	}
 
-	commandDir := filepath.Join(baseDir, "commands", commandName)
-	markdownFile := filepath.Join(baseDir, "commands", commandName+".md")
 	ccmdYamlFile := filepath.Join(commandDir, "ccmd.yaml")
 	indexMdFile := filepath.Join(commandDir, "index.md")
 
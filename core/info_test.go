@@ -0,0 +1,53 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gifflet/ccmd/internal/fs"
+)
+
+func TestGetCommandDetails(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+
+	setupRegenCommand(t, "alpha")
+	commandDir := filepath.Join(".claude", "commands", "alpha")
+	require.NoError(t, writeCommandMetadata(filepath.Join(commandDir, ConfigFileName), &ProjectConfig{
+		Name:     "alpha",
+		Version:  "1.0.0",
+		Author:   "tester",
+		Entry:    "index.md",
+		Tags:     []string{"git"},
+		Commands: []string{"acme/helper@^1.0.0"},
+	}))
+	require.NoError(t, os.WriteFile(filepath.Join(commandDir, "index.md"),
+		[]byte("---\nallowed-tools: Bash\n---\n\n# Alpha\n\nDo it.\n"), 0644))
+
+	info, err := GetCommandDetails("alpha", ".", fs.OS{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"acme/helper@^1.0.0"}, info.Dependencies)
+	assert.Equal(t, []string{"git"}, info.Tags)
+	assert.Positive(t, info.Size)
+	assert.True(t, info.Structure.IsValid)
+
+	document, err := ReadCommandDocument(info.Dir, fs.OS{})
+	require.NoError(t, err)
+	assert.Equal(t, "# Alpha\n\nDo it.\n", document, "the front matter is left out")
+
+	_, err = ReadCommandDocument(filepath.Join(".claude", "commands", "missing"), fs.OS{})
+	assert.Error(t, err)
+}
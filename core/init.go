@@ -55,7 +55,7 @@ func InitDefaults(projectPath string) InitOptions {
 // LoadExistingConfig attempts to load existing ccmd.yaml and returns defaults
 func LoadExistingConfig(projectPath string) (InitOptions, interface{}, error) {
 	defaults := InitDefaults(projectPath)
-	ccmdPath := filepath.Join(projectPath, ConfigFileName)
+	ccmdPath := configFilePath(projectPath)
 
 	// Check if file exists
 	if _, err := os.Stat(ccmdPath); os.IsNotExist(err) {
@@ -139,7 +139,7 @@ func createOrderedConfig(opts InitOptions, existingCommands interface{}) ordered
 // InitProject creates a new ccmd project with the given options
 func InitProject(opts InitOptions) error {
 	// Create .claude/commands directory
-	claudeDir := filepath.Join(opts.ProjectPath, ".claude", "commands")
+	claudeDir := projectCommandsDir(opts.ProjectPath)
 	if err := os.MkdirAll(claudeDir, 0o750); err != nil {
 		return errors.FileError("create .claude directory", claudeDir, err)
 	}
@@ -166,7 +166,7 @@ func InitProject(opts InitOptions) error {
 // InitProjectWithCommands creates a project with existing commands preserved
 func InitProjectWithCommands(opts InitOptions, existingCommands interface{}) error {
 	// Create .claude/commands directory
-	claudeDir := filepath.Join(opts.ProjectPath, ".claude", "commands")
+	claudeDir := projectCommandsDir(opts.ProjectPath)
 	if err := os.MkdirAll(claudeDir, 0o750); err != nil {
 		return errors.FileError("create .claude directory", claudeDir, err)
 	}
@@ -181,7 +181,10 @@ func InitProjectWithCommands(opts InitOptions, existingCommands interface{}) err
 	}
 
 	// Write file
-	configPath := filepath.Join(opts.ProjectPath, ConfigFileName)
+	configPath := configFilePath(opts.ProjectPath)
+	if err := os.MkdirAll(filepath.Dir(configPath), 0o750); err != nil {
+		return errors.FileError("create config directory", filepath.Dir(configPath), err)
+	}
 	if err := os.WriteFile(configPath, data, 0o600); err != nil {
 		return errors.FileError("write config", configPath, err)
 	}
@@ -16,14 +16,20 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 
 	"gopkg.in/yaml.v3"
 
+	"github.com/gifflet/ccmd/internal/fs"
+	"github.com/gifflet/ccmd/pkg/config"
 	"github.com/gifflet/ccmd/pkg/errors"
 	"github.com/gifflet/ccmd/pkg/logger"
 	"github.com/gifflet/ccmd/pkg/output"
+	"github.com/gifflet/ccmd/pkg/repospec"
+	"github.com/gifflet/ccmd/pkg/telemetry"
 )
 
 // InstallOptions represents options for installing a command
@@ -33,80 +39,396 @@ type InstallOptions struct {
 	Commit     string // Specific commit to install (used when different from Version)
 	Name       string // Override command name (optional)
 	Force      bool   // Force reinstall if already exists
+
+	// ProjectPath is a path inside the project to install into; empty uses the working
+	// directory
+	ProjectPath string
+
+	// OverwriteLocal lets Force replace a command whose files were modified after install.
+	// Backup does the same but first copies the modified files to .claude/.backups.
+	OverwriteLocal bool
+	Backup         bool
+
+	// Rename is the name used when the command name is already taken by another
+	// repository or a hand-written command. Without it such an install fails.
+	Rename string
+	// PromptRename is asked for an alternative name on a conflict when Rename is empty.
+	// It receives the name and the source using it; returning "" aborts the install.
+	PromptRename func(name, conflict string) string
+
+	// Profile records the command under this ccmd.yaml profile instead of the shared
+	// commands list
+	Profile string
+
+	// As installs the command as a side-by-side instance under this name, next to other
+	// versions of the same repository. ccmd.yaml records it as "owner/repo@v1 as <name>".
+	As string
+
+	// Archive installs Repository as a release archive (.tar.gz, .tgz, .tar or .zip URL or
+	// file) without git. Sources with an archive extension are detected automatically.
+	Archive bool
+	// Checksum is the expected archive digest as sha256:<hex>; empty skips verification
+	Checksum string
+
+	// SaveStrategy selects the constraint written to ccmd.yaml: exact, caret or tilde.
+	// Empty uses the configured default (caret).
+	SaveStrategy string
+
+	// DefaultVersion selects what an install without a version installs: the newest
+	// stable tag (latest-release) or the head of the default branch (default-branch).
+	// Empty keeps what the lock file records for the command, or uses the default_version
+	// setting.
+	DefaultVersion string
+
+	// Pre lets the latest version and constraints resolve to prerelease tags, as for
+	// ccmd.yaml entries following the beta channel
+	Pre bool
+
+	// NoSubmodules skips the git submodules of repositories with a .gitmodules file,
+	// which are otherwise checked out recursively
+	NoSubmodules bool
+
+	// NoSave installs without recording the command in ccmd.yaml. The lock file marks it
+	// ephemeral until a sync adopts or prunes it, or an install without NoSave saves it.
+	NoSave bool
+
+	// Strict refuses commands whose metadata is incomplete, see checkStrictMetadata. The
+	// strict_metadata setting turns it on for every install.
+	Strict bool
+
+	// Progress receives the steps of the install instead of the progress messages
+	// printed otherwise. Warnings are still printed.
+	Progress ProgressFunc
+
+	progress       *progressReporter // reports the steps to Progress, set by install
+	saveVersion    string            // version recorded in ccmd.yaml, set by resolveInstallVersion
+	defaultVersion string            // how the version was picked when none was given, recorded in the lock
+	archiveDigest  string            // sha256 of the installed archive or markdown file, recorded as the lock commit
+	checkedOut     string            // full SHA of the verified git checkout, recorded as the lock commit
+	branch         string            // branch of the checkout, recorded in the lock
+	submodules     map[string]string // commits of the checked out submodules, recorded in the lock
+	tags           *tagCache         // remote tag lists shared by the installs of a sync
+	frozen         bool              // install the locked entry as is, leaving ccmd.yaml and ccmd-lock.yaml untouched
+}
+
+// Install installs a command from a Git repository and records it in the audit log
+func Install(ctx context.Context, opts InstallOptions) (string, bool, error) {
+	if start, err := startDir(opts.ProjectPath); err == nil {
+		if root, err := LocateProjectRoot(start); err == nil {
+			if root.Source == RootGit || root.Source == RootWorkDir {
+				output.PrintInfof("Installing into %s; pass --project to choose another directory", root.Describe())
+			}
+			defer snapshotBefore(root.Dir, AuditInstall)()
+		}
+	}
+
+	ctx, span := telemetry.Start(ctx, "install", repoHostAttr(opts.Repository))
+	name, isPlugin, err := install(ctx, opts)
+	span.SetAttributes(telemetry.Bool("plugin", isPlugin))
+	span.End(err)
+
+	var multi *MultiCommandError
+	if !stderrors.As(err, &multi) {
+		// Nothing was installed yet; the selected commands are audited on their own
+		auditInstall(AuditInstall, opts, name, "", err)
+	}
+	return name, isPlugin, err
+}
+
+// auditInstall records an install or update. The installed version and commit come
+// from the lock file; a failed install records the requested version.
+func auditInstall(action string, opts InstallOptions, name, previousVersion string, err error) {
+	projectRoot, rootErr := findProjectRootFrom(opts.ProjectPath)
+	if rootErr != nil || opts.Repository == "" {
+		return
+	}
+
+	repo, version := ParseRepositorySpec(opts.Repository)
+	if opts.Version != "" {
+		version = opts.Version
+	}
+
+	event := AuditEvent{
+		Action:          action,
+		Name:            name,
+		Repository:      normalizeRepositoryURL(projectRoot, repo),
+		Version:         version,
+		PreviousVersion: previousVersion,
+	}
+	if err == nil {
+		event = auditLockEntry(projectRoot, event)
+	}
+	recordAudit(projectRoot, auditResult(event, err))
+}
+
+// install installs a command and reports its steps to opts.Progress
+func install(ctx context.Context, opts InstallOptions) (string, bool, error) {
+	opts.progress = newProgressReporter(opts.Progress, opts.Repository)
+	if opts.progress.enabled() {
+		ctx = withCloneProgress(ctx, opts.progress.cloneProgress)
+	}
+
+	name, isPlugin, err := installSource(ctx, opts)
+	if err != nil {
+		opts.progress.fail(err)
+	}
+	return name, isPlugin, err
 }
 
-// Install installs a command from a Git repository
-func Install(_ context.Context, opts InstallOptions) (string, bool, error) {
+func installSource(ctx context.Context, opts InstallOptions) (string, bool, error) {
 	log := logger.New()
 
 	if opts.Repository == "" {
 		return "", false, errors.InvalidInput("repository URL is required")
 	}
 
-	repo, version := ParseRepositorySpec(opts.Repository)
-	if version != "" && opts.Version == "" {
-		opts.Version = version
+	if err := ValidateSaveStrategy(opts.SaveStrategy); err != nil {
+		return "", false, err
+	}
+	if err := ValidateDefaultVersion(opts.DefaultVersion); err != nil {
+		return "", false, err
+	}
+	if opts.As != "" {
+		if err := validateCommandName(opts.As); err != nil {
+			return "", false, err
+		}
+	}
+	if opts.NoSave && opts.Profile != "" {
+		return "", false, errors.InvalidInput("--no-save cannot be combined with --profile")
 	}
-	opts.Repository = repo
 
-	repoURL := NormalizeRepositoryURL(opts.Repository)
+	projectRoot, err := findProjectRootFrom(opts.ProjectPath)
+	if err != nil {
+		return "", false, errors.FileError("find project root", "", err)
+	}
+	ctx = withProjectRoot(ctx, projectRoot)
+
+	isArchive := opts.Archive || IsArchiveSource(opts.Repository)
+	isMarkdown := !isArchive && IsMarkdownSource(opts.Repository)
+	repoURL := opts.Repository
+	pin := ""
+	if isArchive || isMarkdown {
+		// The URL identifies the version; there is no ref to resolve
+		opts.Version = ""
+	} else {
+		// Malformed specs are reported here rather than as a failed clone
+		if _, err := repospec.Parse(opts.Repository); err != nil {
+			return "", false, err
+		}
+		repo, version := ParseRepositorySpec(opts.Repository)
+		if version != "" && opts.Version == "" {
+			opts.Version = version
+		}
+		// sha1:<hash> and sha256:<hash> pins clone the plain hash and stay as written in ccmd.yaml
+		commit, pinned, err := parseCommitPin(opts.Version)
+		if err != nil {
+			return "", false, err
+		}
+		if pinned && commit != opts.Version {
+			pin = opts.Version
+			opts.Version = commit
+		}
+		// <tap>/<command> installs the repository the tap lists
+		if repo, err = resolveTapCommand(ctx, projectRoot, repo); err != nil {
+			return "", false, err
+		}
+		opts.Repository = repo
+		repoURL = normalizeRepositoryURL(projectRoot, opts.Repository)
+	}
 	log.WithField("repository", repoURL).Debug("Installing command")
+	opts.progress.repository = repoURL
 
-	projectRoot, err := findProjectRoot()
+	paths, err := ResolvePaths(projectRoot)
 	if err != nil {
-		return "", false, errors.FileError("find project root", "", err)
+		return "", false, err
 	}
+	commandsDir := paths.Commands
 
-	ccmdDir := filepath.Join(projectRoot, ".claude")
-	commandsDir := filepath.Join(ccmdDir, "commands")
+	targets, err := projectTargets(projectRoot)
+	if err != nil {
+		return "", false, err
+	}
 
 	if err := os.MkdirAll(commandsDir, 0755); err != nil {
 		return "", false, errors.FileError("create commands directory", commandsDir, err)
 	}
 
-	tempDir, err := os.MkdirTemp("", "ccmd-install-*")
+	tempDir, removeTempDir, err := newTempDir(projectRoot, "install")
 	if err != nil {
-		return "", false, errors.FileError("create temp directory", "", err)
+		return "", false, err
 	}
-	defer os.RemoveAll(tempDir)
+	defer removeTempDir()
 
-	output.PrintInfof("Cloning repository %s...", repoURL)
-	cloneVersion := opts.Version
-	if opts.Commit != "" {
-		cloneVersion = opts.Commit
+	checksum := opts.Checksum
+	if checksum == "" && strings.HasPrefix(opts.Commit, "sha256:") {
+		// Reinstalls from ccmd-lock.yaml must get the same archive or file
+		checksum = opts.Commit
 	}
-	if err := gitClone(repoURL, tempDir, cloneVersion); err != nil {
-		return "", false, errors.GitError("clone", err)
+
+	sourceDir := tempDir
+	switch {
+	case isMarkdown:
+		opts.progress.report(StageCloneStarted, "Downloading %s...", repoURL)
+		digest, err := fetchMarkdown(ctx, repoURL, checksum, tempDir)
+		if ctx.Err() != nil {
+			return "", false, ctx.Err()
+		}
+		if err != nil {
+			return "", false, err
+		}
+		opts.archiveDigest = digest
+	case isArchive:
+		opts.progress.report(StageCloneStarted, "Downloading archive %s...", repoURL)
+		root, digest, err := fetchArchive(ctx, repoURL, checksum, tempDir)
+		if ctx.Err() != nil {
+			return "", false, ctx.Err()
+		}
+		if err != nil {
+			return "", false, err
+		}
+		sourceDir = root
+		opts.archiveDigest = digest
+	default:
+		if err := resolveInstallVersion(ctx, projectRoot, repoURL, &opts); err != nil {
+			return "", false, err
+		}
+		if pin != "" {
+			opts.saveVersion = pin
+		}
+
+		// owner/repo//command clones the repository and installs commands/<command>
+		cloneURL, command := splitRepositoryCommand(repoURL)
+		opts.progress.report(StageCloneStarted, "Cloning repository %s...", cloneURL)
+		if mirrored := mirrorFor(configuredMirrors(), cloneURL); mirrored != "" {
+			output.PrintVerbosef("Fetching %s from mirror %s", cloneURL, mirrored)
+		}
+		cloneVersion := opts.Version
+		if opts.Commit != "" {
+			cloneVersion = opts.Commit
+		}
+		if cloneVersion == "" {
+			cloneVersion = hostResolverFor(projectRoot).hostConfig(cloneURL).DefaultBranch
+		}
+		// Only the blobs of the checked out files are fetched, and of a multi-command
+		// repository only the directory of the command
+		cloneOpts := CloneOptions{Partial: true}
+		if command != "" {
+			cloneOpts.SparsePaths = []string{RepositoryCommandsDir + "/" + command}
+		}
+		if err := newGitClient(projectRoot).Checkout(ctx, cloneURL, cloneVersion, tempDir, cloneOpts); err != nil {
+			if ctx.Err() != nil {
+				return "", false, ctx.Err()
+			}
+			var missing *CommitNotFoundError
+			if stderrors.As(err, &missing) {
+				return "", false, missing
+			}
+			return "", false, errors.GitError("clone", err)
+		}
+		commit, err := gitVerifyCheckout(tempDir, cloneVersion)
+		if err != nil {
+			return "", false, errors.GitError("verify checkout", err)
+		}
+		opts.checkedOut = commit
+		if opts.Commit == "" {
+			// Set when the version named a branch, or a repository without tags was
+			// installed from its default branch
+			opts.branch, _ = gitCurrentBranch(tempDir)
+		}
+
+		if !opts.NoSubmodules && hasSubmodules(tempDir) {
+			opts.progress.report(StageCloneProgress, "Fetching submodules of %s...", cloneURL)
+			if err := gitUpdateSubmodules(ctx, tempDir); err != nil {
+				if ctx.Err() != nil {
+					return "", false, ctx.Err()
+				}
+				return "", false, errors.GitError("submodule update", err)
+			}
+			if opts.submodules, err = gitSubmoduleCommits(tempDir); err != nil {
+				return "", false, errors.GitError("submodule status", err)
+			}
+			// Lock files written before submodules were pinned have nothing to check
+			if pinned := lockedSubmodules(projectRoot, opts.Rename); opts.frozen && len(pinned) > 0 {
+				if err := checkSubmodulePins(pinned, opts.submodules); err != nil {
+					return "", false, err
+				}
+			}
+		}
+
+		if command != "" {
+			if sourceDir, err = repositoryCommandDir(tempDir, opts.Repository, command); err != nil {
+				return "", false, err
+			}
+		} else if commands := repositoryCommands(tempDir); len(commands) > 0 {
+			return "", false, &MultiCommandError{
+				Repository:  opts.Repository,
+				Commands:    commands,
+				version:     opts.Version,
+				saveVersion: opts.saveVersion,
+				commit:      commit,
+			}
+		}
 	}
 
-	metadataPath := filepath.Join(tempDir, "ccmd.yaml")
-	metadata, err := readCommandMetadata(metadataPath)
+	if err := ctx.Err(); err != nil {
+		return "", false, err
+	}
+
+	opts.progress.report(StageValidating, "Validating %s...", repoURL)
+	metadata, err := readSourceMetadata(sourceDir)
 	if err != nil {
 		return "", false, err
 	}
+	if opts.Strict || strictMetadata(projectRoot) {
+		if err := checkStrictMetadata(repoURL, metadata); err != nil {
+			return "", false, err
+		}
+	}
+	if err := checkLicense(projectRoot, repoURL, metadata); err != nil {
+		return "", false, err
+	}
+	warnInstallSize(projectRoot, repoURL, sourceDir)
+	if err := scanSource(projectRoot, repoURL, sourceDir); err != nil {
+		return "", false, err
+	}
 
 	if repoType(metadata) == "plugin" {
-		name, err := installPlugin(projectRoot, tempDir, metadata, opts)
+		if opts.As != "" {
+			return "", false, errors.InvalidInput("plugins cannot be installed side by side with --as")
+		}
+		name, err := installPlugin(ctx, projectRoot, sourceDir, metadata, opts)
 		return name, true, err
 	}
 
 	commandName := opts.Name
+	if opts.As != "" {
+		commandName = opts.As
+	}
 	if commandName == "" {
 		commandName = metadata.Name
 		if commandName == "" {
 			commandName = extractCommandName(repoURL)
 		}
+		// Derived names must be valid file names everywhere, e.g. "aux" becomes "aux-cmd"
+		commandName = fs.SanitizeName(commandName)
 	}
 
-	if err := validateCommandName(commandName); err != nil {
+	if err := validateQualifiedName(commandName); err != nil {
 		return "", false, err
 	}
+	opts.progress.name = commandName
 
 	targetRepoPath := ExtractRepoPath(repoURL)
-	existingCommand, err := findExistingCommandByRepo(projectRoot, targetRepoPath)
+	existingCommand, err := findExistingCommandByRepo(projectRoot, targetRepoPath, opts.As)
 	if err != nil {
 		return "", false, errors.FileError("check existing commands", "", err)
 	}
+	if opts.As != "" {
+		if regular, _ := findExistingCommandByRepo(projectRoot, targetRepoPath, ""); regular == opts.As {
+			return "", false, errors.Conflict(fmt.Sprintf(
+				"command %q is the regular install of this repository, choose another --as name", opts.As))
+		}
+	}
 
 	if existingCommand != "" && !opts.Force {
 		return "", false, errors.AlreadyExists(fmt.Sprintf(
@@ -114,21 +436,79 @@ func Install(_ context.Context, opts InstallOptions) (string, bool, error) {
 			existingCommand))
 	}
 
+	// The nested layout qualifies derived names with the owner. Commands installed
+	// before the layout changed keep their name until 'ccmd migrate-layout' moves them.
+	layout := layoutSettings(projectRoot)
+	if opts.Name == "" && opts.As == "" && layout.Commands == config.LayoutNested &&
+		(existingCommand == "" || strings.Contains(existingCommand, "/")) {
+		if err := validateLayout(layout); err != nil {
+			return "", false, err
+		}
+		if owner := repositoryOwner(repoURL); owner != "" {
+			commandName = QualifiedName(owner, commandName)
+		}
+	}
+
+	if opts.Rename == "" && existingCommand != "" {
+		// Reinstalls and updates keep a name chosen to resolve an earlier conflict
+		opts.Rename = existingCommand
+	}
+	commandName, err = resolveNameConflict(projectRoot, commandName, targetRepoPath, opts)
+	if err != nil {
+		return "", false, err
+	}
+
 	commandNameChanged := existingCommand != "" && existingCommand != commandName
+	opts.progress.name = commandName
+
+	if opts.Force && existingCommand != "" {
+		if err := protectLocalChanges(projectRoot, existingCommand, opts.OverwriteLocal, opts.Backup); err != nil {
+			return "", false, err
+		}
+	}
+
+	// Reinstalls keep local overrides and standalone files edited by hand
+	standalone := make(map[string]*LockStandalone)
+	if existingCommand != "" && !commandNameChanged {
+		standalone = lockedStandalone(projectRoot, existingCommand)
+	}
 
 	if opts.Force {
-		output.PrintInfof("Removing previous installation %q...", existingCommand)
-		if err := removeCommandFiles(projectRoot, existingCommand); err != nil {
+		opts.progress.report(StageCopying, "Removing previous installation %q...", existingCommand)
+		if err := removeCommandFiles(projectRoot, existingCommand, standalone); err != nil {
 			return "", false, err
 		}
 	}
 
 	destDir := filepath.Join(commandsDir, commandName)
 
-	output.PrintInfof("Installing command %q...", commandName)
-	if err := copyDirectory(tempDir, destDir); err != nil {
+	opts.progress.report(StageCopying, "Installing command %q...", commandName)
+	installedFiles, skipped, err := copyCommandFiles(ctx, sourceDir, destDir, metadata)
+	if err != nil {
+		fs.RemoveAll(destDir)
+		if ctx.Err() != nil {
+			return "", false, ctx.Err()
+		}
 		return "", false, errors.FileError("copy command files", destDir, err)
 	}
+	if skipped > 0 {
+		opts.progress.report(StageCopying, "Left out %d file(s) excluded by the files filter", skipped)
+	}
+
+	assets, err := installAssets(ctx, destDir, metadata.Assets)
+	if err != nil {
+		fs.RemoveAll(destDir)
+		if ctx.Err() != nil {
+			return "", false, ctx.Err()
+		}
+		return "", false, err
+	}
+	for path := range assets {
+		if !slices.Contains(installedFiles, path) {
+			installedFiles = append(installedFiles, path)
+		}
+	}
+	sort.Strings(installedFiles)
 
 	originalVersion := metadata.Version
 
@@ -136,35 +516,111 @@ func Install(_ context.Context, opts InstallOptions) (string, bool, error) {
 	metadata.Repository = repoURL
 
 	if err := writeCommandMetadata(filepath.Join(destDir, "ccmd.yaml"), metadata); err != nil {
-		os.RemoveAll(destDir)
+		fs.RemoveAll(destDir)
 		return "", false, err
 	}
 
-	standalonePath := filepath.Join(ccmdDir, "commands", commandName+".md")
-	if err := createStandaloneDoc(destDir, standalonePath, metadata); err != nil {
+	standaloneFiles, edited, err := writeTargets(projectRoot, destDir, targets, metadata, standalone, leaveEdits)
+	if err != nil {
 		log.WithError(err).Warn("Failed to create standalone documentation")
 	}
+	for _, file := range edited {
+		output.PrintWarningf("Left %s, which was edited by hand; run 'ccmd regen %s' to keep or discard the edits",
+			file, commandName)
+	}
+
+	// Last point to back out: once the lock file is written the install is completed
+	if err := ctx.Err(); err != nil {
+		fs.RemoveAll(destDir)
+		for _, path := range standaloneFiles {
+			os.Remove(path)
+		}
+		return "", false, err
+	}
 
-	if err := updateLockFile(projectRoot, commandName, metadata, originalVersion, opts.Version); err != nil {
+	indexCommand(projectRoot, commandName)
+
+	if opts.frozen {
+		opts.progress.report(StageDone, "Command %q installed successfully", commandName)
+		return commandName, false, nil
+	}
+
+	ephemeral := opts.NoSave && !listedInConfig(projectRoot, opts.Repository, opts.As)
+	if err := updateLockFile(projectRoot, commandName, metadata, originalVersion, opts.Version, opts.As != ""); err != nil {
 		log.WithError(err).Warn("Failed to update lock file")
+	} else {
+		if opts.archiveDigest != "" {
+			if err := recordLockCommit(projectRoot, commandName, false, opts.archiveDigest); err != nil {
+				log.WithError(err).Warn("Failed to record archive checksum")
+			}
+		} else if opts.checkedOut != "" {
+			if err := recordLockCommit(projectRoot, commandName, false, opts.checkedOut); err != nil {
+				log.WithError(err).Warn("Failed to record commit")
+			}
+		}
+		if opts.branch != "" {
+			if err := recordBranch(projectRoot, commandName, opts.branch); err != nil {
+				log.WithError(err).Warn("Failed to record branch")
+			}
+		}
+		if opts.defaultVersion != "" {
+			if err := recordDefaultVersion(projectRoot, commandName, opts.defaultVersion); err != nil {
+				log.WithError(err).Warn("Failed to record default version")
+			}
+		}
+		if len(opts.submodules) > 0 {
+			if err := recordSubmodules(projectRoot, commandName, opts.submodules); err != nil {
+				log.WithError(err).Warn("Failed to record submodule commits")
+			}
+		}
+		if len(assets) > 0 {
+			if err := recordAssets(projectRoot, commandName, assets); err != nil {
+				log.WithError(err).Warn("Failed to record assets")
+			}
+		}
+		if skipped > 0 {
+			if err := recordInstalledFiles(projectRoot, commandName, installedFiles); err != nil {
+				log.WithError(err).Warn("Failed to record installed files")
+			}
+		}
+		if err := recordStandaloneFiles(projectRoot, commandName, standalone); err != nil {
+			log.WithError(err).Warn("Failed to record standalone files")
+		}
+		if err := recordEphemeral(projectRoot, commandName, false, ephemeral); err != nil {
+			log.WithError(err).Warn("Failed to record ephemeral install")
+		}
+		opts.progress.report(StageLockUpdated, "Recorded %q in %s", commandName, LockFileName)
+	}
+
+	if ephemeral {
+		opts.progress.report(StageDone, "Command %q installed without saving it to %s", commandName, ConfigFileName)
+		if !opts.progress.enabled() {
+			output.PrintInfof("Run 'ccmd sync --adopt' to add it to %s or 'ccmd sync --prune-ephemeral' to remove it", ConfigFileName)
+		}
+		return commandName, false, nil
+	}
+	if opts.NoSave {
+		opts.progress.report(StageDone, "Command %q installed successfully", commandName)
+		return commandName, false, nil
 	}
 
 	repoSpec := opts.Repository
-	if strings.Contains(repoSpec, "://") || strings.HasPrefix(repoSpec, "git@") {
-		repoSpec = ExtractRepoPath(repoSpec)
+	if !isArchive && !isMarkdown {
+		repoSpec = configSource(projectRoot, repoSpec)
 	}
-	versionForConfig := opts.Version
-	if isCommitHash(versionForConfig) && len(versionForConfig) > 7 {
-		versionForConfig = versionForConfig[:7]
+	if opts.Profile != "" || opts.As != "" {
+		err = addToProfile(projectRoot, opts.Profile, formatCommandSpec(repoSpec, configVersion(opts), opts.As))
+	} else {
+		err = addToConfig(projectRoot, commandName, repoSpec, configVersion(opts))
 	}
-	if err := addToConfig(projectRoot, commandName, repoSpec, versionForConfig); err != nil {
+	if err != nil {
 		log.WithError(err).Warn("Failed to update ccmd.yaml")
 	}
 
 	if commandNameChanged {
-		output.PrintSuccessf("Installed command %q renamed to %q successfully", existingCommand, commandName)
+		opts.progress.report(StageDone, "Installed command %q renamed to %q successfully", existingCommand, commandName)
 	} else {
-		output.PrintSuccessf("Command %q installed successfully", commandName)
+		opts.progress.report(StageDone, "Command %q installed successfully", commandName)
 	}
 
 	return commandName, false, nil
@@ -172,87 +628,157 @@ func Install(_ context.Context, opts InstallOptions) (string, bool, error) {
 
 // InstallFromConfig installs all commands and plugins from project's ccmd.yaml
 func InstallFromConfig(ctx context.Context, projectPath string, force bool) error {
+	return InstallProfile(ctx, projectPath, ConfigInstallOptions{Force: force})
+}
+
+// ConfigInstallOptions represents options for installing everything listed in ccmd.yaml
+type ConfigInstallOptions struct {
+	Profile        string // Also install the commands of this profile
+	Force          bool   // Reinstall commands that are already installed
+	OverwriteLocal bool   // Reinstall commands with local modifications
+	Backup         bool   // Back up local modifications before reinstalling
+	// Frozen installs exactly what ccmd-lock.yaml records and fails when it does not match
+	// ccmd.yaml, without writing either file
+	Frozen bool
+	// Strict refuses commands with incomplete metadata
+	Strict bool
+}
+
+// InstallProfile installs the shared commands and plugins from the project's ccmd.yaml
+// plus the commands of a profile. An empty profile installs the shared ones only.
+func InstallProfile(ctx context.Context, projectPath string, configOpts ConfigInstallOptions) error {
 	config, err := LoadProjectConfig(projectPath)
 	if err != nil {
 		return err
 	}
 
-	if len(config.Commands) == 0 && len(config.Plugins) == 0 {
+	commands, err := config.CommandsForProfile(configOpts.Profile)
+	if err != nil {
+		return err
+	}
+
+	if configOpts.Frozen {
+		if err := VerifyLock(projectPath, configOpts.Profile); err != nil {
+			return err
+		}
+	}
+
+	plugins := config.ActivePlugins()
+	if len(commands) == 0 && len(plugins) == 0 {
 		output.PrintInfof("No commands found in ccmd.yaml")
 		return nil
 	}
 
-	lockPath := filepath.Join(projectPath, LockFileName)
+	lockPath := lockFilePath(projectPath)
 	var lockFile *LockFile
 	if fileExists(lockPath) {
 		lockFile, _ = ReadLockFile(lockPath)
 	}
 
-	var installErrors []error
+	var failures []*errors.Failure
 
-	for _, cmdSpec := range config.Commands {
-		repo, version := ParseCommandSpec(cmdSpec)
-		commitToInstall := resolveCommitFromLock(lockFile, repo, false)
+	for _, cmdSpec := range commands {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		repo, version, instance := ParseInstanceSpec(cmdSpec)
 
 		opts := InstallOptions{
 			Repository: repo,
 			Version:    version,
-			Commit:     commitToInstall,
-			Force:      force,
+			As:         instance,
+			Force:      configOpts.Force,
+
+			OverwriteLocal: configOpts.OverwriteLocal,
+			Backup:         configOpts.Backup,
 		}
+		if instance == "" {
+			opts.Commit = resolveCommitFromLock(projectPath, lockFile, repo, false)
+			opts.Rename = resolveNameFromLock(projectPath, lockFile, repo)
+		} else if _, locked := lockedCommand(lockFile, repo, instance); locked != nil {
+			opts.Commit = locked.Commit
+		}
+		if configOpts.Frozen {
+			if name, locked := lockedCommand(lockFile, repo, instance); locked != nil {
+				opts = frozenInstallOptions(name, locked.Source, locked.Resolved, locked.Commit)
+				opts.As = instance
+				opts.Force = configOpts.Force
+				opts.OverwriteLocal = configOpts.OverwriteLocal
+				opts.Backup = configOpts.Backup
+			}
+		}
+		opts.Strict = configOpts.Strict
+		opts.ProjectPath = projectPath
 
 		output.PrintInfof("Installing %s...", cmdSpec)
 		if _, _, err := Install(ctx, opts); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
 			if stderrors.Is(err, errors.ErrAlreadyExists) {
 				output.PrintWarningf("%s already installed, use --force to reinstall", repo)
 			} else {
-				installErrors = append(installErrors, fmt.Errorf("%s: %w", repo, err))
+				failures = append(failures, &errors.Failure{Name: cmdSpec, Repo: repo, Stage: "install", Err: err})
 				output.PrintErrorf("Failed to install %s: %v", repo, err)
 			}
 		}
 	}
 
-	for _, pluginSpec := range config.Plugins {
+	for _, pluginSpec := range plugins {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
 		repo, version := ParseCommandSpec(pluginSpec)
-		commitToInstall := resolveCommitFromLock(lockFile, repo, true)
+		commitToInstall := resolveCommitFromLock(projectPath, lockFile, repo, true)
 
 		opts := InstallOptions{
 			Repository: repo,
 			Version:    version,
 			Commit:     commitToInstall,
-			Force:      force,
+			Force:      configOpts.Force,
 		}
+		if configOpts.Frozen {
+			if name, locked := lockedPlugin(lockFile, repo); locked != nil {
+				opts = frozenInstallOptions(name, locked.Source, locked.Resolved, locked.Commit)
+				opts.Name = name
+				opts.Force = configOpts.Force
+			}
+		}
+		opts.Strict = configOpts.Strict
+		opts.ProjectPath = projectPath
 
 		output.PrintInfof("Installing plugin %s...", pluginSpec)
 		if _, _, err := Install(ctx, opts); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
 			if stderrors.Is(err, errors.ErrAlreadyExists) {
 				output.PrintWarningf("plugin %s already installed, use --force to reinstall", repo)
 			} else {
-				installErrors = append(installErrors, fmt.Errorf("%s: %w", repo, err))
+				failures = append(failures, &errors.Failure{Name: pluginSpec, Repo: repo, Stage: "install plugin", Err: err})
 				output.PrintErrorf("Failed to install plugin %s: %v", repo, err)
 			}
 		}
 	}
 
-	if len(installErrors) > 0 {
-		return fmt.Errorf("failed to install %d package(s)", len(installErrors))
-	}
-
-	return nil
+	return errors.Batch(fmt.Sprintf("failed to install %d of %d package(s)", len(failures), len(commands)+len(plugins)),
+		failures)
 }
 
 // resolveCommitFromLock finds the locked commit hash for a given repo spec.
 // When isPlugin is true, it searches the Plugins map; otherwise Commands.
-func resolveCommitFromLock(lockFile *LockFile, repo string, isPlugin bool) string {
+func resolveCommitFromLock(projectRoot string, lockFile *LockFile, repo string, isPlugin bool) string {
 	if lockFile == nil {
 		return ""
 	}
 
-	normalizedRepo := NormalizeRepositoryURL(repo)
+	normalizedRepo := normalizeRepositoryURL(projectRoot, repo)
 
 	if isPlugin {
 		for _, lockPlugin := range lockFile.Plugins {
-			if NormalizeRepositoryURL(lockPlugin.Source) == normalizedRepo {
+			if normalizeRepositoryURL(projectRoot, lockPlugin.Source) == normalizedRepo {
 				return lockPlugin.Commit
 			}
 		}
@@ -260,7 +786,7 @@ func resolveCommitFromLock(lockFile *LockFile, repo string, isPlugin bool) strin
 	}
 
 	for _, lockCmd := range lockFile.Commands {
-		if NormalizeRepositoryURL(lockCmd.Source) == normalizedRepo {
+		if !lockCmd.Instance && normalizeRepositoryURL(projectRoot, lockCmd.Source) == normalizedRepo {
 			return lockCmd.Commit
 		}
 	}
@@ -268,6 +794,23 @@ func resolveCommitFromLock(lockFile *LockFile, repo string, isPlugin bool) strin
 	return ""
 }
 
+// resolveNameFromLock finds the command name a repo spec was installed under, so commands
+// renamed to resolve a conflict keep their name when installed again
+func resolveNameFromLock(projectRoot string, lockFile *LockFile, repo string) string {
+	if lockFile == nil {
+		return ""
+	}
+
+	normalizedRepo := normalizeRepositoryURL(projectRoot, repo)
+	for name, lockCmd := range lockFile.Commands {
+		if !lockCmd.Instance && normalizeRepositoryURL(projectRoot, lockCmd.Source) == normalizedRepo {
+			return name
+		}
+	}
+
+	return ""
+}
+
 // Helper functions
 
 func readCommandMetadata(path string) (*ProjectConfig, error) {
@@ -317,29 +860,33 @@ func validateCommandName(name string) error {
 		return errors.InvalidInput("command name cannot be empty")
 	}
 
-	if strings.ContainsAny(name, "/\\:*?\"<>|") {
-		return errors.InvalidInput("command name contains invalid characters")
-	}
-
-	return nil
+	return fs.ValidateName(name)
 }
 
-func copyDirectory(src, dst string) error {
+// copyDirectory copies src into dst, stopping with the context error when ctx is cancelled
+func copyDirectory(ctx context.Context, src, dst string) error {
 	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 
 		relPath, err := filepath.Rel(src, path)
 		if err != nil {
 			return err
 		}
 
-		dstPath := filepath.Join(dst, relPath)
+		dstPath := fs.LongPath(filepath.Join(dst, relPath))
 
 		if info.IsDir() {
 			return os.MkdirAll(dstPath, info.Mode())
 		}
+		if !info.Mode().IsRegular() {
+			// Links are not followed, they could point anywhere on the machine
+			return nil
+		}
 
 		return copyFile(path, dstPath, info.Mode())
 	})
@@ -362,20 +909,23 @@ func copyFile(src, dst string, mode os.FileMode) error {
 		return err
 	}
 
-	return os.Chmod(dst, mode)
+	// Keep copies writable by the owner: read-only files (as git leaves some) could not
+	// be replaced by a later update or removed on Windows
+	return os.Chmod(dst, mode|0o200)
 }
 
-func createStandaloneDoc(commandDir, standalonePath string, metadata *ProjectConfig) error {
-	indexPath := filepath.Join(commandDir, "index.md")
-	if !fileExists(indexPath) {
-		return errors.NotFound("index.md not found")
-	}
-
-	content, err := os.ReadFile(indexPath)
+// renderStandaloneDoc builds the standalone <name>.md content from a command's index.md
+// and its local overrides
+func renderStandaloneDoc(commandDir string, metadata *ProjectConfig) ([]byte, error) {
+	content, err := readCommandPrompt(commandDir)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	return standaloneDoc(metadata, fs.NormalizeNewlines(content)), nil
+}
 
+// standaloneDoc puts the ccmd header of a standalone file above a prompt
+func standaloneDoc(metadata *ProjectConfig, content []byte) []byte {
 	standalone := fmt.Sprintf(`# %s
 
 **Version:** %s
@@ -385,11 +935,13 @@ func createStandaloneDoc(commandDir, standalonePath string, metadata *ProjectCon
 %s
 `, metadata.Name, metadata.Version, metadata.Author, metadata.Repository, string(content))
 
-	return os.WriteFile(standalonePath, []byte(standalone), 0644)
+	return []byte(standalone)
 }
 
-func updateLockFile(projectRoot, commandName string, metadata *ProjectConfig, originalVersion string, requestedVersion string) error {
-	lockPath := filepath.Join(projectRoot, LockFileName)
+// updateLockFile records an installed command. The entry of a side-by-side instance is
+// keyed by its name; other entries are matched by repository, so renames replace them.
+func updateLockFile(projectRoot, commandName string, metadata *ProjectConfig, originalVersion string, requestedVersion string, instance bool) error {
+	lockPath := lockFilePath(projectRoot)
 	now := time.Now()
 
 	var lockFile *LockFile
@@ -408,11 +960,15 @@ func updateLockFile(projectRoot, commandName string, metadata *ProjectConfig, or
 	}
 
 	commitHash := "unknown"
-	commandPath := filepath.Join(projectRoot, ".claude", "commands", commandName)
+	commandPath := installedCommandDir(projectRoot, commandName)
 	if hash, err := gitGetCurrentCommit(commandPath); err == nil {
 		commitHash = hash
 	}
 
+	// An empty checksum only disables local modification checks for this command
+	checksum, _ := contentChecksum(commandPath)
+	size, _ := dirSize(commandPath)
+
 	resolved := metadata.Repository
 	if requestedVersion != "" {
 		resolved = fmt.Sprintf("%s@%s", metadata.Repository, requestedVersion)
@@ -428,17 +984,27 @@ func updateLockFile(projectRoot, commandName string, metadata *ProjectConfig, or
 	var existingKey string
 	var existingCmd *LockCommand
 
-	for key, cmd := range lockFile.Commands {
-		if ExtractRepoPath(cmd.Source) == repoPath {
-			existingKey = key
-			existingCmd = cmd
-			break
+	if instance {
+		if cmd, ok := lockFile.Commands[commandName]; ok && ExtractRepoPath(cmd.Source) == repoPath {
+			existingKey, existingCmd = commandName, cmd
+		}
+	} else {
+		for key, cmd := range lockFile.Commands {
+			if !cmd.Instance && ExtractRepoPath(cmd.Source) == repoPath {
+				existingKey = key
+				existingCmd = cmd
+				break
+			}
 		}
 	}
 
 	installedAt := now
-	if existingCmd != nil && !existingCmd.InstalledAt.IsZero() {
-		installedAt = existingCmd.InstalledAt
+	var history []LockHistory
+	if existingCmd != nil {
+		if !existingCmd.InstalledAt.IsZero() {
+			installedAt = existingCmd.InstalledAt
+		}
+		history = appendHistory(existingCmd, commitHash)
 	}
 
 	if existingKey != "" && existingKey != commandName {
@@ -451,15 +1017,91 @@ func updateLockFile(projectRoot, commandName string, metadata *ProjectConfig, or
 		Source:      metadata.Repository,
 		Resolved:    resolved,
 		Commit:      commitHash,
+		Checksum:    checksum,
+		FileSize:    size,
+		License:     metadata.License,
 		InstalledAt: installedAt,
 		UpdatedAt:   now,
+		Instance:    instance,
+		History:     history,
+	}
+	// Reinstalling a pinned command at its commit, as sync does, keeps the pin
+	if existingCmd != nil && existingCmd.Pinned && commitPinHash(requestedVersion) == existingCmd.Commit {
+		lockFile.Commands[commandName].Pinned = true
+		lockFile.Commands[commandName].PinnedFrom = existingCmd.PinnedFrom
 	}
 
-	return WriteLockFile(lockPath, lockFile)
+	if err := WriteLockFile(lockPath, lockFile); err != nil {
+		return err
+	}
+	recordInstallStats(projectRoot, "command", commandName, existingKey)
+	return nil
+}
+
+// commandNameConflict returns what already uses a command name: the repository of another
+// installed command, a lock entry for another repository, or a hand-written command file.
+// It returns "" when the name is free or used by repoPath itself.
+func commandNameConflict(projectRoot, name, repoPath string) string {
+	commandsDir := projectCommandsDir(projectRoot)
+	commandDir := filepath.Join(commandsDir, name)
+
+	if metadata, err := readCommandMetadata(filepath.Join(commandDir, "ccmd.yaml")); err == nil && metadata.Repository != "" {
+		if ExtractRepoPath(metadata.Repository) != repoPath {
+			return metadata.Repository
+		}
+		return ""
+	}
+
+	lockPath := lockFilePath(projectRoot)
+	if fileExists(lockPath) {
+		if lockFile, err := ReadLockFile(lockPath); err == nil {
+			if cmd, ok := lockFile.Commands[name]; ok && ExtractRepoPath(cmd.Source) != repoPath {
+				return cmd.Source
+			}
+		}
+	}
+
+	if dirExists(commandDir) {
+		return fmt.Sprintf("the untracked directory %s", filepath.ToSlash(projectRelative(projectRoot, commandDir)))
+	}
+	if file := claudeFile(projectRoot, name); fileExists(file) {
+		return fmt.Sprintf("the existing file %s", filepath.ToSlash(projectRelative(projectRoot, file)))
+	}
+
+	return ""
+}
+
+// resolveNameConflict returns the name to install under. When the name is taken by
+// another source, opts.Rename or opts.PromptRename provides an alternative.
+func resolveNameConflict(projectRoot, name, repoPath string, opts InstallOptions) (string, error) {
+	conflict := commandNameConflict(projectRoot, name, repoPath)
+	if conflict == "" {
+		return name, nil
+	}
+
+	rename := opts.Rename
+	if rename == "" && opts.PromptRename != nil {
+		rename = opts.PromptRename(name, conflict)
+	}
+	if rename == "" {
+		return "", errors.Conflict(fmt.Sprintf(
+			"command name %q is already used by %s, use --rename <name> to install under another name",
+			name, conflict))
+	}
+
+	if err := validateQualifiedName(rename); err != nil {
+		return "", err
+	}
+	if other := commandNameConflict(projectRoot, rename, repoPath); other != "" {
+		return "", errors.Conflict(fmt.Sprintf("command name %q is already used by %s", rename, other))
+	}
+
+	output.PrintWarningf("Command name %q is already used by %s, installing as %q", name, conflict, rename)
+	return rename, nil
 }
 
 func getInstalledCommands(projectRoot string) (map[string]string, error) {
-	commandsDir := filepath.Join(projectRoot, ".claude", "commands")
+	commandsDir := projectCommandsDir(projectRoot)
 	installedCommands := make(map[string]string)
 
 	entries, err := os.ReadDir(commandsDir)
@@ -471,10 +1113,27 @@ func getInstalledCommands(projectRoot string) (map[string]string, error) {
 	}
 
 	for _, entry := range entries {
-		if entry.IsDir() {
-			metadataPath := filepath.Join(commandsDir, entry.Name(), "ccmd.yaml")
+		if !entry.IsDir() {
+			continue
+		}
+		metadataPath := filepath.Join(commandsDir, entry.Name(), "ccmd.yaml")
+		if metadata, err := readCommandMetadata(metadataPath); err == nil && metadata.Repository != "" {
+			installedCommands[entry.Name()] = ExtractRepoPath(metadata.Repository)
+			continue
+		}
+
+		// Owner directories of the nested layout hold one directory per command
+		nested, err := os.ReadDir(filepath.Join(commandsDir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		for _, sub := range nested {
+			if !sub.IsDir() {
+				continue
+			}
+			metadataPath := filepath.Join(commandsDir, entry.Name(), sub.Name(), "ccmd.yaml")
 			if metadata, err := readCommandMetadata(metadataPath); err == nil && metadata.Repository != "" {
-				installedCommands[entry.Name()] = ExtractRepoPath(metadata.Repository)
+				installedCommands[QualifiedName(entry.Name(), sub.Name())] = ExtractRepoPath(metadata.Repository)
 			}
 		}
 	}
@@ -482,14 +1141,25 @@ func getInstalledCommands(projectRoot string) (map[string]string, error) {
 	return installedCommands, nil
 }
 
-func findExistingCommandByRepo(projectRoot, targetRepoPath string) (string, error) {
+// findExistingCommandByRepo returns the name of the installed command of a repository.
+// With an instance name it returns that side-by-side install; otherwise instances are
+// skipped and the regular install is returned.
+func findExistingCommandByRepo(projectRoot, targetRepoPath, instance string) (string, error) {
 	installedCommands, err := getInstalledCommands(projectRoot)
 	if err != nil {
 		return "", err
 	}
 
+	if instance != "" {
+		if installedCommands[instance] == targetRepoPath {
+			return instance, nil
+		}
+		return "", nil
+	}
+
+	instances := lockedInstances(projectRoot)
 	for commandName, repoPath := range installedCommands {
-		if repoPath == targetRepoPath {
+		if repoPath == targetRepoPath && !instances[commandName] {
 			return commandName, nil
 		}
 	}
@@ -497,6 +1167,21 @@ func findExistingCommandByRepo(projectRoot, targetRepoPath string) (string, erro
 	return "", nil
 }
 
+// lockedInstances returns the names of the side-by-side installs recorded in the lock file
+func lockedInstances(projectRoot string) map[string]bool {
+	instances := make(map[string]bool)
+	lockFile, err := ReadLockFile(lockFilePath(projectRoot))
+	if err != nil {
+		return instances
+	}
+	for name, cmd := range lockFile.Commands {
+		if cmd.Instance {
+			instances[name] = true
+		}
+	}
+	return instances
+}
+
 func addToConfig(projectRoot, commandName, repository, version string) error {
 	var config *ProjectConfig
 	if ProjectConfigExists(projectRoot) {
@@ -525,7 +1210,10 @@ func addToConfig(projectRoot, commandName, repository, version string) error {
 	currentRepo := ExtractRepoPath(repository)
 
 	for i, cmd := range config.Commands {
-		repo, _ := ParseCommandSpec(cmd)
+		repo, _, instance := ParseInstanceSpec(cmd)
+		if instance != "" {
+			continue
+		}
 		repoPath := ExtractRepoPath(repo)
 
 		if repoPath == currentRepo {
@@ -546,92 +1234,99 @@ func addToConfig(projectRoot, commandName, repository, version string) error {
 	return SaveProjectConfig(projectRoot, config)
 }
 
-func ParseRepositorySpec(spec string) (repository, version string) {
-	if strings.HasPrefix(spec, "git@") {
-		gitSuffix := ".git@"
-		gitIndex := strings.LastIndex(spec, gitSuffix)
-		if gitIndex > -1 {
-			repository = spec[:gitIndex+4]
-			version = spec[gitIndex+len(gitSuffix):]
-			return repository, version
-		}
-
-		colonIndex := strings.Index(spec, ":")
-		if colonIndex > -1 {
-			afterColon := spec[colonIndex+1:]
-
-			firstAtIndex := strings.Index(afterColon, "@")
-			if firstAtIndex > -1 {
-				beforeAt := afterColon[:firstAtIndex]
-				if strings.Contains(beforeAt, "/") {
-					repository = spec[:colonIndex+1+firstAtIndex]
-					version = spec[colonIndex+1+firstAtIndex+1:]
-					return repository, version
-				}
-				remainingPart := afterColon[firstAtIndex+1:]
-				nextAtIndex := strings.Index(remainingPart, "@")
-				if nextAtIndex > -1 {
-					totalIndex := colonIndex + 1 + firstAtIndex + 1 + nextAtIndex
-					repository = spec[:totalIndex]
-					version = spec[totalIndex+1:]
-					return repository, version
-				}
+// addToProfile records a command spec in a ccmd.yaml profile. An entry for the same
+// repository in the shared commands list is updated there instead.
+// addToProfile records a command spec in the shared commands list when an entry for the
+// same repository and instance is there, or else in a profile. An empty profile adds it
+// to the shared list.
+func addToProfile(projectRoot, profile, commandSpec string) error {
+	config := &ProjectConfig{}
+	if ProjectConfigExists(projectRoot) {
+		var err error
+		config, err = LoadProjectConfig(projectRoot)
+		if err != nil {
+			return err
+		}
+	}
+
+	key := specKey(commandSpec)
+	replace := func(specs []string) bool {
+		for i, spec := range specs {
+			if specKey(spec) == key {
+				specs[i] = commandSpec
+				return true
 			}
 		}
+		return false
+	}
 
-		return spec, ""
+	switch {
+	case replace(config.Commands):
+	case profile == "":
+		config.Commands = append(config.Commands, commandSpec)
+	default:
+		if config.Profiles == nil {
+			config.Profiles = make(map[string][]string)
+		}
+		if !replace(config.Profiles[profile]) {
+			config.Profiles[profile] = append(config.Profiles[profile], commandSpec)
+		}
 	}
 
-	lastAtIndex := strings.LastIndex(spec, "@")
-	if lastAtIndex == -1 {
+	return SaveProjectConfig(projectRoot, config)
+}
+
+// ParseRepositorySpec splits a spec such as owner/repo@v1.0.0 into the repository, with
+// its //command if any, and the version. Specs that do not parse are returned whole.
+func ParseRepositorySpec(spec string) (repository, version string) {
+	parsed, err := repospec.Parse(spec)
+	if err != nil {
 		return spec, ""
 	}
+	return parsed.Repository(), parsed.Version
+}
 
-	repository = spec[:lastAtIndex]
-	version = spec[lastAtIndex+1:]
-	return repository, version
+// configSource returns the form of a repository recorded in ccmd.yaml: owner/repo for
+// the default host, host.tld/owner/repo for others, or the source itself when no
+// shorthand names it, such as a local repository. The version is recorded separately.
+func configSource(projectRoot, repository string) string {
+	spec, err := repospec.Parse(repository)
+	if err != nil {
+		return repository
+	}
+	return spec.Shorthand(hostResolverFor(projectRoot).defaultHost)
 }
 
+// NormalizeRepositoryURL converts a repository spec into a clone URL. Bare owner/repo
+// shorthands use the configured default host, "gitlab:group/project" style prefixes
+// use host aliases, and "host.tld/owner/repo" names the host explicitly. Archive and
+// markdown URLs are returned unchanged. A //command suffix selecting one command of a
+// multi-command repository is kept.
 func NormalizeRepositoryURL(url string) string {
-	if !strings.Contains(url, "://") && !strings.HasPrefix(url, "git@") {
-		if strings.Count(url, "/") == 1 {
-			return fmt.Sprintf("https://github.com/%s.git", url)
-		}
+	if isDownloadSource(url) {
+		return url
 	}
+	projectRoot, _ := findProjectRoot()
+	return normalizeRepositoryURL(projectRoot, url)
+}
 
-	if !strings.HasSuffix(url, ".git") && strings.Contains(url, "github.com") {
-		url += ".git"
+// normalizeRepositoryURL is NormalizeRepositoryURL with the hosts of the project at
+// projectRoot rather than the one around the working directory
+func normalizeRepositoryURL(projectRoot, url string) string {
+	if isDownloadSource(url) {
+		return url
 	}
-
-	return url
+	resolver := hostResolverFor(projectRoot)
+	if repo, command := splitRepositoryCommand(url); command != "" {
+		return RepositoryCommandSpec(resolver.normalize(repo), command)
+	}
+	return resolver.normalize(url)
 }
 
 func extractCommandName(repoURL string) string {
 	path := ExtractRepoPath(repoURL)
 	parts := strings.Split(path, "/")
-	if len(parts) > 0 {
-		return parts[len(parts)-1]
-	}
-	return ""
-}
-
-func findProjectRoot() (string, error) {
-	dir, err := os.Getwd()
-	if err != nil {
-		return "", err
-	}
-
-	for {
-		if fileExists(filepath.Join(dir, "ccmd.yaml")) {
-			return dir, nil
-		}
-
-		parent := filepath.Dir(dir)
-		if parent == dir {
-			return os.Getwd()
-		}
-		dir = parent
-	}
+	return parts[len(parts)-1]
 }
 
 func fileExists(path string) bool {
@@ -10,14 +10,19 @@
 package core
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/gifflet/ccmd/pkg/errors"
+	"github.com/gifflet/ccmd/pkg/repospec"
 )
 
 func TestUpdateLockFile(t *testing.T) {
@@ -33,7 +38,7 @@ func TestUpdateLockFile(t *testing.T) {
 		}
 
 		// Call updateLockFile
-		err := updateLockFile(tempDir, "test-cmd", metadata, metadata.Version, "")
+		err := updateLockFile(tempDir, "test-cmd", metadata, metadata.Version, "", false)
 		require.NoError(t, err)
 
 		// Read the created lock file
@@ -86,7 +91,7 @@ func TestUpdateLockFile(t *testing.T) {
 		}
 
 		// Call updateLockFile
-		err := updateLockFile(tempDir, "test-cmd", metadata, metadata.Version, "")
+		err := updateLockFile(tempDir, "test-cmd", metadata, metadata.Version, "", false)
 		require.NoError(t, err)
 
 		// Read updated lock file
@@ -128,7 +133,7 @@ func TestUpdateLockFile(t *testing.T) {
 			Repository: "https://github.com/user/new-cmd.git",
 		}
 
-		err := updateLockFile(tempDir, "new-cmd", metadata, metadata.Version, "")
+		err := updateLockFile(tempDir, "new-cmd", metadata, metadata.Version, "", false)
 		require.NoError(t, err)
 
 		// Read updated lock file
@@ -159,7 +164,7 @@ func TestUpdateLockFile(t *testing.T) {
 		}
 
 		// Call updateLockFile
-		err := updateLockFile(tempDir, "test-cmd", metadata, metadata.Version, "")
+		err := updateLockFile(tempDir, "test-cmd", metadata, metadata.Version, "", false)
 		require.NoError(t, err)
 
 		// Read the created lock file
@@ -203,7 +208,7 @@ func TestUpdateLockFile(t *testing.T) {
 			Repository: "https://github.com/owner/cli-tool.git",
 		}
 
-		err := updateLockFile(tempDir, "new-cli-name", metadata, metadata.Version, "")
+		err := updateLockFile(tempDir, "new-cli-name", metadata, metadata.Version, "", false)
 		require.NoError(t, err)
 
 		// Read updated lock file
@@ -256,7 +261,7 @@ func TestUpdateLockFile(t *testing.T) {
 			Repository: "https://github.com/org/second-repo.git",
 		}
 
-		err := updateLockFile(tempDir, "renamed-tool", metadata, metadata.Version, "")
+		err := updateLockFile(tempDir, "renamed-tool", metadata, metadata.Version, "", false)
 		require.NoError(t, err)
 
 		// Read updated lock file
@@ -289,7 +294,7 @@ func TestUpdateLockFile(t *testing.T) {
 		metadata.Version = "v1.0.0" // The version specified during install
 
 		// Call updateLockFile with original version
-		err := updateLockFile(tempDir, "test-cmd", metadata, originalVersion, "v1.0.0")
+		err := updateLockFile(tempDir, "test-cmd", metadata, originalVersion, "v1.0.0", false)
 		require.NoError(t, err)
 
 		// Read the created lock file
@@ -321,7 +326,7 @@ func TestUpdateLockFile(t *testing.T) {
 		originalVersion := "1.0.0" // The version from ccmd.yaml
 
 		// Call updateLockFile with original version
-		err := updateLockFile(tempDir, "test-cmd", metadata, originalVersion, "")
+		err := updateLockFile(tempDir, "test-cmd", metadata, originalVersion, "", false)
 		require.NoError(t, err)
 
 		// Read the created lock file
@@ -502,6 +507,48 @@ func TestParseRepositorySpec(t *testing.T) {
 	}
 }
 
+func FuzzParseRepositorySpec(f *testing.F) {
+	for _, seed := range []string{
+		"gifflet/parallax@v1.0.0",
+		"git@github.com:user@company/repo.git@feature-branch",
+		"https://github.com/gifflet/parallax.git@main",
+		"owner/repo//review@>=1.0 <2.0",
+		"owner/repo＠v1.0.0",
+		"owner/repo/",
+		"./local/repo@v1",
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, spec string) {
+		repository, version := ParseRepositorySpec(spec)
+
+		// The spec splits into repository and version, and is returned whole when it
+		// does not parse
+		joined := repository
+		if version != "" {
+			joined += "@" + version
+		}
+		if joined != spec {
+			t.Fatalf("ParseRepositorySpec(%q) = %q, %q", spec, repository, version)
+		}
+
+		parsed, err := repospec.Parse(spec)
+		if err != nil {
+			if version != "" {
+				t.Fatalf("ParseRepositorySpec(%q) found version %q in a malformed spec", spec, version)
+			}
+			return
+		}
+		if len(spec) > repospec.MaxLength {
+			t.Fatalf("ParseRepositorySpec(%q) accepted %d bytes", spec, len(spec))
+		}
+		if parsed.Remote() && repospec.NonASCIIIndex(parsed.Source) != -1 {
+			t.Fatalf("ParseRepositorySpec(%q) accepted a non-ASCII remote", spec)
+		}
+	})
+}
+
 func TestAddToConfigWithDifferentCommandName(t *testing.T) {
 	t.Run("updates existing command when name differs from repo", func(t *testing.T) {
 		// Create temp directory
@@ -617,3 +664,220 @@ func TestAddToConfigWithDifferentCommandName(t *testing.T) {
 		assert.Contains(t, config.Commands, "company/cli-project@1.0.0")
 	})
 }
+
+func TestInstallCancelled(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	writeConfig(t, []string{})
+
+	data := buildTarGz(t, map[string]string{"ccmd.yaml": archiveMetadata, "index.md": "Say hello"})
+	require.NoError(t, os.WriteFile("hello.tgz", data, 0644))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, _, err := Install(ctx, InstallOptions{Repository: "hello.tgz"})
+	require.ErrorIs(t, err, context.Canceled)
+
+	assert.NoDirExists(t, filepath.Join(".claude", "commands", "hello"))
+	assert.NoFileExists(t, filepath.Join(".claude", "commands", "hello.md"))
+	assert.NoFileExists(t, LockFileName)
+
+	cfg, err := LoadProjectConfig(".")
+	require.NoError(t, err)
+	assert.Empty(t, cfg.Commands)
+}
+
+func TestCopyDirectoryCancelled(t *testing.T) {
+	src := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(src, "index.md"), []byte("x"), 0644))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	dst := filepath.Join(t.TempDir(), "copy")
+	assert.ErrorIs(t, copyDirectory(ctx, src, dst), context.Canceled)
+	assert.NoFileExists(t, filepath.Join(dst, "index.md"))
+}
+
+func TestSyncCancelled(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	writeConfig(t, []string{"test/hello@1.0.0"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	cwd, err := os.Getwd()
+	require.NoError(t, err)
+
+	result, err := Sync(ctx, SyncOptions{ProjectPath: cwd})
+	require.ErrorIs(t, err, context.Canceled)
+	require.NotNil(t, result)
+	assert.Empty(t, result.Installed)
+	assert.Empty(t, result.Failed)
+
+	events, err := ReadAudit(AuditQuery{Action: AuditSync})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, AuditFailure, events[0].Result)
+}
+
+func TestInstallNameConflict(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	writeConfig(t, []string{})
+
+	data := buildTarGz(t, map[string]string{"ccmd.yaml": archiveMetadata, "index.md": "Say hello"})
+	for _, dir := range []string{"a", "b", "c"} {
+		require.NoError(t, os.MkdirAll(dir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "hello.tgz"), data, 0644))
+	}
+
+	name, _, err := Install(context.Background(), InstallOptions{Repository: "a/hello.tgz"})
+	require.NoError(t, err)
+	assert.Equal(t, "hello", name)
+
+	_, _, err = Install(context.Background(), InstallOptions{Repository: "b/hello.tgz"})
+	require.ErrorIs(t, err, errors.ErrConflict)
+	assert.Contains(t, err.Error(), "a/hello.tgz")
+	assert.Contains(t, err.Error(), "--rename")
+
+	standalone, err := os.ReadFile(filepath.Join(".claude", "commands", "hello.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(standalone), "a/hello.tgz")
+
+	name, _, err = Install(context.Background(), InstallOptions{Repository: "b/hello.tgz", Rename: "hello-b"})
+	require.NoError(t, err)
+	assert.Equal(t, "hello-b", name)
+
+	var prompted string
+	name, _, err = Install(context.Background(), InstallOptions{
+		Repository: "c/hello.tgz",
+		PromptRename: func(name, conflict string) string {
+			prompted = conflict
+			return "hello-c"
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "hello-c", name)
+	assert.Equal(t, "a/hello.tgz", prompted)
+
+	lock := readLockFile(t)
+	require.Contains(t, lock.Commands, "hello-b")
+	assert.Equal(t, "b/hello.tgz", lock.Commands["hello-b"].Source)
+
+	cfg, err := LoadProjectConfig(".")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"a/hello.tgz", "b/hello.tgz", "c/hello.tgz"}, cfg.Commands)
+
+	// A fresh install from ccmd.yaml keeps the chosen names
+	require.NoError(t, os.RemoveAll(".claude"))
+	require.NoError(t, InstallFromConfig(context.Background(), ".", false))
+	assert.DirExists(t, filepath.Join(".claude", "commands", "hello"))
+	assert.DirExists(t, filepath.Join(".claude", "commands", "hello-b"))
+	assert.DirExists(t, filepath.Join(".claude", "commands", "hello-c"))
+}
+
+func TestCommandNameConflict(t *testing.T) {
+	dir := t.TempDir()
+	commandsDir := filepath.Join(dir, ".claude", "commands")
+	require.NoError(t, os.MkdirAll(commandsDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(commandsDir, "notes.md"), []byte("# Notes"), 0644))
+
+	assert.Contains(t, commandNameConflict(dir, "notes", "acme/notes"), ".claude/commands/notes.md")
+	assert.Empty(t, commandNameConflict(dir, "free", "acme/free"))
+}
+
+func TestInstallProfile(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	writeConfig(t, []string{})
+
+	data := buildTarGz(t, map[string]string{"ccmd.yaml": archiveMetadata, "index.md": "Say hello"})
+	require.NoError(t, os.WriteFile("hello.tgz", data, 0644))
+
+	_, _, err := Install(context.Background(), InstallOptions{Repository: "hello.tgz", Profile: "docs"})
+	require.NoError(t, err)
+
+	cfg, err := LoadProjectConfig(".")
+	require.NoError(t, err)
+	assert.Empty(t, cfg.Commands)
+	assert.Equal(t, []string{"hello.tgz"}, cfg.Profiles["docs"])
+
+	// A plain install skips profile commands, --profile installs them
+	require.NoError(t, os.RemoveAll(".claude"))
+	require.NoError(t, os.Remove(LockFileName))
+	require.NoError(t, InstallFromConfig(context.Background(), ".", false))
+	assert.NoDirExists(t, filepath.Join(".claude", "commands", "hello"))
+
+	require.NoError(t, InstallProfile(context.Background(), ".", ConfigInstallOptions{Profile: "docs"}))
+	assert.DirExists(t, filepath.Join(".claude", "commands", "hello"))
+
+	err = InstallProfile(context.Background(), ".", ConfigInstallOptions{Profile: "backend"})
+	assert.ErrorIs(t, err, errors.ErrNotFound)
+}
+
+func TestInstallWindowsSafeNames(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	writeConfig(t, []string{})
+
+	metadata := strings.Replace(archiveMetadata, "name: hello", "name: aux", 1)
+	data := buildTarGz(t, map[string]string{"ccmd.yaml": metadata, "index.md": "Say hello\r\nTwice\r\n"})
+	require.NoError(t, os.WriteFile("aux.tgz", data, 0644))
+
+	_, _, err := Install(context.Background(), InstallOptions{Repository: "aux.tgz", Name: "con"})
+	assert.ErrorIs(t, err, errors.ErrInvalidInput)
+
+	// A reserved name taken from the metadata is sanitized instead of failing on Windows
+	name, _, err := Install(context.Background(), InstallOptions{Repository: "aux.tgz"})
+	require.NoError(t, err)
+	assert.Equal(t, "aux-cmd", name)
+
+	standalone, err := os.ReadFile(filepath.Join(".claude", "commands", "aux-cmd.md"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(standalone), "\r")
+	assert.Contains(t, string(standalone), "Say hello\nTwice\n")
+}
+
+func TestSideBySideInstanceEntries(t *testing.T) {
+	tempDir := t.TempDir()
+	metadata := &ProjectConfig{Name: "tool", Version: "2.0.0", Repository: "https://github.com/acme/tool.git"}
+
+	require.NoError(t, updateLockFile(tempDir, "tool", metadata, "2.0.0", "v2.0.0", false))
+	metadata.Version = "1.0.0"
+	require.NoError(t, updateLockFile(tempDir, "tool-v1", metadata, "1.0.0", "v1.0.0", true))
+
+	lockFile := readLockFileFromPath(t, filepath.Join(tempDir, "ccmd-lock.yaml"))
+	require.Len(t, lockFile.Commands, 2)
+	assert.False(t, lockFile.Commands["tool"].Instance)
+	assert.Equal(t, "2.0.0", lockFile.Commands["tool"].Version)
+	assert.True(t, lockFile.Commands["tool-v1"].Instance)
+	assert.Equal(t, "1.0.0", lockFile.Commands["tool-v1"].Version)
+
+	name, locked := lockedCommand(lockFile, "acme/tool", "")
+	assert.Equal(t, "tool", name)
+	assert.Equal(t, "2.0.0", locked.Version)
+	name, _ = lockedCommand(lockFile, "acme/tool", "tool-v1")
+	assert.Equal(t, "tool-v1", name)
+	assert.Equal(t, "tool", resolveNameFromLock(".", lockFile, "acme/tool"))
+
+	// ccmd.yaml keeps one entry per instance
+	require.NoError(t, addToConfig(tempDir, "tool", "acme/tool", "^2.0.0"))
+	require.NoError(t, addToProfile(tempDir, "", formatCommandSpec("acme/tool", "^1.0.0", "tool-v1")))
+	require.NoError(t, addToConfig(tempDir, "tool", "acme/tool", "^2.1.0"))
+	require.NoError(t, addToProfile(tempDir, "", formatCommandSpec("acme/tool", "^1.2.0", "tool-v1")))
+
+	config, err := LoadProjectConfig(tempDir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"acme/tool@^2.1.0", "acme/tool@^1.2.0 as tool-v1"}, config.Commands)
+	assert.Equal(t, "^1.2.0", configuredConstraint(tempDir, "https://github.com/acme/tool.git", "tool-v1"))
+	assert.Equal(t, "^2.1.0", configuredConstraint(tempDir, "https://github.com/acme/tool.git", ""))
+
+	// Removing the instance leaves the regular entry
+	require.NoError(t, removeFromConfig(tempDir, "tool-v1", "https://github.com/acme/tool.git"))
+	config, err = LoadProjectConfig(tempDir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"acme/tool@^2.1.0"}, config.Commands)
+}
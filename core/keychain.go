@@ -0,0 +1,121 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// keychainService names the ccmd entries in the OS keychain
+const keychainService = "ccmd"
+
+// keychain stores secrets in the credential store of the operating system
+type keychain interface {
+	set(host, token string) error
+	get(host string) (string, error)
+	remove(host string) error
+}
+
+// systemKeychain returns the keychain of the running system, or nil when there is none.
+// It is a variable so tests can replace it.
+var systemKeychain = func() keychain {
+	switch runtime.GOOS {
+	case "darwin":
+		if path, err := exec.LookPath("security"); err == nil {
+			return macKeychain{path: path}
+		}
+	case "linux", "freebsd", "openbsd":
+		if path, err := exec.LookPath("secret-tool"); err == nil {
+			return secretServiceKeychain{path: path}
+		}
+	}
+	return nil
+}
+
+// macKeychain uses the macOS security tool
+type macKeychain struct {
+	path string
+}
+
+func (k macKeychain) set(host, token string) error {
+	// In interactive mode security reads the command from stdin, so the token does not
+	// show up in the process list as it would as an argument; -U updates an existing entry
+	if strings.ContainsAny(token, "\r\n") {
+		return fmt.Errorf("the token contains a line break")
+	}
+	command := strings.Join([]string{"add-generic-password", "-U", "-s", keychainService,
+		"-a", quoteSecurityArg(host), "-l", quoteSecurityArg("ccmd " + host), "-w", quoteSecurityArg(token)}, " ")
+	if err := runKeychainTool(exec.Command(k.path, "-i"), command+"\n"); err != nil {
+		return fmt.Errorf("%s", strings.ReplaceAll(err.Error(), token, "***"))
+	}
+
+	// security -i reports failed commands on its output, not in its exit status
+	stored, err := k.get(host)
+	if err != nil {
+		return err
+	}
+	if stored != token {
+		return fmt.Errorf("security add-generic-password: the token was not stored")
+	}
+	return nil
+}
+
+// quoteSecurityArg quotes an argument of a command read by security -i
+func quoteSecurityArg(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+func (k macKeychain) get(host string) (string, error) {
+	out, err := exec.Command(k.path, "find-generic-password", "-s", keychainService, "-a", host, "-w").Output()
+	if err != nil {
+		return "", fmt.Errorf("security find-generic-password: %w", err)
+	}
+	return strings.TrimRight(string(out), "\r\n"), nil
+}
+
+func (k macKeychain) remove(host string) error {
+	return runKeychainTool(exec.Command(k.path, "delete-generic-password", "-s", keychainService, "-a", host), "")
+}
+
+// secretServiceKeychain uses secret-tool from libsecret, backed by GNOME Keyring or KWallet
+type secretServiceKeychain struct {
+	path string
+}
+
+func (k secretServiceKeychain) set(host, token string) error {
+	// The token goes through stdin so it does not show up in the process list
+	return runKeychainTool(exec.Command(k.path, "store", "--label", "ccmd "+host,
+		"service", keychainService, "host", host), token)
+}
+
+func (k secretServiceKeychain) get(host string) (string, error) {
+	out, err := exec.Command(k.path, "lookup", "service", keychainService, "host", host).Output()
+	if err != nil {
+		return "", fmt.Errorf("secret-tool lookup: %w", err)
+	}
+	return strings.TrimRight(string(out), "\r\n"), nil
+}
+
+func (k secretServiceKeychain) remove(host string) error {
+	return runKeychainTool(exec.Command(k.path, "clear", "service", keychainService, "host", host), "")
+}
+
+func runKeychainTool(cmd *exec.Cmd, stdin string) error {
+	if stdin != "" {
+		cmd.Stdin = strings.NewReader(stdin)
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s: %w: %s", cmd.Args[0], err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMacKeychainSetKeepsTokenOffCommandLine(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script in place of security")
+	}
+
+	// The fake security records its arguments and input, and finds the stored token
+	dir := t.TempDir()
+	script := `#!/bin/sh
+echo "$@" >> "` + dir + `/args"
+if [ "$1" = "-i" ]; then
+	cat > "` + dir + `/stdin"
+	exit 0
+fi
+printf '%s\n' 'se"cr\et'
+`
+	path := filepath.Join(dir, "security")
+	require.NoError(t, os.WriteFile(path, []byte(script), 0o755))
+
+	require.NoError(t, macKeychain{path: path}.set("github.com", `se"cr\et`))
+
+	args, err := os.ReadFile(filepath.Join(dir, "args"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(args), `cr\et`)
+	stdin, err := os.ReadFile(filepath.Join(dir, "stdin"))
+	require.NoError(t, err)
+	assert.Equal(t, `add-generic-password -U -s ccmd -a "github.com" -l "ccmd github.com" -w "se\"cr\\et"`+"\n", string(stdin))
+
+	assert.Error(t, macKeychain{path: path}.set("github.com", "other"))
+	assert.Error(t, macKeychain{path: path}.set("github.com", "two\nlines"))
+}
@@ -0,0 +1,304 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gifflet/ccmd/internal/fs"
+	"github.com/gifflet/ccmd/pkg/config"
+	"github.com/gifflet/ccmd/pkg/errors"
+	"github.com/gifflet/ccmd/pkg/output"
+	"github.com/gifflet/ccmd/pkg/repospec"
+)
+
+// defaultQualifiedFileName names the standalone files of commands in the nested layout
+const defaultQualifiedFileName = "{owner}--{name}"
+
+// QualifiedName returns the name of a command in the nested layout, owner/name. It is the
+// command's directory below .claude/commands and its key in the lock file.
+func QualifiedName(owner, name string) string {
+	return owner + "/" + name
+}
+
+// splitQualifiedName splits a qualified name into owner and name. Names of the flat
+// layout have no owner.
+func splitQualifiedName(name string) (owner, short string) {
+	if owner, short, ok := strings.Cut(name, "/"); ok {
+		return owner, short
+	}
+	return "", name
+}
+
+// standaloneBase returns the base name of a command's standalone files: the name itself
+// in the flat layout, and for qualified names the pattern with {owner} and {name} filled in
+func standaloneBase(name, pattern string) string {
+	owner, short := splitQualifiedName(name)
+	if owner == "" {
+		return name
+	}
+	if pattern == "" {
+		pattern = defaultQualifiedFileName
+	}
+	return strings.NewReplacer("{owner}", owner, "{name}", short).Replace(pattern)
+}
+
+// layoutSettings returns the layout settings of a project, the defaults when the
+// configuration cannot be loaded
+func layoutSettings(projectRoot string) config.LayoutSettings {
+	settings, err := config.Load(projectRoot)
+	if err != nil {
+		return config.Defaults().Layout
+	}
+	return settings.Layout
+}
+
+// validateLayout checks the layout settings before commands are placed with them
+func validateLayout(layout config.LayoutSettings) error {
+	if layout.Commands != "" && layout.Commands != config.LayoutFlat && layout.Commands != config.LayoutNested {
+		return errors.InvalidInput(fmt.Sprintf("layout.commands must be %s or %s, got %q",
+			config.LayoutFlat, config.LayoutNested, layout.Commands))
+	}
+	if layout.FileName != "" {
+		if !strings.Contains(layout.FileName, "{name}") {
+			return errors.InvalidInput(fmt.Sprintf("layout.file_name %q must contain {name}", layout.FileName))
+		}
+		if err := fs.ValidateName(standaloneBase(QualifiedName("owner", "name"), layout.FileName)); err != nil {
+			return errors.InvalidInput(fmt.Sprintf("layout.file_name %q: %v", layout.FileName, err))
+		}
+	}
+	return nil
+}
+
+// repositoryOwner returns the owner of a git repository, sanitized for use as a directory
+// name. Downloads and repositories without an owner have none.
+func repositoryOwner(repoURL string) string {
+	if isDownloadSource(repoURL) {
+		return ""
+	}
+	spec, err := repospec.Parse(repoURL)
+	if err != nil || spec.Owner() == "" {
+		return ""
+	}
+	return fs.SanitizeName(spec.Owner())
+}
+
+// validateQualifiedName checks both parts of a qualified name, or a flat name. Names
+// given with --name and --rename may be qualified; instance names may not.
+func validateQualifiedName(name string) error {
+	owner, short := splitQualifiedName(name)
+	if owner != "" {
+		if err := validateCommandName(owner); err != nil {
+			return err
+		}
+	}
+	return validateCommandName(short)
+}
+
+// pruneOwnerDir removes the owner directory of a nested command once its last command
+// is gone
+func pruneOwnerDir(projectRoot, name string) {
+	if owner, _ := splitQualifiedName(name); owner != "" {
+		removeIfEmpty(installedCommandDir(projectRoot, owner))
+	}
+}
+
+// MigrateLayoutOptions represents options for moving installed commands to another layout
+type MigrateLayoutOptions struct {
+	ProjectPath string
+	Layout      string // flat or nested; empty for the layout.commands setting
+	DryRun      bool   // Only report the moves
+}
+
+// LayoutMove is a command renamed by a layout migration
+type LayoutMove struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// MigrateLayoutResult lists the commands that were moved and the ones that could not be
+type MigrateLayoutResult struct {
+	Moved  []LayoutMove
+	Failed []SyncError
+}
+
+// MigrateLayout moves the installed commands to the flat or nested layout: their
+// directories, standalone files in every target and local overrides, and their lock
+// entries, which are keyed by the new names. Side-by-side instances keep their names, as
+// do commands whose new name is taken. The standalone files are regenerated afterwards.
+func MigrateLayout(opts MigrateLayoutOptions) (*MigrateLayoutResult, error) {
+	projectRoot, err := findProjectRootFrom(opts.ProjectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	layout := layoutSettings(projectRoot)
+	if opts.Layout != "" {
+		layout.Commands = opts.Layout
+	}
+	if err := validateLayout(layout); err != nil {
+		return nil, err
+	}
+
+	result := &MigrateLayoutResult{Moved: []LayoutMove{}, Failed: []SyncError{}}
+	lockPath := lockFilePath(projectRoot)
+	if !fileExists(lockPath) {
+		return result, nil
+	}
+	lockFile, err := ReadLockFile(lockPath)
+	if err != nil {
+		return nil, err
+	}
+	targets, err := projectTargets(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(lockFile.Commands))
+	for name := range lockFile.Commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		cmd := lockFile.Commands[name]
+		if cmd.Instance {
+			continue
+		}
+		to := layoutName(name, cmd.Source, layout.Commands)
+		if to == name {
+			continue
+		}
+		if _, taken := lockFile.Commands[to]; taken || dirExists(installedCommandDir(projectRoot, to)) {
+			result.Failed = append(result.Failed, SyncError{Command: name, Operation: "migrate",
+				Error: errors.Conflict(fmt.Sprintf("cannot move to %q, the name is taken", to))})
+			continue
+		}
+
+		if !opts.DryRun {
+			if err := moveCommand(projectRoot, cmd, name, to, targets); err != nil {
+				result.Failed = append(result.Failed, SyncError{Command: name, Operation: "migrate", Error: err})
+				continue
+			}
+			delete(lockFile.Commands, name)
+			lockFile.Commands[to] = cmd
+		}
+		result.Moved = append(result.Moved, LayoutMove{From: name, To: to})
+	}
+
+	if opts.DryRun || len(result.Moved) == 0 {
+		return result, nil
+	}
+	if err := WriteLockFile(lockPath, lockFile); err != nil {
+		return result, err
+	}
+	// The headers of the standalone files name the command
+	refreshStandaloneDocs(projectRoot, "")
+
+	return result, nil
+}
+
+// layoutName returns the name of a command in a layout: owner/name in the nested layout,
+// for git repositories, and the name alone in the flat layout
+func layoutName(name, source, layout string) string {
+	_, short := splitQualifiedName(name)
+	if layout != config.LayoutNested {
+		return short
+	}
+	owner := repositoryOwner(source)
+	if owner == "" {
+		return name
+	}
+	return QualifiedName(owner, short)
+}
+
+// moveCommand renames an installed command from one layout to the other and updates its
+// lock entry, without writing the lock file
+func moveCommand(projectRoot string, cmd *LockCommand, from, to string, targets []Target) error {
+	commandsDir := projectCommandsDir(projectRoot)
+	fromDir, toDir := filepath.Join(commandsDir, from), filepath.Join(commandsDir, to)
+
+	modified, err := hasLocalChanges(projectRoot, from)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(toDir), 0755); err != nil {
+		return errors.FileError("create commands directory", filepath.Dir(toDir), err)
+	}
+	if err := os.Rename(fromDir, toDir); err != nil {
+		return errors.FileError("move command", fromDir, err)
+	}
+	pruneOwnerDir(projectRoot, from)
+
+	// The installed ccmd.yaml names the command; the checksum is updated with it unless
+	// the files were already modified locally
+	metadataPath := filepath.Join(toDir, ConfigFileName)
+	if metadata, err := readCommandMetadata(metadataPath); err == nil {
+		metadata.Name = to
+		if err := writeCommandMetadata(metadataPath, metadata); err != nil {
+			return err
+		}
+	}
+	if cmd.Checksum != "" && !modified {
+		if len(cmd.Files) > 0 {
+			cmd.Checksum, err = checksumFiles(toDir, cmd.Files)
+		} else {
+			cmd.Checksum, err = contentChecksum(toDir)
+		}
+		if err != nil {
+			return errors.FileError("checksum command files", toDir, err)
+		}
+	}
+
+	overrides := filepath.Join(projectRoot, ".claude", "overrides")
+	if dirExists(filepath.Join(overrides, from)) {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(overrides, to)), 0755); err != nil {
+			return errors.FileError("create overrides directory", overrides, err)
+		}
+		if err := os.Rename(filepath.Join(overrides, from), filepath.Join(overrides, to)); err != nil {
+			return errors.FileError("move overrides", filepath.Join(overrides, from), err)
+		}
+		if owner, _ := splitQualifiedName(from); owner != "" {
+			removeIfEmpty(filepath.Join(overrides, owner))
+		}
+	}
+
+	standalone := make(map[string]*LockStandalone, len(cmd.Standalone))
+	for key, record := range cmd.Standalone {
+		standalone[key] = record
+	}
+	for _, target := range targets {
+		oldPath, newPath := target.file(projectRoot, from), target.file(projectRoot, to)
+		if fileExists(oldPath) {
+			if err := os.MkdirAll(filepath.Dir(newPath), 0755); err == nil {
+				err = os.Rename(oldPath, newPath)
+			}
+			if err != nil {
+				output.PrintWarningf("Failed to move %s: %v", target.label(from), err)
+			}
+			pruneTargetDir(oldPath, from)
+		}
+		if record, ok := standalone[target.key(from)]; ok {
+			delete(standalone, target.key(from))
+			standalone[target.key(to)] = record
+		}
+	}
+	if len(standalone) > 0 {
+		cmd.Standalone = standalone
+	}
+
+	cmd.Name = to
+	return nil
+}
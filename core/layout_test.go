@@ -0,0 +1,106 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gifflet/ccmd/pkg/config"
+)
+
+func TestStandaloneBase(t *testing.T) {
+	assert.Equal(t, "tool", standaloneBase("tool", ""))
+	assert.Equal(t, "acme--tool", standaloneBase("acme/tool", ""))
+	assert.Equal(t, "tool.acme", standaloneBase("acme/tool", "{name}.{owner}"))
+
+	assert.NoError(t, validateLayout(config.LayoutSettings{Commands: config.LayoutNested, FileName: "{owner}.{name}"}))
+	assert.Error(t, validateLayout(config.LayoutSettings{Commands: "deep"}))
+	assert.Error(t, validateLayout(config.LayoutSettings{FileName: "{owner}"}))
+}
+
+func TestNestedLayout(t *testing.T) {
+	repo, _ := writeCommandRepo(t)
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	owner := repositoryOwner(repo)
+	require.NotEmpty(t, owner)
+	name := QualifiedName(owner, "tool")
+
+	t.Setenv(config.ConfigEnv, filepath.Join(t.TempDir(), "config.yaml"))
+	require.NoError(t, os.WriteFile(config.ProjectFileName, []byte("layout:\n  commands: nested\n"), 0644))
+	_, _, err := Install(ctx, InstallOptions{Repository: repo, Version: "v1.0.0"})
+	require.NoError(t, err)
+
+	assert.FileExists(t, filepath.Join(".claude", "commands", owner, "tool", "index.md"))
+	standalonePath := filepath.Join(".claude", "commands", owner+"--tool.md")
+	assert.FileExists(t, standalonePath)
+	locked := readLockFile(t).Commands[name]
+	require.NotNil(t, locked)
+	assert.Contains(t, locked.Standalone, ".claude/commands/"+owner+"--tool.md")
+
+	report, err := Verify(VerifyOptions{ProjectPath: "."})
+	require.NoError(t, err)
+	assert.True(t, report.OK(), "%v", report.Issues)
+
+	// Overrides of nested commands live under the owner too
+	overrides := filepath.Join(".claude", "overrides", owner, "tool")
+	require.NoError(t, os.MkdirAll(overrides, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(overrides, OverrideAppendFile), []byte("Local notes\n"), 0644))
+
+	// Migrating to the flat layout moves the directory, the standalone file and the overrides
+	result, err := MigrateLayout(MigrateLayoutOptions{ProjectPath: ".", Layout: "flat", DryRun: true})
+	require.NoError(t, err)
+	assert.Equal(t, []LayoutMove{{From: name, To: "tool"}}, result.Moved)
+	assert.FileExists(t, standalonePath)
+
+	result, err = MigrateLayout(MigrateLayoutOptions{ProjectPath: ".", Layout: "flat"})
+	require.NoError(t, err)
+	assert.Equal(t, []LayoutMove{{From: name, To: "tool"}}, result.Moved)
+	assert.Empty(t, result.Failed)
+
+	assert.NoDirExists(t, filepath.Join(".claude", "commands", owner))
+	assert.NoFileExists(t, standalonePath)
+	assert.FileExists(t, filepath.Join(".claude", "overrides", "tool", OverrideAppendFile))
+	standalone, err := os.ReadFile(filepath.Join(".claude", "commands", "tool.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(standalone), "Local notes")
+	lock := readLockFile(t)
+	assert.NotContains(t, lock.Commands, name)
+	require.Contains(t, lock.Commands, "tool")
+	assert.Contains(t, lock.Commands["tool"].Standalone, ".claude/commands/tool.md")
+
+	report, err = Verify(VerifyOptions{ProjectPath: "."})
+	require.NoError(t, err)
+	assert.True(t, report.OK(), "%v", report.Issues)
+
+	// And back, using the layout setting
+	result, err = MigrateLayout(MigrateLayoutOptions{ProjectPath: "."})
+	require.NoError(t, err)
+	assert.Equal(t, []LayoutMove{{From: "tool", To: name}}, result.Moved)
+	assert.FileExists(t, standalonePath)
+	assert.FileExists(t, filepath.Join(overrides, OverrideAppendFile))
+
+	// Nested commands are removed and restored by their qualified name
+	require.NoError(t, Remove(RemoveOptions{Name: name, Force: true}))
+	assert.NoDirExists(t, filepath.Join(".claude", "commands", owner))
+	assert.NoFileExists(t, standalonePath)
+
+	require.NoError(t, Restore(RestoreOptions{Name: name}))
+	assert.FileExists(t, filepath.Join(".claude", "commands", owner, "tool", "index.md"))
+	assert.FileExists(t, standalonePath)
+}
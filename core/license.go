@@ -0,0 +1,117 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gifflet/ccmd/pkg/config"
+	"github.com/gifflet/ccmd/pkg/errors"
+	"github.com/gifflet/ccmd/pkg/output"
+)
+
+// licensePolicy returns the allowed licenses and the license_policy of a project. The
+// list is empty when the policy is off or the configuration cannot be loaded.
+func licensePolicy(projectRoot string) ([]string, string, error) {
+	settings, err := config.Load(projectRoot)
+	if err != nil {
+		return nil, "", nil
+	}
+	switch settings.LicensePolicy {
+	case config.LicenseOff:
+		return nil, config.LicenseOff, nil
+	case "", config.LicenseWarn, config.LicenseBlock:
+	default:
+		return nil, "", errors.InvalidInput(fmt.Sprintf("license_policy must be %s, %s or %s, got %q",
+			config.LicenseWarn, config.LicenseBlock, config.LicenseOff, settings.LicensePolicy))
+	}
+	return settings.AllowedLicenses, settings.LicensePolicy, nil
+}
+
+// checkLicense applies allowed_licenses to a command or plugin before it is installed.
+// A missing license counts as disallowed. Violations are printed as warnings; with the
+// block policy they fail the install.
+func checkLicense(projectRoot, source string, metadata *ProjectConfig) error {
+	allowed, policy, err := licensePolicy(projectRoot)
+	if err != nil || len(allowed) == 0 {
+		return err
+	}
+	problem := licenseProblem(metadata.License, allowed)
+	if problem == "" {
+		return nil
+	}
+	if policy == config.LicenseBlock {
+		return errors.Conflict(fmt.Sprintf("%s: %s, refused by license_policy %s", source, problem, config.LicenseBlock))
+	}
+	output.PrintWarningf("%s: %s", source, problem)
+	return nil
+}
+
+// licenseProblem describes why a license is not allowed, or returns "" when it is
+func licenseProblem(license string, allowed []string) string {
+	if strings.TrimSpace(license) == "" {
+		return fmt.Sprintf("no license is declared (allowed_licenses: %s)", strings.Join(allowed, ", "))
+	}
+	if licenseAllowed(license, allowed) {
+		return ""
+	}
+	return fmt.Sprintf("license %s is not in allowed_licenses (%s)", license, strings.Join(allowed, ", "))
+}
+
+// licenseAllowed reports whether a license is in the allowed list, ignoring case. SPDX
+// expressions such as "MIT OR Apache-2.0" are allowed when one of the alternatives is,
+// and an alternative joined with AND when all of its licenses are.
+func licenseAllowed(license string, allowed []string) bool {
+	isAllowed := func(id string) bool {
+		for _, a := range allowed {
+			if strings.EqualFold(strings.TrimSpace(a), id) {
+				return true
+			}
+		}
+		return false
+	}
+
+	expression := strings.NewReplacer("(", " ", ")", " ").Replace(license)
+	for _, alternative := range splitLicenseOperator(expression, "OR") {
+		ids := splitLicenseOperator(alternative, "AND")
+		all := len(ids) > 0
+		for _, id := range ids {
+			all = all && isAllowed(id)
+		}
+		if all {
+			return true
+		}
+	}
+	return false
+}
+
+// splitLicenseOperator splits a license expression at an operator, ignoring case
+func splitLicenseOperator(expression, operator string) []string {
+	var parts []string
+	var current []string
+	for _, field := range strings.Fields(expression) {
+		if strings.EqualFold(field, operator) {
+			parts = append(parts, strings.Join(current, " "))
+			current = nil
+			continue
+		}
+		current = append(current, field)
+	}
+	parts = append(parts, strings.Join(current, " "))
+
+	nonEmpty := parts[:0]
+	for _, part := range parts {
+		if part != "" {
+			nonEmpty = append(nonEmpty, part)
+		}
+	}
+	return nonEmpty
+}
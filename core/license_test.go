@@ -0,0 +1,96 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gifflet/ccmd/pkg/config"
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+func TestLicenseAllowed(t *testing.T) {
+	allowed := []string{"MIT", " Apache-2.0"}
+	for license, want := range map[string]bool{
+		"MIT":                         true,
+		"mit":                         true,
+		"apache-2.0":                  true,
+		"GPL-3.0":                     false,
+		"MIT OR GPL-3.0":              true,
+		"(GPL-3.0 or Apache-2.0)":     true,
+		"MIT AND Apache-2.0":          true,
+		"MIT AND GPL-3.0":             false,
+		"GPL-3.0 OR (MIT AND BSD-3)":  false,
+		"BSD-3 OR MIT AND Apache-2.0": true,
+		"OR":                          false,
+	} {
+		assert.Equal(t, want, licenseAllowed(license, allowed), license)
+	}
+
+	assert.Empty(t, licenseProblem("MIT", allowed))
+	assert.Equal(t, "license GPL-3.0 is not in allowed_licenses (MIT,  Apache-2.0)", licenseProblem("GPL-3.0", allowed))
+	assert.Contains(t, licenseProblem(" ", allowed), "no license is declared")
+}
+
+func TestInstallLicensePolicy(t *testing.T) {
+	repo, _ := writeCommandRepo(t)
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	ctx := context.Background()
+	t.Setenv(config.ConfigEnv, filepath.Join(t.TempDir(), "config.yaml"))
+	t.Setenv("CCMD_ALLOWED_LICENSES", "MIT,Apache-2.0")
+
+	// The test repository declares no license
+	t.Setenv("CCMD_LICENSE_POLICY", config.LicenseBlock)
+	_, _, err := Install(ctx, InstallOptions{Repository: repo})
+	require.Error(t, err)
+	assert.ErrorIs(t, err, errors.ErrConflict)
+	assert.Contains(t, err.Error(), "no license is declared")
+	assert.NoDirExists(t, filepath.Join(".claude", "commands", "tool"))
+
+	t.Setenv("CCMD_LICENSE_POLICY", "sometimes")
+	_, _, err = Install(ctx, InstallOptions{Repository: repo})
+	assert.ErrorIs(t, err, errors.ErrInvalidInput)
+
+	// The default policy only warns
+	t.Setenv("CCMD_LICENSE_POLICY", "")
+	_, _, err = Install(ctx, InstallOptions{Repository: repo})
+	require.NoError(t, err)
+
+	dir := strings.TrimPrefix(repo, "file://")
+	metadata := "name: tool\nversion: 1.0.0\ndescription: A tool\nauthor: acme\nlicense: mit\nrepository: https://github.com/acme/tool\nentry: index.md\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ConfigFileName), []byte(metadata), 0644))
+	for _, args := range [][]string{{"commit", "--quiet", "--all", "-m", "license"}, {"tag", "--force", "v1.0.0"}} {
+		out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	t.Setenv("CCMD_LICENSE_POLICY", config.LicenseBlock)
+	_, _, err = Install(ctx, InstallOptions{Repository: repo, Version: "v1.0.0", Force: true})
+	require.NoError(t, err)
+
+	lockFile, err := ReadLockFile(lockFilePath("."))
+	require.NoError(t, err)
+	assert.Equal(t, "mit", lockFile.Commands["tool"].License)
+
+	filter, err := ParseListFilter("license=MIT")
+	require.NoError(t, err)
+	details, err := List(ListOptions{ProjectPath: ".", Filters: []ListFilter{filter}})
+	require.NoError(t, err)
+	require.Len(t, details, 1)
+	assert.Equal(t, "mit", details[0].License)
+}
@@ -0,0 +1,504 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/gifflet/ccmd/internal/fs"
+	"github.com/gifflet/ccmd/pkg/errors"
+	"github.com/gifflet/ccmd/pkg/schema"
+)
+
+// Severity levels for lint diagnostics
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityInfo    = "info"
+)
+
+const (
+	// LintWarnFileSize is the size above which a file produces a warning
+	LintWarnFileSize = 1 << 20
+	// LintMaxFileSize is the size above which a file is rejected
+	LintMaxFileSize = 10 << 20
+)
+
+var (
+	semverPattern      = regexp.MustCompile(`^v?(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)(-[0-9A-Za-z.-]+)?(\+[0-9A-Za-z.-]+)?$`)
+	commandNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+	markdownLink       = regexp.MustCompile(`!?\[[^\]]*\]\(([^)\s]+)(?:\s+"[^"]*")?\)`)
+
+	// disallowedContent flags secrets that must never be published in a command repository
+	disallowedContent = map[string]*regexp.Regexp{
+		"private key":          regexp.MustCompile(`-----BEGIN (RSA |EC |DSA |OPENSSH |PGP )?PRIVATE KEY( BLOCK)?-----`),
+		"AWS access key":       regexp.MustCompile(`\bAKIA[0-9A-Z]{16}\b`),
+		"GitHub token":         regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{36,}\b`),
+		"Anthropic API key":    regexp.MustCompile(`\bsk-ant-[A-Za-z0-9_-]{20,}`),
+		"Slack token":          regexp.MustCompile(`\bxox[abprs]-[A-Za-z0-9-]{10,}`),
+		"generic bearer token": regexp.MustCompile(`(?i)authorization:\s*bearer\s+[A-Za-z0-9._-]{20,}`),
+	}
+)
+
+// Diagnostic is a single lint finding
+type Diagnostic struct {
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	File     string `json:"file,omitempty"`
+	Line     int    `json:"line,omitempty"`
+	Column   int    `json:"column,omitempty"`
+	Message  string `json:"message"`
+	// Snippet shows the offending line with a caret under the column
+	Snippet string `json:"snippet,omitempty"`
+}
+
+// String formats the diagnostic as file:line:column: severity [rule] message
+func (d Diagnostic) String() string {
+	location := d.File
+	if location == "" {
+		location = "."
+	}
+	if d.Line > 0 {
+		location = fmt.Sprintf("%s:%d", location, d.Line)
+		if d.Column > 0 {
+			location = fmt.Sprintf("%s:%d", location, d.Column)
+		}
+	}
+	return fmt.Sprintf("%s: %s [%s] %s", location, d.Severity, d.Rule, d.Message)
+}
+
+// LintReport holds the diagnostics for a command repository
+type LintReport struct {
+	Path        string       `json:"path"`
+	Diagnostics []Diagnostic `json:"diagnostics"`
+	Errors      int          `json:"errors"`
+	Warnings    int          `json:"warnings"`
+}
+
+// Lint checks a command repository before it is published. In a project, whose ccmd.yaml
+// lists commands instead of describing one, only ccmd.yaml is checked.
+func Lint(path string) (*LintReport, error) {
+	if !dirExists(path) {
+		return nil, errors.NotFound(fmt.Sprintf("directory %q", path))
+	}
+
+	l := &linter{root: path}
+	if project, parsed := l.checkConfig(); !project {
+		var metadata *ProjectConfig
+		if parsed {
+			metadata = l.checkMetadata()
+		}
+		if metadata == nil || metadata.Type != "plugin" {
+			l.checkIndex()
+		}
+		if err := l.checkFiles(); err != nil {
+			return nil, err
+		}
+	}
+
+	sort.SliceStable(l.diagnostics, func(i, j int) bool {
+		a, b := l.diagnostics[i], l.diagnostics[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+
+	report := &LintReport{Path: path, Diagnostics: l.diagnostics}
+	if report.Diagnostics == nil {
+		report.Diagnostics = []Diagnostic{}
+	}
+	for _, d := range report.Diagnostics {
+		switch d.Severity {
+		case SeverityError:
+			report.Errors++
+		case SeverityWarning:
+			report.Warnings++
+		}
+	}
+
+	return report, nil
+}
+
+type linter struct {
+	root        string
+	diagnostics []Diagnostic
+}
+
+func (l *linter) report(severity, rule, file string, line int, format string, args ...interface{}) {
+	l.diagnostics = append(l.diagnostics, Diagnostic{
+		Rule:     rule,
+		Severity: severity,
+		File:     file,
+		Line:     line,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// checkConfig reports every syntax error of ccmd.yaml with its line and column. In a
+// project's ccmd.yaml it also reports invalid entries of commands, plugins and profiles;
+// command metadata is checked by checkMetadata once it parses.
+func (l *linter) checkConfig() (project, parsed bool) {
+	data, err := os.ReadFile(filepath.Join(l.root, ConfigFileName))
+	if err != nil {
+		return false, true
+	}
+
+	var doc map[string]interface{}
+	parsed = yaml.Unmarshal(data, &doc) == nil
+	if parsed && doc["name"] == nil && doc["version"] == nil {
+		for _, key := range []string{"commands", "plugins", "profiles"} {
+			if _, ok := doc[key]; ok {
+				project = true
+			}
+		}
+	}
+	if parsed && !project {
+		return false, true
+	}
+
+	for _, problem := range checkConfigData(ConfigFileName, data) {
+		l.diagnostics = append(l.diagnostics, Diagnostic{
+			Rule:     "config",
+			Severity: SeverityError,
+			File:     problem.File,
+			Line:     problem.Line,
+			Column:   problem.Column,
+			Message:  problem.Message,
+			Snippet:  problem.Snippet(),
+		})
+	}
+	return project, parsed
+}
+
+// checkMetadata validates the metadata from ccmd.yaml and the entry file's front matter,
+// and returns it when it could be parsed
+func (l *linter) checkMetadata() *ProjectConfig {
+	sources, err := loadMetadataSources(l.root)
+	if err != nil {
+		l.report(SeverityError, "metadata-parse", ConfigFileName, 0, "invalid YAML: %v", err)
+		return nil
+	}
+	// Invalid front matter is reported by checkIndex
+	if !sources.found() && sources.frontErr == nil {
+		l.report(SeverityError, "metadata-missing", ConfigFileName, 0,
+			"ccmd.yaml not found and %s front matter does not name the command", sources.entry)
+		return nil
+	}
+
+	// keyLocation finds the file and line defining a top-level key. Missing keys are
+	// reported against ccmd.yaml when it exists.
+	keyLocation := func(key string) (string, int) {
+		if _, ok := sources.config[key]; ok {
+			return ConfigFileName, yamlKeyLine(sources.configData, key)
+		}
+		if _, ok := sources.frontMatter[key]; ok {
+			return sources.entry, yamlKeyLine(sources.frontData, key) + 1
+		}
+		if sources.config != nil {
+			return ConfigFileName, 0
+		}
+		return sources.entry, 0
+	}
+
+	// Values of the wrong type are reported and left out, so the remaining checks still
+	// run. Missing fields are reported below, with the rules that predate the schema.
+	merged := sources.merged()
+	for _, v := range schema.Command().Validate(merged) {
+		if v.Keyword == "required" || v.Path == "type" {
+			continue
+		}
+		key := strings.SplitN(strings.SplitN(v.Path, ".", 2)[0], "[", 2)[0]
+		file, line := keyLocation(key)
+		l.report(SeverityError, "schema", file, line, "%s", v)
+		if v.Keyword == "type" {
+			delete(merged, key)
+		}
+	}
+
+	metadata, err := decodeMetadata(merged)
+	if err != nil {
+		l.report(SeverityError, "metadata-parse", ConfigFileName, 0, "%v", err)
+		return nil
+	}
+
+	required := []struct {
+		field string
+		value string
+	}{
+		{"name", metadata.Name},
+		{"version", metadata.Version},
+		{"description", metadata.Description},
+		{"author", metadata.Author},
+		{"repository", metadata.Repository},
+	}
+	if metadata.Type != "plugin" {
+		required = append(required, struct {
+			field string
+			value string
+		}{"entry", metadata.Entry})
+	}
+	for _, r := range required {
+		if strings.TrimSpace(r.value) == "" {
+			file, line := keyLocation(r.field)
+			l.report(SeverityError, "required-field", file, line, "%s is required", r.field)
+		}
+	}
+
+	if metadata.Type != "" && metadata.Type != "plugin" && metadata.Type != "command" {
+		file, line := keyLocation("type")
+		l.report(SeverityError, "type", file, line,
+			"type must be \"command\" or \"plugin\", got %q", metadata.Type)
+	}
+
+	if metadata.Name != "" {
+		if err := validateCommandName(metadata.Name); err != nil {
+			file, line := keyLocation("name")
+			l.report(SeverityError, "name-format", file, line, "%v", err)
+		} else if !commandNamePattern.MatchString(metadata.Name) {
+			file, line := keyLocation("name")
+			l.report(SeverityWarning, "name-format", file, line,
+				"name %q should use lowercase letters, digits, '.', '_' and '-'", metadata.Name)
+		}
+	}
+
+	if metadata.Version != "" && !semverPattern.MatchString(metadata.Version) {
+		file, line := keyLocation("version")
+		l.report(SeverityError, "semver", file, line,
+			"version %q is not a semantic version (MAJOR.MINOR.PATCH)", metadata.Version)
+	}
+
+	if metadata.Entry != "" && !fileExists(filepath.Join(l.root, metadata.Entry)) {
+		file, line := keyLocation("entry")
+		l.report(SeverityError, "entry-missing", file, line,
+			"entry file %q does not exist", metadata.Entry)
+	}
+
+	if metadata.Exec != "" {
+		file, line := keyLocation("exec")
+		if err := validateExecPath(metadata.Exec); err != nil {
+			l.report(SeverityError, "exec-path", file, line, "%v", err)
+		} else if _, ok := metadata.Assets[metadata.Exec]; ok {
+			// The script is a binary downloaded on install
+		} else if info, err := os.Stat(filepath.Join(l.root, metadata.Exec)); err != nil || info.IsDir() {
+			l.report(SeverityError, "exec-missing", file, line, "exec script %q does not exist", metadata.Exec)
+		} else if runtime.GOOS != "windows" && info.Mode()&0111 == 0 {
+			l.report(SeverityWarning, "exec-mode", file, line,
+				"exec script %q is not executable; run chmod +x and commit the mode", metadata.Exec)
+		}
+	}
+
+	if err := validateAssets(metadata.Assets); err != nil {
+		file, line := keyLocation("assets")
+		l.report(SeverityError, "assets", file, line, "%v", err)
+	}
+
+	return metadata
+}
+
+// checkIndex verifies index.md exists and has well-formed front matter
+func (l *linter) checkIndex() {
+	const file = "index.md"
+
+	data, err := os.ReadFile(filepath.Join(l.root, file))
+	if err != nil {
+		l.report(SeverityError, "index-missing", file, 0, "index.md not found; it is required to build the standalone command file")
+		return
+	}
+	data = fs.NormalizeNewlines(data)
+
+	if len(bytes.TrimSpace(data)) == 0 {
+		l.report(SeverityError, "index-empty", file, 1, "index.md is empty")
+		return
+	}
+
+	if !bytes.HasPrefix(data, []byte("---\n")) {
+		l.report(SeverityInfo, "front-matter", file, 1, "no front matter; consider adding a description")
+		return
+	}
+
+	rest := data[bytes.IndexByte(data, '\n')+1:]
+	end := bytes.Index(rest, []byte("\n---"))
+	if end == -1 && !bytes.HasPrefix(rest, []byte("---")) {
+		l.report(SeverityError, "front-matter", file, 1, "front matter is not closed with ---")
+		return
+	}
+	if end == -1 {
+		end = 0
+	}
+
+	var frontMatter map[string]interface{}
+	if err := yaml.Unmarshal(rest[:end], &frontMatter); err != nil {
+		l.report(SeverityError, "front-matter", file, 2, "invalid front matter: %v", err)
+		return
+	}
+
+	if _, ok := frontMatter["description"]; !ok {
+		l.report(SeverityWarning, "front-matter", file, 1, "front matter has no description")
+	}
+}
+
+// checkFiles walks the repository for oversized files, broken links and disallowed content
+func (l *linter) checkFiles() error {
+	return filepath.Walk(l.root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(l.root, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		if info.IsDir() {
+			if info.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if info.Mode()&os.ModeSymlink != 0 {
+			l.checkSymlink(path, rel)
+			return nil
+		}
+
+		switch {
+		case info.Size() > LintMaxFileSize:
+			l.report(SeverityError, "file-size", rel, 0, "file is %s (limit %s)", FormatSize(info.Size()), FormatSize(LintMaxFileSize))
+			return nil
+		case info.Size() > LintWarnFileSize:
+			l.report(SeverityWarning, "file-size", rel, 0, "file is %s; large files slow down installs", FormatSize(info.Size()))
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if bytes.IndexByte(data, 0) != -1 {
+			// Binary file, skip text checks
+			return nil
+		}
+
+		l.checkContent(rel, data)
+		if strings.EqualFold(filepath.Ext(path), ".md") {
+			l.checkLinks(path, rel, data)
+		}
+
+		return nil
+	})
+}
+
+// checkSymlink rejects links that point outside the repository and warns about the
+// others, which installs leave out
+func (l *linter) checkSymlink(path, rel string) {
+	target, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		l.report(SeverityError, "symlink", rel, 0, "broken symbolic link")
+		return
+	}
+
+	root, err := filepath.EvalSymlinks(l.root)
+	if err != nil {
+		return
+	}
+	if inside, err := filepath.Rel(root, target); err != nil || strings.HasPrefix(inside, "..") {
+		l.report(SeverityError, "symlink", rel, 0, "symbolic link points outside the repository")
+		return
+	}
+	l.report(SeverityWarning, "symlink", rel, 0, "symbolic links are not installed; commit a copy of the file")
+}
+
+func (l *linter) checkContent(rel string, data []byte) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), LintMaxFileSize)
+
+	kinds := make([]string, 0, len(disallowedContent))
+	for kind := range disallowedContent {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		for _, kind := range kinds {
+			if disallowedContent[kind].MatchString(text) {
+				l.report(SeverityError, "disallowed-content", rel, line, "possible %s", kind)
+			}
+		}
+	}
+}
+
+func (l *linter) checkLinks(path, rel string, data []byte) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), LintMaxFileSize)
+
+	inFence := false
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if strings.HasPrefix(strings.TrimSpace(text), "```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+
+		for _, match := range markdownLink.FindAllStringSubmatch(text, -1) {
+			target := match[1]
+			if isExternalLink(target) {
+				continue
+			}
+			if idx := strings.IndexAny(target, "#?"); idx != -1 {
+				target = target[:idx]
+			}
+			if target == "" {
+				continue
+			}
+
+			resolved := filepath.Join(filepath.Dir(path), filepath.FromSlash(target))
+			if strings.HasPrefix(target, "/") {
+				resolved = filepath.Join(l.root, filepath.FromSlash(target))
+			}
+			if _, err := os.Stat(resolved); err != nil {
+				l.report(SeverityError, "broken-link", rel, line, "link target %q does not exist", match[1])
+			}
+		}
+	}
+}
+
+func isExternalLink(target string) bool {
+	return strings.Contains(target, "://") ||
+		strings.HasPrefix(target, "mailto:") ||
+		strings.HasPrefix(target, "#")
+}
+
+// yamlKeyLine returns the line of a top-level key in a YAML document, or 0 when absent
+func yamlKeyLine(data []byte, key string) int {
+	for i, line := range strings.Split(string(data), "\n") {
+		if strings.HasPrefix(line, key+":") {
+			return i + 1
+		}
+	}
+	return 0
+}
@@ -14,6 +14,7 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/gifflet/ccmd/pkg/errors"
@@ -38,11 +39,84 @@ type CommandDetail struct {
 	Entry    string
 	Requires string
 	Resolved string
+	// Branch is the branch the command tracks, when it was installed from one
+	Branch string
+	// DefaultVersion is how the version was picked when the command was installed without
+	// one, latest-release or default-branch
+	DefaultVersion string
+	// Size is the installed size in bytes, from the lock file or measured on disk
+	Size int64
+	// Instance is set for side-by-side installs made with --as
+	Instance bool
+	// Ephemeral is set for installs made with --no-save, which ccmd.yaml does not list
+	Ephemeral bool
+	// LocalOnly is set for commands tracked with source local, which ccmd.yaml does not list
+	LocalOnly bool
+	// Pinned is set for commands frozen at their commit with 'ccmd pin'
+	Pinned bool
 }
 
+// Sort orders accepted by ListOptions.Sort
+const (
+	ListSortName      = "name"
+	ListSortUpdated   = "updated"   // most recently updated first
+	ListSortInstalled = "installed" // most recently installed first
+	ListSortSize      = "size"      // largest first
+)
+
 // ListOptions represents options for listing commands
 type ListOptions struct {
-	ProjectPath string // Path to project root
+	ProjectPath string       // Path to project root
+	Filters     []ListFilter // Keep only commands matching every filter
+	Sort        string       // One of the ListSort orders; empty sorts by name
+	BrokenOnly  bool         // Keep only commands with structural problems
+	NoIndex     bool         // Read every command instead of the index, then rewrite the index
+}
+
+// ListFilter selects commands by a field: tag and license (exact), author and source
+// (substring) or type, ignoring case
+type ListFilter struct {
+	Field string
+	Value string
+}
+
+// ParseListFilter parses a field=value filter such as "tag=cli"
+func ParseListFilter(s string) (ListFilter, error) {
+	field, value, ok := strings.Cut(s, "=")
+	field = strings.ToLower(strings.TrimSpace(field))
+	value = strings.TrimSpace(value)
+	if !ok || value == "" {
+		return ListFilter{}, errors.InvalidInput(fmt.Sprintf("invalid filter %q, expected field=value", s))
+	}
+
+	switch field {
+	case "tag", "license", "author", "source", "type":
+		return ListFilter{Field: field, Value: value}, nil
+	}
+	return ListFilter{}, errors.InvalidInput(fmt.Sprintf("unknown filter field %q (valid: tag, license, author, source, type)", field))
+}
+
+// matches reports whether a command matches the filter
+func (f ListFilter) matches(cmd CommandDetail) bool {
+	value := strings.ToLower(f.Value)
+	switch f.Field {
+	case "tag":
+		for _, tag := range cmd.Tags {
+			if strings.ToLower(tag) == value {
+				return true
+			}
+		}
+		return false
+	case "license":
+		return strings.ToLower(cmd.License) == value
+	case "author":
+		return strings.Contains(strings.ToLower(cmd.Author), value)
+	case "source":
+		return strings.Contains(strings.ToLower(cmd.Repository), value)
+	case "type":
+		return strings.ToLower(cmd.Type) == value
+	}
+	return false
 }
 
 // List returns a list of all installed commands
@@ -56,6 +130,11 @@ func List(opts ListOptions) ([]CommandDetail, error) {
 		opts.ProjectPath = cwd
 	}
 
+	sortLess, err := listSort(opts.Sort)
+	if err != nil {
+		return nil, err
+	}
+
 	// Find project root
 	projectRoot, err := findProjectRootFrom(opts.ProjectPath)
 	if err != nil {
@@ -63,7 +142,7 @@ func List(opts ListOptions) ([]CommandDetail, error) {
 	}
 
 	// Read lock file
-	lockPath := filepath.Join(projectRoot, "ccmd-lock.yaml")
+	lockPath := lockFilePath(projectRoot)
 	if !fileExists(lockPath) {
 		// No lock file means no commands installed
 		return []CommandDetail{}, nil
@@ -74,53 +153,63 @@ func List(opts ListOptions) ([]CommandDetail, error) {
 		return nil, err
 	}
 
+	index := loadIndex(projectRoot, opts.NoIndex)
+	index.prune(lockData)
+	defer index.save()
+
 	// Build command list
 	var commands []CommandDetail
-	commandsDir := filepath.Join(projectRoot, ".claude", "commands")
+	commandsDir := projectCommandsDir(projectRoot)
+	targets, err := projectTargets(projectRoot)
+	if err != nil {
+		targets = defaultTargets
+	}
 
 	for name, info := range lockData.Commands {
 		cmd := CommandDetail{
-			Name:        name,
-			Version:     info.Version,
-			Repository:  info.Source,
-			UpdatedAt:   info.UpdatedAt.Format(time.RFC3339),
-			InstalledAt: info.InstalledAt.Format(time.RFC3339),
-			Resolved:    info.Resolved,
-			Type:        "command",
+			Name:           name,
+			Version:        info.Version,
+			Repository:     info.Source,
+			UpdatedAt:      info.UpdatedAt.Format(time.RFC3339),
+			InstalledAt:    info.InstalledAt.Format(time.RFC3339),
+			Resolved:       info.Resolved,
+			Branch:         info.Branch,
+			DefaultVersion: info.DefaultVersion,
+			Type:           "command",
+			Size:           info.FileSize,
+			License:        info.License,
+			Instance:       info.Instance,
+			Ephemeral:      info.Ephemeral,
+			LocalOnly:      info.Local(),
+			Pinned:         info.Pinned,
 		}
 
 		// Check command structure
 		cmdDir := filepath.Join(commandsDir, name)
-		mdFile := filepath.Join(commandsDir, name+".md")
 
 		if !dirExists(cmdDir) {
 			cmd.BrokenStructure = true
 			cmd.StructureError = "command directory not found"
-		} else if !fileExists(mdFile) {
-			cmd.BrokenStructure = true
-			cmd.StructureError = "standalone .md file not found"
-		}
-
-		// Read command metadata if available
-		if dirExists(cmdDir) {
-			metadataPath := filepath.Join(cmdDir, "ccmd.yaml")
-			if metadata, err := readCommandMetadata(metadataPath); err == nil {
-				if metadata.Description != "" {
-					cmd.Description = metadata.Description
-				}
-				if metadata.Author != "" {
-					cmd.Author = metadata.Author
+		} else {
+			for _, target := range targets {
+				if !fileExists(target.file(projectRoot, name)) {
+					cmd.BrokenStructure = true
+					cmd.StructureError = "standalone .md file not found"
+					break
 				}
-				if metadata.Version != "" && cmd.Version == "" {
-					cmd.Version = metadata.Version
-				}
-				cmd.Tags = metadata.Tags
-				cmd.License = metadata.License
-				cmd.Homepage = metadata.Homepage
-				cmd.Entry = metadata.Entry
 			}
 		}
 
+		entry := index.command(projectRoot, name)
+
+		// Lock entries written before sizes were recorded are measured
+		if cmd.Size == 0 {
+			cmd.Size = index.size(entry, cmdDir)
+		}
+
+		cmd.applyMetadata(entry)
+		cmd.Entry = entry.Entry
+
 		commands = append(commands, cmd)
 	}
 
@@ -135,6 +224,9 @@ func List(opts ListOptions) ([]CommandDetail, error) {
 			InstalledAt: info.InstalledAt.Format(time.RFC3339),
 			Resolved:    info.Resolved,
 			Type:        "plugin",
+			Size:        info.FileSize,
+			License:     info.License,
+			Ephemeral:   info.Ephemeral,
 		}
 
 		pluginDir := filepath.Join(pluginsDir, name)
@@ -144,33 +236,79 @@ func List(opts ListOptions) ([]CommandDetail, error) {
 			cmd.StructureError = "plugin directory not found"
 		}
 
-		if dirExists(pluginDir) {
-			metadataPath := filepath.Join(pluginDir, "ccmd.yaml")
-			if metadata, err := readCommandMetadata(metadataPath); err == nil {
-				if metadata.Description != "" {
-					cmd.Description = metadata.Description
-				}
-				if metadata.Author != "" {
-					cmd.Author = metadata.Author
-				}
-				if metadata.Version != "" && cmd.Version == "" {
-					cmd.Version = metadata.Version
-				}
-				cmd.Tags = metadata.Tags
-				cmd.License = metadata.License
-				cmd.Homepage = metadata.Homepage
-			}
+		entry := index.plugin(projectRoot, name)
+		if cmd.Size == 0 {
+			cmd.Size = index.size(entry, pluginDir)
 		}
 
+		cmd.applyMetadata(entry)
+
 		commands = append(commands, cmd)
 	}
 
-	// Sort by name
-	sort.Slice(commands, func(i, j int) bool {
-		return commands[i].Name < commands[j].Name
+	selected := commands[:0]
+	for _, cmd := range commands {
+		if opts.BrokenOnly && !cmd.BrokenStructure {
+			continue
+		}
+		matched := true
+		for _, filter := range opts.Filters {
+			matched = matched && filter.matches(cmd)
+		}
+		if matched {
+			selected = append(selected, cmd)
+		}
+	}
+
+	// Ties keep name order
+	sort.Slice(selected, func(i, j int) bool {
+		return selected[i].Name < selected[j].Name
+	})
+	sort.SliceStable(selected, func(i, j int) bool {
+		return sortLess(selected[i], selected[j])
 	})
 
-	return commands, nil
+	return selected, nil
+}
+
+// applyMetadata copies the ccmd.yaml metadata of an index entry. The version and
+// license are kept when the lock file recorded them.
+func (cmd *CommandDetail) applyMetadata(entry *indexEntry) {
+	if entry.Description != "" {
+		cmd.Description = entry.Description
+	}
+	if entry.Author != "" {
+		cmd.Author = entry.Author
+	}
+	if entry.Version != "" && cmd.Version == "" {
+		cmd.Version = entry.Version
+	}
+	cmd.Tags = entry.Tags
+	if entry.License != "" && cmd.License == "" {
+		cmd.License = entry.License
+	}
+	cmd.Homepage = entry.Homepage
+}
+
+// listSort returns the ordering of a ListOptions.Sort value
+func listSort(order string) (func(a, b CommandDetail) bool, error) {
+	switch order {
+	case "", ListSortName:
+		return func(a, b CommandDetail) bool { return a.Name < b.Name }, nil
+	case ListSortUpdated:
+		return func(a, b CommandDetail) bool { return parseListTime(a.UpdatedAt).After(parseListTime(b.UpdatedAt)) }, nil
+	case ListSortInstalled:
+		return func(a, b CommandDetail) bool { return parseListTime(a.InstalledAt).After(parseListTime(b.InstalledAt)) }, nil
+	case ListSortSize:
+		return func(a, b CommandDetail) bool { return a.Size > b.Size }, nil
+	}
+	return nil, errors.InvalidInput(fmt.Sprintf("unknown sort order %q (valid: name, updated, installed, size)", order))
+}
+
+// parseListTime parses a CommandDetail timestamp; unparsable ones sort last
+func parseListTime(s string) time.Time {
+	t, _ := time.Parse(time.RFC3339, s)
+	return t
 }
 
 // GetCommandInfo returns detailed information about a specific command
@@ -188,21 +326,3 @@ func GetCommandInfo(name, projectPath string) (*CommandDetail, error) {
 
 	return nil, errors.NotFound(fmt.Sprintf("command %q", name))
 }
-
-func findProjectRootFrom(startPath string) (string, error) {
-	dir := startPath
-
-	for {
-		if fileExists(filepath.Join(dir, "ccmd.yaml")) {
-			return dir, nil
-		}
-
-		parent := filepath.Dir(dir)
-		if parent == dir {
-			// Reached root without finding ccmd.yaml
-			// Use start path as project root
-			return startPath, nil
-		}
-		dir = parent
-	}
-}
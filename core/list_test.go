@@ -194,6 +194,83 @@ func TestList(t *testing.T) {
 	})
 }
 
+func TestListFilterAndSort(t *testing.T) {
+	tempDir := t.TempDir()
+
+	lockFile := createBasicLockFile()
+	for i, name := range []string{"alpha", "beta", "gamma"} {
+		lockFile.Commands[name] = &LockCommand{
+			Name:        name,
+			Version:     "1.0.0",
+			Source:      "https://github.com/" + name + "-org/" + name + ".git",
+			InstalledAt: time.Now().Add(-time.Duration(i) * time.Hour),
+			UpdatedAt:   time.Now().Add(-time.Duration(3-i) * time.Hour),
+			FileSize:    int64(100 * (i + 1)),
+		}
+	}
+	writeLockFileToPath(t, filepath.Join(tempDir, "ccmd-lock.yaml"), lockFile)
+
+	commandsDir := filepath.Join(tempDir, ".claude", "commands")
+	metadata := map[string]string{
+		"alpha": "author: Jane Doe\ntags: [cli, git]\n",
+		"beta":  "author: John Roe\ntags: [CLI]\n",
+	}
+	for _, name := range []string{"alpha", "beta", "gamma"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(commandsDir, name), 0755))
+		if content, ok := metadata[name]; ok {
+			require.NoError(t, os.WriteFile(filepath.Join(commandsDir, name, "ccmd.yaml"), []byte(content), 0644))
+		}
+	}
+	// gamma has no standalone .md file
+	for _, name := range []string{"alpha", "beta"} {
+		require.NoError(t, os.WriteFile(filepath.Join(commandsDir, name+".md"), []byte("# "+name), 0644))
+	}
+
+	names := func(details []CommandDetail) []string {
+		result := make([]string, len(details))
+		for i, d := range details {
+			result[i] = d.Name
+		}
+		return result
+	}
+	list := func(opts ListOptions) []string {
+		opts.ProjectPath = tempDir
+		details, err := List(opts)
+		require.NoError(t, err)
+		return names(details)
+	}
+
+	filter := func(s string) ListFilter {
+		f, err := ParseListFilter(s)
+		require.NoError(t, err)
+		return f
+	}
+
+	assert.Equal(t, []string{"alpha", "beta"}, list(ListOptions{Filters: []ListFilter{filter("tag=cli")}}))
+	assert.Equal(t, []string{"alpha"}, list(ListOptions{Filters: []ListFilter{filter("tag=cli"), filter("author=jane")}}))
+	assert.Equal(t, []string{"gamma"}, list(ListOptions{Filters: []ListFilter{filter("source=gamma-org")}}))
+	assert.Empty(t, list(ListOptions{Filters: []ListFilter{filter("type=plugin")}}))
+	assert.Equal(t, []string{"gamma"}, list(ListOptions{BrokenOnly: true}))
+
+	assert.Equal(t, []string{"gamma", "beta", "alpha"}, list(ListOptions{Sort: ListSortSize}))
+	assert.Equal(t, []string{"gamma", "beta", "alpha"}, list(ListOptions{Sort: ListSortUpdated}))
+	assert.Equal(t, []string{"alpha", "beta", "gamma"}, list(ListOptions{Sort: ListSortInstalled}))
+
+	_, err := List(ListOptions{ProjectPath: tempDir, Sort: "color"})
+	assert.Error(t, err)
+}
+
+func TestParseListFilter(t *testing.T) {
+	f, err := ParseListFilter("Tag= cli ")
+	require.NoError(t, err)
+	assert.Equal(t, ListFilter{Field: "tag", Value: "cli"}, f)
+
+	for _, s := range []string{"cli", "tag=", "=cli", "color=red"} {
+		_, err := ParseListFilter(s)
+		assert.Error(t, err, s)
+	}
+}
+
 func TestGetCommandInfo(t *testing.T) {
 	t.Run("returns command info when exists", func(t *testing.T) {
 		tempDir := t.TempDir()
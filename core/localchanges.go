@@ -0,0 +1,161 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	ccmdfs "github.com/gifflet/ccmd/internal/fs"
+	"github.com/gifflet/ccmd/pkg/errors"
+	"github.com/gifflet/ccmd/pkg/output"
+)
+
+const backupTimeFormat = "20060102-150405"
+
+// contentChecksum hashes the files of an installed command as sha256:<hex>. Paths are
+// hashed in sorted order and line endings are normalized, so a checkout with CRLF line
+// endings matches the original. The .git directory is not part of the content.
+func contentChecksum(dir string) (string, error) {
+	files, err := contentFiles(dir)
+	if err != nil {
+		return "", err
+	}
+	return checksumFiles(dir, files)
+}
+
+// contentFiles returns the slash-separated paths of the files in dir outside .git, sorted
+func contentFiles(dir string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Name() == ".git" {
+			// Submodules have a .git file pointing into the repository's .git directory
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// checksumFiles hashes the listed files of dir like contentChecksum. Missing files are
+// left out, so deleting one changes the checksum.
+func checksumFiles(dir string, files []string) (string, error) {
+	hash := sha256.New()
+	for _, file := range files {
+		data, err := os.ReadFile(ccmdfs.LongPath(filepath.Join(dir, filepath.FromSlash(file))))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(hash, "%s\x00", file)
+		hash.Write(ccmdfs.NormalizeNewlines(data))
+		hash.Write([]byte{0})
+	}
+
+	return "sha256:" + hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// hasLocalChanges reports whether an installed command differs from the content recorded
+// in the lock file. Entries written before checksums were recorded are never reported.
+func hasLocalChanges(projectRoot, name string) (bool, error) {
+	lockPath := lockFilePath(projectRoot)
+	if !fileExists(lockPath) {
+		return false, nil
+	}
+	lockFile, err := ReadLockFile(lockPath)
+	if err != nil {
+		return false, err
+	}
+	cmd, ok := lockFile.Commands[name]
+	if !ok || cmd.Checksum == "" {
+		return false, nil
+	}
+
+	dir := installedCommandDir(projectRoot, name)
+	if !dirExists(dir) {
+		return false, nil
+	}
+	// Filtered installs are compared on the files they installed
+	var checksum string
+	if len(cmd.Files) > 0 {
+		checksum, err = checksumFiles(dir, cmd.Files)
+	} else {
+		checksum, err = contentChecksum(dir)
+	}
+	if err != nil {
+		return false, errors.FileError("checksum command files", dir, err)
+	}
+
+	return checksum != cmd.Checksum, nil
+}
+
+// protectLocalChanges is called before an installed command is overwritten. A command
+// with local modifications is only replaced when overwrite is set; with backup its
+// files are first copied to .claude/.backups/<name>-<timestamp>.
+func protectLocalChanges(projectRoot, name string, overwrite, backup bool) error {
+	modified, err := hasLocalChanges(projectRoot, name)
+	if err != nil || !modified {
+		return err
+	}
+
+	if !overwrite && !backup {
+		return errors.Conflict(fmt.Sprintf(
+			"command %q has local modifications; review them with 'ccmd diff %s', "+
+				"then use --backup to keep a copy or --overwrite-local to discard them",
+			name, name))
+	}
+
+	output.PrintWarningf("Command %q has local modifications", name)
+	if !backup {
+		output.PrintWarningf("Discarding local modifications to %q", name)
+		return nil
+	}
+
+	backupDir := filepath.Join(projectRoot, ".claude", ".backups",
+		fmt.Sprintf("%s-%s", name, time.Now().Format(backupTimeFormat)))
+	if err := os.MkdirAll(filepath.Dir(backupDir), 0755); err != nil {
+		return errors.FileError("create backups directory", filepath.Dir(backupDir), err)
+	}
+	if err := copyDirectory(context.Background(), installedCommandDir(projectRoot, name), backupDir); err != nil {
+		ccmdfs.RemoveAll(backupDir)
+		return errors.FileError("back up command files", backupDir, err)
+	}
+
+	rel, _ := filepath.Rel(projectRoot, backupDir)
+	output.PrintInfof("Saved local modifications of %q to %s", name, rel)
+	return nil
+}
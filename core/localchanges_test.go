@@ -0,0 +1,106 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+func TestContentChecksum(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".git"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "index.md"), []byte("Say hello\n"), 0644))
+
+	sum, err := contentChecksum(dir)
+	require.NoError(t, err)
+	assert.Regexp(t, `^sha256:[0-9a-f]{64}$`, sum)
+
+	// Git metadata and line endings do not count as modifications
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".git", "HEAD"), []byte("ref: refs/heads/main"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "index.md"), []byte("Say hello\r\n"), 0644))
+	same, err := contentChecksum(dir)
+	require.NoError(t, err)
+	assert.Equal(t, sum, same)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.md"), []byte(""), 0644))
+	changed, err := contentChecksum(dir)
+	require.NoError(t, err)
+	assert.NotEqual(t, sum, changed)
+}
+
+func TestInstallForceProtectsLocalChanges(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	writeConfig(t, []string{})
+
+	data := buildTarGz(t, map[string]string{"ccmd.yaml": archiveMetadata, "index.md": "Say hello"})
+	require.NoError(t, os.WriteFile("hello.tgz", data, 0644))
+
+	_, _, err := Install(context.Background(), InstallOptions{Repository: "hello.tgz"})
+	require.NoError(t, err)
+	assert.NotEmpty(t, readLockFile(t).Commands["hello"].Checksum)
+
+	// An unmodified command is reinstalled without complaint
+	_, _, err = Install(context.Background(), InstallOptions{Repository: "hello.tgz", Force: true})
+	require.NoError(t, err)
+
+	indexPath := filepath.Join(".claude", "commands", "hello", "index.md")
+	require.NoError(t, os.WriteFile(indexPath, []byte("Say hello, my way"), 0644))
+
+	_, _, err = Install(context.Background(), InstallOptions{Repository: "hello.tgz", Force: true})
+	require.ErrorIs(t, err, errors.ErrConflict)
+	assert.Contains(t, err.Error(), "--overwrite-local")
+	edited, err := os.ReadFile(indexPath)
+	require.NoError(t, err)
+	assert.Equal(t, "Say hello, my way", string(edited))
+
+	_, _, err = Install(context.Background(), InstallOptions{Repository: "hello.tgz", Force: true, Backup: true})
+	require.NoError(t, err)
+
+	backups, err := filepath.Glob(filepath.Join(".claude", ".backups", "hello-*", "index.md"))
+	require.NoError(t, err)
+	require.Len(t, backups, 1)
+	saved, err := os.ReadFile(backups[0])
+	require.NoError(t, err)
+	assert.Equal(t, "Say hello, my way", string(saved))
+
+	restored, err := os.ReadFile(indexPath)
+	require.NoError(t, err)
+	assert.Equal(t, "Say hello", string(restored))
+
+	require.NoError(t, os.WriteFile(indexPath, []byte("Edited again"), 0644))
+	_, _, err = Install(context.Background(), InstallOptions{Repository: "hello.tgz", Force: true, OverwriteLocal: true})
+	require.NoError(t, err)
+	restored, err = os.ReadFile(indexPath)
+	require.NoError(t, err)
+	assert.Equal(t, "Say hello", string(restored))
+}
+
+func TestHasLocalChangesWithoutChecksum(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+
+	lockFile := createBasicLockFile()
+	lockFile.Commands["legacy"] = createTestLockCommand("legacy", "1.0.0", "https://github.com/acme/legacy.git")
+	writeLockFile(t, lockFile)
+	createCommandStructure(t, "legacy")
+
+	modified, err := hasLocalChanges(".", "legacy")
+	require.NoError(t, err)
+	assert.False(t, modified)
+}
@@ -0,0 +1,170 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gifflet/ccmd/internal/fs"
+	"github.com/gifflet/ccmd/pkg/config"
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+// lockWriters serializes the writes of each lock file within the process
+var lockWriters sync.Map
+
+// lockWriter returns the mutex serializing the writes of a lock file
+func lockWriter(path string) *sync.Mutex {
+	mu, _ := lockWriters.LoadOrStore(cacheKey(path), &sync.Mutex{})
+	return mu.(*sync.Mutex)
+}
+
+// lockWriterDir is the directory under the configured cache_dir holding the files that
+// serialize lock file writes across processes
+const lockWriterDir = "locks"
+
+// lockWriterPath returns the file locked while writing the lock file at path. It lives
+// under cache_dir, named after a hash of the absolute path, so the project holds no
+// file for it.
+func lockWriterPath(path string) (string, error) {
+	settings, err := config.Load("")
+	if err != nil {
+		return "", err
+	}
+	dir := settings.CacheDir
+	if dir == "" {
+		dir = filepath.Join(os.TempDir(), "ccmd")
+	}
+	sum := sha256.Sum256([]byte(cacheKey(path)))
+	return filepath.Join(dir, lockWriterDir, hex.EncodeToString(sum[:8])+".lock"), nil
+}
+
+// acquireLockWriter serializes the read-merge-write of a lock file with other goroutines,
+// through lockWriter, and with other processes, through an OS lock on the file returned
+// by lockWriterPath. The returned function releases both.
+func acquireLockWriter(path string) (func(), error) {
+	lockPath, err := lockWriterPath(path)
+	if err != nil {
+		return nil, err
+	}
+	mu := lockWriter(path)
+	mu.Lock()
+	unlock, err := fs.Lock(lockPath)
+	if err != nil {
+		mu.Unlock()
+		return nil, errors.FileError("lock", lockPath, err)
+	}
+	return func() {
+		unlock()
+		mu.Unlock()
+	}, nil
+}
+
+// Reload brings a lock file read with ReadLockFile up to date with the file at path,
+// keeping the changes made to it since it was read. Entries changed both here and in
+// the file, by another goroutine or process, are a conflict: nothing is merged and the
+// error asks to run the command again.
+func (l *LockFile) Reload(path string) error {
+	if !fileExists(path) {
+		return nil
+	}
+	release, err := acquireLockWriter(path)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return l.reload(path)
+}
+
+// reload merges the file at path into l; callers hold acquireLockWriter
+func (l *LockFile) reload(path string) error {
+	if !fileExists(path) {
+		return nil
+	}
+	current, err := ReadLockFile(path)
+	if err != nil {
+		return err
+	}
+
+	base := l.base
+	if base == nil {
+		// Created in memory: every entry of the file is someone else's
+		base = &LockFile{}
+	}
+	var conflicts []string
+	commands, clashes := mergeLockEntries(base.Commands, l.Commands, current.Commands)
+	conflicts = append(conflicts, clashes...)
+	plugins, clashes := mergeLockEntries(base.Plugins, l.Plugins, current.Plugins)
+	for _, name := range clashes {
+		conflicts = append(conflicts, "plugin "+name)
+	}
+	if len(conflicts) > 0 {
+		return errors.Conflict(fmt.Sprintf("%s was changed by another ccmd process while this one was updating it "+
+			"(%s changed by both); run the command again", path, strings.Join(conflicts, ", ")))
+	}
+
+	if l.Version == base.Version {
+		l.Version = current.Version
+	}
+	if l.LockfileVersion == base.LockfileVersion {
+		l.LockfileVersion = current.LockfileVersion
+	}
+	l.Commands = commands
+	l.Plugins = plugins
+	l.base = current.base
+	return nil
+}
+
+// snapshot records the current content of l as the base that later changes are merged
+// against
+func (l *LockFile) snapshot() {
+	l.base = nil
+	base := deepCopy(l).(*LockFile)
+	l.base = base
+}
+
+// mergeLockEntries merges the entries of a lock file changed here (ours) with those in
+// the file (theirs), both derived from base. An entry changed on one side only takes
+// that change; the names of entries changed differently on both sides are returned.
+func mergeLockEntries[T any](base, ours, theirs map[string]*T) (map[string]*T, []string) {
+	names := make(map[string]bool)
+	for _, entries := range []map[string]*T{base, ours, theirs} {
+		for name := range entries {
+			names[name] = true
+		}
+	}
+
+	merged := make(map[string]*T)
+	var conflicts []string
+	for name := range names {
+		b, o, t := base[name], ours[name], theirs[name]
+		entry := o
+		switch {
+		case reflect.DeepEqual(o, b):
+			entry = t
+		case reflect.DeepEqual(t, b), reflect.DeepEqual(o, t):
+		default:
+			conflicts = append(conflicts, name)
+		}
+		if entry != nil {
+			merged[name] = entry
+		}
+	}
+	sort.Strings(conflicts)
+	return merged, conflicts
+}
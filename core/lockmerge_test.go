@@ -0,0 +1,202 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+func TestWriteLockFileMerges(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+
+	lockFile := createBasicLockFile()
+	lockFile.Commands["shared"] = createTestLockCommand("shared", "1.0.0", "https://github.com/acme/shared.git")
+	lockFile.Commands["old"] = createTestLockCommand("old", "1.0.0", "https://github.com/acme/old.git")
+	writeLockFile(t, lockFile)
+
+	first := readLockFileFromPath(t, "ccmd-lock.yaml")
+	second := readLockFileFromPath(t, "ccmd-lock.yaml")
+
+	first.Commands["one"] = createTestLockCommand("one", "1.0.0", "https://github.com/acme/one.git")
+	first.Commands["shared"].Version = "1.1.0"
+	require.NoError(t, WriteLockFile("ccmd-lock.yaml", first))
+
+	second.Commands["two"] = createTestLockCommand("two", "1.0.0", "https://github.com/acme/two.git")
+	delete(second.Commands, "old")
+	require.NoError(t, WriteLockFile("ccmd-lock.yaml", second))
+
+	// Both sides keep what they wrote, and second now holds the merged content
+	assert.ElementsMatch(t, []string{"shared", "one", "two"}, keys(second.Commands))
+	assert.Equal(t, "1.1.0", second.Commands["shared"].Version)
+	merged := readLockFileFromPath(t, "ccmd-lock.yaml")
+	assert.ElementsMatch(t, []string{"shared", "one", "two"}, keys(merged.Commands))
+
+	// Writing the same object again changes nothing
+	require.NoError(t, WriteLockFile("ccmd-lock.yaml", second))
+	assert.ElementsMatch(t, []string{"shared", "one", "two"}, keys(readLockFileFromPath(t, "ccmd-lock.yaml").Commands))
+}
+
+func TestWriteLockFileConflict(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+
+	lockFile := createBasicLockFile()
+	lockFile.Commands["tool"] = createTestLockCommand("tool", "1.0.0", "https://github.com/acme/tool.git")
+	writeLockFile(t, lockFile)
+
+	first := readLockFileFromPath(t, "ccmd-lock.yaml")
+	second := readLockFileFromPath(t, "ccmd-lock.yaml")
+	first.Commands["tool"].Version = "1.1.0"
+	require.NoError(t, WriteLockFile("ccmd-lock.yaml", first))
+
+	second.Commands["tool"].Version = "2.0.0"
+	err := WriteLockFile("ccmd-lock.yaml", second)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, errors.ErrConflict)
+	assert.Contains(t, err.Error(), "(tool changed by both); run the command again")
+	assert.Equal(t, "1.1.0", readLockFileFromPath(t, "ccmd-lock.yaml").Commands["tool"].Version)
+
+	// The same change on both sides is no conflict
+	second = readLockFileFromPath(t, "ccmd-lock.yaml")
+	first.Commands["tool"].Version = "1.2.0"
+	second.Commands["tool"].Version = "1.2.0"
+	require.NoError(t, WriteLockFile("ccmd-lock.yaml", first))
+	require.NoError(t, WriteLockFile("ccmd-lock.yaml", second))
+}
+
+func TestLockFileReload(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+
+	writeLockFile(t, createBasicLockFile())
+	lockFile := readLockFileFromPath(t, "ccmd-lock.yaml")
+	lockFile.Commands["mine"] = createTestLockCommand("mine", "1.0.0", "https://github.com/acme/mine.git")
+
+	// Another process adds an entry
+	external := createBasicLockFile()
+	external.Commands["theirs"] = createTestLockCommand("theirs", "1.0.0", "https://github.com/acme/theirs.git")
+	writeLockFile(t, external)
+
+	require.NoError(t, lockFile.Reload("ccmd-lock.yaml"))
+	assert.ElementsMatch(t, []string{"mine", "theirs"}, keys(lockFile.Commands))
+	assert.ElementsMatch(t, []string{"theirs"}, keys(readLockFileFromPath(t, "ccmd-lock.yaml").Commands))
+}
+
+func TestWriteLockFileConcurrent(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	writeLockFile(t, createBasicLockFile())
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			lockFile, err := ReadLockFile("ccmd-lock.yaml")
+			if err != nil {
+				errs <- err
+				return
+			}
+			name := fmt.Sprintf("cmd%d", i)
+			lockFile.Commands[name] = createTestLockCommand(name, "1.0.0", "https://github.com/acme/"+name+".git")
+			errs <- WriteLockFile("ccmd-lock.yaml", lockFile)
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+	assert.Len(t, readLockFileFromPath(t, "ccmd-lock.yaml").Commands, 20)
+}
+
+// lockWriterProcessEnv makes TestLockWriterProcess add commands to the lock file of the
+// working directory, as a separate ccmd process would
+const lockWriterProcessEnv = "CCMD_TEST_LOCK_WRITER"
+
+func TestLockWriterProcess(t *testing.T) {
+	id := os.Getenv(lockWriterProcessEnv)
+	if id == "" {
+		t.Skip("run by TestWriteLockFileProcesses")
+	}
+	for i := 0; i < 10; i++ {
+		lockFile, err := ReadLockFile("ccmd-lock.yaml")
+		require.NoError(t, err)
+		name := fmt.Sprintf("cmd%s-%d", id, i)
+		lockFile.Commands[name] = createTestLockCommand(name, "1.0.0", "https://github.com/acme/"+name+".git")
+		require.NoError(t, WriteLockFile("ccmd-lock.yaml", lockFile))
+	}
+}
+
+func TestWriteLockFileProcesses(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	t.Setenv("CCMD_CACHE_DIR", t.TempDir())
+	writeLockFile(t, createBasicLockFile())
+
+	// Each process reads, changes and writes the lock file at the same time as the others;
+	// without a lock across processes, one could write over entries another just added
+	processes := make([]*exec.Cmd, 8)
+	for i := range processes {
+		cmd := exec.Command(os.Args[0], "-test.run=^TestLockWriterProcess$")
+		cmd.Env = append(os.Environ(), lockWriterProcessEnv+"="+strconv.Itoa(i))
+		require.NoError(t, cmd.Start())
+		processes[i] = cmd
+	}
+	for _, cmd := range processes {
+		require.NoError(t, cmd.Wait())
+	}
+
+	assert.Len(t, readLockFileFromPath(t, "ccmd-lock.yaml").Commands, 80)
+}
+
+func TestWriteLockFileLeavesOnlyLockFile(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	cacheDir := t.TempDir()
+	t.Setenv("CCMD_CACHE_DIR", cacheDir)
+
+	lockFile := createBasicLockFile()
+	lockFile.Commands["cmd1"] = createTestLockCommand("cmd1", "1.0.0", "https://github.com/acme/cmd1.git")
+	require.NoError(t, WriteLockFile("ccmd-lock.yaml", lockFile))
+
+	entries, err := os.ReadDir(".")
+	require.NoError(t, err)
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	assert.Equal(t, []string{"ccmd-lock.yaml"}, names)
+
+	locks, err := os.ReadDir(filepath.Join(cacheDir, lockWriterDir))
+	require.NoError(t, err)
+	assert.Len(t, locks, 1)
+}
+
+func keys[T any](m map[string]T) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	return names
+}
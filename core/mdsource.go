@@ -0,0 +1,171 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/gifflet/ccmd/internal/fs"
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+const (
+	// MaxMarkdownSize limits the size of a single-file command downloaded from a URL
+	MaxMarkdownSize = 1 << 20
+
+	// markdownVersion is the version given to single-file commands without one
+	markdownVersion = "0.0.0"
+)
+
+var markdownExtensions = []string{".md", ".markdown"}
+
+// IsMarkdownSource reports whether an install source is the http(s) URL of a single
+// markdown file, such as a raw gist, installed as a command without git
+func IsMarkdownSource(source string) bool {
+	if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") {
+		return false
+	}
+	lower := strings.ToLower(source)
+	if idx := strings.IndexAny(lower, "?#"); idx != -1 {
+		lower = lower[:idx]
+	}
+	for _, ext := range markdownExtensions {
+		if strings.HasSuffix(lower, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// isDownloadSource reports whether a source is downloaded rather than cloned: archives
+// and markdown files have no git history, tags or branches
+func isDownloadSource(source string) bool {
+	return IsArchiveSource(source) || IsMarkdownSource(source)
+}
+
+// markdownRepoPath returns the identity of a markdown source. Raw gist URLs change with
+// every revision, so gists are identified by owner, gist id and file name.
+func markdownRepoPath(source string) string {
+	u, err := url.Parse(source)
+	if err != nil {
+		return source
+	}
+
+	p := strings.Trim(u.Path, "/")
+	base := path.Base(p)
+	base = strings.TrimSuffix(base, path.Ext(base))
+
+	// gist.githubusercontent.com/<owner>/<id>/raw[/<revision>]/<file>
+	if u.Host == "gist.githubusercontent.com" {
+		if parts := strings.Split(p, "/"); len(parts) >= 4 && parts[2] == "raw" {
+			return path.Join(parts[0], parts[1], base)
+		}
+	}
+	return path.Join(u.Host, path.Dir(p), base)
+}
+
+// fetchMarkdown downloads a markdown file into dest as index.md, checks it against the
+// expected checksum and writes a ccmd.yaml with the metadata its front matter does not
+// provide. It returns the file digest as sha256:<hex>.
+func fetchMarkdown(ctx context.Context, source, checksum, dest string) (string, error) {
+	data, err := download(ctx, source, "markdown file", MaxMarkdownSize)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+	digest := "sha256:" + hex.EncodeToString(sum[:])
+	if err := verifyArchiveChecksum(digest, checksum); err != nil {
+		return "", err
+	}
+
+	indexPath := filepath.Join(dest, defaultEntry)
+	if err := os.WriteFile(indexPath, data, 0644); err != nil {
+		return "", errors.FileError("write command file", indexPath, err)
+	}
+
+	metadata, err := markdownMetadata(source, data)
+	if err != nil {
+		return "", err
+	}
+	if len(metadata) == 0 {
+		return digest, nil
+	}
+
+	config, err := yaml.Marshal(metadata)
+	if err != nil {
+		return "", errors.FileError("marshal metadata", ConfigFileName, err)
+	}
+	configPath := filepath.Join(dest, ConfigFileName)
+	if err := os.WriteFile(configPath, config, 0644); err != nil {
+		return "", errors.FileError("write metadata", configPath, err)
+	}
+	return digest, nil
+}
+
+// markdownMetadata synthesizes the metadata fields a downloaded markdown file leaves
+// out: the name comes from the file name, the description from its first heading and
+// the author from the gist owner or the host
+func markdownMetadata(source string, data []byte) (map[string]string, error) {
+	front := map[string]interface{}{}
+	rawFront, body, ok := splitFrontMatter(data)
+	if ok {
+		if err := yaml.Unmarshal(rawFront, &front); err != nil {
+			return nil, errors.InvalidInput(fmt.Sprintf("invalid front matter in %s: %v", source, err))
+		}
+	}
+
+	identity := markdownRepoPath(source)
+	owner, _, _ := strings.Cut(identity, "/")
+
+	description := firstHeading(body)
+	if description == "" {
+		description = "Installed from " + source
+	}
+
+	defaults := map[string]string{
+		"name":        fs.SanitizeName(path.Base(identity)),
+		"version":     markdownVersion,
+		"description": description,
+		"author":      owner,
+		"repository":  source,
+		"entry":       defaultEntry,
+	}
+	for key := range defaults {
+		if value, ok := front[key]; ok && value != nil && value != "" {
+			delete(defaults, key)
+		}
+	}
+	return defaults, nil
+}
+
+// firstHeading returns the text of the first markdown heading in body
+func firstHeading(body []byte) string {
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "#") {
+			return strings.TrimSpace(strings.TrimLeft(line, "#"))
+		}
+	}
+	return ""
+}
@@ -0,0 +1,96 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestIsMarkdownSource(t *testing.T) {
+	assert.True(t, IsMarkdownSource("https://gist.githubusercontent.com/u/abc/raw/prompt.md"))
+	assert.True(t, IsMarkdownSource("http://example.com/fix.markdown?token=1"))
+	assert.False(t, IsMarkdownSource("prompt.md"), "local files are not downloaded")
+	assert.False(t, IsMarkdownSource("https://github.com/user/repo"))
+}
+
+func TestMarkdownRepoPath(t *testing.T) {
+	assert.Equal(t, "u/abc/prompt", markdownRepoPath("https://gist.githubusercontent.com/u/abc/raw/0123abcd/prompt.md"))
+	assert.Equal(t, "u/abc/prompt", markdownRepoPath("https://gist.githubusercontent.com/u/abc/raw/prompt.md"))
+	assert.Equal(t, "example.com/prompts/fix", markdownRepoPath("https://example.com/prompts/fix.md"))
+}
+
+func TestMarkdownMetadata(t *testing.T) {
+	source := "https://example.com/prompts/fix.md"
+
+	metadata, err := markdownMetadata(source, []byte("# Quick fix\n\nFix it\n"))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"name":        "fix",
+		"version":     "0.0.0",
+		"description": "Quick fix",
+		"author":      "example.com",
+		"repository":  source,
+		"entry":       "index.md",
+	}, metadata)
+
+	// Front matter values are kept
+	metadata, err = markdownMetadata(source, []byte("---\ndescription: Fixes things\nallowed-tools: Bash\n---\nFix it\n"))
+	require.NoError(t, err)
+	assert.NotContains(t, metadata, "description")
+	assert.Equal(t, "fix", metadata["name"])
+}
+
+func TestInstallFromMarkdownURL(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	writeConfig(t, []string{})
+
+	content := "# Quick fix\n\nFix the failing test\n"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(content))
+	}))
+	defer server.Close()
+	url := server.URL + "/u/abc/raw/prompt.md"
+
+	name, _, err := Install(context.Background(), InstallOptions{Repository: url, Name: "quick-fix"})
+	require.NoError(t, err)
+	assert.Equal(t, "quick-fix", name)
+
+	index, err := os.ReadFile(filepath.Join(".claude", "commands", "quick-fix", "index.md"))
+	require.NoError(t, err)
+	assert.Equal(t, content, string(index))
+	assert.FileExists(t, filepath.Join(".claude", "commands", "quick-fix.md"))
+
+	lock := readLockFile(t).Commands["quick-fix"]
+	assert.Equal(t, url, lock.Source)
+	assert.Regexp(t, `^sha256:[0-9a-f]{64}$`, lock.Commit)
+	assert.Equal(t, url+"@"+lock.Commit, lock.Resolved)
+
+	data, err := os.ReadFile("ccmd.yaml")
+	require.NoError(t, err)
+	var config ProjectConfig
+	require.NoError(t, yaml.Unmarshal(data, &config))
+	assert.Equal(t, []string{url}, config.Commands)
+
+	// A changed file does not match the recorded checksum
+	content = "# Quick fix\n\nSomething else\n"
+	_, _, err = Install(context.Background(), InstallOptions{Repository: url, Name: "quick-fix", Force: true, Commit: lock.Commit})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "checksum mismatch")
+}
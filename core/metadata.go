@@ -10,6 +10,8 @@
 package core
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"os"
 	"path/filepath"
@@ -26,70 +28,92 @@ const (
 	LockFileName = "ccmd-lock.yaml"
 )
 
-// LoadProjectConfig loads the project configuration from ccmd.yaml
+// LoadProjectConfig loads the project configuration from ccmd.yaml, with the base it
+// extends merged in. The file is parsed once per process while it does not change;
+// every call returns a copy the caller may modify.
 func LoadProjectConfig(projectPath string) (*ProjectConfig, error) {
-	configPath := filepath.Join(projectPath, ConfigFileName)
-
-	data, err := os.ReadFile(configPath)
+	configPath := configFilePath(projectPath)
+
+	config, err := projectFiles.load(configPath, "read config", func(data []byte) (interface{}, error) {
+		var config ProjectConfig
+		if err := decodeProjectConfig(data, &config); err != nil {
+			// Report every problem with its position rather than the first one
+			if problems := checkConfigData(ConfigFileName, data); len(problems) > 0 {
+				return nil, problems
+			}
+			return nil, errors.FileError("parse config", configPath, err)
+		}
+		if config.Extends != "" {
+			base, err := loadBaseConfig(context.Background(), projectPath, filepath.Dir(configPath), &config)
+			if err != nil {
+				return nil, err
+			}
+			config.mergeBase(base)
+		}
+		return &config, nil
+	})
 	if err != nil {
-		return nil, errors.FileError("read config", configPath, err)
+		return nil, err
 	}
 
-	var config ProjectConfig
-	if err := yaml.Unmarshal(data, &config); err != nil {
-		return nil, errors.FileError("parse config", configPath, err)
-	}
-
-	return &config, nil
+	return config.(*ProjectConfig), nil
 }
 
 // SaveProjectConfig saves the project configuration to ccmd.yaml
 func SaveProjectConfig(projectPath string, config *ProjectConfig) error {
-	configPath := filepath.Join(projectPath, ConfigFileName)
+	configPath := configFilePath(projectPath)
 
-	data, err := yaml.Marshal(config)
+	data, err := encodeProjectConfig(config.withoutBase())
 	if err != nil {
 		return errors.FileError("marshal config", configPath, err)
 	}
 
-	return os.WriteFile(configPath, data, 0644)
+	if err := writeFileAtomic(configPath, data, 0644); err != nil {
+		return err
+	}
+	projectFiles.invalidate(configPath)
+	return nil
 }
 
 // ProjectConfigExists checks if ccmd.yaml exists in the project
 func ProjectConfigExists(projectPath string) bool {
-	configPath := filepath.Join(projectPath, ConfigFileName)
+	configPath := configFilePath(projectPath)
 	_, err := os.Stat(configPath)
 	return err == nil
 }
 
 // LockFileExists checks if ccmd-lock.yaml exists in the project
 func LockFileExists(projectPath string) bool {
-	lockPath := filepath.Join(projectPath, LockFileName)
+	lockPath := lockFilePath(projectPath)
 	_, err := os.Stat(lockPath)
 	return err == nil
 }
 
-// ReadLockFile reads and parses the ccmd-lock.yaml file
+// ReadLockFile reads and parses the ccmd-lock.yaml file. Like LoadProjectConfig, it
+// parses the file once per process while it does not change and returns a copy.
 func ReadLockFile(path string) (*LockFile, error) {
-	data, err := os.ReadFile(path)
+	lock, err := projectFiles.load(path, "read lock file", func(data []byte) (interface{}, error) {
+		var lock LockFile
+		if err := yaml.Unmarshal(data, &lock); err != nil {
+			return nil, errors.FileError("parse lock file", path, err)
+		}
+		return &lock, nil
+	})
 	if err != nil {
-		return nil, errors.FileError("read lock file", path, err)
-	}
-
-	var lock LockFile
-	if err := yaml.Unmarshal(data, &lock); err != nil {
-		return nil, errors.FileError("parse lock file", path, err)
+		return nil, err
 	}
 
-	if lock.Commands == nil {
-		lock.Commands = make(map[string]*LockCommand)
+	lockFile := lock.(*LockFile)
+	if lockFile.Commands == nil {
+		lockFile.Commands = make(map[string]*LockCommand)
 	}
 
-	if lock.Plugins == nil {
-		lock.Plugins = make(map[string]*LockPlugin)
+	if lockFile.Plugins == nil {
+		lockFile.Plugins = make(map[string]*LockPlugin)
 	}
 
-	return &lock, nil
+	lockFile.snapshot()
+	return lockFile, nil
 }
 
 // ReadClaudeSettings reads the .claude/settings.json file.
@@ -132,15 +156,69 @@ func WriteClaudeSettings(claudeDir string, s *ClaudeSettings) error {
 	return nil
 }
 
-// WriteLockFile writes the lock file to disk
+// WriteLockFile writes the lock file to disk. Changes written to the file since
+// lockFile was read are merged in first, see LockFile.Reload, and writes of the same
+// file are serialized across goroutines and processes.
 func WriteLockFile(path string, lockFile *LockFile) error {
+	if err := checkNoWrite(path); err != nil {
+		return err
+	}
+	release, err := acquireLockWriter(path)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	if err := lockFile.reload(path); err != nil {
+		return err
+	}
 	data, err := yaml.Marshal(lockFile)
 	if err != nil {
 		return errors.FileError("marshal lock file", path, err)
 	}
 
-	if err := os.WriteFile(path, data, 0644); err != nil {
-		return errors.FileError("write lock file", path, err)
+	// An unchanged lock file is left alone, so its modification time only moves with its content
+	if current, err := os.ReadFile(path); err != nil || !bytes.Equal(current, data) {
+		if err := writeFileAtomic(path, data, 0644); err != nil {
+			return err
+		}
+		projectFiles.invalidate(path)
+	}
+	lockFile.snapshot()
+	return nil
+}
+
+// writeFileAtomic writes data to a temporary file next to path and renames it into
+// place, so readers never observe a partially written file
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	if err := checkNoWrite(path); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return errors.FileError("create directory", filepath.Dir(path), err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return errors.FileError("create temp file", path, err)
+	}
+	tmpPath := tmp.Name()
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return errors.FileError("write file", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return errors.FileError("write file", path, err)
+	}
+	if err := os.Chmod(tmpPath, perm); err != nil {
+		os.Remove(tmpPath)
+		return errors.FileError("set file mode", path, err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return errors.FileError("replace file", path, err)
 	}
 
 	return nil
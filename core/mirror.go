@@ -0,0 +1,151 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/gifflet/ccmd/pkg/config"
+)
+
+// mirror redirects the sources under an upstream prefix, such as github.com/acme-org, to
+// an internal mirror URL
+type mirror struct {
+	prefix string // host and path, without scheme, user or .git suffix
+	target string // URL or local path the prefix is replaced with
+}
+
+// configuredMirrors loads the mirrors setting, longest prefix first. It is a user-only
+// key, so the project layer is not read.
+func configuredMirrors() []mirror {
+	settings, err := config.Load("")
+	if err != nil {
+		return nil
+	}
+	return parseMirrors(settings.Mirrors)
+}
+
+// parseMirrors normalizes the mirrors setting. Targets without a scheme that are not
+// local paths are reached over HTTPS.
+func parseMirrors(settings map[string]string) []mirror {
+	mirrors := make([]mirror, 0, len(settings))
+	for prefix, target := range settings {
+		prefix = mirrorPrefix(prefix)
+		target = strings.TrimSuffix(strings.TrimSuffix(strings.TrimSpace(target), "/"), ".git")
+		if prefix == "" || target == "" {
+			continue
+		}
+		if !strings.Contains(target, "://") && !strings.HasPrefix(target, "/") && !strings.HasPrefix(target, ".") {
+			target = "https://" + target
+		}
+		mirrors = append(mirrors, mirror{prefix: prefix, target: target})
+	}
+	sort.Slice(mirrors, func(i, j int) bool {
+		if len(mirrors[i].prefix) != len(mirrors[j].prefix) {
+			return len(mirrors[i].prefix) > len(mirrors[j].prefix)
+		}
+		return mirrors[i].prefix < mirrors[j].prefix
+	})
+	return mirrors
+}
+
+// mirrorPrefix reduces a source or prefix to host and path: scheme, user, scp-style
+// colon, trailing slash and .git suffix are dropped
+func mirrorPrefix(source string) string {
+	source = strings.TrimSpace(source)
+	if idx := strings.Index(source, "://"); idx != -1 {
+		source = source[idx+3:]
+	} else if at := strings.Index(source, "@"); at != -1 && strings.Contains(source[at:], ":") {
+		// git@host:owner/repo
+		source = strings.Replace(source, ":", "/", 1)
+	}
+	if at := strings.Index(source, "@"); at != -1 && at < strings.IndexAny(source+"/", "/") {
+		source = source[at+1:]
+	}
+	source = strings.TrimSuffix(strings.TrimSuffix(source, "/"), ".git")
+	return strings.ToLower(source)
+}
+
+// match returns what follows the prefix in source, when source lies under it
+func (m mirror) match(source string) (string, bool) {
+	key := mirrorPrefix(source)
+	if !strings.HasPrefix(key, m.prefix) {
+		return "", false
+	}
+	rest := key[len(m.prefix):]
+	if rest != "" && !strings.HasPrefix(rest, "/") {
+		return "", false
+	}
+	// Keep the case of the original path
+	stripped := strings.TrimSuffix(strings.TrimSuffix(source, "/"), ".git")
+	rest = stripped[len(stripped)-len(rest):]
+	if strings.HasPrefix(rest, ":") {
+		rest = "/" + rest[1:]
+	}
+	return rest, true
+}
+
+// mirrorFor returns the mirror URL of a repository or download URL, or "" when no
+// mirror is configured for it
+func mirrorFor(mirrors []mirror, source string) string {
+	for _, m := range mirrors {
+		if rest, ok := m.match(source); ok {
+			return m.target + rest
+		}
+	}
+	return ""
+}
+
+// mirrorURL rewrites an http(s) download URL to its mirror, keeping the query. URLs
+// without an http(s) mirror are returned unchanged.
+func mirrorURL(source string) string {
+	u, err := url.Parse(source)
+	if err != nil || u.Host == "" {
+		return source
+	}
+	query := u.RawQuery
+	u.RawQuery, u.Fragment = "", ""
+
+	target := mirrorFor(configuredMirrors(), u.String())
+	if !strings.HasPrefix(target, "http://") && !strings.HasPrefix(target, "https://") {
+		return source
+	}
+	// Downloads keep their file name: mirrorFor drops a .git suffix meant for repositories
+	if strings.HasSuffix(u.Path, ".git") {
+		target += ".git"
+	}
+	if query != "" {
+		target += "?" + query
+	}
+	return target
+}
+
+// gitMirrorEnv adds url.<mirror>.insteadOf rules to a git environment, so clones,
+// fetches and ls-remote calls of upstream repositories reach their mirrors while the
+// upstream URL is what ccmd records
+func gitMirrorEnv(env []string) []string {
+	var entries [][2]string
+	for _, m := range configuredMirrors() {
+		host, path, _ := strings.Cut(m.prefix, "/")
+		upstreams := []string{"https://" + m.prefix, "http://" + m.prefix, "ssh://git@" + m.prefix}
+		if path != "" {
+			upstreams = append(upstreams, "git@"+host+":"+path)
+		}
+		for _, upstream := range upstreams {
+			// A trailing slash keeps github.com/acme from matching github.com/acme-other
+			entries = append(entries,
+				[2]string{"url." + m.target + "/.insteadOf", upstream + "/"},
+				[2]string{"url." + m.target + ".git.insteadOf", upstream + ".git"})
+		}
+	}
+	return appendGitConfig(env, entries)
+}
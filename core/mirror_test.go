@@ -0,0 +1,89 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gifflet/ccmd/pkg/config"
+)
+
+func TestMirrorFor(t *testing.T) {
+	mirrors := parseMirrors(map[string]string{
+		"github.com/acme-org":         "git.internal/acme-mirror/",
+		"https://github.com/acme/one": "https://git.internal/one.git",
+		"gitlab.com":                  "/srv/mirrors/gitlab",
+	})
+
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{"https://github.com/acme-org/hello.git", "https://git.internal/acme-mirror/hello"},
+		{"git@github.com:acme-org/Hello.git", "https://git.internal/acme-mirror/Hello"},
+		{"https://github.com/acme-org/hello/archive/v1.tar.gz", "https://git.internal/acme-mirror/hello/archive/v1.tar.gz"},
+		{"https://github.com/acme/one.git", "https://git.internal/one"},
+		{"https://gitlab.com/group/tool.git", "/srv/mirrors/gitlab/group/tool"},
+		{"https://github.com/acme-org-other/hello.git", ""},
+		{"https://github.com/acme/one-more.git", ""},
+		{"https://example.com/acme-org/hello.git", ""},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, mirrorFor(mirrors, tt.source), tt.source)
+	}
+}
+
+func TestMirrorURL(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	t.Setenv(config.ConfigEnv, filepath.Join(t.TempDir(), "config.yaml"))
+	t.Setenv(config.EnvName("mirrors"), "github.com/acme-org=mirror.internal/acme,gitlab.com=/srv/gitlab")
+
+	assert.Equal(t, "https://mirror.internal/acme/tool/releases/v1.zip?raw=1",
+		mirrorURL("https://github.com/acme-org/tool/releases/v1.zip?raw=1"))
+	assert.Equal(t, "https://gitlab.com/group/tool/-/raw/main/a.md",
+		mirrorURL("https://gitlab.com/group/tool/-/raw/main/a.md"), "local mirrors only serve git")
+	assert.Equal(t, "https://github.com/other/tool/a.md", mirrorURL("https://github.com/other/tool/a.md"))
+}
+
+func TestGitMirrorEnv(t *testing.T) {
+	repo, _ := writeGitRepo(t)
+	cleanup := setupTestDir(t)
+	defer cleanup()
+
+	mirrorDir := t.TempDir()
+	out, err := exec.Command("git", "clone", "--quiet", "--bare", repo, filepath.Join(mirrorDir, "hello.git")).CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	t.Setenv(config.ConfigEnv, filepath.Join(t.TempDir(), "config.yaml"))
+	t.Setenv(config.EnvName("retry_attempts"), "1")
+	t.Setenv(config.EnvName("mirrors"), "github.com/acme-org="+mirrorDir)
+	t.Setenv("GIT_CONFIG_COUNT", "1")
+	t.Setenv("GIT_CONFIG_KEY_0", "core.askPass")
+	t.Setenv("GIT_CONFIG_VALUE_0", "")
+
+	env := gitMirrorEnv([]string{"GIT_CONFIG_COUNT=1"})
+	assert.Contains(t, env, "GIT_CONFIG_KEY_1=url."+mirrorDir+"/.insteadOf")
+	assert.Contains(t, env, "GIT_CONFIG_VALUE_1=https://github.com/acme-org/")
+	assert.Contains(t, env, "GIT_CONFIG_VALUE_7=git@github.com:acme-org/")
+	assert.Equal(t, "GIT_CONFIG_COUNT=9", env[len(env)-1])
+
+	// The upstream URL reaches the mirror
+	refs, err := runGitRemote(context.Background(), "ls-remote", "ls-remote", "--tags", "https://github.com/acme-org/hello.git")
+	require.NoError(t, err)
+	assert.True(t, strings.Contains(string(refs), "refs/tags/v1.0.0"))
+}
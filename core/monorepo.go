@@ -0,0 +1,204 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/gifflet/ccmd/pkg/errors"
+	"github.com/gifflet/ccmd/pkg/output"
+	"github.com/gifflet/ccmd/pkg/repospec"
+)
+
+// RepositoryCommandsDir is the directory of a multi-command repository holding one
+// directory with a ccmd.yaml per command
+const RepositoryCommandsDir = "commands"
+
+// repositoryCommandSeparator joins a repository and one of its commands, as in
+// owner/repo//review
+const repositoryCommandSeparator = repospec.CommandSeparator
+
+// MultiCommandError is returned by Install for a repository that publishes several
+// commands when no command was selected. It matches errors.ErrInvalidInput.
+type MultiCommandError struct {
+	Repository string   // Repository as requested, without version
+	Commands   []string // Names of the commands under commands/, sorted
+
+	// The version and commit that were checked out, so every command selected
+	// afterwards is installed from the same commit
+	version     string
+	saveVersion string
+	commit      string
+}
+
+// Error lists the commands and how to select them
+func (e *MultiCommandError) Error() string {
+	return fmt.Sprintf("%s publishes %d commands (%s); install one with %s%s<command> or all of them with --all",
+		e.Repository, len(e.Commands), strings.Join(e.Commands, ", "), e.Repository, repositoryCommandSeparator)
+}
+
+// Is makes MultiCommandError match errors.ErrInvalidInput
+func (e *MultiCommandError) Is(target error) bool {
+	return target == errors.ErrInvalidInput
+}
+
+// RepositoryCommandSpec returns the spec of one command of a multi-command repository
+func RepositoryCommandSpec(repository, command string) string {
+	return repository + repositoryCommandSeparator + command
+}
+
+// splitRepositoryCommand splits owner/repo//command, or a URL ending in //command, into
+// the repository and the command, which keeps a version given after it. Sources without
+// a command are returned unchanged.
+func splitRepositoryCommand(source string) (repo, command string) {
+	if isDownloadSource(source) {
+		return source, ""
+	}
+	spec, err := repospec.Parse(source)
+	if err != nil || spec.Command == "" {
+		return source, ""
+	}
+	command = spec.Command
+	if spec.Version != "" {
+		command += repospec.VersionSeparator + spec.Version
+	}
+	return spec.Source, command
+}
+
+// repositoryCommands returns the commands of a multi-command repository checked out in
+// dir, sorted. A repository whose root is a command or a plugin has none.
+func repositoryCommands(dir string) []string {
+	if fileExists(filepath.Join(dir, "index.md")) {
+		return nil
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, ConfigFileName)); err == nil {
+		var root struct {
+			Entry string `yaml:"entry"`
+			Type  string `yaml:"type"`
+		}
+		if yaml.Unmarshal(data, &root) == nil && (root.Entry != "" || root.Type == "plugin") {
+			return nil
+		}
+	}
+
+	entries, err := os.ReadDir(filepath.Join(dir, RepositoryCommandsDir))
+	if err != nil {
+		return nil
+	}
+	var commands []string
+	for _, entry := range entries {
+		if entry.IsDir() && fileExists(filepath.Join(dir, RepositoryCommandsDir, entry.Name(), ConfigFileName)) {
+			commands = append(commands, entry.Name())
+		}
+	}
+	sort.Strings(commands)
+	return commands
+}
+
+// repositoryCommandDir returns the directory of one command of a multi-command repository
+func repositoryCommandDir(dir, repository, command string) (string, error) {
+	commands := repositoryCommands(dir)
+	for _, name := range commands {
+		if name == command {
+			return filepath.Join(dir, RepositoryCommandsDir, command), nil
+		}
+	}
+	available := "it publishes a single command"
+	if len(commands) == 0 {
+		// A sparse checkout holds the directory of the requested command only
+		commands = committedCommands(dir)
+	}
+	if len(commands) > 0 {
+		available = "available: " + strings.Join(commands, ", ")
+	}
+	return "", errors.NotFound(fmt.Sprintf("command %q in %s (%s)", command, repository, available))
+}
+
+// committedCommands lists the commands of a multi-command repository from the tree of
+// its checked out commit, including those outside a sparse checkout
+func committedCommands(dir string) []string {
+	git, err := getGitPath()
+	if err != nil {
+		return nil
+	}
+	out, err := exec.Command(git, "-C", dir, "ls-tree", "-r", "--name-only", "HEAD", RepositoryCommandsDir).Output()
+	if err != nil {
+		return nil
+	}
+	var commands []string
+	for _, file := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		parts := strings.Split(file, "/")
+		if len(parts) == 3 && parts[2] == ConfigFileName {
+			commands = append(commands, parts[1])
+		}
+	}
+	sort.Strings(commands)
+	return commands
+}
+
+// InstallRepositoryCommands installs commands of the multi-command repository a
+// MultiCommandError describes, all from the commit that was checked out. Each command is
+// locked and recorded in ccmd.yaml on its own. It returns the names of the installed
+// commands and keeps going when one fails.
+func InstallRepositoryCommands(ctx context.Context, opts InstallOptions, multi *MultiCommandError, commands []string) ([]string, error) {
+	if len(commands) == 0 {
+		return nil, errors.InvalidInput("no commands selected")
+	}
+	if len(commands) > 1 && (opts.Name != "" || opts.As != "") {
+		return nil, errors.InvalidInput("--name and --as select a single command; install commands one at a time")
+	}
+	for _, command := range commands {
+		if !containsFold(multi.Commands, command) {
+			return nil, errors.NotFound(fmt.Sprintf("command %q in %s (available: %s)",
+				command, multi.Repository, strings.Join(multi.Commands, ", ")))
+		}
+	}
+
+	var installed []string
+	var failed []error
+	for _, command := range commands {
+		if err := ctx.Err(); err != nil {
+			return installed, err
+		}
+
+		commandOpts := opts
+		commandOpts.Repository = RepositoryCommandSpec(multi.Repository, command)
+		commandOpts.Version = multi.version
+		commandOpts.Commit = multi.commit
+		commandOpts.saveVersion = multi.saveVersion
+
+		name, _, err := Install(ctx, commandOpts)
+		switch {
+		case ctx.Err() != nil:
+			return installed, ctx.Err()
+		case stderrors.Is(err, errors.ErrAlreadyExists):
+			output.PrintWarningf("%s already installed, use --force to reinstall", command)
+		case err != nil:
+			failed = append(failed, err)
+			output.PrintErrorf("Failed to install %s: %v", command, err)
+		default:
+			installed = append(installed, name)
+		}
+	}
+
+	if len(failed) > 0 {
+		return installed, fmt.Errorf("failed to install %d of %d command(s)", len(failed), len(commands))
+	}
+	return installed, nil
+}
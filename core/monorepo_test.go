@@ -0,0 +1,109 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"context"
+	stderrors "errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+// writeMultiCommandRepo creates a git repository tagged v1.0.0 that publishes the
+// commands explain and review under commands/
+func writeMultiCommandRepo(t *testing.T) string {
+	repo, _ := writeGitRepo(t)
+	for _, name := range []string{"explain", "review"} {
+		dir := filepath.Join(repo, RepositoryCommandsDir, name)
+		require.NoError(t, os.MkdirAll(dir, 0755))
+		metadata := "name: " + name + "\nversion: 1.0.0\ndescription: The " + name + " prompt\n" +
+			"author: acme\nrepository: https://github.com/acme/prompts\nentry: index.md\n"
+		require.NoError(t, os.WriteFile(filepath.Join(dir, ConfigFileName), []byte(metadata), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "index.md"), []byte("# "+name+"\n"), 0644))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "README.md"), []byte("# Prompts\n"), 0644))
+	for _, args := range [][]string{{"add", "."}, {"commit", "--quiet", "-m", "commands"}, {"tag", "--force", "v1.0.0"}} {
+		out, err := exec.Command("git", append([]string{"-C", repo}, args...)...).CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	return "file://" + repo
+}
+
+func TestSplitRepositoryCommand(t *testing.T) {
+	tests := []struct {
+		source, repo, command string
+	}{
+		{"owner/repo//review", "owner/repo", "review"},
+		{"https://github.com/owner/repo.git//review", "https://github.com/owner/repo.git", "review"},
+		{"https://github.com/owner/repo.git", "https://github.com/owner/repo.git", ""},
+		{"git@github.com:owner/repo.git//review", "git@github.com:owner/repo.git", "review"},
+		{"https://example.com/releases/tool.tar.gz", "https://example.com/releases/tool.tar.gz", ""},
+	}
+	for _, tt := range tests {
+		repo, command := splitRepositoryCommand(tt.source)
+		assert.Equal(t, tt.repo, repo, tt.source)
+		assert.Equal(t, tt.command, command, tt.source)
+	}
+
+	assert.Equal(t, "owner/repo//review", ExtractRepoPath("https://github.com/owner/repo.git//review"))
+	assert.Equal(t, "https://github.com/owner/repo.git//review", NormalizeRepositoryURL("github.com/owner/repo//review"))
+	assert.Equal(t, "review", extractCommandName("https://github.com/owner/repo.git//review"))
+}
+
+func TestInstallMultiCommandRepository(t *testing.T) {
+	repo := writeMultiCommandRepo(t)
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	_, _, err := Install(ctx, InstallOptions{Repository: repo})
+	var multi *MultiCommandError
+	require.True(t, stderrors.As(err, &multi), "got %v", err)
+	assert.ErrorIs(t, err, errors.ErrInvalidInput)
+	assert.Equal(t, []string{"explain", "review"}, multi.Commands)
+	assert.NoDirExists(t, filepath.Join(".claude", "commands", "explain"))
+
+	installed, err := InstallRepositoryCommands(ctx, InstallOptions{Repository: repo}, multi, multi.Commands)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"explain", "review"}, installed)
+	assert.FileExists(t, filepath.Join(".claude", "commands", "review", "index.md"))
+	assert.NoFileExists(t, filepath.Join(".claude", "commands", "review", "README.md"))
+
+	// Each command is locked on its own, at the commit of the repository
+	lockFile := readLockFile(t)
+	require.Len(t, lockFile.Commands, 2)
+	explain, review := lockFile.Commands["explain"], lockFile.Commands["review"]
+	require.NotNil(t, explain)
+	require.NotNil(t, review)
+	assert.Equal(t, RepositoryCommandSpec(repo, "explain"), explain.Source)
+	assert.Equal(t, "1.0.0", review.Version)
+	assert.Equal(t, explain.Commit, review.Commit)
+	assert.Len(t, review.Commit, 40)
+
+	config, err := LoadProjectConfig(".")
+	require.NoError(t, err)
+	// Local repositories have no shorthand, so ccmd.yaml keeps their URL
+	assert.Equal(t, []string{repo + "//explain@^1.0.0", repo + "//review@^1.0.0"}, config.Commands)
+
+	analysis, err := AnalyzeSync(".", "")
+	require.NoError(t, err)
+	assert.True(t, analysis.InSync)
+
+	_, _, err = Install(ctx, InstallOptions{Repository: repo + "//lint"})
+	assert.ErrorIs(t, err, errors.ErrNotFound)
+	assert.ErrorContains(t, err, "available: explain, review")
+}
@@ -0,0 +1,195 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gifflet/ccmd/pkg/config"
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+// insecureSkipVerify disables TLS certificate verification for git, the forge API and downloads
+var insecureSkipVerify atomic.Bool
+
+// SetInsecureSkipVerify turns TLS certificate verification off or back on for every
+// network operation. Only use it to diagnose proxies that intercept TLS.
+func SetInsecureSkipVerify(skip bool) {
+	insecureSkipVerify.Store(skip)
+}
+
+// InsecureSkipVerify reports whether TLS certificate verification is disabled
+func InsecureSkipVerify() bool {
+	return insecureSkipVerify.Load()
+}
+
+// networkSettings loads the proxy and TLS configuration from the user config and the
+// environment. The project layer is skipped: a cloned repository must not route the
+// stored tokens through a proxy of its choice, nor make ccmd trust a CA it ships.
+func networkSettings() (config.ProxySettings, config.TLSSettings) {
+	settings, err := config.Load("")
+	if err != nil {
+		return config.ProxySettings{}, config.TLSSettings{}
+	}
+	return settings.Proxy, settings.TLS
+}
+
+// newHTTPClient returns a client honoring the configured proxy, CA bundle and
+// --insecure-skip-verify. Proxy settings fall back to HTTP_PROXY, HTTPS_PROXY and NO_PROXY.
+func newHTTPClient(timeout time.Duration) (*http.Client, error) {
+	proxy, tlsSettings := networkSettings()
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if tlsSettings.CAFile != "" {
+		pool, err := loadCABundle(tlsSettings.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if InsecureSkipVerify() {
+		tlsConfig.InsecureSkipVerify = true //nolint:gosec // explicitly requested with --insecure-skip-verify
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	transport.Proxy = func(req *http.Request) (*url.URL, error) {
+		return proxyFor(req, proxy)
+	}
+
+	return &http.Client{Timeout: timeout, Transport: transport}, nil
+}
+
+// loadCABundle returns a pool of the certificates in a PEM file. They replace the system
+// certificates, as GIT_SSL_CAINFO does for git, so HTTP requests and git trust the same CAs.
+func loadCABundle(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.FileError("read CA bundle", path, err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, errors.InvalidInput(fmt.Sprintf("CA bundle %s contains no PEM certificates", path))
+	}
+
+	return pool, nil
+}
+
+// proxyFor picks the configured proxy for a request, falling back to the environment
+func proxyFor(req *http.Request, proxy config.ProxySettings) (*url.URL, error) {
+	if proxy.HTTP == "" && proxy.HTTPS == "" && proxy.NoProxy == "" {
+		return http.ProxyFromEnvironment(req)
+	}
+
+	if bypassProxy(req.URL.Hostname(), proxy.NoProxy) {
+		return nil, nil
+	}
+
+	raw := proxy.HTTP
+	if req.URL.Scheme == "https" && proxy.HTTPS != "" {
+		raw = proxy.HTTPS
+	}
+	if raw == "" {
+		return http.ProxyFromEnvironment(req)
+	}
+
+	proxyURL, err := url.Parse(raw)
+	if err != nil || proxyURL.Host == "" {
+		return nil, errors.InvalidInput(fmt.Sprintf("invalid proxy URL %q", raw))
+	}
+	return proxyURL, nil
+}
+
+// bypassProxy matches a host against a NO_PROXY style list: "*", exact hosts, domain
+// suffixes (with or without a leading dot) and IP addresses
+func bypassProxy(host, noProxy string) bool {
+	host = strings.ToLower(host)
+	for _, entry := range strings.Split(noProxy, ",") {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+		if entry == "*" {
+			return true
+		}
+		if h, _, err := net.SplitHostPort(entry); err == nil {
+			entry = h
+		}
+		entry = strings.TrimPrefix(entry, ".")
+		if host == entry || strings.HasSuffix(host, "."+entry) {
+			return true
+		}
+	}
+	return false
+}
+
+// gitNetworkEnv returns the environment for git commands that reach the network, with
+// the configured proxy, CA bundle, --insecure-skip-verify, mirrors and stored tokens applied
+func gitNetworkEnv() []string {
+	proxy, tlsSettings := networkSettings()
+
+	env := os.Environ()
+	if proxy.HTTP != "" {
+		env = append(env, "HTTP_PROXY="+proxy.HTTP, "http_proxy="+proxy.HTTP)
+	}
+	if proxy.HTTPS != "" {
+		env = append(env, "HTTPS_PROXY="+proxy.HTTPS, "https_proxy="+proxy.HTTPS)
+	}
+	if proxy.NoProxy != "" {
+		env = append(env, "NO_PROXY="+proxy.NoProxy, "no_proxy="+proxy.NoProxy)
+	}
+	if tlsSettings.CAFile != "" {
+		env = append(env, "GIT_SSL_CAINFO="+tlsSettings.CAFile)
+	}
+	if InsecureSkipVerify() {
+		env = append(env, "GIT_SSL_NO_VERIFY=true")
+	}
+
+	return gitCredentialEnv(gitMirrorEnv(gitPromptEnv(env)))
+}
+
+// appendGitConfig passes git config entries through GIT_CONFIG_KEY_n and
+// GIT_CONFIG_VALUE_n, after the entries GIT_CONFIG_COUNT already declares in env or
+// the process environment
+func appendGitConfig(env []string, entries [][2]string) []string {
+	if len(entries) == 0 {
+		return env
+	}
+
+	declared := os.Getenv("GIT_CONFIG_COUNT")
+	for _, kv := range env {
+		if value, ok := strings.CutPrefix(kv, "GIT_CONFIG_COUNT="); ok {
+			declared = value
+		}
+	}
+	count := 0
+	if n, err := strconv.Atoi(declared); err == nil && n > 0 {
+		count = n
+	}
+
+	for _, entry := range entries {
+		env = append(env,
+			fmt.Sprintf("GIT_CONFIG_KEY_%d=%s", count, entry[0]),
+			fmt.Sprintf("GIT_CONFIG_VALUE_%d=%s", count, entry[1]))
+		count++
+	}
+	return append(env, "GIT_CONFIG_COUNT="+strconv.Itoa(count))
+}
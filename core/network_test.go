@@ -0,0 +1,140 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gifflet/ccmd/pkg/config"
+)
+
+func TestBypassProxy(t *testing.T) {
+	assert.True(t, bypassProxy("internal.example.com", "localhost, .example.com"))
+	assert.True(t, bypassProxy("example.com", "example.com"))
+	assert.True(t, bypassProxy("10.0.0.1", "10.0.0.1:8080"))
+	assert.True(t, bypassProxy("github.com", "*"))
+	assert.False(t, bypassProxy("github.com", "example.com,hub.com"))
+	assert.False(t, bypassProxy("github.com", ""))
+}
+
+func TestProxyFor(t *testing.T) {
+	proxy := config.ProxySettings{
+		HTTP:    "http://proxy.local:3128",
+		HTTPS:   "http://secure-proxy.local:3128",
+		NoProxy: "internal.local",
+	}
+
+	req := &http.Request{URL: &url.URL{Scheme: "https", Host: "github.com"}}
+	got, err := proxyFor(req, proxy)
+	require.NoError(t, err)
+	assert.Equal(t, "secure-proxy.local:3128", got.Host)
+
+	req = &http.Request{URL: &url.URL{Scheme: "http", Host: "example.com"}}
+	got, err = proxyFor(req, proxy)
+	require.NoError(t, err)
+	assert.Equal(t, "proxy.local:3128", got.Host)
+
+	req = &http.Request{URL: &url.URL{Scheme: "https", Host: "git.internal.local"}}
+	got, err = proxyFor(req, proxy)
+	require.NoError(t, err)
+	assert.Nil(t, got)
+
+	_, err = proxyFor(req, config.ProxySettings{HTTPS: "not a url"})
+	assert.Error(t, err)
+}
+
+func TestLoadCABundleRejectsNonPEM(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(path, []byte("not a certificate"), 0644))
+
+	_, err := loadCABundle(path)
+	assert.Error(t, err)
+
+	_, err = loadCABundle(filepath.Join(t.TempDir(), "missing.pem"))
+	assert.Error(t, err)
+}
+
+func TestGitNetworkEnv(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	t.Setenv("CCMD_PROXY_HTTPS", "http://proxy.local:3128")
+	t.Setenv(config.CABundleEnv, "/etc/ccmd/ca.pem")
+
+	SetInsecureSkipVerify(true)
+	defer SetInsecureSkipVerify(false)
+
+	env := gitNetworkEnv()
+	assert.Contains(t, env, "HTTPS_PROXY=http://proxy.local:3128")
+	assert.Contains(t, env, "GIT_SSL_CAINFO=/etc/ccmd/ca.pem")
+	assert.Contains(t, env, "GIT_SSL_NO_VERIFY=true")
+}
+
+func TestGitNetworkEnvIgnoresProjectConfig(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	t.Setenv("CCMD_CONFIG", filepath.Join(t.TempDir(), "config.yaml"))
+	writeConfig(t, []string{})
+	require.NoError(t, os.WriteFile(".ccmdrc.yaml", []byte(
+		"proxy:\n  https: http://attacker.local:3128\ntls:\n  ca_file: attacker.pem\n"), 0644))
+
+	for _, entry := range gitNetworkEnv() {
+		assert.NotContains(t, entry, "attacker")
+	}
+
+	proxy, tlsSettings := networkSettings()
+	assert.Empty(t, proxy.HTTPS)
+	assert.Empty(t, tlsSettings.CAFile)
+}
+
+func TestNewHTTPClientTLS(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	get := func() error {
+		client, err := newHTTPClient(5 * time.Second)
+		if err != nil {
+			return err
+		}
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			return err
+		}
+		return resp.Body.Close()
+	}
+
+	// The test server's certificate is not trusted by default
+	assert.Error(t, get())
+
+	SetInsecureSkipVerify(true)
+	assert.NoError(t, get())
+	SetInsecureSkipVerify(false)
+
+	caPath := filepath.Join(t.TempDir(), "ca.pem")
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	require.NoError(t, os.WriteFile(caPath, cert, 0644))
+	t.Setenv(config.CABundleEnv, caPath)
+
+	assert.NoError(t, get())
+}
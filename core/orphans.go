@@ -0,0 +1,124 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+// Orphan is a command directory or generated standalone file under .claude/commands that
+// no lock entry tracks, typically left behind by a failed install, a rename or manual edits
+type Orphan struct {
+	Name  string   `json:"name"`
+	Paths []string `json:"paths"` // relative to the project root
+}
+
+// FindOrphans compares .claude/commands against the lock file. Directories without a
+// lock entry are orphans, as are standalone .md files generated by ccmd. Hand-written
+// .md commands are never reported.
+func FindOrphans(projectPath string) ([]Orphan, error) {
+	projectRoot, err := findProjectRootFrom(projectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	commandsDir := projectCommandsDir(projectRoot)
+	entries, err := os.ReadDir(commandsDir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, errors.FileError("read commands directory", commandsDir, err)
+	}
+
+	tracked, err := trackedCommandNames(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	found := make(map[string]*Orphan)
+	add := func(name, path string) {
+		if found[name] == nil {
+			found[name] = &Orphan{Name: name}
+		}
+		if rel, err := filepath.Rel(projectRoot, path); err == nil {
+			path = rel
+		}
+		found[name].Paths = append(found[name].Paths, path)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") && !tracked[entry.Name()] {
+			add(entry.Name(), filepath.Join(commandsDir, entry.Name()))
+		}
+	}
+
+	// Standalone files stay in .claude/commands when paths.commands moves the directories
+	standaloneDir := filepath.Join(projectRoot, targetAdapters[TargetClaude].defaultPath)
+	if standaloneDir != commandsDir {
+		if entries, err = os.ReadDir(standaloneDir); err != nil {
+			entries = nil
+		}
+	}
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), ".md")
+		if entry.IsDir() || name == entry.Name() || tracked[name] {
+			continue
+		}
+		path := filepath.Join(standaloneDir, entry.Name())
+		if found[name] != nil || isGeneratedStandalone(path, name) {
+			add(name, path)
+		}
+	}
+
+	orphans := make([]Orphan, 0, len(found))
+	for _, orphan := range found {
+		orphans = append(orphans, *orphan)
+	}
+	sort.Slice(orphans, func(i, j int) bool {
+		return orphans[i].Name < orphans[j].Name
+	})
+
+	return orphans, nil
+}
+
+// PruneOrphans moves orphaned files to the trash, where 'ccmd restore' can recover them
+func PruneOrphans(projectPath string, orphans []Orphan) ([]string, error) {
+	projectRoot, err := findProjectRootFrom(projectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	pruned := []string{}
+	for _, orphan := range orphans {
+		if _, err := trashCommand(projectRoot, orphan.Name, nil, ""); err != nil {
+			return pruned, err
+		}
+		pruned = append(pruned, orphan.Name)
+	}
+
+	return pruned, nil
+}
+
+// isGeneratedStandalone reports whether a .md file has the header written by
+// renderStandaloneDoc, behind front matter when doc_frontmatter is set
+func isGeneratedStandalone(path, name string) bool {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	_, data, _ = splitFrontMatter(data)
+
+	return bytes.HasPrefix(data, []byte("# "+name+"\n\n**Version:** ")) &&
+		bytes.Contains(data, []byte("\n**Repository:** "))
+}
@@ -0,0 +1,96 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupOrphans installs "tracked" and leaves behind a partial directory, a stale
+// generated .md file and a hand-written command
+func setupOrphans(t *testing.T) {
+	t.Helper()
+
+	writeConfig(t, []string{"test/tracked@1.0.0"})
+	lockFile := createBasicLockFile()
+	lockFile.Commands["tracked"] = createTestLockCommand("tracked", "1.0.0", "https://github.com/test/tracked.git")
+	writeLockFile(t, lockFile)
+	createCommandStructure(t, "tracked")
+
+	commandsDir := filepath.Join(".claude", "commands")
+	require.NoError(t, os.MkdirAll(filepath.Join(commandsDir, "partial"), 0755))
+
+	// A standalone file generated for a command that was since renamed
+	source := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(source, "index.md"), []byte("Body"), 0644))
+	metadata := &ProjectConfig{Name: "renamed", Version: "1.0.0", Author: "tester", Repository: "https://github.com/test/renamed.git"}
+	stale, err := renderStandaloneDoc(source, metadata)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(commandsDir, "renamed.md"), stale, 0644))
+
+	require.NoError(t, os.WriteFile(filepath.Join(commandsDir, "notes.md"), []byte("# My notes\n"), 0644))
+}
+
+func TestFindOrphans(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	setupOrphans(t)
+
+	orphans, err := FindOrphans("")
+	require.NoError(t, err)
+	require.Len(t, orphans, 2)
+
+	assert.Equal(t, "partial", orphans[0].Name)
+	assert.Equal(t, []string{filepath.Join(".claude", "commands", "partial")}, orphans[0].Paths)
+	assert.Equal(t, "renamed", orphans[1].Name)
+	assert.Equal(t, []string{filepath.Join(".claude", "commands", "renamed.md")}, orphans[1].Paths)
+}
+
+func TestSyncPrune(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	setupOrphans(t)
+
+	cwd, err := os.Getwd()
+	require.NoError(t, err)
+
+	result, err := Sync(context.Background(), SyncOptions{ProjectPath: cwd})
+	require.NoError(t, err)
+	assert.Len(t, result.Orphans, 2)
+	assert.Empty(t, result.Pruned)
+	assert.DirExists(t, filepath.Join(".claude", "commands", "partial"))
+
+	result, err = Sync(context.Background(), SyncOptions{ProjectPath: cwd, Prune: true})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"partial", "renamed"}, result.Pruned)
+
+	assert.NoDirExists(t, filepath.Join(".claude", "commands", "partial"))
+	assert.NoFileExists(t, filepath.Join(".claude", "commands", "renamed.md"))
+	assert.FileExists(t, filepath.Join(".claude", "commands", "notes.md"))
+	assert.DirExists(t, filepath.Join(".claude", "commands", "tracked"))
+
+	// Pruned files go to the trash and can be restored
+	require.NoError(t, Restore(RestoreOptions{Name: "partial"}))
+	assert.DirExists(t, filepath.Join(".claude", "commands", "partial"))
+}
+
+func TestIsGeneratedStandaloneCRLF(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hello.md")
+	content := "# hello\r\n\r\n**Version:** 1.0.0\r\n**Repository:** https://github.com/acme/hello\r\n"
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+	assert.True(t, isGeneratedStandalone(path, "hello"))
+}
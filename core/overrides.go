@@ -0,0 +1,68 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+// Files of a command's overrides directory, .claude/overrides/<name>/. They are kept
+// across updates and reinstalls, which never touch the overrides directory.
+const (
+	// OverrideIndexFile replaces the command's index.md
+	OverrideIndexFile = "index.md"
+	// OverrideAppendFile is added after the command's prompt
+	OverrideAppendFile = "append.md"
+)
+
+// overridesDir returns the overrides directory of the command installed in commandDir:
+// .claude/overrides/<name> next to .claude/commands/<name>, where name is owner/name in
+// the nested layout
+func overridesDir(commandDir string) string {
+	parent := filepath.Dir(commandDir)
+	if filepath.Base(parent) == "commands" {
+		return filepath.Join(filepath.Dir(parent), "overrides", filepath.Base(commandDir))
+	}
+	return filepath.Join(filepath.Dir(filepath.Dir(parent)), "overrides", filepath.Base(parent), filepath.Base(commandDir))
+}
+
+// readCommandPrompt returns the prompt of the command installed in commandDir, its
+// index.md, with the local overrides applied
+func readCommandPrompt(commandDir string) ([]byte, error) {
+	overrides := overridesDir(commandDir)
+
+	indexPath := filepath.Join(overrides, OverrideIndexFile)
+	if !fileExists(indexPath) {
+		indexPath = filepath.Join(commandDir, "index.md")
+		if !fileExists(indexPath) {
+			return nil, errors.NotFound("index.md not found")
+		}
+	}
+	content, err := os.ReadFile(indexPath)
+	if err != nil {
+		return nil, err
+	}
+
+	extra, err := os.ReadFile(filepath.Join(overrides, OverrideAppendFile))
+	if os.IsNotExist(err) {
+		return content, nil
+	}
+	if err != nil {
+		return nil, errors.FileError("read override", filepath.Join(overrides, OverrideAppendFile), err)
+	}
+
+	// The appended instructions start a paragraph of their own
+	content = append(bytes.TrimRight(content, "\r\n"), "\n\n"...)
+	return append(content, extra...), nil
+}
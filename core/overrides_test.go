@@ -0,0 +1,62 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gifflet/ccmd/internal/fs"
+)
+
+func TestCommandOverrides(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+
+	writeConfigMap(t, map[string]interface{}{"commands": []string{}, "targets": []map[string]string{{"type": TargetClaude}, {"type": TargetCursorCommands}}})
+	setupRegenCommand(t, "alpha")
+
+	overrides := filepath.Join(".claude", "overrides", "alpha")
+	require.NoError(t, os.MkdirAll(overrides, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(overrides, OverrideAppendFile), []byte("Use our style guide.\n"), 0644))
+
+	result, err := Regen(RegenOptions{ProjectPath: "."})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alpha"}, result.Regenerated)
+
+	standalone, err := os.ReadFile(filepath.Join(".claude", "commands", "alpha.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(standalone), "original body\n\nUse our style guide.\n")
+	assertFileContent(t, filepath.Join(".cursor", "commands", "alpha.md"), "original body\n\nUse our style guide.\n")
+
+	// An index.md in the overrides directory replaces the command's prompt
+	require.NoError(t, os.WriteFile(filepath.Join(overrides, OverrideIndexFile), []byte("project prompt"), 0644))
+	report, err := Verify(VerifyOptions{ProjectPath: "."})
+	require.NoError(t, err)
+	assert.Len(t, report.Issues, 2, "both targets are stale")
+
+	_, err = Regen(RegenOptions{ProjectPath: "."})
+	require.NoError(t, err)
+	assertFileContent(t, filepath.Join(".cursor", "commands", "alpha.md"), "project prompt\n\nUse our style guide.\n")
+
+	info, err := GetCommandDetails("alpha", ".", fs.OS{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{OverrideIndexFile, OverrideAppendFile}, info.Overrides)
+
+	// Without overrides the command's own prompt is used again
+	require.NoError(t, os.RemoveAll(overrides))
+	_, err = Regen(RegenOptions{ProjectPath: "."})
+	require.NoError(t, err)
+	assertFileContent(t, filepath.Join(".cursor", "commands", "alpha.md"), "original body")
+}
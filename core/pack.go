@@ -0,0 +1,348 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	ccmdfs "github.com/gifflet/ccmd/internal/fs"
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+const (
+	// PackExtension is the file extension of artifacts built by Pack
+	PackExtension = ".ccmd.tgz"
+	// PackManifestName is the manifest embedded in every artifact, next to ccmd.yaml
+	PackManifestName = "ccmd-manifest.json"
+	// PackManifestVersion is the format version of PackManifest
+	PackManifestVersion = 1
+
+	// templateExtension marks files rendered with the command metadata when packing
+	templateExtension = ".tmpl"
+)
+
+// PackOptions represents options for building a command artifact
+type PackOptions struct {
+	Path      string // Command repository (default: current directory)
+	OutputDir string // Directory for the artifact and manifest (default: Path)
+}
+
+// PackFile is a file inside an artifact
+type PackFile struct {
+	Path     string `json:"path"`
+	Size     int64  `json:"size"`
+	Checksum string `json:"checksum"`
+}
+
+// PackManifest describes a packed command. The copy embedded in the artifact lists its
+// files; the copy written next to it also records the artifact checksum, so catalogs can
+// index and verify artifacts without downloading them.
+type PackManifest struct {
+	ManifestVersion int        `json:"manifest_version"`
+	Name            string     `json:"name"`
+	Version         string     `json:"version"`
+	Description     string     `json:"description,omitempty"`
+	Author          string     `json:"author,omitempty"`
+	Repository      string     `json:"repository,omitempty"`
+	Homepage        string     `json:"homepage,omitempty"`
+	License         string     `json:"license,omitempty"`
+	Tags            []string   `json:"tags,omitempty"`
+	Type            string     `json:"type,omitempty"`
+	Entry           string     `json:"entry,omitempty"`
+	Commands        []string   `json:"commands,omitempty"`
+	Plugins         []string   `json:"plugins,omitempty"`
+	Files           []PackFile `json:"files"`
+
+	Artifact string `json:"artifact,omitempty"`
+	Checksum string `json:"checksum,omitempty"`
+	Size     int64  `json:"size,omitempty"`
+}
+
+// PackResult is the outcome of Pack
+type PackResult struct {
+	Artifact     string        // path of the .ccmd.tgz artifact
+	ManifestPath string        // path of the manifest written next to it
+	Manifest     *PackManifest // manifest as written next to the artifact
+	Lint         *LintReport   // findings of the lint run that precedes packing
+}
+
+// Pack validates a command repository, renders its .tmpl files with the command
+// metadata and writes <name>-<version>.ccmd.tgz plus a <name>-<version>.ccmd.json
+// manifest. The artifact is reproducible: entries are sorted and carry no timestamps.
+// It can be installed directly with 'ccmd install <artifact>'.
+func Pack(opts PackOptions) (*PackResult, error) {
+	root := opts.Path
+	if root == "" {
+		root = "."
+	}
+	outputDir := opts.OutputDir
+	if outputDir == "" {
+		outputDir = root
+	}
+
+	report, err := Lint(root)
+	if err != nil {
+		return nil, err
+	}
+	result := &PackResult{Lint: report}
+	if report.Errors > 0 {
+		return result, errors.InvalidInput(fmt.Sprintf("%s has %d lint error(s); run 'ccmd lint' for details", root, report.Errors))
+	}
+
+	metadata, err := readSourceMetadata(root)
+	if err != nil {
+		return result, err
+	}
+
+	files, err := packFiles(root, metadata)
+	if err != nil {
+		return result, err
+	}
+
+	manifest := newPackManifest(metadata)
+	for _, name := range sortedKeys(files) {
+		sum := sha256.Sum256(files[name].data)
+		manifest.Files = append(manifest.Files, PackFile{
+			Path:     name,
+			Size:     int64(len(files[name].data)),
+			Checksum: "sha256:" + hex.EncodeToString(sum[:]),
+		})
+	}
+
+	embedded, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return result, errors.FileError("marshal manifest", PackManifestName, err)
+	}
+	files[PackManifestName] = packEntry{data: append(embedded, '\n'), mode: 0644}
+
+	base := fmt.Sprintf("%s-%s", metadata.Name, strings.TrimPrefix(metadata.Version, "v"))
+	artifact, err := buildPackArchive(base, files)
+	if err != nil {
+		return result, err
+	}
+
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return result, errors.FileError("create output directory", outputDir, err)
+	}
+
+	sum := sha256.Sum256(artifact)
+	manifest.Artifact = base + PackExtension
+	manifest.Checksum = "sha256:" + hex.EncodeToString(sum[:])
+	manifest.Size = int64(len(artifact))
+
+	result.Artifact = filepath.Join(outputDir, manifest.Artifact)
+	if err := writeFileAtomic(result.Artifact, artifact, 0644); err != nil {
+		return result, err
+	}
+
+	external, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return result, errors.FileError("marshal manifest", base, err)
+	}
+	result.ManifestPath = filepath.Join(outputDir, base+".ccmd.json")
+	if err := writeFileAtomic(result.ManifestPath, append(external, '\n'), 0644); err != nil {
+		return result, err
+	}
+	result.Manifest = manifest
+
+	return result, nil
+}
+
+func newPackManifest(metadata *ProjectConfig) *PackManifest {
+	return &PackManifest{
+		ManifestVersion: PackManifestVersion,
+		Name:            metadata.Name,
+		Version:         metadata.Version,
+		Description:     metadata.Description,
+		Author:          metadata.Author,
+		Repository:      metadata.Repository,
+		Homepage:        metadata.Homepage,
+		License:         metadata.License,
+		Tags:            metadata.Tags,
+		Type:            metadata.Type,
+		Entry:           metadata.Entry,
+		Commands:        metadata.Commands,
+		Plugins:         metadata.Plugins,
+		Files:           []PackFile{},
+	}
+}
+
+type packEntry struct {
+	data []byte
+	mode os.FileMode
+}
+
+// packFiles reads the files to pack, keyed by slash-separated path. .git and earlier
+// artifacts are skipped, .tmpl files are rendered and ccmd.yaml is written from the
+// merged metadata, so artifacts of front-matter commands install like any other.
+func packFiles(root string, metadata *ProjectConfig) (map[string]packEntry, error) {
+	files := make(map[string]packEntry)
+
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		name := filepath.ToSlash(rel)
+		if !d.Type().IsRegular() || strings.HasSuffix(name, PackExtension) || strings.HasSuffix(name, ".ccmd.json") ||
+			name == ConfigFileName || name == PackManifestName {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(ccmdfs.LongPath(p))
+		if err != nil {
+			return err
+		}
+
+		if strings.HasSuffix(name, templateExtension) {
+			name = strings.TrimSuffix(name, templateExtension)
+			if data, err = renderPackTemplate(rel, data, metadata); err != nil {
+				return err
+			}
+		}
+		if _, ok := files[name]; ok {
+			return errors.Conflict(fmt.Sprintf("%s and %s%s would both be packed as %s", name, name, templateExtension, name))
+		}
+		files[name] = packEntry{data: data, mode: info.Mode().Perm()}
+		return nil
+	})
+	if err != nil {
+		if stderrors.Is(err, errors.ErrConflict) || stderrors.Is(err, errors.ErrInvalidInput) {
+			return nil, err
+		}
+		return nil, errors.FileError("read command files", root, err)
+	}
+
+	config, err := yaml.Marshal(metadata)
+	if err != nil {
+		return nil, errors.FileError("marshal metadata", ConfigFileName, err)
+	}
+	files[ConfigFileName] = packEntry{data: config, mode: 0644}
+
+	return files, nil
+}
+
+// renderPackTemplate executes a .tmpl file with the command metadata, e.g. {{.Version}}
+func renderPackTemplate(name string, data []byte, metadata *ProjectConfig) ([]byte, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(string(data))
+	if err != nil {
+		return nil, errors.InvalidInput(fmt.Sprintf("template %s: %v", name, err))
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, metadata); err != nil {
+		return nil, errors.InvalidInput(fmt.Sprintf("template %s: %v", name, err))
+	}
+	return buf.Bytes(), nil
+}
+
+// buildPackArchive writes files under a single top-level directory, in sorted order and
+// with fixed timestamps and owners so the same input always gives the same bytes
+func buildPackArchive(base string, files map[string]packEntry) ([]byte, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+
+	for _, name := range sortedKeys(files) {
+		entry := files[name]
+		mode := int64(0644)
+		if entry.mode&0111 != 0 {
+			mode = 0755
+		}
+		header := &tar.Header{
+			Name:     path.Join(base, name),
+			Mode:     mode,
+			Size:     int64(len(entry.data)),
+			ModTime:  time.Unix(0, 0),
+			Typeflag: tar.TypeReg,
+			Format:   tar.FormatPAX,
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return nil, errors.FileError("write artifact", name, err)
+		}
+		if _, err := tw.Write(entry.data); err != nil {
+			return nil, errors.FileError("write artifact", name, err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, errors.FileError("write artifact", base, err)
+	}
+	if err := gz.Close(); err != nil {
+		return nil, errors.FileError("write artifact", base, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// verifyPackManifest checks extracted files against the manifest embedded by Pack.
+// Archives without a manifest are accepted as they are.
+func verifyPackManifest(root string) error {
+	data, err := os.ReadFile(filepath.Join(root, PackManifestName))
+	if err != nil {
+		return nil
+	}
+
+	var manifest PackManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return errors.InvalidInput(fmt.Sprintf("invalid %s: %v", PackManifestName, err))
+	}
+
+	for _, file := range manifest.Files {
+		content, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(file.Path)))
+		if err != nil {
+			return errors.InvalidInput(fmt.Sprintf("artifact is missing %s listed in its manifest", file.Path))
+		}
+		sum := sha256.Sum256(content)
+		if got := "sha256:" + hex.EncodeToString(sum[:]); got != file.Checksum {
+			return errors.InvalidInput(fmt.Sprintf("checksum mismatch for %s: expected %s, got %s", file.Path, file.Checksum, got))
+		}
+	}
+	return nil
+}
+
+func sortedKeys(files map[string]packEntry) []string {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,108 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+func TestPack(t *testing.T) {
+	dir := writeLintRepo(t, map[string]string{
+		"ccmd.yaml":      archiveMetadata,
+		"index.md":       "---\ndescription: Says hello\n---\nSay hello\n",
+		"README.md.tmpl": "# {{.Name}} {{.Version}}\n",
+		".git/HEAD":      "ref: refs/heads/main\n",
+	})
+	outputDir := filepath.Join(t.TempDir(), "dist")
+
+	result, err := Pack(PackOptions{Path: dir, OutputDir: outputDir})
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(outputDir, "hello-1.0.0.ccmd.tgz"), result.Artifact)
+	assert.Equal(t, filepath.Join(outputDir, "hello-1.0.0.ccmd.json"), result.ManifestPath)
+
+	var paths []string
+	for _, f := range result.Manifest.Files {
+		paths = append(paths, f.Path)
+	}
+	assert.Equal(t, []string{"README.md", "ccmd.yaml", "index.md"}, paths)
+
+	data, err := os.ReadFile(result.ManifestPath)
+	require.NoError(t, err)
+	var manifest PackManifest
+	require.NoError(t, json.Unmarshal(data, &manifest))
+	assert.Equal(t, "hello", manifest.Name)
+	assert.Regexp(t, `^sha256:[0-9a-f]{64}$`, manifest.Checksum)
+
+	// The same input gives the same artifact
+	first, err := os.ReadFile(result.Artifact)
+	require.NoError(t, err)
+	_, err = Pack(PackOptions{Path: dir, OutputDir: outputDir})
+	require.NoError(t, err)
+	second, err := os.ReadFile(result.Artifact)
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+
+	extracted := t.TempDir()
+	root, _, err := fetchArchive(context.Background(), result.Artifact, manifest.Checksum, extracted)
+	require.NoError(t, err)
+	readme, err := os.ReadFile(filepath.Join(root, "README.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "# hello 1.0.0\n", string(readme))
+	assert.FileExists(t, filepath.Join(root, PackManifestName))
+	assert.NoDirExists(t, filepath.Join(root, ".git"))
+}
+
+func TestPackRejectsLintErrors(t *testing.T) {
+	dir := writeLintRepo(t, map[string]string{"ccmd.yaml": "name: hello\n", "index.md": "Hello\n"})
+
+	result, err := Pack(PackOptions{Path: dir})
+	assert.ErrorIs(t, err, errors.ErrInvalidInput)
+	require.NotNil(t, result)
+	assert.Positive(t, result.Lint.Errors)
+}
+
+func TestInstallPackedArtifact(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	writeConfig(t, []string{})
+
+	src := writeLintRepo(t, map[string]string{"index.md": frontMatterIndex})
+	result, err := Pack(PackOptions{Path: src, OutputDir: "dist"})
+	require.NoError(t, err)
+
+	name, _, err := Install(context.Background(), InstallOptions{Repository: "dist/hello-1.0.0.ccmd.tgz"})
+	require.NoError(t, err)
+	assert.Equal(t, "hello", name)
+	assert.Equal(t, result.Manifest.Checksum, readLockFile(t).Commands["hello"].Commit)
+	assert.Equal(t, "dist/hello", ExtractRepoPath("dist/hello-1.0.0.ccmd.tgz"))
+}
+
+func TestVerifyPackManifest(t *testing.T) {
+	dir := writeLintRepo(t, map[string]string{"index.md": "Hello\n"})
+	assert.NoError(t, verifyPackManifest(dir), "archives without a manifest are accepted")
+
+	manifest := PackManifest{Files: []PackFile{{Path: "index.md", Checksum: "sha256:0000"}}}
+	data, err := json.Marshal(manifest)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, PackManifestName), data, 0644))
+
+	err = verifyPackManifest(dir)
+	require.ErrorIs(t, err, errors.ErrInvalidInput)
+	assert.Contains(t, err.Error(), "checksum mismatch for index.md")
+}
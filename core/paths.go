@@ -0,0 +1,144 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+
+	"github.com/gifflet/ccmd/pkg/config"
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+// ProjectPaths locates the files ccmd keeps in a project
+type ProjectPaths struct {
+	Root     string
+	Config   string // ccmd.yaml
+	Lock     string // ccmd-lock.yaml
+	Commands string // the directory holding one directory per installed command
+}
+
+// ResolvePaths returns where the project at projectRoot keeps ccmd.yaml, ccmd-lock.yaml
+// and its commands, as set by the paths settings of .ccmdrc.yaml or CCMD_PATHS_CONFIG,
+// CCMD_PATHS_LOCK and CCMD_PATHS_COMMANDS. The lock file is next to ccmd.yaml unless
+// set. Paths outside the project are rejected.
+func ResolvePaths(projectRoot string) (ProjectPaths, error) {
+	settings, err := config.Load(projectRoot)
+	if err != nil {
+		return defaultPaths(projectRoot), err
+	}
+	return resolvePaths(projectRoot, settings.Paths)
+}
+
+func resolvePaths(projectRoot string, settings config.PathSettings) (ProjectPaths, error) {
+	paths := defaultPaths(projectRoot)
+	for _, setting := range []struct {
+		key, value string
+		path       *string
+	}{
+		{"paths.config", settings.Config, &paths.Config},
+		{"paths.commands", settings.Commands, &paths.Commands},
+		{"paths.lock", settings.Lock, &paths.Lock},
+	} {
+		if setting.value == "" {
+			continue
+		}
+		rel := filepath.FromSlash(setting.value)
+		if !filepath.IsLocal(rel) {
+			return defaultPaths(projectRoot), errors.InvalidInput(fmt.Sprintf(
+				"%s %q must be relative to the project root and stay inside it", setting.key, setting.value))
+		}
+		*setting.path = filepath.Join(projectRoot, rel)
+		if setting.key == "paths.config" {
+			paths.Lock = filepath.Join(filepath.Dir(paths.Config), LockFileName)
+		}
+	}
+	return paths, nil
+}
+
+// defaultPaths returns the locations of a project without paths settings
+func defaultPaths(projectRoot string) ProjectPaths {
+	return ProjectPaths{
+		Root:     projectRoot,
+		Config:   filepath.Join(projectRoot, ConfigFileName),
+		Lock:     filepath.Join(projectRoot, LockFileName),
+		Commands: filepath.Join(projectRoot, ".claude", "commands"),
+	}
+}
+
+// resolvedPaths holds the paths settings of each project root as the last operation on
+// it resolved them, keyed by cacheKey
+var resolvedPaths sync.Map
+
+// resolveProjectPaths resolves the paths of a project once for an operation and keeps
+// them for the lookups the operation makes. findProjectRootFrom calls it, so an
+// operation stops on paths settings that cannot be loaded or are invalid rather than
+// using the default locations.
+func resolveProjectPaths(projectRoot string) (ProjectPaths, error) {
+	settings, err := config.Load(projectRoot)
+	if err != nil {
+		resolvedPaths.Delete(cacheKey(projectRoot))
+		return ProjectPaths{}, err
+	}
+	paths, err := resolvePaths(projectRoot, settings.Paths)
+	if err != nil {
+		resolvedPaths.Delete(cacheKey(projectRoot))
+		return ProjectPaths{}, err
+	}
+	resolvedPaths.Store(cacheKey(projectRoot), settings.Paths)
+	return paths, nil
+}
+
+// projectPaths returns the locations of a project's files as the operation resolved
+// them. Operations find their root with findProjectRootFrom, which already failed on
+// invalid settings; only a root reached otherwise is resolved here, and it gets the
+// default locations if its settings are invalid.
+func projectPaths(projectRoot string) ProjectPaths {
+	if settings, ok := resolvedPaths.Load(cacheKey(projectRoot)); ok {
+		if paths, err := resolvePaths(projectRoot, settings.(config.PathSettings)); err == nil {
+			return paths
+		}
+	}
+	paths, err := resolveProjectPaths(projectRoot)
+	if err != nil {
+		return defaultPaths(projectRoot)
+	}
+	return paths
+}
+
+// configFilePath returns the path of the project's ccmd.yaml
+func configFilePath(projectRoot string) string {
+	return projectPaths(projectRoot).Config
+}
+
+// lockFilePath returns the path of the project's ccmd-lock.yaml
+func lockFilePath(projectRoot string) string {
+	return projectPaths(projectRoot).Lock
+}
+
+// projectCommandsDir returns the directory holding the project's command directories
+func projectCommandsDir(projectRoot string) string {
+	return projectPaths(projectRoot).Commands
+}
+
+// installedCommandDir returns the directory of an installed command
+func installedCommandDir(projectRoot, name string) string {
+	return filepath.Join(projectCommandsDir(projectRoot), filepath.FromSlash(name))
+}
+
+// projectRelative returns path relative to the project root for messages, or path itself
+// when it is elsewhere
+func projectRelative(projectRoot, path string) string {
+	if rel, err := filepath.Rel(projectRoot, path); err == nil && filepath.IsLocal(rel) {
+		return rel
+	}
+	return path
+}
@@ -0,0 +1,123 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gifflet/ccmd/pkg/config"
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+func TestResolvePaths(t *testing.T) {
+	t.Setenv(config.ConfigEnv, filepath.Join(t.TempDir(), "config.yaml"))
+	root := t.TempDir()
+
+	paths, err := ResolvePaths(root)
+	require.NoError(t, err)
+	assert.Equal(t, ProjectPaths{
+		Root:     root,
+		Config:   filepath.Join(root, "ccmd.yaml"),
+		Lock:     filepath.Join(root, "ccmd-lock.yaml"),
+		Commands: filepath.Join(root, ".claude", "commands"),
+	}, paths)
+
+	// The lock file follows ccmd.yaml unless it is set
+	require.NoError(t, os.WriteFile(config.ProjectConfigPath(root),
+		[]byte("paths:\n  config: .config/ccmd/ccmd.yaml\n  commands: .config/ccmd/commands\n"), 0644))
+	paths, err = ResolvePaths(root)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, ".config", "ccmd", "ccmd.yaml"), paths.Config)
+	assert.Equal(t, filepath.Join(root, ".config", "ccmd", "ccmd-lock.yaml"), paths.Lock)
+	assert.Equal(t, filepath.Join(root, ".config", "ccmd", "commands"), paths.Commands)
+
+	t.Setenv("CCMD_PATHS_LOCK", "locks/ccmd-lock.yaml")
+	paths, err = ResolvePaths(root)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, "locks", "ccmd-lock.yaml"), paths.Lock)
+
+	t.Setenv("CCMD_PATHS_COMMANDS", "../commands")
+	paths, err = ResolvePaths(root)
+	assert.ErrorIs(t, err, errors.ErrInvalidInput)
+	assert.Equal(t, defaultPaths(root), paths)
+}
+
+func TestOperationsFailOnInvalidPaths(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	t.Setenv(config.ConfigEnv, filepath.Join(t.TempDir(), "config.yaml"))
+	writeConfig(t, []string{})
+
+	t.Setenv("CCMD_PATHS_COMMANDS", "../commands")
+	_, err := List(ListOptions{ProjectPath: "."})
+	assert.ErrorIs(t, err, errors.ErrInvalidInput)
+	_, err = findProjectRootFrom(".")
+	assert.ErrorIs(t, err, errors.ErrInvalidInput)
+
+	t.Setenv("CCMD_PATHS_COMMANDS", "")
+	require.NoError(t, os.WriteFile(config.ProjectConfigPath("."), []byte("paths: [\n"), 0644))
+	_, err = List(ListOptions{ProjectPath: "."})
+	assert.Error(t, err)
+}
+
+func TestInstallRelocatedPaths(t *testing.T) {
+	repo, _ := writeCommandRepo(t)
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	ctx := context.Background()
+	t.Setenv(config.ConfigEnv, filepath.Join(t.TempDir(), "config.yaml"))
+	t.Setenv("CCMD_PATHS_CONFIG", ".config/ccmd/ccmd.yaml")
+	t.Setenv("CCMD_PATHS_COMMANDS", ".config/ccmd/commands")
+
+	require.NoError(t, InitProject(InitOptions{ProjectPath: ".", Name: "project", Version: "1.0.0"}))
+	assert.NoFileExists(t, ConfigFileName)
+	require.DirExists(t, filepath.Join(".config", "ccmd", "commands"))
+
+	// The relocated ccmd.yaml marks the project root for subdirectories
+	require.NoError(t, os.MkdirAll("docs", 0755))
+	root, err := findProjectRootFrom("docs")
+	require.NoError(t, err)
+	assert.Equal(t, ".", root)
+
+	_, _, err = Install(ctx, InstallOptions{Repository: repo + "@v1.0.0"})
+	require.NoError(t, err)
+	assert.FileExists(t, filepath.Join(".config", "ccmd", "ccmd-lock.yaml"))
+	assert.NoFileExists(t, LockFileName)
+	assert.DirExists(t, filepath.Join(".config", "ccmd", "commands", "tool"))
+	assert.NoDirExists(t, filepath.Join(".claude", "commands", "tool"))
+	// Claude Code reads the standalone file from .claude/commands
+	assert.FileExists(t, filepath.Join(".claude", "commands", "tool.md"))
+
+	data, err := os.ReadFile(filepath.Join(".config", "ccmd", "ccmd.yaml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), repo)
+
+	commands, err := List(ListOptions{ProjectPath: "docs"})
+	require.NoError(t, err)
+	require.Len(t, commands, 1)
+	assert.False(t, commands[0].BrokenStructure, commands[0].StructureError)
+
+	orphans, err := FindOrphans(".")
+	require.NoError(t, err)
+	assert.Empty(t, orphans)
+
+	require.NoError(t, Remove(RemoveOptions{Name: "tool", UpdateFiles: true}))
+	assert.NoDirExists(t, filepath.Join(".config", "ccmd", "commands", "tool"))
+	assert.NotContains(t, readLockFileFromPath(t, filepath.Join(".config", "ccmd", LockFileName)).Commands, "tool")
+	data, err = os.ReadFile(filepath.Join(".config", "ccmd", "ccmd.yaml"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), repo)
+}
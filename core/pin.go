@@ -0,0 +1,231 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"fmt"
+
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+// PinResult describes the ccmd.yaml entry of a command pinned or unpinned
+type PinResult struct {
+	Name    string
+	Commit  string // commit the command is pinned at
+	Version string // version ccmd.yaml requests now; empty when it lists no entry
+	// Previous is the version ccmd.yaml requested before
+	Previous string
+	// Unchanged is set when the command already was pinned, or not pinned
+	Unchanged bool
+}
+
+// Pin freezes an installed command at the commit it is installed at. Its ccmd.yaml
+// entry is rewritten to name the full commit, and its lock entry is marked pinned, so
+// 'ccmd update' skips it even with --force. The version the entry requested before is
+// kept in the lock file for Unpin.
+func Pin(projectPath, name string) (*PinResult, error) {
+	projectRoot, err := findProjectRootFrom(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	locked, err := lockedCommandEntry(name, projectRoot)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case locked.Local():
+		return nil, errors.InvalidInput(fmt.Sprintf("command %q is local-only and has no commit to pin", name))
+	case isDownloadSource(locked.Source):
+		return nil, errors.InvalidInput(fmt.Sprintf("command %q was installed from a download and has no commit to pin", name))
+	case !isFullCommitHash(locked.Commit):
+		return nil, errors.InvalidInput(fmt.Sprintf("%s records no commit for %q; reinstall it first", LockFileName, name))
+	}
+
+	result := &PinResult{Name: name, Commit: locked.Commit, Version: locked.Commit, Previous: locked.PinnedFrom}
+	if locked.Pinned {
+		result.Unchanged = true
+		return result, nil
+	}
+
+	defer snapshotBefore(projectRoot, AuditPin)()
+
+	previous, listed, err := setConfigVersion(projectRoot, lockKey(name, locked), locked.Commit)
+	if err != nil {
+		return nil, err
+	}
+	if !listed {
+		result.Version = ""
+	}
+	if _, pinned, _ := parseCommitPin(previous); pinned {
+		// A commit written by hand is no version to return to
+		previous = ""
+	}
+	result.Previous = previous
+
+	err = updatePin(projectRoot, name, true, previous)
+	recordAudit(projectRoot, auditResult(AuditEvent{
+		Action:     AuditPin,
+		Name:       name,
+		Repository: NormalizeRepositoryURL(locked.Source),
+		Version:    result.Version,
+		Commit:     locked.Commit,
+	}, err))
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// UnpinOptions represents options for unpinning a command
+type UnpinOptions struct {
+	Name string
+	// To is the version ccmd.yaml requests after unpinning. Empty restores the version
+	// requested before 'ccmd pin', or a range for the installed version made with the
+	// save strategy.
+	To string
+}
+
+// Unpin lets a pinned command follow updates again: its ccmd.yaml entry requests a
+// floating version again and its lock entry is no longer pinned. Commands pinned by
+// hand, with a full commit in ccmd.yaml, are unpinned too. Nothing is reinstalled.
+func Unpin(projectPath string, opts UnpinOptions) (*PinResult, error) {
+	projectRoot, err := findProjectRootFrom(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	locked, err := lockedCommandEntry(opts.Name, projectRoot)
+	if err != nil {
+		return nil, err
+	}
+	if _, pinned, err := parseCommitPin(opts.To); err != nil || pinned {
+		return nil, errors.InvalidInput(fmt.Sprintf("--to %q is a commit; use 'ccmd pin' to pin one", opts.To))
+	}
+
+	key := lockKey(opts.Name, locked)
+	current := configuredVersion(projectRoot, key)
+	_, pinnedInConfig, _ := parseCommitPin(current)
+	result := &PinResult{Name: opts.Name, Version: current, Previous: current}
+	if !locked.Pinned && !pinnedInConfig {
+		result.Unchanged = true
+		return result, nil
+	}
+
+	version := opts.To
+	if version == "" {
+		version = locked.PinnedFrom
+	}
+	if version == "" {
+		version = constraintFor(defaultSaveStrategy(projectRoot), locked.Version)
+	}
+
+	defer snapshotBefore(projectRoot, AuditUnpin)()
+
+	_, listed, err := setConfigVersion(projectRoot, key, version)
+	if err != nil {
+		return nil, err
+	}
+	result.Version = ""
+	if listed {
+		result.Version = version
+	}
+
+	err = updatePin(projectRoot, opts.Name, false, "")
+	recordAudit(projectRoot, auditResult(AuditEvent{
+		Action:          AuditUnpin,
+		Name:            opts.Name,
+		Repository:      NormalizeRepositoryURL(locked.Source),
+		Version:         result.Version,
+		PreviousVersion: current,
+		Commit:          locked.Commit,
+	}, err))
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// configuredVersion returns the version the ccmd.yaml entry with the given key
+// requests, in the shared list or a profile
+func configuredVersion(projectRoot, key string) string {
+	if !ProjectConfigExists(projectRoot) {
+		return ""
+	}
+	cfg, err := LoadProjectConfig(projectRoot)
+	if err != nil {
+		return ""
+	}
+	for _, specs := range append([][]string{cfg.Commands}, profileLists(cfg)...) {
+		for _, spec := range specs {
+			if specKey(spec) == key {
+				_, version, _ := ParseInstanceSpec(spec)
+				return version
+			}
+		}
+	}
+	return ""
+}
+
+// setConfigVersion makes the ccmd.yaml entries with the given key, in the shared list
+// and in profiles, request version. It returns the version requested before and
+// whether any entry was found; without one ccmd.yaml is left alone.
+func setConfigVersion(projectRoot, key, version string) (previous string, listed bool, err error) {
+	if !ProjectConfigExists(projectRoot) {
+		return "", false, nil
+	}
+	cfg, err := LoadProjectConfig(projectRoot)
+	if err != nil {
+		return "", false, err
+	}
+
+	for _, specs := range append([][]string{cfg.Commands}, profileLists(cfg)...) {
+		for i, spec := range specs {
+			if specKey(spec) != key {
+				continue
+			}
+			repo, current, instance := ParseInstanceSpec(spec)
+			if !listed {
+				previous = current
+			}
+			specs[i] = formatCommandSpec(repo, version, instance)
+			listed = true
+		}
+	}
+	if !listed {
+		return "", false, nil
+	}
+	return previous, true, SaveProjectConfig(projectRoot, cfg)
+}
+
+// profileLists returns the command lists of the profiles of a configuration
+func profileLists(cfg *ProjectConfig) [][]string {
+	lists := make([][]string, 0, len(cfg.Profiles))
+	for _, specs := range cfg.Profiles {
+		lists = append(lists, specs)
+	}
+	return lists
+}
+
+// updatePin marks the lock entry of a command pinned, with the version to restore, or
+// clears the mark
+func updatePin(projectRoot, name string, pinned bool, from string) error {
+	lockPath := lockFilePath(projectRoot)
+	lockFile, err := ReadLockFile(lockPath)
+	if err != nil {
+		return err
+	}
+	cmd, ok := lockFile.Commands[name]
+	if !ok {
+		return errors.NotFound(fmt.Sprintf("command %q", name))
+	}
+
+	cmd.Pinned = pinned
+	cmd.PinnedFrom = from
+	return WriteLockFile(lockPath, lockFile)
+}
@@ -0,0 +1,103 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+func TestPinAndUnpin(t *testing.T) {
+	repo, release := writeCommandRepo(t)
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	writeConfig(t, []string{repo + "@^1.0.0"})
+	require.NoError(t, InstallFromConfig(ctx, ".", false))
+	locked, err := lockedCommandEntry("tool", ".")
+	require.NoError(t, err)
+	commit := locked.Commit
+
+	result, err := Pin(".", "tool")
+	require.NoError(t, err)
+	assert.Equal(t, commit, result.Version)
+	assert.Equal(t, "^1.0.0", result.Previous)
+
+	cfg, err := LoadProjectConfig(".")
+	require.NoError(t, err)
+	assert.Equal(t, []string{repo + "@" + commit}, cfg.Commands)
+	locked, err = lockedCommandEntry("tool", ".")
+	require.NoError(t, err)
+	assert.True(t, locked.Pinned)
+	assert.Equal(t, "^1.0.0", locked.PinnedFrom)
+
+	result, err = Pin(".", "tool")
+	require.NoError(t, err)
+	assert.True(t, result.Unchanged)
+
+	// Neither update, even forced, nor a sync moves a pinned command
+	release("v1.1.0")
+	_, err = Update(ctx, UpdateOptions{All: true, Force: true})
+	require.NoError(t, err)
+	require.NoError(t, InstallProfile(ctx, ".", ConfigInstallOptions{Force: true}))
+	locked, err = lockedCommandEntry("tool", ".")
+	require.NoError(t, err)
+	assert.Equal(t, commit, locked.Commit)
+	assert.True(t, locked.Pinned)
+	assert.Equal(t, "^1.0.0", locked.PinnedFrom)
+
+	_, err = Unpin(".", UnpinOptions{Name: "tool", To: commit})
+	assert.ErrorIs(t, err, errors.ErrInvalidInput)
+
+	result, err = Unpin(".", UnpinOptions{Name: "tool"})
+	require.NoError(t, err)
+	assert.Equal(t, "^1.0.0", result.Version)
+	cfg, err = LoadProjectConfig(".")
+	require.NoError(t, err)
+	assert.Equal(t, []string{repo + "@^1.0.0"}, cfg.Commands)
+	locked, err = lockedCommandEntry("tool", ".")
+	require.NoError(t, err)
+	assert.False(t, locked.Pinned)
+	assert.Empty(t, locked.PinnedFrom)
+
+	result, err = Unpin(".", UnpinOptions{Name: "tool"})
+	require.NoError(t, err)
+	assert.True(t, result.Unchanged)
+
+	_, err = Pin(".", "missing")
+	assert.ErrorIs(t, err, errors.ErrNotFound)
+}
+
+func TestUnpinHandWrittenCommit(t *testing.T) {
+	repo, _ := writeCommandRepo(t)
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	writeConfig(t, []string{repo})
+	require.NoError(t, InstallFromConfig(ctx, ".", false))
+	locked, err := lockedCommandEntry("tool", ".")
+	require.NoError(t, err)
+	writeConfig(t, []string{repo + "@" + locked.Commit})
+
+	result, err := Unpin(".", UnpinOptions{Name: "tool", To: "~1.0.0"})
+	require.NoError(t, err)
+	assert.Equal(t, "~1.0.0", result.Version)
+	assert.Equal(t, locked.Commit, result.Previous)
+	cfg, err := LoadProjectConfig(".")
+	require.NoError(t, err)
+	assert.Equal(t, []string{repo + "@~1.0.0"}, cfg.Commands)
+}
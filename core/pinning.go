@@ -0,0 +1,191 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"context"
+
+	"github.com/gifflet/ccmd/pkg/config"
+)
+
+// resolveInstallVersion picks the tag to install and the version to record in ccmd.yaml.
+//
+// Constraints such as ^1.2.0 are resolved to the newest matching remote tag and kept in
+// ccmd.yaml. Installs without a version pick the newest stable tag and record it using
+// the save strategy (caret unless configured otherwise), or track the default branch
+// when their default version is default-branch, see installDefaultVersion. Repositories
+// without semver tags keep tracking their default branch as well. Prereleases are only
+// picked with Pre or when the ccmd.yaml entry follows the beta channel.
+func resolveInstallVersion(ctx context.Context, projectRoot, repoURL string, opts *InstallOptions) error {
+	if opts.Version == "" {
+		opts.defaultVersion = installDefaultVersion(projectRoot, repoURL, *opts)
+	}
+	pre := opts.Pre || configuredChannel(projectRoot, repoURL, opts.As) == ChannelBeta
+	strategy := opts.SaveStrategy
+	explicit := strategy != ""
+	if !explicit {
+		strategy = defaultSaveStrategy(projectRoot)
+	}
+
+	switch {
+	case IsConstraint(opts.Version):
+		constraint := opts.Version
+		tag, err := resolveConstraint(ctx, opts.tags, repoURL, constraint, pre)
+		if err != nil {
+			return err
+		}
+		opts.Version = tag
+		opts.saveVersion = constraint
+		if explicit {
+			opts.saveVersion = constraintFor(strategy, tag)
+		}
+
+	case opts.Version == "" && opts.Commit == "" && opts.defaultVersion == DefaultVersionLatestRelease:
+		tags, err := opts.tags.list(ctx, repoURL)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			// Let the clone report connectivity problems
+			return nil
+		}
+		if tag, ok := highestMatchingTag(tags, nil, pre); ok {
+			opts.Version = tag
+			opts.saveVersion = constraintFor(strategy, tag)
+		}
+
+	case opts.Version != "" && explicit:
+		opts.saveVersion = constraintFor(strategy, opts.Version)
+
+	case opts.Version != "":
+		// Keep an existing range when the installed version still satisfies it
+		if existing := configuredConstraint(projectRoot, repoURL, opts.As); existing != "" {
+			if c, err := ParseConstraint(existing); err == nil {
+				if v, err := ParseSemver(opts.Version); err == nil && c.Check(v) {
+					opts.saveVersion = existing
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// configVersion returns the version to write to ccmd.yaml for an install
+func configVersion(opts InstallOptions) string {
+	if opts.saveVersion != "" {
+		return opts.saveVersion
+	}
+
+	// Full commit hashes are immutable pins and are kept whole
+	version := opts.Version
+	if isCommitHash(version) && len(version) > 7 && !isFullCommitHash(version) {
+		version = version[:7]
+	}
+	return version
+}
+
+// installDefaultVersion returns how an install without a version picks one: as
+// requested, as the lock file records for the command, or as the default_version
+// setting says
+func installDefaultVersion(projectRoot, repoURL string, opts InstallOptions) string {
+	if opts.DefaultVersion != "" {
+		return opts.DefaultVersion
+	}
+	if lockFile, err := ReadLockFile(lockFilePath(projectRoot)); err == nil {
+		if _, locked := lockedCommand(lockFile, repoURL, opts.As); locked != nil && locked.DefaultVersion != "" {
+			return locked.DefaultVersion
+		}
+	}
+	return defaultVersionSetting(projectRoot)
+}
+
+// defaultVersionSetting returns the default version from the layered configuration
+func defaultVersionSetting(projectRoot string) string {
+	settings, err := config.Load(projectRoot)
+	if err != nil || ValidateDefaultVersion(settings.DefaultVersion) != nil || settings.DefaultVersion == "" {
+		return DefaultVersionLatestRelease
+	}
+	return settings.DefaultVersion
+}
+
+// recordDefaultVersion stores how the version of a command was picked in the lock file
+func recordDefaultVersion(projectRoot, name, defaultVersion string) error {
+	lockPath := lockFilePath(projectRoot)
+	lockFile, err := ReadLockFile(lockPath)
+	if err != nil {
+		return err
+	}
+	cmd, ok := lockFile.Commands[name]
+	if !ok {
+		return nil
+	}
+
+	cmd.DefaultVersion = defaultVersion
+	return WriteLockFile(lockPath, lockFile)
+}
+
+// defaultSaveStrategy returns the save strategy from the layered configuration
+func defaultSaveStrategy(projectRoot string) string {
+	settings, err := config.Load(projectRoot)
+	if err != nil || ValidateSaveStrategy(settings.SaveStrategy) != nil || settings.SaveStrategy == "" {
+		return SaveCaret
+	}
+	return settings.SaveStrategy
+}
+
+// configuredConstraint returns the version range recorded in ccmd.yaml for a repository,
+// or for a side-by-side instance of it, if any
+func configuredConstraint(projectRoot, repoURL, instance string) string {
+	if !ProjectConfigExists(projectRoot) {
+		return ""
+	}
+
+	cfg, err := LoadProjectConfig(projectRoot)
+	if err != nil {
+		return ""
+	}
+
+	target := instanceKey(ExtractRepoPath(repoURL), instance)
+	for _, spec := range append(append([]string{}, cfg.Commands...), cfg.Plugins...) {
+		repo, version, name := ParseInstanceSpec(spec)
+		if IsConstraint(version) && instanceKey(ExtractRepoPath(NormalizeRepositoryURL(repo)), name) == target {
+			return version
+		}
+	}
+
+	return ""
+}
+
+// configuredChannel returns the update channel of the ccmd.yaml entry of a repository, or
+// of a side-by-side instance of it; entries without one follow the stable channel
+func configuredChannel(projectRoot, repoURL, instance string) string {
+	if !ProjectConfigExists(projectRoot) {
+		return ChannelStable
+	}
+
+	cfg, err := LoadProjectConfig(projectRoot)
+	if err != nil {
+		return ChannelStable
+	}
+
+	target := instanceKey(ExtractRepoPath(repoURL), instance)
+	for _, spec := range append(append([]string{}, cfg.Commands...), cfg.Plugins...) {
+		if specKey(spec) != target {
+			continue
+		}
+		if cond := cfg.conditionFor(spec); cond != nil && cond.Channel != "" {
+			return cond.Channel
+		}
+		break
+	}
+
+	return ChannelStable
+}
@@ -0,0 +1,94 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+func TestInstallDefaultVersion(t *testing.T) {
+	repo, _ := writeCommandRepo(t)
+	dir := strings.TrimPrefix(repo, "file://")
+	tagged, err := gitGetCurrentCommit(dir)
+	require.NoError(t, err)
+	out, err := exec.Command("git", "-C", dir, "commit", "--quiet", "--allow-empty", "-m", "unreleased").CombinedOutput()
+	require.NoError(t, err, string(out))
+	head, err := gitGetCurrentCommit(dir)
+	require.NoError(t, err)
+
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	_, _, err = Install(ctx, InstallOptions{Repository: repo, DefaultVersion: "newest"})
+	assert.ErrorIs(t, err, errors.ErrInvalidInput)
+
+	// default-branch installs the head of the default branch and tracks it
+	_, _, err = Install(ctx, InstallOptions{Repository: repo, DefaultVersion: DefaultVersionDefaultBranch})
+	require.NoError(t, err)
+	locked := readLockFile(t).Commands["tool"]
+	require.NotNil(t, locked)
+	assert.Equal(t, head, locked.Commit)
+	assert.NotEmpty(t, locked.Branch)
+	assert.Equal(t, DefaultVersionDefaultBranch, locked.DefaultVersion)
+	cfg, err := LoadProjectConfig(".")
+	require.NoError(t, err)
+	assert.Equal(t, []string{repo}, cfg.Commands)
+
+	// Reinstalls keep what the lock file records over the setting
+	_, _, err = Install(ctx, InstallOptions{Repository: repo, Force: true})
+	require.NoError(t, err)
+	locked = readLockFile(t).Commands["tool"]
+	assert.Equal(t, head, locked.Commit)
+	assert.Equal(t, DefaultVersionDefaultBranch, locked.DefaultVersion)
+
+	_, _, err = Install(ctx, InstallOptions{Repository: repo, Force: true, DefaultVersion: DefaultVersionLatestRelease})
+	require.NoError(t, err)
+	locked = readLockFile(t).Commands["tool"]
+	assert.Equal(t, tagged, locked.Commit)
+	assert.Empty(t, locked.Branch)
+	assert.Equal(t, DefaultVersionLatestRelease, locked.DefaultVersion)
+	cfg, err = LoadProjectConfig(".")
+	require.NoError(t, err)
+	assert.Equal(t, []string{repo + "@^1.0.0"}, cfg.Commands)
+
+	// Explicit versions record no default version
+	_, _, err = Install(ctx, InstallOptions{Repository: repo, Version: "v1.0.0", Force: true})
+	require.NoError(t, err)
+	assert.Empty(t, readLockFile(t).Commands["tool"].DefaultVersion)
+}
+
+func TestDefaultVersionSetting(t *testing.T) {
+	repo, _ := writeCommandRepo(t)
+	dir := strings.TrimPrefix(repo, "file://")
+	out, err := exec.Command("git", "-C", dir, "commit", "--quiet", "--allow-empty", "-m", "unreleased").CombinedOutput()
+	require.NoError(t, err, string(out))
+	head, err := gitGetCurrentCommit(dir)
+	require.NoError(t, err)
+
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	t.Setenv("CCMD_DEFAULT_VERSION", DefaultVersionDefaultBranch)
+
+	writeConfig(t, []string{repo})
+	require.NoError(t, InstallFromConfig(context.Background(), ".", false))
+	locked := readLockFile(t).Commands["tool"]
+	require.NotNil(t, locked)
+	assert.Equal(t, head, locked.Commit)
+	assert.Equal(t, DefaultVersionDefaultBranch, locked.DefaultVersion)
+}
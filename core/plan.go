@@ -0,0 +1,592 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+// PlanFormatVersion is the version of the plan file format
+const PlanFormatVersion = 1
+
+// Plan actions
+const (
+	PlanInstall = "install"
+	PlanUpdate  = "update"
+	PlanRemove  = "remove"
+	// PlanKeep marks an installed command a sync leaves alone. KeptCommands reports
+	// them; plans never hold them.
+	PlanKeep = "keep"
+)
+
+// Plan reasons
+const (
+	PlanNewEntry         = "new-entry"         // listed in ccmd.yaml, never installed
+	PlanMissingFiles     = "missing-files"     // locked, but the command files are missing
+	PlanConstraintChange = "constraint-change" // ccmd.yaml no longer accepts the locked version
+	PlanOrphan           = "orphan"            // installed, no longer listed in ccmd.yaml
+	PlanInSync           = "in-sync"           // installed at a version ccmd.yaml accepts
+	PlanEphemeral        = "ephemeral"         // installed with --no-save, not in ccmd.yaml
+	PlanLocalOnly        = "local-only"        // tracked with source local, not in ccmd.yaml
+)
+
+// Plan is the set of changes a sync makes, with every version and commit resolved.
+// Sync applies it exactly when it is passed in SyncOptions.Plan.
+type Plan struct {
+	FormatVersion int       `json:"format_version"`
+	CreatedAt     time.Time `json:"created_at"`
+	Profile       string    `json:"profile,omitempty"`
+	// ConfigDigest is the sha256 of ccmd.yaml the plan was computed from
+	ConfigDigest string       `json:"config_digest"`
+	Actions      []PlanAction `json:"actions"`
+}
+
+// PlanAction is one install, update or removal of a plan
+type PlanAction struct {
+	Action     string `json:"action"`
+	Name       string `json:"name"`
+	Repository string `json:"repository,omitempty"`
+	Spec       string `json:"spec,omitempty"`    // ccmd.yaml entry of installs and updates
+	From       string `json:"from,omitempty"`    // locked version of updates and removals
+	Version    string `json:"version,omitempty"` // version to install
+	Commit     string `json:"commit,omitempty"`  // commit to install
+	Reason     string `json:"reason"`
+	Detail     string `json:"detail,omitempty"`
+}
+
+// PlanOptions represents options for computing a plan
+type PlanOptions struct {
+	ProjectPath string
+	Profile     string // Also plan the commands of this ccmd.yaml profile
+	Refresh     bool   // List remote tags again instead of using the tag cache
+}
+
+// Empty reports whether the plan changes nothing
+func (p *Plan) Empty() bool {
+	return len(p.Actions) == 0
+}
+
+// Count returns the number of actions of a kind
+func (p *Plan) Count(action string) int {
+	n := 0
+	for _, a := range p.Actions {
+		if a.Action == action {
+			n++
+		}
+	}
+	return n
+}
+
+// Summary describes the action on one line, such as "acme/tool@^1.2.0 → v1.2.3 (3f2a9c1)"
+func (a PlanAction) Summary() string {
+	subject := a.Spec
+	if a.Action != PlanInstall {
+		subject = a.Name
+		if a.From != "" {
+			subject += " " + a.From
+		}
+	}
+	if a.Action == PlanRemove || a.Version == "" {
+		return subject
+	}
+	summary := subject + " → " + a.Version
+	if a.Commit != "" && !strings.HasPrefix(a.Version, a.Commit) {
+		summary += fmt.Sprintf(" (%.7s)", a.Commit)
+	}
+	return summary
+}
+
+// Change describes the versions of the action as "current → target", such as
+// "v1.1.0 → v2.0.0 (3f2a9c1)". A missing side is shown as "-".
+func (a PlanAction) Change() string {
+	current, target := a.From, a.Version
+	switch a.Action {
+	case PlanInstall:
+		current = ""
+	case PlanRemove:
+		target = ""
+	case PlanKeep:
+		return orDash(current)
+	}
+	if a.Action != PlanRemove && target == "" {
+		// Archives, markdown files and branches without a version
+		_, target = ParseRepositorySpec(a.Spec)
+	}
+	change := orDash(current) + " → " + orDash(target)
+	if a.Commit != "" && !strings.HasPrefix(target, a.Commit) {
+		change += fmt.Sprintf(" (%.7s)", a.Commit)
+	}
+	return change
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// KeptCommands lists the locked commands a sync of the plan leaves alone, sorted by
+// name. Commands installed with --no-save are kept with the reason PlanEphemeral.
+func KeptCommands(projectPath string, plan *Plan) ([]PlanAction, error) {
+	projectRoot, err := findProjectRootFrom(projectPath)
+	if err != nil {
+		return nil, errors.FileError("find project root", projectPath, err)
+	}
+	kept := []PlanAction{}
+	lockPath := lockFilePath(projectRoot)
+	if !fileExists(lockPath) {
+		return kept, nil
+	}
+	lockFile, err := ReadLockFile(lockPath)
+	if err != nil {
+		return nil, err
+	}
+
+	planned := make(map[string]bool)
+	for _, action := range plan.Actions {
+		planned[action.Name] = true
+	}
+	for name, locked := range lockFile.Commands {
+		if planned[name] {
+			continue
+		}
+		action := PlanAction{
+			Action:     PlanKeep,
+			Name:       name,
+			Repository: locked.Source,
+			From:       lockedRef(locked),
+			Version:    lockedRef(locked),
+			Commit:     locked.Commit,
+			Reason:     PlanInSync,
+			Detail:     "installed at a version " + ConfigFileName + " accepts",
+		}
+		switch {
+		case locked.Ephemeral:
+			action.Reason = PlanEphemeral
+			action.Detail = "installed with --no-save, not listed in " + ConfigFileName
+		case locked.Local():
+			action.Reason = PlanLocalOnly
+			action.Detail = "lives in the project, not listed in " + ConfigFileName
+		}
+		kept = append(kept, action)
+	}
+	sort.Slice(kept, func(i, j int) bool { return kept[i].Name < kept[j].Name })
+	return kept, nil
+}
+
+// ComputePlan works out what a sync would install, update and remove, and resolves the
+// version and commit of every install and update
+func ComputePlan(ctx context.Context, opts PlanOptions) (*Plan, error) {
+	plan, err := planActions(opts.ProjectPath, opts.Profile)
+	if err != nil {
+		return nil, err
+	}
+	projectRoot, err := findProjectRootFrom(opts.ProjectPath)
+	if err != nil {
+		return nil, errors.FileError("find project root", opts.ProjectPath, err)
+	}
+
+	var pending []ConfigCommand
+	for _, action := range plan.Actions {
+		if action.Action != PlanRemove {
+			repo, version, _ := ParseInstanceSpec(action.Spec)
+			pending = append(pending, ConfigCommand{Repo: repo, Version: version})
+		}
+	}
+	ctx = withProjectRoot(ctx, projectRoot)
+	tags := newTagCache(projectRoot, opts.Refresh)
+	tags.prefetch(ctx, tagRepositories(projectRoot, pending), configuredJobs(projectRoot))
+
+	client := newGitClient(projectRoot)
+	for i := range plan.Actions {
+		action := &plan.Actions[i]
+		if action.Action == PlanRemove {
+			continue
+		}
+		if err := resolvePlanAction(ctx, projectRoot, client, tags, action); err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			return nil, fmt.Errorf("resolve %s: %w", action.Spec, err)
+		}
+	}
+
+	plan.CreatedAt = time.Now().UTC()
+	return plan, nil
+}
+
+// planActions lists the actions of a plan without resolving versions
+func planActions(projectPath, profile string) (*Plan, error) {
+	analysis, err := AnalyzeSync(projectPath, profile)
+	if err != nil {
+		return nil, err
+	}
+	config, err := LoadProjectConfig(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	projectRoot, err := findProjectRootFrom(projectPath)
+	if err != nil {
+		return nil, errors.FileError("find project root", projectPath, err)
+	}
+	digest, err := configDigest(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+	lockFile, _ := ReadLockFile(lockFilePath(projectRoot))
+
+	plan := &Plan{FormatVersion: PlanFormatVersion, Profile: profile, ConfigDigest: digest, Actions: []PlanAction{}}
+
+	pending := make(map[string]bool)
+	for _, cmd := range analysis.ToInstall {
+		spec := formatCommandSpec(cmd.Repo, cmd.Version, cmd.Name)
+		pending[specKey(spec)] = true
+
+		action := PlanAction{
+			Action:     PlanInstall,
+			Name:       cmd.Name,
+			Repository: cmd.Repo,
+			Spec:       spec,
+			Reason:     PlanNewEntry,
+			Detail:     "listed in " + ConfigFileName + " but not installed",
+		}
+		if name, locked := lockedCommand(lockFile, cmd.Repo, cmd.Name); locked != nil {
+			action.Name = name
+			action.Reason = PlanMissingFiles
+			action.Detail = "locked in " + LockFileName + " but its files are missing"
+		}
+		if action.Name == "" {
+			action.Name = extractCommandName(cmd.Repo)
+		}
+		plan.Actions = append(plan.Actions, action)
+	}
+
+	specs, err := config.CommandsForProfile(profile)
+	if err != nil {
+		return nil, err
+	}
+	for _, spec := range specs {
+		repo, version, instance := ParseInstanceSpec(spec)
+		if pending[specKey(spec)] || version == "" {
+			continue
+		}
+		name, locked := lockedCommand(lockFile, repo, instance)
+		if locked == nil || locked.Commit == "" || locked.Commit == "unknown" {
+			continue
+		}
+		if reason := lockedVersionDrift(version, locked.Resolved, locked.Commit); reason != "" {
+			plan.Actions = append(plan.Actions, PlanAction{
+				Action:     PlanUpdate,
+				Name:       name,
+				Repository: repo,
+				Spec:       spec,
+				From:       lockedRef(locked),
+				Reason:     PlanConstraintChange,
+				Detail:     reason,
+			})
+		}
+	}
+
+	for _, name := range analysis.ToRemove {
+		action := PlanAction{
+			Action: PlanRemove,
+			Name:   name,
+			Reason: PlanOrphan,
+			Detail: "no longer listed in " + ConfigFileName,
+		}
+		if lockFile != nil {
+			if locked := lockFile.Commands[name]; locked != nil {
+				action.Repository = locked.Source
+				action.From = lockedRef(locked)
+			}
+		}
+		plan.Actions = append(plan.Actions, action)
+	}
+
+	order := map[string]int{PlanInstall: 0, PlanUpdate: 1, PlanRemove: 2}
+	sort.SliceStable(plan.Actions, func(i, j int) bool {
+		a, b := plan.Actions[i], plan.Actions[j]
+		if a.Action != b.Action {
+			return order[a.Action] < order[b.Action]
+		}
+		return a.Name < b.Name
+	})
+	return plan, nil
+}
+
+// resolvePlanAction resolves the version an install would pick, as a sync would, and the
+// commit it points to. Archives and markdown files have neither.
+func resolvePlanAction(ctx context.Context, projectRoot string, client GitClient, tags *tagCache, action *PlanAction) error {
+	repo, version, _ := ParseInstanceSpec(action.Spec)
+	if isDownloadSource(repo) {
+		return nil
+	}
+
+	repoURL := normalizeRepositoryURL(projectRoot, repo)
+	opts := InstallOptions{Version: version, tags: tags}
+	if err := resolveInstallVersion(ctx, projectRoot, repoURL, &opts); err != nil {
+		return err
+	}
+
+	cloneURL, _ := splitRepositoryCommand(repoURL)
+	ref := opts.Version
+	if ref == "" {
+		ref = hostResolverFor(projectRoot).hostConfig(cloneURL).DefaultBranch
+	}
+	action.Version = opts.Version
+	if !isCommitHash(ref) {
+		// Tags and branches resolve with git ls-remote, without cloning the repository
+		var remote *RemoteVersion
+		var err error
+		if ref == "" && opts.defaultVersion == DefaultVersionDefaultBranch {
+			remote, err = resolveDefaultBranch(ctx, cloneURL)
+		} else {
+			remote, err = ResolveRemote(ctx, cloneURL, ref)
+		}
+		if err != nil {
+			return err
+		}
+		action.Commit = remote.Commit
+		return nil
+	}
+
+	// A cached copy must know the newest tags before resolving
+	if err := client.Fetch(ctx, cloneURL); err != nil {
+		return err
+	}
+	commit, err := client.ResolveRef(ctx, cloneURL, ref)
+	if err != nil {
+		return err
+	}
+	action.Commit = commit
+	return nil
+}
+
+// lockedRef returns the ref a lock entry was installed from, or its version
+func lockedRef(locked *LockCommand) string {
+	if _, ref := ParseRepositorySpec(locked.Resolved); ref != "" && !isDownloadSource(locked.Source) {
+		return ref
+	}
+	return locked.Version
+}
+
+// configDigest returns the sha256 of the project's ccmd.yaml, or "" without one
+func configDigest(projectRoot string) (string, error) {
+	path := configFilePath(projectRoot)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", errors.FileError("read", path, err)
+	}
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:]), nil
+}
+
+// planKey identifies an action when comparing a plan with the current project
+func planKey(action PlanAction) string {
+	if action.Action == PlanRemove {
+		return action.Action + " " + action.Name
+	}
+	return action.Action + " " + action.Spec + " " + action.From
+}
+
+// checkPlanCurrent returns a conflict error when ccmd.yaml, the installed commands or
+// ccmd-lock.yaml changed in a way that makes the plan differ from a new one
+func checkPlanCurrent(plan, current *Plan) error {
+	if plan.ConfigDigest != current.ConfigDigest {
+		return errors.Conflict(fmt.Sprintf("%s changed since the plan was made; run 'ccmd plan' again", ConfigFileName))
+	}
+
+	planned := make(map[string]bool)
+	for _, action := range plan.Actions {
+		planned[planKey(action)] = true
+	}
+	var lines []string
+	for _, action := range current.Actions {
+		if key := planKey(action); planned[key] {
+			delete(planned, key)
+		} else {
+			lines = append(lines, fmt.Sprintf("  %s %s: not in the plan", action.Action, action.Name))
+		}
+	}
+	for _, action := range plan.Actions {
+		if planned[planKey(action)] {
+			lines = append(lines, fmt.Sprintf("  %s %s: no longer needed", action.Action, action.Name))
+		}
+	}
+	if len(lines) > 0 {
+		return errors.Conflict(fmt.Sprintf("the project changed since the plan was made:\n%s\nrun 'ccmd plan' again",
+			strings.Join(lines, "\n")))
+	}
+	return nil
+}
+
+// applyPlan makes exactly the changes of a plan, after checking it still describes the
+// project. It backs Sync when SyncOptions.Plan is set.
+func applyPlan(ctx context.Context, opts SyncOptions) (*SyncResult, error) {
+	plan := opts.Plan
+	if plan.FormatVersion != PlanFormatVersion {
+		return nil, errors.InvalidInput(fmt.Sprintf("unsupported plan format version %d", plan.FormatVersion))
+	}
+	if opts.Frozen {
+		return nil, errors.InvalidInput("a plan cannot be applied with --frozen")
+	}
+	if opts.Profile != "" && opts.Profile != plan.Profile {
+		return nil, errors.InvalidInput(fmt.Sprintf("the plan was made for profile %q, not %q", plan.Profile, opts.Profile))
+	}
+
+	current, err := planActions(opts.ProjectPath, plan.Profile)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkPlanCurrent(plan, current); err != nil {
+		return nil, err
+	}
+	if opts.DryRun {
+		return &SyncResult{}, nil
+	}
+
+	projectRoot, err := findProjectRootFrom(opts.ProjectPath)
+	if err != nil {
+		return nil, errors.FileError("find project root", opts.ProjectPath, err)
+	}
+
+	result := &SyncResult{
+		Installed: []string{},
+		Updated:   []string{},
+		Removed:   []string{},
+		Failed:    []SyncError{},
+	}
+	for _, action := range plan.Actions {
+		if ctx.Err() != nil {
+			break
+		}
+
+		var err error
+		switch action.Action {
+		case PlanInstall:
+			err = applyPlanInstall(ctx, projectRoot, action, opts.Progress)
+		case PlanUpdate:
+			err = applyPlanUpdate(ctx, projectRoot, action, opts.Progress)
+		case PlanRemove:
+			err = Remove(RemoveOptions{Name: action.Name, Force: opts.Force, UpdateFiles: false, ProjectPath: projectRoot})
+		default:
+			err = errors.InvalidInput(fmt.Sprintf("unknown plan action %q", action.Action))
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		command := action.Name
+		if action.Action == PlanInstall {
+			command = action.Repository
+		}
+		switch {
+		case err != nil:
+			result.Failed = append(result.Failed, SyncError{Command: command, Operation: action.Action, Error: err})
+		case action.Action == PlanInstall:
+			result.Installed = append(result.Installed, command)
+		case action.Action == PlanUpdate:
+			result.Updated = append(result.Updated, command)
+		default:
+			result.Removed = append(result.Removed, command)
+		}
+	}
+
+	return finishSync(ctx, opts, result)
+}
+
+// applyPlanInstall installs the planned version and commit of a ccmd.yaml entry
+func applyPlanInstall(ctx context.Context, projectRoot string, action PlanAction, progress ProgressFunc) error {
+	repo, version, instance := ParseInstanceSpec(action.Spec)
+	opts := InstallOptions{
+		Repository:  normalizeRepositoryURL(projectRoot, repo),
+		Version:     action.Version,
+		Commit:      action.Commit,
+		As:          instance,
+		Progress:    progress,
+		ProjectPath: projectRoot,
+	}
+	if instance == "" {
+		lockFile, _ := ReadLockFile(lockFilePath(projectRoot))
+		opts.Rename = resolveNameFromLock(projectRoot, lockFile, repo)
+	}
+
+	// Record ccmd.yaml as a sync resolving the entry itself would
+	switch {
+	case IsConstraint(version):
+		opts.saveVersion = version
+	case version == "" && action.Version != "":
+		if _, err := ParseSemver(action.Version); err == nil {
+			opts.saveVersion = constraintFor(defaultSaveStrategy(projectRoot), action.Version)
+		}
+	}
+
+	_, _, err := Install(ctx, opts)
+	return err
+}
+
+// applyPlanUpdate reinstalls a command at the planned version and commit
+func applyPlanUpdate(ctx context.Context, projectRoot string, action PlanAction, progress ProgressFunc) error {
+	installed, err := List(ListOptions{ProjectPath: projectRoot})
+	if err != nil {
+		return err
+	}
+	for _, cmd := range installed {
+		if cmd.Name != action.Name {
+			continue
+		}
+		return applyUpdate(ctx, projectRoot, cmd, &UpdatePlan{
+			Name:           cmd.Name,
+			Repository:     cmd.Repository,
+			CurrentVersion: action.From,
+			TargetVersion:  action.Version,
+		}, UpdateOptions{commit: action.Commit, Progress: progress})
+	}
+	return errors.NotFound(fmt.Sprintf("command %q", action.Name))
+}
+
+// WritePlan saves a plan as JSON
+func WritePlan(path string, plan *Plan) error {
+	data, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return errors.FileError("write plan", path, err)
+	}
+	return nil
+}
+
+// ReadPlan loads a plan written by WritePlan
+func ReadPlan(path string) (*Plan, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.FileError("read plan", path, err)
+	}
+	var plan Plan
+	if err := json.Unmarshal(data, &plan); err != nil {
+		return nil, errors.InvalidInput(fmt.Sprintf("invalid plan %s: %v", path, err))
+	}
+	return &plan, nil
+}
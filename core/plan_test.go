@@ -0,0 +1,154 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+// writeCommandRepo creates a git repository of the command "tool" tagged v1.0.0, and a
+// function tagging a new release
+func writeCommandRepo(t *testing.T) (string, func(version string) string) {
+	repo, release := writeGitRepo(t)
+	metadata := "name: tool\nversion: 1.0.0\ndescription: A tool\nauthor: acme\nrepository: https://github.com/acme/tool\nentry: index.md\n"
+	require.NoError(t, os.WriteFile(filepath.Join(repo, ConfigFileName), []byte(metadata), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "index.md"), []byte("# Tool\n"), 0644))
+	for _, args := range [][]string{{"add", "."}, {"commit", "--quiet", "-m", "command"}, {"tag", "--force", "v1.0.0"}} {
+		out, err := exec.Command("git", append([]string{"-C", repo}, args...)...).CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	return "file://" + repo, func(version string) string {
+		release(version)
+		commit, err := gitGetCurrentCommit(repo)
+		require.NoError(t, err)
+		return commit
+	}
+}
+
+func TestPlanAndApply(t *testing.T) {
+	repo, release := writeCommandRepo(t)
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	planned := release("v1.1.0")
+	writeConfig(t, []string{repo + "@^1.0.0"})
+
+	plan, err := ComputePlan(ctx, PlanOptions{ProjectPath: ".", Refresh: true})
+	require.NoError(t, err)
+	require.Len(t, plan.Actions, 1)
+	install := plan.Actions[0]
+	assert.Equal(t, PlanInstall, install.Action)
+	assert.Equal(t, PlanNewEntry, install.Reason)
+	assert.Equal(t, "v1.1.0", install.Version)
+	assert.Equal(t, planned, install.Commit)
+
+	require.NoError(t, WritePlan("plan.json", plan))
+	plan, err = ReadPlan("plan.json")
+	require.NoError(t, err)
+
+	// The plan is applied as written, even after a newer matching release
+	release("v1.2.0")
+	result, err := Sync(ctx, SyncOptions{ProjectPath: ".", Plan: plan})
+	require.NoError(t, err)
+	assert.Equal(t, []string{repo}, result.Installed)
+	locked := readLockFile(t).Commands["tool"]
+	require.NotNil(t, locked)
+	assert.Equal(t, planned, locked.Commit)
+
+	empty, err := ComputePlan(ctx, PlanOptions{ProjectPath: ".", Refresh: true})
+	require.NoError(t, err)
+	assert.True(t, empty.Empty())
+
+	// A stale plan is refused
+	_, err = Sync(ctx, SyncOptions{ProjectPath: ".", Plan: plan})
+	assert.ErrorIs(t, err, errors.ErrConflict)
+
+	// A constraint the locked version no longer satisfies plans an update
+	updated := release("v2.0.0")
+	writeConfig(t, []string{repo + "@^2.0.0"})
+	plan, err = ComputePlan(ctx, PlanOptions{ProjectPath: ".", Refresh: true})
+	require.NoError(t, err)
+	require.Len(t, plan.Actions, 1)
+	assert.Equal(t, PlanUpdate, plan.Actions[0].Action)
+	assert.Equal(t, PlanConstraintChange, plan.Actions[0].Reason)
+	assert.Equal(t, "tool v1.1.0 → v2.0.0 ("+updated[:7]+")", plan.Actions[0].Summary())
+
+	result, err = Sync(ctx, SyncOptions{ProjectPath: ".", Plan: plan})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tool"}, result.Updated)
+	assert.Equal(t, updated, readLockFile(t).Commands["tool"].Commit)
+
+	// Entries dropped from ccmd.yaml are removed
+	writeConfig(t, []string{})
+	plan, err = ComputePlan(ctx, PlanOptions{ProjectPath: ".", Refresh: true})
+	require.NoError(t, err)
+	require.Len(t, plan.Actions, 1)
+	assert.Equal(t, PlanRemove, plan.Actions[0].Action)
+	assert.Equal(t, PlanOrphan, plan.Actions[0].Reason)
+	assert.Equal(t, "v2.0.0", plan.Actions[0].From)
+}
+
+func TestKeptCommands(t *testing.T) {
+	repo, release := writeCommandRepo(t)
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	writeConfig(t, []string{})
+	plan, err := ComputePlan(ctx, PlanOptions{ProjectPath: "."})
+	require.NoError(t, err)
+	kept, err := KeptCommands(".", plan)
+	require.NoError(t, err)
+	assert.Empty(t, kept, "nothing is installed without a lock file")
+
+	writeConfig(t, []string{repo + "@^1.0.0"})
+	_, err = Sync(ctx, SyncOptions{ProjectPath: "."})
+	require.NoError(t, err)
+
+	plan, err = ComputePlan(ctx, PlanOptions{ProjectPath: "."})
+	require.NoError(t, err)
+	kept, err = KeptCommands(".", plan)
+	require.NoError(t, err)
+	require.Len(t, kept, 1)
+	assert.Equal(t, PlanKeep, kept[0].Action)
+	assert.Equal(t, PlanInSync, kept[0].Reason)
+	assert.Equal(t, "v1.0.0", kept[0].Change())
+
+	// Commands the plan changes are not kept
+	updated := release("v2.0.0")
+	writeConfig(t, []string{repo + "@^2.0.0"})
+	plan, err = ComputePlan(ctx, PlanOptions{ProjectPath: ".", Refresh: true})
+	require.NoError(t, err)
+	kept, err = KeptCommands(".", plan)
+	require.NoError(t, err)
+	assert.Empty(t, kept)
+	assert.Equal(t, "v1.0.0 → v2.0.0 ("+updated[:7]+")", plan.Actions[0].Change())
+}
+
+func TestPlanActionChange(t *testing.T) {
+	install := PlanAction{Action: PlanInstall, Spec: "acme/tool@^1.0.0", Version: "v1.2.0", Commit: "3f2a9c1d"}
+	assert.Equal(t, "- → v1.2.0 (3f2a9c1)", install.Change())
+
+	archive := PlanAction{Action: PlanInstall, Spec: "https://example.com/tool.tar.gz"}
+	assert.Equal(t, "- → -", archive.Change())
+
+	remove := PlanAction{Action: PlanRemove, From: "v2.0.0"}
+	assert.Equal(t, "v2.0.0 → -", remove.Change())
+}
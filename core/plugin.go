@@ -10,6 +10,7 @@
 package core
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -18,8 +19,10 @@ import (
 	"strings"
 	"time"
 
+	"github.com/gifflet/ccmd/internal/fs"
 	"github.com/gifflet/ccmd/pkg/errors"
 	"github.com/gifflet/ccmd/pkg/output"
+	"github.com/gifflet/ccmd/pkg/telemetry"
 )
 
 func repoType(cfg *ProjectConfig) string {
@@ -31,7 +34,7 @@ func repoType(cfg *ProjectConfig) string {
 
 // installPlugin copies a cloned plugin repo into .claude/plugins/{name} and
 // registers it in .claude/settings.json and ccmd-lock.yaml.
-func installPlugin(projectRoot, tempDir string, cfg *ProjectConfig, opts InstallOptions) (string, error) {
+func installPlugin(ctx context.Context, projectRoot, tempDir string, cfg *ProjectConfig, opts InstallOptions) (string, error) {
 	name := opts.Name
 	if name == "" {
 		name = cfg.Name
@@ -43,6 +46,7 @@ func installPlugin(projectRoot, tempDir string, cfg *ProjectConfig, opts Install
 	if err := validateCommandName(name); err != nil {
 		return "", err
 	}
+	opts.progress.name = name
 
 	pluginsDir := filepath.Join(projectRoot, ".claude", "plugins")
 	if err := os.MkdirAll(pluginsDir, 0o750); err != nil {
@@ -62,24 +66,51 @@ func installPlugin(projectRoot, tempDir string, cfg *ProjectConfig, opts Install
 	}
 
 	if opts.Force && existingPlugin != "" {
-		output.PrintInfof("Removing previous installation %q...", existingPlugin)
-		if err := removePlugin(projectRoot, existingPlugin); err != nil {
+		opts.progress.report(StageCopying, "Removing previous installation %q...", existingPlugin)
+		remove := removePlugin
+		if opts.frozen {
+			// The lock entry is kept; the plugin is installed again as recorded
+			remove = removePluginFiles
+		}
+		if err := remove(projectRoot, existingPlugin); err != nil {
 			return "", err
 		}
 	}
 
 	destDir := filepath.Join(pluginsDir, name)
-	output.PrintInfof("Installing plugin %q...", name)
-	if err := copyDirectory(tempDir, destDir); err != nil {
+	opts.progress.report(StageCopying, "Installing plugin %q...", name)
+	copyCtx, span := telemetry.Start(ctx, "copy")
+	err = copyDirectory(copyCtx, tempDir, destDir)
+	span.End(err)
+	if err != nil {
+		if removeErr := fs.RemoveAll(destDir); removeErr != nil {
+			output.PrintWarningf("Failed to cleanup plugin directory: %v", removeErr)
+		}
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
 		return "", errors.FileError("copy plugin files", destDir, err)
 	}
+	if telemetry.Enabled() {
+		if size, err := dirSize(destDir); err == nil {
+			telemetry.Add(copyCtx, telemetry.MetricBytes, "By", size)
+		}
+	}
 
 	originalVersion := cfg.Version
 	cfg.Name = name
 	cfg.Repository = opts.Repository
 
 	if err := writeCommandMetadata(filepath.Join(destDir, "ccmd.yaml"), cfg); err != nil {
-		if removeErr := os.RemoveAll(destDir); removeErr != nil {
+		if removeErr := fs.RemoveAll(destDir); removeErr != nil {
+			output.PrintWarningf("Failed to cleanup plugin directory: %v", removeErr)
+		}
+		return "", err
+	}
+
+	// Last point to back out before settings.json and the lock file are written
+	if err := ctx.Err(); err != nil {
+		if removeErr := fs.RemoveAll(destDir); removeErr != nil {
 			output.PrintWarningf("Failed to cleanup plugin directory: %v", removeErr)
 		}
 		return "", err
@@ -89,43 +120,68 @@ func installPlugin(projectRoot, tempDir string, cfg *ProjectConfig, opts Install
 		output.PrintWarningf("Failed to register plugin in settings.json: %v", err)
 	}
 
-	if err := updatePluginLockFile(projectRoot, name, cfg, originalVersion, opts.Version); err != nil {
-		output.PrintWarningf("Failed to update lock file: %v", err)
+	if opts.frozen {
+		pluginInstalled(opts.progress, destDir, "Plugin %q installed successfully", name)
+		return name, nil
 	}
 
-	repoSpec := opts.Repository
-	if strings.Contains(repoSpec, "://") || strings.HasPrefix(repoSpec, "git@") {
-		repoSpec = ExtractRepoPath(repoSpec)
+	lockErr := updatePluginLockFile(projectRoot, name, cfg, originalVersion, opts.Version)
+	if lockErr != nil {
+		output.PrintWarningf("Failed to update lock file: %v", lockErr)
+	} else if opts.archiveDigest != "" {
+		if err := recordLockCommit(projectRoot, name, true, opts.archiveDigest); err != nil {
+			output.PrintWarningf("Failed to record archive checksum: %v", err)
+		}
+	} else if opts.checkedOut != "" {
+		if err := recordLockCommit(projectRoot, name, true, opts.checkedOut); err != nil {
+			output.PrintWarningf("Failed to record commit: %v", err)
+		}
 	}
-	versionForConfig := opts.Version
-	if isCommitHash(versionForConfig) && len(versionForConfig) > 7 {
-		versionForConfig = versionForConfig[:7]
+	ephemeral := opts.NoSave && !listedInConfig(projectRoot, opts.Repository, "")
+	if err := recordEphemeral(projectRoot, name, true, ephemeral); err != nil {
+		output.PrintWarningf("Failed to record ephemeral install: %v", err)
 	}
-	if err := addPluginToConfig(projectRoot, name, repoSpec, versionForConfig); err != nil {
+	if lockErr == nil {
+		opts.progress.report(StageLockUpdated, "Recorded %q in %s", name, LockFileName)
+	}
+
+	if opts.NoSave {
+		if ephemeral {
+			pluginInstalled(opts.progress, destDir, "Plugin %q installed without saving it to %s", name, ConfigFileName)
+		} else {
+			pluginInstalled(opts.progress, destDir, "Plugin %q installed successfully", name)
+		}
+		return name, nil
+	}
+
+	repoSpec := opts.Repository
+	if !opts.Archive && !IsArchiveSource(repoSpec) {
+		repoSpec = configSource(projectRoot, repoSpec)
+	}
+	if err := addPluginToConfig(projectRoot, name, repoSpec, configVersion(opts)); err != nil {
 		output.PrintWarningf("Failed to update ccmd.yaml: %v", err)
 	}
 
-	output.PrintSuccessf("Plugin %q installed successfully", name)
-	printPluginComponents(scanPluginComponents(destDir))
+	pluginInstalled(opts.progress, destDir, "Plugin %q installed successfully", name)
 	return name, nil
 }
 
-// removePlugin deletes a plugin installation and removes it from settings and lock file.
-func removePlugin(projectRoot, name string) error {
-	pluginDir := filepath.Join(projectRoot, ".claude", "plugins", name)
-
-	if dirExists(pluginDir) {
-		output.PrintInfof("Removing plugin directory...")
-		if err := os.RemoveAll(pluginDir); err != nil {
-			return errors.FileError("remove plugin directory", pluginDir, err)
-		}
+// pluginInstalled reports a completed plugin install. Without a ProgressFunc the
+// components of the plugin are listed after the message.
+func pluginInstalled(progress *progressReporter, destDir, format string, args ...interface{}) {
+	progress.report(StageDone, format, args...)
+	if !progress.enabled() {
+		printPluginComponents(scanPluginComponents(destDir))
 	}
+}
 
-	if err := disablePlugin(projectRoot, name); err != nil {
-		output.PrintWarningf("Failed to remove plugin from settings.json: %v", err)
+// removePlugin deletes a plugin installation and removes it from settings and lock file.
+func removePlugin(projectRoot, name string) error {
+	if err := removePluginFiles(projectRoot, name); err != nil {
+		return err
 	}
 
-	lockPath := filepath.Join(projectRoot, LockFileName)
+	lockPath := lockFilePath(projectRoot)
 	if fileExists(lockPath) {
 		lockFile, err := ReadLockFile(lockPath)
 		if err == nil {
@@ -139,6 +195,24 @@ func removePlugin(projectRoot, name string) error {
 	return nil
 }
 
+// removePluginFiles deletes a plugin directory and unregisters it from settings.json
+func removePluginFiles(projectRoot, name string) error {
+	pluginDir := filepath.Join(projectRoot, ".claude", "plugins", name)
+
+	if dirExists(pluginDir) {
+		output.PrintVerbosef("Removing plugin directory...")
+		if err := fs.RemoveAll(pluginDir); err != nil {
+			return errors.FileError("remove plugin directory", pluginDir, err)
+		}
+	}
+
+	if err := disablePlugin(projectRoot, name); err != nil {
+		output.PrintWarningf("Failed to remove plugin from settings.json: %v", err)
+	}
+
+	return nil
+}
+
 func findExistingPluginByRepo(projectRoot, targetRepoPath string) (string, error) {
 	pluginsDir := filepath.Join(projectRoot, ".claude", "plugins")
 	entries, err := os.ReadDir(pluginsDir)
@@ -201,7 +275,7 @@ func addPluginToConfig(projectRoot, _, repository, version string) error {
 }
 
 func removePluginFromConfig(projectRoot, name, repository string) error {
-	configPath := filepath.Join(projectRoot, ConfigFileName)
+	configPath := configFilePath(projectRoot)
 	if !fileExists(configPath) {
 		return nil
 	}
@@ -230,7 +304,7 @@ func updatePluginLockFile(
 	cfg *ProjectConfig,
 	originalVersion, requestedVersion string,
 ) error {
-	lockPath := filepath.Join(projectRoot, LockFileName)
+	lockPath := lockFilePath(projectRoot)
 	now := time.Now()
 
 	var lockFile *LockFile
@@ -254,6 +328,7 @@ func updatePluginLockFile(
 	if hash, err := gitGetCurrentCommit(pluginPath); err == nil {
 		commitHash = hash
 	}
+	size, _ := dirSize(pluginPath)
 
 	resolved := cfg.Repository
 	if requestedVersion != "" {
@@ -293,11 +368,17 @@ func updatePluginLockFile(
 		Source:      cfg.Repository,
 		Resolved:    resolved,
 		Commit:      commitHash,
+		FileSize:    size,
+		License:     cfg.License,
 		InstalledAt: installedAt,
 		UpdatedAt:   now,
 	}
 
-	return WriteLockFile(lockPath, lockFile)
+	if err := WriteLockFile(lockPath, lockFile); err != nil {
+		return err
+	}
+	recordInstallStats(projectRoot, "plugin", name, existingKey)
+	return nil
 }
 
 type ccmdMarketplace struct {
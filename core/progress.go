@@ -0,0 +1,162 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"sync"
+
+	"github.com/gifflet/ccmd/pkg/output"
+)
+
+// ProgressStage identifies a step of an install reported to a ProgressFunc
+type ProgressStage string
+
+// Install stages, in the order an install goes through them
+const (
+	StageCloneStarted  ProgressStage = "clone_started"  // cloning or downloading the source
+	StageCloneProgress ProgressStage = "clone_progress" // git transfer progress, with Percent
+	StageValidating    ProgressStage = "validating"     // reading and checking the source metadata
+	StageCopying       ProgressStage = "copying"        // replacing the installed files
+	StageLockUpdated   ProgressStage = "lock_updated"   // ccmd-lock.yaml records the install
+	StageDone          ProgressStage = "done"           // the install completed
+	StageFailed        ProgressStage = "failed"         // the install failed, with Err
+)
+
+// ProgressEvent is a step of an install. Name is empty until the source metadata says
+// what the command or plugin is called.
+type ProgressEvent struct {
+	Stage      ProgressStage
+	Name       string
+	Repository string
+	Message    string
+	Percent    int // 0-100 for StageCloneProgress, -1 otherwise
+	Err        error
+}
+
+// ProgressFunc receives the progress of installs. Calls are serialized across the
+// process, so one function may serve installs running in parallel.
+type ProgressFunc func(ProgressEvent)
+
+// progressMu serializes the calls to every ProgressFunc
+var progressMu sync.Mutex
+
+// progressReporter sends the steps of one install to its ProgressFunc, or prints them
+// as the CLI does when there is none. A nil reporter prints.
+type progressReporter struct {
+	fn         ProgressFunc
+	repository string
+	name       string
+}
+
+func newProgressReporter(fn ProgressFunc, repository string) *progressReporter {
+	return &progressReporter{fn: fn, repository: repository}
+}
+
+// report sends a step with a formatted message
+func (p *progressReporter) report(stage ProgressStage, format string, args ...interface{}) {
+	p.send(ProgressEvent{Stage: stage, Message: fmt.Sprintf(format, args...), Percent: -1})
+}
+
+// cloneProgress reports the transfer progress of git clone
+func (p *progressReporter) cloneProgress(phase string, percent int) {
+	p.send(ProgressEvent{Stage: StageCloneProgress, Message: fmt.Sprintf("%s: %d%%", phase, percent), Percent: percent})
+}
+
+// fail reports a failed install
+func (p *progressReporter) fail(err error) {
+	p.send(ProgressEvent{Stage: StageFailed, Message: err.Error(), Percent: -1, Err: err})
+}
+
+// enabled reports whether the steps go to a ProgressFunc instead of the output
+func (p *progressReporter) enabled() bool {
+	return p != nil && p.fn != nil
+}
+
+func (p *progressReporter) send(event ProgressEvent) {
+	if !p.enabled() {
+		switch event.Stage {
+		case StageDone:
+			output.PrintSuccessf("%s", event.Message)
+		case StageValidating, StageLockUpdated, StageCloneProgress:
+			output.PrintVerbosef("%s", event.Message)
+		case StageFailed:
+			// Callers print the returned error
+		default:
+			output.PrintInfof("%s", event.Message)
+		}
+		return
+	}
+
+	event.Name = p.name
+	event.Repository = p.repository
+	progressMu.Lock()
+	defer progressMu.Unlock()
+	p.fn(event)
+}
+
+// cloneProgressKey is the context key of the function receiving git clone progress
+type cloneProgressKey struct{}
+
+// withCloneProgress asks the clones run with ctx to report their transfer progress to fn
+func withCloneProgress(ctx context.Context, fn func(phase string, percent int)) context.Context {
+	return context.WithValue(ctx, cloneProgressKey{}, fn)
+}
+
+// cloneProgressFrom returns the function set by withCloneProgress, or nil
+func cloneProgressFrom(ctx context.Context) func(phase string, percent int) {
+	fn, _ := ctx.Value(cloneProgressKey{}).(func(phase string, percent int))
+	return fn
+}
+
+// gitProgressPattern matches the progress lines of git clone --progress, such as
+// "Receiving objects:  45% (9/20)"
+var gitProgressPattern = regexp.MustCompile(`^(?:remote: )?([A-Za-z ]+):\s+(\d{1,3})%`)
+
+// gitProgressWriter collects the stderr of git clone --progress and reports each change
+// of phase or percentage. Git redraws progress lines with a carriage return.
+type gitProgressWriter struct {
+	fn      func(phase string, percent int)
+	output  bytes.Buffer // everything written, for error messages
+	line    []byte
+	phase   string
+	percent int
+}
+
+func (w *gitProgressWriter) Write(p []byte) (int, error) {
+	w.output.Write(p)
+	for _, b := range p {
+		if b != '\r' && b != '\n' {
+			w.line = append(w.line, b)
+			continue
+		}
+		w.parse()
+		w.line = w.line[:0]
+	}
+	return len(p), nil
+}
+
+func (w *gitProgressWriter) parse() {
+	match := gitProgressPattern.FindSubmatch(w.line)
+	if match == nil {
+		return
+	}
+	phase := string(match[1])
+	percent, err := strconv.Atoi(string(match[2]))
+	if err != nil || percent > 100 || (phase == w.phase && percent == w.percent) {
+		return
+	}
+	w.phase, w.percent = phase, percent
+	w.fn(phase, percent)
+}
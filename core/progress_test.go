@@ -0,0 +1,77 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gifflet/ccmd/pkg/config"
+)
+
+func TestInstallProgress(t *testing.T) {
+	repo, _ := writeCommandRepo(t)
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	ctx := context.Background()
+	t.Setenv(config.ConfigEnv, filepath.Join(t.TempDir(), "config.yaml"))
+	writeConfig(t, []string{})
+
+	var events []ProgressEvent
+	collect := func(event ProgressEvent) { events = append(events, event) }
+
+	_, _, err := Install(ctx, InstallOptions{Repository: repo + "@v1.0.0", Progress: collect})
+	require.NoError(t, err)
+
+	var stages []ProgressStage
+	for _, event := range events {
+		if event.Stage == StageCloneProgress {
+			assert.True(t, event.Percent >= 0 && event.Percent <= 100, event.Message)
+			continue
+		}
+		assert.Equal(t, -1, event.Percent)
+		stages = append(stages, event.Stage)
+	}
+	assert.Equal(t, []ProgressStage{StageCloneStarted, StageValidating, StageCopying, StageLockUpdated, StageDone}, stages)
+	last := events[len(events)-1]
+	assert.Equal(t, "tool", last.Name)
+	assert.Equal(t, repo, last.Repository)
+
+	events = nil
+	_, _, err = Install(ctx, InstallOptions{Repository: repo + "@v1.0.0", Progress: collect})
+	require.Error(t, err)
+	last = events[len(events)-1]
+	assert.Equal(t, StageFailed, last.Stage)
+	assert.Equal(t, err, last.Err)
+}
+
+func TestGitProgressWriter(t *testing.T) {
+	type step struct {
+		phase   string
+		percent int
+	}
+	var steps []step
+	writer := &gitProgressWriter{fn: func(phase string, percent int) {
+		steps = append(steps, step{phase, percent})
+	}}
+
+	_, err := writer.Write([]byte("Cloning into 'dest'...\nremote: Counting objects: 100% (3/3), done.\n" +
+		"Receiving objects:  50% (1/2)\rReceiving obj"))
+	require.NoError(t, err)
+	_, err = writer.Write([]byte("ects:  50% (1/2)\rReceiving objects: 100% (2/2), done.\n"))
+	require.NoError(t, err)
+
+	assert.Equal(t, []step{{"Counting objects", 100}, {"Receiving objects", 50}, {"Receiving objects", 100}}, steps)
+	assert.Contains(t, writer.output.String(), "Cloning into")
+}
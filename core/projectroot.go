@@ -0,0 +1,157 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+// ProjectDirEnv sets the project directory of the CLI, as the --project flag does
+const ProjectDirEnv = "CCMD_PROJECT_DIR"
+
+// RootSource tells how the project root was chosen
+type RootSource string
+
+// Ways a project root is chosen, in the order they are tried
+const (
+	RootExplicit RootSource = "explicit" // --project or CCMD_PROJECT_DIR
+	RootConfig   RootSource = "config"   // the nearest directory holding ccmd.yaml
+	RootGit      RootSource = "git"      // no ccmd.yaml up to the root of the git worktree
+	RootWorkDir  RootSource = "workdir"  // no ccmd.yaml and no git worktree
+)
+
+// ProjectRoot is the directory an operation treats as the project root
+type ProjectRoot struct {
+	Dir    string
+	Source RootSource
+}
+
+// Describe says which root was chosen and why
+func (r ProjectRoot) Describe() string {
+	switch r.Source {
+	case RootExplicit:
+		return fmt.Sprintf("%s (set with --project or %s)", r.Dir, ProjectDirEnv)
+	case RootConfig:
+		return fmt.Sprintf("%s (holds %s)", r.Dir, ConfigFileName)
+	case RootGit:
+		return fmt.Sprintf("%s (root of the git worktree; no %s found)", r.Dir, ConfigFileName)
+	default:
+		return fmt.Sprintf("%s (working directory; no %s or git worktree found)", r.Dir, ConfigFileName)
+	}
+}
+
+var (
+	projectDirMu sync.RWMutex
+	projectDir   string
+)
+
+// SetProjectDir makes dir the project root of every operation instead of the directory
+// found from the working directory. An empty dir restores the search.
+func SetProjectDir(dir string) error {
+	if dir != "" {
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			return errors.FileError("resolve project directory", dir, err)
+		}
+		if !dirExists(abs) {
+			return errors.NotFound(fmt.Sprintf("project directory %s", dir))
+		}
+		dir = abs
+	}
+
+	projectDirMu.Lock()
+	defer projectDirMu.Unlock()
+	projectDir = dir
+	return nil
+}
+
+// ProjectDir returns the directory set with SetProjectDir, or ""
+func ProjectDir() string {
+	projectDirMu.RLock()
+	defer projectDirMu.RUnlock()
+	return projectDir
+}
+
+// LocateProjectRoot returns the project root for startPath: the directory set with
+// SetProjectDir, else the nearest directory holding ccmd.yaml. The search stops at the
+// root of the git worktree, so a repository nested in another project is not mistaken
+// for it, and that root is used when it holds no ccmd.yaml. Outside a git worktree
+// startPath itself is used.
+func LocateProjectRoot(startPath string) (ProjectRoot, error) {
+	if dir := ProjectDir(); dir != "" {
+		return ProjectRoot{Dir: dir, Source: RootExplicit}, nil
+	}
+
+	dir := startPath
+	for {
+		paths, err := ResolvePaths(dir)
+		if err != nil {
+			return ProjectRoot{}, err
+		}
+		if fileExists(paths.Config) {
+			return ProjectRoot{Dir: dir, Source: RootConfig}, nil
+		}
+		// A .git directory, or the .git file of a linked worktree or submodule
+		if _, err := os.Lstat(filepath.Join(dir, ".git")); err == nil {
+			return ProjectRoot{Dir: dir, Source: RootGit}, nil
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ProjectRoot{Dir: startPath, Source: RootWorkDir}, nil
+		}
+		dir = parent
+	}
+}
+
+// FindProjectRoot returns the project root for the working directory, as
+// LocateProjectRoot finds it
+func FindProjectRoot() (string, error) {
+	return findProjectRoot()
+}
+
+func findProjectRoot() (string, error) {
+	return findProjectRootFrom("")
+}
+
+// startDir returns the directory the search for a project root starts from: path, or the
+// working directory when it is empty
+func startDir(path string) (string, error) {
+	if path != "" {
+		return path, nil
+	}
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", errors.FileError("get working directory", "", err)
+	}
+	return dir, nil
+}
+
+// findProjectRootFrom returns the project root for startPath, the working directory when
+// empty, and resolves its paths for the operation, failing when they cannot be resolved
+func findProjectRootFrom(startPath string) (string, error) {
+	startPath, err := startDir(startPath)
+	if err != nil {
+		return "", err
+	}
+	root, err := LocateProjectRoot(startPath)
+	if err != nil {
+		return "", err
+	}
+	if _, err := resolveProjectPaths(root.Dir); err != nil {
+		return "", err
+	}
+	return root.Dir, nil
+}
@@ -0,0 +1,73 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gifflet/ccmd/pkg/config"
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+func TestLocateProjectRoot(t *testing.T) {
+	t.Setenv(config.ConfigEnv, filepath.Join(t.TempDir(), "config.yaml"))
+	outer := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(outer, ConfigFileName), []byte("commands: []\n"), 0644))
+	src := filepath.Join(outer, "src", "pkg")
+	require.NoError(t, os.MkdirAll(src, 0755))
+
+	root, err := LocateProjectRoot(src)
+	require.NoError(t, err)
+	assert.Equal(t, ProjectRoot{Dir: outer, Source: RootConfig}, root)
+
+	// A repository nested in the project is a project of its own
+	nested := filepath.Join(outer, "vendor", "tool")
+	require.NoError(t, os.MkdirAll(filepath.Join(nested, ".git"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(nested, "docs"), 0755))
+	root, err = LocateProjectRoot(filepath.Join(nested, "docs"))
+	require.NoError(t, err)
+	assert.Equal(t, ProjectRoot{Dir: nested, Source: RootGit}, root)
+	assert.Contains(t, root.Describe(), "root of the git worktree")
+
+	// Linked worktrees and submodules have a .git file
+	require.NoError(t, os.RemoveAll(filepath.Join(nested, ".git")))
+	require.NoError(t, os.WriteFile(filepath.Join(nested, ".git"), []byte("gitdir: ../../.git/worktrees/tool\n"), 0644))
+	root, err = LocateProjectRoot(nested)
+	require.NoError(t, err)
+	assert.Equal(t, RootGit, root.Source)
+
+	elsewhere := t.TempDir()
+	root, err = LocateProjectRoot(elsewhere)
+	require.NoError(t, err)
+	assert.Equal(t, ProjectRoot{Dir: elsewhere, Source: RootWorkDir}, root)
+
+	// An explicit directory wins over the search
+	require.NoError(t, SetProjectDir(elsewhere))
+	t.Cleanup(func() { _ = SetProjectDir("") })
+	root, err = LocateProjectRoot(src)
+	require.NoError(t, err)
+	assert.Equal(t, ProjectRoot{Dir: elsewhere, Source: RootExplicit}, root)
+	dir, err := findProjectRootFrom(".")
+	require.NoError(t, err)
+	assert.Equal(t, elsewhere, dir)
+
+	assert.ErrorIs(t, SetProjectDir(filepath.Join(elsewhere, "missing")), errors.ErrNotFound)
+	assert.Equal(t, elsewhere, ProjectDir(), "a failed call keeps the directory")
+
+	require.NoError(t, SetProjectDir(""))
+	root, err = LocateProjectRoot(src)
+	require.NoError(t, err)
+	assert.Equal(t, RootConfig, root.Source)
+}
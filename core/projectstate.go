@@ -0,0 +1,173 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+// racyWindow is how long after its last modification a file is compared by content
+// rather than by size and modification time. Filesystems with coarse timestamps can
+// record two quick writes with the same time, which only the content tells apart.
+const racyWindow = 2 * time.Second
+
+// projectFiles holds the parsed ccmd.yaml and ccmd-lock.yaml files of the process, so
+// every layer asking for them shares one read and one parse
+var projectFiles = &parsedFiles{entries: make(map[string]*parsedFile)}
+
+// parsedFiles caches parsed files by absolute path. An entry is reused while the file
+// keeps its size and modification time; recently modified files are read again and
+// only parsed when their content changed. Values are never handed out directly:
+// callers get deep copies they are free to modify.
+type parsedFiles struct {
+	mu      sync.Mutex
+	entries map[string]*parsedFile
+}
+
+// parsedFile is one cached file with the stat it was read at
+type parsedFile struct {
+	size    int64
+	modTime time.Time
+	readAt  time.Time
+	data    []byte
+	value   interface{}
+}
+
+// fresh reports whether the entry still describes a file with the given stat, without
+// reading it
+func (f *parsedFile) fresh(info os.FileInfo) bool {
+	return f.size == info.Size() && f.modTime.Equal(info.ModTime()) && f.readAt.Sub(info.ModTime()) > racyWindow
+}
+
+// load returns a copy of the parsed content of path, parsing it only when it changed
+// since the last load. Read errors are reported as op.
+func (c *parsedFiles) load(path, op string, parse func([]byte) (interface{}, error)) (interface{}, error) {
+	key := cacheKey(path)
+	info, err := os.Stat(path)
+	if err != nil {
+		c.forget(key)
+		return nil, errors.FileError(op, path, err)
+	}
+
+	c.mu.Lock()
+	entry := c.entries[key]
+	c.mu.Unlock()
+	if entry != nil && entry.fresh(info) {
+		return deepCopy(entry.value), nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.FileError(op, path, err)
+	}
+	if entry == nil || !bytes.Equal(entry.data, data) {
+		value, err := parse(data)
+		if err != nil {
+			c.forget(key)
+			return nil, err
+		}
+		entry = &parsedFile{data: data, value: value}
+	}
+
+	c.remember(key, entry, info)
+	return deepCopy(entry.value), nil
+}
+
+// invalidate drops the entry of a file ccmd just wrote. The next load parses what was
+// written rather than trusting the in-memory value the file was encoded from, which
+// encoding may normalize.
+func (c *parsedFiles) invalidate(path string) {
+	c.forget(cacheKey(path))
+}
+
+func (c *parsedFiles) remember(key string, entry *parsedFile, info os.FileInfo) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = &parsedFile{
+		size:    info.Size(),
+		modTime: info.ModTime(),
+		readAt:  time.Now(),
+		data:    entry.data,
+		value:   entry.value,
+	}
+}
+
+func (c *parsedFiles) forget(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, key)
+}
+
+// cacheKey returns the absolute form of path, so relative and absolute paths to the
+// same file share an entry
+func cacheKey(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
+
+// deepCopy returns a copy of v sharing no pointers, maps or slices with it. Unexported
+// fields are copied as they are.
+func deepCopy(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	return copyValue(reflect.ValueOf(v)).Interface()
+}
+
+func copyValue(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		dup := reflect.New(v.Type().Elem())
+		dup.Elem().Set(copyValue(v.Elem()))
+		return dup
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		dup := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			dup.SetMapIndex(iter.Key(), copyValue(iter.Value()))
+		}
+		return dup
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		dup := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			dup.Index(i).Set(copyValue(v.Index(i)))
+		}
+		return dup
+	case reflect.Struct:
+		dup := reflect.New(v.Type()).Elem()
+		dup.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if dup.Field(i).CanSet() {
+				dup.Field(i).Set(copyValue(v.Field(i)))
+			}
+		}
+		return dup
+	default:
+		return v
+	}
+}
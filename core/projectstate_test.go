@@ -0,0 +1,125 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsedFilesLoad(t *testing.T) {
+	cache := &parsedFiles{entries: make(map[string]*parsedFile)}
+	path := filepath.Join(t.TempDir(), "file.txt")
+	parses := 0
+	parse := func(data []byte) (interface{}, error) {
+		parses++
+		return &[]string{string(data)}, nil
+	}
+	load := func() string {
+		value, err := cache.load(path, "read file", parse)
+		require.NoError(t, err)
+		return (*value.(*[]string))[0]
+	}
+
+	// Files modified within the racy window are read again, but only parsed on changes
+	require.NoError(t, os.WriteFile(path, []byte("one"), 0644))
+	assert.Equal(t, "one", load())
+	assert.Equal(t, "one", load())
+	assert.Equal(t, 1, parses)
+	require.NoError(t, os.WriteFile(path, []byte("two"), 0644))
+	assert.Equal(t, "two", load())
+	assert.Equal(t, 2, parses)
+
+	// Older files are trusted by their stat
+	old := time.Now().Add(-time.Hour)
+	require.NoError(t, os.Chtimes(path, old, old))
+	assert.Equal(t, "two", load())
+	assert.Equal(t, "two", load())
+	assert.Equal(t, 2, parses)
+
+	// Callers get copies
+	value, err := cache.load(path, "read file", parse)
+	require.NoError(t, err)
+	(*value.(*[]string))[0] = "changed"
+	assert.Equal(t, "two", load())
+
+	// A changed stat is noticed, and so is a removed file
+	require.NoError(t, os.WriteFile(path, []byte("three"), 0644))
+	require.NoError(t, os.Chtimes(path, old, old))
+	assert.Equal(t, "three", load())
+	require.NoError(t, os.Remove(path))
+	_, err = cache.load(path, "read file", parse)
+	assert.Error(t, err)
+	assert.Empty(t, cache.entries)
+}
+
+func TestReadLockFileCopies(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+
+	lock := &LockFile{
+		Version:         "1.0",
+		LockfileVersion: 1,
+		Commands: map[string]*LockCommand{
+			"tool": {
+				Name:       "tool",
+				Files:      []string{"index.md"},
+				Standalone: map[string]*LockStandalone{".claude/commands/tool.md": {Checksum: "abc"}},
+			},
+		},
+	}
+	require.NoError(t, WriteLockFile(LockFileName, lock))
+
+	first, err := ReadLockFile(LockFileName)
+	require.NoError(t, err)
+	first.Commands["tool"].Files[0] = "changed.md"
+	first.Commands["tool"].Standalone[".claude/commands/tool.md"].Override = true
+	delete(first.Commands, "tool")
+
+	second, err := ReadLockFile(LockFileName)
+	require.NoError(t, err)
+	require.Contains(t, second.Commands, "tool")
+	assert.Equal(t, []string{"index.md"}, second.Commands["tool"].Files)
+	assert.False(t, second.Commands["tool"].Standalone[".claude/commands/tool.md"].Override)
+
+	// Writes are seen by the next read
+	second.Commands["tool"].Version = "v2.0.0"
+	require.NoError(t, WriteLockFile(LockFileName, second))
+	third, err := ReadLockFile(filepath.Join(".", LockFileName))
+	require.NoError(t, err)
+	assert.Equal(t, "v2.0.0", third.Commands["tool"].Version)
+}
+
+func TestLoadProjectConfigCopies(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	writeConfigMap(t, map[string]interface{}{
+		"commands": []interface{}{"acme/tool@v1.0.0", map[string]interface{}{"repo": "acme/other", "os": []string{"linux"}}},
+		"targets":  []interface{}{map[string]interface{}{"type": "claude"}},
+	})
+
+	first, err := LoadProjectConfig(".")
+	require.NoError(t, err)
+	first.Commands[0] = "acme/changed"
+	for _, cond := range first.Conditions {
+		cond.OS[0] = "plan9"
+	}
+
+	second, err := LoadProjectConfig(".")
+	require.NoError(t, err)
+	assert.Equal(t, "acme/tool@v1.0.0", second.Commands[0])
+	assert.Equal(t, []string{"linux"}, second.Conditions["acme/other"].OS)
+	assert.Equal(t, "claude", second.Targets[0].Type)
+}
@@ -0,0 +1,211 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+// PromptDiff summarizes how the entry file of a command, the prompt Claude Code runs,
+// changes in an update. Front matter is left out.
+type PromptDiff struct {
+	File     string          // entry file of the target version, such as index.md
+	Added    int             // lines added
+	Removed  int             // lines removed
+	Sections []SectionChange // sections that change, in the order of the target file, then removed ones
+}
+
+// Unchanged reports whether the prompt stays the same
+func (d *PromptDiff) Unchanged() bool {
+	return d.Added == 0 && d.Removed == 0
+}
+
+// SectionChange is a markdown section of the entry file that an update changes
+type SectionChange struct {
+	// Heading is the heading line, such as "## Usage"; empty for the text before the
+	// first heading
+	Heading string
+	Status  string // DiffAdded for new sections, DiffRemoved or DiffModified
+	Added   int
+	Removed int
+}
+
+// markdownHeading matches ATX headings, "# Title" up to "###### Title"
+var markdownHeading = regexp.MustCompile(`^#{1,6}\s+\S`)
+
+// promptSection is the heading and lines of one section of a markdown file
+type promptSection struct {
+	heading string
+	key     string // heading and occurrence, matching headings used more than once in order
+	lines   []string
+}
+
+// fetchPromptDiff checks out the target version of an update and compares its entry
+// file with the installed one. Archives and markdown files are not compared.
+func fetchPromptDiff(ctx context.Context, projectRoot string, plan *UpdatePlan) (*PromptDiff, error) {
+	if isDownloadSource(plan.Repository) {
+		return nil, errors.InvalidInput("only git sources are compared")
+	}
+
+	tempDir, removeTempDir, err := newTempDir(projectRoot, "prompt-diff")
+	if err != nil {
+		return nil, err
+	}
+	defer removeTempDir()
+
+	repo, command := splitRepositoryCommand(NormalizeRepositoryURL(plan.Repository))
+	if err := newGitClient(projectRoot).Checkout(ctx, repo, plan.TargetVersion, tempDir, CloneOptions{}); err != nil {
+		return nil, errors.GitError("clone", err)
+	}
+	sourceDir := tempDir
+	if command != "" {
+		if sourceDir, err = repositoryCommandDir(tempDir, repo, command); err != nil {
+			return nil, err
+		}
+	}
+
+	from, _, err := readEntryFile(installedCommandDir(projectRoot, plan.Name))
+	if err != nil {
+		return nil, err
+	}
+	to, entry, err := readEntryFile(sourceDir)
+	if err != nil {
+		return nil, err
+	}
+	return promptDiff(entry, from, to), nil
+}
+
+// readEntryFile returns the entry file of a command directory without its front matter,
+// and its name. A missing entry file reads as empty.
+func readEntryFile(dir string) ([]byte, string, error) {
+	sources, err := loadMetadataSources(dir)
+	if err != nil {
+		return nil, "", err
+	}
+	path := filepath.Join(dir, filepath.FromSlash(sources.entry))
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, sources.entry, nil
+	}
+	if err != nil {
+		return nil, "", errors.FileError("read entry file", path, err)
+	}
+	_, body, _ := splitFrontMatter(data)
+	return body, sources.entry, nil
+}
+
+// promptDiff compares two versions of an entry file section by section. Sections are
+// matched by heading; a heading used more than once is matched by occurrence.
+func promptDiff(file string, from, to []byte) *PromptDiff {
+	diff := &PromptDiff{File: file}
+	for _, op := range diffLines(splitLines(string(from)), splitLines(string(to))) {
+		switch op.kind {
+		case '+':
+			diff.Added++
+		case '-':
+			diff.Removed++
+		}
+	}
+	if diff.Unchanged() {
+		return diff
+	}
+
+	oldSections := promptSections(from)
+	old := make(map[string]promptSection, len(oldSections))
+	for _, section := range oldSections {
+		old[section.key] = section
+	}
+
+	seen := make(map[string]bool)
+	for _, section := range promptSections(to) {
+		seen[section.key] = true
+		previous, ok := old[section.key]
+		if !ok {
+			diff.Sections = append(diff.Sections, SectionChange{
+				Heading: section.heading, Status: DiffAdded, Added: len(section.lines),
+			})
+			continue
+		}
+
+		change := SectionChange{Heading: section.heading, Status: DiffModified}
+		for _, op := range diffLines(previous.lines, section.lines) {
+			switch op.kind {
+			case '+':
+				change.Added++
+			case '-':
+				change.Removed++
+			}
+		}
+		if change.Added+change.Removed > 0 {
+			diff.Sections = append(diff.Sections, change)
+		}
+	}
+	for _, section := range oldSections {
+		if !seen[section.key] {
+			diff.Sections = append(diff.Sections, SectionChange{
+				Heading: section.heading, Status: DiffRemoved, Removed: len(section.lines),
+			})
+		}
+	}
+
+	return diff
+}
+
+// promptSections splits markdown at its headings, ignoring lines in fenced code blocks.
+// Blank lines around sections do not count as content.
+func promptSections(data []byte) []promptSection {
+	var sections []promptSection
+	current := promptSection{}
+	fence := ""
+
+	flush := func() {
+		for len(current.lines) > 0 && strings.TrimSpace(current.lines[len(current.lines)-1]) == "" {
+			current.lines = current.lines[:len(current.lines)-1]
+		}
+		if current.heading != "" || len(current.lines) > 0 {
+			sections = append(sections, current)
+		}
+	}
+
+	for _, line := range splitLines(string(data)) {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case fence != "":
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+		case strings.HasPrefix(trimmed, "```"), strings.HasPrefix(trimmed, "~~~"):
+			fence = trimmed[:3]
+		case markdownHeading.MatchString(line):
+			flush()
+			current = promptSection{heading: strings.TrimSpace(line)}
+			continue
+		}
+		if len(current.lines) == 0 && trimmed == "" {
+			continue
+		}
+		current.lines = append(current.lines, line)
+	}
+	flush()
+
+	counts := make(map[string]int)
+	for i := range sections {
+		counts[sections[i].heading]++
+		sections[i].key = fmt.Sprintf("%s#%d", sections[i].heading, counts[sections[i].heading])
+	}
+	return sections
+}
@@ -0,0 +1,74 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPromptDiff(t *testing.T) {
+	from := []byte("Review the code.\n\n## Steps\n1. Read\n2. Comment\n\n## Notes\nBe kind.\n\n## Example\n```sh\n# not a heading\nrun\n```\n")
+	to := []byte("Review the code.\n\n## Steps\n1. Read\n2. Test\n3. Comment\n\n## Example\n```sh\n# not a heading\nrun\n```\n\n## Output\nA list.\n")
+
+	diff := promptDiff("index.md", from, to)
+	assert.Equal(t, "index.md", diff.File)
+	assert.False(t, diff.Unchanged())
+	assert.Equal(t, 5, diff.Added)
+	assert.Equal(t, 4, diff.Removed)
+	assert.Equal(t, []SectionChange{
+		{Heading: "## Steps", Status: DiffModified, Added: 2, Removed: 1},
+		{Heading: "## Output", Status: DiffAdded, Added: 1},
+		{Heading: "## Notes", Status: DiffRemoved, Removed: 1},
+	}, diff.Sections)
+
+	// Repeated headings are matched in order
+	diff = promptDiff("index.md", []byte("## Step\na\n## Step\nb\n"), []byte("## Step\na\n## Step\nc\n"))
+	assert.Equal(t, []SectionChange{{Heading: "## Step", Status: DiffModified, Added: 1, Removed: 1}}, diff.Sections)
+
+	diff = promptDiff("index.md", from, from)
+	assert.True(t, diff.Unchanged())
+	assert.Empty(t, diff.Sections)
+}
+
+func TestUpdateConfirmShowsPromptDiff(t *testing.T) {
+	repo, release := writeCommandRepo(t)
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	writeConfig(t, []string{repo + "@^1.0.0"})
+	require.NoError(t, InstallFromConfig(ctx, ".", false))
+
+	dir := strings.TrimPrefix(repo, "file://")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "index.md"), []byte("# Tool\n\n## Usage\nRun it.\n"), 0644))
+	out, err := exec.Command("git", "-C", dir, "add", "index.md").CombinedOutput()
+	require.NoError(t, err, string(out))
+	release("v1.1.0")
+
+	var confirmed *UpdatePlan
+	result, err := Update(ctx, UpdateOptions{Name: "tool", Confirm: func(plan *UpdatePlan) bool {
+		confirmed = plan
+		return false
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.SkippedCount)
+	require.NotNil(t, confirmed)
+	require.NotNil(t, confirmed.PromptDiff)
+	assert.Equal(t, "index.md", confirmed.PromptDiff.File)
+	assert.Equal(t, []SectionChange{{Heading: "## Usage", Status: DiffAdded, Added: 1}}, confirmed.PromptDiff.Sections)
+}
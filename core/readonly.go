@@ -0,0 +1,79 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/gifflet/ccmd/internal/fs"
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+// noWrite holds the project root of the no-write mode, or "" when ccmd may write
+var noWrite struct {
+	sync.RWMutex
+	root string
+}
+
+// CheckWritable returns a read-only error naming the first path ccmd writes in a
+// project that cannot be written: the project root, ccmd.yaml, the lock file, .claude
+// and the directories of commands and plugins. Paths that do not exist yet are
+// created in a directory checked before them. Nothing is written to find out, so it is
+// safe on read-only mounts.
+func CheckWritable(projectRoot string) error {
+	paths := []string{
+		projectRoot,
+		configFilePath(projectRoot),
+		lockFilePath(projectRoot),
+		filepath.Join(projectRoot, ".claude"),
+		projectCommandsDir(projectRoot),
+		filepath.Join(projectRoot, ".claude", "plugins"),
+	}
+	for _, path := range paths {
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		if !fs.Writable(path) {
+			return errors.ReadOnly(path)
+		}
+	}
+	return nil
+}
+
+// SetNoWrite switches ccmd into no-write mode for a project, or out of it with "":
+// caches kept in the project, such as the index, are not written, and any other write
+// into the project fails with a read-only error instead of a raw permission error
+// halfway through. Commands that only read use it on read-only checkouts.
+func SetNoWrite(projectRoot string) {
+	noWrite.Lock()
+	noWrite.root = projectRoot
+	noWrite.Unlock()
+}
+
+// checkNoWrite returns a read-only error for a path inside the project of the
+// no-write mode
+func checkNoWrite(path string) error {
+	noWrite.RLock()
+	root := noWrite.root
+	noWrite.RUnlock()
+	if root == "" {
+		return nil
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil
+	}
+	if rel, err := filepath.Rel(root, abs); err != nil || !filepath.IsLocal(rel) {
+		return nil
+	}
+	return errors.ReadOnly(path)
+}
@@ -0,0 +1,65 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+func TestCheckWritable(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	writeConfig(t, []string{"acme/tool"})
+
+	projectRoot, err := os.Getwd()
+	require.NoError(t, err)
+	assert.NoError(t, CheckWritable(projectRoot))
+
+	if os.Geteuid() == 0 {
+		t.Skip("root can write read-only files")
+	}
+	require.NoError(t, os.Chmod(ConfigFileName, 0444))
+	err = CheckWritable(projectRoot)
+	require.Error(t, err)
+	assert.Equal(t, errors.CodeReadOnly, errors.Code(err))
+	assert.Contains(t, err.Error(), ConfigFileName)
+}
+
+func TestNoWriteMode(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+
+	projectRoot, err := os.Getwd()
+	require.NoError(t, err)
+	SetNoWrite(projectRoot)
+	defer SetNoWrite("")
+
+	err = writeFileAtomic(filepath.Join(".claude", "notes.md"), []byte("x"), 0644)
+	require.Error(t, err)
+	assert.Equal(t, errors.CodeReadOnly, errors.Code(err))
+	assert.NoDirExists(t, ".claude")
+
+	// Files outside the project, such as caches, are still written
+	outside := filepath.Join(t.TempDir(), "cache.yaml")
+	require.NoError(t, writeFileAtomic(outside, []byte("x"), 0644))
+
+	// The index is a cache, so it is silently left alone
+	require.NoError(t, os.MkdirAll(".claude", 0755))
+	index := loadIndex(projectRoot, true)
+	index.save()
+	assert.NoFileExists(t, index.path)
+}
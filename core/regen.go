@@ -0,0 +1,182 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	stderrors "errors"
+	"fmt"
+	"maps"
+	"path/filepath"
+	"sort"
+
+	"github.com/gifflet/ccmd/pkg/errors"
+	"github.com/gifflet/ccmd/pkg/output"
+)
+
+// RegenOptions represents options for regenerating standalone command files
+type RegenOptions struct {
+	Name        string // Command name (empty for all)
+	ProjectPath string
+	// KeepEdits keeps standalone files edited by hand as local overrides
+	KeepEdits bool
+	// DiscardEdits regenerates standalone files edited by hand and local overrides
+	DiscardEdits bool
+}
+
+// RegenResult lists the commands whose standalone files were rewritten or left as they
+// were, and the files edited by hand
+type RegenResult struct {
+	Regenerated []string
+	Unchanged   []string
+	Failed      []SyncError
+	// Edited lists files edited by hand that were left alone, or with KeepEdits, the
+	// files that became local overrides
+	Edited []StandaloneEdit
+}
+
+// Regen rewrites the standalone <name>.md files of each command in every target, from
+// the command's index.md and ccmd.yaml. Files that are already current are left untouched,
+// and so are local overrides and files edited by hand unless DiscardEdits is set.
+func Regen(opts RegenOptions) (*RegenResult, error) {
+	if opts.KeepEdits && opts.DiscardEdits {
+		return nil, errors.InvalidInput("edits cannot be both kept and discarded")
+	}
+	policy := leaveEdits
+	switch {
+	case opts.KeepEdits:
+		policy = keepEdits
+	case opts.DiscardEdits:
+		policy = discardEdits
+	}
+
+	projectRoot, err := findProjectRootFrom(opts.ProjectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	names, err := installedCommandNames(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	targets, err := projectTargets(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Name != "" {
+		found := false
+		for _, name := range names {
+			if name == opts.Name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, errors.NotFound(fmt.Sprintf("command %q", opts.Name))
+		}
+		names = []string{opts.Name}
+	}
+
+	result := &RegenResult{
+		Regenerated: []string{},
+		Unchanged:   []string{},
+		Failed:      []SyncError{},
+		Edited:      []StandaloneEdit{},
+	}
+
+	for _, name := range names {
+		records := lockedStandalone(projectRoot, name)
+		previous := maps.Clone(records)
+
+		written, edited, err := regenerateStandaloneDoc(projectRoot, name, targets, records, policy)
+		for _, file := range edited {
+			result.Edited = append(result.Edited, StandaloneEdit{Command: name, File: file})
+		}
+		switch {
+		case err != nil:
+			result.Failed = append(result.Failed, SyncError{Command: name, Operation: "regen", Error: err})
+		case len(written) > 0:
+			result.Regenerated = append(result.Regenerated, name)
+		case len(edited) == 0:
+			result.Unchanged = append(result.Unchanged, name)
+		}
+
+		if !maps.EqualFunc(records, previous, func(a, b *LockStandalone) bool { return *a == *b }) {
+			if err := recordStandaloneFiles(projectRoot, name, records); err != nil {
+				output.PrintWarningf("Failed to record standalone files of %s: %v", name, err)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// installedCommandNames returns the sorted names of commands recorded in the lock file
+func installedCommandNames(projectRoot string) ([]string, error) {
+	lockPath := lockFilePath(projectRoot)
+	if !fileExists(lockPath) {
+		return []string{}, nil
+	}
+
+	lockFile, err := ReadLockFile(lockPath)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(lockFile.Commands))
+	for name := range lockFile.Commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// regenerateStandaloneDoc rewrites the standalone files of one command whose content is
+// out of date, returning the files written and the files edited by hand
+func regenerateStandaloneDoc(projectRoot, name string, targets []Target,
+	records map[string]*LockStandalone, policy editPolicy) (written, edited []string, err error) {
+	commandDir := installedCommandDir(projectRoot, name)
+	metadata, err := readCommandMetadata(filepath.Join(commandDir, "ccmd.yaml"))
+	if stderrors.Is(err, errors.ErrNotFound) {
+		// Commands adopted from a checkout may have no ccmd.yaml
+		metadata, err = &ProjectConfig{}, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if metadata.Name == "" {
+		metadata.Name = name
+	}
+
+	return writeTargets(projectRoot, commandDir, targets, metadata, records, policy)
+}
+
+// refreshStandaloneDocs regenerates stale standalone files after update or sync,
+// warning about failures instead of aborting the surrounding operation
+func refreshStandaloneDocs(projectPath, name string) {
+	result, err := Regen(RegenOptions{Name: name, ProjectPath: projectPath})
+	if err != nil {
+		output.PrintWarningf("Failed to regenerate standalone files: %v", err)
+		return
+	}
+
+	for _, name := range result.Regenerated {
+		output.PrintInfof("Regenerated %s.md", name)
+	}
+	for _, failure := range result.Failed {
+		output.PrintWarningf("Failed to regenerate %s.md: %v", failure.Command, failure.Error)
+	}
+	for _, edit := range result.Edited {
+		output.PrintWarningf("Left %s, which was edited by hand; run 'ccmd regen %s' to keep or discard the edits",
+			edit.File, edit.Command)
+	}
+}
@@ -0,0 +1,122 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupRegenCommand(t *testing.T, name string) {
+	t.Helper()
+
+	createCommandStructure(t, name)
+	commandDir := filepath.Join(".claude", "commands", name)
+	require.NoError(t, writeCommandMetadata(filepath.Join(commandDir, "ccmd.yaml"), &ProjectConfig{
+		Name:        name,
+		Version:     "1.0.0",
+		Description: "test command",
+		Author:      "tester",
+		Entry:       "index.md",
+		Repository:  "https://github.com/test/" + name + ".git",
+	}))
+	require.NoError(t, os.WriteFile(filepath.Join(commandDir, "index.md"), []byte("original body"), 0644))
+
+	lockFile := readOrCreateTestLock(t)
+	lockFile.Commands[name] = createTestLockCommand(name, "1.0.0", "https://github.com/test/"+name+".git")
+	writeLockFile(t, lockFile)
+}
+
+func readOrCreateTestLock(t *testing.T) *LockFile {
+	t.Helper()
+	if !fileExists(LockFileName) {
+		return createBasicLockFile()
+	}
+	return readLockFile(t)
+}
+
+func TestRegen(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+
+	writeConfig(t, []string{})
+	setupRegenCommand(t, "alpha")
+	setupRegenCommand(t, "beta")
+
+	cwd, err := os.Getwd()
+	require.NoError(t, err)
+
+	result, err := Regen(RegenOptions{ProjectPath: cwd})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alpha", "beta"}, result.Regenerated)
+	assert.Empty(t, result.Failed)
+
+	content, err := os.ReadFile(filepath.Join(".claude", "commands", "alpha.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "**Version:** 1.0.0")
+	assert.Contains(t, string(content), "**Author:** tester")
+	assert.Contains(t, string(content), "original body")
+
+	// Nothing changed, so nothing is rewritten
+	result, err = Regen(RegenOptions{ProjectPath: cwd})
+	require.NoError(t, err)
+	assert.Empty(t, result.Regenerated)
+	assert.Equal(t, []string{"alpha", "beta"}, result.Unchanged)
+
+	// Editing index.md makes only that command stale
+	require.NoError(t, os.WriteFile(filepath.Join(".claude", "commands", "beta", "index.md"), []byte("edited body"), 0644))
+	result, err = Regen(RegenOptions{ProjectPath: cwd})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"beta"}, result.Regenerated)
+
+	content, err = os.ReadFile(filepath.Join(".claude", "commands", "beta.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "edited body")
+}
+
+func TestRegenSingleCommand(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+
+	writeConfig(t, []string{})
+	setupRegenCommand(t, "alpha")
+	setupRegenCommand(t, "beta")
+
+	cwd, err := os.Getwd()
+	require.NoError(t, err)
+
+	result, err := Regen(RegenOptions{Name: "beta", ProjectPath: cwd})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"beta"}, result.Regenerated)
+
+	_, err = Regen(RegenOptions{Name: "missing", ProjectPath: cwd})
+	assert.Error(t, err)
+}
+
+func TestRegenMissingIndex(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+
+	writeConfig(t, []string{})
+	setupRegenCommand(t, "alpha")
+	require.NoError(t, os.Remove(filepath.Join(".claude", "commands", "alpha", "index.md")))
+
+	cwd, err := os.Getwd()
+	require.NoError(t, err)
+
+	result, err := Regen(RegenOptions{ProjectPath: cwd})
+	require.NoError(t, err)
+	require.Len(t, result.Failed, 1)
+	assert.Equal(t, "alpha", result.Failed[0].Command)
+}
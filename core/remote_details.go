@@ -0,0 +1,352 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/gifflet/ccmd/pkg/errors"
+	"github.com/gifflet/ccmd/pkg/repospec"
+	"github.com/gifflet/ccmd/pkg/schema"
+)
+
+const (
+	// DefaultDetailCacheSize is the number of repositories whose details a DetailLoader keeps
+	DefaultDetailCacheSize = 256
+
+	// detailHeadSize is how much of the entry file is requested; its front matter and
+	// first lines are all the details need
+	detailHeadSize = 4 << 10
+	// detailSummaryLength caps the summary taken from the entry file
+	detailSummaryLength = 200
+)
+
+// rawContentURLs are the raw file URLs of the forges with a raw content endpoint, with
+// {path} for the repository path and {file} for the file in it. HEAD is the default
+// branch. Tests point them at a local server.
+var rawContentURLs = map[string]string{
+	"github.com":    "https://raw.githubusercontent.com/{path}/HEAD/{file}",
+	"gitlab.com":    "https://gitlab.com/{path}/-/raw/HEAD/{file}",
+	"bitbucket.org": "https://bitbucket.org/{path}/raw/HEAD/{file}",
+}
+
+// RemoteDetails is what a command repository says about itself in its ccmd.yaml and the
+// front matter and first lines of its entry file
+type RemoteDetails struct {
+	Repository  string   `json:"repository"`
+	Name        string   `json:"name,omitempty"`
+	Version     string   `json:"version,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Author      string   `json:"author,omitempty"`
+	License     string   `json:"license,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	// Summary is the first line of the prompt, without markdown heading markers
+	Summary string `json:"summary,omitempty"`
+}
+
+// detailCall is a load in flight, shared by every caller asking for the same repository
+type detailCall struct {
+	done    chan struct{}
+	details *RemoteDetails
+	err     error
+}
+
+// detailEntry is a cached repository in the loader's recency list
+type detailEntry struct {
+	repository string
+	details    *RemoteDetails
+}
+
+// DetailLoader reads the details of remote command repositories through the raw content
+// endpoints of their forges, fetching only ccmd.yaml and the head of the entry file
+// instead of cloning. Concurrent loads of a repository share one set of requests, and
+// the details of the most recently used repositories are cached; failures are not.
+type DetailLoader struct {
+	client *http.Client
+	size   int
+
+	mu       sync.Mutex
+	recent   *list.List // of *detailEntry, most recently used first
+	entries  map[string]*list.Element
+	inflight map[string]*detailCall
+}
+
+// NewDetailLoader returns a loader caching the details of size repositories. A nil
+// client uses the configured proxy and TLS settings.
+func NewDetailLoader(client *http.Client, size int) (*DetailLoader, error) {
+	if client == nil {
+		var err error
+		if client, err = newHTTPClient(remoteSearchTimeout); err != nil {
+			return nil, err
+		}
+	}
+	if size < 1 {
+		size = DefaultDetailCacheSize
+	}
+	return &DetailLoader{
+		client:   client,
+		size:     size,
+		recent:   list.New(),
+		entries:  make(map[string]*list.Element),
+		inflight: make(map[string]*detailCall),
+	}, nil
+}
+
+// Load returns the details of a repository, from the cache when it holds them
+func (l *DetailLoader) Load(ctx context.Context, repository string) (*RemoteDetails, error) {
+	key := detailKey(repository)
+
+	l.mu.Lock()
+	if element, ok := l.entries[key]; ok {
+		l.recent.MoveToFront(element)
+		details := element.Value.(*detailEntry).details
+		l.mu.Unlock()
+		return details, nil
+	}
+	if call, ok := l.inflight[key]; ok {
+		l.mu.Unlock()
+		select {
+		case <-call.done:
+			return call.details, call.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	call := &detailCall{done: make(chan struct{})}
+	l.inflight[key] = call
+	l.mu.Unlock()
+
+	call.details, call.err = fetchRemoteDetails(ctx, l.client, repository)
+
+	l.mu.Lock()
+	delete(l.inflight, key)
+	if call.err == nil {
+		l.remember(key, call.details)
+	}
+	l.mu.Unlock()
+	close(call.done)
+
+	return call.details, call.err
+}
+
+// remember caches details, evicting the least recently used repository when the cache
+// is full. The caller holds l.mu.
+func (l *DetailLoader) remember(key string, details *RemoteDetails) {
+	l.entries[key] = l.recent.PushFront(&detailEntry{repository: key, details: details})
+	for l.recent.Len() > l.size {
+		oldest := l.recent.Back()
+		l.recent.Remove(oldest)
+		delete(l.entries, oldest.Value.(*detailEntry).repository)
+	}
+}
+
+// detailKey identifies a repository, or one command of a multi-command repository,
+// regardless of URL form
+func detailKey(repository string) string {
+	repo, command := splitRepositoryCommand(repository)
+	if command == "" {
+		return repoKey(repo)
+	}
+	return repoKey(repo) + "//" + command
+}
+
+// Prefetch loads the details of repositories in parallel, at most jobs at a time, so
+// later calls to Load return at once. Failures are reported when Load is called again.
+func (l *DetailLoader) Prefetch(ctx context.Context, repositories []string, jobs int) {
+	if jobs < 1 {
+		jobs = 1
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, jobs)
+	for _, repository := range repositories {
+		wg.Add(1)
+		go func(repository string) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			defer func() { <-sem }()
+			_, _ = l.Load(ctx, repository)
+		}(repository)
+	}
+	wg.Wait()
+}
+
+// Apply fills the fields of a search result its source left empty with the details of
+// its repository
+func (d *RemoteDetails) Apply(result *RemoteSearchResult) {
+	if result.Description == "" {
+		result.Description = d.Description
+	}
+	if result.Author == "" {
+		result.Author = d.Author
+	}
+	if len(result.Tags) == 0 {
+		result.Tags = d.Tags
+	}
+	result.Version = d.Version
+	result.Summary = d.Summary
+}
+
+// fetchRemoteDetails reads ccmd.yaml and the head of the entry file of a repository. The
+// metadata can be in either, as for installs.
+func fetchRemoteDetails(ctx context.Context, client *http.Client, repository string) (*RemoteDetails, error) {
+	details := &RemoteDetails{Repository: repository}
+
+	// raw.githubusercontent.com takes the GitHub token, other forges their stored one
+	token := ""
+	if repo, _ := splitRepositoryCommand(repository); hostFromURL(NormalizeRepositoryURL(repo)) == "github.com" {
+		token = githubToken()
+	}
+
+	configURL, err := rawFileURL(repository, ConfigFileName)
+	if err != nil {
+		return nil, err
+	}
+	data, err := fetchRawFile(ctx, client, configURL, token, 0)
+	if err != nil {
+		return nil, err
+	}
+	sources := &metadataSources{entry: defaultEntry}
+	if data != nil {
+		config := map[string]interface{}{}
+		if err := yaml.Unmarshal(data, &config); err != nil {
+			return nil, errors.InvalidInput(fmt.Sprintf("invalid %s in %s: %v", ConfigFileName, repository, err))
+		}
+		sources.config = config
+		if entry, ok := config["entry"].(string); ok && entry != "" {
+			sources.entry = entry
+		}
+	}
+
+	entryURL, err := rawFileURL(repository, sources.entry)
+	if err != nil {
+		return nil, err
+	}
+	head, err := fetchRawFile(ctx, client, entryURL, token, detailHeadSize)
+	if err != nil {
+		return nil, err
+	}
+	if data == nil && head == nil {
+		return nil, errors.NotFound(fmt.Sprintf("%s or %s in %s", ConfigFileName, sources.entry, repository))
+	}
+
+	front, body, ok := splitFrontMatter(head)
+	if ok {
+		all := map[string]interface{}{}
+		if yaml.Unmarshal(front, &all) == nil {
+			sources.frontMatter = map[string]interface{}{}
+			for _, key := range schema.Command().PropertyNames() {
+				if value, ok := all[key]; ok {
+					sources.frontMatter[key] = value
+				}
+			}
+		}
+	}
+	details.Summary = promptSummary(body)
+
+	// Details are informative, so metadata that does not decode is left out
+	if metadata, err := sources.decode(); err == nil {
+		details.Name = metadata.Name
+		details.Version = metadata.Version
+		details.Description = metadata.Description
+		details.Author = metadata.Author
+		details.License = metadata.License
+		details.Tags = metadata.Tags
+	}
+	return details, nil
+}
+
+// promptSummary returns the first non-empty line of a prompt, without heading markers
+func promptSummary(body []byte) string {
+	for _, line := range strings.Split(string(body), "\n") {
+		line = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "#"))
+		if line == "" {
+			continue
+		}
+		if runes := []rune(line); len(runes) > detailSummaryLength {
+			line = string(runes[:detailSummaryLength]) + "…"
+		}
+		return line
+	}
+	return ""
+}
+
+// rawFileURL returns the raw content URL of a file in a repository. Files of one command
+// of a multi-command repository are read from its directory under commands/.
+func rawFileURL(repository, file string) (string, error) {
+	spec, err := repospec.Parse(NormalizeRepositoryURL(repository))
+	if err != nil {
+		return "", err
+	}
+	if spec.Command != "" {
+		file = RepositoryCommandsDir + "/" + spec.Command + "/" + file
+	}
+
+	pattern, ok := rawContentURLs[spec.Host]
+	if !ok {
+		return "", errors.InvalidInput(fmt.Sprintf("%s has no raw content endpoint", repository))
+	}
+	return strings.NewReplacer("{path}", spec.Path, "{file}", file).Replace(pattern), nil
+}
+
+// fetchRawFile downloads a file from a raw content endpoint, nil when it does not exist.
+// A positive limit requests only that many bytes, and reads no more when the server
+// sends the whole file anyway.
+func fetchRawFile(ctx context.Context, client *http.Client, rawURL, token string, limit int64) ([]byte, error) {
+	if limit <= 0 {
+		limit = 1 << 20
+	}
+
+	var data []byte
+	err := withRetry(ctx, "Request to "+hostFromURL(rawURL), func() error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, http.NoBody)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Range", fmt.Sprintf("bytes=0-%d", limit-1))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		authorizeRequest(req)
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		switch resp.StatusCode {
+		case http.StatusOK, http.StatusPartialContent:
+			data, err = io.ReadAll(io.LimitReader(resp.Body, limit))
+			return err
+		case http.StatusNotFound, http.StatusRequestedRangeNotSatisfiable:
+			data = nil
+			return nil
+		default:
+			return checkHTTPStatus(resp, fmt.Errorf("%s returned %s", req.URL.Host, resp.Status))
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
@@ -0,0 +1,173 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newRawServer serves files of GitHub repositories from files, keyed by
+// owner/repo/path, and counts the requests
+func newRawServer(t *testing.T, files map[string]string, requests *int32) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+		content, ok := files[r.URL.Path[1:]]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, content)
+	}))
+	useRawServer(t, server)
+
+	return server
+}
+
+// useRawServer points the raw content URLs of GitHub at server
+func useRawServer(t *testing.T, server *httptest.Server) {
+	t.Helper()
+	t.Cleanup(server.Close)
+
+	old := rawContentURLs
+	rawContentURLs = map[string]string{"github.com": server.URL + "/{path}/{file}"}
+	t.Cleanup(func() { rawContentURLs = old })
+	t.Setenv(GitHubTokenEnv, "")
+}
+
+func TestDetailLoader(t *testing.T) {
+	var requests int32
+	newRawServer(t, map[string]string{
+		"acme/review/ccmd.yaml":            "name: review\nversion: 1.4.0\ndescription: Code review helper\ntags: [git]\n",
+		"acme/review/index.md":             "# Review the diff\n\nLook at every file.\n",
+		"acme/notes/index.md":              "---\nname: notes\nversion: 0.2.0\nallowed-tools: Bash\n---\n\nTake notes.\n",
+		"acme/kit/commands/lint/ccmd.yaml": "name: lint\nversion: 2.0.0\n",
+	}, &requests)
+
+	loader, err := NewDetailLoader(nil, 10)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	details, err := loader.Load(ctx, "https://github.com/acme/review.git")
+	require.NoError(t, err)
+	assert.Equal(t, "1.4.0", details.Version)
+	assert.Equal(t, "Code review helper", details.Description)
+	assert.Equal(t, []string{"git"}, details.Tags)
+	assert.Equal(t, "Review the diff", details.Summary)
+	assert.EqualValues(t, 2, requests)
+
+	// Other URL forms of a repository are served from the cache
+	_, err = loader.Load(ctx, "acme/review")
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, requests)
+
+	// Metadata can come from front matter alone
+	details, err = loader.Load(ctx, "acme/notes")
+	require.NoError(t, err)
+	assert.Equal(t, "notes", details.Name)
+	assert.Equal(t, "0.2.0", details.Version)
+	assert.Equal(t, "Take notes.", details.Summary)
+
+	// One command of a multi-command repository is read from its directory
+	details, err = loader.Load(ctx, "acme/kit//lint")
+	require.NoError(t, err)
+	assert.Equal(t, "2.0.0", details.Version)
+
+	_, err = loader.Load(ctx, "acme/missing")
+	assert.Error(t, err)
+
+	_, err = loader.Load(ctx, "https://example.com/acme/other.git")
+	assert.Error(t, err, "hosts without a raw content endpoint have no details")
+}
+
+func TestDetailLoaderCoalescesAndEvicts(t *testing.T) {
+	var requests int32
+	started := make(chan struct{}, 16)
+	release := make(chan struct{})
+	useRawServer(t, httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		started <- struct{}{}
+		<-release
+		fmt.Fprint(w, "name: tool\nversion: 1.0.0\n")
+	})))
+
+	loader, err := NewDetailLoader(nil, 1)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			details, err := loader.Load(ctx, "acme/tool")
+			assert.NoError(t, err)
+			assert.Equal(t, "1.0.0", details.Version)
+		}()
+	}
+	<-started
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	assert.EqualValues(t, 2, requests, "concurrent loads share one fetch")
+
+	// A cache of one repository forgets the first when a second is loaded
+	_, err = loader.Load(ctx, "acme/other")
+	require.NoError(t, err)
+	_, err = loader.Load(ctx, "acme/tool")
+	require.NoError(t, err)
+	assert.EqualValues(t, 6, requests)
+}
+
+func TestSearchRemoteDetails(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+
+	var requests int32
+	newRawServer(t, map[string]string{
+		"acme/deploy/ccmd.yaml": "name: deploy\nversion: 3.1.0\ndescription: Ship things\ntags: [ops]\n",
+	}, &requests)
+
+	catalog := "commands:\n  - name: deploy\n    repository: acme/deploy\n  - name: gone\n    repository: acme/gone\n"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, catalog)
+	}))
+	defer server.Close()
+
+	report, err := SearchRemote(context.Background(), RemoteSearchOptions{
+		Keyword:  "e",
+		Catalogs: []string{server.URL + "/catalog.yaml"},
+		NoGitHub: true,
+		Details:  true,
+	})
+	require.NoError(t, err)
+	require.Len(t, report.Results, 2)
+
+	for _, result := range report.Results {
+		if result.Name == "deploy" {
+			assert.Equal(t, "3.1.0", result.Version)
+			assert.Equal(t, "Ship things", result.Description)
+			assert.Equal(t, []string{"ops"}, result.Tags)
+		}
+	}
+	require.Len(t, report.Warnings, 1)
+	assert.Contains(t, report.Warnings[0], "acme/gone")
+}
@@ -0,0 +1,136 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+// RemoteVersion is a version of a remote repository resolved without cloning it
+type RemoteVersion struct {
+	Ref    string `json:"ref"`    // the tag or branch
+	Commit string `json:"commit"` // the commit Ref points to
+}
+
+// remoteRefs are the branches and tags of a remote repository, with the commit each
+// points to. Annotated tags are peeled to the commit they tag.
+type remoteRefs struct {
+	head     string // the default branch
+	branches map[string]string
+	tags     map[string]string
+}
+
+// ResolveRemote resolves a version of a remote repository with git ls-remote, before
+// anything is cloned: a constraint such as ^1.2.0 to the newest stable tag satisfying
+// it, a tag or branch to itself, and an empty version to the newest stable tag, or the
+// default branch when the repository has no semver tags. Commits are returned as given.
+func ResolveRemote(ctx context.Context, repoURL, version string) (*RemoteVersion, error) {
+	if isCommitHash(version) {
+		return &RemoteVersion{Ref: version, Commit: version}, nil
+	}
+
+	refs, err := listRemoteRefs(ctx, repoURL)
+	if err != nil {
+		return nil, err
+	}
+
+	ref := version
+	switch {
+	case IsConstraint(version):
+		c, err := ParseConstraint(version)
+		if err != nil {
+			return nil, err
+		}
+		tag, ok := highestMatchingTag(refs.tagNames(), c, false)
+		if !ok {
+			return nil, errors.NotFound(fmt.Sprintf("no tag of %s satisfies %q", repoURL, version))
+		}
+		ref = tag
+	case version == "":
+		if tag, ok := highestMatchingTag(refs.tagNames(), nil, false); ok {
+			ref = tag
+		} else {
+			ref = refs.head
+		}
+	}
+
+	commit, ok := refs.tags[ref]
+	if !ok {
+		commit, ok = refs.branches[ref]
+	}
+	if !ok {
+		return nil, errors.NotFound(fmt.Sprintf("ref %q in %s", ref, repoURL))
+	}
+	return &RemoteVersion{Ref: ref, Commit: commit}, nil
+}
+
+// resolveDefaultBranch resolves the head of the default branch of a remote repository,
+// which installs without a version track under the default-branch default version
+func resolveDefaultBranch(ctx context.Context, repoURL string) (*RemoteVersion, error) {
+	refs, err := listRemoteRefs(ctx, repoURL)
+	if err != nil {
+		return nil, err
+	}
+	commit, ok := refs.branches[refs.head]
+	if !ok {
+		return nil, errors.NotFound(fmt.Sprintf("default branch of %s", repoURL))
+	}
+	return &RemoteVersion{Ref: refs.head, Commit: commit}, nil
+}
+
+// listRemoteRefs lists the branches, tags and default branch of a remote repository
+func listRemoteRefs(ctx context.Context, repoURL string) (*remoteRefs, error) {
+	out, err := runGitRemote(ctx, "Ref listing of "+repoURL, "ls-remote", "--symref", repoURL)
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if err != nil {
+		return nil, errors.GitError("list remote refs", err)
+	}
+	return parseRemoteRefs(string(out)), nil
+}
+
+// parseRemoteRefs reads the output of git ls-remote --symref
+func parseRemoteRefs(out string) *remoteRefs {
+	refs := &remoteRefs{branches: make(map[string]string), tags: make(map[string]string)}
+	peeled := make(map[string]string)
+
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 3 && fields[0] == "ref:" && fields[2] == "HEAD":
+			refs.head = strings.TrimPrefix(fields[1], "refs/heads/")
+		case len(fields) != 2:
+		case strings.HasPrefix(fields[1], "refs/heads/"):
+			refs.branches[strings.TrimPrefix(fields[1], "refs/heads/")] = fields[0]
+		case strings.HasSuffix(fields[1], "^{}"):
+			peeled[strings.TrimSuffix(strings.TrimPrefix(fields[1], "refs/tags/"), "^{}")] = fields[0]
+		case strings.HasPrefix(fields[1], "refs/tags/"):
+			refs.tags[strings.TrimPrefix(fields[1], "refs/tags/")] = fields[0]
+		}
+	}
+	for tag, commit := range peeled {
+		refs.tags[tag] = commit
+	}
+	return refs
+}
+
+// tagNames returns the names of the tags
+func (r *remoteRefs) tagNames() []string {
+	names := make([]string, 0, len(r.tags))
+	for name := range r.tags {
+		names = append(names, name)
+	}
+	return names
+}
@@ -0,0 +1,85 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+func TestResolveRemote(t *testing.T) {
+	repo, release := writeCommandRepo(t)
+	ctx := context.Background()
+	v11 := release("v1.1.0")
+	release("v2.0.0-beta.1")
+
+	// Annotated tags resolve to the commit they tag
+	dir := strings.TrimPrefix(repo, "file://")
+	out, err := exec.Command("git", "-C", dir, "tag", "-a", "v1.2.0", "-m", "v1.2.0").CombinedOutput()
+	require.NoError(t, err, string(out))
+	head, err := gitGetCurrentCommit(dir)
+	require.NoError(t, err)
+
+	tests := []struct {
+		version string
+		ref     string
+		commit  string
+	}{
+		{"^1.0.0", "v1.2.0", head},
+		{"~1.1.0", "v1.1.0", v11},
+		{"v1.1.0", "v1.1.0", v11},
+		{"v1.2.0", "v1.2.0", head},
+		{"", "v1.2.0", head},
+		{"main", "main", head},
+		{v11, v11, v11},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			remote, err := ResolveRemote(ctx, repo, tt.version)
+			require.NoError(t, err)
+			assert.Equal(t, tt.ref, remote.Ref)
+			assert.Equal(t, tt.commit, remote.Commit)
+		})
+	}
+
+	_, err = ResolveRemote(ctx, repo, "^3.0.0")
+	assert.ErrorIs(t, err, errors.ErrNotFound)
+	_, err = ResolveRemote(ctx, repo, "missing")
+	assert.ErrorIs(t, err, errors.ErrNotFound)
+}
+
+func TestUpdateCheckWithoutCheckout(t *testing.T) {
+	repo, release := writeCommandRepo(t)
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	// Installs keep no .git directory; the locked commit is compared with the remote
+	_, _, err := Install(ctx, InstallOptions{Repository: repo, Version: "main"})
+	require.NoError(t, err)
+	cmd, err := GetCommandInfo("tool", "")
+	require.NoError(t, err)
+
+	plan, needsUpdate := planUpdate(ctx, ".", *cmd, false, false)
+	assert.False(t, needsUpdate)
+	assert.Equal(t, "already up to date", plan.Reason)
+
+	release("v1.1.0")
+	plan, needsUpdate = planUpdate(ctx, ".", *cmd, false, false)
+	assert.True(t, needsUpdate)
+	assert.Equal(t, "new commits on main", plan.Reason)
+}
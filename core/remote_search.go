@@ -0,0 +1,532 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+const (
+	// CommandTopic is the GitHub topic that marks a repository as a ccmd command
+	CommandTopic = "ccmd-command"
+	// GitHubTokenEnv holds an optional token used for GitHub API requests
+	GitHubTokenEnv = "GITHUB_TOKEN"
+
+	remoteSearchTimeout = 15 * time.Second
+	defaultRemoteLimit  = 20
+)
+
+// githubAPIURL is the GitHub REST API base URL; tests point it at a local server
+var githubAPIURL = "https://api.github.com"
+
+// RemoteSearchOptions configures a search across catalogs and GitHub
+type RemoteSearchOptions struct {
+	Keyword     string
+	Tags        []string
+	Catalogs    []string // catalog URLs or file paths
+	Taps        []Tap    // catalogs added with 'ccmd tap add', read through the tap cache
+	NoGitHub    bool
+	Limit       int
+	ProjectPath string
+	Client      *http.Client
+	// Details reads version, description and tags from the ccmd.yaml and index.md of each
+	// result through the forge's raw content endpoint
+	Details bool
+	Loader  *DetailLoader // shared across searches; a new loader when nil
+}
+
+// RemoteSearchResult is a command found in a catalog or on GitHub
+type RemoteSearchResult struct {
+	Name             string   `json:"name"`
+	Description      string   `json:"description,omitempty"`
+	Author           string   `json:"author,omitempty"`
+	Tags             []string `json:"tags,omitempty"`
+	Repository       string   `json:"repository"`
+	Stars            int      `json:"stars,omitempty"`
+	Sources          []string `json:"sources"`
+	Installed        bool     `json:"installed"`
+	InstalledVersion string   `json:"installed_version,omitempty"`
+	Score            float64  `json:"score"`
+	Version          string   `json:"version,omitempty"`
+	Summary          string   `json:"summary,omitempty"`
+}
+
+// Catalog is a published list of commands, in YAML or JSON
+type Catalog struct {
+	Commands []CatalogEntry `yaml:"commands" json:"commands"`
+}
+
+// CatalogEntry describes one command in a catalog
+type CatalogEntry struct {
+	Name        string   `yaml:"name" json:"name"`
+	Repository  string   `yaml:"repository" json:"repository"`
+	Description string   `yaml:"description,omitempty" json:"description,omitempty"`
+	Author      string   `yaml:"author,omitempty" json:"author,omitempty"`
+	Tags        []string `yaml:"tags,omitempty" json:"tags,omitempty"`
+}
+
+// RemoteSearchReport holds merged results and the sources that could not be queried
+type RemoteSearchReport struct {
+	Results  []RemoteSearchResult `json:"results"`
+	Warnings []string             `json:"warnings,omitempty"`
+}
+
+// SearchRemote queries the configured catalogs and the GitHub search API, then merges,
+// ranks and annotates the results with local install status. A failing source is
+// reported as a warning rather than aborting the search.
+func SearchRemote(ctx context.Context, opts RemoteSearchOptions) (*RemoteSearchReport, error) {
+	if opts.Keyword == "" && len(opts.Tags) == 0 {
+		return nil, errors.InvalidInput("remote search requires a keyword or tags")
+	}
+
+	client := opts.Client
+	if client == nil {
+		var err error
+		if client, err = newHTTPClient(remoteSearchTimeout); err != nil {
+			return nil, err
+		}
+	}
+
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = defaultRemoteLimit
+	}
+
+	report := &RemoteSearchReport{Results: []RemoteSearchResult{}}
+	merged := make(map[string]*RemoteSearchResult)
+	var order []string
+
+	add := func(result RemoteSearchResult) {
+		key := repoKey(result.Repository)
+		if existing, ok := merged[key]; ok {
+			mergeRemoteResult(existing, result)
+			return
+		}
+		merged[key] = &result
+		order = append(order, key)
+	}
+
+	addCatalog := func(source string, catalog *Catalog) {
+		for _, entry := range catalog.Commands {
+			if entry.Repository == "" {
+				continue
+			}
+			result := RemoteSearchResult{
+				Name:        entry.Name,
+				Description: entry.Description,
+				Author:      entry.Author,
+				Tags:        entry.Tags,
+				Repository:  entry.Repository,
+				Sources:     []string{source},
+			}
+			if result.Name == "" {
+				result.Name = extractCommandName(entry.Repository)
+			}
+			if remoteMatches(result, opts) {
+				add(result)
+			}
+		}
+	}
+
+	for _, location := range opts.Catalogs {
+		catalog, err := fetchCatalog(ctx, client, location)
+		if err != nil {
+			report.Warnings = append(report.Warnings, fmt.Sprintf("catalog %s: %v", location, err))
+			continue
+		}
+		addCatalog("catalog:"+location, catalog)
+	}
+
+	if len(opts.Taps) > 0 {
+		cache := newTapCache(opts.ProjectPath)
+		for _, tap := range opts.Taps {
+			catalog, err := cache.catalog(ctx, client, tap)
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			if err != nil {
+				report.Warnings = append(report.Warnings, fmt.Sprintf("tap %s: %v", tap.Name, err))
+				continue
+			}
+			addCatalog("tap:"+tap.Name, catalog)
+		}
+	}
+
+	if !opts.NoGitHub {
+		results, err := searchGitHub(ctx, client, opts)
+		if err != nil {
+			report.Warnings = append(report.Warnings, fmt.Sprintf("github: %v", err))
+		}
+		for _, result := range results {
+			add(result)
+		}
+	}
+
+	installed := installedVersions(opts.ProjectPath)
+	for _, key := range order {
+		result := merged[key]
+		if version, ok := installed[key]; ok {
+			result.Installed = true
+			result.InstalledVersion = version
+		}
+		result.Score = scoreRemoteResult(*result, opts.Keyword)
+		report.Results = append(report.Results, *result)
+	}
+
+	sort.SliceStable(report.Results, func(i, j int) bool {
+		return report.Results[i].Score > report.Results[j].Score
+	})
+	if len(report.Results) > limit {
+		report.Results = report.Results[:limit]
+	}
+
+	if opts.Details {
+		if err := loadResultDetails(ctx, client, opts, report); err != nil {
+			return nil, err
+		}
+	}
+
+	return report, nil
+}
+
+// loadResultDetails fills the results in with the details of their repositories, loaded
+// in parallel. Repositories whose details cannot be read keep what their source said.
+func loadResultDetails(ctx context.Context, client *http.Client, opts RemoteSearchOptions, report *RemoteSearchReport) error {
+	loader := opts.Loader
+	if loader == nil {
+		var err error
+		if loader, err = NewDetailLoader(client, len(report.Results)); err != nil {
+			return err
+		}
+	}
+
+	repositories := make([]string, len(report.Results))
+	for i, result := range report.Results {
+		repositories[i] = result.Repository
+	}
+	loader.Prefetch(ctx, repositories, configuredJobs(opts.ProjectPath))
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	for i := range report.Results {
+		details, err := loader.Load(ctx, report.Results[i].Repository)
+		if err != nil {
+			report.Warnings = append(report.Warnings, fmt.Sprintf("details of %s: %v", report.Results[i].Repository, err))
+			continue
+		}
+		details.Apply(&report.Results[i])
+	}
+	return nil
+}
+
+// fetchCatalog loads a catalog from an http(s) URL or a local file
+func fetchCatalog(ctx context.Context, client *http.Client, location string) (*Catalog, error) {
+	var data []byte
+
+	if strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://") {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, location, http.NoBody)
+		if err != nil {
+			return nil, err
+		}
+		body, err := doRequest(client, req)
+		if err != nil {
+			return nil, err
+		}
+		data = body
+	} else {
+		body, err := os.ReadFile(filepath.Clean(location))
+		if err != nil {
+			return nil, err
+		}
+		data = body
+	}
+
+	var catalog Catalog
+	// JSON is valid YAML, so a single decoder handles both formats
+	if err := yaml.Unmarshal(data, &catalog); err != nil {
+		return nil, fmt.Errorf("invalid catalog: %w", err)
+	}
+
+	return &catalog, nil
+}
+
+type githubRepository struct {
+	FullName    string   `json:"full_name"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	HTMLURL     string   `json:"html_url"`
+	Stars       int      `json:"stargazers_count"`
+	Topics      []string `json:"topics"`
+	Owner       struct {
+		Login string `json:"login"`
+	} `json:"owner"`
+}
+
+// searchGitHub finds repositories tagged with the ccmd-command topic and, when a token is
+// available, repositories with a ccmd.yaml at their root
+func searchGitHub(ctx context.Context, client *http.Client, opts RemoteSearchOptions) ([]RemoteSearchResult, error) {
+	terms := []string{"topic:" + CommandTopic}
+	if opts.Keyword != "" {
+		terms = append([]string{opts.Keyword}, terms...)
+	}
+	for _, tag := range opts.Tags {
+		terms = append(terms, "topic:"+tag)
+	}
+
+	var repoResponse struct {
+		Items []githubRepository `json:"items"`
+	}
+	if err := githubGet(ctx, client, "/search/repositories?q="+url.QueryEscape(strings.Join(terms, " "))+"&sort=stars", &repoResponse); err != nil {
+		return nil, err
+	}
+
+	results := make([]RemoteSearchResult, 0, len(repoResponse.Items))
+	for _, repo := range repoResponse.Items {
+		results = append(results, githubResult(repo))
+	}
+
+	// Code search is only available to authenticated requests
+	if githubToken() == "" || opts.Keyword == "" || len(opts.Tags) > 0 {
+		return results, nil
+	}
+
+	var codeResponse struct {
+		Items []struct {
+			Path       string           `json:"path"`
+			Repository githubRepository `json:"repository"`
+		} `json:"items"`
+	}
+	query := opts.Keyword + " filename:ccmd.yaml path:/"
+	if err := githubGet(ctx, client, "/search/code?q="+url.QueryEscape(query), &codeResponse); err != nil {
+		return results, err
+	}
+
+	for _, item := range codeResponse.Items {
+		if item.Path != "ccmd.yaml" {
+			continue
+		}
+		results = append(results, githubResult(item.Repository))
+	}
+
+	return results, nil
+}
+
+// githubToken returns $GITHUB_TOKEN, or the token stored with 'ccmd login github.com'
+func githubToken() string {
+	if token := os.Getenv(GitHubTokenEnv); token != "" {
+		return token
+	}
+	if cred, ok := hostToken("github.com"); ok {
+		return cred.token
+	}
+	return ""
+}
+
+func githubResult(repo githubRepository) RemoteSearchResult {
+	var tags []string
+	for _, topic := range repo.Topics {
+		if topic != CommandTopic {
+			tags = append(tags, topic)
+		}
+	}
+
+	repository := repo.HTMLURL
+	if repository == "" {
+		repository = "https://github.com/" + repo.FullName
+	}
+
+	return RemoteSearchResult{
+		Name:        repo.Name,
+		Description: repo.Description,
+		Author:      repo.Owner.Login,
+		Tags:        tags,
+		Repository:  repository,
+		Stars:       repo.Stars,
+		Sources:     []string{"github"},
+	}
+}
+
+func githubGet(ctx context.Context, client *http.Client, path string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, githubAPIURL+path, http.NoBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if token := githubToken(); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	body, err := doRequest(client, req)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(body, out)
+}
+
+// doRequest sends a GET request, retrying transient failures and rate limits
+func doRequest(client *http.Client, req *http.Request) ([]byte, error) {
+	var body []byte
+	err := withRetry(req.Context(), "Request to "+req.URL.Host, func() error {
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		body, err = io.ReadAll(io.LimitReader(resp.Body, 10<<20))
+		if err != nil {
+			return err
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			return checkHTTPStatus(resp, fmt.Errorf("%s returned %s", req.URL.Host, resp.Status))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return body, nil
+}
+
+// remoteMatches applies the keyword and tag filters to a catalog entry
+func remoteMatches(result RemoteSearchResult, opts RemoteSearchOptions) bool {
+	if opts.Keyword != "" {
+		keyword := strings.ToLower(opts.Keyword)
+		if !strings.Contains(strings.ToLower(result.Name), keyword) &&
+			!strings.Contains(strings.ToLower(result.Description), keyword) &&
+			!strings.Contains(strings.ToLower(result.Repository), keyword) {
+			return false
+		}
+	}
+
+	for _, tag := range opts.Tags {
+		found := false
+		for _, resultTag := range result.Tags {
+			if strings.EqualFold(tag, resultTag) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+func mergeRemoteResult(existing *RemoteSearchResult, other RemoteSearchResult) {
+	if existing.Description == "" {
+		existing.Description = other.Description
+	}
+	if existing.Author == "" {
+		existing.Author = other.Author
+	}
+	if other.Stars > existing.Stars {
+		existing.Stars = other.Stars
+	}
+	for _, tag := range other.Tags {
+		if !containsFold(existing.Tags, tag) {
+			existing.Tags = append(existing.Tags, tag)
+		}
+	}
+	for _, source := range other.Sources {
+		if !containsFold(existing.Sources, source) {
+			existing.Sources = append(existing.Sources, source)
+		}
+	}
+}
+
+// scoreRemoteResult ranks name matches above description matches, then favors
+// results listed by several sources and popular repositories
+func scoreRemoteResult(result RemoteSearchResult, keyword string) float64 {
+	score := 0.0
+	keyword = strings.ToLower(keyword)
+	name := strings.ToLower(result.Name)
+
+	switch {
+	case keyword == "":
+	case name == keyword:
+		score += 100
+	case strings.HasPrefix(name, keyword):
+		score += 60
+	case strings.Contains(name, keyword):
+		score += 40
+	case strings.Contains(strings.ToLower(result.Description), keyword):
+		score += 20
+	}
+
+	score += 10 * float64(len(result.Sources)-1)
+	score += 5 * math.Log10(float64(result.Stars)+1)
+
+	return score
+}
+
+// installedVersions maps repository keys to installed versions for the project
+func installedVersions(projectPath string) map[string]string {
+	installed := make(map[string]string)
+
+	projectRoot, err := findProjectRootFrom(projectPath)
+	if err != nil {
+		return installed
+	}
+
+	lockPath := lockFilePath(projectRoot)
+	if !fileExists(lockPath) {
+		return installed
+	}
+
+	lockFile, err := ReadLockFile(lockPath)
+	if err != nil {
+		return installed
+	}
+
+	for _, cmd := range lockFile.Commands {
+		installed[repoKey(cmd.Source)] = cmd.Version
+	}
+	for _, p := range lockFile.Plugins {
+		installed[repoKey(p.Source)] = p.Version
+	}
+
+	return installed
+}
+
+// repoKey identifies a repository regardless of URL form
+func repoKey(repository string) string {
+	return strings.ToLower(ExtractRepoPath(NormalizeRepositoryURL(repository)))
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
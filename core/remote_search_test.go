@@ -0,0 +1,128 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testCatalog = `commands:
+  - name: review
+    repository: github.com/acme/review
+    description: Code review helper
+    tags: [git, review]
+  - name: deploy
+    repository: https://github.com/acme/deploy
+    description: Ship things
+`
+
+func newGitHubServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/search/repositories":
+			assert.Contains(t, r.URL.Query().Get("q"), "topic:"+CommandTopic)
+			fmt.Fprint(w, `{"items": [
+				{"name": "review", "full_name": "acme/review", "html_url": "https://github.com/acme/review",
+				 "description": "", "stargazers_count": 42, "topics": ["ccmd-command", "ai"], "owner": {"login": "acme"}},
+				{"name": "reviewer-pro", "full_name": "other/reviewer-pro", "html_url": "https://github.com/other/reviewer-pro",
+				 "description": "Another reviewer", "stargazers_count": 3, "topics": ["ccmd-command"], "owner": {"login": "other"}}
+			]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	oldURL := githubAPIURL
+	githubAPIURL = server.URL
+	t.Cleanup(func() { githubAPIURL = oldURL })
+	t.Setenv(GitHubTokenEnv, "")
+
+	return server
+}
+
+func TestSearchRemote(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+
+	newGitHubServer(t)
+	require.NoError(t, os.WriteFile("catalog.yaml", []byte(testCatalog), 0o600))
+
+	lockFile := createBasicLockFile()
+	lockFile.Commands["review"] = createTestLockCommand("review", "1.2.0", "https://github.com/acme/review.git")
+	writeLockFile(t, lockFile)
+
+	report, err := SearchRemote(context.Background(), RemoteSearchOptions{
+		Keyword:  "review",
+		Catalogs: []string{"catalog.yaml"},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, report.Warnings)
+	require.Len(t, report.Results, 2)
+
+	top := report.Results[0]
+	assert.Equal(t, "review", top.Name)
+	assert.Equal(t, "Code review helper", top.Description)
+	assert.Equal(t, 42, top.Stars)
+	assert.ElementsMatch(t, []string{"catalog:catalog.yaml", "github"}, top.Sources)
+	assert.ElementsMatch(t, []string{"git", "review", "ai"}, top.Tags)
+	assert.True(t, top.Installed)
+	assert.Equal(t, "1.2.0", top.InstalledVersion)
+
+	assert.Equal(t, "reviewer-pro", report.Results[1].Name)
+	assert.False(t, report.Results[1].Installed)
+}
+
+func TestSearchRemoteCatalogOnly(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"commands": [{"name": "deploy", "repository": "acme/deploy", "tags": ["ops"]}]}`)
+	}))
+	defer server.Close()
+
+	report, err := SearchRemote(context.Background(), RemoteSearchOptions{
+		Tags:     []string{"ops"},
+		Catalogs: []string{server.URL + "/catalog.json", "missing.yaml"},
+		NoGitHub: true,
+	})
+	require.NoError(t, err)
+
+	require.Len(t, report.Results, 1)
+	assert.Equal(t, "deploy", report.Results[0].Name)
+	require.Len(t, report.Warnings, 1)
+	assert.True(t, strings.HasPrefix(report.Warnings[0], "catalog missing.yaml"))
+}
+
+func TestSearchRemoteRequiresQuery(t *testing.T) {
+	_, err := SearchRemote(context.Background(), RemoteSearchOptions{NoGitHub: true})
+	assert.Error(t, err)
+}
+
+func TestScoreRemoteResult(t *testing.T) {
+	exact := scoreRemoteResult(RemoteSearchResult{Name: "lint", Sources: []string{"github"}}, "lint")
+	prefix := scoreRemoteResult(RemoteSearchResult{Name: "linter", Sources: []string{"github"}}, "lint")
+	description := scoreRemoteResult(RemoteSearchResult{Name: "x", Description: "runs lint", Sources: []string{"github"}}, "lint")
+
+	assert.Greater(t, exact, prefix)
+	assert.Greater(t, prefix, description)
+}
@@ -13,10 +13,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"strings"
 
 	"gopkg.in/yaml.v3"
 
+	"github.com/gifflet/ccmd/internal/fs"
 	"github.com/gifflet/ccmd/pkg/errors"
 	"github.com/gifflet/ccmd/pkg/output"
 )
@@ -26,20 +26,54 @@ type RemoveOptions struct {
 	Name        string
 	Force       bool
 	UpdateFiles bool
+	DryRun      bool // Only report what would be removed
+
+	// ProjectPath is a path inside the project to remove from; empty uses the working
+	// directory
+	ProjectPath string
+
+	// Names removes several commands and plugins at once, given by name or by glob
+	// pattern such as "acme-*". All removes every command and plugin. Either way the
+	// removal is all or nothing, and ccmd.yaml and ccmd-lock.yaml are written once.
+	Names []string
+	All   bool
 }
 
-// Remove removes an installed command
+// Remove removes an installed command and records it in the audit log
 func Remove(opts RemoveOptions) error {
+	if len(opts.Names) > 0 || opts.All {
+		return removeMany(opts)
+	}
+	if opts.DryRun || opts.Name == "" {
+		return remove(opts)
+	}
+
+	projectRoot, err := findProjectRootFrom(opts.ProjectPath)
+	if err != nil {
+		return err
+	}
+	defer snapshotBefore(projectRoot, AuditRemove)()
+
+	// Capture the lock entry before it is deleted
+	event := auditLockEntry(projectRoot, AuditEvent{Action: AuditRemove, Name: opts.Name})
+	err = remove(opts)
+	if event.Type != "" || err == nil {
+		recordAudit(projectRoot, auditResult(event, err))
+	}
+	return err
+}
+
+func remove(opts RemoveOptions) error {
 	if opts.Name == "" {
 		return errors.InvalidInput("command name is required")
 	}
 
-	projectRoot, err := findProjectRoot()
+	projectRoot, err := findProjectRootFrom(opts.ProjectPath)
 	if err != nil {
 		return err
 	}
 
-	lockPath := filepath.Join(projectRoot, LockFileName)
+	lockPath := lockFilePath(projectRoot)
 	if !fileExists(lockPath) {
 		return errors.NotFound("no commands installed (ccmd-lock.yaml not found)")
 	}
@@ -56,6 +90,11 @@ func Remove(opts RemoveOptions) error {
 		return errors.NotFound(fmt.Sprintf("command or plugin %q", opts.Name))
 	}
 
+	var config *ProjectConfig
+	if ProjectConfigExists(projectRoot) {
+		config, _ = LoadProjectConfig(projectRoot)
+	}
+
 	if isPlugin {
 		output.PrintInfof("Will remove plugin %q", opts.Name)
 		output.PrintInfof("Repository: %s", pluginInfo.Source)
@@ -63,6 +102,19 @@ func Remove(opts RemoveOptions) error {
 			output.PrintInfof("Version: %s", pluginInfo.Version)
 		}
 
+		if opts.DryRun {
+			printRemoveDryRun(projectRoot, filepath.Join(".claude", "plugins", opts.Name), "", opts.UpdateFiles)
+			return nil
+		}
+
+		spec := ""
+		if opts.UpdateFiles && config != nil {
+			spec = findConfigSpec(config.Plugins, opts.Name, pluginInfo.Source)
+		}
+		if _, err := trashPlugin(projectRoot, opts.Name, pluginInfo, spec); err != nil {
+			return err
+		}
+
 		if err := removePlugin(projectRoot, opts.Name); err != nil {
 			return err
 		}
@@ -76,19 +128,35 @@ func Remove(opts RemoveOptions) error {
 		}
 
 		output.PrintSuccessf("Plugin %q removed successfully", opts.Name)
+		output.PrintInfof("Run 'ccmd restore %s' within %s to undo", opts.Name, formatRetention(TrashRetention))
 		return nil
 	}
 
-	if err := removeCommandFiles(projectRoot, opts.Name); err != nil {
-		return err
-	}
-
 	output.PrintInfof("Will remove command %q", opts.Name)
 	output.PrintInfof("Repository: %s", cmdInfo.Source)
 	if cmdInfo.Version != "" {
 		output.PrintInfof("Version: %s", cmdInfo.Version)
 	}
 
+	if opts.DryRun {
+		for _, rel := range extraTargetFiles(projectRoot, opts.Name) {
+			if fileExists(filepath.Join(projectRoot, rel)) {
+				output.PrintInfof("Would move %s to %s", rel, filepath.Join(".claude", TrashDirName))
+			}
+		}
+		mdFile, _ := filepath.Rel(projectRoot, claudeFile(projectRoot, opts.Name))
+		printRemoveDryRun(projectRoot, projectRelative(projectRoot, installedCommandDir(projectRoot, opts.Name)), mdFile, opts.UpdateFiles)
+		return nil
+	}
+
+	spec := ""
+	if opts.UpdateFiles && config != nil {
+		spec = findConfigSpec(config.Commands, opts.Name, cmdInfo.Source)
+	}
+	if _, err := trashCommand(projectRoot, opts.Name, cmdInfo, spec); err != nil {
+		return err
+	}
+
 	delete(lockFile.Commands, opts.Name)
 
 	if err := WriteLockFile(lockPath, lockFile); err != nil {
@@ -104,11 +172,32 @@ func Remove(opts RemoveOptions) error {
 	}
 
 	output.PrintSuccessf("Command %q removed successfully", opts.Name)
+	output.PrintInfof("Run 'ccmd restore %s' within %s to undo", opts.Name, formatRetention(TrashRetention))
 	return nil
 }
 
+// printRemoveDryRun reports the files a removal would touch without changing anything.
+// mdFile is the standalone file of a command, empty for plugins.
+func printRemoveDryRun(projectRoot, relPath, mdFile string, updateFiles bool) {
+	output.PrintInfof("Would move %s to %s", relPath, filepath.Join(".claude", TrashDirName))
+	if mdFile != "" && fileExists(filepath.Join(projectRoot, mdFile)) {
+		output.PrintInfof("Would move %s to %s", mdFile, filepath.Join(".claude", TrashDirName))
+	}
+	output.PrintInfof("Would update %s", LockFileName)
+	if updateFiles {
+		output.PrintInfof("Would update %s", ConfigFileName)
+	}
+	output.PrintInfof("(dry-run mode - no changes made)")
+}
+
 func removeFromConfig(projectRoot, name, repository string) error {
-	configPath := filepath.Join(projectRoot, "ccmd.yaml")
+	return removeSpecsFromConfig(projectRoot, map[string]string{name: repository}, nil)
+}
+
+// removeSpecsFromConfig drops the ccmd.yaml entries of commands and plugins, each given
+// as name -> repository, writing the file once
+func removeSpecsFromConfig(projectRoot string, commands, plugins map[string]string) error {
+	configPath := configFilePath(projectRoot)
 	if !fileExists(configPath) {
 		return nil
 	}
@@ -123,43 +212,44 @@ func removeFromConfig(projectRoot, name, repository string) error {
 		return err
 	}
 
-	commandsRaw, ok := config["commands"]
-	if !ok {
-		return nil
-	}
-
-	commands, ok := commandsRaw.([]interface{})
-	if !ok {
-		return nil
-	}
-
-	newCommands := make([]interface{}, 0, len(commands))
 	removed := false
 
-	for _, cmd := range commands {
-		cmdStr, ok := cmd.(string)
-		if !ok {
-			newCommands = append(newCommands, cmd)
-			continue
+	for name, repository := range commands {
+		if specs, ok := config["commands"].([]interface{}); ok {
+			if kept, found := withoutSpec(specs, name, repository); found {
+				config["commands"] = kept
+				removed = true
+			}
 		}
 
-		parts := strings.Split(cmdStr, "@")
-		cmdRepo := parts[0]
-
-		if cmdRepo == repository || extractCommandName(cmdRepo) == name {
-			removed = true
-			continue
+		// Profile lists hold specs in the same format
+		if profiles, ok := config["profiles"].(map[string]interface{}); ok {
+			for profile, list := range profiles {
+				specs, ok := list.([]interface{})
+				if !ok {
+					continue
+				}
+				if kept, found := withoutSpec(specs, name, repository); found {
+					profiles[profile] = kept
+					removed = true
+				}
+			}
 		}
+	}
 
-		newCommands = append(newCommands, cmd)
+	for name, repository := range plugins {
+		if specs, ok := config["plugins"].([]interface{}); ok {
+			if kept, found := withoutPluginSpec(specs, name, repository); found {
+				config["plugins"] = kept
+				removed = true
+			}
+		}
 	}
 
 	if !removed {
 		return nil
 	}
 
-	config["commands"] = newCommands
-
 	output, err := yaml.Marshal(config)
 	if err != nil {
 		return err
@@ -183,23 +273,92 @@ func ListCommands(projectPath string) ([]string, error) {
 	return names, nil
 }
 
-func removeCommandFiles(projectRoot, name string) error {
-	commandDir := filepath.Join(projectRoot, ".claude", "commands", name)
-	mdFile := filepath.Join(projectRoot, ".claude", "commands", name+".md")
+// removeCommandFiles removes an installed command and its standalone files, except the
+// standalone files keep records as local overrides or that were edited by hand
+func removeCommandFiles(projectRoot, name string, keep map[string]*LockStandalone) error {
+	commandDir := installedCommandDir(projectRoot, name)
+	mdFile := claudeFile(projectRoot, name)
 
 	if dirExists(commandDir) {
-		output.PrintInfof("Removing command directory...")
-		if err := os.RemoveAll(commandDir); err != nil {
+		output.PrintVerbosef("Removing command directory...")
+		if err := fs.RemoveAll(commandDir); err != nil {
 			return errors.FileError("remove command directory", commandDir, err)
 		}
 	}
+	pruneOwnerDir(projectRoot, name)
 
-	if fileExists(mdFile) {
-		output.PrintInfof("Removing md file...")
+	mdRel, _ := filepath.Rel(projectRoot, mdFile)
+	if fileExists(mdFile) && !standaloneEdited(projectRoot, mdRel, keep) {
+		output.PrintVerbosef("Removing md file...")
 		if err := os.Remove(mdFile); err != nil {
 			output.PrintWarningf("Failed to remove .md file: %v", err)
 		}
 	}
 
+	for _, rel := range extraTargetFiles(projectRoot, name) {
+		if standaloneEdited(projectRoot, rel, keep) {
+			continue
+		}
+		path := filepath.Join(projectRoot, rel)
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			output.PrintWarningf("Failed to remove %s: %v", filepath.ToSlash(rel), err)
+		}
+		pruneTargetDir(path, name)
+	}
+
 	return nil
 }
+
+// withoutSpec returns the ccmd.yaml entries that don't refer to a command and whether
+// any entry was dropped
+func withoutSpec(specs []interface{}, name, repository string) ([]interface{}, bool) {
+	kept := make([]interface{}, 0, len(specs))
+	found := false
+
+	for _, spec := range specs {
+		specStr, ok := spec.(string)
+		if entry, isMap := spec.(map[string]interface{}); isMap {
+			// Entries with conditions keep their spec under repo
+			specStr, ok = entry["repo"].(string)
+		}
+		if !ok {
+			kept = append(kept, spec)
+			continue
+		}
+
+		specRepo, _, instance := ParseInstanceSpec(specStr)
+
+		// Side-by-side installs only match their own instance name
+		if (instance != "" && instance == name) || (instance == "" && (specRepo == repository || extractCommandName(specRepo) == name)) {
+			found = true
+			continue
+		}
+
+		kept = append(kept, spec)
+	}
+
+	return kept, found
+}
+
+// withoutPluginSpec returns the ccmd.yaml plugin entries that don't refer to a plugin and
+// whether any entry was dropped
+func withoutPluginSpec(specs []interface{}, name, repository string) ([]interface{}, bool) {
+	kept := make([]interface{}, 0, len(specs))
+	found := false
+
+	for _, spec := range specs {
+		specStr, ok := spec.(string)
+		if !ok {
+			kept = append(kept, spec)
+			continue
+		}
+		repo, _ := ParseCommandSpec(specStr)
+		if ExtractRepoPath(repo) == ExtractRepoPath(repository) || extractCommandName(repo) == name {
+			found = true
+			continue
+		}
+		kept = append(kept, spec)
+	}
+
+	return kept, found
+}
@@ -0,0 +1,225 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/gifflet/ccmd/pkg/errors"
+	"github.com/gifflet/ccmd/pkg/output"
+)
+
+// IsRemovePattern reports whether a remove argument is a glob pattern such as "acme-*"
+func IsRemovePattern(s string) bool {
+	return strings.ContainsAny(s, "*?[")
+}
+
+// ResolveRemoveNames expands the names and glob patterns given to remove into the
+// installed commands and plugins they match, sorted. Patterns match whole names, and
+// "*" does not cross the "/" of nested commands. With all, every command and plugin is
+// returned. A name or pattern that matches nothing is an error.
+func ResolveRemoveNames(projectPath string, patterns []string, all bool) ([]string, error) {
+	projectRoot, err := findProjectRootFrom(projectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	lockPath := lockFilePath(projectRoot)
+	if !fileExists(lockPath) {
+		return nil, errors.NotFound("no commands installed (ccmd-lock.yaml not found)")
+	}
+	lockFile, err := ReadLockFile(lockPath)
+	if err != nil {
+		return nil, err
+	}
+
+	installed := make([]string, 0, len(lockFile.Commands)+len(lockFile.Plugins))
+	for name := range lockFile.Commands {
+		installed = append(installed, name)
+	}
+	for name := range lockFile.Plugins {
+		installed = append(installed, name)
+	}
+	sort.Strings(installed)
+	if all {
+		return installed, nil
+	}
+
+	seen := make(map[string]bool)
+	names := []string{}
+	for _, pattern := range patterns {
+		matched := false
+		for _, name := range installed {
+			ok := name == pattern
+			if !ok && IsRemovePattern(pattern) {
+				if ok, err = path.Match(pattern, name); err != nil {
+					return nil, errors.InvalidInput(fmt.Sprintf("invalid pattern %q: %v", pattern, err))
+				}
+			}
+			if !ok {
+				continue
+			}
+			matched = true
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+		if !matched {
+			if IsRemovePattern(pattern) {
+				return nil, errors.NotFound(fmt.Sprintf("command or plugin matching %q", pattern))
+			}
+			return nil, errors.NotFound(fmt.Sprintf("command or plugin %q", pattern))
+		}
+	}
+
+	sort.Strings(names)
+	return names, nil
+}
+
+// removeMany removes the commands and plugins matched by opts.Names, or all of them with
+// opts.All, recording each removal in the audit log
+func removeMany(opts RemoveOptions) error {
+	if opts.Name != "" {
+		return errors.InvalidInput("use either a single name or names and patterns")
+	}
+
+	projectRoot, err := findProjectRootFrom(opts.ProjectPath)
+	if err != nil {
+		return err
+	}
+	names, err := ResolveRemoveNames(projectRoot, opts.Names, opts.All)
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		output.PrintInfof("No commands or plugins installed")
+		return nil
+	}
+
+	if opts.DryRun {
+		for _, name := range names {
+			if err := remove(RemoveOptions{Name: name, UpdateFiles: opts.UpdateFiles, DryRun: true}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	defer snapshotBefore(projectRoot, AuditRemove)()
+
+	// Capture the lock entries before they are deleted
+	events := make([]AuditEvent, len(names))
+	for i, name := range names {
+		events[i] = auditLockEntry(projectRoot, AuditEvent{Action: AuditRemove, Name: name})
+	}
+	err = removeAll(projectRoot, names, opts.UpdateFiles)
+	for _, event := range events {
+		recordAudit(projectRoot, auditResult(event, err))
+	}
+	return err
+}
+
+// removeAll moves the files of several commands and plugins to the trash, then writes
+// ccmd-lock.yaml and, with updateFiles, ccmd.yaml once. When a removal fails, the files
+// already moved are put back and neither file is changed.
+func removeAll(projectRoot string, names []string, updateFiles bool) error {
+	lockPath := lockFilePath(projectRoot)
+	lockFile, err := ReadLockFile(lockPath)
+	if err != nil {
+		return err
+	}
+
+	var config *ProjectConfig
+	if updateFiles && ProjectConfigExists(projectRoot) {
+		config, _ = LoadProjectConfig(projectRoot)
+	}
+
+	commands := make(map[string]string)
+	plugins := make(map[string]string)
+	var trashed []*TrashEntry
+	for _, name := range names {
+		var entry *TrashEntry
+		if cmd, ok := lockFile.Commands[name]; ok {
+			spec := ""
+			if config != nil {
+				spec = findConfigSpec(config.Commands, name, cmd.Source)
+			}
+			entry, err = trashCommand(projectRoot, name, cmd, spec)
+			commands[name] = cmd.Source
+		} else {
+			plugin := lockFile.Plugins[name]
+			spec := ""
+			if config != nil {
+				spec = findConfigSpec(config.Plugins, name, plugin.Source)
+			}
+			entry, err = trashPlugin(projectRoot, name, plugin, spec)
+			plugins[name] = plugin.Source
+		}
+		if entry != nil {
+			trashed = append(trashed, entry)
+		}
+		if err != nil {
+			untrash(projectRoot, trashed)
+			return fmt.Errorf("failed to remove %s, nothing was removed: %w", name, err)
+		}
+		output.PrintVerbosef("Moved %s to the trash", name)
+	}
+
+	for name := range plugins {
+		if err := removePluginFiles(projectRoot, name); err != nil {
+			output.PrintWarningf("Failed to unregister plugin %s: %v", name, err)
+		}
+		delete(lockFile.Plugins, name)
+	}
+	for name := range commands {
+		delete(lockFile.Commands, name)
+	}
+	if err := WriteLockFile(lockPath, lockFile); err != nil {
+		untrash(projectRoot, trashed)
+		return err
+	}
+
+	if updateFiles {
+		if err := removeSpecsFromConfig(projectRoot, commands, plugins); err != nil {
+			output.PrintWarningf("Failed to update ccmd.yaml: %v", err)
+		} else {
+			output.PrintInfof("Updated ccmd.yaml")
+		}
+	}
+
+	output.PrintSuccessf("Removed %d command(s) and plugin(s): %s", len(names), strings.Join(names, ", "))
+	output.PrintInfof("Run 'ccmd restore <name>' within %s to undo", formatRetention(TrashRetention))
+	return nil
+}
+
+// untrash moves the files of trashed entries back, newest first, leaving the lock file
+// and ccmd.yaml alone
+func untrash(projectRoot string, trashed []*TrashEntry) {
+	for i := len(trashed) - 1; i >= 0; i-- {
+		entry := *trashed[i]
+		entry.Command, entry.Plugin, entry.Spec = nil, nil, ""
+
+		var err error
+		if entry.Type == trashTypePlugin {
+			err = restorePlugin(projectRoot, &entry)
+		} else {
+			err = restoreCommand(projectRoot, &entry)
+		}
+		if err != nil {
+			output.PrintWarningf("Failed to put %s back: %v", entry.Name, err)
+			continue
+		}
+		discardTrashEntry(&entry)
+	}
+}
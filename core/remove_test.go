@@ -18,6 +18,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"gopkg.in/yaml.v3"
+
+	"github.com/gifflet/ccmd/pkg/errors"
 )
 
 func TestRemove(t *testing.T) {
@@ -170,6 +172,28 @@ func TestRemove(t *testing.T) {
 		assert.Equal(t, "user/keep-cmd", commands[0])
 	})
 
+	t.Run("dry run leaves files untouched", func(t *testing.T) {
+		cleanup := setupTestDir(t)
+		defer cleanup()
+
+		lockFile := createBasicLockFile()
+		lockFile.Commands["test-cmd"] = createTestLockCommand("test-cmd", "1.0.0", "https://github.com/user/test-cmd.git")
+		writeLockFile(t, lockFile)
+		createCommandStructure(t, "test-cmd")
+
+		err := Remove(RemoveOptions{
+			Name:   "test-cmd",
+			DryRun: true,
+		})
+		require.NoError(t, err)
+
+		updatedLock := readLockFile(t)
+		assert.NotNil(t, updatedLock.Commands["test-cmd"])
+		assert.True(t, dirExists(filepath.Join(".claude", "commands", "test-cmd")))
+		assert.True(t, fileExists(filepath.Join(".claude", "commands", "test-cmd.md")))
+		assert.False(t, dirExists(filepath.Join(".claude", TrashDirName)))
+	})
+
 	t.Run("requires command name", func(t *testing.T) {
 		err := Remove(RemoveOptions{})
 		assert.Error(t, err)
@@ -242,6 +266,30 @@ func TestRemoveFromConfig(t *testing.T) {
 		assert.Equal(t, "user/keep-cmd", commands[0])
 	})
 
+	t.Run("removes command from profiles", func(t *testing.T) {
+		tempDir := t.TempDir()
+
+		config := map[string]interface{}{
+			"commands": []interface{}{"user/keep-cmd"},
+			"profiles": map[string]interface{}{
+				"backend": []interface{}{"user/test-cmd@^1.0.0", "user/api"},
+				"docs":    []interface{}{"user/test-cmd"},
+			},
+		}
+		data, err := yaml.Marshal(config)
+		require.NoError(t, err)
+		configPath := filepath.Join(tempDir, "ccmd.yaml")
+		require.NoError(t, os.WriteFile(configPath, data, 0644))
+
+		require.NoError(t, removeFromConfig(tempDir, "test-cmd", "https://github.com/user/test-cmd.git"))
+
+		loaded, err := LoadProjectConfig(tempDir)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"user/keep-cmd"}, loaded.Commands)
+		assert.Equal(t, []string{"user/api"}, loaded.Profiles["backend"])
+		assert.Empty(t, loaded.Profiles["docs"])
+	})
+
 	t.Run("handles missing config file", func(t *testing.T) {
 		tempDir := t.TempDir()
 
@@ -312,3 +360,89 @@ func TestListCommands(t *testing.T) {
 		assert.Empty(t, names)
 	})
 }
+
+func TestRemoveMany(t *testing.T) {
+	setup := func(t *testing.T) {
+		lockFile := createBasicLockFile()
+		for _, name := range []string{"acme-one", "acme-two", "keep-cmd"} {
+			lockFile.Commands[name] = createTestLockCommand(name, "1.0.0", "https://github.com/user/"+name+".git")
+			createCommandStructure(t, name)
+		}
+		writeLockFile(t, lockFile)
+		writeConfig(t, []string{
+			"https://github.com/user/acme-one.git@1.0.0",
+			"https://github.com/user/acme-two.git@1.0.0",
+			"https://github.com/user/keep-cmd.git@1.0.0",
+		})
+	}
+
+	t.Run("removes the commands matching a pattern", func(t *testing.T) {
+		cleanup := setupTestDir(t)
+		defer cleanup()
+		setup(t)
+
+		names, err := ResolveRemoveNames(".", []string{"acme-*", "acme-one"}, false)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"acme-one", "acme-two"}, names)
+
+		require.NoError(t, Remove(RemoveOptions{Names: []string{"acme-*"}, UpdateFiles: true}))
+
+		lockFile := readLockFile(t)
+		assert.Len(t, lockFile.Commands, 1)
+		assert.NotNil(t, lockFile.Commands["keep-cmd"])
+		assert.NoDirExists(t, filepath.Join(".claude", "commands", "acme-one"))
+		assert.NoDirExists(t, filepath.Join(".claude", "commands", "acme-two"))
+		assert.DirExists(t, filepath.Join(".claude", "commands", "keep-cmd"))
+
+		config, err := LoadProjectConfig(".")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"https://github.com/user/keep-cmd.git@1.0.0"}, config.Commands)
+
+		// Each removal can be restored on its own
+		entries, err := ListTrash(".")
+		require.NoError(t, err)
+		assert.Len(t, entries, 2)
+		require.NoError(t, Restore(RestoreOptions{Name: "acme-two"}))
+		assert.NotNil(t, readLockFile(t).Commands["acme-two"])
+	})
+
+	t.Run("removes nothing when a name matches nothing", func(t *testing.T) {
+		cleanup := setupTestDir(t)
+		defer cleanup()
+		setup(t)
+
+		err := Remove(RemoveOptions{Names: []string{"acme-one", "other-*"}})
+		assert.ErrorIs(t, err, errors.ErrNotFound)
+		assert.Len(t, readLockFile(t).Commands, 3)
+		assert.DirExists(t, filepath.Join(".claude", "commands", "acme-one"))
+	})
+
+	t.Run("removes everything with all", func(t *testing.T) {
+		cleanup := setupTestDir(t)
+		defer cleanup()
+		setup(t)
+
+		require.NoError(t, Remove(RemoveOptions{All: true, UpdateFiles: true}))
+		assert.Empty(t, readLockFile(t).Commands)
+		config, err := LoadProjectConfig(".")
+		require.NoError(t, err)
+		assert.Empty(t, config.Commands)
+	})
+
+	t.Run("puts trashed commands back when a removal fails", func(t *testing.T) {
+		cleanup := setupTestDir(t)
+		defer cleanup()
+		setup(t)
+
+		trashed, err := trashCommand(".", "acme-one", nil, "")
+		require.NoError(t, err)
+		assert.NoDirExists(t, filepath.Join(".claude", "commands", "acme-one"))
+
+		untrash(".", []*TrashEntry{trashed})
+		assert.DirExists(t, filepath.Join(".claude", "commands", "acme-one"))
+		assert.FileExists(t, filepath.Join(".claude", "commands", "acme-one.md"))
+		entries, err := ListTrash(".")
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+}
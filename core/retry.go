@@ -0,0 +1,234 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/gifflet/ccmd/pkg/config"
+	"github.com/gifflet/ccmd/pkg/logger"
+	"github.com/gifflet/ccmd/pkg/output"
+)
+
+const (
+	// retryBaseDelay is the wait before the first retry; it doubles with every attempt
+	retryBaseDelay = time.Second
+	// retryMaxDelay caps the backoff between two attempts
+	retryMaxDelay = 30 * time.Second
+	// retryMaxWait is the longest Retry-After or rate limit reset ccmd waits for; longer
+	// waits fail right away so a sync does not hang for an hour
+	retryMaxWait = 2 * time.Minute
+)
+
+// gitTransientErrors are git messages of failures worth retrying: DNS, connection and
+// server errors, and rate limits. Authentication and missing repositories are final.
+var gitTransientErrors = []string{
+	"could not resolve host",
+	"connection timed out",
+	"connection reset",
+	"connection refused",
+	"failed to connect",
+	"operation timed out",
+	"early eof",
+	"the remote end hung up unexpectedly",
+	"rpc failed",
+	"unexpected disconnect",
+	"tls handshake timeout",
+	"gnutls recv error",
+	"ssl_read",
+	"returned error: 429",
+	"returned error: 500",
+	"returned error: 502",
+	"returned error: 503",
+	"returned error: 504",
+	"rate limit",
+}
+
+// retryableError marks a failure worth retrying. after is the wait the server asked
+// for, zero when the backoff decides.
+type retryableError struct {
+	err   error
+	after time.Duration
+}
+
+func (e *retryableError) Error() string { return e.err.Error() }
+func (e *retryableError) Unwrap() error { return e.err }
+
+// retrySleep waits for d or until ctx is done. Tests replace it to run without delays.
+var retrySleep = func(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// withRetry runs op until it succeeds, fails with an error that is not transient, or
+// the configured number of attempts (retry_attempts) is used up. Waits grow
+// exponentially with jitter, and follow Retry-After when the server sends it.
+func withRetry(ctx context.Context, what string, op func() error) error {
+	attempts := retryAttempts(ctx)
+
+	for attempt := 1; ; attempt++ {
+		err := op()
+		if err != nil {
+			logger.WithFields(logger.Fields{"operation": what, "attempt": attempt, "attempts": attempts}).
+				WithError(err).Debug("Operation failed")
+		}
+		if err == nil || ctx.Err() != nil || attempt >= attempts || !isTransient(err) {
+			return err
+		}
+
+		delay := backoffDelay(attempt)
+		var retryable *retryableError
+		if stderrors.As(err, &retryable) && retryable.after > 0 {
+			if retryable.after > retryMaxWait {
+				return fmt.Errorf("%w (retry possible in %s)", err, retryable.after.Round(time.Second))
+			}
+			delay = retryable.after
+		}
+
+		output.PrintWarningf("%s failed: %s; retrying in %s (attempt %d of %d)...",
+			what, transientReason(err), delay.Round(100*time.Millisecond), attempt+1, attempts)
+		if err := retrySleep(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+// projectRootKey is the context key of the project root an operation works on
+type projectRootKey struct{}
+
+// withProjectRoot returns a context whose retries use the settings of the project at
+// projectRoot rather than those of the project around the working directory
+func withProjectRoot(ctx context.Context, projectRoot string) context.Context {
+	return context.WithValue(ctx, projectRootKey{}, projectRoot)
+}
+
+// retryAttempts returns the configured number of attempts, at least one
+func retryAttempts(ctx context.Context) int {
+	projectRoot, ok := ctx.Value(projectRootKey{}).(string)
+	if !ok {
+		projectRoot, _ = findProjectRoot()
+	}
+	settings, err := config.Load(projectRoot)
+	if err != nil || settings.RetryAttempts < 1 {
+		return 1
+	}
+	return settings.RetryAttempts
+}
+
+// backoffDelay returns the wait after the given failed attempt: half of the exponential
+// delay plus a random share of the other half, so parallel retries spread out
+func backoffDelay(attempt int) time.Duration {
+	delay := retryBaseDelay << (attempt - 1)
+	if delay > retryMaxDelay || delay <= 0 {
+		delay = retryMaxDelay
+	}
+	half := delay / 2
+	return half + rand.N(half+1)
+}
+
+// isTransient reports whether an error is worth retrying
+func isTransient(err error) bool {
+	var retryable *retryableError
+	if stderrors.As(err, &retryable) {
+		return true
+	}
+	if stderrors.Is(err, context.Canceled) {
+		return false
+	}
+
+	// Every *url.Error is a net.Error, so only timeouts, temporary DNS failures
+	// (EAI_AGAIN) and dropped or refused connections count; certificate errors do not.
+	// Timeouts include HTTP client deadlines; withRetry stops once its own context is done.
+	var netErr net.Error
+	if stderrors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	var dnsErr *net.DNSError
+	if stderrors.As(err, &dnsErr) && dnsErr.IsTemporary {
+		return true
+	}
+	if stderrors.Is(err, syscall.ECONNRESET) || stderrors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+
+	message := strings.ToLower(err.Error())
+	for _, pattern := range gitTransientErrors {
+		if strings.Contains(message, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkHTTPStatus turns rate limit and server error responses into retryable errors,
+// with the wait from Retry-After or GitHub's rate limit reset
+func checkHTTPStatus(resp *http.Response, err error) error {
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests,
+		resp.StatusCode == http.StatusForbidden && resp.Header.Get("X-RateLimit-Remaining") == "0":
+		return &retryableError{err: fmt.Errorf("%w (rate limited)", err), after: retryAfter(resp, time.Now())}
+	case resp.StatusCode == http.StatusBadGateway, resp.StatusCode == http.StatusServiceUnavailable,
+		resp.StatusCode == http.StatusGatewayTimeout, resp.StatusCode == http.StatusInternalServerError:
+		return &retryableError{err: err, after: retryAfter(resp, time.Now())}
+	default:
+		return err
+	}
+}
+
+// retryAfter reads the wait a server asks for from Retry-After (seconds or an HTTP date)
+// or X-RateLimit-Reset (Unix time). It returns zero when there is none.
+func retryAfter(resp *http.Response, now time.Time) time.Duration {
+	if value := resp.Header.Get("Retry-After"); value != "" {
+		if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+			return time.Duration(seconds) * time.Second
+		}
+		if at, err := http.ParseTime(value); err == nil && at.After(now) {
+			return at.Sub(now)
+		}
+	}
+	if value := resp.Header.Get("X-RateLimit-Reset"); value != "" {
+		if reset, err := strconv.ParseInt(value, 10, 64); err == nil {
+			if at := time.Unix(reset, 0); at.After(now) {
+				return at.Sub(now)
+			}
+		}
+	}
+	return 0
+}
+
+// transientReason returns the line of an error message that made it transient, e.g.
+// git's "Could not resolve host" rather than "exit status 128"
+func transientReason(err error) string {
+	lines := strings.Split(strings.TrimSpace(err.Error()), "\n")
+	for _, line := range lines {
+		lower := strings.ToLower(line)
+		for _, pattern := range gitTransientErrors {
+			if strings.Contains(lower, pattern) {
+				return strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "Output:"))
+			}
+		}
+	}
+	return lines[0]
+}
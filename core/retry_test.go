@@ -0,0 +1,172 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"bytes"
+	"context"
+	stderrors "errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gifflet/ccmd/pkg/config"
+	"github.com/gifflet/ccmd/pkg/output"
+)
+
+// stubRetries sets the number of attempts, records the waits instead of sleeping and
+// captures the progress messages
+func stubRetries(t *testing.T, attempts int) (*[]time.Duration, *bytes.Buffer) {
+	t.Helper()
+
+	t.Setenv(config.ConfigEnv, filepath.Join(t.TempDir(), "config.yaml"))
+	t.Setenv("CCMD_RETRY_ATTEMPTS", fmt.Sprint(attempts))
+
+	var delays []time.Duration
+	previous := retrySleep
+	retrySleep = func(_ context.Context, d time.Duration) error {
+		delays = append(delays, d)
+		return nil
+	}
+
+	var out bytes.Buffer
+	restore := output.SetOutput(&out, &out)
+	t.Cleanup(func() {
+		retrySleep = previous
+		restore()
+	})
+	return &delays, &out
+}
+
+func TestWithRetry(t *testing.T) {
+	delays, out := stubRetries(t, 3)
+
+	calls := 0
+	err := withRetry(context.Background(), "Clone of repo", func() error {
+		calls++
+		if calls < 3 {
+			return fmt.Errorf("git clone failed: exit status 128\nOutput: fatal: Could not resolve host: github.com")
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 3, calls)
+	require.Len(t, *delays, 2)
+	assert.GreaterOrEqual(t, (*delays)[0], retryBaseDelay/2)
+	assert.LessOrEqual(t, (*delays)[0], retryBaseDelay)
+	assert.GreaterOrEqual(t, (*delays)[1], retryBaseDelay)
+	assert.Contains(t, out.String(), "Clone of repo failed: fatal: Could not resolve host: github.com; retrying in")
+	assert.Contains(t, out.String(), "(attempt 3 of 3)")
+}
+
+func TestWithRetryStops(t *testing.T) {
+	delays, _ := stubRetries(t, 3)
+
+	calls := 0
+	permanent := stderrors.New("fatal: Authentication failed for 'https://github.com/acme/private/'")
+	err := withRetry(context.Background(), "Clone", func() error {
+		calls++
+		return permanent
+	})
+	assert.Equal(t, permanent, err)
+	assert.Equal(t, 1, calls, "authentication failures are not retried")
+
+	calls = 0
+	err = withRetry(context.Background(), "Clone", func() error {
+		calls++
+		return stderrors.New("early EOF")
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 3, calls, "gives up after the configured attempts")
+
+	*delays = nil
+	calls = 0
+	err = withRetry(context.Background(), "Request", func() error {
+		calls++
+		return &retryableError{err: stderrors.New("rate limited"), after: time.Hour}
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "retry possible in 1h0m0s")
+	assert.Equal(t, 1, calls, "waits beyond the limit fail right away")
+	assert.Empty(t, *delays)
+}
+
+func TestIsTransientNetworkErrors(t *testing.T) {
+	// A server whose certificate the client does not trust
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	_, err := http.Get(server.URL)
+	require.Error(t, err)
+	assert.False(t, isTransient(err), "certificate errors are not retried: %v", err)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	require.NoError(t, listener.Close())
+	_, err = http.Get("http://" + addr)
+	require.Error(t, err)
+	assert.True(t, isTransient(err), "refused connections are retried: %v", err)
+
+	client := &http.Client{Timeout: time.Nanosecond}
+	_, err = client.Get(server.URL)
+	require.Error(t, err)
+	assert.True(t, isTransient(err), "timeouts are retried: %v", err)
+
+	assert.True(t, isTransient(&net.DNSError{Err: "try again", Name: "github.com", IsTemporary: true}))
+	assert.False(t, isTransient(&net.DNSError{Err: "no such host", Name: "github.invalid", IsNotFound: true}))
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	header := func(key, value string) *http.Response {
+		return &http.Response{Header: http.Header{key: []string{value}}}
+	}
+
+	assert.Equal(t, 5*time.Second, retryAfter(header("Retry-After", "5"), now))
+	assert.Equal(t, 10*time.Second, retryAfter(header("Retry-After", now.Add(10*time.Second).Format(http.TimeFormat)), now))
+	assert.Equal(t, 30*time.Second, retryAfter(header("X-Ratelimit-Reset", fmt.Sprint(now.Add(30*time.Second).Unix())), now))
+	assert.Zero(t, retryAfter(&http.Response{Header: http.Header{}}, now))
+}
+
+func TestDownloadRetriesRateLimit(t *testing.T) {
+	delays, _ := stubRetries(t, 3)
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.Header().Set("Retry-After", "7")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write([]byte("# Hello\n"))
+	}))
+	defer server.Close()
+
+	data, err := download(context.Background(), server.URL+"/hello.md", "markdown file", MaxMarkdownSize)
+	require.NoError(t, err)
+	assert.Equal(t, "# Hello\n", string(data))
+	assert.Equal(t, []time.Duration{7 * time.Second}, *delays)
+
+	// Client errors are final
+	requests.Store(0)
+	notFound := httptest.NewServer(http.NotFoundHandler())
+	defer notFound.Close()
+	_, err = download(context.Background(), notFound.URL+"/missing.md", "markdown file", MaxMarkdownSize)
+	assert.Error(t, err)
+	assert.Len(t, *delays, 1)
+}
@@ -0,0 +1,181 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/gifflet/ccmd/pkg/config"
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+// sandboxNetworkProfile is the sandbox-exec profile denying network access on macOS
+const sandboxNetworkProfile = "(version 1)(allow default)(deny network*)"
+
+// unshareArgs run a program in new user and network namespaces, which hold only a
+// loopback interface that is down
+var unshareArgs = []string{"--user", "--map-root-user", "--net", "--"}
+
+// sandbox runs a script with a temporary HOME, resource limits and, where supported,
+// without network access
+type sandbox struct {
+	home       string
+	removeHome func()
+	wrapper    []string
+}
+
+// sandboxSettings returns the sandbox settings of a project. The project config can
+// only tighten the policy of the user config and the environment: it can enable the
+// sandbox, take network access away and lower limits, but not the reverse.
+func sandboxSettings(projectRoot string) (config.SandboxSettings, error) {
+	user, err := config.Load("")
+	if err != nil {
+		return config.SandboxSettings{}, err
+	}
+	project, err := config.Load(projectRoot)
+	if err != nil {
+		return config.SandboxSettings{}, err
+	}
+
+	base, local := user.Sandbox, project.Sandbox
+	return config.SandboxSettings{
+		Enabled:       base.Enabled || local.Enabled,
+		Network:       base.Network && local.Network,
+		MaxMemoryMB:   minLimit(base.MaxMemoryMB, local.MaxMemoryMB),
+		MaxCPUSeconds: minLimit(base.MaxCPUSeconds, local.MaxCPUSeconds),
+		MaxOpenFiles:  minLimit(base.MaxOpenFiles, local.MaxOpenFiles),
+	}, nil
+}
+
+// minLimit returns the stricter of two limits, where 0 means no limit
+func minLimit(a, b int) int {
+	if a <= 0 {
+		return b
+	}
+	if b <= 0 {
+		return a
+	}
+	return min(a, b)
+}
+
+// newSandbox prepares a sandbox: it creates the temporary HOME and finds the tool that
+// takes the network away. A platform that cannot take it away is an error unless
+// settings.Network allows network access.
+func newSandbox(ctx context.Context, projectRoot string, settings config.SandboxSettings) (*sandbox, error) {
+	if runtime.GOOS == "windows" {
+		return nil, errors.InvalidInput("the exec sandbox is not supported on Windows")
+	}
+
+	var wrapper []string
+	if !settings.Network {
+		var err error
+		if wrapper, err = networkWrapper(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	if limits := sandboxLimits(settings); limits != "" {
+		wrapper = append(wrapper, "/bin/sh", "-c", limits+`exec "$0" "$@"`)
+	}
+
+	home, removeHome, err := newTempDir(projectRoot, "exec-home")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Mkdir(filepath.Join(home, "tmp"), 0o700); err != nil {
+		removeHome()
+		return nil, errors.FileError("create sandbox home", home, err)
+	}
+
+	return &sandbox{home: home, removeHome: removeHome, wrapper: wrapper}, nil
+}
+
+// networkWrapper returns the command prefix that runs a program without network access:
+// unshare on Linux and sandbox-exec on macOS
+func networkWrapper(ctx context.Context) ([]string, error) {
+	unavailable := func(reason string) error {
+		return errors.InvalidInput(fmt.Sprintf("the exec sandbox cannot disable network access: %s; "+
+			"set sandbox.network to true to run scripts with network access", reason))
+	}
+
+	switch runtime.GOOS {
+	case "linux":
+		unshare, err := exec.LookPath("unshare")
+		if err != nil {
+			return nil, unavailable("unshare was not found")
+		}
+		// User namespaces can be disabled by the kernel or a container runtime
+		probe := append(append([]string{}, unshareArgs...), "true")
+		if out, err := exec.CommandContext(ctx, unshare, probe...).CombinedOutput(); err != nil {
+			return nil, unavailable(fmt.Sprintf("unshare failed: %s", strings.TrimSpace(string(out))))
+		}
+		return append([]string{unshare}, unshareArgs...), nil
+	case "darwin":
+		sandboxExec, err := exec.LookPath("sandbox-exec")
+		if err != nil {
+			return nil, unavailable("sandbox-exec was not found")
+		}
+		return []string{sandboxExec, "-p", sandboxNetworkProfile}, nil
+	default:
+		return nil, unavailable("not supported on " + runtime.GOOS)
+	}
+}
+
+// sandboxLimits returns the ulimit calls applying the resource limits of settings, each
+// followed by && so a limit that cannot be set stops the script
+func sandboxLimits(settings config.SandboxSettings) string {
+	var limits strings.Builder
+	if settings.MaxMemoryMB > 0 {
+		fmt.Fprintf(&limits, "ulimit -v %d && ", settings.MaxMemoryMB*1024)
+	}
+	if settings.MaxCPUSeconds > 0 {
+		fmt.Fprintf(&limits, "ulimit -t %d && ", settings.MaxCPUSeconds)
+	}
+	if settings.MaxOpenFiles > 0 {
+		fmt.Fprintf(&limits, "ulimit -n %d && ", settings.MaxOpenFiles)
+	}
+	return limits.String()
+}
+
+// command returns the program and arguments running script with args in the sandbox
+func (s *sandbox) command(script string, args []string) (string, []string) {
+	if len(s.wrapper) == 0 {
+		return script, args
+	}
+	argv := append(append(append([]string{}, s.wrapper[1:]...), script), args...)
+	return s.wrapper[0], argv
+}
+
+// env replaces HOME and TMPDIR in env with the temporary HOME and sets CCMD_SANDBOX
+func (s *sandbox) env(env []string) []string {
+	out := make([]string, 0, len(env)+3)
+	for _, entry := range env {
+		name, _, _ := strings.Cut(entry, "=")
+		if name != "HOME" && name != "TMPDIR" && name != "CCMD_SANDBOX" {
+			out = append(out, entry)
+		}
+	}
+	return append(out,
+		"HOME="+s.home,
+		"TMPDIR="+filepath.Join(s.home, "tmp"),
+		"CCMD_SANDBOX=1",
+	)
+}
+
+// cleanup removes the temporary HOME
+func (s *sandbox) cleanup() {
+	s.removeHome()
+}
@@ -0,0 +1,115 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gifflet/ccmd/pkg/config"
+)
+
+func TestSandboxLimits(t *testing.T) {
+	assert.Equal(t, "", sandboxLimits(config.SandboxSettings{}))
+	assert.Equal(t, "ulimit -v 524288 && ulimit -t 60 && ulimit -n 64 && ",
+		sandboxLimits(config.SandboxSettings{MaxMemoryMB: 512, MaxCPUSeconds: 60, MaxOpenFiles: 64}))
+}
+
+func TestSandboxSettingsProjectOnlyTightens(t *testing.T) {
+	project := t.TempDir()
+	userConfig := filepath.Join(t.TempDir(), "config.yaml")
+	t.Setenv("CCMD_CONFIG", userConfig)
+	require.NoError(t, os.WriteFile(userConfig, []byte(
+		"sandbox:\n  enabled: true\n  max_memory_mb: 1024\n  max_open_files: 0\n"), 0644))
+	require.NoError(t, os.WriteFile(config.ProjectConfigPath(project), []byte(
+		"sandbox:\n  enabled: false\n  network: true\n  max_memory_mb: 4096\n  max_cpu_seconds: 0\n  max_open_files: 32\n"), 0644))
+
+	settings, err := sandboxSettings(project)
+	require.NoError(t, err)
+	assert.Equal(t, config.SandboxSettings{
+		Enabled:       true,
+		Network:       false,
+		MaxMemoryMB:   1024,
+		MaxCPUSeconds: 300,
+		MaxOpenFiles:  32,
+	}, settings)
+
+	require.NoError(t, os.WriteFile(config.ProjectConfigPath(project), []byte("sandbox: [\n"), 0644))
+	_, err = sandboxSettings(project)
+	assert.Error(t, err)
+}
+
+func TestSandboxEnv(t *testing.T) {
+	box := &sandbox{home: "/tmp/ccmd-exec-home-1"}
+	assert.Equal(t, []string{
+		"PATH=/usr/bin",
+		"HOME=/tmp/ccmd-exec-home-1",
+		"TMPDIR=/tmp/ccmd-exec-home-1/tmp",
+		"CCMD_SANDBOX=1",
+	}, box.env([]string{"HOME=/home/dev", "PATH=/usr/bin", "TMPDIR=/var/tmp"}))
+
+	name, args := box.command("/cmd/run.sh", []string{"a"})
+	assert.Equal(t, "/cmd/run.sh", name)
+	assert.Equal(t, []string{"a"}, args)
+
+	box.wrapper = []string{"/usr/bin/unshare", "--net", "--"}
+	name, args = box.command("/cmd/run.sh", []string{"a"})
+	assert.Equal(t, "/usr/bin/unshare", name)
+	assert.Equal(t, []string{"--net", "--", "/cmd/run.sh", "a"}, args)
+}
+
+func TestExecSandbox(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("network isolation is tested with unshare")
+	}
+	if _, err := networkWrapper(context.Background()); err != nil {
+		t.Skip(err)
+	}
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	t.Setenv("CCMD_SANDBOX_MAX_OPEN_FILES", "64")
+
+	writeExecCommand(t, "bin/run.sh", `#!/bin/sh
+echo "home: $HOME"
+echo "sandbox: $CCMD_SANDBOX"
+echo "files: $(ulimit -n)"
+echo "interfaces: $(grep -c : /proc/net/dev)"
+`)
+
+	var stdout bytes.Buffer
+	require.NoError(t, Exec(context.Background(), ExecOptions{ProjectPath: ".", Name: "tool", Sandbox: true, Stdout: &stdout}))
+	assert.Contains(t, stdout.String(), "sandbox: 1\n")
+	assert.Contains(t, stdout.String(), "files: 64\n")
+	// Only the loopback interface is left
+	assert.Contains(t, stdout.String(), "interfaces: 1\n")
+
+	home, _, _ := strings.Cut(strings.TrimPrefix(stdout.String(), "home: "), "\n")
+	assert.Contains(t, home, "ccmd-exec-home-")
+	_, err := os.Stat(home)
+	assert.True(t, os.IsNotExist(err), "the sandbox home is removed")
+
+	// sandbox.network keeps the interfaces of the host
+	t.Setenv("CCMD_SANDBOX_NETWORK", "true")
+	t.Setenv("CCMD_SANDBOX_ENABLED", "true")
+	stdout.Reset()
+	require.NoError(t, Exec(context.Background(), ExecOptions{ProjectPath: ".", Name: "tool", Stdout: &stdout}))
+	assert.Contains(t, stdout.String(), "sandbox: 1\n")
+	if dev, err := os.ReadFile("/proc/net/dev"); err == nil && strings.Count(string(dev), ":") > 1 {
+		assert.NotContains(t, stdout.String(), "interfaces: 1\n")
+	}
+}
@@ -0,0 +1,373 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/gifflet/ccmd/pkg/config"
+	"github.com/gifflet/ccmd/pkg/errors"
+	"github.com/gifflet/ccmd/pkg/output"
+)
+
+// SeverityOff disables a rule in a scan ruleset
+const SeverityOff = "off"
+
+// uploadFlags are the curl, wget and PowerShell options that send data
+const uploadFlags = `(\s-d\s|\s-F\s|\s-T\s|--data|--form|--upload-file|--post-data|--post-file|-Method\s+Post|-InFile)`
+
+// defaultScanRules are the patterns the security scan looks for in command content.
+// Prompts are instructions an agent follows, so a command asking to upload files or read
+// credentials is as dangerous as a script doing it.
+var defaultScanRules = []ScanRule{
+	{
+		ID:       "exfiltration-url",
+		Severity: SeverityError,
+		Pattern:  `(?i)\b(curl|wget|Invoke-WebRequest|Invoke-RestMethod|iwr|irm)\b.*(https?://.*` + uploadFlags + `|` + uploadFlags + `.*https?://)`,
+		Message:  "sends data to a remote URL",
+	},
+	{
+		ID:       "exfiltration-endpoint",
+		Severity: SeverityError,
+		Pattern:  `(?i)\b(webhook\.site|requestbin\.(com|net)|pipedream\.net|[a-z0-9-]+\.ngrok(-free)?\.(io|app)|interact\.sh|oast\.(fun|me|pro|live|site|online)|burpcollaborator\.net|hooks\.slack\.com/services|discord(app)?\.com/api/webhooks)`,
+		Message:  "refers to a request capture or webhook service",
+	},
+	{
+		ID:       "decode-exec",
+		Severity: SeverityError,
+		Pattern:  `(?i)((base64\s+(-d|-D|--decode)|xxd\s+-r)[^\n|]*\|\s*(ba|z|da)?sh\b|FromBase64String.*(Invoke-Expression|\biex\b))`,
+		Message:  "decodes and runs hidden code",
+	},
+	{
+		ID:       "hidden-characters",
+		Severity: SeverityError,
+		Pattern:  `[\x{200B}-\x{200F}\x{202A}-\x{202E}\x{2066}-\x{2069}\x{E0000}-\x{E007F}]`,
+		Message:  "contains invisible or bidirectional control characters",
+	},
+	{
+		ID:       "secret-files",
+		Severity: SeverityWarning,
+		Pattern:  "(?i)((^|[\\s'\"`/(@=])\\.env(\\.[a-z]+)?\\b|\\bid_(rsa|dsa|ecdsa|ed25519)\\b|\\.ssh/|\\.aws/credentials|\\.netrc\\b|\\.git-credentials|\\.npmrc\\b|\\.pypirc\\b|\\.docker/config\\.json|\\.kube/config)",
+		Message:  "refers to a file holding secrets",
+	},
+	{
+		ID:       "environment-dump",
+		Severity: SeverityWarning,
+		Pattern:  `(?i)(\bprintenv\b|/proc/(self|\d+)/environ|\b(Get-ChildItem|gci|dir)\s+env:)`,
+		Message:  "reads all environment variables",
+	},
+	{
+		ID:       "encoded-blob",
+		Severity: SeverityWarning,
+		Pattern:  `[A-Za-z0-9+/]{200,}={0,2}`,
+		Message:  "contains a long encoded blob",
+	},
+}
+
+// ScanRule is a pattern the security scan reports, matched against each line of the
+// text files of a command
+type ScanRule struct {
+	ID       string `yaml:"id"`
+	Severity string `yaml:"severity"` // error, warning, info, or off to disable a built-in rule
+	Pattern  string `yaml:"pattern"`
+	Message  string `yaml:"message"`
+
+	pattern *regexp.Regexp
+}
+
+// ScanReport holds the findings of the security scan for an installed command or plugin
+type ScanReport struct {
+	Name     string       `json:"name"`
+	Type     string       `json:"type"` // command or plugin
+	Findings []Diagnostic `json:"findings"`
+	Errors   int          `json:"errors"`
+	Warnings int          `json:"warnings"`
+}
+
+// ScanOptions represents options for scanning installed commands
+type ScanOptions struct {
+	ProjectPath string
+	Name        string // Scan a single command or plugin; empty scans everything installed
+}
+
+// Scan audits the content of installed commands and plugins with the security scan, the
+// same one installs run on incoming content
+func Scan(opts ScanOptions) ([]*ScanReport, error) {
+	projectRoot, err := findProjectRootFrom(opts.ProjectPath)
+	if err != nil {
+		return nil, err
+	}
+	rules, err := loadScanRules(projectRoot, scanSettings(projectRoot))
+	if err != nil {
+		return nil, err
+	}
+
+	reports := []*ScanReport{}
+	lockPath := lockFilePath(projectRoot)
+	if !fileExists(lockPath) {
+		if opts.Name != "" {
+			return nil, errors.NotFound(fmt.Sprintf("command %q", opts.Name))
+		}
+		return reports, nil
+	}
+	lockFile, err := ReadLockFile(lockPath)
+	if err != nil {
+		return nil, err
+	}
+
+	installed := make(map[string]string)
+	for name := range lockFile.Commands {
+		installed[installedPath(projectRoot, "command", name)] = name
+	}
+	for name := range lockFile.Plugins {
+		installed[installedPath(projectRoot, "plugin", name)] = name
+	}
+	paths := make([]string, 0, len(installed))
+	for path := range installed {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		name := installed[path]
+		if opts.Name != "" && name != opts.Name {
+			continue
+		}
+		kind := "command"
+		if _, ok := lockFile.Plugins[name]; ok && path == installedPath(projectRoot, "plugin", name) {
+			kind = "plugin"
+		}
+
+		findings, err := scanDir(filepath.Join(projectRoot, filepath.FromSlash(path)), rules)
+		if err != nil {
+			return nil, err
+		}
+		report := &ScanReport{Name: name, Type: kind, Findings: findings}
+		for _, finding := range findings {
+			switch finding.Severity {
+			case SeverityError:
+				report.Errors++
+			case SeverityWarning:
+				report.Warnings++
+			}
+		}
+		reports = append(reports, report)
+	}
+
+	if opts.Name != "" && len(reports) == 0 {
+		return nil, errors.NotFound(fmt.Sprintf("command %q", opts.Name))
+	}
+	return reports, nil
+}
+
+// scanSource runs the security scan on the content of a command or plugin before it is
+// installed. Findings are printed as warnings; with the block policy, error findings
+// fail the install.
+func scanSource(projectRoot, source, dir string) error {
+	settings := scanSettings(projectRoot)
+	switch settings.Policy {
+	case config.ScanOff:
+		return nil
+	case "", config.ScanWarn, config.ScanBlock:
+	default:
+		return errors.InvalidInput(fmt.Sprintf("scan.policy must be %s, %s or %s, got %q",
+			config.ScanWarn, config.ScanBlock, config.ScanOff, settings.Policy))
+	}
+
+	rules, err := loadScanRules(projectRoot, settings)
+	if err != nil {
+		return err
+	}
+	findings, err := scanDir(dir, rules)
+	if err != nil {
+		return err
+	}
+
+	blocking, reported := 0, 0
+	for _, finding := range findings {
+		switch finding.Severity {
+		case SeverityError:
+			blocking++
+			reported++
+		case SeverityWarning:
+			reported++
+		}
+	}
+	if reported > 0 {
+		output.PrintWarningf("Security scan of %s found %d issue(s):", source, reported)
+	}
+	for _, finding := range findings {
+		if finding.Severity == SeverityInfo {
+			output.PrintVerbosef("%s", finding)
+		} else {
+			output.PrintWarningf("  %s", finding)
+		}
+	}
+	if blocking > 0 && settings.Policy == config.ScanBlock {
+		return errors.Conflict(fmt.Sprintf("%s has %d suspicious finding(s), refused by scan.policy %s",
+			source, blocking, config.ScanBlock))
+	}
+	return nil
+}
+
+// scanSettings returns the scan settings of a project, the defaults when the
+// configuration cannot be loaded
+func scanSettings(projectRoot string) config.ScanSettings {
+	settings, err := config.Load(projectRoot)
+	if err != nil {
+		return config.Defaults().Scan
+	}
+	return settings.Scan
+}
+
+// loadScanRules returns the built-in rules with the ruleset file of the settings applied:
+// entries with the id of a built-in rule change it, others add a rule
+func loadScanRules(projectRoot string, settings config.ScanSettings) ([]ScanRule, error) {
+	rules := append([]ScanRule(nil), defaultScanRules...)
+
+	if settings.RulesFile != "" {
+		path := settings.RulesFile
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(projectRoot, path)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, errors.FileError("read scan rules", path, err)
+		}
+		var ruleset struct {
+			Rules []ScanRule `yaml:"rules"`
+		}
+		if err := yaml.Unmarshal(data, &ruleset); err != nil {
+			return nil, errors.InvalidInput(fmt.Sprintf("%s: %v", path, err))
+		}
+
+		for _, custom := range ruleset.Rules {
+			if custom.ID == "" {
+				return nil, errors.InvalidInput(fmt.Sprintf("%s: every rule needs an id", path))
+			}
+			i := indexScanRule(rules, custom.ID)
+			if i < 0 {
+				if custom.Pattern == "" {
+					return nil, errors.InvalidInput(fmt.Sprintf("%s: rule %s needs a pattern", path, custom.ID))
+				}
+				if custom.Severity == "" {
+					custom.Severity = SeverityWarning
+				}
+				if custom.Message == "" {
+					custom.Message = "matches rule " + custom.ID
+				}
+				rules = append(rules, custom)
+				continue
+			}
+			if custom.Severity != "" {
+				rules[i].Severity = custom.Severity
+			}
+			if custom.Pattern != "" {
+				rules[i].Pattern = custom.Pattern
+			}
+			if custom.Message != "" {
+				rules[i].Message = custom.Message
+			}
+		}
+	}
+
+	enabled := rules[:0]
+	for _, rule := range rules {
+		switch rule.Severity {
+		case SeverityOff:
+			continue
+		case SeverityError, SeverityWarning, SeverityInfo:
+		default:
+			return nil, errors.InvalidInput(fmt.Sprintf("scan rule %s: severity must be %s, %s, %s or %s, got %q",
+				rule.ID, SeverityError, SeverityWarning, SeverityInfo, SeverityOff, rule.Severity))
+		}
+		pattern, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return nil, errors.InvalidInput(fmt.Sprintf("scan rule %s: %v", rule.ID, err))
+		}
+		rule.pattern = pattern
+		enabled = append(enabled, rule)
+	}
+	return enabled, nil
+}
+
+// indexScanRule returns the position of the rule with an id, or -1
+func indexScanRule(rules []ScanRule, id string) int {
+	for i, rule := range rules {
+		if rule.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// scanDir matches the rules against every line of the text files in dir, skipping .git,
+// symbolic links, binary files and files above the lint size limit
+func scanDir(dir string, rules []ScanRule) ([]Diagnostic, error) {
+	findings := []Diagnostic{}
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Name() == ".git" {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !info.Mode().IsRegular() || info.Size() > LintMaxFileSize {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if bytes.IndexByte(data, 0) != -1 {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		scanner.Buffer(make([]byte, 64*1024), LintMaxFileSize)
+		for line := 1; scanner.Scan(); line++ {
+			text := scanner.Text()
+			for _, rule := range rules {
+				if loc := rule.pattern.FindStringIndex(text); loc != nil {
+					findings = append(findings, Diagnostic{
+						Rule:     rule.ID,
+						Severity: rule.Severity,
+						File:     filepath.ToSlash(rel),
+						Line:     line,
+						Column:   utf8.RuneCountInString(text[:loc[0]]) + 1,
+						Message:  rule.Message,
+					})
+				}
+			}
+		}
+		return scanner.Err()
+	})
+	if err != nil {
+		return nil, errors.FileError("scan", dir, err)
+	}
+	return findings, nil
+}
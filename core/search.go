@@ -19,6 +19,7 @@ type SearchOptions struct {
 	Tags    []string
 	Author  string
 	ShowAll bool
+	NoIndex bool // Read every command instead of the index
 }
 
 // SearchResult represents a command found in the search
@@ -34,7 +35,7 @@ type SearchResult struct {
 // Search searches for installed commands based on the provided options
 func Search(opts SearchOptions) ([]SearchResult, error) {
 	// Get all installed commands
-	commands, err := List(ListOptions{})
+	commands, err := List(ListOptions{NoIndex: opts.NoIndex})
 	if err != nil {
 		return nil, err
 	}
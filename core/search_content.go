@@ -0,0 +1,178 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"bufio"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+// ContentSearchOptions contains options for searching the files of installed commands
+type ContentSearchOptions struct {
+	ProjectPath string
+	// Text is matched case-insensitively within single lines
+	Text string
+	// Names limits the search to these commands; empty searches every installed command
+	Names []string
+}
+
+// ContentMatch is a line of an installed command's markdown files containing the text
+type ContentMatch struct {
+	Name string `json:"name"`
+	File string `json:"file"` // slash-separated, relative to the command directory
+	Line int    `json:"line"`
+	Text string `json:"text"` // the line without surrounding whitespace
+	// Start and End are the byte offsets of the first match within Text
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// SearchContent searches the markdown files of installed commands, index.md and the
+// partials it includes, for lines containing opts.Text. Matches are sorted by command,
+// file and line.
+func SearchContent(opts ContentSearchOptions) ([]ContentMatch, error) {
+	if strings.TrimSpace(opts.Text) == "" {
+		return nil, errors.InvalidInput("search text is required")
+	}
+	projectRoot, err := findProjectRootFrom(opts.ProjectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	names := opts.Names
+	if len(names) == 0 {
+		lockFile, err := ReadLockFile(lockFilePath(projectRoot))
+		if err != nil {
+			return nil, err
+		}
+		for name := range lockFile.Commands {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var matches []ContentMatch
+	for _, name := range names {
+		found, err := searchCommandContent(installedCommandDir(projectRoot, name), name, opts.Text)
+		if err != nil {
+			return nil, err
+		}
+		matches = append(matches, found...)
+	}
+	return matches, nil
+}
+
+// searchCommandContent searches the markdown files below dir. A missing directory has
+// no matches.
+func searchCommandContent(dir, name, text string) ([]ContentMatch, error) {
+	if !dirExists(dir) {
+		return nil, nil
+	}
+	needle := strings.ToLower(text)
+
+	var matches []ContentMatch
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if entry.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.EqualFold(filepath.Ext(path), ".md") {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		scanner := bufio.NewScanner(file)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for line := 1; scanner.Scan(); line++ {
+			content := strings.TrimSpace(scanner.Text())
+			start, end := indexFold(content, needle)
+			if start < 0 {
+				continue
+			}
+			matches = append(matches, ContentMatch{
+				Name:  name,
+				File:  filepath.ToSlash(rel),
+				Line:  line,
+				Text:  content,
+				Start: start,
+				End:   end,
+			})
+		}
+		return scanner.Err()
+	})
+	if err != nil {
+		return nil, errors.FileError("search", dir, err)
+	}
+	return matches, nil
+}
+
+// indexFold returns the byte offsets of the first match of lowerNeedle in s ignoring
+// case, or -1. Offsets are those of s, so they stay valid for lines whose lower case has
+// another length.
+func indexFold(s, lowerNeedle string) (start, end int) {
+	if lower := strings.ToLower(s); len(lower) == len(s) {
+		if start = strings.Index(lower, lowerNeedle); start < 0 {
+			return -1, -1
+		}
+		return start, start + len(lowerNeedle)
+	}
+
+	for start = range s {
+		if !strings.HasPrefix(strings.ToLower(s[start:]), lowerNeedle) {
+			continue
+		}
+		for end = start; end < len(s) && len(strings.ToLower(s[start:end])) < len(lowerNeedle); {
+			_, size := utf8.DecodeRuneInString(s[end:])
+			end += size
+		}
+		return start, end
+	}
+	return -1, -1
+}
+
+// Snippet splits the line of a match into the text before the match, the match and the
+// text after it, shortened around the match to width runes with "…" marking the cuts.
+// The match itself is never shortened.
+func (m ContentMatch) Snippet(width int) (before, match, after string) {
+	before, match, after = m.Text[:m.Start], m.Text[m.Start:m.End], m.Text[m.End:]
+
+	// Room the text after the match does not need goes to the text before it
+	room := max(0, width-utf8.RuneCountInString(match))
+	keepBefore := max(room/2, room-utf8.RuneCountInString(after))
+	if r := []rune(before); len(r) > keepBefore {
+		before = "…" + string(r[len(r)-max(0, keepBefore-1):])
+	}
+	keepAfter := max(0, room-utf8.RuneCountInString(before))
+	if r := []rune(after); len(r) > keepAfter {
+		after = string(r[:max(0, keepAfter-1)]) + "…"
+	}
+	return before, match, after
+}
@@ -0,0 +1,89 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+func TestSearchContent(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+
+	writeConfig(t, []string{})
+	lockFile := createBasicLockFile()
+	files := map[string]map[string]string{
+		"review": {
+			"index.md":          "# Review\n\nRespect the API rate limit.\n",
+			"partials/retry.md": "Back off when you hit a RATE LIMIT\n",
+			"notes.txt":         "rate limit in a file that is not markdown\n",
+			".git/HEAD.md":      "rate limit\n",
+		},
+		"explain": {"index.md": "# Explain\n"},
+		"deploy":  {"index.md": "Ünïcode: İstanbul rate limit\n"},
+	}
+	for name, contents := range files {
+		lockFile.Commands[name] = createTestLockCommand(name, "v1.0.0", "https://github.com/acme/"+name+".git")
+		for file, content := range contents {
+			path := filepath.Join(".claude", "commands", name, filepath.FromSlash(file))
+			require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+			require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		}
+	}
+	writeLockFile(t, lockFile)
+
+	matches, err := SearchContent(ContentSearchOptions{ProjectPath: ".", Text: "Rate Limit"})
+	require.NoError(t, err)
+	require.Len(t, matches, 3)
+	assert.Equal(t, ContentMatch{Name: "deploy", File: "index.md", Line: 1, Text: "Ünïcode: İstanbul rate limit", Start: 21, End: 31}, matches[0])
+	assert.Equal(t, "rate limit", matches[0].Text[matches[0].Start:matches[0].End])
+	assert.Equal(t, ContentMatch{Name: "review", File: "index.md", Line: 3, Text: "Respect the API rate limit.", Start: 16, End: 26}, matches[1])
+	assert.Equal(t, "partials/retry.md", matches[2].File)
+	assert.Equal(t, "RATE LIMIT", matches[2].Text[matches[2].Start:matches[2].End])
+
+	matches, err = SearchContent(ContentSearchOptions{ProjectPath: ".", Text: "rate limit", Names: []string{"explain"}})
+	require.NoError(t, err)
+	assert.Empty(t, matches)
+
+	_, err = SearchContent(ContentSearchOptions{ProjectPath: ".", Text: " "})
+	assert.ErrorIs(t, err, errors.ErrInvalidInput)
+}
+
+func TestContentMatchSnippet(t *testing.T) {
+	text := "Before the match there is a lot of text, then the rate limit, and a long tail after it"
+	start := strings.Index(text, "rate limit")
+	m := ContentMatch{Text: text, Start: start, End: start + len("rate limit")}
+
+	before, match, after := m.Snippet(200)
+	assert.Equal(t, text, before+match+after)
+
+	before, match, after = m.Snippet(30)
+	assert.Equal(t, "rate limit", match)
+	assert.Equal(t, "…then the ", before)
+	assert.Equal(t, ", and a l…", after)
+	assert.Equal(t, 30, utf8.RuneCountInString(before+match+after))
+
+	// Room left over at the end of the line goes before the match
+	start = strings.Index(text, "after")
+	m = ContentMatch{Text: text, Start: start, End: start + len("after")}
+	before, match, after = m.Snippet(20)
+	assert.Equal(t, "after it", match+after)
+	assert.Equal(t, "… long tail ", before)
+	assert.Equal(t, 20, utf8.RuneCountInString(before+match+after))
+}
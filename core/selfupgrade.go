@@ -0,0 +1,270 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/gifflet/ccmd/pkg/errors"
+	"github.com/gifflet/ccmd/pkg/output"
+)
+
+const (
+	// ReleaseRepository is the GitHub repository ccmd releases are published in
+	ReleaseRepository = "gifflet/ccmd"
+
+	// releaseChecksumsAsset lists the sha256 of every archive of a release
+	releaseChecksumsAsset = "checksums.txt"
+)
+
+// SelfUpgradeOptions represents options for replacing the running ccmd with the latest release
+type SelfUpgradeOptions struct {
+	CurrentVersion string // Version of the running binary, "dev" for development builds
+	CheckOnly      bool   // Only report whether a newer release exists
+	Force          bool   // Install the latest release even when it is not newer
+	Executable     string // Binary to replace (default: the running executable)
+}
+
+// SelfUpgradeResult describes the latest release and what was done with it
+type SelfUpgradeResult struct {
+	CurrentVersion  string `json:"current_version"`
+	LatestVersion   string `json:"latest_version"`
+	UpdateAvailable bool   `json:"update_available"`
+	Upgraded        bool   `json:"upgraded"`
+	ReleaseURL      string `json:"release_url,omitempty"`
+	Asset           string `json:"asset,omitempty"`
+	Path            string `json:"path,omitempty"`
+}
+
+type githubRelease struct {
+	TagName string               `json:"tag_name"`
+	HTMLURL string               `json:"html_url"`
+	Assets  []githubReleaseAsset `json:"assets"`
+}
+
+type githubReleaseAsset struct {
+	Name        string `json:"name"`
+	DownloadURL string `json:"browser_download_url"`
+}
+
+// SelfUpgrade compares the running version with the latest GitHub release and, unless
+// CheckOnly is set, downloads the archive for this platform, verifies it against the
+// checksums of the release and replaces the executable with the binary it contains
+func SelfUpgrade(ctx context.Context, opts SelfUpgradeOptions) (*SelfUpgradeResult, error) {
+	client, err := newHTTPClient(remoteSearchTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	var release githubRelease
+	if err := githubGet(ctx, client, "/repos/"+ReleaseRepository+"/releases/latest", &release); err != nil {
+		return nil, fmt.Errorf("check latest release: %w", err)
+	}
+	latest, err := ParseSemver(release.TagName)
+	if err != nil {
+		return nil, fmt.Errorf("latest release has an unexpected tag: %w", err)
+	}
+
+	result := &SelfUpgradeResult{
+		CurrentVersion: opts.CurrentVersion,
+		LatestVersion:  latest.String(),
+		ReleaseURL:     release.HTMLURL,
+	}
+	current, err := ParseSemver(opts.CurrentVersion)
+	development := err != nil
+	result.UpdateAvailable = development || latest.Compare(current) > 0
+
+	if opts.CheckOnly || (!result.UpdateAvailable && !opts.Force) {
+		return result, nil
+	}
+	if development && !opts.Force {
+		return nil, errors.InvalidInput(fmt.Sprintf(
+			"this is a development build (%s); use --force to replace it with %s", opts.CurrentVersion, latest.Tag))
+	}
+
+	executable := opts.Executable
+	if executable == "" {
+		if executable, err = os.Executable(); err != nil {
+			return nil, errors.FileError("locate executable", "ccmd", err)
+		}
+	}
+	if resolved, err := filepath.EvalSymlinks(executable); err == nil {
+		executable = resolved
+	}
+
+	result.Asset = releaseAssetName(runtime.GOOS, runtime.GOARCH)
+	archiveURL, checksumsURL := "", ""
+	for _, asset := range release.Assets {
+		switch asset.Name {
+		case result.Asset:
+			archiveURL = asset.DownloadURL
+		case releaseChecksumsAsset:
+			checksumsURL = asset.DownloadURL
+		}
+	}
+	if archiveURL == "" {
+		return nil, errors.NotFound(fmt.Sprintf("release %s for %s/%s", latest.Tag, runtime.GOOS, runtime.GOARCH))
+	}
+	if checksumsURL == "" {
+		return nil, errors.NotFound(fmt.Sprintf("%s of release %s", releaseChecksumsAsset, latest.Tag))
+	}
+
+	output.PrintInfof("Downloading %s %s...", result.Asset, latest.Tag)
+	data, err := download(ctx, archiveURL, "release archive", MaxArchiveSize)
+	if err != nil {
+		return nil, err
+	}
+	sums, err := download(ctx, checksumsURL, releaseChecksumsAsset, 1<<20)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifyReleaseChecksum(data, sums, result.Asset); err != nil {
+		return nil, err
+	}
+
+	binary, cleanup, err := extractReleaseBinary(data, result.Asset)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	if err := replaceExecutable(ctx, executable, binary); err != nil {
+		return nil, err
+	}
+
+	result.Upgraded = true
+	result.Path = executable
+	return result, nil
+}
+
+// releaseAssetName returns the name of the release archive for a platform, as
+// .goreleaser.yaml names it
+func releaseAssetName(goos, goarch string) string {
+	arch := goarch
+	switch goarch {
+	case "amd64":
+		arch = "x86_64"
+	case "386":
+		arch = "i386"
+	}
+	ext := ".tar.gz"
+	if goos == "windows" {
+		ext = ".zip"
+	}
+	return fmt.Sprintf("ccmd-%s-%s%s", goos, arch, ext)
+}
+
+// verifyReleaseChecksum checks an archive against its line in checksums.txt
+func verifyReleaseChecksum(data, sums []byte, asset string) error {
+	scanner := bufio.NewScanner(bytes.NewReader(sums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 || strings.TrimPrefix(fields[1], "*") != asset {
+			continue
+		}
+		sum := sha256.Sum256(data)
+		digest := "sha256:" + hex.EncodeToString(sum[:])
+		if err := verifyArchiveChecksum(digest, fields[0]); err != nil {
+			return fmt.Errorf("%s: %w", asset, err)
+		}
+		return nil
+	}
+	return errors.NotFound(fmt.Sprintf("checksum of %s in %s", asset, releaseChecksumsAsset))
+}
+
+// extractReleaseBinary unpacks a release archive into a temporary directory and returns
+// the path of the ccmd binary in it, with a function that removes the directory
+func extractReleaseBinary(data []byte, asset string) (string, func(), error) {
+	// ccmd itself is upgraded, so only user settings apply
+	dir, cleanup, err := newTempDir("", "upgrade")
+	if err != nil {
+		return "", nil, err
+	}
+
+	if err := extractArchive(data, asset, dir); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+
+	name := "ccmd"
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+	var binary string
+	_ = filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err == nil && binary == "" && !d.IsDir() && d.Name() == name {
+			binary = path
+		}
+		return nil
+	})
+	if binary == "" {
+		cleanup()
+		return "", nil, errors.NotFound(fmt.Sprintf("%s in %s", name, asset))
+	}
+	return binary, cleanup, nil
+}
+
+// replaceExecutable swaps executable for binary. The new binary is written next to the
+// executable and must run 'ccmd --version' before it is renamed over it, so a failure
+// leaves the installed version untouched.
+func replaceExecutable(ctx context.Context, executable, binary string) error {
+	dir := filepath.Dir(executable)
+	tmp, err := os.CreateTemp(dir, ".ccmd-upgrade-*")
+	if err != nil {
+		return errors.FileError("write new binary", dir, err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+
+	src, err := os.Open(binary)
+	if err != nil {
+		tmp.Close()
+		return errors.FileError("open new binary", binary, err)
+	}
+	_, err = io.Copy(tmp, src)
+	src.Close()
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return errors.FileError("write new binary", tmpPath, err)
+	}
+	if err := os.Chmod(tmpPath, 0755); err != nil {
+		return errors.FileError("make new binary executable", tmpPath, err)
+	}
+
+	if out, err := exec.CommandContext(ctx, tmpPath, "--version").CombinedOutput(); err != nil {
+		return fmt.Errorf("new binary does not run: %w\nOutput: %s", err, strings.TrimSpace(string(out)))
+	}
+
+	// A running executable cannot be overwritten on Windows, but it can be renamed
+	if runtime.GOOS == "windows" {
+		old := executable + ".old"
+		_ = os.Remove(old)
+		if err := os.Rename(executable, old); err != nil {
+			return errors.FileError("move old binary", executable, err)
+		}
+	}
+	if err := os.Rename(tmpPath, executable); err != nil {
+		return errors.FileError("replace binary", executable, err)
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newReleaseServer serves a latest release v2.0.0 whose archive for this platform
+// contains a ccmd script printing its version
+func newReleaseServer(t *testing.T, checksum string) {
+	var archive bytes.Buffer
+	gz := gzip.NewWriter(&archive)
+	tw := tar.NewWriter(gz)
+	script := []byte("#!/bin/sh\necho 'ccmd version 2.0.0'\n")
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "ccmd", Mode: 0755, Size: int64(len(script))}))
+	_, err := tw.Write(script)
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+
+	asset := releaseAssetName(runtime.GOOS, runtime.GOARCH)
+	if checksum == "" {
+		sum := sha256.Sum256(archive.Bytes())
+		checksum = hex.EncodeToString(sum[:])
+	}
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/" + ReleaseRepository + "/releases/latest":
+			fmt.Fprintf(w, `{"tag_name": "v2.0.0", "html_url": "https://github.com/gifflet/ccmd/releases/v2.0.0",
+				"assets": [{"name": %q, "browser_download_url": "%s/download/archive"},
+				           {"name": "checksums.txt", "browser_download_url": "%s/download/checksums"}]}`,
+				asset, server.URL, server.URL)
+		case "/download/archive":
+			_, _ = w.Write(archive.Bytes())
+		case "/download/checksums":
+			fmt.Fprintf(w, "0000  ccmd-plan9-mips.tar.gz\n%s  %s\n", checksum, asset)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	oldURL := githubAPIURL
+	githubAPIURL = server.URL
+	t.Cleanup(func() { githubAPIURL = oldURL })
+	t.Setenv(GitHubTokenEnv, "")
+}
+
+func TestSelfUpgrade(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the release fixture is a shell script")
+	}
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	executable := filepath.Join(t.TempDir(), "ccmd")
+	require.NoError(t, os.WriteFile(executable, []byte("old"), 0755))
+
+	t.Run("check only", func(t *testing.T) {
+		newReleaseServer(t, "")
+		result, err := SelfUpgrade(ctx, SelfUpgradeOptions{CurrentVersion: "1.4.0", CheckOnly: true, Executable: executable})
+		require.NoError(t, err)
+		assert.True(t, result.UpdateAvailable)
+		assert.False(t, result.Upgraded)
+		assert.Equal(t, "2.0.0", result.LatestVersion)
+
+		data, err := os.ReadFile(executable)
+		require.NoError(t, err)
+		assert.Equal(t, "old", string(data))
+	})
+
+	t.Run("up to date", func(t *testing.T) {
+		newReleaseServer(t, "")
+		result, err := SelfUpgrade(ctx, SelfUpgradeOptions{CurrentVersion: "v2.0.0", Executable: executable})
+		require.NoError(t, err)
+		assert.False(t, result.UpdateAvailable)
+		assert.False(t, result.Upgraded)
+	})
+
+	t.Run("development builds need force", func(t *testing.T) {
+		newReleaseServer(t, "")
+		_, err := SelfUpgrade(ctx, SelfUpgradeOptions{CurrentVersion: "dev", Executable: executable})
+		assert.ErrorContains(t, err, "--force")
+	})
+
+	t.Run("checksum mismatch", func(t *testing.T) {
+		newReleaseServer(t, "deadbeef")
+		_, err := SelfUpgrade(ctx, SelfUpgradeOptions{CurrentVersion: "1.4.0", Executable: executable})
+		require.Error(t, err)
+
+		data, err := os.ReadFile(executable)
+		require.NoError(t, err)
+		assert.Equal(t, "old", string(data))
+	})
+
+	t.Run("replaces the executable", func(t *testing.T) {
+		newReleaseServer(t, "")
+		result, err := SelfUpgrade(ctx, SelfUpgradeOptions{CurrentVersion: "1.4.0", Executable: executable})
+		require.NoError(t, err)
+		assert.True(t, result.Upgraded)
+		assert.Equal(t, releaseAssetName(runtime.GOOS, runtime.GOARCH), result.Asset)
+
+		data, err := os.ReadFile(executable)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), "2.0.0")
+
+		entries, err := os.ReadDir(filepath.Dir(executable))
+		require.NoError(t, err)
+		assert.Len(t, entries, 1, "no temporary files are left behind")
+	})
+}
+
+func TestReleaseAssetName(t *testing.T) {
+	assert.Equal(t, "ccmd-linux-x86_64.tar.gz", releaseAssetName("linux", "amd64"))
+	assert.Equal(t, "ccmd-darwin-arm64.tar.gz", releaseAssetName("darwin", "arm64"))
+	assert.Equal(t, "ccmd-windows-x86_64.zip", releaseAssetName("windows", "amd64"))
+}
@@ -0,0 +1,431 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+// Save strategies control the version constraint written to ccmd.yaml
+const (
+	SaveExact = "exact" // v1.2.3
+	SaveCaret = "caret" // ^1.2.3, allows minor and patch updates
+	SaveTilde = "tilde" // ~1.2.3, allows patch updates
+)
+
+// Update channels select the tags latest and constraint resolution may pick
+const (
+	ChannelStable = "stable" // releases only (default)
+	ChannelBeta   = "beta"   // prereleases such as v2.0.0-rc.1 as well
+)
+
+// Default versions select what an install without a version installs
+const (
+	DefaultVersionLatestRelease = "latest-release" // newest stable semver tag (default)
+	DefaultVersionDefaultBranch = "default-branch" // head of the default branch
+)
+
+// Semver is a parsed semantic version. Tag keeps the original tag name.
+type Semver struct {
+	Major      int
+	Minor      int
+	Patch      int
+	Prerelease string
+	Tag        string
+}
+
+// ParseSemver parses MAJOR.MINOR.PATCH[-prerelease][+build] with an optional v prefix
+func ParseSemver(s string) (Semver, error) {
+	if !semverPattern.MatchString(s) {
+		return Semver{}, errors.InvalidInput(fmt.Sprintf("%q is not a semantic version", s))
+	}
+
+	v := Semver{Tag: s}
+	core := strings.TrimPrefix(s, "v")
+	if idx := strings.Index(core, "+"); idx != -1 {
+		core = core[:idx]
+	}
+	if idx := strings.Index(core, "-"); idx != -1 {
+		v.Prerelease = core[idx+1:]
+		core = core[:idx]
+	}
+
+	parts := strings.Split(core, ".")
+	v.Major, _ = strconv.Atoi(parts[0])
+	v.Minor, _ = strconv.Atoi(parts[1])
+	v.Patch, _ = strconv.Atoi(parts[2])
+
+	return v, nil
+}
+
+// Compare returns -1, 0 or 1 when v is lower than, equal to or greater than other
+func (v Semver) Compare(other Semver) int {
+	for _, d := range []int{v.Major - other.Major, v.Minor - other.Minor, v.Patch - other.Patch} {
+		if d < 0 {
+			return -1
+		}
+		if d > 0 {
+			return 1
+		}
+	}
+
+	switch {
+	case v.Prerelease == other.Prerelease:
+		return 0
+	case v.Prerelease == "":
+		return 1
+	case other.Prerelease == "":
+		return -1
+	default:
+		return comparePrerelease(v.Prerelease, other.Prerelease)
+	}
+}
+
+// comparePrerelease compares prerelease versions identifier by identifier, as SemVer
+// orders them: numeric identifiers numerically and below alphanumeric ones, which
+// compare as strings, and a shorter list first when all its identifiers are equal.
+// So 1.0.0-alpha < 1.0.0-alpha.1 < 1.0.0-alpha.9 < 1.0.0-alpha.10 < 1.0.0-beta.
+func comparePrerelease(a, b string) int {
+	left, right := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(left) && i < len(right); i++ {
+		x, xErr := strconv.ParseUint(left[i], 10, 64)
+		y, yErr := strconv.ParseUint(right[i], 10, 64)
+		switch {
+		case xErr == nil && yErr == nil:
+			if x != y {
+				if x < y {
+					return -1
+				}
+				return 1
+			}
+		case xErr == nil:
+			return -1
+		case yErr == nil:
+			return 1
+		default:
+			if c := strings.Compare(left[i], right[i]); c != 0 {
+				return c
+			}
+		}
+	}
+
+	switch {
+	case len(left) < len(right):
+		return -1
+	case len(left) > len(right):
+		return 1
+	default:
+		return 0
+	}
+}
+
+// String returns the version without a v prefix or build metadata
+func (v Semver) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Prerelease != "" {
+		s += "-" + v.Prerelease
+	}
+	return s
+}
+
+type comparator struct {
+	op      string
+	version Semver
+}
+
+func (c comparator) matches(v Semver) bool {
+	cmp := v.Compare(c.version)
+	switch c.op {
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	default:
+		return cmp == 0
+	}
+}
+
+// Constraint is a version range such as ^1.2.0, ~1.2, 1.x or ">=1.0.0 <2.0.0", with ||
+// alternatives
+type Constraint struct {
+	raw  string
+	sets [][]comparator
+}
+
+// IsConstraint reports whether a version string is a range rather than a tag, branch or
+// commit. A bare partial version such as 1.2 is read as a tag; 1.2.x is a range.
+func IsConstraint(s string) bool {
+	if strings.ContainsAny(s, "^~<>=*") || strings.Contains(s, "||") {
+		return true
+	}
+	_, parts, err := parsePartialVersion(s)
+	return err == nil && parts < 3 && strings.ContainsAny(s, "xX")
+}
+
+// ParseConstraint parses an npm-style version range. Terms within an alternative are
+// separated by spaces or commas, and may leave out the minor and patch versions.
+func ParseConstraint(s string) (*Constraint, error) {
+	c := &Constraint{raw: s}
+
+	for _, alternative := range strings.Split(s, "||") {
+		var set []comparator
+		terms := strings.FieldsFunc(alternative, func(r rune) bool { return r == ',' || unicode.IsSpace(r) })
+		for _, term := range terms {
+			comparators, err := parseConstraintTerm(term)
+			if err != nil {
+				return nil, errors.InvalidInput(fmt.Sprintf("invalid version constraint %q: %v", s, err))
+			}
+			set = append(set, comparators...)
+		}
+		if len(set) == 0 {
+			return nil, errors.InvalidInput(fmt.Sprintf("invalid version constraint %q", s))
+		}
+		c.sets = append(c.sets, set)
+	}
+
+	return c, nil
+}
+
+// parseConstraintTerm parses one comparison. Partial versions such as 1.2 or 1.x stand
+// for every version they leave open: ^1.2 is >=1.2.0 <2.0.0, 1.x is >=1.0.0 <2.0.0 and
+// <=1.2 is <1.3.0.
+func parseConstraintTerm(term string) ([]comparator, error) {
+	op := ""
+	for _, candidate := range []string{">=", "<=", ">", "<", "=", "^", "~"} {
+		if strings.HasPrefix(term, candidate) {
+			op = candidate
+			break
+		}
+	}
+
+	v, parts, err := parsePartialVersion(strings.TrimPrefix(term, op))
+	if err != nil {
+		return nil, err
+	}
+	if parts == 0 {
+		if op == ">" || op == "<" {
+			return nil, fmt.Errorf("%q matches no version", term)
+		}
+		return []comparator{{">=", Semver{}}}, nil
+	}
+	// next is the lowest version above every version v leaves open
+	next := Semver{Major: v.Major + 1}
+	if parts == 2 {
+		next = Semver{Major: v.Major, Minor: v.Minor + 1}
+	}
+
+	switch op {
+	case "^":
+		upper := Semver{Major: v.Major + 1}
+		if v.Major == 0 && v.Minor == 0 && parts == 3 {
+			upper = Semver{Patch: v.Patch + 1}
+		} else if v.Major == 0 && parts > 1 {
+			upper = Semver{Minor: v.Minor + 1}
+		}
+		return []comparator{{">=", v}, {"<", upper}}, nil
+	case "~":
+		if parts == 1 {
+			return []comparator{{">=", v}, {"<", next}}, nil
+		}
+		return []comparator{{">=", v}, {"<", Semver{Major: v.Major, Minor: v.Minor + 1}}}, nil
+	case ">=", "<":
+		return []comparator{{op, v}}, nil
+	}
+
+	if parts == 3 {
+		if op == "" {
+			op = "="
+		}
+		return []comparator{{op, v}}, nil
+	}
+	switch op {
+	case ">":
+		return []comparator{{">=", next}}, nil
+	case "<=":
+		return []comparator{{"<", next}}, nil
+	default:
+		return []comparator{{">=", v}, {"<", next}}, nil
+	}
+}
+
+// parsePartialVersion parses a semantic version that may leave out its minor and patch
+// versions or give them as x, X or *. It returns how many numbers were given.
+func parsePartialVersion(s string) (Semver, int, error) {
+	if v, err := ParseSemver(s); err == nil {
+		return v, 3, nil
+	}
+
+	v := Semver{Tag: s}
+	fields := strings.Split(strings.TrimPrefix(s, "v"), ".")
+	if len(fields) > 3 {
+		return Semver{}, 0, errors.InvalidInput(fmt.Sprintf("%q is not a version", s))
+	}
+
+	numbers := []*int{&v.Major, &v.Minor, &v.Patch}
+	parts := 0
+	for i, field := range fields {
+		if field == "x" || field == "X" || field == "*" {
+			continue
+		}
+		if parts < i || field == "" || strings.Trim(field, "0123456789") != "" {
+			return Semver{}, 0, errors.InvalidInput(fmt.Sprintf("%q is not a version", s))
+		}
+		*numbers[i], _ = strconv.Atoi(field)
+		parts++
+	}
+	if parts == 3 {
+		return Semver{}, 0, errors.InvalidInput(fmt.Sprintf("%q is not a version", s))
+	}
+
+	return v, parts, nil
+}
+
+// Check reports whether v satisfies the constraint. Prereleases only match
+// when the constraint itself mentions a prerelease.
+func (c *Constraint) Check(v Semver) bool {
+	return c.check(v, false)
+}
+
+// check is Check, with pre letting prereleases match any constraint
+func (c *Constraint) check(v Semver, pre bool) bool {
+	if v.Prerelease != "" && !pre && !strings.Contains(c.raw, "-") {
+		return false
+	}
+
+	for _, set := range c.sets {
+		matched := true
+		for _, cmp := range set {
+			if !cmp.matches(v) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// String returns the constraint as written
+func (c *Constraint) String() string {
+	return c.raw
+}
+
+// highestMatchingTag returns the highest semver tag satisfying the constraint. A nil
+// constraint accepts any stable version. With pre, prereleases are candidates too.
+func highestMatchingTag(tags []string, constraint *Constraint, pre bool) (string, bool) {
+	var best *Semver
+	for _, tag := range tags {
+		v, err := ParseSemver(tag)
+		if err != nil {
+			continue
+		}
+		if constraint == nil && v.Prerelease != "" && !pre {
+			continue
+		}
+		if constraint != nil && !constraint.check(v, pre) {
+			continue
+		}
+		if best == nil || v.Compare(*best) > 0 {
+			candidate := v
+			best = &candidate
+		}
+	}
+
+	if best == nil {
+		return "", false
+	}
+	return best.Tag, true
+}
+
+// resolveConstraint finds the newest remote tag matching a version constraint, including
+// prereleases with pre. A nil cache lists the remote tags directly.
+func resolveConstraint(ctx context.Context, cache *tagCache, repoURL, constraint string, pre bool) (string, error) {
+	c, err := ParseConstraint(constraint)
+	if err != nil {
+		return "", err
+	}
+
+	tags, err := cache.list(ctx, repoURL)
+	if ctx.Err() != nil {
+		return "", ctx.Err()
+	}
+	if err != nil {
+		return "", errors.GitError("list tags", err)
+	}
+
+	tag, ok := highestMatchingTag(tags, c, pre)
+	if !ok {
+		return "", errors.NotFound(fmt.Sprintf("no tag of %s satisfies %q", repoURL, constraint))
+	}
+
+	return tag, nil
+}
+
+// constraintFor returns the ccmd.yaml version for an installed tag under a save strategy.
+// Versions that are not semantic versions (branches, commits) are returned unchanged.
+func constraintFor(strategy, version string) string {
+	v, err := ParseSemver(version)
+	if err != nil {
+		return version
+	}
+
+	switch strategy {
+	case SaveCaret:
+		return "^" + v.String()
+	case SaveTilde:
+		return "~" + v.String()
+	default:
+		return version
+	}
+}
+
+// ValidateSaveStrategy checks a save strategy name; empty means "use the configured default"
+func ValidateSaveStrategy(strategy string) error {
+	switch strategy {
+	case "", SaveExact, SaveCaret, SaveTilde:
+		return nil
+	default:
+		return errors.InvalidInput(fmt.Sprintf("unknown save strategy %q (expected exact, caret or tilde)", strategy))
+	}
+}
+
+// ValidateDefaultVersion checks a default version name; empty means "use the configured default"
+func ValidateDefaultVersion(defaultVersion string) error {
+	switch defaultVersion {
+	case "", DefaultVersionLatestRelease, DefaultVersionDefaultBranch:
+		return nil
+	default:
+		return errors.InvalidInput(fmt.Sprintf("unknown default version %q (expected %s or %s)",
+			defaultVersion, DefaultVersionLatestRelease, DefaultVersionDefaultBranch))
+	}
+}
+
+// ValidateChannel checks an update channel name; empty means stable
+func ValidateChannel(channel string) error {
+	switch channel {
+	case "", ChannelStable, ChannelBeta:
+		return nil
+	default:
+		return errors.InvalidInput(fmt.Sprintf("unknown channel %q (expected stable or beta)", channel))
+	}
+}
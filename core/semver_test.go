@@ -0,0 +1,262 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gifflet/ccmd/pkg/config"
+)
+
+func TestParseSemver(t *testing.T) {
+	v, err := ParseSemver("v1.2.3-beta.1+build")
+	require.NoError(t, err)
+	assert.Equal(t, Semver{Major: 1, Minor: 2, Patch: 3, Prerelease: "beta.1", Tag: "v1.2.3-beta.1+build"}, v)
+	assert.Equal(t, "1.2.3-beta.1", v.String())
+
+	_, err = ParseSemver("main")
+	assert.Error(t, err)
+	_, err = ParseSemver("1.2")
+	assert.Error(t, err)
+}
+
+func TestSemverCompare(t *testing.T) {
+	parse := func(s string) Semver {
+		v, err := ParseSemver(s)
+		require.NoError(t, err)
+		return v
+	}
+
+	assert.Equal(t, -1, parse("1.2.3").Compare(parse("1.10.0")))
+	assert.Equal(t, 1, parse("2.0.0").Compare(parse("1.99.99")))
+	assert.Equal(t, 0, parse("v1.0.0").Compare(parse("1.0.0")))
+	assert.Equal(t, -1, parse("1.0.0-rc.1").Compare(parse("1.0.0")))
+
+	// Prereleases in the order of the SemVer specification
+	ordered := []string{"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.9", "1.0.0-alpha.10", "1.0.0-alpha.beta",
+		"1.0.0-beta", "1.0.0-beta.2", "1.0.0-beta.11", "1.0.0-rc.1", "1.0.0"}
+	for i := 1; i < len(ordered); i++ {
+		assert.Equal(t, -1, parse(ordered[i-1]).Compare(parse(ordered[i])), ordered[i-1]+" < "+ordered[i])
+		assert.Equal(t, 1, parse(ordered[i]).Compare(parse(ordered[i-1])), ordered[i]+" > "+ordered[i-1])
+	}
+}
+
+func TestConstraintCheck(t *testing.T) {
+	tests := []struct {
+		constraint string
+		version    string
+		expected   bool
+	}{
+		{"^1.2.3", "1.2.3", true},
+		{"^1.2.3", "1.9.0", true},
+		{"^1.2.3", "2.0.0", false},
+		{"^1.2.3", "1.2.2", false},
+		{"^0.2.3", "0.2.9", true},
+		{"^0.2.3", "0.3.0", false},
+		{"^0.0.3", "0.0.4", false},
+		{"~1.2.3", "1.2.9", true},
+		{"~1.2.3", "1.3.0", false},
+		{">=1.0.0 <2.0.0", "1.5.0", true},
+		{">=1.0.0 <2.0.0", "2.0.0", false},
+		{"^1.0.0 || ^3.0.0", "3.1.0", true},
+		{"=1.2.3", "v1.2.3", true},
+		{"*", "4.5.6", true},
+		{"^1.0.0", "1.1.0-beta", false},
+		{"^1.1.0-beta", "1.1.0-beta", true},
+		{"^1.2", "1.9.0", true},
+		{"^1.2", "1.1.9", false},
+		{"^1.2", "2.0.0", false},
+		{"^0.2", "0.2.5", true},
+		{"^0.2", "0.3.0", false},
+		{"~1.2", "1.2.9", true},
+		{"~1.2", "1.3.0", false},
+		{"~1", "1.9.0", true},
+		{"1.x", "1.4.0", true},
+		{"1.x", "2.0.0", false},
+		{"1.2.x", "1.2.7", true},
+		{"1.2.x", "1.3.0", false},
+		{"1.0", "1.0.5", true},
+		{"1.0", "1.1.0", false},
+		{">=1.0.0, <2.0.0", "1.5.0", true},
+		{">=1.0.0, <2.0.0", "2.0.0", false},
+		{">=1.0,<2", "1.9.9", true},
+		{">1.2", "1.2.9", false},
+		{">1.2", "1.3.0", true},
+		{"<=1.2", "1.2.9", true},
+		{"<=1.2", "1.3.0", false},
+		{"x", "0.1.0", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.constraint+" "+tt.version, func(t *testing.T) {
+			c, err := ParseConstraint(tt.constraint)
+			require.NoError(t, err)
+			v, err := ParseSemver(tt.version)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, c.Check(v))
+		})
+	}
+
+	for _, invalid := range []string{"^main", "1.x.3", "1.2.3.4", ">*", ">=1.-1"} {
+		_, err := ParseConstraint(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestIsConstraint(t *testing.T) {
+	assert.True(t, IsConstraint("^1.0.0"))
+	assert.True(t, IsConstraint("~1.0.0"))
+	assert.True(t, IsConstraint(">=1.0.0"))
+	assert.True(t, IsConstraint("1.x"))
+	assert.True(t, IsConstraint("v1.2.X"))
+	assert.False(t, IsConstraint("1.2"))
+	assert.False(t, IsConstraint("fix-x"))
+	assert.False(t, IsConstraint("v1.0.0"))
+	assert.False(t, IsConstraint("main"))
+	assert.False(t, IsConstraint("abc1234"))
+}
+
+func TestHighestMatchingTag(t *testing.T) {
+	tags := []string{"v1.0.0", "v1.4.2", "v2.0.0-rc.1", "v1.10.0", "latest", "v0.9.0"}
+
+	tag, ok := highestMatchingTag(tags, nil, false)
+	assert.True(t, ok)
+	assert.Equal(t, "v1.10.0", tag)
+
+	// The prerelease channel considers prereleases too
+	tag, ok = highestMatchingTag(tags, nil, true)
+	assert.True(t, ok)
+	assert.Equal(t, "v2.0.0-rc.1", tag)
+
+	c, err := ParseConstraint("~1.4.0")
+	require.NoError(t, err)
+	tag, ok = highestMatchingTag(tags, c, false)
+	assert.True(t, ok)
+	assert.Equal(t, "v1.4.2", tag)
+
+	c, err = ParseConstraint(">=1.5.0")
+	require.NoError(t, err)
+	tag, ok = highestMatchingTag(tags, c, false)
+	assert.True(t, ok)
+	assert.Equal(t, "v1.10.0", tag)
+	tag, ok = highestMatchingTag(tags, c, true)
+	assert.True(t, ok)
+	assert.Equal(t, "v2.0.0-rc.1", tag)
+
+	c, err = ParseConstraint("^3.0.0")
+	require.NoError(t, err)
+	_, ok = highestMatchingTag(tags, c, false)
+	assert.False(t, ok)
+}
+
+func TestConstraintFor(t *testing.T) {
+	assert.Equal(t, "^1.2.3", constraintFor(SaveCaret, "v1.2.3"))
+	assert.Equal(t, "~1.2.3", constraintFor(SaveTilde, "v1.2.3"))
+	assert.Equal(t, "v1.2.3", constraintFor(SaveExact, "v1.2.3"))
+	assert.Equal(t, "main", constraintFor(SaveCaret, "main"))
+}
+
+func TestResolveInstallVersionExplicit(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+
+	cwd, err := os.Getwd()
+	require.NoError(t, err)
+
+	// Explicit version and strategy
+	opts := InstallOptions{Version: "v1.2.3", SaveStrategy: SaveTilde}
+	require.NoError(t, resolveInstallVersion(context.Background(), cwd, "https://github.com/owner/repo.git", &opts))
+	assert.Equal(t, "v1.2.3", opts.Version)
+	assert.Equal(t, "~1.2.3", configVersion(opts))
+
+	// Explicit version without strategy keeps the version as given
+	opts = InstallOptions{Version: "v1.2.3"}
+	require.NoError(t, resolveInstallVersion(context.Background(), cwd, "https://github.com/owner/repo.git", &opts))
+	assert.Equal(t, "v1.2.3", configVersion(opts))
+
+	// An existing constraint that still matches is preserved
+	writeConfig(t, []string{"owner/repo@^1.0.0"})
+	opts = InstallOptions{Version: "v1.2.3"}
+	require.NoError(t, resolveInstallVersion(context.Background(), cwd, "https://github.com/owner/repo.git", &opts))
+	assert.Equal(t, "^1.0.0", configVersion(opts))
+
+	// ...but not when the new version falls outside it
+	opts = InstallOptions{Version: "v2.0.0"}
+	require.NoError(t, resolveInstallVersion(context.Background(), cwd, "https://github.com/owner/repo.git", &opts))
+	assert.Equal(t, "v2.0.0", configVersion(opts))
+
+	// Abbreviated commits are shortened as before
+	opts = InstallOptions{Version: "0123456789ab"}
+	require.NoError(t, resolveInstallVersion(context.Background(), cwd, "https://github.com/owner/repo.git", &opts))
+	assert.Equal(t, "0123456", configVersion(opts))
+
+	// ...while full commits are pins and kept whole
+	opts = InstallOptions{Version: "0123456789abcdef0123456789abcdef01234567"}
+	require.NoError(t, resolveInstallVersion(context.Background(), cwd, "https://github.com/owner/repo.git", &opts))
+	assert.Equal(t, "0123456789abcdef0123456789abcdef01234567", configVersion(opts))
+}
+
+func TestUpdateChannels(t *testing.T) {
+	repo, release := writeCommandRepo(t)
+	release("v1.1.0-beta.1")
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	ctx := context.Background()
+	t.Setenv(config.ConfigEnv, filepath.Join(t.TempDir(), "config.yaml"))
+
+	resolved := func() string {
+		_, version := ParseRepositorySpec(readLockFile(t).Commands["tool"].Resolved)
+		return version
+	}
+
+	// The stable channel skips prereleases
+	_, _, err := Install(ctx, InstallOptions{Repository: repo})
+	require.NoError(t, err)
+	assert.Equal(t, "v1.0.0", resolved())
+	cmd, err := GetCommandInfo("tool", "")
+	require.NoError(t, err)
+	plan, needsUpdate := planUpdate(ctx, ".", *cmd, false, false)
+	assert.False(t, needsUpdate)
+	assert.Equal(t, ChannelStable, plan.Channel)
+
+	// --pre lets updates pick them for one run
+	plan, needsUpdate = planUpdate(ctx, ".", *cmd, false, true)
+	assert.True(t, needsUpdate)
+	assert.Equal(t, ChannelBeta, plan.Channel)
+	assert.Equal(t, "v1.1.0-beta.1", plan.TargetVersion)
+
+	// ...and "channel: beta" for every update of the entry
+	data, err := os.ReadFile(ConfigFileName)
+	require.NoError(t, err)
+	entry := repo + "@^1.0.0"
+	require.Contains(t, string(data), entry)
+	data = []byte(strings.Replace(string(data), "- "+entry, "- repo: "+entry+"\n      channel: beta", 1))
+	require.NoError(t, os.WriteFile(ConfigFileName, data, 0644))
+
+	_, err = Update(ctx, UpdateOptions{Name: "tool"})
+	require.NoError(t, err)
+	assert.Equal(t, "v1.1.0-beta.1", resolved())
+	data, err = os.ReadFile(ConfigFileName)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "channel: beta", "the channel survives the rewrite of ccmd.yaml")
+}
+
+func TestValidateSaveStrategy(t *testing.T) {
+	assert.NoError(t, ValidateSaveStrategy(""))
+	assert.NoError(t, ValidateSaveStrategy(SaveTilde))
+	assert.Error(t, ValidateSaveStrategy("loose"))
+}
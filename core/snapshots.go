@@ -0,0 +1,462 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	ccmdfs "github.com/gifflet/ccmd/internal/fs"
+	"github.com/gifflet/ccmd/pkg/errors"
+	"github.com/gifflet/ccmd/pkg/logger"
+	"github.com/gifflet/ccmd/pkg/output"
+)
+
+const (
+	// SnapshotsDirName is the directory under .claude where project snapshots are kept
+	SnapshotsDirName = ".snapshots"
+	// SnapshotLimit is how many project snapshots are kept
+	SnapshotLimit = 20
+
+	snapshotTimeFormat = "20060102T150405.000Z"
+	snapshotManifest   = "snapshot.yaml"
+)
+
+// Snapshot is a copy of ccmd.yaml and ccmd-lock.yaml taken before an operation changed
+// them. A file missing from the snapshot did not exist at the time.
+type Snapshot struct {
+	ID        string    `yaml:"-" json:"id"`
+	Operation string    `yaml:"operation" json:"operation"`
+	CreatedAt time.Time `yaml:"created_at" json:"created_at"`
+
+	dir string
+}
+
+// snapshotDepth counts the operations in progress that took a snapshot, so the
+// installs and removals a sync or an update makes are undone with it in one step
+var snapshotDepth int32
+
+// snapshotBefore snapshots the project before a mutating operation and returns the
+// function to call once it is done. Operations started by another one share its
+// snapshot, and a new snapshot is dropped again when the operation changed nothing.
+// A failed snapshot is logged and does not stop the operation.
+func snapshotBefore(projectRoot, operation string) (done func()) {
+	if atomic.AddInt32(&snapshotDepth, 1) != 1 {
+		return func() { atomic.AddInt32(&snapshotDepth, -1) }
+	}
+
+	var created *Snapshot
+	snapshots, err := ListSnapshots(projectRoot)
+	if err == nil {
+		created, err = takeSnapshot(projectRoot, operation, time.Now())
+	}
+	if err != nil {
+		logger.New().WithError(err).Warn("Failed to snapshot the project")
+	}
+	if created != nil && len(snapshots) > 0 && snapshots[0].ID == created.ID {
+		created = nil
+	}
+
+	return func() {
+		defer atomic.AddInt32(&snapshotDepth, -1)
+		if created == nil {
+			return
+		}
+		recorded, err := created.state()
+		if err != nil {
+			return
+		}
+		if current, err := readProjectState(projectRoot); err == nil && current.equal(recorded) {
+			ccmdfs.RemoveAll(created.dir)
+			removeIfEmpty(snapshotsRoot(projectRoot))
+			removeIfEmpty(filepath.Join(projectRoot, ".claude"))
+		}
+	}
+}
+
+// snapshotsRoot returns the snapshots directory of a project
+func snapshotsRoot(projectRoot string) string {
+	return filepath.Join(projectRoot, ".claude", SnapshotsDirName)
+}
+
+// projectState holds the content of ccmd.yaml and ccmd-lock.yaml; nil for a missing file
+type projectState struct {
+	config []byte
+	lock   []byte
+}
+
+func (s projectState) equal(other projectState) bool {
+	return bytes.Equal(s.config, other.config) && bytes.Equal(s.lock, other.lock) &&
+		(s.config == nil) == (other.config == nil) && (s.lock == nil) == (other.lock == nil)
+}
+
+// readProjectState reads the ccmd.yaml and ccmd-lock.yaml of a project
+func readProjectState(projectRoot string) (projectState, error) {
+	paths := projectPaths(projectRoot)
+	return readState(paths.Config, paths.Lock)
+}
+
+// readState reads the ccmd.yaml and ccmd-lock.yaml at the given paths
+func readState(configPath, lockPath string) (projectState, error) {
+	var state projectState
+	for path, data := range map[string]*[]byte{configPath: &state.config, lockPath: &state.lock} {
+		content, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return state, errors.FileError("read file", path, err)
+		}
+		if content == nil {
+			content = []byte{}
+		}
+		*data = content
+	}
+	return state, nil
+}
+
+// state returns the files recorded by the snapshot
+func (s *Snapshot) state() (projectState, error) {
+	return readState(filepath.Join(s.dir, ConfigFileName), filepath.Join(s.dir, LockFileName))
+}
+
+// takeSnapshot records the current ccmd.yaml and ccmd-lock.yaml of a project, unless the
+// newest snapshot already holds them, and drops the snapshots beyond SnapshotLimit
+func takeSnapshot(projectRoot, operation string, now time.Time) (*Snapshot, error) {
+	current, err := readProjectState(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+	if current.config == nil && current.lock == nil {
+		return nil, nil
+	}
+
+	snapshots, err := ListSnapshots(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+	if len(snapshots) > 0 {
+		if latest, err := snapshots[0].state(); err == nil && latest.equal(current) {
+			return &snapshots[0], nil
+		}
+	}
+
+	snapshot := &Snapshot{Operation: operation, CreatedAt: now.UTC()}
+	root := snapshotsRoot(projectRoot)
+	id := now.UTC().Format(snapshotTimeFormat)
+	snapshot.ID = id
+	for i := 2; dirExists(filepath.Join(root, snapshot.ID)); i++ {
+		snapshot.ID = fmt.Sprintf("%s-%d", id, i)
+	}
+	snapshot.dir = filepath.Join(root, snapshot.ID)
+
+	if err := os.MkdirAll(snapshot.dir, 0o750); err != nil {
+		return nil, errors.FileError("create snapshot directory", snapshot.dir, err)
+	}
+	files := map[string][]byte{ConfigFileName: current.config, LockFileName: current.lock}
+	if files[snapshotManifest], err = yaml.Marshal(snapshot); err != nil {
+		ccmdfs.RemoveAll(snapshot.dir)
+		return nil, errors.FileError("marshal snapshot", snapshot.dir, err)
+	}
+	for name, data := range files {
+		if data == nil {
+			continue
+		}
+		path := filepath.Join(snapshot.dir, name)
+		if err := os.WriteFile(path, data, 0o600); err != nil {
+			ccmdfs.RemoveAll(snapshot.dir)
+			return nil, errors.FileError("write snapshot", path, err)
+		}
+	}
+
+	snapshots = append([]Snapshot{*snapshot}, snapshots...)
+	for _, old := range snapshots[min(len(snapshots), SnapshotLimit):] {
+		if err := ccmdfs.RemoveAll(old.dir); err != nil {
+			logger.New().WithError(err).Warn("Failed to remove old snapshot")
+		}
+	}
+	return snapshot, nil
+}
+
+// ListSnapshots returns the snapshots of a project, newest first
+func ListSnapshots(projectPath string) ([]Snapshot, error) {
+	projectRoot, err := findProjectRootFrom(projectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	root := snapshotsRoot(projectRoot)
+	dirs, err := os.ReadDir(root)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []Snapshot{}, nil
+		}
+		return nil, errors.FileError("read snapshots directory", root, err)
+	}
+
+	snapshots := []Snapshot{}
+	for _, d := range dirs {
+		if !d.IsDir() {
+			continue
+		}
+		dir := filepath.Join(root, d.Name())
+		data, err := os.ReadFile(filepath.Join(dir, snapshotManifest))
+		if err != nil {
+			continue
+		}
+		var snapshot Snapshot
+		if err := yaml.Unmarshal(data, &snapshot); err != nil {
+			continue
+		}
+		snapshot.ID = d.Name()
+		snapshot.dir = dir
+		snapshots = append(snapshots, snapshot)
+	}
+
+	sort.SliceStable(snapshots, func(i, j int) bool {
+		if !snapshots[i].CreatedAt.Equal(snapshots[j].CreatedAt) {
+			return snapshots[i].CreatedAt.After(snapshots[j].CreatedAt)
+		}
+		return snapshots[i].ID > snapshots[j].ID
+	})
+	return snapshots, nil
+}
+
+// RestoreSnapshotOptions represents options for restoring a project snapshot
+type RestoreSnapshotOptions struct {
+	// To selects the snapshot by ID, or restores the project as it was at a time given
+	// as ParseAuditTime accepts it. Empty undoes the last change.
+	To string
+
+	// OverwriteLocal and Backup handle local modifications as they do for updates
+	OverwriteLocal bool
+	Backup         bool
+}
+
+// RestoreSnapshot puts ccmd.yaml and ccmd-lock.yaml back as a snapshot recorded them and
+// reinstalls the commands and plugins whose locked version differs, removing those the
+// snapshot does not hold. The state it replaces is snapshotted first, so a restore can
+// itself be undone.
+func RestoreSnapshot(ctx context.Context, opts RestoreSnapshotOptions) (*Snapshot, error) {
+	projectRoot, err := findProjectRoot()
+	if err != nil {
+		return nil, err
+	}
+	snapshots, err := ListSnapshots(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+	current, err := readProjectState(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+	target, err := snapshotTarget(snapshots, current, opts.To, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	done := snapshotBefore(projectRoot, AuditRollback)
+	defer done()
+
+	output.PrintInfof("Restoring snapshot %s, taken before %s on %s", target.ID, target.Operation,
+		target.CreatedAt.Local().Format("2006-01-02 15:04:05"))
+	err = restoreSnapshot(ctx, projectRoot, target, opts)
+	recordAudit(projectRoot, auditResult(AuditEvent{
+		Action:  AuditRollback,
+		Details: "snapshot " + target.ID,
+	}, err))
+	if err != nil {
+		return nil, err
+	}
+	return target, nil
+}
+
+// snapshotTarget picks the snapshot to restore. Without to it is the newest one that
+// differs from the current state. A time selects the state the project had then: the
+// one recorded by the first snapshot taken after it.
+func snapshotTarget(snapshots []Snapshot, current projectState, to string, now time.Time) (*Snapshot, error) {
+	if len(snapshots) == 0 {
+		return nil, errors.NotFound("project snapshots; they are taken before ccmd changes ccmd.yaml or ccmd-lock.yaml")
+	}
+
+	if to == "" {
+		for i := range snapshots {
+			if state, err := snapshots[i].state(); err == nil && !state.equal(current) {
+				return &snapshots[i], nil
+			}
+		}
+		return nil, errors.NotFound("snapshot that differs from the current ccmd.yaml and ccmd-lock.yaml")
+	}
+
+	for i := range snapshots {
+		if snapshots[i].ID == to {
+			return &snapshots[i], nil
+		}
+	}
+
+	at, err := ParseAuditTime(to, now)
+	if err != nil {
+		return nil, errors.InvalidInput(fmt.Sprintf("%q is neither a snapshot ID nor a time; "+
+			"run 'ccmd rollback --list' to see the snapshots", to))
+	}
+	var target *Snapshot
+	for i := range snapshots {
+		if !snapshots[i].CreatedAt.After(at) {
+			break
+		}
+		target = &snapshots[i]
+	}
+	if target == nil {
+		return nil, errors.NotFound(fmt.Sprintf("change since %s; the project is as it was then",
+			at.Local().Format("2006-01-02 15:04:05")))
+	}
+	return target, nil
+}
+
+// restoreSnapshot makes the installed commands and plugins match the snapshot's lock
+// file, then writes its ccmd.yaml and ccmd-lock.yaml. Installs run while the current lock
+// file is in place, so local modifications are detected against what was installed.
+func restoreSnapshot(ctx context.Context, projectRoot string, target *Snapshot, opts RestoreSnapshotOptions) error {
+	state, err := target.state()
+	if err != nil {
+		return err
+	}
+	lockPath := lockFilePath(projectRoot)
+	currentLock, err := readOrCreateLockFile(lockPath)
+	if err != nil {
+		return err
+	}
+	targetLock, err := readOrCreateLockFile(filepath.Join(target.dir, LockFileName))
+	if err != nil {
+		return err
+	}
+
+	var reinstall, remove []string
+	for name, want := range targetLock.Commands {
+		have := currentLock.Commands[name]
+		if have != nil && have.Source != want.Source {
+			remove = append(remove, name)
+			have = nil
+		}
+		if have == nil || have.Commit != want.Commit || !dirExists(installedCommandDir(projectRoot, name)) {
+			reinstall = append(reinstall, name)
+		}
+	}
+	for name, want := range targetLock.Plugins {
+		have := currentLock.Plugins[name]
+		if have != nil && have.Source != want.Source {
+			remove = append(remove, name)
+			have = nil
+		}
+		if have == nil || have.Commit != want.Commit || !dirExists(filepath.Join(projectRoot, ".claude", "plugins", name)) {
+			reinstall = append(reinstall, name)
+		}
+	}
+	for name := range currentLock.Commands {
+		if targetLock.Commands[name] == nil {
+			remove = append(remove, name)
+		}
+	}
+	for name := range currentLock.Plugins {
+		if targetLock.Plugins[name] == nil {
+			remove = append(remove, name)
+		}
+	}
+	sort.Strings(reinstall)
+	sort.Strings(remove)
+
+	// Refuse before changing anything; Install asks again and makes the backups
+	if !opts.OverwriteLocal && !opts.Backup {
+		for _, name := range reinstall {
+			if currentLock.Commands[name] == nil || slices.Contains(remove, name) {
+				continue
+			}
+			if err := protectLocalChanges(projectRoot, name, false, false); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, name := range remove {
+		// Removed commands go to the trash, where 'ccmd restore' finds them
+		if err := Remove(RemoveOptions{Name: name, Force: true}); err != nil {
+			return err
+		}
+	}
+
+	var failed []string
+	for _, name := range reinstall {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		installOpts := snapshotInstallOptions(name, targetLock)
+		installOpts.Force = (currentLock.Commands[name] != nil || currentLock.Plugins[name] != nil) &&
+			!slices.Contains(remove, name)
+		installOpts.OverwriteLocal = opts.OverwriteLocal
+		installOpts.Backup = opts.Backup
+		if _, _, err := Install(ctx, installOpts); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			output.PrintWarningf("Failed to reinstall %s: %v", name, err)
+			failed = append(failed, name)
+		}
+	}
+	if len(failed) > 0 {
+		return errors.Conflict(fmt.Sprintf("could not reinstall %s; %s and %s were left as they are, "+
+			"run the rollback again once the problem is solved", strings.Join(failed, ", "), ConfigFileName, LockFileName))
+	}
+
+	paths := projectPaths(projectRoot)
+	for path, data := range map[string][]byte{paths.Config: state.config, paths.Lock: state.lock} {
+		if data == nil {
+			err = os.Remove(path)
+			if os.IsNotExist(err) {
+				err = nil
+			}
+		} else {
+			err = writeFileAtomic(path, data, 0o644)
+		}
+		projectFiles.invalidate(path)
+		if err != nil {
+			return errors.FileError("restore file", path, err)
+		}
+	}
+	return nil
+}
+
+// snapshotInstallOptions returns install options reproducing a command or plugin of a
+// snapshot's lock file. They are not frozen: the installs record themselves in the lock
+// file, which is then replaced by the snapshot's.
+func snapshotInstallOptions(name string, lockFile *LockFile) InstallOptions {
+	if plugin := lockFile.Plugins[name]; plugin != nil {
+		opts := frozenInstallOptions(name, plugin.Source, plugin.Resolved, plugin.Commit)
+		opts.Name = name
+		opts.frozen = false
+		return opts
+	}
+	cmd := lockFile.Commands[name]
+	opts := frozenInstallOptions(name, cmd.Source, cmd.Resolved, cmd.Commit)
+	if cmd.Instance {
+		opts.As = name
+	}
+	opts.frozen = false
+	return opts
+}
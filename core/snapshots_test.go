@@ -0,0 +1,148 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gifflet/ccmd/pkg/config"
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+func TestRestoreSnapshot(t *testing.T) {
+	repo, release := writeCommandRepo(t)
+	first, err := gitGetCurrentCommit(strings.TrimPrefix(repo, "file://"))
+	require.NoError(t, err)
+	second := release("v1.1.0")
+
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	ctx := context.Background()
+	t.Setenv(config.ConfigEnv, filepath.Join(t.TempDir(), "config.yaml"))
+
+	_, err = RestoreSnapshot(ctx, RestoreSnapshotOptions{})
+	assert.ErrorIs(t, err, errors.ErrNotFound)
+
+	writeConfig(t, []string{})
+	_, _, err = Install(ctx, InstallOptions{Repository: repo + "@v1.0.0"})
+	require.NoError(t, err)
+	_, _, err = Install(ctx, InstallOptions{Repository: repo + "@v1.1.0", Force: true})
+	require.NoError(t, err)
+
+	// Operations that change nothing leave no snapshot behind
+	_, _, err = Install(ctx, InstallOptions{Repository: "file://" + filepath.Join(t.TempDir(), "missing")})
+	require.Error(t, err)
+
+	snapshots, err := ListSnapshots(".")
+	require.NoError(t, err)
+	require.Len(t, snapshots, 2)
+	assert.Equal(t, AuditInstall, snapshots[0].Operation)
+	assert.FileExists(t, filepath.Join(snapshots[0].dir, LockFileName))
+	assert.NoFileExists(t, filepath.Join(snapshots[1].dir, LockFileName), "nothing was locked before the first install")
+	installed := snapshots[1].ID
+
+	// The last change is undone, and undoing again redoes it
+	target, err := RestoreSnapshot(ctx, RestoreSnapshotOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, snapshots[0].ID, target.ID)
+	assert.Equal(t, first, readLockFile(t).Commands["tool"].Commit)
+	data, err := os.ReadFile(ConfigFileName)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), repo+"@v1.0.0")
+
+	_, err = RestoreSnapshot(ctx, RestoreSnapshotOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, second, readLockFile(t).Commands["tool"].Commit)
+
+	// Restoring the state before the first install removes the command
+	_, err = RestoreSnapshot(ctx, RestoreSnapshotOptions{To: installed})
+	require.NoError(t, err)
+	assert.NoFileExists(t, LockFileName)
+	assert.NoDirExists(t, filepath.Join(".claude", "commands", "tool"))
+	data, err = os.ReadFile(ConfigFileName)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), repo)
+
+	_, err = RestoreSnapshot(ctx, RestoreSnapshotOptions{To: "yesterday"})
+	assert.ErrorIs(t, err, errors.ErrInvalidInput)
+}
+
+func TestTakeSnapshot(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	snapshot, err := takeSnapshot(".", AuditSync, now)
+	require.NoError(t, err)
+	assert.Nil(t, snapshot, "nothing to record without ccmd.yaml or a lock file")
+
+	writeConfig(t, []string{})
+	snapshot, err = takeSnapshot(".", AuditSync, now)
+	require.NoError(t, err)
+	assert.Equal(t, "20250601T120000.000Z", snapshot.ID)
+
+	// Unchanged files share the newest snapshot
+	again, err := takeSnapshot(".", AuditInstall, now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, snapshot.ID, again.ID)
+
+	// Old snapshots are dropped beyond the limit
+	for i := 0; i < SnapshotLimit+5; i++ {
+		writeConfig(t, []string{strings.Repeat("x", i+1)})
+		_, err := takeSnapshot(".", AuditInstall, now.Add(time.Duration(i+2)*time.Minute))
+		require.NoError(t, err)
+	}
+	snapshots, err := ListSnapshots(".")
+	require.NoError(t, err)
+	assert.Len(t, snapshots, SnapshotLimit)
+}
+
+func TestSnapshotTarget(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	dir := t.TempDir()
+	snapshots := make([]Snapshot, 3)
+	for i := range snapshots {
+		snapshots[i] = Snapshot{
+			ID:        string(rune('c' - i)),
+			CreatedAt: now.Add(-time.Duration(i) * time.Hour),
+			dir:       filepath.Join(dir, string(rune('c'-i))),
+		}
+		require.NoError(t, os.MkdirAll(snapshots[i].dir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(snapshots[i].dir, LockFileName), []byte{byte('c' - i)}, 0644))
+	}
+
+	// The newest snapshot equal to the current state is skipped
+	target, err := snapshotTarget(snapshots, projectState{lock: []byte("c")}, "", now)
+	require.NoError(t, err)
+	assert.Equal(t, "b", target.ID)
+
+	target, err = snapshotTarget(snapshots, projectState{}, "a", now)
+	require.NoError(t, err)
+	assert.Equal(t, "a", target.ID)
+
+	// A time restores the state recorded by the first snapshot after it
+	target, err = snapshotTarget(snapshots, projectState{}, "90m", now)
+	require.NoError(t, err)
+	assert.Equal(t, "b", target.ID)
+
+	target, err = snapshotTarget(snapshots, projectState{}, "10m", now)
+	require.NoError(t, err)
+	assert.Equal(t, "c", target.ID)
+	_, err = snapshotTarget(snapshots, projectState{}, "2025-06-02", now)
+	assert.ErrorIs(t, err, errors.ErrNotFound)
+}
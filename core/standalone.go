@@ -0,0 +1,100 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+
+	ccmdfs "github.com/gifflet/ccmd/internal/fs"
+)
+
+// editPolicy decides what happens to standalone files edited by hand when the standalone
+// files of a command are written
+type editPolicy int
+
+const (
+	// leaveEdits leaves edited files alone and reports them
+	leaveEdits editPolicy = iota
+	// keepEdits keeps edited files as local overrides, which are left alone from then on
+	keepEdits
+	// discardEdits regenerates edited files and local overrides from the command's source
+	discardEdits
+)
+
+// StandaloneEdit is a standalone file that was edited by hand since ccmd wrote it
+type StandaloneEdit struct {
+	Command string `json:"command"`
+	File    string `json:"file"`
+}
+
+// standaloneChecksum hashes the content of a standalone file as sha256:<hex>, with line
+// endings normalized like contentChecksum
+func standaloneChecksum(content []byte) string {
+	sum := sha256.Sum256(ccmdfs.NormalizeNewlines(content))
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// standaloneEdited reports whether the standalone file at rel, relative to the project
+// root, is a local override or differs from what ccmd wrote. Files without a record,
+// written before checksums were recorded, are never reported.
+func standaloneEdited(projectRoot, rel string, records map[string]*LockStandalone) bool {
+	record := records[filepath.ToSlash(rel)]
+	if record == nil {
+		return false
+	}
+	content, err := os.ReadFile(filepath.Join(projectRoot, rel))
+	if err != nil {
+		return false
+	}
+	return record.Override || standaloneChecksum(content) != record.Checksum
+}
+
+// lockedStandalone returns a copy of the standalone file records of a command in the lock
+// file, empty when it has none
+func lockedStandalone(projectRoot, name string) map[string]*LockStandalone {
+	records := make(map[string]*LockStandalone)
+	lockPath := lockFilePath(projectRoot)
+	if !fileExists(lockPath) {
+		return records
+	}
+	lockFile, err := ReadLockFile(lockPath)
+	if err != nil {
+		return records
+	}
+	if cmd, ok := lockFile.Commands[name]; ok {
+		for key, record := range cmd.Standalone {
+			copied := *record
+			records[key] = &copied
+		}
+	}
+	return records
+}
+
+// recordStandaloneFiles stores the standalone file records of a command in the lock file
+func recordStandaloneFiles(projectRoot, name string, records map[string]*LockStandalone) error {
+	lockPath := lockFilePath(projectRoot)
+	lockFile, err := ReadLockFile(lockPath)
+	if err != nil {
+		return err
+	}
+	cmd, ok := lockFile.Commands[name]
+	if !ok {
+		return nil
+	}
+
+	cmd.Standalone = records
+	if len(records) == 0 {
+		cmd.Standalone = nil
+	}
+	return WriteLockFile(lockPath, lockFile)
+}
@@ -0,0 +1,96 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStandaloneEdits(t *testing.T) {
+	repo, _ := writeCommandRepo(t)
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	_, _, err := Install(ctx, InstallOptions{Repository: repo, Version: "v1.0.0"})
+	require.NoError(t, err)
+
+	standalonePath := filepath.Join(".claude", "commands", "tool.md")
+	generated, err := os.ReadFile(standalonePath)
+	require.NoError(t, err)
+	record := readLockFile(t).Commands["tool"].Standalone[".claude/commands/tool.md"]
+	require.NotNil(t, record)
+	assert.Equal(t, standaloneChecksum(generated), record.Checksum)
+	assert.False(t, record.Override)
+
+	// A stale file is only out of date, an edited one is reported as such
+	require.NoError(t, os.WriteFile(filepath.Join(".claude", "commands", "tool", "index.md"), []byte("# Changed\n"), 0644))
+	report, err := Verify(VerifyOptions{ProjectPath: "."})
+	require.NoError(t, err)
+	require.Len(t, report.Issues, 1)
+	assert.Contains(t, report.Issues[0].Problem, "out of date")
+
+	edited := append(generated, []byte("My notes\n")...)
+	require.NoError(t, os.WriteFile(standalonePath, edited, 0644))
+	report, err = Verify(VerifyOptions{ProjectPath: "."})
+	require.NoError(t, err)
+	require.Len(t, report.Issues, 1)
+	assert.Equal(t, "tool.md was edited by hand (run ccmd regen tool to keep or discard the edits)", report.Issues[0].Problem)
+
+	// Regen leaves edited files alone unless told otherwise
+	result, err := Regen(RegenOptions{ProjectPath: "."})
+	require.NoError(t, err)
+	assert.Equal(t, []StandaloneEdit{{Command: "tool", File: "tool.md"}}, result.Edited)
+	assert.Empty(t, result.Regenerated)
+	assertFileContent(t, standalonePath, string(edited))
+
+	_, err = Regen(RegenOptions{ProjectPath: ".", KeepEdits: true, DiscardEdits: true})
+	assert.Error(t, err)
+
+	// Kept edits become a local override that verify accepts and reinstalls keep
+	result, err = Regen(RegenOptions{ProjectPath: ".", KeepEdits: true})
+	require.NoError(t, err)
+	assert.Len(t, result.Edited, 1)
+	assert.True(t, readLockFile(t).Commands["tool"].Standalone[".claude/commands/tool.md"].Override)
+
+	report, err = Verify(VerifyOptions{ProjectPath: "."})
+	require.NoError(t, err)
+	assert.True(t, report.OK(), "%v", report.Issues)
+
+	_, _, err = Install(ctx, InstallOptions{Repository: repo, Version: "v1.0.0", Force: true, OverwriteLocal: true})
+	require.NoError(t, err)
+	assertFileContent(t, standalonePath, string(edited))
+	assert.True(t, readLockFile(t).Commands["tool"].Standalone[".claude/commands/tool.md"].Override)
+
+	result, err = Regen(RegenOptions{ProjectPath: "."})
+	require.NoError(t, err)
+	assert.Empty(t, result.Edited)
+	assert.Equal(t, []string{"tool"}, result.Unchanged)
+
+	// Discarding regenerates overrides from the source
+	result, err = Regen(RegenOptions{ProjectPath: ".", DiscardEdits: true})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tool"}, result.Regenerated)
+	assertFileContent(t, standalonePath, string(generated))
+	assert.False(t, readLockFile(t).Commands["tool"].Standalone[".claude/commands/tool.md"].Override)
+}
+
+func assertFileContent(t *testing.T, path, want string) {
+	t.Helper()
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, want, string(content))
+}
@@ -0,0 +1,261 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/gifflet/ccmd/pkg/config"
+	"github.com/gifflet/ccmd/pkg/errors"
+	"github.com/gifflet/ccmd/pkg/output"
+)
+
+// usageStatsDir is the directory under the configured cache_dir holding the usage
+// statistics of each project
+const usageStatsDir = "stats"
+
+// StaleAfter is how long a branch-tracking command can go without updates before it is reported as stale
+const StaleAfter = 30 * 24 * time.Hour
+
+var tagLikeVersion = regexp.MustCompile(`^v?\d+(\.\d+)*([-+].*)?$`)
+
+// CommandStats holds locally recorded usage data for one installed command or plugin
+type CommandStats struct {
+	Name         string    `json:"name"`
+	Type         string    `json:"type"`
+	Version      string    `json:"version"`
+	Ref          string    `json:"ref,omitempty"`
+	InstalledAt  time.Time `json:"installed_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	InstallCount int       `json:"install_count"`
+	UpdateCount  int       `json:"update_count"`
+	TracksBranch bool      `json:"tracks_branch"`
+	Stale        bool      `json:"stale"`
+}
+
+// StatsReport summarizes local usage statistics for a project
+type StatsReport struct {
+	Commands []CommandStats `json:"commands"`
+	LastSync time.Time      `json:"last_sync,omitempty"`
+}
+
+// usageStats is what ccmd records about the use of a project on this machine. It is kept
+// under cache_dir rather than in ccmd-lock.yaml, which is committed and shared.
+type usageStats struct {
+	LastSync time.Time               `json:"last_sync,omitempty"`
+	Commands map[string]*usageCounts `json:"commands,omitempty"`
+	Plugins  map[string]*usageCounts `json:"plugins,omitempty"`
+}
+
+// usageCounts counts the installs and updates of one command or plugin
+type usageCounts struct {
+	InstallCount int `json:"install_count"`
+	UpdateCount  int `json:"update_count"`
+}
+
+// entries returns the counts of commands or plugins, by the kind used in CommandStats
+func (s *usageStats) entries(kind string) map[string]*usageCounts {
+	if kind == "plugin" {
+		if s.Plugins == nil {
+			s.Plugins = make(map[string]*usageCounts)
+		}
+		return s.Plugins
+	}
+	if s.Commands == nil {
+		s.Commands = make(map[string]*usageCounts)
+	}
+	return s.Commands
+}
+
+// usageStatsPath returns the usage statistics file of a project, named after a hash of
+// its absolute path, or "" without a cache_dir
+func usageStatsPath(projectRoot string) (string, error) {
+	settings, err := config.Load("")
+	if err != nil || settings.CacheDir == "" {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(cacheKey(projectRoot)))
+	return filepath.Join(settings.CacheDir, usageStatsDir, hex.EncodeToString(sum[:8])+".json"), nil
+}
+
+// readUsageStats returns the usage statistics of a project, empty when none were recorded
+func readUsageStats(projectRoot string) (*usageStats, error) {
+	stats := &usageStats{}
+	path, err := usageStatsPath(projectRoot)
+	if err != nil || path == "" {
+		return stats, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return stats, nil
+	}
+	if err != nil {
+		return nil, errors.FileError("read usage statistics", path, err)
+	}
+	if err := json.Unmarshal(data, stats); err != nil {
+		return nil, errors.FileError("parse usage statistics", path, err)
+	}
+	return stats, nil
+}
+
+// updateUsageStats applies change to the usage statistics of a project and writes them,
+// serialized with other processes like lock file writes
+func updateUsageStats(projectRoot string, change func(*usageStats)) error {
+	path, err := usageStatsPath(projectRoot)
+	if err != nil || path == "" {
+		return err
+	}
+	release, err := acquireLockWriter(path)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	stats, err := readUsageStats(projectRoot)
+	if err != nil {
+		return err
+	}
+	change(stats)
+	data, err := json.MarshalIndent(stats, "", "  ")
+	if err != nil {
+		return errors.FileError("marshal usage statistics", path, err)
+	}
+	return writeFileAtomic(path, data, 0o600)
+}
+
+// recordInstallStats counts an install of a command or plugin. previous is the name it
+// was installed under before, "" for a first install, which resets the counts.
+// Statistics are best effort: a failure is only reported.
+func recordInstallStats(projectRoot, kind, name, previous string) {
+	err := updateUsageStats(projectRoot, func(stats *usageStats) {
+		entries := stats.entries(kind)
+		counts := &usageCounts{InstallCount: 1}
+		if previous != "" {
+			old := entries[previous]
+			if old == nil {
+				old = &usageCounts{}
+			}
+			counts.InstallCount, counts.UpdateCount = bumpUsageCounts(old.InstallCount, old.UpdateCount)
+			delete(entries, previous)
+		}
+		entries[name] = counts
+	})
+	if err != nil {
+		output.PrintWarningf("Failed to record usage statistics: %v", err)
+	}
+}
+
+// Stats builds a usage report from the project's lock file and the usage statistics
+// recorded on this machine. It never touches the network.
+func Stats(projectPath string) (*StatsReport, error) {
+	projectRoot, err := findProjectRootFrom(projectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &StatsReport{Commands: []CommandStats{}}
+
+	lockPath := lockFilePath(projectRoot)
+	if !fileExists(lockPath) {
+		return report, nil
+	}
+
+	lockFile, err := ReadLockFile(lockPath)
+	if err != nil {
+		return nil, err
+	}
+	usage, err := readUsageStats(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	report.LastSync = usage.LastSync
+
+	for name, cmd := range lockFile.Commands {
+		report.Commands = append(report.Commands, newCommandStats(
+			name, "command", cmd.Version, cmd.Resolved, cmd.InstalledAt, cmd.UpdatedAt,
+			usage.entries("command")[name], now))
+	}
+
+	for name, p := range lockFile.Plugins {
+		report.Commands = append(report.Commands, newCommandStats(
+			name, "plugin", p.Version, p.Resolved, p.InstalledAt, p.UpdatedAt,
+			usage.entries("plugin")[name], now))
+	}
+
+	sort.Slice(report.Commands, func(i, j int) bool {
+		if report.Commands[i].UpdatedAt.Equal(report.Commands[j].UpdatedAt) {
+			return report.Commands[i].Name < report.Commands[j].Name
+		}
+		return report.Commands[i].UpdatedAt.After(report.Commands[j].UpdatedAt)
+	})
+
+	return report, nil
+}
+
+func newCommandStats(
+	name, kind, version, resolved string,
+	installedAt, updatedAt time.Time,
+	counts *usageCounts,
+	now time.Time,
+) CommandStats {
+	_, ref := ParseCommandSpec(resolved)
+	tracksBranch := isBranchRef(ref)
+
+	// Entries installed before counts were recorded were installed at least once
+	installCount, updateCount := 1, 0
+	if counts != nil && counts.InstallCount > 0 {
+		installCount, updateCount = counts.InstallCount, counts.UpdateCount
+	}
+
+	return CommandStats{
+		Name:         name,
+		Type:         kind,
+		Version:      version,
+		Ref:          ref,
+		InstalledAt:  installedAt,
+		UpdatedAt:    updatedAt,
+		InstallCount: installCount,
+		UpdateCount:  updateCount,
+		TracksBranch: tracksBranch,
+		Stale:        tracksBranch && now.Sub(updatedAt) > StaleAfter,
+	}
+}
+
+// isBranchRef reports whether a resolved ref looks like a branch rather than a tag or commit
+func isBranchRef(ref string) bool {
+	if ref == "" || isCommitHash(ref) {
+		return false
+	}
+	return !tagLikeVersion.MatchString(ref)
+}
+
+// bumpUsageCounts returns the counters for a reinstall of an existing entry
+func bumpUsageCounts(installCount, updateCount int) (newInstallCount, newUpdateCount int) {
+	if installCount == 0 {
+		installCount = 1
+	}
+	return installCount, updateCount + 1
+}
+
+// recordSync stores the time of the last successful sync in the usage statistics
+func recordSync(projectRoot string, at time.Time) error {
+	return updateUsageStats(projectRoot, func(stats *usageStats) {
+		stats.LastSync = at
+	})
+}
@@ -0,0 +1,101 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStats(t *testing.T) {
+	t.Run("reports counts, ordering and stale branches", func(t *testing.T) {
+		tempDir := t.TempDir()
+		t.Setenv("CCMD_CACHE_DIR", t.TempDir())
+		now := time.Now()
+
+		lockFile := createBasicLockFile()
+		lockFile.Commands["fresh"] = &LockCommand{
+			Name:        "fresh",
+			Resolved:    "https://github.com/user/fresh.git@v1.2.0",
+			InstalledAt: now.Add(-48 * time.Hour),
+			UpdatedAt:   now,
+		}
+		lockFile.Commands["old-branch"] = &LockCommand{
+			Name:        "old-branch",
+			Resolved:    "https://github.com/user/old-branch.git@main",
+			InstalledAt: now.Add(-90 * 24 * time.Hour),
+			UpdatedAt:   now.Add(-60 * 24 * time.Hour),
+		}
+		writeLockFileToPath(t, filepath.Join(tempDir, LockFileName), lockFile)
+		lastSync := now.Add(-time.Hour)
+		require.NoError(t, updateUsageStats(tempDir, func(stats *usageStats) {
+			stats.LastSync = lastSync
+			stats.entries("command")["fresh"] = &usageCounts{InstallCount: 1, UpdateCount: 3}
+		}))
+
+		report, err := Stats(tempDir)
+		require.NoError(t, err)
+		require.Len(t, report.Commands, 2)
+
+		assert.Equal(t, "fresh", report.Commands[0].Name)
+		assert.Equal(t, 3, report.Commands[0].UpdateCount)
+		assert.False(t, report.Commands[0].TracksBranch)
+
+		assert.Equal(t, "old-branch", report.Commands[1].Name)
+		assert.Equal(t, 1, report.Commands[1].InstallCount)
+		assert.True(t, report.Commands[1].TracksBranch)
+		assert.True(t, report.Commands[1].Stale)
+
+		assert.WithinDuration(t, lastSync, report.LastSync, time.Second)
+	})
+
+	t.Run("returns empty report without lock file", func(t *testing.T) {
+		t.Setenv("CCMD_CACHE_DIR", t.TempDir())
+		report, err := Stats(t.TempDir())
+		require.NoError(t, err)
+		assert.Empty(t, report.Commands)
+		assert.True(t, report.LastSync.IsZero())
+	})
+}
+
+func TestIsBranchRef(t *testing.T) {
+	assert.True(t, isBranchRef("main"))
+	assert.True(t, isBranchRef("feature/x"))
+	assert.False(t, isBranchRef("v1.0.0"))
+	assert.False(t, isBranchRef("1.2"))
+	assert.False(t, isBranchRef("a76c963"))
+	assert.False(t, isBranchRef(""))
+}
+
+func TestUpdateLockFileCounts(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("CCMD_CACHE_DIR", t.TempDir())
+	metadata := &ProjectConfig{
+		Name:       "test-cmd",
+		Version:    "1.0.0",
+		Repository: "https://github.com/user/test-cmd.git",
+	}
+
+	require.NoError(t, updateLockFile(tempDir, "test-cmd", metadata, "1.0.0", "", false))
+	require.NoError(t, updateLockFile(tempDir, "test-cmd", metadata, "1.0.0", "", false))
+
+	usage, err := readUsageStats(tempDir)
+	require.NoError(t, err)
+	assert.Equal(t, &usageCounts{InstallCount: 1, UpdateCount: 1}, usage.entries("command")["test-cmd"])
+
+	data, err := os.ReadFile(filepath.Join(tempDir, LockFileName))
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "install_count")
+}
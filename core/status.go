@@ -0,0 +1,174 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"sort"
+	"time"
+
+	"github.com/gifflet/ccmd/pkg/config"
+)
+
+// StatusIssue is one command reported by Status, with what is wrong with it
+type StatusIssue struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+// StatusUpdate is a newer version of an installed command found in the tag cache
+type StatusUpdate struct {
+	Name    string `json:"name"`
+	Current string `json:"current"`
+	Latest  string `json:"latest"`
+}
+
+// StatusReport is a one-screen overview of a project's installation
+type StatusReport struct {
+	Installed int            `json:"installed"`
+	Plugins   int            `json:"plugins"`
+	Drift     []StatusIssue  `json:"drift"`  // entries out of sync with ccmd.yaml
+	Broken    []StatusIssue  `json:"broken"` // commands with structure problems
+	Updates   []StatusUpdate `json:"updates"`
+	LastSync  time.Time      `json:"last_sync,omitempty"`
+	CacheSize int64          `json:"cache_size"`
+}
+
+// Clean reports whether the installation matches ccmd.yaml and every command is intact
+func (r *StatusReport) Clean() bool {
+	return len(r.Drift) == 0 && len(r.Broken) == 0
+}
+
+// StatusOptions contains options for Status
+type StatusOptions struct {
+	ProjectPath string
+	NoIndex     bool // Check every command instead of the index, then rewrite the index
+}
+
+// Status summarizes the installation of a project from local data only: the lock file,
+// the installed files and the cached tag lists. It never touches the network, so it is
+// fast enough for shell prompts and git hooks. Available updates are as fresh as the
+// tag cache, which syncs and updates refill.
+func Status(opts StatusOptions) (*StatusReport, error) {
+	projectRoot, err := findProjectRootFrom(opts.ProjectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &StatusReport{Drift: []StatusIssue{}, Broken: []StatusIssue{}, Updates: []StatusUpdate{}}
+	if settings, err := config.Load(projectRoot); err == nil && settings.CacheDir != "" && dirExists(settings.CacheDir) {
+		report.CacheSize, _ = dirSize(settings.CacheDir)
+	}
+
+	var lockFile *LockFile
+	if lockPath := lockFilePath(projectRoot); fileExists(lockPath) {
+		if lockFile, err = ReadLockFile(lockPath); err != nil {
+			return nil, err
+		}
+		report.Installed = len(lockFile.Commands)
+		report.Plugins = len(lockFile.Plugins)
+		usage, err := readUsageStats(projectRoot)
+		if err != nil {
+			return nil, err
+		}
+		report.LastSync = usage.LastSync
+	}
+
+	if ProjectConfigExists(projectRoot) {
+		drift, err := CheckLockDrift(projectRoot, "")
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range drift {
+			report.Drift = append(report.Drift, StatusIssue{Name: d.Repository, Reason: d.Reason})
+		}
+		if lockFile != nil {
+			if report.Updates, err = cachedUpdates(projectRoot, lockFile); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if lockFile != nil {
+		index := loadIndex(projectRoot, opts.NoIndex)
+		index.prune(lockFile)
+		defer index.save()
+		for name := range lockFile.Commands {
+			for _, issue := range index.command(projectRoot, name).Issues {
+				report.Broken = append(report.Broken, StatusIssue{Name: name, Reason: issue})
+			}
+		}
+		sort.SliceStable(report.Broken, func(i, j int) bool { return report.Broken[i].Name < report.Broken[j].Name })
+	}
+
+	return report, nil
+}
+
+// cachedUpdates returns the commands whose ccmd.yaml constraint admits a newer tag than
+// the locked one, according to the cached tag lists however old they are. Commands
+// without a constraint or a cached tag list are left out.
+func cachedUpdates(projectRoot string, lockFile *LockFile) ([]StatusUpdate, error) {
+	cfg, err := LoadProjectConfig(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := newTagCache(projectRoot, false)
+	updates := []StatusUpdate{}
+	for _, spec := range cfg.Commands {
+		repo, version, instance := ParseInstanceSpec(spec)
+		if !IsConstraint(version) {
+			continue
+		}
+		name, locked := lockedCommand(lockFile, repo, instance)
+		if locked == nil {
+			continue
+		}
+		constraint, err := ParseConstraint(version)
+		if err != nil {
+			continue
+		}
+
+		repoURL, _ := splitRepositoryCommand(NormalizeRepositoryURL(repo))
+		entry, ok := tags.read(repoURL)
+		if !ok {
+			continue
+		}
+
+		pre := false
+		if cond := cfg.conditionFor(spec); cond != nil && cond.Channel == ChannelBeta {
+			pre = true
+		}
+		latest, ok := highestMatchingTag(entry.Tags, constraint, pre)
+		if !ok {
+			continue
+		}
+		_, current := ParseRepositorySpec(locked.Resolved)
+		if isNewerTag(latest, current) {
+			updates = append(updates, StatusUpdate{Name: name, Current: current, Latest: latest})
+		}
+	}
+
+	sort.Slice(updates, func(i, j int) bool { return updates[i].Name < updates[j].Name })
+	return updates, nil
+}
+
+// isNewerTag reports whether tag is a higher semantic version than current. A current version
+// that is not semantic (a branch or commit) is never behind.
+func isNewerTag(tag, current string) bool {
+	latest, err := ParseSemver(tag)
+	if err != nil {
+		return false
+	}
+	installed, err := ParseSemver(current)
+	if err != nil {
+		return false
+	}
+	return latest.Compare(installed) > 0
+}
@@ -0,0 +1,72 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gifflet/ccmd/pkg/config"
+)
+
+func TestStatus(t *testing.T) {
+	repo, release := writeCommandRepo(t)
+
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	t.Setenv(config.ConfigEnv, filepath.Join(t.TempDir(), "config.yaml"))
+	t.Setenv("CCMD_CACHE_DIR", t.TempDir())
+
+	// A project without anything installed
+	report, err := Status(StatusOptions{ProjectPath: "."})
+	require.NoError(t, err)
+	assert.Zero(t, report.Installed)
+	assert.True(t, report.Clean())
+
+	writeConfig(t, []string{repo + "@^1.0.0"})
+	_, err = Sync(context.Background(), SyncOptions{ProjectPath: "."})
+	require.NoError(t, err)
+
+	report, err = Status(StatusOptions{ProjectPath: "."})
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Installed)
+	assert.True(t, report.Clean())
+	assert.Empty(t, report.Updates)
+	assert.False(t, report.LastSync.IsZero())
+	assert.Positive(t, report.CacheSize)
+
+	// A release only shows once the tag cache knows about it
+	release("v1.1.0")
+	report, err = Status(StatusOptions{ProjectPath: "."})
+	require.NoError(t, err)
+	assert.Empty(t, report.Updates)
+
+	newTagCache(".", false).store(repo, []string{"v1.0.0", "v1.1.0", "v2.0.0", "v1.2.0-beta.1"})
+	report, err = Status(StatusOptions{ProjectPath: "."})
+	require.NoError(t, err)
+	assert.Equal(t, []StatusUpdate{{Name: "tool", Current: "v1.0.0", Latest: "v1.1.0"}}, report.Updates)
+
+	// Drift from ccmd.yaml and broken structures make the installation unclean
+	writeConfig(t, []string{repo + "@^1.0.0", "acme/other@v1.0.0"})
+	require.NoError(t, os.Remove(filepath.Join(".claude", "commands", "tool", ConfigFileName)))
+
+	report, err = Status(StatusOptions{ProjectPath: "."})
+	require.NoError(t, err)
+	assert.False(t, report.Clean())
+	require.Len(t, report.Drift, 1)
+	assert.Equal(t, "acme/other", report.Drift[0].Name)
+	require.NotEmpty(t, report.Broken)
+	assert.Equal(t, "tool", report.Broken[0].Name)
+}
@@ -0,0 +1,54 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gifflet/ccmd/pkg/config"
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+// strictMetadata reports whether the strict_metadata setting of a project is on
+func strictMetadata(projectRoot string) bool {
+	settings, err := config.Load(projectRoot)
+	return err == nil && settings.StrictMetadata
+}
+
+// checkStrictMetadata returns an error naming everything the metadata of a command
+// lacks to be complete: a description, an author, a license, at least one tag and a
+// semantic version
+func checkStrictMetadata(source string, metadata *ProjectConfig) error {
+	var problems []string
+	for _, field := range []struct{ name, value string }{
+		{"description", metadata.Description},
+		{"author", metadata.Author},
+		{"license", metadata.License},
+	} {
+		if strings.TrimSpace(field.value) == "" {
+			problems = append(problems, field.name+" is missing")
+		}
+	}
+	if len(metadata.Tags) == 0 {
+		problems = append(problems, "tags are missing")
+	}
+	if metadata.Version == "" {
+		problems = append(problems, "version is missing")
+	} else if _, err := ParseSemver(metadata.Version); err != nil {
+		problems = append(problems, fmt.Sprintf("version %q is not a semantic version", metadata.Version))
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	return errors.InvalidInput(fmt.Sprintf("metadata of %s is incomplete for strict mode: %s",
+		source, strings.Join(problems, "; ")))
+}
@@ -0,0 +1,81 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckStrictMetadata(t *testing.T) {
+	complete := ProjectConfig{
+		Name:        "tool",
+		Version:     "1.2.0",
+		Description: "A tool",
+		Author:      "acme",
+		License:     "MIT",
+		Tags:        []string{"cli"},
+	}
+	assert.NoError(t, checkStrictMetadata("github.com/acme/tool", &complete))
+
+	incomplete := complete
+	incomplete.License = " "
+	incomplete.Tags = nil
+	incomplete.Version = "latest"
+	err := checkStrictMetadata("github.com/acme/tool", &incomplete)
+	require.Error(t, err)
+	assert.Equal(t, `invalid input: metadata of github.com/acme/tool is incomplete for strict mode: `+
+		`license is missing; tags are missing; version "latest" is not a semantic version`, err.Error())
+
+	incomplete = complete
+	incomplete.Version = ""
+	incomplete.Author = ""
+	err = checkStrictMetadata("github.com/acme/tool", &incomplete)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "author is missing; version is missing")
+}
+
+func TestInstallStrict(t *testing.T) {
+	repo, _ := writeCommandRepo(t)
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	// The test repository has neither a license nor tags
+	_, _, err := Install(ctx, InstallOptions{Repository: repo, Strict: true})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "license is missing; tags are missing")
+	assert.NoDirExists(t, filepath.Join(".claude", "commands", "tool"))
+
+	t.Setenv("CCMD_STRICT_METADATA", "true")
+	_, _, err = Install(ctx, InstallOptions{Repository: repo})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "incomplete for strict mode")
+
+	// Complete metadata installs
+	dir := strings.TrimPrefix(repo, "file://")
+	metadata := "name: tool\nversion: 1.0.0\ndescription: A tool\nauthor: acme\nlicense: MIT\ntags: [cli]\n" +
+		"repository: https://github.com/acme/tool\nentry: index.md\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ConfigFileName), []byte(metadata), 0644))
+	for _, args := range [][]string{{"commit", "--quiet", "--all", "-m", "complete"}, {"tag", "--force", "v1.0.0"}} {
+		out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	_, _, err = Install(ctx, InstallOptions{Repository: repo})
+	require.NoError(t, err)
+	assert.DirExists(t, filepath.Join(".claude", "commands", "tool"))
+}
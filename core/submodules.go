@@ -0,0 +1,135 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+// gitmodulesFile declares the submodules of a repository
+const gitmodulesFile = ".gitmodules"
+
+// hasSubmodules reports whether the checkout in dir declares git submodules
+func hasSubmodules(dir string) bool {
+	return fileExists(filepath.Join(dir, gitmodulesFile))
+}
+
+// gitUpdateSubmodules checks out the submodules of the checkout in dir, recursively, at
+// the commits the checkout records for them. Relative submodule URLs resolve against
+// the origin of the checkout.
+func gitUpdateSubmodules(ctx context.Context, dir string) error {
+	_, err := runGitRemote(ctx, "Submodule update of "+filepath.Base(dir),
+		"-C", dir, "submodule", "update", "--init", "--recursive", "--quiet")
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err != nil {
+		return fmt.Errorf("git submodule update failed: %w", err)
+	}
+	return nil
+}
+
+// gitSubmoduleCommits returns the commit checked out for each submodule in dir, keyed by
+// its slash-separated path. Nested submodules are included.
+func gitSubmoduleCommits(dir string) (map[string]string, error) {
+	git, err := getGitPath()
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := exec.Command(git, "-C", dir, "submodule", "status", "--recursive").Output()
+	if err != nil {
+		return nil, fmt.Errorf("git submodule status failed: %w", err)
+	}
+
+	// Lines are " <commit> <path> (<describe>)", prefixed with - for submodules that are
+	// not checked out, + for a different commit and U for merge conflicts
+	commits := make(map[string]string)
+	for _, line := range strings.Split(string(out), "\n") {
+		if line == "" {
+			continue
+		}
+		state, fields := line[0], strings.Fields(line[1:])
+		if len(fields) < 2 {
+			continue
+		}
+		if state == '-' {
+			return nil, fmt.Errorf("submodule %s is not checked out", fields[1])
+		}
+		commits[fields[1]] = fields[0]
+	}
+	return commits, nil
+}
+
+// checkSubmodulePins fails when the submodules checked out differ from the commits
+// pinned in the lock file
+func checkSubmodulePins(pinned, checkedOut map[string]string) error {
+	paths := make([]string, 0, len(pinned)+len(checkedOut))
+	for path := range pinned {
+		paths = append(paths, path)
+	}
+	for path := range checkedOut {
+		if _, ok := pinned[path]; !ok {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		switch want, got := pinned[path], checkedOut[path]; {
+		case want == "":
+			return errors.Conflict(fmt.Sprintf("submodule %s is not pinned in %s", path, LockFileName))
+		case got == "":
+			return errors.Conflict(fmt.Sprintf("submodule %s pinned in %s is missing", path, LockFileName))
+		case want != got:
+			return errors.Conflict(fmt.Sprintf("submodule %s is at %s, %s pins %s", path, got, LockFileName, want))
+		}
+	}
+	return nil
+}
+
+// lockedSubmodules returns the submodule commits of a command in the lock file
+func lockedSubmodules(projectRoot, name string) map[string]string {
+	lockPath := lockFilePath(projectRoot)
+	if name == "" || !fileExists(lockPath) {
+		return nil
+	}
+	lockFile, err := ReadLockFile(lockPath)
+	if err != nil {
+		return nil
+	}
+	if cmd, ok := lockFile.Commands[name]; ok {
+		return cmd.Submodules
+	}
+	return nil
+}
+
+// recordSubmodules stores the submodule commits of a command in the lock file
+func recordSubmodules(projectRoot, name string, commits map[string]string) error {
+	lockPath := lockFilePath(projectRoot)
+	lockFile, err := ReadLockFile(lockPath)
+	if err != nil {
+		return err
+	}
+	cmd, ok := lockFile.Commands[name]
+	if !ok {
+		return nil
+	}
+
+	cmd.Submodules = commits
+	return WriteLockFile(lockPath, lockFile)
+}
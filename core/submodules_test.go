@@ -0,0 +1,86 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+func TestInstallSubmodules(t *testing.T) {
+	repo, _ := writeCommandRepo(t)
+	shared, _ := writeGitRepo(t)
+	dir := strings.TrimPrefix(repo, "file://")
+	require.NoError(t, os.WriteFile(filepath.Join(shared, "fragment.md"), []byte("Shared fragment\n"), 0644))
+
+	// Local submodule URLs are refused by git unless the file protocol is allowed
+	t.Setenv("GIT_CONFIG_COUNT", "1")
+	t.Setenv("GIT_CONFIG_KEY_0", "protocol.file.allow")
+	t.Setenv("GIT_CONFIG_VALUE_0", "always")
+	git := func(dir string, args ...string) {
+		out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	git(shared, "add", "fragment.md")
+	git(shared, "commit", "--quiet", "-m", "fragment")
+	git(dir, "submodule", "--quiet", "add", fileURL(shared), "shared")
+	git(dir, "commit", "--quiet", "-m", "submodule")
+	git(dir, "tag", "--force", "v1.0.0")
+	pinned, err := gitGetCurrentCommit(shared)
+	require.NoError(t, err)
+
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	_, _, err = Install(ctx, InstallOptions{Repository: repo, Version: "v1.0.0"})
+	require.NoError(t, err)
+	assertFileContent(t, filepath.Join(".claude", "commands", "tool", "shared", "fragment.md"), "Shared fragment\n")
+	locked := readLockFile(t).Commands["tool"]
+	require.NotNil(t, locked)
+	assert.Equal(t, map[string]string{"shared": pinned}, locked.Submodules)
+
+	report, err := Verify(VerifyOptions{ProjectPath: "."})
+	require.NoError(t, err)
+	assert.True(t, report.OK(), "%v", report.Issues)
+
+	// Frozen installs check the checked out submodules against the lock file
+	lockFile := readLockFile(t)
+	lockFile.Commands["tool"].Submodules["shared"] = "0123456789abcdef0123456789abcdef01234567"
+	writeLockFile(t, lockFile)
+	opts := frozenInstallOptions("tool", locked.Source, locked.Resolved, locked.Commit)
+	opts.Force = true
+	_, _, err = Install(ctx, opts)
+	assert.ErrorIs(t, err, errors.ErrConflict)
+
+	// Submodules can be skipped
+	_, _, err = Install(ctx, InstallOptions{Repository: repo, Version: "v1.0.0", Force: true, NoSubmodules: true})
+	require.NoError(t, err)
+	assert.NoFileExists(t, filepath.Join(".claude", "commands", "tool", "shared", "fragment.md"))
+	assert.Empty(t, readLockFile(t).Commands["tool"].Submodules)
+}
+
+func TestCheckSubmodulePins(t *testing.T) {
+	pinned := map[string]string{"a": "1111111", "b": "2222222"}
+
+	assert.NoError(t, checkSubmodulePins(pinned, map[string]string{"a": "1111111", "b": "2222222"}))
+	assert.ErrorIs(t, checkSubmodulePins(pinned, map[string]string{"a": "1111111", "b": "3333333"}), errors.ErrConflict)
+	assert.ErrorIs(t, checkSubmodulePins(pinned, map[string]string{"a": "1111111"}), errors.ErrConflict)
+	assert.ErrorIs(t, checkSubmodulePins(pinned, map[string]string{"a": "1111111", "b": "2222222", "c": "4444444"}), errors.ErrConflict)
+}
@@ -12,7 +12,12 @@ package core
 import (
 	"context"
 	"fmt"
-	"strings"
+	"sort"
+	"time"
+
+	"github.com/gifflet/ccmd/pkg/errors"
+	"github.com/gifflet/ccmd/pkg/output"
+	"github.com/gifflet/ccmd/pkg/telemetry"
 )
 
 // SyncOptions represents options for syncing commands
@@ -20,31 +25,76 @@ type SyncOptions struct {
 	ProjectPath string
 	DryRun      bool
 	Force       bool
+	Prune       bool   // Move orphaned command files to the trash
+	Profile     string // Also sync the commands of this ccmd.yaml profile
+	Refresh     bool   // List remote tags again instead of using the tag cache
+	// Frozen installs exactly what ccmd-lock.yaml records and fails when it does not match
+	// ccmd.yaml, without writing either file
+	Frozen bool
+	// AdoptEphemeral records the commands installed with --no-save in ccmd.yaml;
+	// PruneEphemeral removes them instead
+	AdoptEphemeral bool
+	PruneEphemeral bool
+	// Plan, computed by ComputePlan, is applied exactly instead of analyzing the project.
+	// The sync fails when the project changed since the plan was made.
+	Plan *Plan
+	// Progress receives the steps of each install, as InstallOptions.Progress does
+	Progress ProgressFunc
 }
 
 // SyncAnalysis represents the analysis of what needs to be synced
 type SyncAnalysis struct {
 	ToInstall []ConfigCommand
 	ToRemove  []string
-	InSync    bool
+	// Ephemeral lists the commands and plugins installed with --no-save. Sync leaves them
+	// alone unless asked to adopt them into ccmd.yaml or prune them.
+	Ephemeral []string
+	// Skipped lists the entries whose os or when condition does not hold on this machine
+	Skipped []string
+	InSync  bool
 }
 
 // SyncResult represents the result of a sync operation
 type SyncResult struct {
 	Installed []string
+	Updated   []string // commands reinstalled at another version (only when applying a plan)
 	Removed   []string
 	Failed    []SyncError
+	Orphans   []Orphan // untracked command files found after syncing
+	Pruned    []string // orphans moved to the trash (with Prune)
+	Adopted   []string // ephemeral commands added to ccmd.yaml (with AdoptEphemeral)
 }
 
 // SyncError represents an error during sync operation
 type SyncError struct {
 	Command   string
-	Operation string // "install" or "remove"
+	Operation string // "install", "remove", "prune" or "adopt"
 	Error     error
 }
 
-// AnalyzeSync analyzes what needs to be synced between config and installed commands
-func AnalyzeSync(projectPath string) (*SyncAnalysis, error) {
+// Err returns the failed operations as a batch error, or nil when none failed
+func (r *SyncResult) Err() error {
+	failures := make([]*errors.Failure, 0, len(r.Failed))
+	for _, failed := range r.Failed {
+		failure := &errors.Failure{Name: failed.Command, Stage: failed.Operation, Err: failed.Error}
+		if failed.Operation == "install" {
+			// Installs are keyed by the repository ccmd.yaml lists
+			failure.Repo = failed.Command
+		}
+		failures = append(failures, failure)
+	}
+	return errors.Batch(fmt.Sprintf("sync failed with %d error(s)", len(r.Failed)), failures)
+}
+
+// AnalyzeSync analyzes what needs to be synced between config and installed commands.
+// With a profile, its commands are installed too and commands of other profiles are
+// removed; without one, commands listed in any profile are left alone.
+func AnalyzeSync(projectPath, profile string) (*SyncAnalysis, error) {
+	// Report every invalid entry before anything is installed
+	if problems, err := CheckProjectConfig(projectPath); err == nil && len(problems) > 0 {
+		return nil, problems
+	}
+
 	// Load project config
 	config, err := LoadProjectConfig(projectPath)
 	if err != nil {
@@ -63,11 +113,29 @@ func AnalyzeSync(projectPath string) (*SyncAnalysis, error) {
 		installedMap[cmd.Name] = cmd
 	}
 
-	configCommands := config.GetConfigCommands()
+	installedRepos := make(map[string]string)
+	for _, cmd := range installed {
+		if cmd.Repository != "" && !cmd.Instance {
+			installedRepos[ExtractRepoPath(cmd.Repository)] = cmd.Name
+		}
+	}
+
+	specs, err := config.CommandsForProfile(profile)
+	if err != nil {
+		return nil, err
+	}
+	configCommands := (&ProjectConfig{Commands: specs}).GetConfigCommands()
 	configMap := make(map[string]ConfigCommand)
 	for _, cmd := range configCommands {
-		// Extract name from repo
-		name := extractCommandName(cmd.Repo)
+		// Commands installed under another name (metadata name or --rename) match by
+		// repository; side-by-side installs by their instance name
+		name, ok := cmd.Name, cmd.Name != ""
+		if !ok {
+			name, ok = installedRepos[ExtractRepoPath(cmd.Repo)]
+		}
+		if !ok {
+			name = extractCommandName(cmd.Repo)
+		}
 		configMap[name] = cmd
 	}
 
@@ -75,38 +143,102 @@ func AnalyzeSync(projectPath string) (*SyncAnalysis, error) {
 	var toInstall []ConfigCommand
 	var toRemove []string
 
-	// Find commands to install
+	// Find commands to install, including locked ones whose files are missing, as in a
+	// fresh checkout that does not commit .claude/commands
+	projectRoot, _ := findProjectRootFrom(projectPath)
 	for name, cmd := range configMap {
-		if _, exists := installedMap[name]; !exists {
+		if _, exists := installedMap[name]; !exists || !dirExists(installedCommandDir(projectRoot, name)) {
 			toInstall = append(toInstall, cmd)
 		}
 	}
 
 	// Find commands to remove
-	for name := range installedMap {
-		if _, exists := configMap[name]; !exists {
-			toRemove = append(toRemove, name)
+	var ephemeral []string
+	for name, cmd := range installedMap {
+		if _, exists := configMap[name]; exists {
+			continue
+		}
+		if cmd.Ephemeral {
+			ephemeral = append(ephemeral, name)
+			continue
+		}
+		if cmd.LocalOnly {
+			// Local commands live in the project, ccmd.yaml never lists them
+			continue
+		}
+		key := ExtractRepoPath(cmd.Repository)
+		if cmd.Instance {
+			key = instanceKey(key, name)
+		}
+		if profile == "" && cmd.Repository != "" && config.inAnyProfile(key) {
+			continue
+		}
+		toRemove = append(toRemove, name)
+	}
+
+	var skipped []string
+	for _, spec := range append(append([]string{}, config.Commands...), config.Profiles[profile]...) {
+		if !config.applies(spec) {
+			skipped = append(skipped, spec)
 		}
 	}
 
+	sort.Strings(ephemeral)
 	return &SyncAnalysis{
 		ToInstall: toInstall,
 		ToRemove:  toRemove,
+		Ephemeral: ephemeral,
+		Skipped:   skipped,
 		InSync:    len(toInstall) == 0 && len(toRemove) == 0,
 	}, nil
 }
 
-// Sync synchronizes installed commands with the project configuration
+// Sync synchronizes installed commands with the project configuration. When ctx is
+// cancelled, Sync stops before the next command and returns the partial result with
+// the context error; the command being installed is cleaned up.
 func Sync(ctx context.Context, opts SyncOptions) (*SyncResult, error) {
+	ctx, span := telemetry.Start(ctx, "sync", telemetry.Bool("dry_run", opts.DryRun), telemetry.Bool("plan", opts.Plan != nil))
+	result, err := syncProject(ctx, opts)
+	if result != nil {
+		span.SetAttributes(
+			telemetry.Int("installed", int64(len(result.Installed))),
+			telemetry.Int("updated", int64(len(result.Updated))),
+			telemetry.Int("removed", int64(len(result.Removed))),
+			telemetry.Int("failed", int64(len(result.Failed))),
+		)
+	}
+	span.End(err)
+	return result, err
+}
+
+func syncProject(ctx context.Context, opts SyncOptions) (*SyncResult, error) {
+	if !opts.DryRun {
+		if projectRoot, err := findProjectRootFrom(opts.ProjectPath); err == nil {
+			defer snapshotBefore(projectRoot, AuditSync)()
+		}
+	}
+
+	if opts.Plan != nil {
+		return applyPlan(ctx, opts)
+	}
+
+	// A shared base may have changed since it was cached
+	if projectRoot, err := findProjectRootFrom(opts.ProjectPath); err == nil {
+		if err := refreshBaseConfig(ctx, projectRoot); err != nil {
+			return nil, err
+		}
+	}
+
 	// Analyze what needs to be done
-	analysis, err := AnalyzeSync(opts.ProjectPath)
+	analysis, err := AnalyzeSync(opts.ProjectPath, opts.Profile)
 	if err != nil {
 		return nil, err
 	}
 
-	// If in sync, return empty result
-	if analysis.InSync {
-		return &SyncResult{}, nil
+	if opts.Frozen {
+		if err := VerifyLock(opts.ProjectPath, opts.Profile); err != nil {
+			return nil, err
+		}
 	}
 
 	// If dry run, return without making changes
@@ -114,23 +246,72 @@ func Sync(ctx context.Context, opts SyncOptions) (*SyncResult, error) {
 		return &SyncResult{}, nil
 	}
 
+	// If in sync, only orphaned files can need attention
+	if analysis.InSync {
+		result := &SyncResult{}
+		settleEphemeral(opts, analysis.Ephemeral, result)
+		collectOrphans(opts, result)
+		refreshStandaloneDocs(opts.ProjectPath, "")
+		if !opts.Frozen {
+			markSynced(opts.ProjectPath)
+		}
+		if len(result.Pruned) > 0 || len(result.Adopted) > 0 || len(result.Removed) > 0 || len(result.Failed) > 0 {
+			auditSync(opts.ProjectPath, result, nil)
+		}
+		return result, nil
+	}
+
 	result := &SyncResult{
 		Installed: []string{},
 		Removed:   []string{},
 		Failed:    []SyncError{},
 	}
 
+	var lockFile *LockFile
+	projectRoot, err := findProjectRootFrom(opts.ProjectPath)
+	if err == nil {
+		lockFile, _ = ReadLockFile(lockFilePath(projectRoot))
+		ctx = withProjectRoot(ctx, projectRoot)
+	}
+
+	// Resolve the versions of every command up front, in parallel. Frozen installs use the
+	// locked refs instead.
+	tags := newTagCache(projectRoot, opts.Refresh)
+	if !opts.Frozen {
+		tags.prefetch(ctx, tagRepositories(projectRoot, analysis.ToInstall), configuredJobs(projectRoot))
+	}
+
 	// Install missing commands
 	for _, cmd := range analysis.ToInstall {
-		repository := normalizeRepository(cmd.Repo)
+		if ctx.Err() != nil {
+			break
+		}
+
+		repository := normalizeRepositoryURL(projectRoot, cmd.Repo)
 
 		installOpts := InstallOptions{
 			Repository: repository,
 			Version:    cmd.Version,
+			As:         cmd.Name,
 			Force:      false,
+			tags:       tags,
 		}
+		if cmd.Name == "" {
+			installOpts.Rename = resolveNameFromLock(projectRoot, lockFile, cmd.Repo)
+		}
+		if opts.Frozen {
+			if name, locked := lockedCommand(lockFile, cmd.Repo, cmd.Name); locked != nil {
+				installOpts = frozenInstallOptions(name, locked.Source, locked.Resolved, locked.Commit)
+				installOpts.As = cmd.Name
+			}
+		}
+		installOpts.Progress = opts.Progress
+		installOpts.ProjectPath = opts.ProjectPath
 
 		if _, _, err := Install(ctx, installOpts); err != nil {
+			if ctx.Err() != nil {
+				break
+			}
 			result.Failed = append(result.Failed, SyncError{
 				Command:   cmd.Repo,
 				Operation: "install",
@@ -143,6 +324,10 @@ func Sync(ctx context.Context, opts SyncOptions) (*SyncResult, error) {
 
 	// Remove extra commands
 	for _, name := range analysis.ToRemove {
+		if ctx.Err() != nil {
+			break
+		}
+
 		removeOpts := RemoveOptions{
 			Name:        name,
 			Force:       opts.Force,
@@ -160,20 +345,141 @@ func Sync(ctx context.Context, opts SyncOptions) (*SyncResult, error) {
 		}
 	}
 
+	if ctx.Err() == nil {
+		settleEphemeral(opts, analysis.Ephemeral, result)
+	}
+
+	return finishSync(ctx, opts, result)
+}
+
+// settleEphemeral adopts or prunes the commands installed with --no-save, as the sync
+// options ask. Frozen syncs write neither ccmd.yaml nor the lock file and leave them.
+func settleEphemeral(opts SyncOptions, names []string, result *SyncResult) {
+	if opts.Frozen || (!opts.AdoptEphemeral && !opts.PruneEphemeral) {
+		return
+	}
+
+	for _, name := range names {
+		if opts.AdoptEphemeral {
+			if err := AdoptEphemeral(opts.ProjectPath, name); err != nil {
+				result.Failed = append(result.Failed, SyncError{Command: name, Operation: "adopt", Error: err})
+			} else {
+				result.Adopted = append(result.Adopted, name)
+			}
+			continue
+		}
+
+		if err := Remove(RemoveOptions{Name: name, Force: true, ProjectPath: opts.ProjectPath}); err != nil {
+			result.Failed = append(result.Failed, SyncError{Command: name, Operation: "remove", Error: err})
+		} else {
+			result.Removed = append(result.Removed, name)
+		}
+	}
+}
+
+// finishSync records the outcome of a sync that made changes, after handling orphaned
+// files and regenerating the standalone docs
+func finishSync(ctx context.Context, opts SyncOptions, result *SyncResult) (*SyncResult, error) {
+	if err := ctx.Err(); err != nil {
+		auditSync(opts.ProjectPath, result, err)
+		return result, err
+	}
+
+	collectOrphans(opts, result)
+	refreshStandaloneDocs(opts.ProjectPath, "")
+	if !opts.Frozen {
+		markSynced(opts.ProjectPath)
+	}
+	auditSync(opts.ProjectPath, result, nil)
+
 	return result, nil
 }
 
-// normalizeRepository converts a short repo reference to a full URL
-func normalizeRepository(repo string) string {
-	if isFullURL(repo) {
-		return repo
+// tagRepositories returns the repositories whose tags an install resolves: those
+// without a version or with a version constraint. Archives and markdown files have no tags.
+func tagRepositories(projectRoot string, commands []ConfigCommand) []string {
+	var repos []string
+	seen := make(map[string]bool)
+	for _, cmd := range commands {
+		if isDownloadSource(cmd.Repo) {
+			continue
+		}
+		repo, version := ParseRepositorySpec(normalizeRepositoryURL(projectRoot, cmd.Repo))
+		if cmd.Version != "" {
+			version = cmd.Version
+		}
+		if version != "" && !IsConstraint(version) {
+			continue
+		}
+		repoURL := normalizeRepositoryURL(projectRoot, repo)
+		if !seen[repoURL] {
+			seen[repoURL] = true
+			repos = append(repos, repoURL)
+		}
+	}
+	return repos
+}
+
+// collectOrphans records untracked command files in the result and, with opts.Prune,
+// moves them to the trash
+func collectOrphans(opts SyncOptions, result *SyncResult) {
+	orphans, err := FindOrphans(opts.ProjectPath)
+	if err != nil {
+		output.PrintWarningf("Failed to scan for orphaned commands: %v", err)
+		return
+	}
+	result.Orphans = orphans
+
+	if !opts.Prune || len(orphans) == 0 {
+		return
+	}
+
+	pruned, err := PruneOrphans(opts.ProjectPath, orphans)
+	result.Pruned = pruned
+	if err != nil {
+		result.Failed = append(result.Failed, SyncError{
+			Command:   orphans[len(pruned)].Name,
+			Operation: "prune",
+			Error:     err,
+		})
+	}
+}
+
+// auditSync records the outcome of a sync that changed something or was cancelled
+func auditSync(projectPath string, result *SyncResult, syncErr error) {
+	projectRoot, err := findProjectRootFrom(projectPath)
+	if err != nil {
+		return
+	}
+
+	event := AuditEvent{
+		Action: AuditSync,
+		Details: fmt.Sprintf("%d installed, %d removed, %d failed",
+			len(result.Installed), len(result.Removed), len(result.Failed)),
+	}
+	if len(result.Updated) > 0 {
+		event.Details += fmt.Sprintf(", %d updated", len(result.Updated))
 	}
-	return "https://github.com/" + repo + ".git"
+	if len(result.Pruned) > 0 {
+		event.Details += fmt.Sprintf(", %d pruned", len(result.Pruned))
+	}
+	if len(result.Adopted) > 0 {
+		event.Details += fmt.Sprintf(", %d adopted", len(result.Adopted))
+	}
+	if len(result.Failed) > 0 {
+		event.Result = AuditFailure
+	}
+	recordAudit(projectRoot, auditResult(event, syncErr))
 }
 
-// isFullURL checks if a string is a full URL
-func isFullURL(s string) bool {
-	return strings.HasPrefix(s, "http://") ||
-		strings.HasPrefix(s, "https://") ||
-		strings.HasPrefix(s, "git@")
+// markSynced records the sync time when the project has a lock file
+func markSynced(projectPath string) {
+	projectRoot, err := findProjectRootFrom(projectPath)
+	if err != nil || !fileExists(lockFilePath(projectRoot)) {
+		return
+	}
+
+	if err := recordSync(projectRoot, time.Now()); err != nil {
+		output.PrintWarningf("Failed to record sync time: %v", err)
+	}
 }
@@ -0,0 +1,146 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gifflet/ccmd/pkg/config"
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+func TestAnalyzeSyncProfiles(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+
+	writeConfigMap(t, map[string]interface{}{
+		"commands": []string{"acme/shared"},
+		"profiles": map[string][]string{
+			"backend": {"acme/api"},
+			"docs":    {"acme/writer"},
+		},
+	})
+
+	lockFile := createBasicLockFile()
+	for _, name := range []string{"shared", "writer"} {
+		lockFile.Commands[name] = createTestLockCommand(name, "1.0.0", "https://github.com/acme/"+name+".git")
+		createCommandStructure(t, name)
+	}
+	writeLockFile(t, lockFile)
+
+	// Without a profile, commands from profiles are kept but not installed
+	analysis, err := AnalyzeSync("", "")
+	require.NoError(t, err)
+	assert.True(t, analysis.InSync)
+
+	// With a profile, its commands are installed and other profiles' commands removed
+	analysis, err = AnalyzeSync("", "backend")
+	require.NoError(t, err)
+	require.Len(t, analysis.ToInstall, 1)
+	assert.Equal(t, "acme/api", analysis.ToInstall[0].Repo)
+	assert.Equal(t, []string{"writer"}, analysis.ToRemove)
+
+	analysis, err = AnalyzeSync("", "docs")
+	require.NoError(t, err)
+	assert.True(t, analysis.InSync)
+
+	_, err = AnalyzeSync("", "frontend")
+	assert.ErrorIs(t, err, errors.ErrNotFound)
+}
+
+func TestAnalyzeSyncInstances(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+
+	writeConfig(t, []string{"acme/tool@v2.0.0", "acme/tool@v1.0.0 as tool-v1"})
+
+	lockFile := createBasicLockFile()
+	lockFile.Commands["tool"] = createTestLockCommand("tool", "2.0.0", "https://github.com/acme/tool.git")
+	createCommandStructure(t, "tool")
+	writeLockFile(t, lockFile)
+
+	analysis, err := AnalyzeSync("", "")
+	require.NoError(t, err)
+	assert.Equal(t, []ConfigCommand{{Repo: "acme/tool", Version: "v1.0.0", Name: "tool-v1"}}, analysis.ToInstall)
+	assert.Empty(t, analysis.ToRemove)
+
+	lockFile.Commands["tool-v1"] = createTestLockCommand("tool-v1", "1.0.0", "https://github.com/acme/tool.git")
+	lockFile.Commands["tool-v1"].Instance = true
+	createCommandStructure(t, "tool-v1")
+	writeLockFile(t, lockFile)
+
+	analysis, err = AnalyzeSync("", "")
+	require.NoError(t, err)
+	assert.True(t, analysis.InSync)
+
+	// Dropping the instance from ccmd.yaml removes it and keeps the regular install
+	writeConfig(t, []string{"acme/tool@v2.0.0"})
+	analysis, err = AnalyzeSync("", "")
+	require.NoError(t, err)
+	assert.Empty(t, analysis.ToInstall)
+	assert.Equal(t, []string{"tool-v1"}, analysis.ToRemove)
+}
+
+func TestSyncResultErr(t *testing.T) {
+	result := &SyncResult{Installed: []string{"acme/shared"}}
+	assert.NoError(t, result.Err())
+
+	result.Failed = []SyncError{
+		{Command: "acme/api", Operation: "install", Error: errors.NotFound("tag v2.0.0")},
+		{Command: "writer", Operation: "remove", Error: errors.FileError("remove", "writer", nil)},
+	}
+	err := result.Err()
+	require.Error(t, err)
+	assert.Equal(t, "sync failed with 2 error(s)", err.Error())
+	assert.Equal(t, errors.CodePartialFailure, errors.Code(err))
+
+	batch := errors.AsBatch(err)
+	require.NotNil(t, batch)
+	assert.Equal(t, "acme/api", batch.Failures[0].Repo)
+	assert.Empty(t, batch.Failures[1].Repo)
+	assert.Equal(t, "remove", batch.Failures[1].Stage)
+}
+
+func TestSyncInSyncLeavesLockFile(t *testing.T) {
+	repo, _ := writeCommandRepo(t)
+
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	t.Setenv(config.ConfigEnv, filepath.Join(t.TempDir(), "config.yaml"))
+	t.Setenv("CCMD_CACHE_DIR", t.TempDir())
+
+	writeConfig(t, []string{repo + "@^1.0.0"})
+	_, err := Sync(context.Background(), SyncOptions{ProjectPath: "."})
+	require.NoError(t, err)
+	before, err := os.ReadFile(LockFileName)
+	require.NoError(t, err)
+	info, err := os.Stat(LockFileName)
+	require.NoError(t, err)
+
+	_, err = Sync(context.Background(), SyncOptions{ProjectPath: "."})
+	require.NoError(t, err)
+	after, err := os.ReadFile(LockFileName)
+	require.NoError(t, err)
+	assert.Equal(t, string(before), string(after))
+	assert.NotContains(t, string(after), "last_sync")
+	afterInfo, err := os.Stat(LockFileName)
+	require.NoError(t, err)
+	assert.Equal(t, info.ModTime(), afterInfo.ModTime())
+
+	report, err := Stats(".")
+	require.NoError(t, err)
+	assert.False(t, report.LastSync.IsZero())
+}
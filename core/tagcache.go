@@ -0,0 +1,192 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/gifflet/ccmd/pkg/config"
+)
+
+// tagCacheDir is the directory under the configured cache_dir holding cached tag lists
+const tagCacheDir = "tags"
+
+// tagCacheEntry is the cached tag list of one repository
+type tagCacheEntry struct {
+	Repository string    `json:"repository"`
+	FetchedAt  time.Time `json:"fetched_at"`
+	Tags       []string  `json:"tags"`
+}
+
+// tagListing is the outcome of one ls-remote call
+type tagListing struct {
+	tags []string
+	err  error
+}
+
+// tagCache remembers the tags of remote repositories. Within a run each repository is
+// listed at most once; tag lists younger than the staleness budget (tag_cache_ttl) are
+// reused from disk across runs, so repeated syncs do not hit the remotes at all.
+type tagCache struct {
+	dir     string        // on-disk cache, empty when disabled
+	ttl     time.Duration // staleness budget for on-disk entries
+	refresh bool          // ignore on-disk entries, but still store fresh listings
+	now     func() time.Time
+
+	mu       sync.Mutex
+	listings map[string]tagListing
+}
+
+// newTagCache returns a cache configured from the layered settings of a project.
+// With refresh, cached tag lists are ignored and replaced.
+func newTagCache(projectRoot string, refresh bool) *tagCache {
+	cache := &tagCache{refresh: refresh, now: time.Now, listings: make(map[string]tagListing)}
+
+	settings, err := config.Load(projectRoot)
+	if err != nil || settings.CacheDir == "" || settings.TagCacheTTL <= 0 {
+		return cache
+	}
+	cache.dir = filepath.Join(settings.CacheDir, tagCacheDir)
+	cache.ttl = time.Duration(settings.TagCacheTTL) * time.Second
+	return cache
+}
+
+// list returns the tags of repo. A nil cache lists them from the remote every time.
+func (c *tagCache) list(ctx context.Context, repo string) ([]string, error) {
+	// The commands of a multi-command repository share its tags
+	repo, _ = splitRepositoryCommand(repo)
+	if c == nil {
+		return gitListRemoteTags(ctx, repo)
+	}
+
+	c.mu.Lock()
+	listing, ok := c.listings[repo]
+	c.mu.Unlock()
+	if ok {
+		return listing.tags, listing.err
+	}
+
+	if !c.refresh {
+		if tags, ok := c.load(repo); ok {
+			c.remember(repo, tagListing{tags: tags})
+			return tags, nil
+		}
+	}
+
+	tags, err := gitListRemoteTags(ctx, repo)
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	c.remember(repo, tagListing{tags: tags, err: err})
+	if err == nil {
+		c.store(repo, tags)
+	}
+	return tags, err
+}
+
+// prefetch lists the tags of every repository in parallel, at most jobs at a time.
+// Failures are remembered and reported when the tags are used.
+func (c *tagCache) prefetch(ctx context.Context, repos []string, jobs int) {
+	if jobs < 1 {
+		jobs = 1
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, jobs)
+	for _, repo := range repos {
+		wg.Add(1)
+		go func(repo string) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			defer func() { <-sem }()
+			_, _ = c.list(ctx, repo)
+		}(repo)
+	}
+	wg.Wait()
+}
+
+func (c *tagCache) remember(repo string, listing tagListing) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.listings[repo] = listing
+}
+
+// path returns the cache file of a repository
+func (c *tagCache) path(repo string) string {
+	sum := sha256.Sum256([]byte(repo))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:8])+".json")
+}
+
+// load returns the cached tags of repo when they are within the staleness budget
+func (c *tagCache) load(repo string) ([]string, bool) {
+	entry, ok := c.read(repo)
+	if !ok {
+		return nil, false
+	}
+	if age := c.now().Sub(entry.FetchedAt); age < 0 || age > c.ttl {
+		return nil, false
+	}
+	return entry.Tags, true
+}
+
+// read returns the cached tag list of repo, however old it is
+func (c *tagCache) read(repo string) (*tagCacheEntry, bool) {
+	if c.dir == "" {
+		return nil, false
+	}
+
+	data, err := os.ReadFile(c.path(repo))
+	if err != nil {
+		return nil, false
+	}
+
+	var entry tagCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.Repository != repo {
+		return nil, false
+	}
+	return &entry, true
+}
+
+// store writes a fresh tag list to disk. The cache is an optimization, so failures
+// are ignored.
+func (c *tagCache) store(repo string, tags []string) {
+	if c.dir == "" {
+		return
+	}
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return
+	}
+
+	data, err := json.Marshal(tagCacheEntry{Repository: repo, FetchedAt: c.now().UTC(), Tags: tags})
+	if err != nil {
+		return
+	}
+	_ = writeFileAtomic(c.path(repo), data, 0644)
+}
+
+// configuredJobs returns the configured number of parallel operations
+func configuredJobs(projectRoot string) int {
+	settings, err := config.Load(projectRoot)
+	if err != nil || settings.Jobs < 1 {
+		return 1
+	}
+	return settings.Jobs
+}
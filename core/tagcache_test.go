@@ -0,0 +1,95 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTagCache(t *testing.T) {
+	cacheDir := t.TempDir()
+	t.Setenv("CCMD_CACHE_DIR", cacheDir)
+	t.Setenv("CCMD_TAG_CACHE_TTL", "60")
+
+	// A repository that does not exist, so every ls-remote fails
+	repo := filepath.Join(t.TempDir(), "missing")
+	now := time.Now()
+
+	cache := newTagCache("", false)
+	require.Equal(t, filepath.Join(cacheDir, tagCacheDir), cache.dir)
+	cache.now = func() time.Time { return now }
+	cache.store(repo, []string{"v1.0.0", "v1.1.0"})
+
+	tags, err := cache.list(context.Background(), repo)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"v1.0.0", "v1.1.0"}, tags)
+
+	// Entries older than the staleness budget are listed again
+	stale := newTagCache("", false)
+	stale.now = func() time.Time { return now.Add(2 * time.Minute) }
+	_, err = stale.list(context.Background(), repo)
+	assert.Error(t, err)
+
+	// --refresh ignores fresh entries too
+	_, err = newTagCache("", true).list(context.Background(), repo)
+	assert.Error(t, err)
+}
+
+func TestTagCacheDisabled(t *testing.T) {
+	t.Setenv("CCMD_CACHE_DIR", t.TempDir())
+	t.Setenv("CCMD_TAG_CACHE_TTL", "0")
+
+	cache := newTagCache("", false)
+	assert.Empty(t, cache.dir)
+
+	repo := filepath.Join(t.TempDir(), "missing")
+	cache.store(repo, []string{"v1.0.0"})
+	_, ok := cache.load(repo)
+	assert.False(t, ok)
+}
+
+func TestTagCachePrefetch(t *testing.T) {
+	t.Setenv("CCMD_CACHE_DIR", t.TempDir())
+
+	cache := newTagCache("", false)
+	repos := []string{filepath.Join(t.TempDir(), "a"), filepath.Join(t.TempDir(), "b")}
+	cache.prefetch(context.Background(), repos, 2)
+
+	// Failures are remembered, so installs do not list the remotes again
+	for _, repo := range repos {
+		listing, ok := cache.listings[repo]
+		require.True(t, ok)
+		assert.Error(t, listing.err)
+	}
+
+	var nilCache *tagCache
+	_, err := nilCache.list(context.Background(), repos[0])
+	assert.Error(t, err)
+}
+
+func TestTagRepositories(t *testing.T) {
+	repos := tagRepositories(".", []ConfigCommand{
+		{Repo: "github.com/acme/latest"},
+		{Repo: "github.com/acme/range", Version: "^1.0.0"},
+		{Repo: "github.com/acme/exact", Version: "v1.0.0"},
+		{Repo: "github.com/acme/latest"},
+		{Repo: "https://example.com/hello.tgz"},
+	})
+	assert.Equal(t, []string{
+		NormalizeRepositoryURL("github.com/acme/latest"),
+		NormalizeRepositoryURL("github.com/acme/range"),
+	}, repos)
+}
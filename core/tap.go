@@ -0,0 +1,271 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gifflet/ccmd/pkg/config"
+	"github.com/gifflet/ccmd/pkg/errors"
+	"github.com/gifflet/ccmd/pkg/output"
+)
+
+// tapCacheDir is the directory under the configured cache_dir holding tap catalogs
+const tapCacheDir = "taps"
+
+// tapNamePattern keeps tap names apart from hosts and owner/repo shorthands
+var tapNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
+// Tap is a named third-party catalog. Its commands are searched with the configured
+// catalogs and can be installed as <tap>/<command>.
+type Tap struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// tapCacheEntry is the cached catalog of one tap
+type tapCacheEntry struct {
+	URL       string    `json:"url"`
+	FetchedAt time.Time `json:"fetched_at"`
+	Catalog   Catalog   `json:"catalog"`
+}
+
+// ListTaps returns the taps configured for a project, sorted by name
+func ListTaps(projectPath string) ([]Tap, error) {
+	projectRoot, err := findProjectRootFrom(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	settings, err := config.Load(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	taps := make([]Tap, 0, len(settings.Taps))
+	for name, url := range settings.Taps {
+		taps = append(taps, Tap{Name: name, URL: url})
+	}
+	sort.Slice(taps, func(i, j int) bool { return taps[i].Name < taps[j].Name })
+	return taps, nil
+}
+
+// AddTap fetches the catalog of a tap, caches it and stores the tap in the user config.
+// It returns the number of commands the catalog lists.
+func AddTap(ctx context.Context, name, url string) (int, error) {
+	if !tapNamePattern.MatchString(name) {
+		return 0, errors.InvalidInput(fmt.Sprintf("invalid tap name %q: use letters, digits, '-' and '_'", name))
+	}
+	if url == "" {
+		return 0, errors.InvalidInput("tap URL is required")
+	}
+
+	path, values, taps, err := readUserTaps()
+	if err != nil {
+		return 0, err
+	}
+	if existing, ok := taps[name]; ok {
+		return 0, errors.AlreadyExists(fmt.Sprintf("tap %q (%v); remove it first", name, existing))
+	}
+
+	client, err := newHTTPClient(remoteSearchTimeout)
+	if err != nil {
+		return 0, err
+	}
+	catalog, err := fetchCatalog(ctx, client, url)
+	if ctx.Err() != nil {
+		return 0, ctx.Err()
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch catalog of tap %s: %w", name, err)
+	}
+
+	taps[name] = url
+	values["taps"] = taps
+	if err := config.WriteFile(path, values); err != nil {
+		return 0, err
+	}
+
+	newTapCache("").store(Tap{Name: name, URL: url}, catalog)
+	return len(catalog.Commands), nil
+}
+
+// RemoveTap removes a tap from the user config and deletes its cached catalog
+func RemoveTap(name string) error {
+	path, values, taps, err := readUserTaps()
+	if err != nil {
+		return err
+	}
+	if _, ok := taps[name]; !ok {
+		return errors.NotFound(fmt.Sprintf("tap %q", name))
+	}
+
+	delete(taps, name)
+	if len(taps) == 0 {
+		delete(values, "taps")
+	} else {
+		values["taps"] = taps
+	}
+	if err := config.WriteFile(path, values); err != nil {
+		return err
+	}
+
+	if cache := newTapCache(""); cache.dir != "" {
+		_ = os.Remove(cache.path(name))
+	}
+	return nil
+}
+
+// readUserTaps reads the user config file and its taps section
+func readUserTaps() (string, map[string]interface{}, map[string]interface{}, error) {
+	path, err := config.UserConfigPath()
+	if err != nil {
+		return "", nil, nil, err
+	}
+	values, err := config.ReadFile(path)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	taps := make(map[string]interface{})
+	if existing, ok := values["taps"].(map[string]interface{}); ok {
+		taps = existing
+	}
+	return path, values, taps, nil
+}
+
+// tapCache keeps the catalogs of taps on disk. Catalogs younger than tag_cache_ttl are
+// reused; an older copy is still used when the tap cannot be reached.
+type tapCache struct {
+	dir string // empty when cache_dir is not set
+	ttl time.Duration
+	now func() time.Time
+}
+
+func newTapCache(projectRoot string) *tapCache {
+	cache := &tapCache{now: time.Now}
+
+	settings, err := config.Load(projectRoot)
+	if err != nil || settings.CacheDir == "" {
+		return cache
+	}
+	cache.dir = filepath.Join(settings.CacheDir, tapCacheDir)
+	cache.ttl = time.Duration(settings.TagCacheTTL) * time.Second
+	return cache
+}
+
+// path returns the cache file of a tap
+func (c *tapCache) path(name string) string {
+	return filepath.Join(c.dir, name+".json")
+}
+
+// catalog returns the catalog of a tap, from the cache when it is fresh
+func (c *tapCache) catalog(ctx context.Context, client *http.Client, tap Tap) (*Catalog, error) {
+	cached, fetchedAt, ok := c.load(tap)
+	if ok {
+		if age := c.now().Sub(fetchedAt); age >= 0 && age <= c.ttl {
+			return cached, nil
+		}
+	}
+
+	catalog, err := fetchCatalog(ctx, client, tap.URL)
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if err != nil {
+		if ok {
+			output.PrintWarningf("Using the cached catalog of tap %s: %v", tap.Name, err)
+			return cached, nil
+		}
+		return nil, err
+	}
+	c.store(tap, catalog)
+	return catalog, nil
+}
+
+func (c *tapCache) load(tap Tap) (*Catalog, time.Time, bool) {
+	if c.dir == "" {
+		return nil, time.Time{}, false
+	}
+
+	data, err := os.ReadFile(c.path(tap.Name))
+	if err != nil {
+		return nil, time.Time{}, false
+	}
+	var entry tapCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.URL != tap.URL {
+		return nil, time.Time{}, false
+	}
+	return &entry.Catalog, entry.FetchedAt, true
+}
+
+// store writes a fetched catalog to disk. The cache is an optimization, so failures
+// are ignored.
+func (c *tapCache) store(tap Tap, catalog *Catalog) {
+	if c.dir == "" {
+		return
+	}
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return
+	}
+
+	data, err := json.Marshal(tapCacheEntry{URL: tap.URL, FetchedAt: c.now().UTC(), Catalog: *catalog})
+	if err != nil {
+		return
+	}
+	_ = writeFileAtomic(c.path(tap.Name), data, 0644)
+}
+
+// resolveTapCommand turns a <tap>/<command> spec into the repository the tap lists for
+// the command. Specs whose first segment is not a configured tap are returned unchanged.
+func resolveTapCommand(ctx context.Context, projectRoot, spec string) (string, error) {
+	tapName, command, ok := strings.Cut(spec, "/")
+	if !ok || strings.Contains(command, "/") || !tapNamePattern.MatchString(tapName) {
+		return spec, nil
+	}
+
+	settings, err := config.Load(projectRoot)
+	if err != nil {
+		return "", err
+	}
+	url, ok := settings.Taps[tapName]
+	if !ok {
+		return spec, nil
+	}
+
+	client, err := newHTTPClient(remoteSearchTimeout)
+	if err != nil {
+		return "", err
+	}
+	catalog, err := newTapCache(projectRoot).catalog(ctx, client, Tap{Name: tapName, URL: url})
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch catalog of tap %s: %w", tapName, err)
+	}
+
+	for _, entry := range catalog.Commands {
+		name := entry.Name
+		if name == "" {
+			name = extractCommandName(entry.Repository)
+		}
+		if entry.Repository != "" && strings.EqualFold(name, command) {
+			output.PrintVerbosef("Tap %s resolves %s to %s", tapName, command, entry.Repository)
+			return entry.Repository, nil
+		}
+	}
+	return "", errors.NotFound(fmt.Sprintf("command %q in tap %q", command, tapName))
+}
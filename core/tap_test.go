@@ -0,0 +1,79 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gifflet/ccmd/pkg/config"
+)
+
+func TestTaps(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+
+	dir := t.TempDir()
+	t.Setenv(config.ConfigEnv, filepath.Join(dir, "config.yaml"))
+	t.Setenv(config.EnvName("cache_dir"), filepath.Join(dir, "cache"))
+
+	catalogPath := filepath.Join(dir, "catalog.yaml")
+	require.NoError(t, os.WriteFile(catalogPath, []byte(testCatalog), 0o600))
+	ctx := context.Background()
+
+	count, err := AddTap(ctx, "acme", catalogPath)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	_, err = AddTap(ctx, "acme", catalogPath)
+	assert.Error(t, err, "duplicate tap")
+	_, err = AddTap(ctx, "github.com", catalogPath)
+	assert.Error(t, err, "tap names cannot look like hosts")
+	_, err = AddTap(ctx, "missing", filepath.Join(dir, "missing.yaml"))
+	assert.Error(t, err, "the catalog must be reachable")
+
+	cwd, err := os.Getwd()
+	require.NoError(t, err)
+	taps, err := ListTaps(cwd)
+	require.NoError(t, err)
+	assert.Equal(t, []Tap{{Name: "acme", URL: catalogPath}}, taps)
+
+	// The cached catalog is used while it is fresh
+	require.NoError(t, os.Remove(catalogPath))
+
+	repo, err := resolveTapCommand(ctx, cwd, "acme/review")
+	require.NoError(t, err)
+	assert.Equal(t, "github.com/acme/review", repo)
+
+	repo, err = resolveTapCommand(ctx, cwd, "other/review")
+	require.NoError(t, err)
+	assert.Equal(t, "other/review", repo, "specs outside taps are unchanged")
+
+	_, err = resolveTapCommand(ctx, cwd, "acme/unknown")
+	assert.Error(t, err)
+
+	report, err := SearchRemote(ctx, RemoteSearchOptions{Keyword: "deploy", Taps: taps, NoGitHub: true, ProjectPath: cwd})
+	require.NoError(t, err)
+	require.Len(t, report.Results, 1)
+	assert.Equal(t, []string{"tap:acme"}, report.Results[0].Sources)
+
+	require.NoError(t, RemoveTap("acme"))
+	assert.NoFileExists(t, filepath.Join(dir, "cache", tapCacheDir, "acme.json"))
+	assert.Error(t, RemoveTap("acme"))
+
+	taps, err = ListTaps(cwd)
+	require.NoError(t, err)
+	assert.Empty(t, taps)
+}
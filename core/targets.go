@@ -0,0 +1,399 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/gifflet/ccmd/internal/fs"
+	"github.com/gifflet/ccmd/pkg/config"
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+// Target types. Each one writes a standalone file per installed command in the format
+// a tool expects.
+const (
+	// TargetClaude writes <name>.md, the entry file with a ccmd header, read by Claude Code
+	TargetClaude = "claude"
+	// TargetCursor writes <name>.mdc Cursor rules that the agent applies on request
+	TargetCursor = "cursor"
+	// TargetCursorCommands writes <name>.md, the entry file without front matter, as
+	// Cursor custom commands
+	TargetCursorCommands = "cursor-commands"
+	// TargetAgents writes <name>/AGENTS.md following the AGENTS.md convention
+	TargetAgents = "agents"
+	// TargetMarkdown writes <name>.md, the entry file without front matter, to any directory
+	TargetMarkdown = "markdown"
+)
+
+// Target is an output layout: the directory, relative to the project root, where
+// standalone command files are written and the adapter that renders them
+type Target struct {
+	Type string `yaml:"type" json:"type"`
+	Path string `yaml:"path,omitempty" json:"path,omitempty"`
+
+	// fileName is the layout.file_name pattern naming the files of nested commands,
+	// empty for the default
+	fileName string
+	// frontMatter starts the files of the claude target with Claude Code front matter,
+	// as the doc_frontmatter setting asks
+	frontMatter bool
+}
+
+// targetAdapter renders the standalone file of a command for one tool
+type targetAdapter struct {
+	defaultPath string // empty when the target needs an explicit path
+	nested      bool   // each command gets its own directory holding fileName
+	fileName    string // file name, or extension appended to the command name
+	render      func(commandDir string, metadata *ProjectConfig) ([]byte, error)
+}
+
+var targetAdapters = map[string]targetAdapter{
+	TargetClaude:         {defaultPath: filepath.Join(".claude", "commands"), fileName: ".md", render: renderStandaloneDoc},
+	TargetCursor:         {defaultPath: filepath.Join(".cursor", "rules"), fileName: ".mdc", render: renderCursorRule},
+	TargetCursorCommands: {defaultPath: filepath.Join(".cursor", "commands"), fileName: ".md", render: renderPlainDoc},
+	TargetAgents:         {defaultPath: filepath.Join(".agents", "commands"), nested: true, fileName: "AGENTS.md", render: renderAgentsDoc},
+	TargetMarkdown:       {fileName: ".md", render: renderPlainDoc},
+}
+
+// defaultTargets is the layout used when neither ccmd.yaml nor the configuration lists targets
+var defaultTargets = []Target{{Type: TargetClaude}}
+
+// TargetTypes returns the supported target types
+func TargetTypes() []string {
+	types := make([]string, 0, len(targetAdapters))
+	for t := range targetAdapters {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// ParseTarget parses a target written as type or type:path, as in the targets setting
+func ParseTarget(spec string) (Target, error) {
+	targetType, path, _ := strings.Cut(strings.TrimSpace(spec), ":")
+	target := Target{Type: targetType, Path: path}
+	return target, target.Validate()
+}
+
+// Validate checks the target type and that its path stays inside the project
+func (t Target) Validate() error {
+	adapter, ok := targetAdapters[t.Type]
+	if !ok {
+		return errors.InvalidInput(fmt.Sprintf("unknown target type %q (valid: %s)",
+			t.Type, strings.Join(TargetTypes(), ", ")))
+	}
+	if t.Path == "" && adapter.defaultPath == "" {
+		return errors.InvalidInput(fmt.Sprintf("target %q needs a path", t.Type))
+	}
+	if t.Path != "" && !filepath.IsLocal(filepath.FromSlash(t.Path)) {
+		return errors.InvalidInput(fmt.Sprintf("target path %q must be relative to the project root", t.Path))
+	}
+	return nil
+}
+
+// dir returns the directory of the target's files, relative to the project root
+func (t Target) dir() string {
+	if t.Path != "" {
+		return filepath.Clean(filepath.FromSlash(t.Path))
+	}
+	return targetAdapters[t.Type].defaultPath
+}
+
+// relFile returns the path of a command's standalone file relative to the target directory
+func (t Target) relFile(name string) string {
+	adapter := targetAdapters[t.Type]
+	base := standaloneBase(name, t.fileName)
+	if adapter.nested {
+		return filepath.Join(base, adapter.fileName)
+	}
+	return base + adapter.fileName
+}
+
+// file returns the absolute path of a command's standalone file in this target
+func (t Target) file(projectRoot, name string) string {
+	return filepath.Join(projectRoot, t.dir(), t.relFile(name))
+}
+
+// key returns the slash-separated path of a command's standalone file relative to the
+// project root, as recorded in the lock file
+func (t Target) key(name string) string {
+	return filepath.ToSlash(filepath.Join(t.dir(), t.relFile(name)))
+}
+
+// label names a command's standalone file in messages: <name>.md in .claude/commands,
+// the project-relative path elsewhere
+func (t Target) label(name string) string {
+	if t.dir() == targetAdapters[TargetClaude].defaultPath && t.Type == TargetClaude {
+		return t.relFile(name)
+	}
+	return t.key(name)
+}
+
+// render builds the content of a command's standalone file in this target
+func (t Target) render(commandDir string, metadata *ProjectConfig) ([]byte, error) {
+	if t.Type == TargetClaude && t.frontMatter {
+		return renderClaudeCommand(commandDir, metadata)
+	}
+	return targetAdapters[t.Type].render(commandDir, metadata)
+}
+
+// String returns the target in type:path form
+func (t Target) String() string {
+	return t.Type + ":" + filepath.ToSlash(t.dir())
+}
+
+// projectTargets returns the output layouts of a project: the targets listed in
+// ccmd.yaml, else the targets setting, else the Claude Code layout. Targets that
+// resolve to the same directory are written once.
+func projectTargets(projectRoot string) ([]Target, error) {
+	var targets []Target
+	var fileName string
+	var frontMatter bool
+
+	if settings, err := config.Load(projectRoot); err == nil {
+		fileName = settings.Layout.FileName
+		frontMatter = settings.DocFrontmatter
+		for _, spec := range settings.Targets {
+			target, err := ParseTarget(spec)
+			if err != nil {
+				return nil, errors.InvalidInput(fmt.Sprintf("targets setting: %v", err))
+			}
+			targets = append(targets, target)
+		}
+	}
+
+	if ProjectConfigExists(projectRoot) {
+		if cfg, err := LoadProjectConfig(projectRoot); err == nil && len(cfg.Targets) > 0 {
+			targets = cfg.Targets
+			for _, target := range targets {
+				if err := target.Validate(); err != nil {
+					return nil, errors.InvalidInput(fmt.Sprintf("%s targets: %v", ConfigFileName, err))
+				}
+			}
+		}
+	}
+
+	if len(targets) == 0 {
+		targets = defaultTargets
+	}
+
+	seen := make(map[string]bool)
+	unique := make([]Target, 0, len(targets))
+	for _, target := range targets {
+		if !seen[target.dir()] {
+			seen[target.dir()] = true
+			target.fileName = fileName
+			target.frontMatter = frontMatter
+			unique = append(unique, target)
+		}
+	}
+	return unique, nil
+}
+
+// claudeFile returns the path of a command's standalone file in .claude/commands
+func claudeFile(projectRoot, name string) string {
+	return Target{Type: TargetClaude, fileName: layoutSettings(projectRoot).FileName}.file(projectRoot, name)
+}
+
+// writeTargets writes a command's standalone file in every target, leaving files that
+// are already current untouched, and records the checksum of each file in records. Local
+// overrides and files edited by hand are handled as policy says; the labels of the edited
+// files are returned. On failure the files already written are left for the caller.
+func writeTargets(projectRoot, commandDir string, targets []Target, metadata *ProjectConfig,
+	records map[string]*LockStandalone, policy editPolicy) (written, edited []string, err error) {
+	for _, target := range targets {
+		path := target.file(projectRoot, metadata.Name)
+		key := target.key(metadata.Name)
+		existing, readErr := os.ReadFile(path)
+
+		if record := records[key]; record != nil && readErr == nil && policy != discardEdits {
+			if record.Override {
+				continue
+			}
+			if checksum := standaloneChecksum(existing); checksum != record.Checksum {
+				edited = append(edited, target.label(metadata.Name))
+				if policy == keepEdits {
+					records[key] = &LockStandalone{Checksum: checksum, Override: true}
+				}
+				continue
+			}
+		}
+
+		content, err := target.render(commandDir, metadata)
+		if err != nil {
+			return written, edited, err
+		}
+		records[key] = &LockStandalone{Checksum: standaloneChecksum(content)}
+		if readErr == nil && bytes.Equal(existing, content) {
+			continue
+		}
+
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return written, edited, errors.FileError("create target directory", filepath.Dir(path), err)
+		}
+		if err := os.WriteFile(path, content, 0644); err != nil {
+			return written, edited, errors.FileError("write standalone file", path, err)
+		}
+		written = append(written, path)
+	}
+	return written, edited, nil
+}
+
+// targetFiles returns the standalone files of a command in the project's targets. Invalid
+// targets are reported when files are written; here the Claude Code layout is assumed.
+func targetFiles(projectRoot, name string) []string {
+	targets, err := projectTargets(projectRoot)
+	if err != nil {
+		targets = defaultTargets
+	}
+
+	files := make([]string, len(targets))
+	for i, target := range targets {
+		files[i] = target.file(projectRoot, name)
+	}
+	return files
+}
+
+// extraTargetFiles returns the standalone files of a command other than
+// .claude/commands/<name>.md, relative to the project root
+func extraTargetFiles(projectRoot, name string) []string {
+	claude := claudeFile(projectRoot, name)
+
+	var files []string
+	for _, path := range targetFiles(projectRoot, name) {
+		if path == claude {
+			continue
+		}
+		if rel, err := filepath.Rel(projectRoot, path); err == nil {
+			files = append(files, rel)
+		}
+	}
+	return files
+}
+
+// renderPlainDoc returns a command's index.md, with its local overrides, without front
+// matter, for tools that read plain markdown prompts
+func renderPlainDoc(commandDir string, _ *ProjectConfig) ([]byte, error) {
+	content, err := readCommandPrompt(commandDir)
+	if err != nil {
+		return nil, err
+	}
+	_, body, _ := splitFrontMatter(content)
+	return body, nil
+}
+
+// renderClaudeCommand renders the standalone file of the claude target behind the front
+// matter Claude Code reads to describe a slash command: the description, argument hint
+// and tags of the metadata. Keys of the entry file's own front matter, such as
+// allowed-tools, are kept and take precedence.
+func renderClaudeCommand(commandDir string, metadata *ProjectConfig) ([]byte, error) {
+	content, err := readCommandPrompt(commandDir)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := &yaml.Node{Kind: yaml.MappingNode}
+	front, body, ok := splitFrontMatter(content)
+	if ok {
+		var doc yaml.Node
+		if yaml.Unmarshal(front, &doc) == nil && len(doc.Content) == 1 && doc.Content[0].Kind == yaml.MappingNode {
+			fields = doc.Content[0]
+		} else {
+			// Not a mapping Claude Code could read either; leave the document as it is
+			body = fs.NormalizeNewlines(content)
+		}
+	}
+
+	var generated []*yaml.Node
+	add := func(key string, value *yaml.Node) {
+		for i := 0; i < len(fields.Content); i += 2 {
+			if fields.Content[i].Value == key {
+				return
+			}
+		}
+		generated = append(generated, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, value)
+	}
+	if description := strings.Join(strings.Fields(metadata.Description), " "); description != "" {
+		add("description", &yaml.Node{Kind: yaml.ScalarNode, Value: description})
+	}
+	if hint := strings.TrimSpace(metadata.ArgumentHint); hint != "" {
+		add("argument-hint", &yaml.Node{Kind: yaml.ScalarNode, Value: hint})
+	}
+	if len(metadata.Tags) > 0 {
+		tags := &yaml.Node{Kind: yaml.SequenceNode, Style: yaml.FlowStyle}
+		for _, tag := range metadata.Tags {
+			tags.Content = append(tags.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: tag})
+		}
+		add("tags", tags)
+	}
+	fields.Content = append(generated, fields.Content...)
+
+	doc := standaloneDoc(metadata, body)
+	if len(fields.Content) == 0 {
+		return doc, nil
+	}
+	data, err := yaml.Marshal(fields)
+	if err != nil {
+		return nil, errors.FileError("render front matter", commandDir, err)
+	}
+	return append([]byte("---\n"+string(data)+"---\n"), doc...), nil
+}
+
+// renderCursorRule wraps a command's prompt in the front matter of a Cursor rule. The
+// rule has no globs and is not always applied, so the agent picks it by description.
+func renderCursorRule(commandDir string, metadata *ProjectConfig) ([]byte, error) {
+	body, err := renderPlainDoc(commandDir, metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	description := strings.Join(strings.Fields(metadata.Description), " ")
+	if description == "" {
+		description = metadata.Name
+	}
+
+	rule := fmt.Sprintf("---\ndescription: %s\nglobs:\nalwaysApply: false\n---\n%s", description, body)
+	return []byte(rule), nil
+}
+
+// renderAgentsDoc writes a command's prompt as an AGENTS.md with the command name as
+// title and its description as introduction
+func renderAgentsDoc(commandDir string, metadata *ProjectConfig) ([]byte, error) {
+	body, err := renderPlainDoc(commandDir, metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc strings.Builder
+	fmt.Fprintf(&doc, "# %s\n\n", metadata.Name)
+	if metadata.Description != "" {
+		fmt.Fprintf(&doc, "%s\n\n", strings.TrimSpace(metadata.Description))
+	}
+	doc.Write(body)
+	return []byte(doc.String()), nil
+}
+
+// pruneTargetDir removes the empty directory left by moving or deleting a command's file
+// in a target that gives each command its own directory. The directory of a nested
+// command is named by the layout.file_name pattern, which always contains the name.
+func pruneTargetDir(path, name string) {
+	_, short := splitQualifiedName(name)
+	if dir := filepath.Dir(path); filepath.Base(dir) == name || (short != name && strings.Contains(filepath.Base(dir), short)) {
+		removeIfEmpty(dir)
+	}
+}
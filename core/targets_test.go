@@ -0,0 +1,238 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+func TestParseTarget(t *testing.T) {
+	target, err := ParseTarget("markdown:.ai/commands")
+	require.NoError(t, err)
+	assert.Equal(t, Target{Type: TargetMarkdown, Path: ".ai/commands"}, target)
+	assert.Equal(t, "cursor:.cursor/rules", Target{Type: TargetCursor}.String())
+
+	_, err = ParseTarget("markdown")
+	assert.ErrorIs(t, err, errors.ErrInvalidInput, "markdown needs a path")
+	_, err = ParseTarget("vim")
+	assert.ErrorIs(t, err, errors.ErrInvalidInput)
+	_, err = ParseTarget("claude:../elsewhere")
+	assert.ErrorIs(t, err, errors.ErrInvalidInput)
+}
+
+func TestProjectTargets(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	t.Setenv("CCMD_TARGETS", "cursor")
+
+	root, err := os.Getwd()
+	require.NoError(t, err)
+
+	targets, err := projectTargets(root)
+	require.NoError(t, err)
+	assert.Equal(t, []Target{{Type: TargetCursor}}, targets)
+
+	// ccmd.yaml takes precedence; targets sharing a directory are written once
+	writeConfigMap(t, map[string]interface{}{
+		"targets": []map[string]string{
+			{"type": "claude"},
+			{"type": "markdown", "path": ".claude/commands"},
+			{"type": "markdown", "path": ".ai/commands"},
+		},
+	})
+	targets, err = projectTargets(root)
+	require.NoError(t, err)
+	assert.Equal(t, []Target{{Type: TargetClaude}, {Type: TargetMarkdown, Path: ".ai/commands"}}, targets)
+
+	writeConfigMap(t, map[string]interface{}{"targets": []map[string]string{{"type": "vim"}}})
+	_, err = projectTargets(root)
+	assert.ErrorIs(t, err, errors.ErrInvalidInput)
+}
+
+func TestInstallRemoveTargets(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	writeConfigMap(t, map[string]interface{}{
+		"commands": []string{},
+		"targets": []map[string]string{
+			{"type": "claude"},
+			{"type": "cursor-commands"},
+			{"type": "markdown", "path": ".ai/commands"},
+		},
+	})
+
+	data := buildTarGz(t, map[string]string{"index.md": frontMatterIndex})
+	require.NoError(t, os.WriteFile("hello.tgz", data, 0644))
+
+	_, _, err := Install(context.Background(), InstallOptions{Repository: "hello.tgz"})
+	require.NoError(t, err)
+
+	claude, err := os.ReadFile(filepath.Join(".claude", "commands", "hello.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(claude), "**Version:** 1.0.0")
+	for _, path := range []string{filepath.Join(".cursor", "commands", "hello.md"), filepath.Join(".ai", "commands", "hello.md")} {
+		plain, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, "Say hello\n", string(plain))
+	}
+
+	report, err := Verify(VerifyOptions{})
+	require.NoError(t, err)
+	assert.True(t, report.OK())
+
+	require.NoError(t, os.Remove(filepath.Join(".ai", "commands", "hello.md")))
+	report, err = Verify(VerifyOptions{})
+	require.NoError(t, err)
+	require.Len(t, report.Issues, 1)
+	assert.Equal(t, ".ai/commands/hello.md is missing", report.Issues[0].Problem)
+
+	result, err := Regen(RegenOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"hello"}, result.Regenerated)
+
+	require.NoError(t, Remove(RemoveOptions{Name: "hello"}))
+	assert.NoFileExists(t, filepath.Join(".cursor", "commands", "hello.md"))
+	assert.NoFileExists(t, filepath.Join(".ai", "commands", "hello.md"))
+
+	require.NoError(t, Restore(RestoreOptions{Name: "hello"}))
+	assert.FileExists(t, filepath.Join(".cursor", "commands", "hello.md"))
+	assert.FileExists(t, filepath.Join(".ai", "commands", "hello.md"))
+}
+
+func TestToolTargets(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	writeConfigMap(t, map[string]interface{}{
+		"commands": []string{},
+		"targets":  []map[string]string{{"type": "cursor"}, {"type": "agents"}},
+	})
+
+	data := buildTarGz(t, map[string]string{"index.md": frontMatterIndex})
+	require.NoError(t, os.WriteFile("hello.tgz", data, 0644))
+	_, _, err := Install(context.Background(), InstallOptions{Repository: "hello.tgz"})
+	require.NoError(t, err)
+
+	assert.NoFileExists(t, filepath.Join(".claude", "commands", "hello.md"), "only the configured targets are written")
+
+	rule, err := os.ReadFile(filepath.Join(".cursor", "rules", "hello.mdc"))
+	require.NoError(t, err)
+	assert.Equal(t, "---\ndescription: Says hello\nglobs:\nalwaysApply: false\n---\nSay hello\n", string(rule))
+
+	agents, err := os.ReadFile(filepath.Join(".agents", "commands", "hello", "AGENTS.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "# hello\n\nSays hello\n\nSay hello\n", string(agents))
+
+	commands, err := List(ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, commands, 1)
+	assert.False(t, commands[0].BrokenStructure)
+
+	require.NoError(t, Remove(RemoveOptions{Name: "hello"}))
+	assert.NoDirExists(t, filepath.Join(".agents", "commands", "hello"))
+	assert.NoFileExists(t, filepath.Join(".cursor", "rules", "hello.mdc"))
+}
+
+func TestRenderClaudeCommand(t *testing.T) {
+	dir := t.TempDir()
+	metadata := &ProjectConfig{
+		Name:         "review",
+		Version:      "1.0.0",
+		Description:  "Reviews\n  a change",
+		Author:       "acme",
+		Repository:   "https://github.com/acme/review",
+		ArgumentHint: "<file> [--fix]",
+		Tags:         []string{"git", "review"},
+	}
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "index.md"), []byte("Review it\n"), 0644))
+	content, err := renderClaudeCommand(dir, metadata)
+	require.NoError(t, err)
+	assert.Equal(t, "---\ndescription: Reviews a change\nargument-hint: <file> [--fix]\ntags: [git, review]\n---\n"+
+		"# review\n\n**Version:** 1.0.0\n**Author:** acme\n**Repository:** https://github.com/acme/review\n\nReview it\n\n", string(content))
+
+	// Keys of the entry's front matter are kept and win
+	index := "---\nallowed-tools: Bash(git diff:*)\ndescription: Reviews the staged change\n---\nReview it\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "index.md"), []byte(index), 0644))
+	content, err = renderClaudeCommand(dir, metadata)
+	require.NoError(t, err)
+	front, body, ok := splitFrontMatter(content)
+	require.True(t, ok)
+	assert.Equal(t, "argument-hint: <file> [--fix]\ntags: [git, review]\nallowed-tools: Bash(git diff:*)\ndescription: Reviews the staged change\n", string(front))
+	assert.NotContains(t, string(body), "allowed-tools")
+
+	// Nothing to describe leaves the plain standalone file
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "index.md"), []byte("Review it\n"), 0644))
+	plain, err := renderClaudeCommand(dir, &ProjectConfig{Name: "review"})
+	require.NoError(t, err)
+	expected, err := renderStandaloneDoc(dir, &ProjectConfig{Name: "review"})
+	require.NoError(t, err)
+	assert.Equal(t, expected, plain)
+}
+
+func TestInstallDocFrontmatter(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	t.Setenv("CCMD_DOC_FRONTMATTER", "true")
+
+	data := buildTarGz(t, map[string]string{"index.md": frontMatterIndex})
+	require.NoError(t, os.WriteFile("hello.tgz", data, 0644))
+	_, _, err := Install(context.Background(), InstallOptions{Repository: "hello.tgz"})
+	require.NoError(t, err)
+
+	path := filepath.Join(".claude", "commands", "hello.md")
+	doc, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(doc), "---\nname: hello\n")
+	assert.Contains(t, string(doc), "allowed-tools: Bash(git status:*)\n---\n# hello\n")
+	assert.True(t, isGeneratedStandalone(path, "hello"))
+
+	orphans, err := FindOrphans(".")
+	require.NoError(t, err)
+	assert.Empty(t, orphans)
+}
+
+func TestExport(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	writeConfig(t, []string{})
+
+	data := buildTarGz(t, map[string]string{"index.md": frontMatterIndex})
+	require.NoError(t, os.WriteFile("hello.tgz", data, 0644))
+	_, _, err := Install(context.Background(), InstallOptions{Repository: "hello.tgz"})
+	require.NoError(t, err)
+
+	result, err := Export(ExportOptions{Targets: []string{"cursor", "markdown:.ai/commands"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{".cursor/rules/hello.mdc", ".ai/commands/hello.md"}, result.Files)
+	assert.FileExists(t, filepath.Join(".cursor", "rules", "hello.mdc"))
+
+	config, err := LoadProjectConfig(".")
+	require.NoError(t, err)
+	assert.Empty(t, config.Targets, "targets are only saved with Save")
+
+	_, err = Export(ExportOptions{Name: "hello", Targets: []string{"cursor"}, Save: true})
+	require.NoError(t, err)
+	config, err = LoadProjectConfig(".")
+	require.NoError(t, err)
+	assert.Equal(t, []Target{{Type: TargetClaude}, {Type: TargetCursor}}, config.Targets)
+
+	_, err = Export(ExportOptions{Name: "missing", Targets: []string{"cursor"}})
+	assert.ErrorIs(t, err, errors.ErrNotFound)
+	_, err = Export(ExportOptions{})
+	assert.ErrorIs(t, err, errors.ErrInvalidInput)
+}
@@ -0,0 +1,76 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"context"
+	"time"
+
+	"github.com/gifflet/ccmd/pkg/config"
+	"github.com/gifflet/ccmd/pkg/telemetry"
+)
+
+// telemetryTimeout bounds the export at exit, so an unreachable collector never holds
+// up a command for long
+const telemetryTimeout = 3 * time.Second
+
+// EnableTelemetry starts recording traces and metrics when telemetry.enabled is set.
+// The settings come from the user config and the environment only, so a cloned
+// project cannot turn the export on or point it elsewhere. The export honours the
+// proxy and CA settings like every other request.
+func EnableTelemetry(version string) error {
+	settings, err := config.Load("")
+	if err != nil || !settings.Telemetry.Enabled {
+		return err
+	}
+	client, err := newHTTPClient(telemetryTimeout)
+	if err != nil {
+		return err
+	}
+	telemetry.Enable(telemetry.Config{Endpoint: settings.Telemetry.Endpoint, Version: version, Client: client})
+	return nil
+}
+
+// FlushTelemetry exports what was recorded, if telemetry is enabled
+func FlushTelemetry() error {
+	ctx, cancel := context.WithTimeout(context.Background(), telemetryTimeout)
+	defer cancel()
+	return telemetry.Shutdown(ctx)
+}
+
+// repoHostAttr is the host of a repository as a span attribute. Only the host is
+// recorded, never the owner or name of the repository.
+func repoHostAttr(repo string) telemetry.Attr {
+	host := hostFromURL(NormalizeRepositoryURL(repo))
+	if host == "" {
+		host = "unknown"
+	}
+	return telemetry.String("repo.host", host)
+}
+
+// tracedGitClient records a span for every fetch and checkout of a GitClient
+type tracedGitClient struct {
+	GitClient
+	cached bool
+}
+
+func (c tracedGitClient) Fetch(ctx context.Context, repo string) error {
+	ctx, span := telemetry.Start(ctx, "git.fetch", repoHostAttr(repo))
+	err := c.GitClient.Fetch(ctx, repo)
+	span.End(err)
+	return err
+}
+
+func (c tracedGitClient) Checkout(ctx context.Context, repo, ref, dest string, opts CloneOptions) error {
+	ctx, span := telemetry.Start(ctx, "git.checkout", repoHostAttr(repo), telemetry.Bool("git.cached", c.cached))
+	err := c.GitClient.Checkout(ctx, repo, ref, dest, opts)
+	span.End(err)
+	return err
+}
@@ -0,0 +1,35 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gifflet/ccmd/pkg/config"
+	"github.com/gifflet/ccmd/pkg/telemetry"
+)
+
+func TestEnableTelemetryIgnoresProjectConfig(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	t.Setenv("CCMD_CONFIG", filepath.Join(t.TempDir(), "config.yaml"))
+	writeConfig(t, []string{})
+	require.NoError(t, os.WriteFile(config.ProjectConfigPath("."), []byte(
+		"telemetry:\n  enabled: true\n  endpoint: http://collector.attacker.local\n"), 0644))
+
+	require.NoError(t, EnableTelemetry("test"))
+	defer FlushTelemetry()
+	assert.False(t, telemetry.Enabled())
+}
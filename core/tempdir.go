@@ -0,0 +1,243 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gifflet/ccmd/internal/fs"
+	"github.com/gifflet/ccmd/pkg/config"
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+// tmpCacheDir is the directory under the configured cache_dir holding the temporary
+// directories of running operations
+const tmpCacheDir = "tmp"
+
+// tempInUse is how recently a temporary directory must have changed for a sweep to
+// assume another ccmd process is still using it
+const tempInUse = time.Hour
+
+// tempPrefix starts the name of every temporary directory ccmd creates
+const tempPrefix = "ccmd-"
+
+// tempKinds are the kinds of temporary directories ccmd creates. In the system temp
+// directory, where earlier versions created them and where they go without a cache_dir,
+// only directories of these kinds are swept.
+var tempKinds = []string{"install", "update", "diff", "changelog", "upgrade", "base"}
+
+// liveTempDirs holds the temporary directories of this process that were not removed yet
+var liveTempDirs = struct {
+	sync.Mutex
+	dirs map[string]bool
+}{dirs: make(map[string]bool)}
+
+// tempRoot returns the directory temporary directories are created in: cache_dir/tmp,
+// or the system temp directory without a cache_dir
+func tempRoot(projectRoot string) string {
+	settings, err := config.Load(projectRoot)
+	if err != nil || settings.CacheDir == "" {
+		return os.TempDir()
+	}
+	return filepath.Join(settings.CacheDir, tmpCacheDir)
+}
+
+// newTempDir creates the temporary directory of one operation, named ccmd-<kind>-*
+// under cache_dir/tmp, and returns it with the function that removes it. Until then it
+// is registered, so RemoveTempDirs can clean up after a run that exits early.
+func newTempDir(projectRoot, kind string) (string, func(), error) {
+	root := tempRoot(projectRoot)
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return "", nil, errors.FileError("create temp directory", root, err)
+	}
+	dir, err := os.MkdirTemp(root, tempPrefix+kind+"-*")
+	if err != nil {
+		return "", nil, errors.FileError("create temp directory", root, err)
+	}
+
+	liveTempDirs.Lock()
+	liveTempDirs.dirs[dir] = true
+	liveTempDirs.Unlock()
+
+	return dir, func() {
+		_ = fs.RemoveAll(dir)
+		liveTempDirs.Lock()
+		delete(liveTempDirs.dirs, dir)
+		liveTempDirs.Unlock()
+	}, nil
+}
+
+// RemoveTempDirs removes the temporary directories of this process that are still
+// there, such as those of an operation stopped by an error exit
+func RemoveTempDirs() {
+	liveTempDirs.Lock()
+	defer liveTempDirs.Unlock()
+	for dir := range liveTempDirs.dirs {
+		_ = fs.RemoveAll(dir)
+		delete(liveTempDirs.dirs, dir)
+	}
+}
+
+// tempEntry is a temporary directory found by a sweep
+type tempEntry struct {
+	path    string
+	modTime time.Time
+	size    int64
+}
+
+// staleTempDirs lists the temporary directories no running operation uses, oldest first:
+// those under cache_dir/tmp, and those in the system temp directory
+func staleTempDirs(projectRoot string, now time.Time) []tempEntry {
+	roots := map[string]bool{tempRoot(projectRoot): false, os.TempDir(): true}
+
+	var stale []tempEntry
+	for root, system := range roots {
+		entries, err := os.ReadDir(root)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() || !isTempDirName(entry.Name(), system) {
+				continue
+			}
+			path := filepath.Join(root, entry.Name())
+			info, err := entry.Info()
+			if err != nil || now.Sub(info.ModTime()) < tempInUse {
+				continue
+			}
+			liveTempDirs.Lock()
+			live := liveTempDirs.dirs[path]
+			liveTempDirs.Unlock()
+			if live {
+				continue
+			}
+			size, _ := dirSize(path)
+			stale = append(stale, tempEntry{path: path, modTime: info.ModTime(), size: size})
+		}
+	}
+
+	sort.Slice(stale, func(i, j int) bool { return stale[i].modTime.Before(stale[j].modTime) })
+	return stale
+}
+
+// isTempDirName reports whether a directory is a temporary directory of ccmd. In the
+// system temp directory, only the known kinds count.
+func isTempDirName(name string, system bool) bool {
+	if !strings.HasPrefix(name, tempPrefix) {
+		return false
+	}
+	if !system {
+		return true
+	}
+	for _, kind := range tempKinds {
+		if strings.HasPrefix(name, tempPrefix+kind+"-") {
+			return true
+		}
+	}
+	return false
+}
+
+// CacheCleanResult lists what a cache clean removed
+type CacheCleanResult struct {
+	Removed []string `json:"removed"`
+	Freed   int64    `json:"freed"`
+}
+
+func (r *CacheCleanResult) add(path string, size int64) {
+	r.Removed = append(r.Removed, path)
+	r.Freed += size
+}
+
+// SweepTempDirs removes the temporary directories older than tmp.max_age_hours, then
+// the oldest ones until those left fit in tmp.max_size_mb. It runs when ccmd starts,
+// so failures are ignored.
+func SweepTempDirs(projectRoot string) *CacheCleanResult {
+	result := &CacheCleanResult{Removed: []string{}}
+	settings, err := config.Load(projectRoot)
+	if err != nil {
+		return result
+	}
+
+	now := time.Now()
+	maxAge := time.Duration(settings.Tmp.MaxAgeHours) * time.Hour
+	var total int64
+	var kept []tempEntry
+	for _, entry := range staleTempDirs(projectRoot, now) {
+		if maxAge > 0 && now.Sub(entry.modTime) > maxAge {
+			if fs.RemoveAll(entry.path) == nil {
+				result.add(entry.path, entry.size)
+			}
+			continue
+		}
+		kept = append(kept, entry)
+		total += entry.size
+	}
+
+	limit := int64(settings.Tmp.MaxSizeMB) << 20
+	for _, entry := range kept {
+		if limit <= 0 || total <= limit {
+			break
+		}
+		if fs.RemoveAll(entry.path) == nil {
+			result.add(entry.path, entry.size)
+			total -= entry.size
+		}
+	}
+	return result
+}
+
+// CacheCleanOptions contains options for cleaning the cache
+type CacheCleanOptions struct {
+	ProjectPath string
+	Tmp         bool // remove only the temporary directories
+}
+
+// CleanCache removes the cached repositories, tag lists, tap catalogs and base
+// configurations under cache_dir, and the temporary directories no running operation
+// uses, whatever their age. With Tmp only the temporary directories are removed.
+func CleanCache(opts CacheCleanOptions) (*CacheCleanResult, error) {
+	projectRoot, _ := findProjectRootFrom(opts.ProjectPath)
+	result := &CacheCleanResult{Removed: []string{}}
+
+	for _, entry := range staleTempDirs(projectRoot, time.Now()) {
+		if err := fs.RemoveAll(entry.path); err != nil {
+			return result, errors.FileError("remove", entry.path, err)
+		}
+		result.add(entry.path, entry.size)
+	}
+	if opts.Tmp {
+		return result, nil
+	}
+
+	settings, err := config.Load(projectRoot)
+	if err != nil {
+		return result, err
+	}
+	if settings.CacheDir == "" {
+		return result, nil
+	}
+	for _, name := range []string{gitCacheDir, tagCacheDir, tapCacheDir, baseCacheDir} {
+		path := filepath.Join(settings.CacheDir, name)
+		if !dirExists(path) {
+			continue
+		}
+		size, _ := dirSize(path)
+		if err := fs.RemoveAll(path); err != nil {
+			return result, errors.FileError("remove", path, err)
+		}
+		result.add(path, size)
+	}
+	return result, nil
+}
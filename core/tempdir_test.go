@@ -0,0 +1,101 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// isolateTempDirs points cache_dir and the system temp directory at empty directories
+func isolateTempDirs(t *testing.T) string {
+	cacheDir := t.TempDir()
+	t.Setenv("CCMD_CACHE_DIR", cacheDir)
+	t.Setenv("TMPDIR", t.TempDir())
+	return cacheDir
+}
+
+// writeTempDir creates a temporary directory last changed age ago, holding size bytes
+func writeTempDir(t *testing.T, root, name string, age time.Duration, size int) string {
+	dir := filepath.Join(root, name)
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "data"), make([]byte, size), 0644))
+	modTime := time.Now().Add(-age)
+	require.NoError(t, os.Chtimes(dir, modTime, modTime))
+	return dir
+}
+
+func TestNewTempDir(t *testing.T) {
+	cacheDir := isolateTempDirs(t)
+
+	dir, remove, err := newTempDir("", "install")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(cacheDir, tmpCacheDir), filepath.Dir(dir))
+	assert.True(t, strings.HasPrefix(filepath.Base(dir), "ccmd-install-"))
+	assert.DirExists(t, dir)
+	remove()
+	assert.NoDirExists(t, dir)
+
+	// Directories an early exit left behind are removed on the way out
+	dir, _, err = newTempDir("", "diff")
+	require.NoError(t, err)
+	RemoveTempDirs()
+	assert.NoDirExists(t, dir)
+}
+
+func TestSweepTempDirs(t *testing.T) {
+	cacheDir := isolateTempDirs(t)
+	t.Setenv("CCMD_TMP_MAX_SIZE_MB", "1")
+	root := filepath.Join(cacheDir, tmpCacheDir)
+
+	expired := writeTempDir(t, root, "ccmd-install-1", 48*time.Hour, 10)
+	older := writeTempDir(t, root, "ccmd-install-2", 3*time.Hour, 700<<10)
+	newer := writeTempDir(t, root, "ccmd-install-3", 2*time.Hour, 700<<10)
+	inUse := writeTempDir(t, root, "ccmd-install-4", time.Minute, 2<<20)
+	legacy := writeTempDir(t, os.TempDir(), "ccmd-update-5", 48*time.Hour, 10)
+	foreign := writeTempDir(t, os.TempDir(), "ccmd-other-6", 48*time.Hour, 10)
+
+	result := SweepTempDirs("")
+	assert.ElementsMatch(t, []string{expired, legacy, older}, result.Removed)
+	for _, dir := range []string{newer, inUse, foreign} {
+		assert.DirExists(t, dir)
+	}
+
+	// A limit of 0 keeps everything that is not too old
+	t.Setenv("CCMD_TMP_MAX_SIZE_MB", "0")
+	writeTempDir(t, root, "ccmd-install-7", 3*time.Hour, 2<<20)
+	assert.Empty(t, SweepTempDirs("").Removed)
+}
+
+func TestCleanCache(t *testing.T) {
+	cacheDir := isolateTempDirs(t)
+	stale := writeTempDir(t, filepath.Join(cacheDir, tmpCacheDir), "ccmd-install-1", 2*time.Hour, 10)
+	inUse := writeTempDir(t, filepath.Join(cacheDir, tmpCacheDir), "ccmd-install-2", time.Minute, 10)
+	repos := writeTempDir(t, cacheDir, gitCacheDir, 0, 10)
+	tags := writeTempDir(t, cacheDir, tagCacheDir, 0, 10)
+
+	result, err := CleanCache(CacheCleanOptions{ProjectPath: t.TempDir(), Tmp: true})
+	require.NoError(t, err)
+	assert.Equal(t, []string{stale}, result.Removed)
+	assert.DirExists(t, inUse)
+	assert.DirExists(t, repos)
+
+	result, err = CleanCache(CacheCleanOptions{ProjectPath: t.TempDir()})
+	require.NoError(t, err)
+	assert.Equal(t, []string{repos, tags}, result.Removed)
+	assert.Equal(t, int64(20), result.Freed)
+	assert.DirExists(t, inUse)
+}
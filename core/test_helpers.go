@@ -88,7 +88,7 @@ func createTestLockCommand(name, version, source string) *LockCommand {
 		Version:     version,
 		Source:      source,
 		Resolved:    source + "@" + version,
-		Commit:      "abc123",
+		Commit:      "0123456789abcdef0123456789abcdef01234567",
 		InstalledAt: time.Now(),
 		UpdatedAt:   time.Now(),
 	}
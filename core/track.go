@@ -0,0 +1,138 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/gifflet/ccmd/internal/fs"
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+// TrackOptions represents options for tracking a local command
+type TrackOptions struct {
+	ProjectPath string
+	// Dir is the command directory under .claude/commands: a path, absolute or relative
+	// to ProjectPath, or the command name
+	Dir string
+}
+
+// Track records a command directory that lives in the project, such as one committed
+// with the repository, in the lock file with source local. list, status and verify
+// include it; sync and update leave it alone and never contact a remote for it. Tracking
+// a local command again records its current files. It returns the command name.
+func Track(opts TrackOptions) (string, error) {
+	projectRoot, err := findProjectRootFrom(opts.ProjectPath)
+	if err != nil {
+		return "", err
+	}
+
+	commandDir := opts.Dir
+	if !filepath.IsAbs(commandDir) {
+		commandDir = filepath.Join(opts.ProjectPath, commandDir)
+	}
+	if !dirExists(commandDir) && filepath.Base(opts.Dir) == opts.Dir {
+		commandDir = installedCommandDir(projectRoot, opts.Dir)
+	}
+	commandDir, err = filepath.Abs(commandDir)
+	if err != nil {
+		return "", errors.FileError("resolve", opts.Dir, err)
+	}
+	if !dirExists(commandDir) {
+		return "", errors.NotFound(fmt.Sprintf("command directory %s", opts.Dir))
+	}
+
+	commandsDir, err := filepath.Abs(projectCommandsDir(projectRoot))
+	if err != nil {
+		return "", errors.FileError("resolve", projectCommandsDir(projectRoot), err)
+	}
+	if filepath.Dir(commandDir) != commandsDir {
+		return "", errors.InvalidInput(fmt.Sprintf("%s is not a command directory in %s",
+			opts.Dir, projectRelative(projectRoot, commandsDir)))
+	}
+
+	name := filepath.Base(commandDir)
+	if err := fs.ValidateName(name); err != nil {
+		return "", err
+	}
+	return name, trackLocal(projectRoot, name, commandDir)
+}
+
+// trackLocal records a local command, or records the current files of one already
+// tracked. A ccmd.yaml naming index.md as the entry is written when the directory has none.
+func trackLocal(projectRoot, name, commandDir string) error {
+	if !fileExists(filepath.Join(commandDir, "index.md")) {
+		return errors.InvalidInput(fmt.Sprintf("%s has no index.md", projectRelative(projectRoot, commandDir)))
+	}
+
+	lockFile, err := readOrCreateLockFile(lockFilePath(projectRoot))
+	if err != nil {
+		return err
+	}
+	existing := lockFile.Commands[name]
+	if existing != nil && !existing.Local() {
+		return errors.Conflict(fmt.Sprintf("%s is installed from %s; only local commands can be tracked again",
+			name, existing.Source))
+	}
+
+	metadataPath := filepath.Join(commandDir, "ccmd.yaml")
+	if !fileExists(metadataPath) {
+		if err := writeCommandMetadata(metadataPath, &ProjectConfig{Entry: "index.md"}); err != nil {
+			return err
+		}
+	}
+	metadata, err := readCommandMetadata(metadataPath)
+	if err != nil {
+		return err
+	}
+	if metadata.Name == "" {
+		metadata.Name = name
+	}
+
+	var records map[string]*LockStandalone
+	if existing != nil {
+		records = existing.Standalone
+	}
+	records, err = adoptStandaloneRecords(projectRoot, commandDir, metadata, records)
+	if err != nil {
+		return err
+	}
+
+	if existing == nil {
+		return recordAdopted(projectRoot, commandDir, &LockCommand{
+			Name:       name,
+			Version:    metadata.Version,
+			Source:     LocalSource,
+			License:    metadata.License,
+			Standalone: records,
+		})
+	}
+
+	checksum, err := contentChecksum(commandDir)
+	if err != nil {
+		return errors.FileError("checksum", commandDir, err)
+	}
+	existing.Checksum = checksum
+	existing.FileSize, _ = dirSize(commandDir)
+	existing.Version = metadata.Version
+	existing.License = metadata.License
+	existing.Standalone = records
+	if len(records) == 0 {
+		existing.Standalone = nil
+	}
+	existing.UpdatedAt = time.Now()
+	if err := WriteLockFile(lockFilePath(projectRoot), lockFile); err != nil {
+		return err
+	}
+	recordInstallStats(projectRoot, "command", name, name)
+	return nil
+}
@@ -0,0 +1,77 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+func TestTrack(t *testing.T) {
+	repo, _ := writeCommandRepo(t)
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	ctx := context.Background()
+	writeConfig(t, []string{repo + "@v1.0.0"})
+	_, err := Sync(ctx, SyncOptions{ProjectPath: "."})
+	require.NoError(t, err)
+
+	dir := filepath.Join(".claude", "commands", "team-style")
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	_, err = Track(TrackOptions{ProjectPath: ".", Dir: dir})
+	assert.ErrorIs(t, err, errors.ErrInvalidInput, "index.md is required")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "index.md"), []byte("Follow the team style.\n"), 0644))
+
+	_, err = Track(TrackOptions{ProjectPath: ".", Dir: "."})
+	assert.ErrorIs(t, err, errors.ErrInvalidInput, "only directories under .claude/commands")
+	_, err = Track(TrackOptions{ProjectPath: ".", Dir: "tool"})
+	assert.ErrorIs(t, err, errors.ErrConflict, "installed commands cannot be tracked")
+
+	name, err := Track(TrackOptions{ProjectPath: ".", Dir: dir})
+	require.NoError(t, err)
+	assert.Equal(t, "team-style", name)
+	locked := readLockFile(t).Commands["team-style"]
+	require.NotNil(t, locked)
+	assert.Equal(t, LocalSource, locked.Source)
+	assert.FileExists(t, filepath.Join(dir, "ccmd.yaml"))
+	assert.FileExists(t, filepath.Join(".claude", "commands", "team-style.md"))
+
+	installed, err := List(ListOptions{ProjectPath: "."})
+	require.NoError(t, err)
+	for _, cmd := range installed {
+		assert.False(t, cmd.BrokenStructure, cmd.Name)
+		assert.Equal(t, cmd.Name == "team-style", cmd.LocalOnly, cmd.Name)
+	}
+	report, err := Verify(VerifyOptions{ProjectPath: "."})
+	require.NoError(t, err)
+	assert.Empty(t, report.Issues)
+
+	// Sync keeps local commands without contacting a remote
+	analysis, err := AnalyzeSync(".", "")
+	require.NoError(t, err)
+	assert.True(t, analysis.InSync)
+	result, err := Sync(ctx, SyncOptions{ProjectPath: "."})
+	require.NoError(t, err)
+	assert.Empty(t, result.Removed)
+
+	// Tracking again records the edited files by name
+	checksum := locked.Checksum
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "index.md"), []byte("Follow the new team style.\n"), 0644))
+	_, err = Track(TrackOptions{ProjectPath: ".", Dir: "team-style"})
+	require.NoError(t, err)
+	assert.NotEqual(t, checksum, readLockFile(t).Commands["team-style"].Checksum)
+}
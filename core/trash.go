@@ -0,0 +1,485 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	ccmdfs "github.com/gifflet/ccmd/internal/fs"
+	"github.com/gifflet/ccmd/pkg/errors"
+	"github.com/gifflet/ccmd/pkg/output"
+)
+
+const (
+	// TrashDirName is the directory under .claude where removed commands are kept
+	TrashDirName = ".trash"
+	// TrashRetention is how long removed commands can be restored
+	TrashRetention = 7 * 24 * time.Hour
+
+	trashTimeFormat    = "20060102T150405.000000000"
+	trashManifestExt   = ".trash.yaml"
+	trashTypeCommand   = "command"
+	trashTypePlugin    = "plugin"
+	trashFilesDirName  = "files"
+	trashTargetsDir    = "targets" // standalone files of other targets, by project-relative path
+	trashStandaloneExt = ".md"
+)
+
+// TrashEntry describes a command or plugin moved to the trash
+type TrashEntry struct {
+	Name      string       `yaml:"name"`
+	Type      string       `yaml:"type"`
+	RemovedAt time.Time    `yaml:"removed_at"`
+	Spec      string       `yaml:"spec,omitempty"`
+	Command   *LockCommand `yaml:"command,omitempty"`
+	Plugin    *LockPlugin  `yaml:"plugin,omitempty"`
+
+	dir string
+}
+
+// RestoreOptions represents options for restoring a removed command
+type RestoreOptions struct {
+	Name string
+}
+
+// trashRoot returns the trash directory for a project
+func trashRoot(projectRoot string) string {
+	return filepath.Join(projectRoot, ".claude", TrashDirName)
+}
+
+// newTrashDir creates a fresh timestamped trash directory
+func newTrashDir(projectRoot string, now time.Time) (string, error) {
+	dir := filepath.Join(trashRoot(projectRoot), now.UTC().Format(trashTimeFormat))
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return "", errors.FileError("create trash directory", dir, err)
+	}
+	return dir, nil
+}
+
+// trashKey returns the name a command's files and manifest have in the trash: the name,
+// with the owner of a nested command joined by "--"
+func trashKey(name string) string {
+	return strings.ReplaceAll(name, "/", "--")
+}
+
+// trashCommand moves a command's files into the trash and records how to restore it
+func trashCommand(projectRoot, name string, lockCmd *LockCommand, spec string) (*TrashEntry, error) {
+	purgeExpiredTrash(projectRoot, time.Now())
+
+	now := time.Now()
+	dir, err := newTrashDir(projectRoot, now)
+	if err != nil {
+		return nil, err
+	}
+
+	commandsDir := projectCommandsDir(projectRoot)
+	filesDir := filepath.Join(dir, trashFilesDirName)
+	if err := os.MkdirAll(filesDir, 0o750); err != nil {
+		return nil, errors.FileError("create trash directory", filesDir, err)
+	}
+
+	// Nested commands are kept flat in the trash, under their owner--name key
+	key := trashKey(name)
+	commandDir := filepath.Join(commandsDir, name)
+	if dirExists(commandDir) {
+		if err := os.Rename(commandDir, filepath.Join(filesDir, key)); err != nil {
+			return nil, errors.FileError("move command to trash", commandDir, err)
+		}
+		pruneOwnerDir(projectRoot, name)
+	}
+
+	mdFile := claudeFile(projectRoot, name)
+	if fileExists(mdFile) {
+		if err := os.Rename(mdFile, filepath.Join(filesDir, key+trashStandaloneExt)); err != nil {
+			output.PrintWarningf("Failed to move .md file to trash: %v", err)
+		}
+	}
+
+	for _, rel := range extraTargetFiles(projectRoot, name) {
+		src := filepath.Join(projectRoot, rel)
+		if !fileExists(src) {
+			continue
+		}
+		dst := filepath.Join(filesDir, trashTargetsDir, rel)
+		if err := os.MkdirAll(filepath.Dir(dst), 0o750); err == nil {
+			err = os.Rename(src, dst)
+		}
+		if err != nil {
+			output.PrintWarningf("Failed to move %s to trash: %v", filepath.ToSlash(rel), err)
+		}
+		pruneTargetDir(src, name)
+	}
+
+	entry := &TrashEntry{
+		Name:      name,
+		Type:      trashTypeCommand,
+		RemovedAt: now,
+		Spec:      spec,
+		Command:   lockCmd,
+		dir:       dir,
+	}
+	return entry, writeTrashEntry(dir, entry)
+}
+
+// trashPlugin moves a plugin directory into the trash and records how to restore it
+func trashPlugin(projectRoot, name string, lockPlugin *LockPlugin, spec string) (*TrashEntry, error) {
+	purgeExpiredTrash(projectRoot, time.Now())
+
+	now := time.Now()
+	dir, err := newTrashDir(projectRoot, now)
+	if err != nil {
+		return nil, err
+	}
+
+	filesDir := filepath.Join(dir, trashFilesDirName)
+	if err := os.MkdirAll(filesDir, 0o750); err != nil {
+		return nil, errors.FileError("create trash directory", filesDir, err)
+	}
+
+	pluginDir := filepath.Join(projectRoot, ".claude", "plugins", name)
+	if dirExists(pluginDir) {
+		if err := os.Rename(pluginDir, filepath.Join(filesDir, name)); err != nil {
+			return nil, errors.FileError("move plugin to trash", pluginDir, err)
+		}
+	}
+
+	entry := &TrashEntry{
+		Name:      name,
+		Type:      trashTypePlugin,
+		RemovedAt: now,
+		Spec:      spec,
+		Plugin:    lockPlugin,
+		dir:       dir,
+	}
+	return entry, writeTrashEntry(dir, entry)
+}
+
+func writeTrashEntry(dir string, entry *TrashEntry) error {
+	path := filepath.Join(dir, trashKey(entry.Name)+trashManifestExt)
+
+	data, err := yaml.Marshal(entry)
+	if err != nil {
+		return errors.FileError("marshal trash entry", path, err)
+	}
+
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return errors.FileError("write trash entry", path, err)
+	}
+
+	return nil
+}
+
+// ListTrash returns restorable trash entries, newest first
+func ListTrash(projectPath string) ([]TrashEntry, error) {
+	projectRoot, err := findProjectRootFrom(projectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	root := trashRoot(projectRoot)
+	dirs, err := os.ReadDir(root)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []TrashEntry{}, nil
+		}
+		return nil, errors.FileError("read trash directory", root, err)
+	}
+
+	cutoff := time.Now().Add(-TrashRetention)
+	entries := []TrashEntry{}
+
+	for _, d := range dirs {
+		if !d.IsDir() {
+			continue
+		}
+		dir := filepath.Join(root, d.Name())
+		manifests, err := filepath.Glob(filepath.Join(dir, "*"+trashManifestExt))
+		if err != nil {
+			continue
+		}
+		for _, manifest := range manifests {
+			data, err := os.ReadFile(manifest)
+			if err != nil {
+				continue
+			}
+			var entry TrashEntry
+			if err := yaml.Unmarshal(data, &entry); err != nil {
+				continue
+			}
+			if entry.RemovedAt.Before(cutoff) {
+				continue
+			}
+			entry.dir = dir
+			entries = append(entries, entry)
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].RemovedAt.After(entries[j].RemovedAt)
+	})
+
+	return entries, nil
+}
+
+// Restore moves the most recently removed copy of a command back into place
+func Restore(opts RestoreOptions) error {
+	if opts.Name == "" {
+		return errors.InvalidInput("command name is required")
+	}
+
+	projectRoot, err := findProjectRoot()
+	if err != nil {
+		return err
+	}
+
+	entries, err := ListTrash(projectRoot)
+	if err != nil {
+		return err
+	}
+
+	var entry *TrashEntry
+	for i := range entries {
+		if entries[i].Name == opts.Name {
+			entry = &entries[i]
+			break
+		}
+	}
+
+	if entry == nil {
+		return errors.NotFound(fmt.Sprintf("no restorable removal of %q in the last %s",
+			opts.Name, formatRetention(TrashRetention)))
+	}
+
+	if entry.Type == trashTypePlugin {
+		err = restorePlugin(projectRoot, entry)
+	} else {
+		err = restoreCommand(projectRoot, entry)
+	}
+	if err != nil {
+		return err
+	}
+
+	discardTrashEntry(entry)
+	return nil
+}
+
+// discardTrashEntry deletes the manifest of a restored entry, and its trash directory
+// once nothing else is left in it
+func discardTrashEntry(entry *TrashEntry) {
+	if err := os.Remove(filepath.Join(entry.dir, trashKey(entry.Name)+trashManifestExt)); err != nil {
+		output.PrintWarningf("Failed to clean up trash entry: %v", err)
+	}
+	removeIfEmpty(filepath.Join(entry.dir, trashFilesDirName))
+	removeIfEmpty(entry.dir)
+}
+
+func restoreCommand(projectRoot string, entry *TrashEntry) error {
+	commandDir := installedCommandDir(projectRoot, entry.Name)
+	mdFile := claudeFile(projectRoot, entry.Name)
+
+	if dirExists(commandDir) || fileExists(mdFile) {
+		return errors.AlreadyExists(fmt.Sprintf("command %q is installed, remove it before restoring", entry.Name))
+	}
+
+	if err := os.MkdirAll(filepath.Dir(commandDir), 0o750); err != nil {
+		return errors.FileError("create commands directory", filepath.Dir(commandDir), err)
+	}
+
+	key := trashKey(entry.Name)
+	filesDir := filepath.Join(entry.dir, trashFilesDirName)
+	if src := filepath.Join(filesDir, key); dirExists(src) {
+		if err := os.Rename(src, commandDir); err != nil {
+			return errors.FileError("restore command directory", commandDir, err)
+		}
+	}
+	if src := filepath.Join(filesDir, key+trashStandaloneExt); fileExists(src) {
+		if err := os.Rename(src, mdFile); err != nil {
+			return errors.FileError("restore .md file", mdFile, err)
+		}
+	}
+	restoreTargetFiles(projectRoot, filepath.Join(filesDir, trashTargetsDir))
+
+	if entry.Command != nil {
+		lockPath := lockFilePath(projectRoot)
+		lockFile, err := readOrCreateLockFile(lockPath)
+		if err != nil {
+			return err
+		}
+		lockFile.Commands[entry.Name] = entry.Command
+		if err := WriteLockFile(lockPath, lockFile); err != nil {
+			return err
+		}
+	}
+
+	if entry.Spec != "" {
+		var err error
+		if repo, version, instance := ParseInstanceSpec(entry.Spec); instance != "" {
+			err = addToProfile(projectRoot, "", entry.Spec)
+		} else {
+			err = addToConfig(projectRoot, entry.Name, repo, version)
+		}
+		if err != nil {
+			output.PrintWarningf("Failed to update ccmd.yaml: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// restoreTargetFiles moves trashed standalone files of other targets back to their
+// project-relative paths. Files that were written again since the removal are kept.
+func restoreTargetFiles(projectRoot, targetsDir string) {
+	if !dirExists(targetsDir) {
+		return
+	}
+
+	_ = filepath.WalkDir(targetsDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(targetsDir, path)
+		if err != nil {
+			return nil
+		}
+		dst := filepath.Join(projectRoot, rel)
+		if fileExists(dst) {
+			return nil
+		}
+		if err := os.MkdirAll(filepath.Dir(dst), 0o750); err == nil {
+			err = os.Rename(path, dst)
+		}
+		if err != nil {
+			output.PrintWarningf("Failed to restore %s: %v", filepath.ToSlash(rel), err)
+		}
+		return nil
+	})
+	if err := ccmdfs.RemoveAll(targetsDir); err != nil {
+		output.PrintWarningf("Failed to clean up trash entry: %v", err)
+	}
+}
+
+func restorePlugin(projectRoot string, entry *TrashEntry) error {
+	pluginsDir := filepath.Join(projectRoot, ".claude", "plugins")
+	pluginDir := filepath.Join(pluginsDir, entry.Name)
+
+	if dirExists(pluginDir) {
+		return errors.AlreadyExists(fmt.Sprintf("plugin %q is installed, remove it before restoring", entry.Name))
+	}
+
+	if err := os.MkdirAll(pluginsDir, 0o750); err != nil {
+		return errors.FileError("create plugins directory", pluginsDir, err)
+	}
+
+	if src := filepath.Join(entry.dir, trashFilesDirName, entry.Name); dirExists(src) {
+		if err := os.Rename(src, pluginDir); err != nil {
+			return errors.FileError("restore plugin directory", pluginDir, err)
+		}
+	}
+
+	if err := enablePlugin(projectRoot, entry.Name); err != nil {
+		output.PrintWarningf("Failed to register plugin in settings.json: %v", err)
+	}
+
+	if entry.Plugin != nil {
+		lockPath := lockFilePath(projectRoot)
+		lockFile, err := readOrCreateLockFile(lockPath)
+		if err != nil {
+			return err
+		}
+		lockFile.Plugins[entry.Name] = entry.Plugin
+		if err := WriteLockFile(lockPath, lockFile); err != nil {
+			return err
+		}
+	}
+
+	if entry.Spec != "" {
+		repo, version := ParseCommandSpec(entry.Spec)
+		if err := addPluginToConfig(projectRoot, entry.Name, repo, version); err != nil {
+			output.PrintWarningf("Failed to update ccmd.yaml: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// purgeExpiredTrash deletes trash directories older than the retention window
+func purgeExpiredTrash(projectRoot string, now time.Time) {
+	root := trashRoot(projectRoot)
+	dirs, err := os.ReadDir(root)
+	if err != nil {
+		return
+	}
+
+	cutoff := now.Add(-TrashRetention)
+	for _, d := range dirs {
+		if !d.IsDir() {
+			continue
+		}
+		removedAt, err := time.Parse(trashTimeFormat, d.Name())
+		if err != nil || !removedAt.Before(cutoff) {
+			continue
+		}
+		_ = ccmdfs.RemoveAll(filepath.Join(root, d.Name()))
+	}
+}
+
+// readOrCreateLockFile reads the lock file or returns an empty one when it doesn't exist
+func readOrCreateLockFile(lockPath string) (*LockFile, error) {
+	if fileExists(lockPath) {
+		return ReadLockFile(lockPath)
+	}
+
+	return &LockFile{
+		Version:         "1.0",
+		LockfileVersion: 1,
+		Commands:        make(map[string]*LockCommand),
+		Plugins:         make(map[string]*LockPlugin),
+	}, nil
+}
+
+// findConfigSpec returns the ccmd.yaml entry that references the given repository or name
+func findConfigSpec(specs []string, name, repository string) string {
+	currentRepo := ExtractRepoPath(repository)
+	for _, spec := range specs {
+		repo, _, instance := ParseInstanceSpec(spec)
+		if instance != "" {
+			// Side-by-side installs only match their own instance name
+			if instance == name {
+				return spec
+			}
+		} else if ExtractRepoPath(repo) == currentRepo || extractCommandName(repo) == name {
+			return spec
+		}
+	}
+	return ""
+}
+
+func removeIfEmpty(dir string) {
+	entries, err := os.ReadDir(dir)
+	if err == nil && len(entries) == 0 {
+		_ = os.Remove(dir)
+	}
+}
+
+func formatRetention(d time.Duration) string {
+	days := int(d.Hours() / 24)
+	if days == 1 {
+		return "1 day"
+	}
+	return fmt.Sprintf("%d days", days)
+}
@@ -0,0 +1,103 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRestore(t *testing.T) {
+	t.Run("restores removed command with lock and config entries", func(t *testing.T) {
+		cleanup := setupTestDir(t)
+		defer cleanup()
+
+		lockFile := createBasicLockFile()
+		lockFile.Commands["test-cmd"] = createTestLockCommand("test-cmd", "1.0.0", "https://github.com/user/test-cmd.git")
+		writeLockFile(t, lockFile)
+		writeConfig(t, []string{"user/test-cmd@v1.0.0", "user/keep-cmd"})
+		createCommandStructure(t, "test-cmd")
+
+		err := Remove(RemoveOptions{Name: "test-cmd", Force: true, UpdateFiles: true})
+		require.NoError(t, err)
+		assert.False(t, dirExists(filepath.Join(".claude", "commands", "test-cmd")))
+
+		entries, err := ListTrash(".")
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, "test-cmd", entries[0].Name)
+		assert.Equal(t, "user/test-cmd@v1.0.0", entries[0].Spec)
+
+		err = Restore(RestoreOptions{Name: "test-cmd"})
+		require.NoError(t, err)
+
+		assert.True(t, dirExists(filepath.Join(".claude", "commands", "test-cmd")))
+		assert.True(t, fileExists(filepath.Join(".claude", "commands", "test-cmd.md")))
+
+		updatedLock := readLockFile(t)
+		require.NotNil(t, updatedLock.Commands["test-cmd"])
+		assert.Equal(t, "1.0.0", updatedLock.Commands["test-cmd"].Version)
+
+		config, err := LoadProjectConfig(".")
+		require.NoError(t, err)
+		assert.Contains(t, config.Commands, "user/test-cmd@v1.0.0")
+
+		entries, err = ListTrash(".")
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+
+	t.Run("refuses to overwrite an installed command", func(t *testing.T) {
+		cleanup := setupTestDir(t)
+		defer cleanup()
+
+		lockFile := createBasicLockFile()
+		lockFile.Commands["test-cmd"] = createTestLockCommand("test-cmd", "1.0.0", "https://github.com/user/test-cmd.git")
+		writeLockFile(t, lockFile)
+		createCommandStructure(t, "test-cmd")
+
+		require.NoError(t, Remove(RemoveOptions{Name: "test-cmd", Force: true}))
+		createCommandStructure(t, "test-cmd")
+
+		err := Restore(RestoreOptions{Name: "test-cmd"})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "already exists")
+	})
+
+	t.Run("returns not found when nothing was removed", func(t *testing.T) {
+		cleanup := setupTestDir(t)
+		defer cleanup()
+
+		err := Restore(RestoreOptions{Name: "missing"})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "not found")
+	})
+}
+
+func TestPurgeExpiredTrash(t *testing.T) {
+	tempDir := t.TempDir()
+	now := time.Now()
+
+	oldDir, err := newTrashDir(tempDir, now.Add(-TrashRetention-time.Hour))
+	require.NoError(t, err)
+	freshDir, err := newTrashDir(tempDir, now)
+	require.NoError(t, err)
+
+	purgeExpiredTrash(tempDir, now)
+
+	_, err = os.Stat(oldDir)
+	assert.True(t, os.IsNotExist(err))
+	assert.True(t, dirExists(freshDir))
+}
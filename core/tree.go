@@ -0,0 +1,217 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"path/filepath"
+	"sort"
+)
+
+// TreeNode is an entry of the command tree with the dependencies its own ccmd.yaml lists
+type TreeNode struct {
+	Spec         string      `json:"spec"`           // entry as written in ccmd.yaml or the dependent's ccmd.yaml
+	Name         string      `json:"name,omitempty"` // installed name, empty when not installed
+	Type         string      `json:"type"`           // "command" or "plugin"
+	Version      string      `json:"version,omitempty"`
+	Missing      bool        `json:"missing,omitempty"` // listed but not installed
+	Broken       bool        `json:"broken,omitempty"`
+	Problem      string      `json:"problem,omitempty"`
+	Latest       string      `json:"latest,omitempty"` // newer version found in the tag cache
+	Cycle        bool        `json:"cycle,omitempty"`  // already shown above it in the same branch
+	Dependencies []*TreeNode `json:"dependencies,omitempty"`
+}
+
+// Outdated reports whether the tag cache knows a newer version of the node
+func (n *TreeNode) Outdated() bool {
+	return n.Latest != ""
+}
+
+// TreeGroup is the shared entries of ccmd.yaml, or the entries of one profile
+type TreeGroup struct {
+	Profile string      `json:"profile,omitempty"` // empty for the shared entries
+	Nodes   []*TreeNode `json:"nodes"`
+}
+
+// CommandTree groups the entries of ccmd.yaml by profile and nests dependencies under
+// their dependents. Installed commands reached from no entry, such as those installed
+// with --no-save, are listed as Unlisted.
+type CommandTree struct {
+	Groups   []TreeGroup `json:"groups"`
+	Unlisted []*TreeNode `json:"unlisted"`
+}
+
+// ListTree builds the command tree of a project from the same state as List: the lock
+// file, the index and ccmd.yaml. Dependencies are read from the ccmd.yaml of each
+// installed command. Like Status, it never touches the network, so outdated nodes are
+// as fresh as the tag cache.
+func ListTree(opts ListOptions) (*CommandTree, error) {
+	details, err := List(ListOptions{ProjectPath: opts.ProjectPath, NoIndex: opts.NoIndex})
+	if err != nil {
+		return nil, err
+	}
+	projectRoot, err := findProjectRootFrom(opts.ProjectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &treeBuilder{
+		projectRoot: projectRoot,
+		details:     make(map[string]CommandDetail, len(details)),
+		latest:      make(map[string]string),
+		reached:     make(map[string]bool),
+		lockFile:    &LockFile{},
+	}
+	for _, detail := range details {
+		b.details[detail.Type+"/"+detail.Name] = detail
+	}
+	if lockPath := lockFilePath(projectRoot); fileExists(lockPath) {
+		if b.lockFile, err = ReadLockFile(lockPath); err != nil {
+			return nil, err
+		}
+	}
+
+	tree := &CommandTree{Groups: []TreeGroup{}, Unlisted: []*TreeNode{}}
+	if ProjectConfigExists(projectRoot) {
+		cfg, err := LoadProjectConfig(projectRoot)
+		if err != nil {
+			return nil, err
+		}
+		updates, err := cachedUpdates(projectRoot, b.lockFile)
+		if err != nil {
+			return nil, err
+		}
+		for _, update := range updates {
+			b.latest[update.Name] = update.Latest
+		}
+
+		shared := TreeGroup{Nodes: b.nodes(cfg.activeSpecs(cfg.Commands), cfg.activeSpecs(cfg.Plugins), nil)}
+		if len(shared.Nodes) > 0 {
+			tree.Groups = append(tree.Groups, shared)
+		}
+		profiles := make([]string, 0, len(cfg.Profiles))
+		for name := range cfg.Profiles {
+			profiles = append(profiles, name)
+		}
+		sort.Strings(profiles)
+		for _, name := range profiles {
+			tree.Groups = append(tree.Groups, TreeGroup{Profile: name, Nodes: b.nodes(cfg.activeSpecs(cfg.Profiles[name]), nil, nil)})
+		}
+	}
+
+	// Unlisted commands another unlisted command depends on are shown under it only
+	var unlisted []*TreeNode
+	for _, detail := range details {
+		key := detail.Type + "/" + detail.Name
+		if b.reached[key] {
+			continue
+		}
+		node := b.installedNode(configSource(b.projectRoot, detail.Repository), detail)
+		node.Dependencies = b.dependencies(node, map[string]bool{key: true})
+		unlisted = append(unlisted, node)
+	}
+	for _, node := range unlisted {
+		if !b.reached[node.Type+"/"+node.Name] {
+			tree.Unlisted = append(tree.Unlisted, node)
+		}
+	}
+	return tree, nil
+}
+
+// treeBuilder resolves entries to installed commands while building a CommandTree
+type treeBuilder struct {
+	projectRoot string
+	lockFile    *LockFile
+	details     map[string]CommandDetail // by type/name
+	latest      map[string]string        // newer versions by command name
+	reached     map[string]bool          // type/name of the installed commands in the tree
+}
+
+// nodes returns the nodes of a list of command and plugin entries. path holds the
+// type/name of the nodes above them, to stop at cycles.
+func (b *treeBuilder) nodes(commands, plugins []string, path map[string]bool) []*TreeNode {
+	nodes := make([]*TreeNode, 0, len(commands)+len(plugins))
+	for _, spec := range commands {
+		nodes = append(nodes, b.node(spec, "command", path))
+	}
+	for _, spec := range plugins {
+		nodes = append(nodes, b.node(spec, "plugin", path))
+	}
+	return nodes
+}
+
+// node resolves one entry and, unless it closes a cycle, its dependencies
+func (b *treeBuilder) node(spec, kind string, path map[string]bool) *TreeNode {
+	repo, _, instance := ParseInstanceSpec(spec)
+	name := ""
+	if kind == "plugin" {
+		for pluginName, plugin := range b.lockFile.Plugins {
+			if ExtractRepoPath(plugin.Source) == ExtractRepoPath(repo) {
+				name = pluginName
+				break
+			}
+		}
+	} else {
+		name, _ = lockedCommand(b.lockFile, repo, instance)
+	}
+
+	detail, ok := b.details[kind+"/"+name]
+	if name == "" || !ok {
+		return &TreeNode{Spec: spec, Type: kind, Missing: true}
+	}
+
+	key := kind + "/" + name
+	node := b.installedNode(spec, detail)
+	if path[key] {
+		node.Cycle = true
+		return node
+	}
+	b.reached[key] = true
+
+	below := make(map[string]bool, len(path)+1)
+	for k := range path {
+		below[k] = true
+	}
+	below[key] = true
+	node.Dependencies = b.dependencies(node, below)
+	return node
+}
+
+// installedNode returns the node of an installed command, without its dependencies
+func (b *treeBuilder) installedNode(spec string, detail CommandDetail) *TreeNode {
+	node := &TreeNode{
+		Spec:    spec,
+		Name:    detail.Name,
+		Type:    detail.Type,
+		Version: detail.Version,
+		Broken:  detail.BrokenStructure,
+		Problem: detail.StructureError,
+	}
+	if detail.Branch != "" {
+		node.Version = BranchLabel(detail.Branch)
+	}
+	if detail.Type == "command" {
+		node.Latest = b.latest[detail.Name]
+	}
+	return node
+}
+
+// dependencies returns the nodes of the entries listed in the ccmd.yaml of an
+// installed command
+func (b *treeBuilder) dependencies(node *TreeNode, path map[string]bool) []*TreeNode {
+	dir := installedCommandDir(b.projectRoot, node.Name)
+	if node.Type == "plugin" {
+		dir = filepath.Join(b.projectRoot, ".claude", "plugins", node.Name)
+	}
+	metadata, err := readCommandMetadata(filepath.Join(dir, ConfigFileName))
+	if err != nil || len(metadata.Commands)+len(metadata.Plugins) == 0 {
+		return nil
+	}
+	return b.nodes(metadata.Commands, metadata.Plugins, path)
+}
@@ -0,0 +1,90 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListTree(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	setupWhyProject(t)
+
+	writeConfigMap(t, map[string]interface{}{
+		"commands": []string{"acme/deploy@^1.2.0"},
+		"profiles": map[string][]string{"review": {"acme/lint"}},
+	})
+
+	// shell depends on deploy in turn, and its standalone file is gone
+	metadata := &ProjectConfig{
+		Name:        "shell",
+		Version:     "2.0.0",
+		Description: "Opens a shell",
+		Author:      "acme",
+		Repository:  "https://github.com/acme/shell.git",
+		Entry:       "index.md",
+		Commands:    []string{"acme/deploy"},
+	}
+	require.NoError(t, writeCommandMetadata(filepath.Join(".claude", "commands", "shell", "ccmd.yaml"), metadata))
+	require.NoError(t, os.Remove(filepath.Join(".claude", "commands", "shell.md")))
+
+	tree, err := ListTree(ListOptions{NoIndex: true})
+	require.NoError(t, err)
+
+	require.Len(t, tree.Groups, 2)
+	shared := tree.Groups[0]
+	assert.Empty(t, shared.Profile)
+	require.Len(t, shared.Nodes, 1)
+	deploy := shared.Nodes[0]
+	assert.Equal(t, "deploy", deploy.Name)
+	assert.Equal(t, "acme/deploy@^1.2.0", deploy.Spec)
+
+	require.Len(t, deploy.Dependencies, 1)
+	shell := deploy.Dependencies[0]
+	assert.Equal(t, "shell", shell.Name)
+	assert.Equal(t, "acme/shell@~2.0.0", shell.Spec)
+	assert.True(t, shell.Broken)
+
+	require.Len(t, shell.Dependencies, 1)
+	assert.True(t, shell.Dependencies[0].Cycle)
+	assert.Empty(t, shell.Dependencies[0].Dependencies)
+
+	review := tree.Groups[1]
+	assert.Equal(t, "review", review.Profile)
+	require.Len(t, review.Nodes, 1)
+	assert.True(t, review.Nodes[0].Missing)
+	assert.Equal(t, "acme/lint", review.Nodes[0].Spec)
+
+	require.Len(t, tree.Unlisted, 1)
+	assert.Equal(t, "stale", tree.Unlisted[0].Name)
+}
+
+func TestListTreeWithoutConfig(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+
+	lockFile := createBasicLockFile()
+	lockFile.Commands["tool"] = createTestLockCommand("tool", "1.0.0", "https://github.com/acme/tool.git")
+	writeLockFile(t, lockFile)
+	createCommandStructure(t, "tool")
+
+	tree, err := ListTree(ListOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, tree.Groups)
+	require.Len(t, tree.Unlisted, 1)
+	assert.Equal(t, "acme/tool", tree.Unlisted[0].Spec)
+	assert.False(t, tree.Unlisted[0].Outdated())
+}
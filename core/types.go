@@ -11,11 +11,11 @@ package core
 
 import (
 	"encoding/json"
+	"fmt"
+	"sort"
 	"strings"
 	"time"
 
-	"gopkg.in/yaml.v3"
-
 	"github.com/gifflet/ccmd/pkg/errors"
 )
 
@@ -25,17 +25,73 @@ type LockFile struct {
 	LockfileVersion int                     `yaml:"lockfileVersion"`
 	Commands        map[string]*LockCommand `yaml:"commands"`
 	Plugins         map[string]*LockPlugin  `yaml:"plugins,omitempty"`
+
+	// base is the content the lock file had when it was read or last written, which
+	// WriteLockFile and Reload merge concurrent changes against
+	base *LockFile
 }
 
+// LocalSource is the lock file source of local-only commands, which live in the project
+// itself. ccmd.yaml does not list them, and sync and update leave them alone.
+const LocalSource = "local"
+
 // LockCommand represents a command entry in the lock file
 type LockCommand struct {
-	Name        string    `yaml:"name"`
-	Version     string    `yaml:"version"`
-	Source      string    `yaml:"source"`
-	Resolved    string    `yaml:"resolved"`
-	Commit      string    `yaml:"commit"`
+	Name     string `yaml:"name"`
+	Version  string `yaml:"version"`
+	Source   string `yaml:"source"`
+	Resolved string `yaml:"resolved"`
+	Commit   string `yaml:"commit"`
+	// Branch is the branch the command tracks when it was installed from a branch rather
+	// than a tag, such as the default branch of a repository without tags
+	Branch string `yaml:"branch,omitempty"`
+	// DefaultVersion is how the version of a command installed without one was picked,
+	// latest-release or default-branch; reinstalls and updates keep picking it that way
+	DefaultVersion string `yaml:"default_version,omitempty"`
+	// Checksum is the sha256 of the installed files, used to detect local modifications
+	Checksum string `yaml:"checksum,omitempty"`
+	// FileSize is the total size in bytes of the installed files
+	FileSize int64 `yaml:"file_size,omitempty"`
+	// License is the license of the command as its ccmd.yaml declared it when installed
+	License string `yaml:"license,omitempty"`
+	// Files lists the installed files when the files filter left some out; the checksum
+	// covers exactly these files
+	Files       []string  `yaml:"files,omitempty"`
 	InstalledAt time.Time `yaml:"installed_at"`
 	UpdatedAt   time.Time `yaml:"updated_at"`
+	// Instance marks a side-by-side install made with --as, keyed by the instance name
+	Instance bool `yaml:"instance,omitempty"`
+	// Ephemeral marks a command installed with --no-save, which ccmd.yaml does not list
+	// and sync neither removes nor reinstalls
+	Ephemeral bool `yaml:"ephemeral,omitempty"`
+	// Pinned marks a command frozen at its commit with 'ccmd pin', which update skips;
+	// PinnedFrom is the version ccmd.yaml requested before, restored by 'ccmd unpin'
+	Pinned     bool   `yaml:"pinned,omitempty"`
+	PinnedFrom string `yaml:"pinned_from,omitempty"`
+	// Standalone records the standalone files written for the command, keyed by their
+	// path relative to the project root, to tell files edited by hand from stale ones
+	Standalone map[string]*LockStandalone `yaml:"standalone,omitempty"`
+	// Submodules pins the commit of each git submodule, keyed by its path in the repository
+	Submodules map[string]string `yaml:"submodules,omitempty"`
+	// Assets records the release asset installed for each helper binary, keyed by its
+	// path in the command directory
+	Assets map[string]*LockAsset `yaml:"assets,omitempty"`
+	// History lists the versions the command was installed at before, oldest first and
+	// at most HistoryLimit of them, for 'ccmd history' and 'ccmd rollback'
+	History []LockHistory `yaml:"history,omitempty"`
+}
+
+// Local reports whether the command lives in the project instead of a repository
+func (c *LockCommand) Local() bool {
+	return c.Source == LocalSource
+}
+
+// LockStandalone records one standalone command file
+type LockStandalone struct {
+	// Checksum is the sha256 of the file as ccmd wrote it, or as it was kept for overrides
+	Checksum string `yaml:"checksum"`
+	// Override marks a file edited by hand that regen, update and sync leave alone
+	Override bool `yaml:"override,omitempty"`
 }
 
 // LockPlugin represents a plugin entry in the lock file
@@ -45,8 +101,12 @@ type LockPlugin struct {
 	Source      string    `yaml:"source"`
 	Resolved    string    `yaml:"resolved"`
 	Commit      string    `yaml:"commit"`
+	FileSize    int64     `yaml:"file_size,omitempty"` // total size in bytes of the installed files
+	License     string    `yaml:"license,omitempty"`   // license declared by the plugin's ccmd.yaml
 	InstalledAt time.Time `yaml:"installed_at"`
 	UpdatedAt   time.Time `yaml:"updated_at"`
+	// Ephemeral marks a plugin installed with --no-save, which ccmd.yaml does not list
+	Ephemeral bool `yaml:"ephemeral,omitempty"`
 }
 
 // MarketplaceSource represents the source configuration for a plugin marketplace
@@ -87,18 +147,52 @@ type ProjectConfig struct {
 	Author      string   `yaml:"author" json:"author"`
 	Repository  string   `yaml:"repository" json:"repository"`
 	Entry       string   `yaml:"entry,omitempty" json:"entry,omitempty"`
+	Exec        string   `yaml:"exec,omitempty" json:"exec,omitempty"` // script run by 'ccmd exec', relative to the command directory
 	Tags        []string `yaml:"tags,omitempty" json:"tags,omitempty"`
 	License     string   `yaml:"license,omitempty" json:"license,omitempty"`
 	Homepage    string   `yaml:"homepage,omitempty" json:"homepage,omitempty"`
+	// ArgumentHint describes the arguments of the command, such as "<file> [--fix]"
+	ArgumentHint string `yaml:"argument_hint,omitempty" json:"argument_hint,omitempty"`
 
 	// Type indicates whether this is a "plugin" or command (default)
 	Type string `yaml:"type,omitempty" json:"type,omitempty"`
 
+	// Extends names a shared base configuration whose commands, plugins, profiles and
+	// settings are merged beneath the project's own (see parseBaseRef)
+	Extends string `yaml:"extends,omitempty" json:"extends,omitempty"`
+
+	// Base is the configuration Extends names, merged in by LoadProjectConfig
+	Base *ProjectConfig `yaml:"-" json:"-"`
+
 	// Commands list (when ccmd.yaml is for a project)
 	Commands []string `yaml:"commands,omitempty" json:"commands,omitempty"`
 
 	// Plugins list (when ccmd.yaml is for a project)
 	Plugins []string `yaml:"plugins,omitempty" json:"plugins,omitempty"`
+
+	// Profiles groups commands by role. A profile selected with --profile is installed
+	// in addition to the shared Commands list.
+	Profiles map[string][]string `yaml:"profiles,omitempty" json:"profiles,omitempty"`
+
+	// Conditions holds the os and when conditions of entries written as mappings, by
+	// specKey. Entries whose condition does not hold are skipped by install and sync.
+	Conditions map[string]*EntryCondition `yaml:"-" json:"conditions,omitempty"`
+
+	// DefaultHost is the forge used for owner/repo shorthands (default github.com)
+	DefaultHost string `yaml:"default_host,omitempty" json:"default_host,omitempty"`
+
+	// Hosts holds per-host settings keyed by host name
+	Hosts map[string]HostConfig `yaml:"hosts,omitempty" json:"hosts,omitempty"`
+
+	// Targets lists the layouts standalone command files are written to (default: claude)
+	Targets []Target `yaml:"targets,omitempty" json:"targets,omitempty"`
+
+	// Files selects the files of a command repository that are installed
+	Files *FileFilter `yaml:"files,omitempty" json:"files,omitempty"`
+
+	// Assets maps helper binaries, by path relative to the command directory, to the
+	// release assets downloaded for each platform
+	Assets map[string]Asset `yaml:"assets,omitempty" json:"assets,omitempty"`
 }
 
 // ConfigCommand represents a command in the configuration
@@ -136,18 +230,27 @@ func (pc *ProjectConfig) Validate() error {
 			return errors.InvalidInput("entry is required")
 		}
 	}
+	if pc.Exec != "" {
+		if err := validateExecPath(pc.Exec); err != nil {
+			return err
+		}
+	}
+
+	if err := validateAssets(pc.Assets); err != nil {
+		return err
+	}
 
-	return nil
+	return pc.Files.Validate()
 }
 
 // MarshalYAML marshals ProjectConfig to YAML
 func (pc *ProjectConfig) MarshalYAML() ([]byte, error) {
-	return yaml.Marshal(pc)
+	return encodeProjectConfig(pc)
 }
 
 // UnmarshalYAML unmarshals YAML data into ProjectConfig
 func (pc *ProjectConfig) UnmarshalYAML(data []byte) error {
-	return yaml.Unmarshal(data, pc)
+	return decodeProjectConfig(data, pc)
 }
 
 // MarshalJSON marshals ProjectConfig to JSON
@@ -166,21 +269,112 @@ func (pc *ProjectConfig) UnmarshalJSON(data []byte) error {
 func (pc *ProjectConfig) GetConfigCommands() []ConfigCommand {
 	commands := make([]ConfigCommand, 0, len(pc.Commands))
 	for _, cmd := range pc.Commands {
-		repo, version := ParseCommandSpec(cmd)
+		repo, version, instance := ParseInstanceSpec(cmd)
 		commands = append(commands, ConfigCommand{
 			Repo:    repo,
 			Version: version,
+			Name:    instance,
 		})
 	}
 	return commands
 }
 
-// ParseCommandSpec parses a command specification (e.g., "owner/repo@version")
+// ProfileNames returns the names of the profiles defined in ccmd.yaml, sorted
+func (pc *ProjectConfig) ProfileNames() []string {
+	names := make([]string, 0, len(pc.Profiles))
+	for name := range pc.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// CommandsForProfile returns the shared commands followed by the commands of a profile.
+// Entries listed in both are returned once, with the shared entry. An empty
+// profile returns the shared commands only. Entries whose condition does not hold on
+// this machine are left out.
+func (pc *ProjectConfig) CommandsForProfile(profile string) ([]string, error) {
+	if profile == "" {
+		return pc.activeSpecs(pc.Commands), nil
+	}
+
+	specs, ok := pc.Profiles[profile]
+	if !ok {
+		available := "none defined"
+		if names := pc.ProfileNames(); len(names) > 0 {
+			available = "available: " + strings.Join(names, ", ")
+		}
+		return nil, errors.NotFound(fmt.Sprintf("profile %q in ccmd.yaml (%s)", profile, available))
+	}
+
+	commands := append([]string{}, pc.Commands...)
+	seen := make(map[string]bool)
+	for _, spec := range pc.Commands {
+		seen[specKey(spec)] = true
+	}
+	for _, spec := range specs {
+		if !seen[specKey(spec)] {
+			seen[specKey(spec)] = true
+			commands = append(commands, spec)
+		}
+	}
+
+	return pc.activeSpecs(commands), nil
+}
+
+// inAnyProfile reports whether an entry is listed in one of the profiles. key is the
+// repository path, or the instanceKey of a side-by-side install.
+func (pc *ProjectConfig) inAnyProfile(key string) bool {
+	for _, specs := range pc.Profiles {
+		for _, spec := range specs {
+			if specKey(spec) == key {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ParseCommandSpec parses a command specification (e.g., "owner/repo@version"). The
+// instance name of a side-by-side install is ignored.
 func ParseCommandSpec(spec string) (repo, version string) {
-	parts := strings.Split(spec, "@")
-	repo = parts[0]
-	if len(parts) > 1 {
-		version = parts[1]
+	spec, _, _ = strings.Cut(spec, " as ")
+	return ParseRepositorySpec(strings.TrimSpace(spec))
+}
+
+// ParseInstanceSpec parses a command specification that may name a side-by-side install
+// (e.g., "owner/repo@v1 as repo-v1")
+func ParseInstanceSpec(spec string) (repo, version, instance string) {
+	repo, version = ParseCommandSpec(spec)
+	if _, name, ok := strings.Cut(spec, " as "); ok {
+		instance = strings.TrimSpace(name)
+	}
+	return repo, version, instance
+}
+
+// formatCommandSpec returns the ccmd.yaml entry of a repository, version and instance
+func formatCommandSpec(repo, version, instance string) string {
+	spec := repo
+	if version != "" {
+		spec += "@" + version
 	}
-	return repo, version
+	if instance != "" {
+		spec += " as " + instance
+	}
+	return spec
+}
+
+// instanceKey identifies an entry of ccmd.yaml: the repository path, plus the instance
+// name for side-by-side installs of the same repository
+func instanceKey(repoPath, instance string) string {
+	if instance == "" {
+		return repoPath
+	}
+	return repoPath + " as " + instance
+}
+
+// specKey returns the instanceKey of a ccmd.yaml entry
+func specKey(spec string) string {
+	repo, _, instance := ParseInstanceSpec(spec)
+	return instanceKey(ExtractRepoPath(repo), instance)
 }
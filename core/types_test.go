@@ -16,6 +16,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"gopkg.in/yaml.v3"
+
+	"github.com/gifflet/ccmd/pkg/errors"
 )
 
 func TestLockFile_Marshal(t *testing.T) {
@@ -180,3 +182,101 @@ func TestLockFile_RoundTrip(t *testing.T) {
 		assert.Equal(t, original.Commands["test-cmd"].UpdatedAt.Unix(), cmd.UpdatedAt.Unix())
 	})
 }
+
+func TestCommandsForProfile(t *testing.T) {
+	config := &ProjectConfig{
+		Commands: []string{"acme/shared", "acme/api@^1.0.0"},
+		Profiles: map[string][]string{
+			"backend": {"acme/api@^2.0.0", "acme/db"},
+			"docs":    {"acme/writer"},
+		},
+	}
+
+	commands, err := config.CommandsForProfile("")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"acme/shared", "acme/api@^1.0.0"}, commands)
+
+	// Profile entries are added to the shared list; shared entries win for the same repository
+	commands, err = config.CommandsForProfile("backend")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"acme/shared", "acme/api@^1.0.0", "acme/db"}, commands)
+
+	assert.Equal(t, []string{"backend", "docs"}, config.ProfileNames())
+
+	_, err = config.CommandsForProfile("frontend")
+	require.ErrorIs(t, err, errors.ErrNotFound)
+	assert.Contains(t, err.Error(), "backend, docs")
+}
+
+func FuzzParseCommandSpec(f *testing.F) {
+	for _, seed := range []string{
+		"acme/tool@^1.0.0 as tool-v1",
+		"git@github.com:acme/tool.git@v1.0.0",
+		"acme/tool@>=1.0.0 <2.0.0",
+		"acme/tool//lint@sha1:a76c96359914b84ed1bcdbc11df03e6313e09ecf",
+		"https://example.com/tool.tar.gz",
+		"acme/tool＠v1.0.0",
+		"acme/tool/@v1.0.0",
+		"acme/ tool as  x ",
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, spec string) {
+		repo, version, instance := ParseInstanceSpec(spec)
+		offset, msg := checkSpec(spec, true)
+		if offset < 0 || offset > len(spec) {
+			t.Fatalf("checkSpec(%q) reported offset %d", spec, offset)
+		}
+		if msg != "" {
+			return
+		}
+
+		// Accepted entries name a repository and are written back to the same entry
+		if repo == "" {
+			t.Fatalf("ParseInstanceSpec(%q) has no repository", spec)
+		}
+		formatted := formatCommandSpec(repo, version, instance)
+		if _, msg := checkSpec(formatted, true); msg != "" {
+			t.Fatalf("checkSpec(%q) of %q: %s", formatted, spec, msg)
+		}
+		if specKey(formatted) != specKey(spec) {
+			t.Fatalf("specKey(%q) = %q, specKey(%q) = %q", formatted, specKey(formatted), spec, specKey(spec))
+		}
+	})
+}
+
+func TestParseInstanceSpec(t *testing.T) {
+	repo, version, instance := ParseInstanceSpec("acme/tool@^1.0.0 as tool-v1")
+	assert.Equal(t, "acme/tool", repo)
+	assert.Equal(t, "^1.0.0", version)
+	assert.Equal(t, "tool-v1", instance)
+
+	repo, version = ParseCommandSpec("acme/tool@^1.0.0 as tool-v1")
+	assert.Equal(t, "acme/tool", repo)
+	assert.Equal(t, "^1.0.0", version)
+
+	_, _, instance = ParseInstanceSpec("acme/tool@v2.0.0")
+	assert.Empty(t, instance)
+
+	repo, version = ParseCommandSpec("git@github.com:acme/tool.git@v1.0.0")
+	assert.Equal(t, "git@github.com:acme/tool.git", repo)
+	assert.Equal(t, "v1.0.0", version)
+
+	assert.Equal(t, "acme/tool@v1.0.0 as tool-v1", formatCommandSpec("acme/tool", "v1.0.0", "tool-v1"))
+	assert.Equal(t, "acme/tool", specKey("https://github.com/acme/tool.git@v2.0.0"))
+	assert.Equal(t, "acme/tool as tool-v1", specKey("acme/tool@v1.0.0 as tool-v1"))
+
+	// Instances of a repository are separate entries of a profile
+	config := &ProjectConfig{
+		Commands: []string{"acme/tool@^2.0.0"},
+		Profiles: map[string][]string{"migration": {"acme/tool@^1.0.0 as tool-v1"}},
+	}
+	commands, err := config.CommandsForProfile("migration")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"acme/tool@^2.0.0", "acme/tool@^1.0.0 as tool-v1"}, commands)
+	assert.Equal(t, []ConfigCommand{
+		{Repo: "acme/tool", Version: "^2.0.0"},
+		{Repo: "acme/tool", Version: "^1.0.0", Name: "tool-v1"},
+	}, (&ProjectConfig{Commands: commands}).GetConfigCommands())
+}
@@ -12,11 +12,11 @@ package core
 import (
 	"context"
 	"fmt"
-	"path/filepath"
 	"strings"
 
 	"github.com/gifflet/ccmd/pkg/errors"
 	"github.com/gifflet/ccmd/pkg/output"
+	"github.com/gifflet/ccmd/pkg/telemetry"
 )
 
 // UpdateOptions represents options for updating commands
@@ -25,6 +25,35 @@ type UpdateOptions struct {
 	All       bool   // Update all commands
 	CheckOnly bool   // Only check for updates without installing
 	Force     bool   // Force update even if version appears current
+
+	// ProjectPath is a path inside the project to update; empty uses the working directory
+	ProjectPath string
+
+	// OverwriteLocal replaces commands whose files were modified after install. Backup
+	// does the same but first copies the modified files to .claude/.backups.
+	OverwriteLocal bool
+	Backup         bool
+
+	// SaveStrategy rewrites the ccmd.yaml constraint (exact, caret or tilde); empty keeps it
+	SaveStrategy string
+
+	// Pre lets updates pick prerelease tags for every command, as the beta channel does
+	// for the ccmd.yaml entries that select it
+	Pre bool
+
+	// Confirm is called with each pending update before it is installed. Returning false
+	// skips the update. A nil Confirm applies every update.
+	Confirm func(plan *UpdatePlan) bool
+
+	// Progress receives the steps of each reinstall, as InstallOptions.Progress does
+	Progress ProgressFunc
+
+	// Format prints the result of CheckOnly with All as an output table format: table
+	// (default), csv or tsv
+	Format string
+
+	commit string // commit to install, fixed in advance by a sync plan
+	audit  string // audit action of the reinstall, AuditUpdate when empty
 }
 
 // UpdateResult represents the result of an update operation
@@ -32,6 +61,23 @@ type UpdateResult struct {
 	UpdatedCount int
 	FailedCount  int
 	CheckedCount int
+	SkippedCount int
+}
+
+// UpdatePlan describes a pending update of one command
+type UpdatePlan struct {
+	Name           string
+	Repository     string
+	Constraint     string // version range from ccmd.yaml, if any
+	Channel        string // update channel: stable, or beta for prereleases
+	CurrentVersion string // installed ref
+	TargetVersion  string // ref that will be installed
+	Branch         string // branch the command tracks, when it was installed from one
+	Reason         string
+	Changelog      string // CHANGELOG excerpt between the two versions, when available
+	// PromptDiff summarizes the changes to the entry file. It is only fetched for updates
+	// that ask for confirmation, and is nil when the versions could not be compared.
+	PromptDiff *PromptDiff
 }
 
 // Update updates one or more installed commands
@@ -44,16 +90,34 @@ func Update(ctx context.Context, opts UpdateOptions) (*UpdateResult, error) {
 		return nil, errors.InvalidInput("command name required (or use --all)")
 	}
 
-	if opts.All {
-		return updateAllCommands(ctx, opts.CheckOnly, opts.Force)
+	if err := ValidateSaveStrategy(opts.SaveStrategy); err != nil {
+		return nil, err
+	}
+
+	if !opts.CheckOnly {
+		if projectRoot, err := findProjectRootFrom(opts.ProjectPath); err == nil {
+			defer snapshotBefore(projectRoot, AuditUpdate)()
+		}
 	}
 
-	return updateSingleCommand(ctx, opts.Name, opts.CheckOnly, opts.Force)
+	ctx, span := telemetry.Start(ctx, "update", telemetry.Bool("all", opts.All), telemetry.Bool("check_only", opts.CheckOnly))
+	var result *UpdateResult
+	var err error
+	if opts.All {
+		result, err = updateAllCommands(ctx, opts)
+	} else {
+		result, err = updateSingleCommand(ctx, opts)
+	}
+	if result != nil {
+		span.SetAttributes(telemetry.Int("updated", int64(result.UpdatedCount)), telemetry.Int("failed", int64(result.FailedCount)))
+	}
+	span.End(err)
+	return result, err
 }
 
-func updateAllCommands(ctx context.Context, checkOnly, force bool) (*UpdateResult, error) {
+func updateAllCommands(ctx context.Context, opts UpdateOptions) (*UpdateResult, error) {
 	// List all commands
-	commands, err := List(ListOptions{})
+	commands, err := List(ListOptions{ProjectPath: opts.ProjectPath})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list commands: %w", err)
 	}
@@ -63,48 +127,56 @@ func updateAllCommands(ctx context.Context, checkOnly, force bool) (*UpdateResul
 		return &UpdateResult{}, nil
 	}
 
+	projectRoot, err := findProjectRootFrom(opts.ProjectPath)
+	if err != nil {
+		return nil, err
+	}
+	ctx = withProjectRoot(ctx, projectRoot)
+
+	if opts.CheckOnly {
+		return checkAllCommands(ctx, projectRoot, commands, opts)
+	}
+
 	output.PrintInfof("Checking %d commands for updates...", len(commands))
 
 	result := &UpdateResult{}
 
 	for _, cmd := range commands {
+		if cmd.LocalOnly {
+			output.PrintVerbosef("Skipping %s: it is local-only and has no source to update from", cmd.Name)
+			continue
+		}
 		output.PrintInfof("\nChecking %s...", cmd.Name)
 		result.CheckedCount++
 
-		_, version := ParseCommandSpec(cmd.Resolved)
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
 
-		needsUpdate, reason := shouldUpdateCommand(cmd.Name, version, force)
+		plan, needsUpdate := planUpdate(ctx, projectRoot, cmd, opts.Force, opts.Pre)
 
-		if checkOnly {
-			if strings.Contains(reason, "pinned to commit") {
-				output.PrintInfof("Installed with commit %.7s (no updates for commits)", version)
-			} else if needsUpdate {
-				output.PrintWarningf("Update available for %s", cmd.Name)
-			} else {
-				output.PrintInfof("%s is up to date", cmd.Name)
-			}
+		if cmd.Pinned || strings.Contains(plan.Reason, "pinned to commit") && isFullCommitHash(plan.CurrentVersion) {
+			output.PrintInfof("%s is %s", cmd.Name, plan.Reason)
+			refreshUpToDateCommand(projectRoot, cmd.Name)
 			continue
 		}
-
 		if !needsUpdate {
 			output.PrintInfof("%s is already up to date", cmd.Name)
+			refreshUpToDateCommand(projectRoot, cmd.Name)
 			continue
 		}
 
-		if force && isCommitHash(version) {
-			output.PrintWarningf("Force updating command installed with commit %.7s", version)
+		if opts.Force && isCommitHash(plan.CurrentVersion) {
+			output.PrintWarningf("Force updating command installed with commit %.7s", plan.CurrentVersion)
 		}
 
-		// Force reinstall to update
-		// Note: We don't pass Name to allow Install to use the name from ccmd.yaml
-		// This handles cases where the command name changed in the remote repository
-		opts := InstallOptions{
-			Repository: cmd.Repository,
-			Version:    version,
-			Force:      true,
+		if !confirmUpdate(ctx, projectRoot, plan, opts.Confirm) {
+			output.PrintInfof("Skipped %s", cmd.Name)
+			result.SkippedCount++
+			continue
 		}
 
-		if _, _, err := Install(ctx, opts); err != nil {
+		if err := applyUpdate(ctx, projectRoot, cmd, plan, opts); err != nil {
 			output.PrintErrorf("Failed to update %s: %v", cmd.Name, err)
 			result.FailedCount++
 		} else {
@@ -118,6 +190,9 @@ func updateAllCommands(ctx context.Context, checkOnly, force bool) (*UpdateResul
 	if result.UpdatedCount > 0 {
 		output.PrintSuccessf("%d command(s) updated", result.UpdatedCount)
 	}
+	if result.SkippedCount > 0 {
+		output.PrintInfof("%d command(s) skipped", result.SkippedCount)
+	}
 	if result.FailedCount > 0 {
 		output.PrintErrorf("%d command(s) failed to update", result.FailedCount)
 	}
@@ -125,49 +200,116 @@ func updateAllCommands(ctx context.Context, checkOnly, force bool) (*UpdateResul
 	return result, nil
 }
 
-func checkIfUpdateNeeded(commandName, version string) (bool, error) {
-	// Get project root
-	projectRoot, err := findProjectRoot()
-	if err != nil {
-		return false, err
+// checkAllCommands reports which commands have updates as a table with their current
+// and target versions, channel and status: UPDATE, OK, or UNKNOWN when the check failed
+func checkAllCommands(ctx context.Context, projectRoot string, commands []CommandDetail, opts UpdateOptions) (*UpdateResult, error) {
+	table := output.NewTable(
+		output.Column{Header: "NAME", MaxWidth: 30},
+		output.Column{Header: "CURRENT", MaxWidth: 20},
+		output.Column{Header: "TARGET", MaxWidth: 20},
+		output.Column{Header: "CHANNEL"},
+		output.Column{Header: "STATUS", Status: true},
+		output.Column{Header: "REASON", MaxWidth: 50},
+	)
+	if opts.Format == "" || opts.Format == output.FormatTable {
+		output.PrintInfof("Checking %d commands for updates...\n", len(commands))
 	}
 
-	commandDir := filepath.Join(projectRoot, ".claude", "commands", commandName)
-	if !dirExists(commandDir) {
+	result := &UpdateResult{}
+	updates := 0
+	for _, cmd := range commands {
+		if cmd.LocalOnly {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		result.CheckedCount++
+
+		plan, needsUpdate := planUpdate(ctx, projectRoot, cmd, opts.Force, opts.Pre)
+		status := output.StatusOK
+		switch {
+		case strings.HasPrefix(plan.Reason, "check failed"):
+			status = "UNKNOWN"
+		case needsUpdate:
+			status = output.StatusUpdate
+			updates++
+		}
+		current, target := versionOrLatest(plan.CurrentVersion), versionOrLatest(plan.TargetVersion)
+		if plan.Branch != "" && plan.TargetVersion == plan.Branch {
+			current, target = BranchLabel(plan.Branch), BranchLabel(plan.Branch)
+		}
+		table.AddRow(cmd.Name, current, target, plan.Channel, status, plan.Reason)
+	}
+
+	if err := table.Print(opts.Format); err != nil {
+		return result, err
+	}
+	if opts.Format == "" || opts.Format == output.FormatTable {
+		output.PrintInfof("\n%d of %d command(s) have updates; run 'ccmd update --all' to install them",
+			updates, result.CheckedCount)
+	}
+	return result, nil
+}
+
+// versionOrLatest labels an empty version, which tracks the latest commit
+func versionOrLatest(version string) string {
+	if version == "" {
+		return "latest"
+	}
+	return version
+}
+
+// checkIfUpdateNeeded compares the locked commit of a command with the commit its
+// version resolves to on the remote. The remote is queried with git ls-remote, so no
+// checkout is needed; an empty version resolves as an install without one would.
+func checkIfUpdateNeeded(ctx context.Context, projectRoot string, cmd CommandDetail, version string) (bool, error) {
+	if !dirExists(installedCommandDir(projectRoot, cmd.Name)) {
 		// Command directory doesn't exist, needs update
 		return true, nil
 	}
 
-	localCommit, err := gitGetRefCommit(commandDir, version)
+	lockFile, err := ReadLockFile(lockFilePath(projectRoot))
 	if err != nil {
-		// Local ref doesn't exist or error getting it
+		return false, err
+	}
+	locked, ok := lockFile.Commands[cmd.Name]
+	if !ok || !isCommitHash(locked.Commit) {
+		// Without a known commit there is nothing to compare
 		return true, nil
 	}
 
-	remoteCommit, err := gitGetRemoteRefCommit(commandDir, version)
+	cloneURL, _ := splitRepositoryCommand(normalizeRepositoryURL(projectRoot, cmd.Repository))
+	remote, err := ResolveRemote(ctx, cloneURL, version)
 	if err != nil {
-		// Can't get remote ref, assume update needed
-		return true, nil
+		return false, err
 	}
+	return remote.Commit != locked.Commit, nil
+}
 
-	return localCommit != remoteCommit, nil
+// refreshUpToDateCommand regenerates the standalone file of a command that was not reinstalled,
+// so local changes to index.md or ccmd.yaml are still picked up
+func refreshUpToDateCommand(projectRoot, name string) {
+	refreshStandaloneDocs(projectRoot, name)
 }
 
 // shouldUpdateCommand determines if a command needs updating based on version and flags
-func shouldUpdateCommand(commandName, version string, force bool) (needsUpdate bool, reason string) {
-	if force {
-		return true, "forced update"
+func shouldUpdateCommand(ctx context.Context, projectRoot string, cmd CommandDetail, version string,
+	force bool) (needsUpdate bool, reason string) {
+	// Full commit hashes are immutable pins that only change in ccmd.yaml, even with force
+	if isFullCommitHash(version) {
+		return false, fmt.Sprintf("pinned to commit %.7s; install another commit to update", version)
 	}
 
-	if version == "" {
-		return true, "tracks latest version"
+	if force {
+		return true, "forced update"
 	}
 
 	if isCommitHash(version) {
 		return false, fmt.Sprintf("pinned to commit %.7s", version)
 	}
 
-	updateNeeded, err := checkIfUpdateNeeded(commandName, version)
+	updateNeeded, err := checkIfUpdateNeeded(ctx, projectRoot, cmd, version)
 	if err != nil {
 		return true, fmt.Sprintf("check failed: %v", err)
 	}
@@ -179,88 +321,281 @@ func shouldUpdateCommand(commandName, version string, force bool) (needsUpdate b
 	return true, "update available"
 }
 
-func updateSingleCommand(ctx context.Context, name string, checkOnly, force bool) (*UpdateResult, error) {
+// planUpdate decides the target version for a command. When ccmd.yaml holds a version
+// range, the newest tag satisfying it is the target, counting prereleases on the beta
+// channel or with pre; otherwise the installed ref is refreshed if its remote commit moved.
+// Commands installed from a branch move to the head of the branch.
+func planUpdate(ctx context.Context, projectRoot string, cmd CommandDetail, force, pre bool) (*UpdatePlan, bool) {
+	_, current := ParseCommandSpec(cmd.Resolved)
+	if cmd.Branch != "" {
+		current = cmd.Branch
+	}
+	plan := &UpdatePlan{
+		Name:           cmd.Name,
+		Repository:     cmd.Repository,
+		CurrentVersion: current,
+		TargetVersion:  current,
+		Branch:         cmd.Branch,
+	}
+
+	// Only 'ccmd unpin' lifts a pin, --force does not
+	if cmd.Pinned {
+		plan.Reason = fmt.Sprintf("pinned; run 'ccmd unpin %s' to update it", cmd.Name)
+		return plan, false
+	}
+
+	if IsArchiveSource(cmd.Repository) {
+		if force {
+			plan.Reason = "forced reinstall of archive"
+			return plan, true
+		}
+		plan.Reason = "installed from an archive; install a newer archive to update"
+		return plan, false
+	}
+	if IsMarkdownSource(cmd.Repository) {
+		if force {
+			plan.Reason = "forced download of markdown file"
+			return plan, true
+		}
+		plan.Reason = "installed from a markdown URL; use --force to download it again"
+		return plan, false
+	}
+
+	repoURL := normalizeRepositoryURL(projectRoot, cmd.Repository)
+	plan.Channel = configuredChannel(projectRoot, repoURL, cmdInstance(cmd))
+	if pre {
+		plan.Channel = ChannelBeta
+	}
+	if constraint := configuredConstraint(projectRoot, repoURL, cmdInstance(cmd)); constraint != "" {
+		plan.Constraint = constraint
+
+		target, err := resolveConstraint(ctx, nil, repoURL, constraint, plan.Channel == ChannelBeta)
+		if err != nil {
+			plan.Reason = fmt.Sprintf("check failed: %v", err)
+			return plan, force
+		}
+		plan.TargetVersion = target
+
+		switch {
+		case target != current:
+			plan.Reason = fmt.Sprintf("newer version satisfies %s", constraint)
+			return plan, true
+		case force:
+			plan.Reason = "forced update"
+			return plan, true
+		default:
+			plan.Reason = "already up to date"
+			return plan, false
+		}
+	}
+
+	needsUpdate, reason := shouldUpdateCommand(ctx, projectRoot, cmd, current, force)
+	if needsUpdate && reason == "update available" && cmd.Branch != "" {
+		reason = fmt.Sprintf("new commits on %s", cmd.Branch)
+	}
+	plan.Reason = reason
+	return plan, needsUpdate
+}
+
+// confirmUpdate shows the version change and changelog excerpt, then asks for confirmation.
+// Confirm receives the plan with the changes to the prompt, which it shows before asking.
+func confirmUpdate(ctx context.Context, projectRoot string, plan *UpdatePlan, confirm func(*UpdatePlan) bool) bool {
+	if _, err := ParseSemver(plan.TargetVersion); err == nil && plan.TargetVersion != plan.CurrentVersion {
+		if content, err := fetchChangelog(ctx, projectRoot, normalizeRepositoryURL(projectRoot, plan.Repository), plan.TargetVersion); err == nil {
+			plan.Changelog = changelogExcerpt(content, plan.CurrentVersion, plan.TargetVersion)
+		}
+	}
+
+	output.PrintInfof("%s%s", plan.Name, versionChange(plan))
+	if plan.Constraint != "" {
+		output.PrintInfof("  Constraint: %s", plan.Constraint)
+	}
+	if plan.Channel == ChannelBeta {
+		output.PrintInfof("  Channel: %s", plan.Channel)
+	}
+	if plan.Changelog != "" {
+		output.PrintInfof("  Changes:")
+		for _, line := range strings.Split(plan.Changelog, "\n") {
+			output.Printf("    %s", line)
+		}
+	}
+
+	if confirm == nil {
+		return true
+	}
+	diff, err := fetchPromptDiff(ctx, projectRoot, plan)
+	if err != nil {
+		output.PrintVerbosef("Could not compare the prompt of %s: %v", plan.Name, err)
+	}
+	plan.PromptDiff = diff
+	return confirm(plan)
+}
+
+// versionChange formats " (v1.0.0 → v1.2.0)" for a plan, " (main (branch))" when it
+// follows a branch, or "" when the ref does not change
+func versionChange(plan *UpdatePlan) string {
+	if plan.Branch != "" && plan.TargetVersion == plan.Branch {
+		return fmt.Sprintf(" (%s)", BranchLabel(plan.Branch))
+	}
+	if plan.TargetVersion == "" || plan.TargetVersion == plan.CurrentVersion {
+		return ""
+	}
+	return fmt.Sprintf(" (%s → %s)", versionOrLatest(plan.CurrentVersion), plan.TargetVersion)
+}
+
+// applyUpdate installs the target version. The command files, ccmd.yaml and ccmd-lock.yaml
+// are snapshotted first and restored together if the install fails.
+func applyUpdate(ctx context.Context, projectRoot string, cmd CommandDetail, plan *UpdatePlan, updateOpts UpdateOptions) error {
+	snapshot, err := takeUpdateSnapshot(projectRoot, cmd)
+	if err != nil {
+		return err
+	}
+	defer snapshot.discard()
+
+	// Force reinstall to update
+	// Note: We don't pass Name to allow Install to use the name from ccmd.yaml
+	// This handles cases where the command name changed in the remote repository
+	opts := InstallOptions{
+		Repository:   cmd.Repository,
+		Version:      plan.TargetVersion,
+		Commit:       updateOpts.commit,
+		As:           cmdInstance(cmd),
+		Force:        true,
+		SaveStrategy: updateOpts.SaveStrategy,
+		Pre:          updateOpts.Pre,
+		NoSave:       cmd.Ephemeral,
+		Progress:     updateOpts.Progress,
+		ProjectPath:  projectRoot,
+
+		OverwriteLocal: updateOpts.OverwriteLocal,
+		Backup:         updateOpts.Backup,
+
+		defaultVersion: cmd.DefaultVersion,
+	}
+
+	action := updateOpts.audit
+	if action == "" {
+		action = AuditUpdate
+	}
+
+	name, _, err := install(ctx, opts)
+	if err != nil {
+		if restoreErr := snapshot.restore(); restoreErr != nil {
+			err = fmt.Errorf("%w (rollback failed: %v)", err, restoreErr)
+		}
+		auditInstall(action, opts, cmd.Name, plan.CurrentVersion, err)
+		return err
+	}
+
+	auditInstall(action, opts, name, plan.CurrentVersion, nil)
+	return nil
+}
+
+func updateSingleCommand(ctx context.Context, opts UpdateOptions) (*UpdateResult, error) {
+	name := opts.Name
+
+	projectRoot, err := findProjectRootFrom(opts.ProjectPath)
+	if err != nil {
+		return nil, err
+	}
+	ctx = withProjectRoot(ctx, projectRoot)
+
 	// Get command info
-	cmdInfo, err := GetCommandInfo(name, "")
+	cmdInfo, err := GetCommandInfo(name, projectRoot)
 	if err != nil {
 		return nil, errors.NotFound(fmt.Sprintf("command %q", name))
 	}
+	if cmdInfo.LocalOnly {
+		return nil, errors.InvalidInput(fmt.Sprintf("command %q is local-only and has no source to update from", name))
+	}
 
 	output.PrintInfof("Checking %s for updates...", name)
 
 	result := &UpdateResult{CheckedCount: 1}
 
-	// Extract version from Resolved field (repo@version or repo@commit)
-	_, version := ParseCommandSpec(cmdInfo.Resolved)
-
 	// Check if update is needed
-	needsUpdate, reason := shouldUpdateCommand(name, version, force)
+	plan, needsUpdate := planUpdate(ctx, projectRoot, *cmdInfo, opts.Force, opts.Pre)
+	version := plan.CurrentVersion
+	reason := plan.Reason
 
-	if checkOnly {
+	if opts.CheckOnly {
 		// Report status based on reason
-		if strings.Contains(reason, "pinned to commit") {
+		if cmdInfo.Pinned {
+			output.PrintInfof("Command %q is %s", name, reason)
+		} else if strings.Contains(reason, "pinned to commit") {
 			output.PrintInfof("Command %q is installed with commit %.7s (no updates for commits)", name, version)
-		} else if strings.Contains(reason, "tracks latest") {
-			output.PrintWarningf("Command %q tracks latest version, update may be available", name)
 		} else if strings.Contains(reason, "check failed") {
 			output.PrintWarningf("Could not check for updates: %s", reason)
 		} else if needsUpdate {
-			output.PrintWarningf("Update available for %q", name)
+			output.PrintWarningf("Update available for %q%s", name, versionChange(plan))
 		} else {
 			output.PrintInfof("Command %q is up to date", name)
 		}
 		output.PrintInfof("Current version: %s", cmdInfo.Version)
+		if plan.Channel != "" {
+			output.PrintInfof("Channel: %s", plan.Channel)
+		}
+		return result, nil
+	}
+
+	if cmdInfo.Pinned {
+		output.PrintWarningf("Command %q is %s", name, reason)
+		refreshUpToDateCommand(projectRoot, name)
 		return result, nil
 	}
 
 	// Handle commit hash updates
-	if version != "" && isCommitHash(version) && !force {
+	if version != "" && isCommitHash(version) && (!opts.Force || isFullCommitHash(version)) {
 		output.PrintWarningf("Command %q is installed with commit hash %.7s and cannot be updated.", name, version)
-		output.PrintWarningf("To change versions, reinstall with a different tag, branch, or commit.")
+		if isFullCommitHash(version) {
+			output.PrintWarningf("To change versions, install another commit with --force, which updates the pin in %s.", ConfigFileName)
+		} else {
+			output.PrintWarningf("To change versions, reinstall with a different tag, branch, or commit.")
+		}
+		refreshUpToDateCommand(projectRoot, name)
 		return result, nil
 	}
 
-	// Check if update is needed
 	if !needsUpdate {
 		output.PrintInfof("Command %q is already up to date", name)
+		refreshUpToDateCommand(projectRoot, name)
 		return result, nil
 	}
 
 	// Show update reason if special case
-	if force && isCommitHash(version) {
+	if opts.Force && isCommitHash(version) {
 		output.PrintWarningf("Force updating command installed with commit hash %.7s", version)
 	} else if strings.Contains(reason, "check failed") {
 		output.PrintWarningf("Proceeding with update: %s", reason)
 	}
 
-	// Force reinstall to update
-	// Note: We don't pass Name to allow Install to use the name from ccmd.yaml
-	// This handles cases where the command name changed in the remote repository
-	opts := InstallOptions{
-		Repository: cmdInfo.Repository,
-		Version:    version,
-		Force:      true,
+	if !confirmUpdate(ctx, projectRoot, plan, opts.Confirm) {
+		output.PrintInfof("Update canceled")
+		result.SkippedCount = 1
+		return result, nil
 	}
 
-	if _, _, err := Install(ctx, opts); err != nil {
+	if err := applyUpdate(ctx, projectRoot, *cmdInfo, plan, opts); err != nil {
 		result.FailedCount = 1
 		return result, fmt.Errorf("failed to update: %w", err)
 	}
 
 	// Get the current name of the command after installation
-	projectRoot, err := findProjectRoot()
-	if err == nil {
-		currentName, _ := findExistingCommandByRepo(projectRoot, ExtractRepoPath(cmdInfo.Repository))
-		if currentName != "" && currentName != name {
-			output.PrintSuccessf("Command %q updated to %q successfully", name, currentName)
-		} else {
-			output.PrintSuccessf("Command %q updated successfully", name)
-		}
+	currentName, _ := findExistingCommandByRepo(projectRoot, ExtractRepoPath(cmdInfo.Repository), cmdInstance(*cmdInfo))
+	if currentName != "" && currentName != name {
+		output.PrintSuccessf("Command %q updated to %q successfully", name, currentName)
 	} else {
-		// Fallback if we can't determine the project root
 		output.PrintSuccessf("Command %q updated successfully", name)
 	}
 
 	result.UpdatedCount = 1
 	return result, nil
 }
+
+// cmdInstance returns the instance name of a side-by-side install, or ""
+func cmdInstance(cmd CommandDetail) string {
+	if cmd.Instance {
+		return cmd.Name
+	}
+	return ""
+}
@@ -0,0 +1,117 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/gifflet/ccmd/internal/fs"
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+// updateSnapshot keeps copies of everything an update rewrites so a failed
+// install can be rolled back as a unit
+type updateSnapshot struct {
+	removeBackup func()            // removes the temporary directory holding the copies
+	files        map[string][]byte // path -> content; nil when the file did not exist
+	dirs         map[string]string // path -> backup copy; empty when the directory did not exist
+}
+
+func takeUpdateSnapshot(projectRoot string, cmd CommandDetail) (*updateSnapshot, error) {
+	backupDir, removeBackup, err := newTempDir(projectRoot, "update")
+	if err != nil {
+		return nil, err
+	}
+
+	s := &updateSnapshot{
+		removeBackup: removeBackup,
+		files:        make(map[string][]byte),
+		dirs:         make(map[string]string),
+	}
+
+	claudeDir := filepath.Join(projectRoot, ".claude")
+	files := []string{
+		configFilePath(projectRoot),
+		lockFilePath(projectRoot),
+	}
+	dirs := []string{}
+
+	if cmd.Type == "plugin" {
+		files = append(files, filepath.Join(claudeDir, "settings.json"))
+		dirs = append(dirs, filepath.Join(claudeDir, "plugins", cmd.Name))
+	} else {
+		files = append(files, claudeFile(projectRoot, cmd.Name))
+		for _, rel := range extraTargetFiles(projectRoot, cmd.Name) {
+			files = append(files, filepath.Join(projectRoot, rel))
+		}
+		dirs = append(dirs, installedCommandDir(projectRoot, cmd.Name))
+	}
+
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil && !os.IsNotExist(err) {
+			s.discard()
+			return nil, errors.FileError("back up file", path, err)
+		}
+		s.files[path] = data
+	}
+
+	for _, path := range dirs {
+		if !dirExists(path) {
+			s.dirs[path] = ""
+			continue
+		}
+		backup := filepath.Join(backupDir, filepath.Base(path))
+		if err := copyDirectory(context.Background(), path, backup); err != nil {
+			s.discard()
+			return nil, errors.FileError("back up directory", path, err)
+		}
+		s.dirs[path] = backup
+	}
+
+	return s, nil
+}
+
+// restore puts every snapshotted file and directory back
+func (s *updateSnapshot) restore() error {
+	for path, backup := range s.dirs {
+		if err := fs.RemoveAll(path); err != nil {
+			return errors.FileError("remove partial update", path, err)
+		}
+		if backup == "" {
+			continue
+		}
+		// A rollback must run to completion even when the update was cancelled
+		if err := copyDirectory(context.Background(), backup, path); err != nil {
+			return errors.FileError("restore directory", path, err)
+		}
+	}
+
+	for path, data := range s.files {
+		if data == nil {
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				return errors.FileError("remove file", path, err)
+			}
+			continue
+		}
+		if err := writeFileAtomic(path, data, 0644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// discard removes the backup copies
+func (s *updateSnapshot) discard() {
+	s.removeBackup()
+}
@@ -10,9 +10,16 @@
 package core
 
 import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gifflet/ccmd/pkg/output"
 )
 
 func TestIsCommitHashUpdate(t *testing.T) {
@@ -36,3 +43,90 @@ func TestIsCommitHashUpdate(t *testing.T) {
 		})
 	}
 }
+
+func TestChangelogExcerpt(t *testing.T) {
+	changelog := `# Changelog
+
+## [1.3.0] - 2025-03-01
+- Added reports
+
+## [1.2.0] - 2025-02-01
+- Faster reviews
+
+## v1.1.0
+- Initial public release
+`
+
+	excerpt := changelogExcerpt(changelog, "v1.1.0", "v1.3.0")
+	assert.Contains(t, excerpt, "Added reports")
+	assert.Contains(t, excerpt, "Faster reviews")
+	assert.NotContains(t, excerpt, "Initial public release")
+
+	assert.Equal(t, "## [1.2.0] - 2025-02-01\n- Faster reviews", changelogExcerpt(changelog, "1.1.0", "1.2.0"))
+	assert.Empty(t, changelogExcerpt(changelog, "main", "v1.3.0"))
+}
+
+func TestVersionChange(t *testing.T) {
+	assert.Equal(t, " (v1.0.0 → v1.2.0)", versionChange(&UpdatePlan{CurrentVersion: "v1.0.0", TargetVersion: "v1.2.0"}))
+	assert.Equal(t, "", versionChange(&UpdatePlan{CurrentVersion: "main", TargetVersion: "main"}))
+}
+
+func TestUpdateSnapshotRestore(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+
+	cwd, err := os.Getwd()
+	require.NoError(t, err)
+
+	writeConfig(t, []string{"owner/review@^1.0.0"})
+	createCommandStructure(t, "review")
+	require.NoError(t, os.WriteFile(filepath.Join(".claude", "commands", "review", "index.md"), []byte("v1"), 0644))
+	lockFile := createBasicLockFile()
+	lockFile.Commands["review"] = createTestLockCommand("review", "1.0.0", "https://github.com/owner/review.git")
+	writeLockFile(t, lockFile)
+
+	snapshot, err := takeUpdateSnapshot(cwd, CommandDetail{Name: "review"})
+	require.NoError(t, err)
+	defer snapshot.discard()
+
+	// Simulate an install that failed halfway through
+	require.NoError(t, os.RemoveAll(filepath.Join(".claude", "commands", "review")))
+	require.NoError(t, os.WriteFile(filepath.Join(".claude", "commands", "review.md"), []byte("partial"), 0644))
+	writeConfig(t, []string{"owner/review@v2.0.0"})
+	require.NoError(t, os.Remove(LockFileName))
+
+	require.NoError(t, snapshot.restore())
+
+	index, err := os.ReadFile(filepath.Join(".claude", "commands", "review", "index.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "v1", string(index))
+
+	standalone, err := os.ReadFile(filepath.Join(".claude", "commands", "review.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "# review", string(standalone))
+
+	cfg, err := LoadProjectConfig(cwd)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"owner/review@^1.0.0"}, cfg.Commands)
+	assert.Contains(t, readLockFile(t).Commands, "review")
+}
+
+func TestUpdateCheckAllTable(t *testing.T) {
+	repo, release := writeCommandRepo(t)
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	_, _, err := Install(ctx, InstallOptions{Repository: repo})
+	require.NoError(t, err)
+	release("v1.1.0")
+
+	var buf bytes.Buffer
+	defer output.SetOutput(&buf, &buf)()
+	result, err := Update(ctx, UpdateOptions{All: true, CheckOnly: true, Format: output.FormatCSV})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.CheckedCount)
+	assert.Equal(t, ""+
+		"NAME,CURRENT,TARGET,CHANNEL,STATUS,REASON\n"+
+		"tool,v1.0.0,v1.1.0,stable,UPDATE,newer version satisfies ^1.0.0\n", buf.String())
+}
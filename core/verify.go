@@ -0,0 +1,206 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// VerifyOptions represents options for verifying installed commands
+type VerifyOptions struct {
+	ProjectPath string
+}
+
+// VerifyIssue describes one problem found with an installed command or plugin
+type VerifyIssue struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"` // "command" or "plugin"
+	Problem string `json:"problem"`
+}
+
+// VerifyReport holds the result of verifying a project's installation
+type VerifyReport struct {
+	Checked int           `json:"checked"`
+	Issues  []VerifyIssue `json:"issues"`
+}
+
+// OK reports whether no issues were found
+func (r *VerifyReport) OK() bool {
+	return len(r.Issues) == 0
+}
+
+// Verify checks that every command and plugin recorded in the lock file is present on
+// disk and that the standalone command files are current. Installed files are never
+// modified; the result is recorded in the audit log.
+func Verify(opts VerifyOptions) (*VerifyReport, error) {
+	projectRoot, err := findProjectRootFrom(opts.ProjectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &VerifyReport{Issues: []VerifyIssue{}}
+
+	lockPath := lockFilePath(projectRoot)
+	if !fileExists(lockPath) {
+		return report, nil
+	}
+
+	lockFile, err := ReadLockFile(lockPath)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(lockFile.Commands))
+	for name := range lockFile.Commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	targets, err := projectTargets(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range names {
+		report.Checked++
+		cmd := lockFile.Commands[name]
+		var standalone map[string]*LockStandalone
+		if cmd != nil {
+			standalone = cmd.Standalone
+		}
+		problems := verifyCommand(projectRoot, name, targets, standalone)
+		if cmd != nil && !cmd.Local() {
+			commandDir := installedCommandDir(projectRoot, name)
+			problems = append(problems, verifyLockedCommit(commandDir, cmd.Source, cmd.Resolved, cmd.Commit)...)
+		}
+		for _, problem := range problems {
+			report.Issues = append(report.Issues, VerifyIssue{Name: name, Type: "command", Problem: problem})
+		}
+	}
+
+	plugins := make([]string, 0, len(lockFile.Plugins))
+	for name := range lockFile.Plugins {
+		plugins = append(plugins, name)
+	}
+	sort.Strings(plugins)
+
+	for _, name := range plugins {
+		report.Checked++
+		pluginDir := filepath.Join(projectRoot, ".claude", "plugins", name)
+		if !dirExists(pluginDir) {
+			report.Issues = append(report.Issues, VerifyIssue{Name: name, Type: "plugin", Problem: "plugin directory is missing"})
+			continue
+		}
+		plugin := lockFile.Plugins[name]
+		for _, problem := range verifyLockedCommit(pluginDir, plugin.Source, plugin.Resolved, plugin.Commit) {
+			report.Issues = append(report.Issues, VerifyIssue{Name: name, Type: "plugin", Problem: problem})
+		}
+	}
+
+	event := AuditEvent{
+		Action:  AuditVerify,
+		Details: fmt.Sprintf("%d checked, %d issue(s)", report.Checked, len(report.Issues)),
+	}
+	if !report.OK() {
+		event.Result = AuditFailure
+	}
+	recordAudit(projectRoot, event)
+
+	return report, nil
+}
+
+// verifyCommand returns the problems found with one installed command and its
+// standalone files. Files that differ from the checksum standalone records for them were
+// edited by hand; local overrides are not compared.
+func verifyCommand(projectRoot, name string, targets []Target, standalone map[string]*LockStandalone) []string {
+	commandDir := installedCommandDir(projectRoot, name)
+	if !dirExists(commandDir) {
+		return []string{"command directory is missing"}
+	}
+
+	var problems []string
+
+	metadataPath := filepath.Join(commandDir, "ccmd.yaml")
+	if !fileExists(metadataPath) {
+		problems = append(problems, "ccmd.yaml is missing")
+	}
+	if !fileExists(filepath.Join(commandDir, "index.md")) {
+		problems = append(problems, "index.md is missing")
+	}
+	if len(problems) > 0 {
+		return problems
+	}
+
+	metadata, err := readCommandMetadata(metadataPath)
+	if err != nil {
+		return []string{fmt.Sprintf("invalid ccmd.yaml: %v", err)}
+	}
+	if metadata.Name == "" {
+		metadata.Name = name
+	}
+
+	for _, target := range targets {
+		existing, err := os.ReadFile(target.file(projectRoot, name))
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s is missing", target.label(name)))
+			continue
+		}
+
+		record := standalone[target.key(name)]
+		if record != nil && record.Override {
+			continue
+		}
+		expected, err := target.render(commandDir, metadata)
+		switch {
+		case err != nil || bytes.Equal(existing, expected):
+		case record != nil && standaloneChecksum(existing) != record.Checksum:
+			problems = append(problems, fmt.Sprintf("%s was edited by hand (run ccmd regen %s to keep or discard the edits)",
+				target.label(name), name))
+		default:
+			problems = append(problems, fmt.Sprintf("%s is out of date (run ccmd regen)", target.label(name)))
+		}
+	}
+
+	return problems
+}
+
+// verifyLockedCommit checks the commit recorded for a git install: it must be a full SHA,
+// match the commit checked out in dir and be the commit the locked tag points to.
+// Archives and markdown files record checksums instead and are not checked.
+func verifyLockedCommit(dir, source, resolved, commit string) []string {
+	if isDownloadSource(source) || !dirExists(dir) {
+		return nil
+	}
+	if commit == "" || commit == "unknown" {
+		return []string{LockFileName + " records no commit"}
+	}
+	if !isFullCommitHash(commit) {
+		return []string{fmt.Sprintf("%s records commit %q instead of a full commit SHA", LockFileName, commit)}
+	}
+	if !dirExists(filepath.Join(dir, ".git")) {
+		return nil
+	}
+
+	if head, err := gitGetCurrentCommit(dir); err == nil && head != commit {
+		return []string{fmt.Sprintf("installed commit %s does not match %s commit %s", head, LockFileName, commit)}
+	}
+	_, ref := ParseRepositorySpec(resolved)
+	if ref == "" || isCommitHash(ref) {
+		return nil
+	}
+	if target, err := gitGetRefCommit(dir, "refs/tags/"+ref); err == nil && target != commit {
+		return []string{fmt.Sprintf("tag %s points to %s, %s records %s", ref, target, LockFileName, commit)}
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerify(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+
+	writeConfig(t, []string{})
+	setupRegenCommand(t, "alpha")
+	setupRegenCommand(t, "beta")
+	setupRegenCommand(t, "gamma")
+
+	cwd, err := os.Getwd()
+	require.NoError(t, err)
+
+	_, err = Regen(RegenOptions{ProjectPath: cwd})
+	require.NoError(t, err)
+
+	report, err := Verify(VerifyOptions{ProjectPath: cwd})
+	require.NoError(t, err)
+	assert.True(t, report.OK())
+	assert.Equal(t, 3, report.Checked)
+
+	require.NoError(t, os.WriteFile(filepath.Join(".claude", "commands", "beta", "index.md"), []byte("edited"), 0644))
+	require.NoError(t, os.RemoveAll(filepath.Join(".claude", "commands", "gamma")))
+
+	lockFile := readLockFile(t)
+	lockFile.Plugins = map[string]*LockPlugin{"tools": {Name: "tools", Version: "1.0.0"}}
+	writeLockFile(t, lockFile)
+
+	report, err = Verify(VerifyOptions{ProjectPath: cwd})
+	require.NoError(t, err)
+	assert.False(t, report.OK())
+	assert.Equal(t, 4, report.Checked)
+	assert.Equal(t, []VerifyIssue{
+		{Name: "beta", Type: "command", Problem: "beta.md is out of date (run ccmd regen)"},
+		{Name: "gamma", Type: "command", Problem: "command directory is missing"},
+		{Name: "tools", Type: "plugin", Problem: "plugin directory is missing"},
+	}, report.Issues)
+}
+
+func TestVerifyWithoutLockFile(t *testing.T) {
+	report, err := Verify(VerifyOptions{ProjectPath: t.TempDir()})
+	require.NoError(t, err)
+	assert.True(t, report.OK())
+	assert.Zero(t, report.Checked)
+}
+
+func TestVerifyLockedCommit(t *testing.T) {
+	repo, release := writeGitRepo(t)
+	tagged, err := gitGetCurrentCommit(repo)
+	require.NoError(t, err)
+	const source = "https://github.com/acme/tool.git"
+
+	assert.Empty(t, verifyLockedCommit(repo, source, source+"@v1.0.0", tagged))
+	assert.Equal(t, []string{"ccmd-lock.yaml records no commit"}, verifyLockedCommit(repo, source, source+"@v1.0.0", "unknown"))
+	assert.Equal(t, []string{`ccmd-lock.yaml records commit "abc1234" instead of a full commit SHA`},
+		verifyLockedCommit(repo, source, source+"@v1.0.0", "abc1234"))
+	assert.Empty(t, verifyLockedCommit(repo, "https://example.com/tool.tar.gz", "", "sha256:00"))
+
+	release("v1.1.0")
+	head, err := gitGetCurrentCommit(repo)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"installed commit " + head + " does not match ccmd-lock.yaml commit " + tagged},
+		verifyLockedCommit(repo, source, source+"@v1.0.0", tagged))
+	assert.Equal(t, []string{"tag v1.0.0 points to " + tagged + ", ccmd-lock.yaml records " + head},
+		verifyLockedCommit(repo, source, source+"@v1.0.0", head))
+}
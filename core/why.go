@@ -0,0 +1,203 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+// Reasons a command is installed, as reported by Why
+const (
+	WhyDirect     = "direct"     // listed in the project's ccmd.yaml
+	WhyDependency = "dependency" // listed in the ccmd.yaml of another installed command
+	WhyOrphaned   = "orphaned"   // installed but not referenced anywhere
+)
+
+// WhyOptions represents options for explaining why a command is installed
+type WhyOptions struct {
+	Name        string
+	ProjectPath string
+}
+
+// WhyReference is one place that asks for a command
+type WhyReference struct {
+	Kind       string `json:"kind"`                  // WhyDirect or WhyDependency
+	RequiredBy string `json:"required_by,omitempty"` // command declaring a dependency
+	Spec       string `json:"spec"`                  // entry as written in ccmd.yaml
+	Constraint string `json:"constraint,omitempty"`  // requested version or range
+}
+
+// WhyReport explains why a command or plugin is installed
+type WhyReport struct {
+	Name       string         `json:"name"`
+	Type       string         `json:"type"` // "command" or "plugin"
+	Repository string         `json:"repository"`
+	Status     string         `json:"status"` // WhyDirect, WhyDependency or WhyOrphaned
+	References []WhyReference `json:"references"`
+	Version    string         `json:"version,omitempty"`
+	Resolved   string         `json:"resolved,omitempty"`
+	Commit     string         `json:"commit,omitempty"`
+	// Chain is the resolved version chain: requested constraint, resolved ref, commit
+	Chain []string `json:"chain"`
+}
+
+// Why reports whether an installed command is a direct entry in ccmd.yaml, a dependency
+// declared by another installed command, or orphaned.
+func Why(opts WhyOptions) (*WhyReport, error) {
+	if opts.Name == "" {
+		return nil, errors.InvalidInput("command name is required")
+	}
+
+	projectRoot, err := findProjectRootFrom(opts.ProjectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	lockPath := lockFilePath(projectRoot)
+	if !fileExists(lockPath) {
+		return nil, errors.NotFound(fmt.Sprintf("command %q", opts.Name))
+	}
+
+	lockFile, err := ReadLockFile(lockPath)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &WhyReport{Name: opts.Name, References: []WhyReference{}}
+	isPlugin := false
+
+	if cmd, ok := lockFile.Commands[opts.Name]; ok {
+		report.Type = "command"
+		report.Repository = cmd.Source
+		report.Version = cmd.Version
+		report.Resolved = cmd.Resolved
+		report.Commit = cmd.Commit
+	} else if plugin, ok := lockFile.Plugins[opts.Name]; ok {
+		isPlugin = true
+		report.Type = "plugin"
+		report.Repository = plugin.Source
+		report.Version = plugin.Version
+		report.Resolved = plugin.Resolved
+		report.Commit = plugin.Commit
+	} else {
+		return nil, errors.NotFound(fmt.Sprintf("command %q", opts.Name))
+	}
+
+	repoPath := ExtractRepoPath(report.Repository)
+
+	if ProjectConfigExists(projectRoot) {
+		config, err := LoadProjectConfig(projectRoot)
+		if err != nil {
+			return nil, err
+		}
+
+		specs := config.Commands
+		if isPlugin {
+			specs = config.Plugins
+		}
+		if ref, ok := findReference(specs, repoPath); ok {
+			ref.Kind = WhyDirect
+			report.References = append(report.References, ref)
+		}
+	}
+
+	report.References = append(report.References, dependencyReferences(projectRoot, lockFile, opts.Name, repoPath)...)
+
+	switch {
+	case len(report.References) == 0:
+		report.Status = WhyOrphaned
+	case report.References[0].Kind == WhyDirect:
+		report.Status = WhyDirect
+	default:
+		report.Status = WhyDependency
+	}
+
+	report.Chain = versionChain(report)
+
+	return report, nil
+}
+
+// dependencyReferences finds installed commands and plugins whose ccmd.yaml lists repoPath
+func dependencyReferences(projectRoot string, lockFile *LockFile, name, repoPath string) []WhyReference {
+	var refs []WhyReference
+
+	check := func(dependent, dir string) {
+		metadata, err := readCommandMetadata(filepath.Join(dir, "ccmd.yaml"))
+		if err != nil {
+			return
+		}
+		for _, specs := range [][]string{metadata.Commands, metadata.Plugins} {
+			if ref, ok := findReference(specs, repoPath); ok {
+				ref.Kind = WhyDependency
+				ref.RequiredBy = dependent
+				refs = append(refs, ref)
+				return
+			}
+		}
+	}
+
+	for dependent := range lockFile.Commands {
+		if dependent != name {
+			check(dependent, installedCommandDir(projectRoot, dependent))
+		}
+	}
+	for dependent := range lockFile.Plugins {
+		if dependent != name {
+			check(dependent, filepath.Join(projectRoot, ".claude", "plugins", dependent))
+		}
+	}
+
+	sort.Slice(refs, func(i, j int) bool {
+		return refs[i].RequiredBy < refs[j].RequiredBy
+	})
+
+	return refs
+}
+
+// findReference returns the ccmd.yaml entry for a repository, matched by owner/repo path
+func findReference(specs []string, repoPath string) (WhyReference, bool) {
+	for _, spec := range specs {
+		repo, version := ParseCommandSpec(spec)
+		if ExtractRepoPath(repo) == repoPath {
+			return WhyReference{Spec: spec, Constraint: version}, true
+		}
+	}
+	return WhyReference{}, false
+}
+
+// versionChain lists how the installed version was reached: the requested constraint
+// (or "latest"), the resolved ref and the installed commit
+func versionChain(report *WhyReport) []string {
+	requested := "latest"
+	if len(report.References) > 0 && report.References[0].Constraint != "" {
+		requested = report.References[0].Constraint
+	}
+	chain := []string{requested}
+
+	if _, ref := ParseRepositorySpec(report.Resolved); ref != "" && ref != requested {
+		chain = append(chain, ref)
+	} else if report.Version != "" && report.Version != requested {
+		chain = append(chain, report.Version)
+	}
+
+	if report.Commit != "" && report.Commit != "unknown" {
+		commit := report.Commit
+		if isCommitHash(commit) && len(commit) > 12 {
+			commit = commit[:12]
+		}
+		chain = append(chain, commit)
+	}
+
+	return chain
+}
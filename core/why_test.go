@@ -0,0 +1,98 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+func setupWhyProject(t *testing.T) {
+	t.Helper()
+
+	writeConfig(t, []string{"acme/deploy@^1.2.0"})
+
+	lockFile := createBasicLockFile()
+	lockFile.Commands["deploy"] = createTestLockCommand("deploy", "1.4.0", "https://github.com/acme/deploy.git")
+	lockFile.Commands["deploy"].Resolved = "https://github.com/acme/deploy.git@v1.4.0"
+	lockFile.Commands["deploy"].Commit = "0123456789abcdef0123456789abcdef01234567"
+	lockFile.Commands["shell"] = createTestLockCommand("shell", "2.0.0", "https://github.com/acme/shell.git")
+	lockFile.Commands["stale"] = createTestLockCommand("stale", "0.1.0", "https://github.com/acme/stale.git")
+	writeLockFile(t, lockFile)
+
+	for _, name := range []string{"deploy", "shell", "stale"} {
+		createCommandStructure(t, name)
+	}
+
+	// deploy declares shell as a dependency in its own ccmd.yaml
+	metadata := &ProjectConfig{
+		Name:        "deploy",
+		Version:     "1.4.0",
+		Description: "Deploys the project",
+		Author:      "acme",
+		Repository:  "https://github.com/acme/deploy.git",
+		Entry:       "index.md",
+		Commands:    []string{"acme/shell@~2.0.0"},
+	}
+	require.NoError(t, writeCommandMetadata(filepath.Join(".claude", "commands", "deploy", "ccmd.yaml"), metadata))
+}
+
+func TestWhyDirect(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	setupWhyProject(t)
+
+	report, err := Why(WhyOptions{Name: "deploy"})
+	require.NoError(t, err)
+
+	assert.Equal(t, WhyDirect, report.Status)
+	require.Len(t, report.References, 1)
+	assert.Equal(t, "acme/deploy@^1.2.0", report.References[0].Spec)
+	assert.Equal(t, []string{"^1.2.0", "v1.4.0", "0123456789ab"}, report.Chain)
+}
+
+func TestWhyDependencyAndOrphaned(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	setupWhyProject(t)
+
+	report, err := Why(WhyOptions{Name: "shell"})
+	require.NoError(t, err)
+	assert.Equal(t, WhyDependency, report.Status)
+	require.Len(t, report.References, 1)
+	assert.Equal(t, "deploy", report.References[0].RequiredBy)
+	assert.Equal(t, "~2.0.0", report.References[0].Constraint)
+	assert.Equal(t, "~2.0.0", report.Chain[0])
+
+	report, err = Why(WhyOptions{Name: "stale"})
+	require.NoError(t, err)
+	assert.Equal(t, WhyOrphaned, report.Status)
+	assert.Empty(t, report.References)
+	assert.Equal(t, "latest", report.Chain[0])
+}
+
+func TestWhyNotInstalled(t *testing.T) {
+	cleanup := setupTestDir(t)
+	defer cleanup()
+	setupWhyProject(t)
+
+	_, err := Why(WhyOptions{Name: "missing"})
+	assert.ErrorIs(t, err, errors.ErrNotFound)
+
+	require.NoError(t, os.Remove(LockFileName))
+	_, err = Why(WhyOptions{Name: "deploy"})
+	assert.ErrorIs(t, err, errors.ErrNotFound)
+}
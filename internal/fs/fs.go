@@ -36,7 +36,7 @@ func safeReadFile(path string) ([]byte, error) {
 	if err := validateFilePath(path); err != nil {
 		return nil, err
 	}
-	return os.ReadFile(path) //nolint:gosec // Path is validated above
+	return os.ReadFile(LongPath(path)) //nolint:gosec // Path is validated above
 }
 
 // GetClaudeCommandsDir returns the path to .claude/commands/ directory
@@ -37,42 +37,42 @@ func (OS) ReadFile(name string) ([]byte, error) {
 
 // WriteFile writes data to the named file, creating it if necessary
 func (OS) WriteFile(name string, data []byte, perm os.FileMode) error {
-	return os.WriteFile(name, data, perm)
+	return os.WriteFile(LongPath(name), data, perm)
 }
 
 // Remove removes the named file or directory
 func (OS) Remove(name string) error {
-	return os.Remove(name)
+	return os.Remove(LongPath(name))
 }
 
 // RemoveAll removes path and any children it contains
 func (OS) RemoveAll(path string) error {
-	return os.RemoveAll(path)
+	return RemoveAll(path)
 }
 
 // Rename renames (moves) oldpath to newpath
 func (OS) Rename(oldpath, newpath string) error {
-	return os.Rename(oldpath, newpath)
+	return os.Rename(LongPath(oldpath), LongPath(newpath))
 }
 
 // Stat returns a FileInfo describing the named file
 func (OS) Stat(name string) (fs.FileInfo, error) {
-	return os.Stat(name)
+	return os.Stat(LongPath(name))
 }
 
 // MkdirAll creates a directory named path, along with any necessary parents
 func (OS) MkdirAll(path string, perm os.FileMode) error {
-	return os.MkdirAll(path, perm)
+	return os.MkdirAll(LongPath(path), perm)
 }
 
 // ReadDir reads the directory named by dirname and returns a list of directory entries
 func (OS) ReadDir(name string) ([]fs.DirEntry, error) {
-	return os.ReadDir(name)
+	return os.ReadDir(LongPath(name))
 }
 
 // Exists checks if a path exists
 func (OS) Exists(path string) (bool, error) {
-	_, err := os.Stat(path)
+	_, err := os.Stat(LongPath(path))
 	if err == nil {
 		return true, nil
 	}
@@ -0,0 +1,56 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package fs
+
+import "strings"
+
+// maxPath is the length from which Windows needs the extended-length prefix. It is
+// MAX_PATH (260) minus room for the 8.3 file name appended when creating directories.
+const maxPath = 248
+
+// extendedLengthPath returns the \\?\ form of an absolute Windows path so the file APIs
+// accept it beyond MAX_PATH. Short, relative and already prefixed paths are returned
+// unchanged. Paths use Windows syntax regardless of the platform running the code, so
+// the conversion can be tested everywhere.
+func extendedLengthPath(path string) string {
+	if len(path) < maxPath || strings.HasPrefix(path, `\\?\`) || strings.HasPrefix(path, `\\.\`) {
+		return path
+	}
+
+	path = strings.ReplaceAll(path, "/", `\`)
+
+	switch {
+	case strings.HasPrefix(path, `\\`):
+		// UNC path: \\server\share\dir becomes \\?\UNC\server\share\dir
+		return `\\?\UNC\` + cleanWindowsPath(path[2:])
+	case len(path) >= 3 && path[1] == ':' && path[2] == '\\':
+		return `\\?\` + path[:3] + cleanWindowsPath(path[3:])
+	default:
+		return path
+	}
+}
+
+// cleanWindowsPath resolves "." and ".." elements and doubled separators, which the
+// \\?\ prefix makes Windows take literally
+func cleanWindowsPath(path string) string {
+	var parts []string
+	for _, part := range strings.Split(path, `\`) {
+		switch part {
+		case "", ".":
+		case "..":
+			if len(parts) > 0 {
+				parts = parts[:len(parts)-1]
+			}
+		default:
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, `\`)
+}
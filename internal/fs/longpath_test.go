@@ -0,0 +1,41 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package fs
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExtendedLengthPath(t *testing.T) {
+	long := strings.Repeat(`nested\`, 40) + "index.md"
+
+	tests := []struct {
+		name string
+		path string
+		want string
+	}{
+		{"short path", `C:\project\.claude\commands\hello`, `C:\project\.claude\commands\hello`},
+		{"relative path", long, long},
+		{"drive path", `C:\project\` + long, `\\?\C:\project\` + long},
+		{"forward slashes", `C:/project/` + strings.ReplaceAll(long, `\`, "/"), `\\?\C:\project\` + long},
+		{"dot elements", `C:\project\.\tmp\..\` + long, `\\?\C:\project\` + long},
+		{"UNC path", `\\server\share\` + long, `\\?\UNC\server\share\` + long},
+		{"already prefixed", `\\?\C:\` + long, `\\?\C:\` + long},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := extendedLengthPath(tt.path); got != tt.want {
+				t.Errorf("extendedLengthPath() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
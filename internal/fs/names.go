@@ -0,0 +1,84 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package fs
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+// invalidNameChars cannot appear in file names on Windows; '/' and '\' are path separators
+const invalidNameChars = `<>:"/\|?*`
+
+// reservedNames are device names Windows refuses as file names, with or without an
+// extension, so "con.md" cannot be created either
+var reservedNames = map[string]bool{
+	"CON": true, "PRN": true, "AUX": true, "NUL": true,
+	"COM1": true, "COM2": true, "COM3": true, "COM4": true, "COM5": true,
+	"COM6": true, "COM7": true, "COM8": true, "COM9": true,
+	"LPT1": true, "LPT2": true, "LPT3": true, "LPT4": true, "LPT5": true,
+	"LPT6": true, "LPT7": true, "LPT8": true, "LPT9": true,
+}
+
+// IsReservedName reports whether name is a Windows device name such as CON or LPT1,
+// compared case-insensitively and ignoring any extension
+func IsReservedName(name string) bool {
+	base, _, _ := strings.Cut(name, ".")
+	return reservedNames[strings.ToUpper(strings.TrimRight(base, " "))]
+}
+
+// ValidateName checks that name can be used as a file or directory name on every
+// platform ccmd supports
+func ValidateName(name string) error {
+	switch {
+	case name == "":
+		return errors.InvalidInput("name cannot be empty")
+	case name == "." || name == "..":
+		return errors.InvalidInput(fmt.Sprintf("%q is not a valid name", name))
+	case strings.ContainsAny(name, invalidNameChars):
+		return errors.InvalidInput(fmt.Sprintf("name %q contains invalid characters (%s)", name, invalidNameChars))
+	case strings.IndexFunc(name, func(r rune) bool { return r < 0x20 }) != -1:
+		return errors.InvalidInput(fmt.Sprintf("name %q contains control characters", name))
+	case strings.HasSuffix(name, ".") || strings.HasSuffix(name, " "):
+		return errors.InvalidInput(fmt.Sprintf("name %q cannot end with a dot or space", name))
+	case IsReservedName(name):
+		return errors.InvalidInput(fmt.Sprintf("name %q is reserved on Windows", name))
+	}
+	return nil
+}
+
+// SanitizeName turns name into one that passes ValidateName: invalid and control
+// characters become '-', trailing dots and spaces are dropped and reserved device
+// names get a "-cmd" suffix. An empty result stays empty.
+func SanitizeName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || strings.ContainsRune(invalidNameChars, r) {
+			return '-'
+		}
+		return r
+	}, name)
+
+	name = strings.TrimRight(name, ". ")
+	if name == "" {
+		return ""
+	}
+
+	if IsReservedName(name) {
+		base, ext, found := strings.Cut(name, ".")
+		name = base + "-cmd"
+		if found {
+			name += "." + ext
+		}
+	}
+
+	return name
+}
@@ -0,0 +1,70 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package fs
+
+import "testing"
+
+func TestValidateName(t *testing.T) {
+	valid := []string{"deploy", "my-command", "v1.2", "console", "auxiliary", "com10"}
+	for _, name := range valid {
+		if err := ValidateName(name); err != nil {
+			t.Errorf("ValidateName(%q) error = %v", name, err)
+		}
+	}
+
+	invalid := []string{"", ".", "..", "a/b", `a\b`, "a:b", "what?", "tab\there", "trailing.", "trailing ",
+		"con", "CON", "aux.md", "Nul", "com1", "LPT9.txt"}
+	for _, name := range invalid {
+		if err := ValidateName(name); err == nil {
+			t.Errorf("ValidateName(%q) expected error", name)
+		}
+	}
+}
+
+func TestSanitizeName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"deploy", "deploy"},
+		{"con", "con-cmd"},
+		{"AUX.md", "AUX-cmd.md"},
+		{"a:b|c", "a-b-c"},
+		{"trailing. ", "trailing"},
+		{"...", ""},
+	}
+
+	for _, tt := range tests {
+		got := SanitizeName(tt.name)
+		if got != tt.want {
+			t.Errorf("SanitizeName(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+		if got != "" {
+			if err := ValidateName(got); err != nil {
+				t.Errorf("SanitizeName(%q) = %q is not valid: %v", tt.name, got, err)
+			}
+		}
+	}
+}
+
+func TestNormalizeNewlines(t *testing.T) {
+	tests := map[string]string{
+		"a\r\nb\r\n": "a\nb\n",
+		"a\rb":       "a\nb",
+		"a\nb":       "a\nb",
+		"":           "",
+	}
+
+	for input, want := range tests {
+		if got := string(NormalizeNewlines([]byte(input))); got != want {
+			t.Errorf("NormalizeNewlines(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+//go:build !windows
+
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package fs
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// LongPath returns path unchanged; only Windows limits path length to MAX_PATH
+func LongPath(path string) string {
+	return path
+}
+
+// writeOK is W_OK of access(2)
+const writeOK = 0x2
+
+// RemoveAll removes path and its children
+func RemoveAll(path string) error {
+	return os.RemoveAll(path)
+}
+
+// Writable reports whether path can be written by this process. It asks the kernel
+// instead of writing a probe file, so read-only mounts are detected without touching them.
+func Writable(path string) bool {
+	return syscall.Access(path, writeOK) == nil
+}
+
+// lockFile waits for an exclusive flock(2) lock on f
+func lockFile(f *os.File) error {
+	for {
+		err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX)
+		if !errors.Is(err, syscall.EINTR) {
+			return err
+		}
+	}
+}
+
+// unlockFile releases the lock taken by lockFile
+func unlockFile(f *os.File) error {
+	return syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+}
@@ -0,0 +1,64 @@
+//go:build windows
+
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package fs
+
+import (
+	"os"
+	"path/filepath"
+
+	"golang.org/x/sys/windows"
+)
+
+// LongPath returns path in a form Windows accepts beyond MAX_PATH. Deeply nested
+// command directories easily exceed it when copied from a clone in the temp directory.
+func LongPath(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+	return extendedLengthPath(abs)
+}
+
+// RemoveAll removes path and its children. Git marks pack files read-only, which
+// Windows refuses to delete, so read-only files are made writable and removal retried.
+func RemoveAll(path string) error {
+	path = LongPath(path)
+	if err := os.RemoveAll(path); err == nil {
+		return nil
+	}
+
+	_ = filepath.Walk(path, func(p string, info os.FileInfo, err error) error {
+		if err == nil && info.Mode().Perm()&0o200 == 0 {
+			_ = os.Chmod(p, info.Mode().Perm()|0o200)
+		}
+		return nil
+	})
+
+	return os.RemoveAll(path)
+}
+
+// Writable reports whether path can be written: Windows marks read-only files and
+// directories with an attribute rather than permission bits for each user
+func Writable(path string) bool {
+	info, err := os.Stat(LongPath(path))
+	return err == nil && info.Mode().Perm()&0o200 != 0
+}
+
+// lockFile waits for an exclusive LockFileEx lock on the first byte of f
+func lockFile(f *os.File) error {
+	return windows.LockFileEx(windows.Handle(f.Fd()), windows.LOCKFILE_EXCLUSIVE_LOCK, 0, 1, 0, &windows.Overlapped{})
+}
+
+// unlockFile releases the lock taken by lockFile
+func unlockFile(f *os.File) error {
+	return windows.UnlockFileEx(windows.Handle(f.Fd()), 0, 1, 0, &windows.Overlapped{})
+}
@@ -0,0 +1,56 @@
+//go:build windows
+
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package fs
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLongPathBeyondMaxPath(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), strings.Repeat(`nested\`, 40))
+	file := filepath.Join(dir, "index.md")
+
+	if err := os.MkdirAll(LongPath(dir), 0o755); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+	if err := os.WriteFile(LongPath(file), []byte("hello"), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	var fs OS
+	data, err := fs.ReadFile(file)
+	if err != nil || string(data) != "hello" {
+		t.Fatalf("ReadFile() = %q, %v", data, err)
+	}
+}
+
+func TestRemoveAllReadOnly(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "clone")
+	file := filepath.Join(dir, ".git", "objects", "pack", "pack-1.pack")
+
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+	if err := os.WriteFile(file, []byte("pack"), 0o444); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	if err := RemoveAll(dir); err != nil {
+		t.Fatalf("RemoveAll() error = %v", err)
+	}
+	if DirExists(dir) {
+		t.Errorf("RemoveAll() left %s behind", dir)
+	}
+}
@@ -0,0 +1,534 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+// Package ccmd is the Go API for managing Claude Code commands from other programs.
+//
+// A Client operates on one project, the directory holding ccmd.yaml:
+//
+//	client := ccmd.New("/path/to/project")
+//	cmd, err := client.Install(ctx, ccmd.InstallOptions{Repository: "github.com/user/repo"})
+//	if errors.Is(err, ccmd.ErrAlreadyExists) {
+//		// ...
+//	}
+//
+// Functions return typed results and errors and never print. The progress messages the
+// CLI would show are written to Client.Log when it is set and discarded otherwise.
+// Programs rendering their own progress set Client.Progress instead, which receives
+// typed ProgressEvent values for each step of the installs of Install, Sync and Update.
+//
+// Each call works on Client.Dir without changing the working directory, and calls can
+// run concurrently. The messages of calls running at the same time go to the Log of
+// each of them, since the CLI output is process-wide.
+package ccmd
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"slices"
+	"sync"
+
+	"github.com/gifflet/ccmd/core"
+	ccmderrors "github.com/gifflet/ccmd/pkg/errors"
+	"github.com/gifflet/ccmd/pkg/logger"
+	"github.com/gifflet/ccmd/pkg/output"
+)
+
+// Errors returned by the client wrap these sentinels; test them with errors.Is
+var (
+	ErrNotFound      = ccmderrors.ErrNotFound
+	ErrAlreadyExists = ccmderrors.ErrAlreadyExists
+	ErrConflict      = ccmderrors.ErrConflict
+	ErrInvalidInput  = ccmderrors.ErrInvalidInput
+	ErrGitOperation  = ccmderrors.ErrGitOperation
+	ErrFileOperation = ccmderrors.ErrFileOperation
+
+	// ErrAuthenticationRequired matches installs of repositories whose host asked for
+	// credentials; git never prompts for them
+	ErrAuthenticationRequired = ccmderrors.ErrAuthenticationRequired
+)
+
+// Save strategies for the version constraint recorded in ccmd.yaml
+const (
+	SaveExact = core.SaveExact
+	SaveCaret = core.SaveCaret
+	SaveTilde = core.SaveTilde
+)
+
+// ProgressStage identifies a step of an install
+type ProgressStage = core.ProgressStage
+
+// Install stages, in the order an install goes through them
+const (
+	StageCloneStarted  = core.StageCloneStarted
+	StageCloneProgress = core.StageCloneProgress
+	StageValidating    = core.StageValidating
+	StageCopying       = core.StageCopying
+	StageLockUpdated   = core.StageLockUpdated
+	StageDone          = core.StageDone
+	StageFailed        = core.StageFailed
+)
+
+// ProgressEvent is a step of an install reported to Client.Progress
+type ProgressEvent struct {
+	Stage      ProgressStage
+	Name       string // command or plugin name; empty until the source has been read
+	Repository string
+	Message    string
+	Percent    int // 0-100 for StageCloneProgress, -1 otherwise
+	Err        error
+}
+
+// Command describes an installed command or plugin
+type Command struct {
+	Name        string
+	Type        string // "command" or "plugin"
+	Version     string
+	Description string
+	Author      string
+	Repository  string
+	Resolved    string // repository@ref that was installed
+	Branch      string // branch the command tracks, when it was installed from one
+	Tags        []string
+	InstalledAt string
+	UpdatedAt   string
+
+	// Broken is set when the installed files are incomplete; Problem says why
+	Broken  bool
+	Problem string
+}
+
+// InstallOptions configures Client.Install
+type InstallOptions struct {
+	Repository   string // Git repository URL or shorthand such as github.com/user/repo
+	Version      string // Tag, branch, commit or version constraint; empty installs the newest release
+	Name         string // Override the command name
+	Rename       string // Name to use when the command name is taken by another repository
+	As           string // Install side by side with other versions of the repository under this name
+	Force        bool   // Reinstall if already installed
+	SaveStrategy string // SaveExact, SaveCaret or SaveTilde; empty uses the configured default
+	Profile      string // Record the command under this ccmd.yaml profile instead of the shared list
+	Pre          bool   // Let the newest version and constraints resolve to prerelease tags
+
+	// OverwriteLocal lets Force replace a command whose files were modified after install.
+	// Backup does the same but first copies the modified files to .claude/.backups.
+	OverwriteLocal bool
+	Backup         bool
+}
+
+// RemoveOptions configures Client.Remove
+type RemoveOptions struct {
+	Save bool // Also remove the command from ccmd.yaml
+}
+
+// SyncOptions configures Client.Sync
+type SyncOptions struct {
+	DryRun  bool   // Report what would change without changing anything
+	Prune   bool   // Move orphaned command files (not tracked by ccmd-lock.yaml) to the trash
+	Profile string // Also sync the commands of this ccmd.yaml profile
+	Refresh bool   // List remote tags again instead of using the tag cache
+	Frozen  bool   // Install what ccmd-lock.yaml records without writing it; fail if it is out of date
+}
+
+// SyncResult lists what Client.Sync installed and removed
+type SyncResult struct {
+	Installed []string // repositories installed from ccmd.yaml
+	Removed   []string // commands not declared in ccmd.yaml
+	Pruned    []string // orphaned commands moved to the trash, with SyncOptions.Prune
+	Failed    []Failure
+}
+
+// Failure is an operation that failed for one command during a batch operation
+type Failure struct {
+	Name      string
+	Operation string
+	Err       error
+}
+
+// UpdateOptions configures Client.Update
+type UpdateOptions struct {
+	Name         string // Command to update; empty updates every installed command
+	CheckOnly    bool   // Report available updates without installing them
+	Force        bool   // Reinstall even when the version appears current
+	SaveStrategy string // Rewrite the ccmd.yaml constraint; empty keeps it
+	Pre          bool   // Let every command update to prerelease tags, as the beta channel does
+
+	// OverwriteLocal replaces commands whose files were modified after install. Backup
+	// does the same but first copies the modified files to .claude/.backups.
+	OverwriteLocal bool
+	Backup         bool
+
+	// Confirm is called before each update is installed. Returning false skips it.
+	// A nil Confirm applies every update.
+	Confirm func(plan UpdatePlan) bool
+}
+
+// UpdatePlan describes a pending update of one command
+type UpdatePlan struct {
+	Name           string
+	Repository     string
+	Constraint     string
+	Channel        string
+	CurrentVersion string
+	TargetVersion  string
+	Branch         string // branch the command tracks, when it was installed from one
+	Reason         string
+	Changelog      string
+	// PromptDiff summarizes the changes to the command's entry file; nil when the
+	// versions could not be compared
+	PromptDiff *PromptDiff
+}
+
+// PromptDiff summarizes how the entry file of a command changes in an update
+type PromptDiff = core.PromptDiff
+
+// SectionChange is a markdown section of the entry file that an update changes
+type SectionChange = core.SectionChange
+
+// UpdateResult counts the outcome of Client.Update
+type UpdateResult struct {
+	Updated int
+	Failed  int
+	Checked int
+	Skipped int
+}
+
+// VerifyIssue is a problem found with an installed command or plugin
+type VerifyIssue struct {
+	Name    string
+	Type    string
+	Problem string
+}
+
+// VerifyReport is the result of Client.Verify
+type VerifyReport struct {
+	Checked int
+	Issues  []VerifyIssue
+}
+
+// OK reports whether verification found no issues
+func (r *VerifyReport) OK() bool {
+	return len(r.Issues) == 0
+}
+
+// Client manages the commands of one project
+type Client struct {
+	// Dir is the project directory; empty means the current working directory
+	Dir string
+	// Log receives progress messages; nil discards them
+	Log io.Writer
+	// Progress receives the steps of each install in place of the progress messages.
+	// A call reports its steps one at a time; calls running concurrently each report
+	// their own.
+	Progress func(ProgressEvent)
+}
+
+// New returns a client for the project in dir
+func New(dir string) *Client {
+	return &Client{Dir: dir}
+}
+
+// progress returns the core callback forwarding to c.Progress, or nil
+func (c *Client) progress() core.ProgressFunc {
+	if c.Progress == nil {
+		return nil
+	}
+	return func(event core.ProgressEvent) {
+		c.Progress(ProgressEvent(event))
+	}
+}
+
+// logs receives the CLI output while calls run and forwards it to their logs
+var logs = &logRouter{}
+
+// logRouter redirects the process-wide CLI output while any call runs, to the Log of
+// every running call, and restores it when the last one returns
+type logRouter struct {
+	mu      sync.Mutex
+	calls   int
+	targets []*logTarget
+	restore func()
+}
+
+// logTarget is a log receiving the output, with the number of running calls using it
+type logTarget struct {
+	w     io.Writer
+	calls int
+}
+
+// attach counts a running call and adds its log, when set. The returned function
+// detaches it.
+func (r *logRouter) attach(log io.Writer) func() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.calls == 0 {
+		restoreOutput := output.SetOutput(r, r)
+		restoreLogger := logger.SetOutput(r)
+		r.restore = func() {
+			restoreLogger()
+			restoreOutput()
+		}
+	}
+	r.calls++
+
+	var target *logTarget
+	if log != nil {
+		// Concurrent calls of one client write each message to its log once
+		i := slices.IndexFunc(r.targets, func(t *logTarget) bool { return sameWriter(t.w, log) })
+		if i < 0 {
+			r.targets = append(r.targets, &logTarget{w: log})
+			i = len(r.targets) - 1
+		}
+		target = r.targets[i]
+		target.calls++
+	}
+
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		if target != nil {
+			if target.calls--; target.calls == 0 {
+				r.targets = slices.DeleteFunc(r.targets, func(t *logTarget) bool { return t == target })
+			}
+		}
+		if r.calls--; r.calls == 0 {
+			r.restore()
+			r.restore = nil
+		}
+	}
+}
+
+// Write forwards p to the log of every running call; errors of a log are ignored so
+// they do not fail the call
+func (r *logRouter) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, target := range r.targets {
+		_, _ = target.w.Write(p)
+	}
+	return len(p), nil
+}
+
+// sameWriter reports whether two logs are the same writer. Writers whose type cannot
+// be compared are never the same.
+func sameWriter(a, b io.Writer) bool {
+	t := reflect.TypeOf(a)
+	return t == reflect.TypeOf(b) && t.Comparable() && a == b
+}
+
+// run executes fn with output redirected to the client log. The project is passed to
+// core as c.Dir.
+func (c *Client) run(ctx context.Context, fn func() error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	defer logs.attach(c.Log)()
+	return fn()
+}
+
+// Install installs a command or plugin and records it in ccmd.yaml and ccmd-lock.yaml
+func (c *Client) Install(ctx context.Context, opts InstallOptions) (*Command, error) {
+	var installed *Command
+	err := c.run(ctx, func() error {
+		name, _, err := core.Install(ctx, core.InstallOptions{
+			Repository:   opts.Repository,
+			Version:      opts.Version,
+			Name:         opts.Name,
+			Rename:       opts.Rename,
+			As:           opts.As,
+			Force:        opts.Force,
+			SaveStrategy: opts.SaveStrategy,
+			Profile:      opts.Profile,
+			Pre:          opts.Pre,
+			Progress:     c.progress(),
+			ProjectPath:  c.Dir,
+
+			OverwriteLocal: opts.OverwriteLocal,
+			Backup:         opts.Backup,
+		})
+		if err != nil {
+			return err
+		}
+
+		detail, err := core.GetCommandInfo(name, c.Dir)
+		if err != nil {
+			return err
+		}
+		installed = toCommand(*detail)
+		return nil
+	})
+	return installed, err
+}
+
+// Remove uninstalls a command or plugin. Removed files can be recovered with ccmd restore.
+func (c *Client) Remove(ctx context.Context, name string, opts RemoveOptions) error {
+	return c.run(ctx, func() error {
+		return core.Remove(core.RemoveOptions{
+			Name:        name,
+			Force:       true,
+			UpdateFiles: opts.Save,
+			ProjectPath: c.Dir,
+		})
+	})
+}
+
+// List returns the installed commands and plugins
+func (c *Client) List(ctx context.Context) ([]Command, error) {
+	var commands []Command
+	err := c.run(ctx, func() error {
+		details, err := core.List(core.ListOptions{ProjectPath: c.Dir})
+		if err != nil {
+			return err
+		}
+
+		commands = make([]Command, 0, len(details))
+		for _, detail := range details {
+			commands = append(commands, *toCommand(detail))
+		}
+		return nil
+	})
+	return commands, err
+}
+
+// Sync installs the commands declared in ccmd.yaml and removes the ones that are not
+func (c *Client) Sync(ctx context.Context, opts SyncOptions) (*SyncResult, error) {
+	result := &SyncResult{Installed: []string{}, Removed: []string{}, Pruned: []string{}, Failed: []Failure{}}
+	err := c.run(ctx, func() error {
+		if opts.Frozen {
+			if err := core.VerifyLock(c.Dir, opts.Profile); err != nil {
+				return err
+			}
+		}
+		if opts.DryRun {
+			analysis, err := core.AnalyzeSync(c.Dir, opts.Profile)
+			if err != nil {
+				return err
+			}
+			for _, cmd := range analysis.ToInstall {
+				result.Installed = append(result.Installed, cmd.Repo)
+			}
+			result.Removed = append(result.Removed, analysis.ToRemove...)
+
+			if opts.Prune {
+				orphans, err := core.FindOrphans(c.Dir)
+				if err != nil {
+					return err
+				}
+				for _, orphan := range orphans {
+					result.Pruned = append(result.Pruned, orphan.Name)
+				}
+			}
+			return nil
+		}
+
+		synced, err := core.Sync(ctx, core.SyncOptions{
+			Force:       true,
+			Prune:       opts.Prune,
+			Profile:     opts.Profile,
+			Refresh:     opts.Refresh,
+			Frozen:      opts.Frozen,
+			Progress:    c.progress(),
+			ProjectPath: c.Dir,
+		})
+		if err != nil {
+			return err
+		}
+		result.Installed = append(result.Installed, synced.Installed...)
+		result.Removed = append(result.Removed, synced.Removed...)
+		result.Pruned = append(result.Pruned, synced.Pruned...)
+		for _, failure := range synced.Failed {
+			result.Failed = append(result.Failed, Failure{
+				Name:      failure.Command,
+				Operation: failure.Operation,
+				Err:       failure.Error,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Update installs newer versions of one or all installed commands within their ccmd.yaml
+// constraints. A failed update is rolled back and counted in UpdateResult.Failed.
+func (c *Client) Update(ctx context.Context, opts UpdateOptions) (*UpdateResult, error) {
+	coreOpts := core.UpdateOptions{
+		Name:         opts.Name,
+		All:          opts.Name == "",
+		CheckOnly:    opts.CheckOnly,
+		Force:        opts.Force,
+		SaveStrategy: opts.SaveStrategy,
+		Pre:          opts.Pre,
+		Progress:     c.progress(),
+		ProjectPath:  c.Dir,
+
+		OverwriteLocal: opts.OverwriteLocal,
+		Backup:         opts.Backup,
+	}
+	if opts.Confirm != nil {
+		coreOpts.Confirm = func(plan *core.UpdatePlan) bool {
+			return opts.Confirm(UpdatePlan(*plan))
+		}
+	}
+
+	var result *UpdateResult
+	err := c.run(ctx, func() error {
+		updated, err := core.Update(ctx, coreOpts)
+		if err != nil {
+			return err
+		}
+		result = &UpdateResult{
+			Updated: updated.UpdatedCount,
+			Failed:  updated.FailedCount,
+			Checked: updated.CheckedCount,
+			Skipped: updated.SkippedCount,
+		}
+		return nil
+	})
+	return result, err
+}
+
+// Verify checks that the installed files match ccmd-lock.yaml without changing anything
+func (c *Client) Verify(ctx context.Context) (*VerifyReport, error) {
+	var report *VerifyReport
+	err := c.run(ctx, func() error {
+		verified, err := core.Verify(core.VerifyOptions{ProjectPath: c.Dir})
+		if err != nil {
+			return err
+		}
+
+		report = &VerifyReport{Checked: verified.Checked, Issues: make([]VerifyIssue, 0, len(verified.Issues))}
+		for _, issue := range verified.Issues {
+			report.Issues = append(report.Issues, VerifyIssue(issue))
+		}
+		return nil
+	})
+	return report, err
+}
+
+func toCommand(detail core.CommandDetail) *Command {
+	return &Command{
+		Name:        detail.Name,
+		Type:        detail.Type,
+		Version:     detail.Version,
+		Description: detail.Description,
+		Author:      detail.Author,
+		Repository:  detail.Repository,
+		Resolved:    detail.Resolved,
+		Branch:      detail.Branch,
+		Tags:        detail.Tags,
+		InstalledAt: detail.InstalledAt,
+		UpdatedAt:   detail.UpdatedAt,
+		Broken:      detail.BrokenStructure,
+		Problem:     detail.StructureError,
+	}
+}
@@ -0,0 +1,165 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package ccmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testLock = `version: "1.0"
+lockfileVersion: 1
+commands:
+  hello:
+    name: hello
+    version: 1.0.0
+    source: https://github.com/test/hello.git
+    resolved: https://github.com/test/hello.git@1.0.0
+    commit: 0123456789abcdef0123456789abcdef01234567
+    installed_at: 2025-01-01T00:00:00Z
+    updated_at: 2025-01-01T00:00:00Z
+`
+
+func setupProject(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	commandDir := filepath.Join(dir, ".claude", "commands", "hello")
+	require.NoError(t, os.MkdirAll(commandDir, 0o755))
+
+	files := map[string]string{
+		filepath.Join(dir, "ccmd.yaml"):      "commands:\n  - test/hello@1.0.0\n",
+		filepath.Join(dir, "ccmd-lock.yaml"): testLock,
+		filepath.Join(commandDir, "ccmd.yaml"): "name: hello\nversion: 1.0.0\ndescription: Says hello\n" +
+			"author: tester\nrepository: https://github.com/test/hello.git\nentry: index.md\n",
+		filepath.Join(commandDir, "index.md"):                 "Say hello",
+		filepath.Join(dir, ".claude", "commands", "hello.md"): "stale",
+	}
+	for path, content := range files {
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+
+	return dir
+}
+
+func TestClientList(t *testing.T) {
+	client := New(setupProject(t))
+
+	commands, err := client.List(context.Background())
+	require.NoError(t, err)
+	require.Len(t, commands, 1)
+	assert.Equal(t, "hello", commands[0].Name)
+	assert.Equal(t, "1.0.0", commands[0].Version)
+	assert.Equal(t, "Says hello", commands[0].Description)
+	assert.False(t, commands[0].Broken)
+}
+
+func TestClientVerify(t *testing.T) {
+	client := New(setupProject(t))
+
+	report, err := client.Verify(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Checked)
+	assert.False(t, report.OK())
+	require.Len(t, report.Issues, 1)
+	assert.Equal(t, "hello", report.Issues[0].Name)
+}
+
+func TestClientRemoveLogsInsteadOfPrinting(t *testing.T) {
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+
+	var log bytes.Buffer
+	client := &Client{Dir: setupProject(t), Log: &log}
+
+	require.NoError(t, client.Remove(context.Background(), "hello", RemoveOptions{Save: true}))
+	assert.Contains(t, log.String(), `Command "hello" removed successfully`)
+
+	commands, err := client.List(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, commands)
+
+	after, err := os.Getwd()
+	require.NoError(t, err)
+	assert.Equal(t, wd, after, "working directory must be restored")
+}
+
+func TestClientsRunConcurrently(t *testing.T) {
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+
+	const n = 4
+	clients := make([]*Client, n)
+	logs := make([]bytes.Buffer, n)
+	for i := range clients {
+		clients[i] = &Client{Dir: setupProject(t), Log: &logs[i]}
+	}
+
+	// Each call works on its own project without changing the working directory
+	var wg sync.WaitGroup
+	errs := make([]error, n)
+	for i, client := range clients {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = client.Remove(context.Background(), "hello", RemoveOptions{Save: true})
+		}()
+	}
+	wg.Wait()
+
+	for i, client := range clients {
+		require.NoError(t, errs[i])
+		assert.Contains(t, logs[i].String(), `Command "hello" removed successfully`)
+		assert.NoDirExists(t, filepath.Join(client.Dir, ".claude", "commands", "hello"))
+	}
+	after, err := os.Getwd()
+	require.NoError(t, err)
+	assert.Equal(t, wd, after)
+}
+
+func TestClientErrors(t *testing.T) {
+	client := New(setupProject(t))
+
+	err := client.Remove(context.Background(), "missing", RemoveOptions{})
+	assert.True(t, errors.Is(err, ErrNotFound))
+
+	_, err = client.Install(context.Background(), InstallOptions{})
+	assert.True(t, errors.Is(err, ErrInvalidInput))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = client.List(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestClientSyncDryRun(t *testing.T) {
+	dir := setupProject(t)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ccmd.yaml"), []byte("commands:\n  - test/other\n"), 0o644))
+
+	result, err := New(dir).Sync(context.Background(), SyncOptions{DryRun: true})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"test/other"}, result.Installed)
+	assert.Equal(t, []string{"hello"}, result.Removed)
+	assert.DirExists(t, filepath.Join(dir, ".claude", "commands", "hello"))
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".claude", "commands", "leftover"), 0o755))
+	result, err = New(dir).Sync(context.Background(), SyncOptions{DryRun: true, Prune: true})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"leftover"}, result.Pruned)
+	assert.DirExists(t, filepath.Join(dir, ".claude", "commands", "leftover"))
+}
@@ -0,0 +1,402 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+// Package config provides layered user and project configuration for CCMD.
+//
+// Settings are resolved in priority order (lowest first):
+//
+//  1. built-in defaults
+//  2. user config: $CCMD_CONFIG, or $XDG_CONFIG_HOME/ccmd/config.yaml (~/.config/ccmd/config.yaml)
+//  3. project config: .ccmdrc.yaml in the project root, except the keys UserOnly reports
+//  4. environment variables: CCMD_<KEY>, with dots replaced by underscores (e.g. CCMD_LOG_LEVEL)
+//
+// CCMD_CA_BUNDLE is accepted as an alias of CCMD_TLS_CA_FILE.
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+const (
+	// ConfigEnv overrides the user config file path
+	ConfigEnv = "CCMD_CONFIG"
+	// ProjectFileName is the project-level override file
+	ProjectFileName = ".ccmdrc.yaml"
+	// EnvPrefix is prepended to upper-cased keys to form environment variable names
+	EnvPrefix = "CCMD_"
+
+	// CABundleEnv is an alias for the tls.ca_file setting
+	CABundleEnv = "CCMD_CA_BUNDLE"
+
+	// ColorAuto enables colors when writing to a terminal
+	ColorAuto = "auto"
+	// ColorAlways forces colored output
+	ColorAlways = "always"
+	// ColorNever disables colored output
+	ColorNever = "never"
+
+	// LayoutFlat installs commands in .claude/commands/<name>
+	LayoutFlat = "flat"
+	// LayoutNested installs commands in .claude/commands/<owner>/<name>
+	LayoutNested = "nested"
+
+	// ScanWarn reports suspicious command content found at install time
+	ScanWarn = "warn"
+	// ScanBlock refuses installs with error findings
+	ScanBlock = "block"
+	// ScanOff disables the scan at install time
+	ScanOff = "off"
+
+	// LicenseWarn reports installs of commands whose license is not allowed
+	LicenseWarn = "warn"
+	// LicenseBlock refuses installs of commands whose license is not allowed
+	LicenseBlock = "block"
+	// LicenseOff disables the license check at install time
+	LicenseOff = "off"
+)
+
+// Settings holds every configurable value
+type Settings struct {
+	DefaultHost  string   `yaml:"default_host,omitempty"`
+	CacheDir     string   `yaml:"cache_dir,omitempty"`
+	Jobs         int      `yaml:"jobs,omitempty"`
+	Color        string   `yaml:"color,omitempty"`
+	Theme        string   `yaml:"theme,omitempty"`  // auto, unicode or ascii
+	Locale       string   `yaml:"locale,omitempty"` // e.g. pt_BR; empty follows LC_ALL, LC_MESSAGES and LANG
+	LogLevel     string   `yaml:"log_level,omitempty"`
+	Catalogs     []string `yaml:"catalogs,omitempty"`
+	Targets      []string `yaml:"targets,omitempty"` // type or type:path, overridden by ccmd.yaml targets
+	SaveStrategy string   `yaml:"save_strategy,omitempty"`
+	TagCacheTTL  int      `yaml:"tag_cache_ttl,omitempty"` // seconds; 0 disables the tag cache
+	SizeLimitMB  int      `yaml:"size_limit_mb,omitempty"` // warn above this install size; 0 disables
+	// DefaultVersion is what installs without a version pick: latest-release or default-branch
+	DefaultVersion string `yaml:"default_version,omitempty"`
+	// RetryAttempts is how often network operations are tried on transient failures; 1 disables retries
+	RetryAttempts int `yaml:"retry_attempts,omitempty"`
+	// StrictMetadata refuses to install commands whose metadata lacks a description, author,
+	// license, tags or semantic version, as 'ccmd install --strict' does
+	StrictMetadata bool `yaml:"strict_metadata,omitempty"`
+	// DocFrontmatter starts the standalone .md files of commands with the front matter
+	// Claude Code reads to describe slash commands
+	DocFrontmatter bool `yaml:"doc_frontmatter,omitempty"`
+	// AllowedHosts lists the hosts 'ccmd ci' accepts command sources from; empty allows any
+	AllowedHosts []string `yaml:"allowed_hosts,omitempty"`
+	// AllowedLicenses lists the license identifiers commands may have, such as MIT;
+	// empty allows any license
+	AllowedLicenses []string `yaml:"allowed_licenses,omitempty"`
+	// LicensePolicy is warn, block or off and applies AllowedLicenses to installs
+	LicensePolicy string `yaml:"license_policy,omitempty"`
+	// Taps maps tap names to catalog URLs, managed with 'ccmd tap'
+	Taps map[string]string `yaml:"taps,omitempty"`
+	// Mirrors maps source prefixes such as github.com/acme-org to internal mirrors
+	Mirrors map[string]string `yaml:"mirrors,omitempty"`
+
+	Proxy   ProxySettings   `yaml:"proxy,omitempty"`
+	TLS     TLSSettings     `yaml:"tls,omitempty"`
+	Log     LogSettings     `yaml:"log,omitempty"`
+	Layout  LayoutSettings  `yaml:"layout,omitempty"`
+	Scan    ScanSettings    `yaml:"scan,omitempty"`
+	Paths   PathSettings    `yaml:"paths,omitempty"`
+	Sandbox SandboxSettings `yaml:"sandbox,omitempty"`
+	Tmp     TmpSettings     `yaml:"tmp,omitempty"`
+
+	Telemetry TelemetrySettings `yaml:"telemetry,omitempty"`
+}
+
+// ProxySettings override the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables
+type ProxySettings struct {
+	HTTP    string `yaml:"http,omitempty"`
+	HTTPS   string `yaml:"https,omitempty"`
+	NoProxy string `yaml:"no_proxy,omitempty"`
+}
+
+// TLSSettings configure certificate verification for network operations
+type TLSSettings struct {
+	// CAFile is a PEM bundle trusted instead of the system certificates
+	CAFile string `yaml:"ca_file,omitempty"`
+}
+
+// LogSettings configure the persistent log file
+type LogSettings struct {
+	// File receives JSON log records of every invocation. Relative paths are relative to
+	// the config file setting them, and under cache_dir/logs when set by CCMD_LOG_FILE.
+	// Empty disables the log file.
+	File string `yaml:"file,omitempty"`
+	// MaxSizeMB is the size at which the file is rotated
+	MaxSizeMB int `yaml:"max_size_mb,omitempty"`
+	// MaxFiles is the number of rotated files kept next to the current one
+	MaxFiles int `yaml:"max_files,omitempty"`
+}
+
+// LayoutSettings configure where new commands are installed
+type LayoutSettings struct {
+	// Commands is flat or nested; nested keeps commands of different owners apart
+	Commands string `yaml:"commands,omitempty"`
+	// FileName names the standalone files of nested commands, with {owner} and {name}
+	// placeholders; empty for {owner}--{name}
+	FileName string `yaml:"file_name,omitempty"`
+}
+
+// ScanSettings configure the security scan of command content
+type ScanSettings struct {
+	// Policy is warn, block or off and applies to installs
+	Policy string `yaml:"policy,omitempty"`
+	// RulesFile is a YAML ruleset adding rules and changing the severity of built-in ones;
+	// relative paths are resolved against the project root
+	RulesFile string `yaml:"rules_file,omitempty"`
+}
+
+// SandboxSettings restrict the scripts run by 'ccmd exec'
+type SandboxSettings struct {
+	// Enabled runs every script with a temporary HOME, the limits below and, unless
+	// Network is set, without network access
+	Enabled bool `yaml:"enabled,omitempty"`
+	// Network keeps network access for sandboxed scripts
+	Network bool `yaml:"network,omitempty"`
+	// MaxMemoryMB caps the address space of a sandboxed script; 0 for no limit
+	MaxMemoryMB int `yaml:"max_memory_mb,omitempty"`
+	// MaxCPUSeconds caps the CPU time of a sandboxed script; 0 for no limit
+	MaxCPUSeconds int `yaml:"max_cpu_seconds,omitempty"`
+	// MaxOpenFiles caps the open files of a sandboxed script; 0 for no limit
+	MaxOpenFiles int `yaml:"max_open_files,omitempty"`
+}
+
+// TmpSettings bound the temporary directories ccmd keeps under cache_dir/tmp. Directories
+// left behind by crashed runs are removed when ccmd starts.
+type TmpSettings struct {
+	// MaxAgeHours is the age after which a temporary directory is removed; 0 keeps them
+	MaxAgeHours int `yaml:"max_age_hours,omitempty"`
+	// MaxSizeMB is the total size above which the oldest directories are removed; 0 for no limit
+	MaxSizeMB int `yaml:"max_size_mb,omitempty"`
+}
+
+// TelemetrySettings configure the opt-in export of traces and metrics to an
+// OpenTelemetry collector
+type TelemetrySettings struct {
+	// Enabled turns the export on; it is off by default
+	Enabled bool `yaml:"enabled,omitempty"`
+	// Endpoint is the OTLP/HTTP base URL; empty uses OTEL_EXPORTER_OTLP_ENDPOINT or
+	// http://localhost:4318
+	Endpoint string `yaml:"endpoint,omitempty"`
+}
+
+// PathSettings relocate the files ccmd keeps in a project. Paths are relative to the
+// project root and stay inside it.
+type PathSettings struct {
+	// Config is the project's ccmd.yaml
+	Config string `yaml:"config,omitempty"`
+	// Lock is ccmd-lock.yaml; empty keeps it next to Config
+	Lock string `yaml:"lock,omitempty"`
+	// Commands is the directory holding the installed command directories
+	Commands string `yaml:"commands,omitempty"`
+}
+
+// Layer identifies where a setting came from
+type Layer string
+
+// Configuration layers in priority order
+const (
+	LayerDefault Layer = "default"
+	LayerUser    Layer = "user"
+	LayerProject Layer = "project"
+	LayerEnv     Layer = "env"
+)
+
+// Defaults returns the built-in settings
+func Defaults() *Settings {
+	cacheDir := filepath.Join(os.TempDir(), "ccmd-cache")
+	if dir, err := os.UserCacheDir(); err == nil {
+		cacheDir = filepath.Join(dir, "ccmd")
+	}
+
+	return &Settings{
+		DefaultHost:    "github.com",
+		CacheDir:       cacheDir,
+		Jobs:           runtime.NumCPU(),
+		Color:          ColorAuto,
+		Theme:          "auto",
+		LogLevel:       "info",
+		SaveStrategy:   "caret",
+		DefaultVersion: "latest-release",
+		TagCacheTTL:    600,
+		SizeLimitMB:    50,
+		RetryAttempts:  3,
+		LicensePolicy:  LicenseWarn,
+		Log:            LogSettings{MaxSizeMB: 10, MaxFiles: 3},
+		Layout:         LayoutSettings{Commands: LayoutFlat},
+		Scan:           ScanSettings{Policy: ScanWarn},
+		Paths:          PathSettings{Config: "ccmd.yaml", Commands: ".claude/commands"},
+		Sandbox:        SandboxSettings{MaxCPUSeconds: 300, MaxOpenFiles: 1024},
+		Tmp:            TmpSettings{MaxAgeHours: 24, MaxSizeMB: 1024},
+	}
+}
+
+// UserConfigPath returns the path of the user-level config file
+func UserConfigPath() (string, error) {
+	if path := os.Getenv(ConfigEnv); path != "" {
+		return path, nil
+	}
+
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "ccmd", "config.yaml"), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", errors.FileError("get home directory", "", err)
+	}
+
+	return filepath.Join(home, ".config", "ccmd", "config.yaml"), nil
+}
+
+// ProjectConfigPath returns the path of the project-level override file
+func ProjectConfigPath(projectRoot string) string {
+	return filepath.Join(projectRoot, ProjectFileName)
+}
+
+// Load resolves settings for a project. An empty projectRoot skips the project layer.
+func Load(projectRoot string) (*Settings, error) {
+	settings, _, err := LoadWithSources(projectRoot)
+	return settings, err
+}
+
+// LoadWithSources resolves settings and reports which layer supplied each key
+func LoadWithSources(projectRoot string) (*Settings, map[string]Layer, error) {
+	settings := Defaults()
+	sources := make(map[string]Layer)
+	for _, key := range Keys() {
+		sources[key] = LayerDefault
+	}
+
+	userPath, err := UserConfigPath()
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := applyFile(settings, sources, userPath, LayerUser); err != nil {
+		return nil, nil, err
+	}
+
+	if projectRoot != "" {
+		if err := applyFile(settings, sources, ProjectConfigPath(projectRoot), LayerProject); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	for _, key := range Keys() {
+		if value, ok := os.LookupEnv(EnvName(key)); ok && value != "" {
+			if err := setField(settings, key, value); err != nil {
+				return nil, nil, errors.InvalidInput(EnvName(key) + ": " + err.Error())
+			}
+			sources[key] = LayerEnv
+		}
+	}
+
+	if value := os.Getenv(CABundleEnv); value != "" && sources["tls.ca_file"] != LayerEnv {
+		settings.TLS.CAFile = value
+		sources["tls.ca_file"] = LayerEnv
+	}
+
+	if _, ok := os.LookupEnv("NO_COLOR"); ok && sources["color"] != LayerEnv {
+		settings.Color = ColorNever
+		sources["color"] = LayerEnv
+	}
+
+	return settings, sources, nil
+}
+
+// applyFile overlays the values present in a config file onto settings. The project
+// layer skips user-only keys, and relative paths are resolved against the file's directory.
+func applyFile(settings *Settings, sources map[string]Layer, path string, layer Layer) error {
+	values, err := ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	for key, value := range flatten("", values) {
+		if layer == LayerProject && UserOnly(key) {
+			continue
+		}
+		if s, ok := value.(string); ok && s != "" && slices.Contains(pathKeys, key) && !filepath.IsAbs(s) {
+			value = filepath.Join(filepath.Dir(path), s)
+		}
+		if err := setField(settings, key, value); err != nil {
+			return errors.InvalidInput(path + ": " + err.Error())
+		}
+		sources[key] = layer
+	}
+
+	return nil
+}
+
+// IgnoredProjectKeys returns the user-only keys a project's .ccmdrc.yaml sets, which
+// Load ignores
+func IgnoredProjectKeys(projectRoot string) ([]string, error) {
+	values, err := ReadFile(ProjectConfigPath(projectRoot))
+	if err != nil {
+		return nil, err
+	}
+
+	var ignored []string
+	for key := range flatten("", values) {
+		if UserOnly(key) {
+			ignored = append(ignored, key)
+		}
+	}
+	sort.Strings(ignored)
+	return ignored, nil
+}
+
+// ReadFile reads a config file into a raw map. A missing file yields an empty map.
+func ReadFile(path string) (map[string]interface{}, error) {
+	values := make(map[string]interface{})
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return values, nil
+		}
+		return nil, errors.FileError("read config", path, err)
+	}
+
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return nil, errors.FileError("parse config", path, err)
+	}
+
+	if values == nil {
+		values = make(map[string]interface{})
+	}
+
+	return values, nil
+}
+
+// WriteFile writes a raw config map, creating parent directories as needed
+func WriteFile(path string, values map[string]interface{}) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return errors.FileError("create config directory", filepath.Dir(path), err)
+	}
+
+	data, err := yaml.Marshal(values)
+	if err != nil {
+		return errors.FileError("marshal config", path, err)
+	}
+
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return errors.FileError("write config", path, err)
+	}
+
+	return nil
+}
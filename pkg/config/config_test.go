@@ -0,0 +1,212 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// isolate points the user config at a temp file and clears overriding env vars
+func isolate(t *testing.T) string {
+	t.Helper()
+
+	userPath := filepath.Join(t.TempDir(), "config.yaml")
+	t.Setenv(ConfigEnv, userPath)
+	for _, key := range Keys() {
+		t.Setenv(EnvName(key), "")
+	}
+	t.Setenv(CABundleEnv, "")
+	if value, ok := os.LookupEnv("NO_COLOR"); ok {
+		require.NoError(t, os.Unsetenv("NO_COLOR"))
+		t.Cleanup(func() { os.Setenv("NO_COLOR", value) })
+	}
+
+	return userPath
+}
+
+func TestKeys(t *testing.T) {
+	assert.Equal(t, []string{
+		"allowed_hosts", "allowed_licenses", "cache_dir", "catalogs", "color", "default_host", "default_version", "doc_frontmatter", "jobs",
+		"layout.commands", "layout.file_name", "license_policy", "locale", "log.file", "log.max_files", "log.max_size_mb", "log_level", "mirrors",
+		"paths.commands", "paths.config", "paths.lock", "proxy.http", "proxy.https", "proxy.no_proxy", "retry_attempts",
+		"sandbox.enabled", "sandbox.max_cpu_seconds", "sandbox.max_memory_mb", "sandbox.max_open_files", "sandbox.network",
+		"save_strategy", "scan.policy", "scan.rules_file",
+		"size_limit_mb", "strict_metadata", "tag_cache_ttl",
+		"taps", "targets", "telemetry.enabled", "telemetry.endpoint", "theme", "tls.ca_file", "tmp.max_age_hours", "tmp.max_size_mb",
+	}, Keys())
+	assert.Equal(t, "CCMD_TLS_CA_FILE", EnvName("tls.ca_file"))
+	assert.Equal(t, "CCMD_LOG_LEVEL", EnvName("log_level"))
+	assert.Equal(t, "CCMD_LOG_FILE", EnvName("log.file"))
+	assert.True(t, IsKey("jobs"))
+	assert.False(t, IsKey("unknown"))
+}
+
+func TestLoadDefaults(t *testing.T) {
+	isolate(t)
+
+	settings, sources, err := LoadWithSources("")
+	require.NoError(t, err)
+
+	assert.Equal(t, "github.com", settings.DefaultHost)
+	assert.Equal(t, ColorAuto, settings.Color)
+	assert.Equal(t, "info", settings.LogLevel)
+	assert.Positive(t, settings.Jobs)
+	assert.Equal(t, LayerDefault, sources["jobs"])
+}
+
+func TestLoadLayering(t *testing.T) {
+	userPath := isolate(t)
+	projectRoot := t.TempDir()
+
+	require.NoError(t, WriteFile(userPath, map[string]interface{}{
+		"jobs":         2,
+		"color":        "never",
+		"default_host": "gitlab.com",
+	}))
+	require.NoError(t, WriteFile(ProjectConfigPath(projectRoot), map[string]interface{}{
+		"jobs": 8,
+	}))
+	t.Setenv("CCMD_COLOR", "always")
+
+	settings, sources, err := LoadWithSources(projectRoot)
+	require.NoError(t, err)
+
+	assert.Equal(t, 8, settings.Jobs)
+	assert.Equal(t, LayerProject, sources["jobs"])
+	assert.Equal(t, "gitlab.com", settings.DefaultHost)
+	assert.Equal(t, LayerUser, sources["default_host"])
+	assert.Equal(t, ColorAlways, settings.Color)
+	assert.Equal(t, LayerEnv, sources["color"])
+}
+
+func TestLoadIgnoresUserOnlyProjectKeys(t *testing.T) {
+	userPath := isolate(t)
+	projectRoot := t.TempDir()
+
+	require.NoError(t, WriteFile(userPath, map[string]interface{}{"cache_dir": "cache"}))
+	require.NoError(t, WriteFile(ProjectConfigPath(projectRoot), map[string]interface{}{
+		"jobs":      8,
+		"cache_dir": "..",
+		"proxy":     map[string]interface{}{"https": "http://attacker.example:3128"},
+		"tls":       map[string]interface{}{"ca_file": "evil.pem"},
+		"telemetry": map[string]interface{}{"enabled": true, "endpoint": "https://attacker.example"},
+		"log":       map[string]interface{}{"file": "../log", "max_files": 5},
+		"mirrors":   map[string]interface{}{"github.com": "attacker.example"},
+		"taps":      map[string]interface{}{"evil": "https://attacker.example/catalog.yaml"},
+		"tmp":       map[string]interface{}{"max_age_hours": 0},
+	}))
+
+	settings, sources, err := LoadWithSources(projectRoot)
+	require.NoError(t, err)
+	assert.Equal(t, 8, settings.Jobs)
+	assert.Equal(t, 5, settings.Log.MaxFiles)
+	// A relative path is relative to the file that set it
+	assert.Equal(t, filepath.Join(filepath.Dir(userPath), "cache"), settings.CacheDir)
+	assert.Equal(t, LayerUser, sources["cache_dir"])
+	assert.Empty(t, settings.Proxy.HTTPS)
+	assert.Empty(t, settings.TLS.CAFile)
+	assert.False(t, settings.Telemetry.Enabled)
+	assert.Empty(t, settings.Telemetry.Endpoint)
+	assert.Empty(t, settings.Log.File)
+	assert.Empty(t, settings.Mirrors)
+	assert.Empty(t, settings.Taps)
+	assert.Equal(t, 24, settings.Tmp.MaxAgeHours)
+
+	ignored, err := IgnoredProjectKeys(projectRoot)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cache_dir", "log.file", "mirrors", "proxy.https", "taps", "telemetry.enabled",
+		"telemetry.endpoint", "tls.ca_file", "tmp.max_age_hours"}, ignored)
+
+	// The environment may still set them
+	t.Setenv("CCMD_PROXY_HTTPS", "http://proxy.corp:3128")
+	settings, err = Load(projectRoot)
+	require.NoError(t, err)
+	assert.Equal(t, "http://proxy.corp:3128", settings.Proxy.HTTPS)
+}
+
+func TestUserOnly(t *testing.T) {
+	assert.True(t, UserOnly("cache_dir"))
+	assert.True(t, UserOnly("proxy.https"))
+	assert.True(t, UserOnly("log.file"))
+	assert.False(t, UserOnly("log.max_files"))
+	assert.False(t, UserOnly("jobs"))
+	assert.False(t, UserOnly("tmpdir"))
+}
+
+func TestLoadNoColor(t *testing.T) {
+	isolate(t)
+	t.Setenv("NO_COLOR", "1")
+
+	settings, err := Load("")
+	require.NoError(t, err)
+	assert.Equal(t, ColorNever, settings.Color)
+}
+
+func TestLoadCABundleAlias(t *testing.T) {
+	userPath := isolate(t)
+
+	require.NoError(t, WriteFile(userPath, map[string]interface{}{
+		"tls":   map[string]interface{}{"ca_file": "/etc/ssl/user.pem"},
+		"proxy": map[string]interface{}{"https": "http://proxy.corp:3128"},
+	}))
+
+	settings, sources, err := LoadWithSources("")
+	require.NoError(t, err)
+	assert.Equal(t, "/etc/ssl/user.pem", settings.TLS.CAFile)
+	assert.Equal(t, "http://proxy.corp:3128", settings.Proxy.HTTPS)
+	assert.Equal(t, LayerUser, sources["tls.ca_file"])
+
+	t.Setenv(CABundleEnv, "/etc/ssl/corp.pem")
+	settings, sources, err = LoadWithSources("")
+	require.NoError(t, err)
+	assert.Equal(t, "/etc/ssl/corp.pem", settings.TLS.CAFile)
+	assert.Equal(t, LayerEnv, sources["tls.ca_file"])
+
+	t.Setenv("CCMD_TLS_CA_FILE", "/etc/ssl/explicit.pem")
+	settings, err = Load("")
+	require.NoError(t, err)
+	assert.Equal(t, "/etc/ssl/explicit.pem", settings.TLS.CAFile)
+}
+
+func TestLoadInvalidValues(t *testing.T) {
+	userPath := isolate(t)
+
+	require.NoError(t, WriteFile(userPath, map[string]interface{}{"jobs": "lots"}))
+	_, err := Load("")
+	assert.Error(t, err)
+
+	require.NoError(t, WriteFile(userPath, map[string]interface{}{"future_key": true}))
+	_, err = Load("")
+	assert.NoError(t, err, "unknown keys are ignored for forward compatibility")
+}
+
+func TestGetSetUnset(t *testing.T) {
+	values := map[string]interface{}{}
+
+	require.NoError(t, SetValue(values, "jobs", "4"))
+	assert.Equal(t, 4, values["jobs"])
+
+	assert.Error(t, SetValue(values, "jobs", "four"))
+	assert.Error(t, SetValue(values, "unknown", "x"))
+
+	settings := Defaults()
+	require.NoError(t, setField(settings, "jobs", values["jobs"]))
+	value, err := Get(settings, "jobs")
+	require.NoError(t, err)
+	assert.Equal(t, "4", value)
+
+	assert.True(t, UnsetValue(values, "jobs"))
+	assert.False(t, UnsetValue(values, "jobs"))
+}
@@ -0,0 +1,296 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+// userOnlyKeys are the keys and sections only the user config and the environment may
+// set. They decide where ccmd sends credentials and network traffic, what it trusts,
+// runs and deletes, so a .ccmdrc.yaml committed to a cloned repository must not.
+var userOnlyKeys = []string{"cache_dir", "log.file", "mirrors", "proxy", "taps", "telemetry", "tls", "tmp"}
+
+// pathKeys hold file paths; relative values in a config file are relative to that file
+var pathKeys = []string{"cache_dir", "log.file"}
+
+// UserOnly reports whether key may only be set in the user config or the environment,
+// not in a project's .ccmdrc.yaml
+func UserOnly(key string) bool {
+	for _, prefix := range userOnlyKeys {
+		if key == prefix || strings.HasPrefix(key, prefix+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// Keys returns every settable key in dotted form, sorted
+func Keys() []string {
+	var keys []string
+	collectKeys("", reflect.TypeOf(Settings{}), &keys)
+	sort.Strings(keys)
+	return keys
+}
+
+// IsKey reports whether key names a setting
+func IsKey(key string) bool {
+	_, err := lookupField(reflect.ValueOf(&Settings{}).Elem(), key)
+	return err == nil
+}
+
+// EnvName returns the environment variable that overrides key
+func EnvName(key string) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// Get returns the string form of a setting
+func Get(settings *Settings, key string) (string, error) {
+	field, err := lookupField(reflect.ValueOf(settings).Elem(), key)
+	if err != nil {
+		return "", err
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		return field.String(), nil
+	case reflect.Int:
+		return strconv.FormatInt(field.Int(), 10), nil
+	case reflect.Bool:
+		return strconv.FormatBool(field.Bool()), nil
+	case reflect.Slice:
+		items := make([]string, field.Len())
+		for i := range items {
+			items[i] = field.Index(i).String()
+		}
+		return strings.Join(items, ","), nil
+	case reflect.Map:
+		keys := make([]string, 0, field.Len())
+		for _, k := range field.MapKeys() {
+			keys = append(keys, fmt.Sprintf("%s=%v", k.String(), field.MapIndex(k).Interface()))
+		}
+		sort.Strings(keys)
+		return strings.Join(keys, ","), nil
+	default:
+		return fmt.Sprintf("%v", field.Interface()), nil
+	}
+}
+
+// SetValue validates raw for key and stores it in a raw config map
+func SetValue(values map[string]interface{}, key, raw string) error {
+	probe := &Settings{}
+	if err := setField(probe, key, raw); err != nil {
+		return err
+	}
+
+	field, _ := lookupField(reflect.ValueOf(probe).Elem(), key)
+
+	parts := strings.Split(key, ".")
+	current := values
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := current[part].(map[string]interface{})
+		if !ok {
+			next = make(map[string]interface{})
+			current[part] = next
+		}
+		current = next
+	}
+	current[parts[len(parts)-1]] = field.Interface()
+
+	return nil
+}
+
+// UnsetValue removes key from a raw config map. It reports whether anything was removed.
+func UnsetValue(values map[string]interface{}, key string) bool {
+	parts := strings.Split(key, ".")
+	current := values
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := current[part].(map[string]interface{})
+		if !ok {
+			return false
+		}
+		current = next
+	}
+
+	last := parts[len(parts)-1]
+	if _, ok := current[last]; !ok {
+		return false
+	}
+	delete(current, last)
+	return true
+}
+
+func collectKeys(prefix string, t reflect.Type, keys *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := yamlName(f)
+		if name == "" {
+			continue
+		}
+		if prefix != "" {
+			name = prefix + "." + name
+		}
+		if f.Type.Kind() == reflect.Struct {
+			collectKeys(name, f.Type, keys)
+			continue
+		}
+		*keys = append(*keys, name)
+	}
+}
+
+func yamlName(f reflect.StructField) string {
+	tag := f.Tag.Get("yaml")
+	if tag == "-" || !f.IsExported() {
+		return ""
+	}
+	name, _, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = strings.ToLower(f.Name)
+	}
+	return name
+}
+
+func lookupField(v reflect.Value, key string) (reflect.Value, error) {
+	for _, part := range strings.Split(key, ".") {
+		if v.Kind() != reflect.Struct {
+			return reflect.Value{}, errors.InvalidInput(fmt.Sprintf("unknown config key %q", key))
+		}
+		found := false
+		for i := 0; i < v.NumField(); i++ {
+			if yamlName(v.Type().Field(i)) == part {
+				v = v.Field(i)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return reflect.Value{}, errors.InvalidInput(fmt.Sprintf("unknown config key %q", key))
+		}
+	}
+
+	if v.Kind() == reflect.Struct {
+		return reflect.Value{}, errors.InvalidInput(fmt.Sprintf("%q is a section, not a key", key))
+	}
+
+	return v, nil
+}
+
+// setField assigns a value from YAML or a string to the setting named by key
+func setField(settings *Settings, key string, value interface{}) error {
+	field, err := lookupField(reflect.ValueOf(settings).Elem(), key)
+	if err != nil {
+		return err
+	}
+
+	invalid := func() error {
+		return errors.InvalidInput(fmt.Sprintf("invalid value %v for %q (expected %s)", value, key, field.Kind()))
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(fmt.Sprintf("%v", value))
+	case reflect.Int:
+		switch v := value.(type) {
+		case int:
+			field.SetInt(int64(v))
+		case string:
+			n, err := strconv.Atoi(strings.TrimSpace(v))
+			if err != nil {
+				return invalid()
+			}
+			field.SetInt(int64(n))
+		default:
+			return invalid()
+		}
+	case reflect.Bool:
+		switch v := value.(type) {
+		case bool:
+			field.SetBool(v)
+		case string:
+			b, err := strconv.ParseBool(strings.TrimSpace(v))
+			if err != nil {
+				return invalid()
+			}
+			field.SetBool(b)
+		default:
+			return invalid()
+		}
+	case reflect.Slice:
+		var items []string
+		switch v := value.(type) {
+		case string:
+			for _, item := range strings.Split(v, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					items = append(items, item)
+				}
+			}
+		case []interface{}:
+			for _, item := range v {
+				items = append(items, fmt.Sprintf("%v", item))
+			}
+		default:
+			return invalid()
+		}
+		field.Set(reflect.ValueOf(items))
+	case reflect.Map:
+		m := make(map[string]string)
+		switch v := value.(type) {
+		case string:
+			for _, pair := range strings.Split(v, ",") {
+				k, val, ok := strings.Cut(strings.TrimSpace(pair), "=")
+				if !ok {
+					if pair == "" {
+						continue
+					}
+					return invalid()
+				}
+				m[strings.TrimSpace(k)] = strings.TrimSpace(val)
+			}
+		case map[string]interface{}:
+			for k, val := range v {
+				m[k] = fmt.Sprintf("%v", val)
+			}
+		default:
+			return invalid()
+		}
+		field.Set(reflect.ValueOf(m))
+	default:
+		return invalid()
+	}
+
+	return nil
+}
+
+// flatten converts nested maps into dotted keys, keeping only known setting keys
+func flatten(prefix string, values map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{})
+	for k, v := range values {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		if IsKey(key) {
+			out[key] = v
+			continue
+		}
+		if nested, ok := v.(map[string]interface{}); ok {
+			for nk, nv := range flatten(key, nested) {
+				out[nk] = nv
+			}
+		}
+	}
+	return out
+}
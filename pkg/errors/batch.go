@@ -0,0 +1,80 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package errors
+
+import "errors"
+
+// Failure is the failure of one item of a batch operation, such as one command of
+// 'ccmd sync'
+type Failure struct {
+	Name  string // command or plugin, or the spec it was requested with
+	Repo  string // repository, when known
+	Stage string // step that failed, such as install, remove or prune
+	Err   error
+}
+
+func (f *Failure) Error() string {
+	return f.Name + ": " + f.Err.Error()
+}
+
+func (f *Failure) Unwrap() error {
+	return f.Err
+}
+
+// BatchError collects the failures of a batch operation under a summary message. Like
+// an error of errors.Join it unwraps to every failure, so errors.Is and errors.As see
+// each of them.
+type BatchError struct {
+	Message  string
+	Failures []*Failure
+}
+
+// Batch returns a BatchError for the failures, or nil when there are none
+func Batch(message string, failures []*Failure) error {
+	if len(failures) == 0 {
+		return nil
+	}
+	return &BatchError{Message: message, Failures: failures}
+}
+
+func (e *BatchError) Error() string {
+	return e.Message
+}
+
+func (e *BatchError) Unwrap() []error {
+	errs := make([]error, len(e.Failures))
+	for i, failure := range e.Failures {
+		errs[i] = failure
+	}
+	return errs
+}
+
+// code is the code the failures share, or CodePartialFailure when they differ
+func (e *BatchError) code() string {
+	code := ""
+	for _, failure := range e.Failures {
+		switch c := Code(failure.Err); {
+		case code == "":
+			code = c
+		case c != code:
+			return CodePartialFailure
+		}
+	}
+	return code
+}
+
+// AsBatch returns the BatchError err is or wraps, or nil
+func AsBatch(err error) *BatchError {
+	var batch *BatchError
+	if errors.As(err, &batch) {
+		return batch
+	}
+	return nil
+}
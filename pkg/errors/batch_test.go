@@ -0,0 +1,49 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestBatch(t *testing.T) {
+	if err := Batch("failed", nil); err != nil {
+		t.Fatalf("Batch() without failures = %v, want nil", err)
+	}
+
+	missing := &Failure{Name: "acme/deploy", Repo: "acme/deploy", Stage: "install", Err: NotFound("tag v2.0.0")}
+	err := fmt.Errorf("sync: %w", Batch("sync failed with 1 error(s)", []*Failure{missing}))
+	if got := err.Error(); got != "sync: sync failed with 1 error(s)" {
+		t.Errorf("Error() = %q", got)
+	}
+	if !errors.Is(err, ErrNotFound) {
+		t.Error("errors.Is() should see the failures")
+	}
+	if got := Code(err); got != CodeNotFound {
+		t.Errorf("Code() of a batch with one code = %q, want %q", got, CodeNotFound)
+	}
+	if batch := AsBatch(err); batch == nil || batch.Failures[0] != missing {
+		t.Errorf("AsBatch() = %v", batch)
+	}
+	if got := missing.Error(); got != "acme/deploy: not found: tag v2.0.0" {
+		t.Errorf("Failure.Error() = %q", got)
+	}
+
+	taken := &Failure{Name: "lint", Stage: "remove", Err: Conflict("name taken")}
+	err = Batch("sync failed with 2 error(s)", []*Failure{missing, taken})
+	if got := Code(err); got != CodePartialFailure {
+		t.Errorf("Code() of a mixed batch = %q, want %q", got, CodePartialFailure)
+	}
+	if HintFor(err) != hints[CodePartialFailure] {
+		t.Errorf("HintFor() of a mixed batch = %+v", HintFor(err))
+	}
+}
@@ -0,0 +1,198 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package errors
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// Error codes identify the kind of failure in machine-readable output
+const (
+	CodeNotFound      = "not_found"
+	CodeAlreadyExists = "already_exists"
+	CodeConflict      = "conflict"
+	CodeInvalidInput  = "invalid_input"
+	CodeGitOperation  = "git_operation"
+	CodeFileOperation = "file_operation"
+	CodeCanceled      = "canceled"
+	CodeUnknown       = "unknown"
+
+	CodeAuthenticationRequired = "authentication_required"
+	CodeReadOnly               = "read_only"
+
+	// CodePartialFailure is the code of a batch operation whose failures have
+	// different codes
+	CodePartialFailure = "partial_failure"
+)
+
+// Hint explains an error and suggests what to do next
+type Hint struct {
+	Explanation string
+	Suggestion  string
+}
+
+var hints = map[string]Hint{
+	CodeNotFound: {
+		Explanation: "The command, file or version does not exist.",
+		Suggestion:  "Run 'ccmd list' to see installed commands, or check the name and version.",
+	},
+	CodeAlreadyExists: {
+		Explanation: "The command is already installed.",
+		Suggestion:  "Use --force to reinstall it, or 'ccmd update' to move to a newer version.",
+	},
+	CodeConflict: {
+		Explanation: "The name is already used by another repository or a hand-written command.",
+		Suggestion:  "Install under another name with --rename <name>.",
+	},
+	CodeInvalidInput: {
+		Explanation: "An argument, flag or configuration value is not valid.",
+		Suggestion:  "Check the usage with --help.",
+	},
+	CodeGitOperation: {
+		Explanation: "A git command failed while fetching the repository.",
+		Suggestion: "Check the repository URL, your network and git credentials, e.g. with " +
+			"'git ls-remote <repository>'. Behind a proxy, set proxy.https with 'ccmd config set'.",
+	},
+	CodeFileOperation: {
+		Explanation: "Reading or writing a file failed.",
+		Suggestion:  "Check permissions and free disk space, then run 'ccmd verify' to find damaged installs.",
+	},
+	CodeCanceled: {
+		Explanation: "The operation was interrupted before it finished.",
+		Suggestion:  "Run the command again; partially installed files were cleaned up.",
+	},
+	CodeAuthenticationRequired: {
+		Explanation: "The repository host asked for credentials; ccmd does not prompt for them.",
+		Suggestion:  "Store a token with 'ccmd login <host>', or load your SSH key with 'ssh-add'.",
+	},
+	CodeReadOnly: {
+		Explanation: "The project is on a read-only file system or not writable by this user, so nothing was changed.",
+		Suggestion:  "Run the command in a writable copy of the project. 'ccmd list', 'info', 'search' and 'status' still work here.",
+	},
+	CodePartialFailure: {
+		Explanation: "Some items failed for different reasons; the others were processed.",
+		Suggestion:  "Fix the failures listed above and run the command again.",
+	},
+}
+
+// messageHint refines the hint of a code when the error message contains match
+type messageHint struct {
+	match string
+	hint  Hint
+}
+
+// messageHints refine the generic hint for failures recognised from the error message,
+// such as git's output
+var messageHints = map[string][]messageHint{
+	CodeGitOperation: {
+		{"authentication failed", Hint{
+			Explanation: "git could not authenticate with the repository host.",
+			Suggestion:  "Check your credentials or SSH key, or store a token with 'ccmd login <host>' for private repositories.",
+		}},
+		{"repository not found", Hint{
+			Explanation: "The repository does not exist or is private.",
+			Suggestion:  "Check the owner and name, and that your credentials can access it.",
+		}},
+		{"could not resolve host", Hint{
+			Explanation: "The repository host could not be reached.",
+			Suggestion:  "Check your network connection and proxy settings ('ccmd config get proxy.https').",
+		}},
+		{"certificate", Hint{
+			Explanation: "The TLS certificate of the repository host was not trusted.",
+			Suggestion:  "Set tls.ca_file to your organisation's CA bundle with 'ccmd config set'.",
+		}},
+	},
+	CodeConflict: {
+		{"local modifications", Hint{
+			Explanation: "The installed files were edited after install and would be overwritten.",
+			Suggestion:  "Review the edits with 'ccmd diff <name>', then retry with --backup or --overwrite-local.",
+		}},
+	},
+}
+
+// Code returns the error code for err, based on the sentinel it wraps. A batch error
+// has the code its failures share.
+func Code(err error) string {
+	if batch := AsBatch(err); batch != nil {
+		return batch.code()
+	}
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return CodeCanceled
+	case errors.Is(err, ErrConflict):
+		return CodeConflict
+	case errors.Is(err, ErrAlreadyExists):
+		return CodeAlreadyExists
+	case errors.Is(err, ErrNotFound):
+		return CodeNotFound
+	case errors.Is(err, ErrInvalidInput):
+		return CodeInvalidInput
+	case errors.Is(err, ErrAuthenticationRequired):
+		return CodeAuthenticationRequired
+	case errors.Is(err, ErrReadOnly):
+		return CodeReadOnly
+	case errors.Is(err, ErrGitOperation):
+		return CodeGitOperation
+	case errors.Is(err, ErrFileOperation):
+		return CodeFileOperation
+	default:
+		return CodeUnknown
+	}
+}
+
+// HintFor returns the explanation and suggested next step for err. Errors that know
+// their own remedy provide it with a Hint method; errors without a known code get an
+// empty hint.
+func HintFor(err error) Hint {
+	if Code(err) == CodePartialFailure {
+		return hints[CodePartialFailure]
+	}
+	var hinted interface{ Hint() Hint }
+	if errors.As(err, &hinted) {
+		return hinted.Hint()
+	}
+
+	code := Code(err)
+	if refined := messageHints[code]; len(refined) > 0 {
+		message := strings.ToLower(err.Error())
+		for _, h := range refined {
+			if strings.Contains(message, h.match) {
+				return h.hint
+			}
+		}
+	}
+	return hints[code]
+}
+
+// Chain returns the messages of err and every error it wraps, outermost first
+func Chain(err error) []string {
+	var chain []string
+	var walk func(error)
+	walk = func(err error) {
+		if err == nil {
+			return
+		}
+		chain = append(chain, err.Error())
+		switch wrapped := err.(type) {
+		case interface{ Unwrap() error }:
+			walk(wrapped.Unwrap())
+		case interface{ Unwrap() []error }:
+			for _, e := range wrapped.Unwrap() {
+				walk(e)
+			}
+		}
+	}
+	walk(err)
+	return chain
+}
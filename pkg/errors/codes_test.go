@@ -0,0 +1,81 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package errors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{nil, ""},
+		{NotFound("command"), CodeNotFound},
+		{AlreadyExists("command"), CodeAlreadyExists},
+		{Conflict("name taken"), CodeConflict},
+		{InvalidInput("bad"), CodeInvalidInput},
+		{GitError("clone", nil), CodeGitOperation},
+		{GitError("clone", fmt.Errorf("fetch: %w", ErrAuthenticationRequired)), CodeAuthenticationRequired},
+		{FileError("write", "/tmp/x", nil), CodeFileOperation},
+		{fmt.Errorf("install: %w", ReadOnly("/repo/ccmd.yaml")), CodeReadOnly},
+		{fmt.Errorf("install: %w", context.Canceled), CodeCanceled},
+		{fmt.Errorf("sync: %w", NotFound("ccmd.yaml")), CodeNotFound},
+		{errors.New("boom"), CodeUnknown},
+	}
+
+	for _, tt := range tests {
+		if got := Code(tt.err); got != tt.want {
+			t.Errorf("Code(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestHintFor(t *testing.T) {
+	if hint := HintFor(Conflict("name taken")); !strings.Contains(hint.Suggestion, "--rename") {
+		t.Errorf("conflict hint should suggest --rename, got %q", hint.Suggestion)
+	}
+	if hint := HintFor(Conflict(`command "x" has local modifications`)); !strings.Contains(hint.Suggestion, "--backup") {
+		t.Errorf("local modification hint should suggest --backup, got %q", hint.Suggestion)
+	}
+
+	hint := HintFor(GitError("clone", errors.New("fatal: Authentication failed for 'https://github.com/a/b'")))
+	if !strings.Contains(hint.Explanation, "authenticate") {
+		t.Errorf("expected the authentication hint, got %q", hint.Explanation)
+	}
+
+	if hint := HintFor(GitError("clone", errors.New("exit status 1"))); hint != hints[CodeGitOperation] {
+		t.Errorf("expected the generic git hint, got %+v", hint)
+	}
+
+	if hint := HintFor(errors.New("boom")); hint != (Hint{}) {
+		t.Errorf("unknown errors should have no hint, got %+v", hint)
+	}
+}
+
+func TestChain(t *testing.T) {
+	err := fmt.Errorf("sync failed: %w", NotFound("command \"a\""))
+
+	chain := Chain(err)
+	want := []string{`sync failed: not found: command "a"`, `not found: command "a"`, "not found"}
+	if strings.Join(chain, "|") != strings.Join(want, "|") {
+		t.Errorf("Chain() = %q, want %q", chain, want)
+	}
+
+	joined := errors.Join(errors.New("a"), errors.New("b"))
+	if got := Chain(joined); len(got) != 3 {
+		t.Errorf("Chain() of joined errors = %q, want 3 entries", got)
+	}
+}
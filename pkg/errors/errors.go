@@ -19,9 +19,18 @@ import (
 var (
 	ErrNotFound      = errors.New("not found")
 	ErrAlreadyExists = errors.New("already exists")
+	ErrConflict      = errors.New("conflict")
 	ErrInvalidInput  = errors.New("invalid input")
 	ErrGitOperation  = errors.New("git operation failed")
 	ErrFileOperation = errors.New("file operation failed")
+
+	// ErrAuthenticationRequired is matched by git failures where the repository host
+	// asked for credentials, which ccmd never prompts for
+	ErrAuthenticationRequired = errors.New("authentication required")
+
+	// ErrReadOnly is returned before an operation starts when the project, or a file it
+	// would change, cannot be written
+	ErrReadOnly = errors.New("read-only")
 )
 
 // errorWithContextFormat is the format string for errors with context
@@ -37,19 +46,33 @@ func AlreadyExists(resource string) error {
 	return fmt.Errorf(errorWithContextFormat, ErrAlreadyExists, resource)
 }
 
+// Conflict creates a conflict error with context, for names already used by another source
+func Conflict(msg string) error {
+	return fmt.Errorf(errorWithContextFormat, ErrConflict, msg)
+}
+
 // InvalidInput creates an invalid input error with context
 func InvalidInput(msg string) error {
 	return fmt.Errorf(errorWithContextFormat, ErrInvalidInput, msg)
 }
 
-// GitError creates a git operation error with context
+// GitError creates a git operation error with context. Authentication errors stay
+// reachable with errors.As so their host and remedy are not lost.
 func GitError(operation string, err error) error {
 	if err == nil {
 		return fmt.Errorf("%w during %s", ErrGitOperation, operation)
 	}
+	if errors.Is(err, ErrAuthenticationRequired) {
+		return fmt.Errorf("%w during %s: %w", ErrGitOperation, operation, err)
+	}
 	return fmt.Errorf("%w during %s: %v", ErrGitOperation, operation, err)
 }
 
+// ReadOnly creates a read-only error naming the path that cannot be written
+func ReadOnly(path string) error {
+	return fmt.Errorf("%w: %s is not writable", ErrReadOnly, path)
+}
+
 // FileError creates a file operation error with context
 func FileError(operation, path string, err error) error {
 	if err == nil {
@@ -22,6 +22,7 @@ func TestSentinelErrors(t *testing.T) {
 	}{
 		{"NotFound", NotFound("user"), ErrNotFound},
 		{"AlreadyExists", AlreadyExists("command"), ErrAlreadyExists},
+		{"Conflict", Conflict("name taken"), ErrConflict},
 		{"InvalidInput", InvalidInput("bad format"), ErrInvalidInput},
 		{"GitError", GitError("clone", nil), ErrGitOperation},
 		{"FileError", FileError("read", "/tmp/test", nil), ErrFileOperation},
@@ -44,6 +45,7 @@ func TestErrorMessages(t *testing.T) {
 	}{
 		{"NotFound", NotFound("user"), "not found: user"},
 		{"AlreadyExists", AlreadyExists("command"), "already exists: command"},
+		{"Conflict", Conflict("name taken"), "conflict: name taken"},
 		{"InvalidInput", InvalidInput("bad format"), "invalid input: bad format"},
 		{"GitError", GitError("clone", nil), "git operation failed during clone"},
 		{"FileError", FileError("read", "/tmp/test", nil), "file operation failed: read on /tmp/test"},
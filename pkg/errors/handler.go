@@ -42,6 +42,8 @@ func (h *Handler) Handle(err error) bool {
 		fmt.Fprintf(os.Stderr, "Not found: %v\n", err)
 	case errors.Is(err, ErrAlreadyExists):
 		fmt.Fprintf(os.Stderr, "Already exists: %v\n", err)
+	case errors.Is(err, ErrConflict):
+		fmt.Fprintf(os.Stderr, "Conflict: %v\n", err)
 	case errors.Is(err, ErrInvalidInput):
 		fmt.Fprintf(os.Stderr, "Invalid input: %v\n", err)
 	case errors.Is(err, ErrGitOperation):
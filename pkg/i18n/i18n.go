@@ -0,0 +1,173 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+// Package i18n translates the messages and help of the ccmd CLI.
+//
+// Messages are identified by their English text, as in gettext: T looks the text up in
+// the catalog of the selected locale and returns it unchanged when there is no
+// translation, so English needs no catalog and untranslated messages stay readable.
+// Catalogs are the YAML files in locales/, named after their locale (pt_BR.yaml), and
+// are embedded in the binary.
+package i18n
+
+import (
+	"embed"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultLocale is the locale of the messages in the source code
+const DefaultLocale = "en"
+
+//go:embed locales/*.yaml
+var localeFiles embed.FS
+
+// Catalog is the translation of ccmd's messages into one locale
+type Catalog struct {
+	Locale   string    `yaml:"locale"`
+	Name     string    `yaml:"name"` // name of the language in the language itself
+	Messages []Message `yaml:"messages"`
+}
+
+// Message is the translation of one message. Placeholders such as %s must appear in
+// Text in the same order as in ID.
+type Message struct {
+	ID   string `yaml:"id"`
+	Text string `yaml:"text"`
+}
+
+// Selected locale, changed by SetLocale
+var (
+	mu       sync.RWMutex
+	locale   = DefaultLocale
+	messages map[string]string
+)
+
+// Locales returns the locales with a catalog, DefaultLocale included
+func Locales() []string {
+	locales := []string{DefaultLocale}
+	entries, _ := localeFiles.ReadDir("locales")
+	for _, entry := range entries {
+		locales = append(locales, strings.TrimSuffix(entry.Name(), path.Ext(entry.Name())))
+	}
+	sort.Strings(locales[1:])
+	return locales
+}
+
+// LoadCatalog reads the embedded catalog of a locale
+func LoadCatalog(name string) (*Catalog, error) {
+	data, err := localeFiles.ReadFile("locales/" + name + ".yaml")
+	if err != nil {
+		return nil, fmt.Errorf("no catalog for locale %q (available: %s)", name, strings.Join(Locales(), ", "))
+	}
+	var catalog Catalog
+	if err := yaml.Unmarshal(data, &catalog); err != nil {
+		return nil, fmt.Errorf("invalid catalog for locale %q: %w", name, err)
+	}
+	return &catalog, nil
+}
+
+// Detect returns the locale to use: setting when it is not empty, otherwise the first
+// of LC_ALL, LC_MESSAGES and LANG that is set
+func Detect(setting string) string {
+	if setting != "" {
+		return Normalize(setting)
+	}
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if value := os.Getenv(name); value != "" {
+			return Normalize(value)
+		}
+	}
+	return DefaultLocale
+}
+
+// Normalize turns a POSIX or BCP 47 locale name such as pt_BR.UTF-8 or pt-br into the
+// form catalogs are named after, pt_BR. C and POSIX are DefaultLocale.
+func Normalize(name string) string {
+	name, _, _ = strings.Cut(name, ".")
+	name, _, _ = strings.Cut(name, "@")
+	if name == "" || name == "C" || name == "POSIX" {
+		return DefaultLocale
+	}
+
+	language, region, ok := strings.Cut(strings.ReplaceAll(name, "-", "_"), "_")
+	language = strings.ToLower(language)
+	if !ok {
+		return language
+	}
+	return language + "_" + strings.ToUpper(region)
+}
+
+// SetLocale selects the catalog T uses. A locale without a catalog of its own falls
+// back to the catalog of its language (pt_PT to pt), then to DefaultLocale with an
+// error.
+func SetLocale(name string) error {
+	name = Normalize(name)
+	selected, catalog := DefaultLocale, (*Catalog)(nil)
+
+	var err error
+	if name != DefaultLocale {
+		language, _, _ := strings.Cut(name, "_")
+		for _, candidate := range []string{name, language} {
+			if candidate == DefaultLocale {
+				break
+			}
+			if catalog, err = LoadCatalog(candidate); err == nil {
+				selected = candidate
+				break
+			}
+		}
+		if language == DefaultLocale {
+			err = nil
+		}
+	}
+
+	var translations map[string]string
+	if catalog != nil {
+		translations = make(map[string]string, len(catalog.Messages))
+		for _, message := range catalog.Messages {
+			if message.Text != "" {
+				translations[message.ID] = message.Text
+			}
+		}
+	}
+
+	mu.Lock()
+	locale, messages = selected, translations
+	mu.Unlock()
+	return err
+}
+
+// Locale returns the selected locale
+func Locale() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return locale
+}
+
+// T returns the translation of a message, or the message itself
+func T(id string) string {
+	mu.RLock()
+	defer mu.RUnlock()
+	if text, ok := messages[id]; ok {
+		return text
+	}
+	return id
+}
+
+// Sprintf formats the translation of format
+func Sprintf(format string, a ...interface{}) string {
+	return fmt.Sprintf(T(format), a...)
+}
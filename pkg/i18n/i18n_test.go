@@ -0,0 +1,136 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package i18n
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalize(t *testing.T) {
+	for input, want := range map[string]string{
+		"pt_BR.UTF-8":     "pt_BR",
+		"pt-br":           "pt_BR",
+		"de_DE@euro":      "de_DE",
+		"FR":              "fr",
+		"C":               "en",
+		"POSIX":           "en",
+		"C.UTF-8":         "en",
+		"":                "en",
+		"en_US.ISO8859-1": "en_US",
+	} {
+		assert.Equal(t, want, Normalize(input), input)
+	}
+}
+
+func TestDetect(t *testing.T) {
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_MESSAGES", "")
+	t.Setenv("LANG", "pt_BR.UTF-8")
+	assert.Equal(t, "pt_BR", Detect(""))
+	assert.Equal(t, "fr", Detect("fr"))
+
+	t.Setenv("LC_MESSAGES", "de_DE.UTF-8")
+	assert.Equal(t, "de_DE", Detect(""))
+	t.Setenv("LC_ALL", "C")
+	assert.Equal(t, "en", Detect(""))
+}
+
+func TestSetLocale(t *testing.T) {
+	t.Cleanup(func() { _ = SetLocale(DefaultLocale) })
+
+	require.NoError(t, SetLocale("pt_BR.UTF-8"))
+	assert.Equal(t, "pt_BR", Locale())
+	assert.Equal(t, "Operação cancelada", T("Operation cancelled"))
+	assert.Equal(t, "Código: E42", Sprintf("Code: %s", "E42"))
+	assert.Equal(t, "not translated", T("not translated"))
+
+	// English needs no catalog, whatever the region
+	require.NoError(t, SetLocale("en_GB"))
+	assert.Equal(t, DefaultLocale, Locale())
+	assert.Equal(t, "Operation cancelled", T("Operation cancelled"))
+
+	err := SetLocale("pt_PT")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "pt_BR")
+	assert.Equal(t, DefaultLocale, Locale())
+	assert.Equal(t, "Operation cancelled", T("Operation cancelled"))
+}
+
+// external are messages that are not literals in the source code: cobra's own help and
+// texts assembled from constants
+var external = map[string]bool{
+	"Help about any command": true,
+	"Project directory to use instead of searching for ccmd.yaml (default $CCMD_PROJECT_DIR)": true,
+}
+
+// placeholders matches fmt verbs
+var placeholders = regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z%]`)
+
+// TestCatalogs checks every catalog: its placeholders must match the messages they
+// translate, and every message must still be used somewhere in the source code.
+func TestCatalogs(t *testing.T) {
+	source := readSources(t, filepath.Join("..", ".."))
+
+	locales := Locales()
+	require.Greater(t, len(locales), 1)
+	for _, locale := range locales[1:] {
+		catalog, err := LoadCatalog(locale)
+		require.NoError(t, err, locale)
+		assert.Equal(t, locale, catalog.Locale)
+		assert.NotEmpty(t, catalog.Name, locale)
+
+		seen := make(map[string]bool)
+		for _, message := range catalog.Messages {
+			assert.False(t, seen[message.ID], "%s: duplicate message %q", locale, message.ID)
+			seen[message.ID] = true
+
+			assert.NotEmpty(t, message.Text, "%s: %q is not translated", locale, message.ID)
+			assert.Equal(t, placeholders.FindAllString(message.ID, -1), placeholders.FindAllString(message.Text, -1),
+				"%s: placeholders of %q", locale, message.ID)
+
+			quoted := strconv.Quote(message.ID)
+			used := external[message.ID] || strings.Contains(source, message.ID) || strings.Contains(source, quoted[1:len(quoted)-1])
+			assert.True(t, used, "%s: %q is not used in the source code", locale, message.ID)
+		}
+	}
+}
+
+// readSources concatenates the Go files below root, tests excluded
+func readSources(t *testing.T, root string) string {
+	var source strings.Builder
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() && strings.HasPrefix(entry.Name(), ".") && path != root {
+			return filepath.SkipDir
+		}
+		if entry.IsDir() || !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		source.Write(data)
+		return nil
+	})
+	require.NoError(t, err)
+	return source.String()
+}
@@ -0,0 +1,207 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package logger
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// RotatingFile is an append-only log file that is rotated once it would grow past
+// maxSize: ccmd.log becomes ccmd.log.1, ccmd.log.1 becomes ccmd.log.2 and so on, keeping
+// at most maxBackups old files
+type RotatingFile struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+// OpenRotatingFile opens or creates the log file at path, creating its directory
+func OpenRotatingFile(path string, maxSize int64, maxBackups int) (*RotatingFile, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	r := &RotatingFile{path: path, maxSize: maxSize, maxBackups: maxBackups}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *RotatingFile) open() error {
+	file, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	r.file = file
+	r.size = info.Size()
+	return nil
+}
+
+// Write appends p, rotating first when p would take the file past its maximum size
+func (r *RotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return 0, os.ErrClosed
+	}
+	if r.maxSize > 0 && r.size > 0 && r.size+int64(len(p)) > r.maxSize {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// rotate shifts the old files by one, dropping the oldest, and starts a new file
+func (r *RotatingFile) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return err
+	}
+	r.file = nil
+
+	if r.maxBackups < 1 {
+		_ = os.Remove(r.path)
+	} else {
+		_ = os.Remove(fmt.Sprintf("%s.%d", r.path, r.maxBackups))
+		for i := r.maxBackups - 1; i >= 1; i-- {
+			_ = os.Rename(fmt.Sprintf("%s.%d", r.path, i), fmt.Sprintf("%s.%d", r.path, i+1))
+		}
+		if err := os.Rename(r.path, r.path+".1"); err != nil {
+			return err
+		}
+	}
+	return r.open()
+}
+
+// Close closes the file
+func (r *RotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
+
+// File sink receiving every record as JSON, whatever the console level; nil when off
+var (
+	fileMu      sync.RWMutex
+	fileHandler slog.Handler
+)
+
+// SetFile sends the records of all loggers, including ones already created, to w as
+// JSON lines at every level. fields are added to each record, such as the correlation
+// ID of an invocation. It returns a function that restores the previous sink.
+func SetFile(w io.Writer, fields Fields) (restore func()) {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	attrs := make([]slog.Attr, 0, len(keys))
+	for _, key := range keys {
+		attrs = append(attrs, slog.Any(key, fields[key]))
+	}
+
+	handler := slog.NewJSONHandler(w, &slog.HandlerOptions{Level: slog.LevelDebug}).WithAttrs(attrs)
+
+	fileMu.Lock()
+	prev := fileHandler
+	fileHandler = handler
+	fileMu.Unlock()
+
+	return func() {
+		fileMu.Lock()
+		fileHandler = prev
+		fileMu.Unlock()
+	}
+}
+
+func currentFileHandler() slog.Handler {
+	fileMu.RLock()
+	defer fileMu.RUnlock()
+	return fileHandler
+}
+
+// NewCorrelationID returns a random ID tying together the records of one invocation
+func NewCorrelationID() string {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b[:])
+}
+
+// teeHandler writes records to the console handler at its level and to the file sink,
+// if one is set, at every level
+type teeHandler struct {
+	console slog.Handler
+	// with replays WithAttrs and WithGroup calls on the file sink, which can be set
+	// after the logger was created
+	with []func(slog.Handler) slog.Handler
+}
+
+func (h *teeHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.console.Enabled(ctx, level) || currentFileHandler() != nil
+}
+
+func (h *teeHandler) Handle(ctx context.Context, record slog.Record) error {
+	var err error
+	if h.console.Enabled(ctx, record.Level) {
+		err = h.console.Handle(ctx, record)
+	}
+	if file := currentFileHandler(); file != nil {
+		for _, with := range h.with {
+			file = with(file)
+		}
+		// A log file that cannot be written must not fail the operation being logged
+		_ = file.Handle(ctx, record.Clone())
+	}
+	return err
+}
+
+func (h *teeHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return h.extend(h.console.WithAttrs(attrs), func(file slog.Handler) slog.Handler { return file.WithAttrs(attrs) })
+}
+
+func (h *teeHandler) WithGroup(name string) slog.Handler {
+	return h.extend(h.console.WithGroup(name), func(file slog.Handler) slog.Handler { return file.WithGroup(name) })
+}
+
+func (h *teeHandler) extend(console slog.Handler, with func(slog.Handler) slog.Handler) slog.Handler {
+	return &teeHandler{
+		console: console,
+		with:    append(append([]func(slog.Handler) slog.Handler{}, h.with...), with),
+	}
+}
@@ -12,9 +12,11 @@ package logger
 
 import (
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
 	"strings"
+	"sync"
 )
 
 // Fields represents structured fields for logging
@@ -44,31 +46,70 @@ type logger struct {
 
 // New creates a new logger instance
 func New() Logger {
-	level := slog.LevelInfo
-
-	if envLevel := os.Getenv("CCMD_LOG_LEVEL"); envLevel != "" {
-		switch strings.ToLower(envLevel) {
-		case "debug":
-			level = slog.LevelDebug
-		case "info":
-			level = slog.LevelInfo
-		case "warn", "warning":
-			level = slog.LevelWarn
-		case "error":
-			level = slog.LevelError
-		}
-	}
+	return NewWithLevel(os.Getenv("CCMD_LOG_LEVEL"))
+}
 
+// NewWithLevel creates a logger at the named level (debug, info, warn, error).
+// Unknown or empty names fall back to info.
+func NewWithLevel(name string) Logger {
 	opts := &slog.HandlerOptions{
-		Level: level,
+		Level: parseLevel(name),
 	}
 
-	handler := slog.NewTextHandler(os.Stderr, opts)
+	handler := &teeHandler{console: slog.NewTextHandler(logWriter{}, opts)}
 	return &logger{
 		slogger: slog.New(handler),
 	}
 }
 
+// Redirected log destination; nil means os.Stderr
+var (
+	writerMu sync.RWMutex
+	writer   io.Writer
+)
+
+// SetOutput redirects all loggers, including ones already created, to w and returns
+// a function that restores the previous destination
+func SetOutput(w io.Writer) (restore func()) {
+	writerMu.Lock()
+	prev := writer
+	writer = w
+	writerMu.Unlock()
+
+	return func() {
+		writerMu.Lock()
+		writer = prev
+		writerMu.Unlock()
+	}
+}
+
+// logWriter forwards log records to the current destination
+type logWriter struct{}
+
+func (logWriter) Write(p []byte) (int, error) {
+	writerMu.RLock()
+	w := writer
+	writerMu.RUnlock()
+
+	if w == nil {
+		return os.Stderr.Write(p)
+	}
+	return w.Write(p)
+}
+
+func parseLevel(name string) slog.Level {
+	switch strings.ToLower(name) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn", "warning":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
 // Default creates a logger with default settings
 func Default() Logger {
 	return New()
@@ -155,6 +196,11 @@ func (l *logger) WithError(err error) Logger {
 // Global logger instance
 var defaultLogger = Default()
 
+// SetLevel replaces the default logger with one at the named level
+func SetLevel(name string) {
+	defaultLogger = NewWithLevel(name)
+}
+
 // GetDefault returns the default logger
 func GetDefault() Logger {
 	return defaultLogger
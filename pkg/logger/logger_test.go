@@ -254,3 +254,20 @@ func TestLoggerSpecialValues(t *testing.T) {
 		})
 	}
 }
+
+func TestSetOutput(t *testing.T) {
+	var buf strings.Builder
+	l := New()
+
+	restore := SetOutput(&buf)
+	l.Warn("redirected message")
+	restore()
+	l.Warn("back on stderr")
+
+	if !strings.Contains(buf.String(), "redirected message") {
+		t.Errorf("expected redirected output, got %q", buf.String())
+	}
+	if strings.Contains(buf.String(), "back on stderr") {
+		t.Error("output was not restored")
+	}
+}
@@ -0,0 +1,158 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	ccmderrors "github.com/gifflet/ccmd/pkg/errors"
+	"github.com/gifflet/ccmd/pkg/i18n"
+)
+
+// ErrorReport is the structured form of a failed command
+type ErrorReport struct {
+	Code        string   `json:"code"`
+	Message     string   `json:"message"`
+	Explanation string   `json:"explanation,omitempty"`
+	Suggestion  string   `json:"suggestion,omitempty"`
+	Chain       []string `json:"chain,omitempty"` // wrapped errors, with verbose output only
+	// Failures lists the failed items of a batch operation, such as sync
+	Failures []FailureReport `json:"failures,omitempty"`
+}
+
+// FailureReport is the structured form of one failed item of a batch operation
+type FailureReport struct {
+	Name    string `json:"name"`
+	Repo    string `json:"repo,omitempty"`
+	Stage   string `json:"stage"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// NewErrorReport describes err with its code and remediation hint. The error chain is
+// included when verbose is set.
+func NewErrorReport(err error, verbose bool) ErrorReport {
+	hint := ccmderrors.HintFor(err)
+	report := ErrorReport{
+		Code:        ccmderrors.Code(err),
+		Message:     err.Error(),
+		Explanation: hint.Explanation,
+		Suggestion:  hint.Suggestion,
+	}
+	if verbose {
+		report.Chain = ccmderrors.Chain(err)
+	}
+	if batch := ccmderrors.AsBatch(err); batch != nil {
+		for _, failure := range batch.Failures {
+			report.Failures = append(report.Failures, FailureReport{
+				Name:    failure.Name,
+				Repo:    failure.Repo,
+				Stage:   failure.Stage,
+				Code:    ccmderrors.Code(failure.Err),
+				Message: failure.Err.Error(),
+			})
+		}
+	}
+	return report
+}
+
+// PrintFailures prints the failures of a batch error as a table grouped by stage. Other
+// errors print nothing.
+func PrintFailures(err error) {
+	writeFailures(errWriter(), NewErrorReport(err, false).Failures, enabled(LevelVerbose))
+}
+
+// writeFailures prints failures as a table showing the first line of each message.
+// With verbose, the full messages of failures that span several lines follow the table.
+func writeFailures(w io.Writer, failures []FailureReport, verbose bool) {
+	if len(failures) == 0 {
+		return
+	}
+	failures = append([]FailureReport(nil), failures...)
+	sort.SliceStable(failures, func(i, j int) bool {
+		return failures[i].Stage < failures[j].Stage
+	})
+
+	table := NewTable(Column{Header: "STAGE"}, Column{Header: "NAME"}, Column{Header: "REPO"},
+		Column{Header: "CODE"}, Column{Header: "ERROR"})
+	var detailed []FailureReport
+	for _, failure := range failures {
+		repo := failure.Repo
+		if repo == "" {
+			repo = "-"
+		}
+		message, _, multiline := strings.Cut(failure.Message, "\n")
+		if multiline {
+			detailed = append(detailed, failure)
+		}
+		table.AddRow(failure.Stage, failure.Name, repo, failure.Code, strings.TrimSpace(message))
+	}
+	_, _ = fmt.Fprintln(w)
+	_ = table.Write(w, FormatTable)
+	_, _ = fmt.Fprintln(w)
+
+	if !verbose {
+		return
+	}
+	for _, failure := range detailed {
+		_, _ = fmt.Fprintf(w, "%s (%s):\n", Bold(failure.Name), failure.Stage)
+		for _, line := range strings.Split(failure.Message, "\n") {
+			_, _ = fmt.Fprintf(w, "  %s\n", line)
+		}
+		_, _ = fmt.Fprintln(w)
+	}
+}
+
+// RenderError prints err with an explanation and suggested next step. With verbose,
+// the error code and every wrapped error are printed as well.
+func RenderError(err error, verbose bool) {
+	if err == nil {
+		return
+	}
+
+	report := NewErrorReport(err, verbose)
+	w := errWriter()
+
+	_, _ = fmt.Fprintln(w, Error(i18n.T("Error:")+" "+report.Message))
+	writeFailures(w, report.Failures, verbose)
+	if report.Explanation != "" {
+		_, _ = fmt.Fprintf(w, "  %s\n", i18n.T(report.Explanation))
+	}
+	if report.Suggestion != "" {
+		_, _ = fmt.Fprintf(w, "  %s %s\n", Bold(i18n.T("Hint:")), i18n.T(report.Suggestion))
+	}
+
+	if verbose {
+		_, _ = fmt.Fprintf(w, "\n%s\n%s\n", i18n.Sprintf("Code: %s", report.Code), i18n.T("Error chain:"))
+		for i, message := range report.Chain {
+			_, _ = fmt.Fprintf(w, "  %d. %s\n", i+1, message)
+		}
+	} else if len(ccmderrors.Chain(err)) > 1 {
+		_, _ = fmt.Fprintln(w, "  "+i18n.T("Run with --verbose for details."))
+	}
+}
+
+// RenderErrorJSON prints err as a JSON object {"error": ErrorReport} for scripts
+func RenderErrorJSON(err error, verbose bool) {
+	if err == nil {
+		return
+	}
+
+	data, marshalErr := json.MarshalIndent(map[string]ErrorReport{"error": NewErrorReport(err, verbose)}, "", "  ")
+	if marshalErr != nil {
+		RenderError(err, verbose)
+		return
+	}
+	_, _ = fmt.Fprintln(errWriter(), string(data))
+}
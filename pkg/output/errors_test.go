@@ -0,0 +1,122 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	ccmderrors "github.com/gifflet/ccmd/pkg/errors"
+)
+
+func TestRenderError(t *testing.T) {
+	var errOut bytes.Buffer
+	defer SetOutput(&bytes.Buffer{}, &errOut)()
+
+	err := fmt.Errorf("install failed: %w", ccmderrors.Conflict("name \"hello\" is taken"))
+
+	RenderError(err, false)
+	got := errOut.String()
+	if !strings.Contains(got, "install failed") || !strings.Contains(got, "--rename") {
+		t.Errorf("RenderError() missing message or hint:\n%s", got)
+	}
+	if strings.Contains(got, "Error chain") {
+		t.Errorf("RenderError() should not print the chain without verbose:\n%s", got)
+	}
+
+	errOut.Reset()
+	RenderError(err, true)
+	got = errOut.String()
+	if !strings.Contains(got, "Code: conflict") || !strings.Contains(got, "3. conflict") {
+		t.Errorf("RenderError() verbose output missing code or chain:\n%s", got)
+	}
+}
+
+func TestRenderBatchError(t *testing.T) {
+	var errOut bytes.Buffer
+	defer SetOutput(&bytes.Buffer{}, &errOut)()
+
+	err := ccmderrors.Batch("sync failed with 2 error(s)", []*ccmderrors.Failure{
+		{Name: "lint", Stage: "remove", Err: ccmderrors.Conflict("name taken")},
+		{Name: "acme/deploy", Repo: "acme/deploy", Stage: "install", Err: ccmderrors.NotFound("tag v2.0.0")},
+	})
+
+	RenderError(err, false)
+	lines := strings.Split(errOut.String(), "\n")
+	if len(lines) < 6 || !strings.HasPrefix(lines[2], "STAGE") ||
+		!strings.HasPrefix(lines[4], "install  acme/deploy  acme/deploy  not_found  not found: tag v2.0.0") ||
+		!strings.HasPrefix(lines[5], "remove   lint         -            conflict") {
+		t.Errorf("RenderError() should group the failures by stage:\n%s", errOut.String())
+	}
+
+	errOut.Reset()
+	RenderErrorJSON(err, false)
+	var decoded struct {
+		Error ErrorReport `json:"error"`
+	}
+	if err := json.Unmarshal(errOut.Bytes(), &decoded); err != nil {
+		t.Fatalf("RenderErrorJSON() wrote invalid JSON: %v", err)
+	}
+	want := FailureReport{Name: "lint", Stage: "remove", Code: ccmderrors.CodeConflict, Message: "conflict: name taken"}
+	if decoded.Error.Code != ccmderrors.CodePartialFailure || len(decoded.Error.Failures) != 2 || decoded.Error.Failures[0] != want {
+		t.Errorf("unexpected report: %+v", decoded.Error)
+	}
+}
+
+func TestRenderBatchErrorMultilineMessage(t *testing.T) {
+	var errOut bytes.Buffer
+	defer SetOutput(&bytes.Buffer{}, &errOut)()
+
+	err := ccmderrors.Batch("sync failed with 1 error(s)", []*ccmderrors.Failure{
+		{Name: "deploy", Repo: "acme/deploy", Stage: "install", Err: fmt.Errorf("clone failed\nfatal: repository not found\nhint: check the URL")},
+	})
+
+	RenderError(err, false)
+	got := errOut.String()
+	if !strings.Contains(got, "clone failed\n") || strings.Contains(got, "fatal: repository not found") {
+		t.Errorf("RenderError() table should show the first line of the message only:\n%s", got)
+	}
+
+	errOut.Reset()
+	RenderError(err, true)
+	got = errOut.String()
+	if !strings.Contains(got, "  fatal: repository not found\n  hint: check the URL\n") {
+		t.Errorf("RenderError() verbose output should show the full message:\n%s", got)
+	}
+
+	errOut.Reset()
+	RenderErrorJSON(err, false)
+	if !strings.Contains(errOut.String(), `clone failed\nfatal: repository not found`) {
+		t.Errorf("RenderErrorJSON() should keep the full message:\n%s", errOut.String())
+	}
+}
+
+func TestRenderErrorJSON(t *testing.T) {
+	var errOut bytes.Buffer
+	defer SetOutput(&bytes.Buffer{}, &errOut)()
+
+	RenderErrorJSON(ccmderrors.NotFound("command \"deploy\""), false)
+
+	var decoded struct {
+		Error ErrorReport `json:"error"`
+	}
+	if err := json.Unmarshal(errOut.Bytes(), &decoded); err != nil {
+		t.Fatalf("RenderErrorJSON() wrote invalid JSON: %v\n%s", err, errOut.String())
+	}
+	if decoded.Error.Code != ccmderrors.CodeNotFound || decoded.Error.Suggestion == "" {
+		t.Errorf("unexpected report: %+v", decoded.Error)
+	}
+	if decoded.Error.Chain != nil {
+		t.Errorf("chain should be omitted without verbose, got %q", decoded.Error.Chain)
+	}
+}
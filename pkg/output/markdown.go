@@ -0,0 +1,137 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package output
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// Styles of rendered markdown
+var (
+	heading = color.New(color.Bold, color.FgCyan).SprintFunc()
+	code    = color.New(color.FgYellow).SprintFunc()
+	faint   = color.New(color.Faint).SprintFunc()
+	italic  = color.New(color.Italic).SprintFunc()
+)
+
+// ruleWidth is the width of a rendered horizontal rule
+const ruleWidth = 40
+
+var (
+	headingLine  = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	bulletLine   = regexp.MustCompile(`^(\s*)[-*+]\s+(.*)$`)
+	numberedLine = regexp.MustCompile(`^(\s*)(\d+[.)])\s+(.*)$`)
+	ruleLine     = regexp.MustCompile(`^\s*((-\s*){3,}|(\*\s*){3,}|(_\s*){3,})$`)
+
+	imageSpan  = regexp.MustCompile(`!\[([^\]]*)\]\(([^)\s]+)[^)]*\)`)
+	linkSpan   = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)[^)]*\)`)
+	boldSpan   = regexp.MustCompile(`\*\*([^*]+)\*\*|__([^_]+)__`)
+	italicStar = regexp.MustCompile(`\*([^*\s][^*]*)\*`)
+	italicLine = regexp.MustCompile(`\b_([^_]+)_\b`)
+)
+
+// RenderMarkdown renders markdown for the terminal: headings are bold (the first two
+// levels also in color), list markers become bullets, code is colored and indented, and
+// links show their target. Colors follow SetColor and glyphs the current theme. Tables
+// and HTML are kept as written.
+func RenderMarkdown(src string) string {
+	var out []string
+	fence := ""
+	for _, line := range strings.Split(strings.TrimRight(src, "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		// Fenced code blocks are printed verbatim, indented
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+				continue
+			}
+			out = append(out, "    "+code(line))
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = trimmed[:3]
+			continue
+		}
+
+		switch {
+		case trimmed == "":
+			out = append(out, "")
+		case headingLine.MatchString(line):
+			m := headingLine.FindStringSubmatch(line)
+			if len(m[1]) <= 2 {
+				out = append(out, heading(m[2]))
+			} else {
+				out = append(out, Bold(m[2]))
+			}
+		case ruleLine.MatchString(line):
+			out = append(out, faint(strings.Repeat("─", ruleWidth)))
+		case bulletLine.MatchString(line):
+			m := bulletLine.FindStringSubmatch(line)
+			out = append(out, "  "+m[1]+"• "+renderInline(m[2]))
+		case numberedLine.MatchString(line):
+			m := numberedLine.FindStringSubmatch(line)
+			out = append(out, "  "+m[1]+m[2]+" "+renderInline(m[3]))
+		case strings.HasPrefix(trimmed, ">"):
+			text := strings.TrimSpace(strings.TrimPrefix(trimmed, ">"))
+			out = append(out, faint("│ ")+italic(renderInline(text)))
+		case strings.HasPrefix(line, "    ") || strings.HasPrefix(line, "\t"):
+			out = append(out, "    "+code(strings.TrimPrefix(strings.TrimPrefix(line, "\t"), "    ")))
+		default:
+			out = append(out, renderInline(line))
+		}
+	}
+	return render(strings.Join(out, "\n"))
+}
+
+// renderInline styles code spans, emphasis, links and images within a line. Text in
+// backticks is left alone.
+func renderInline(s string) string {
+	parts := strings.Split(s, "`")
+	// An unmatched backtick is text
+	if len(parts)%2 == 0 {
+		parts[len(parts)-2] += "`" + parts[len(parts)-1]
+		parts = parts[:len(parts)-1]
+	}
+
+	var b strings.Builder
+	for i, part := range parts {
+		if i%2 == 1 {
+			b.WriteString(code(part))
+			continue
+		}
+		part = imageSpan.ReplaceAllStringFunc(part, func(m string) string {
+			sub := imageSpan.FindStringSubmatch(m)
+			return faint("[image: " + sub[1] + "]")
+		})
+		part = linkSpan.ReplaceAllStringFunc(part, func(m string) string {
+			sub := linkSpan.FindStringSubmatch(m)
+			if sub[1] == sub[2] {
+				return sub[2]
+			}
+			return sub[1] + " " + faint("("+sub[2]+")")
+		})
+		part = boldSpan.ReplaceAllStringFunc(part, func(m string) string {
+			sub := boldSpan.FindStringSubmatch(m)
+			return Bold(sub[1] + sub[2])
+		})
+		part = italicStar.ReplaceAllStringFunc(part, func(m string) string {
+			return italic(italicStar.FindStringSubmatch(m)[1])
+		})
+		part = italicLine.ReplaceAllStringFunc(part, func(m string) string {
+			return italic(italicLine.FindStringSubmatch(m)[1])
+		})
+		b.WriteString(part)
+	}
+	return b.String()
+}
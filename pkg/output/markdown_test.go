@@ -0,0 +1,69 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package output
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	useRenderer(t, ThemeUnicode, LevelNormal)
+
+	src := strings.Join([]string{
+		"# Review",
+		"",
+		"Check **every** file, see [the guide](https://example.com/guide) and `git diff`.",
+		"",
+		"- first_item",
+		"  * nested _note_",
+		"1. step",
+		"> quoted",
+		"---",
+		"```bash",
+		"# not a heading",
+		"```",
+		"![logo](logo.png) <https://example.com>",
+	}, "\n")
+
+	want := strings.Join([]string{
+		"Review",
+		"",
+		"Check every file, see the guide (https://example.com/guide) and git diff.",
+		"",
+		"  • first_item",
+		"    • nested note",
+		"  1. step",
+		"│ quoted",
+		strings.Repeat("─", ruleWidth),
+		"    # not a heading",
+		"[image: logo] <https://example.com>",
+	}, "\n")
+	assert.Equal(t, want, RenderMarkdown(src))
+
+	// Unmatched backticks and asterisks are text
+	assert.Equal(t, "2 * 3 and `x", RenderMarkdown("2 * 3 and `x"))
+
+	// Glyphs follow the theme
+	useRenderer(t, ThemeASCII, LevelNormal)
+	assert.Equal(t, "  * item\n| quote", RenderMarkdown("- item\n> quote"))
+}
+
+func TestRenderMarkdownColors(t *testing.T) {
+	useRenderer(t, ThemeUnicode, LevelNormal)
+	color.NoColor = false
+
+	rendered := RenderMarkdown("# Title\n\nUse `ccmd`.")
+	assert.NotEqual(t, "Title\n\nUse ccmd.", rendered)
+	assert.Contains(t, rendered, "\x1b[")
+}
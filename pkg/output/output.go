@@ -12,9 +12,13 @@ package output
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"sync"
 
 	"github.com/fatih/color"
+
+	"github.com/gifflet/ccmd/pkg/i18n"
 )
 
 // Color functions for different message types
@@ -24,31 +28,86 @@ var (
 	Warning = color.New(color.FgYellow).SprintFunc()
 	Info    = color.New(color.FgBlue).SprintFunc()
 	Bold    = color.New(color.Bold).SprintFunc()
+	Label   = color.New(color.FgCyan).SprintFunc() // field names in detail views
+)
+
+// Redirected writers; nil means os.Stdout and os.Stderr
+var (
+	writerMu sync.RWMutex
+	stdout   io.Writer
+	stderr   io.Writer
 )
 
-// PrintSuccessf prints a formatted success message.
+// SetOutput redirects messages to out and errors to errOut, returning a function
+// that restores the previous writers. Embedders use it to silence the CLI output.
+func SetOutput(out, errOut io.Writer) (restore func()) {
+	writerMu.Lock()
+	prevOut, prevErr := stdout, stderr
+	stdout, stderr = out, errOut
+	writerMu.Unlock()
+
+	return func() {
+		writerMu.Lock()
+		stdout, stderr = prevOut, prevErr
+		writerMu.Unlock()
+	}
+}
+
+func outWriter() io.Writer {
+	writerMu.RLock()
+	defer writerMu.RUnlock()
+	if stdout == nil {
+		return os.Stdout
+	}
+	return stdout
+}
+
+func errWriter() io.Writer {
+	writerMu.RLock()
+	defer writerMu.RUnlock()
+	if stderr == nil {
+		return os.Stderr
+	}
+	return stderr
+}
+
+// The Print functions translate format with the catalog of the selected locale before
+// formatting, see package i18n.
+
+// PrintSuccessf prints a formatted success message, unless the level is quiet.
 func PrintSuccessf(format string, a ...interface{}) {
-	_, _ = fmt.Fprintf(os.Stdout, Success(format)+"\n", a...)
+	if enabled(LevelNormal) {
+		_, _ = fmt.Fprintln(outWriter(), Success(render(i18n.Sprintf(format, a...))))
+	}
 }
 
 // PrintErrorf prints a formatted error message.
 func PrintErrorf(format string, a ...interface{}) {
-	_, _ = fmt.Fprintf(os.Stderr, Error(format)+"\n", a...)
+	_, _ = fmt.Fprintln(errWriter(), Error(render(i18n.Sprintf(format, a...))))
 }
 
 // PrintWarningf prints a formatted warning message.
 func PrintWarningf(format string, a ...interface{}) {
-	_, _ = fmt.Fprintf(os.Stdout, Warning(format)+"\n", a...)
+	_, _ = fmt.Fprintln(outWriter(), Warning(render(i18n.Sprintf(format, a...))))
 }
 
-// PrintInfof prints a formatted info message.
+// PrintInfof prints a formatted info message, unless the level is quiet.
 func PrintInfof(format string, a ...interface{}) {
-	_, _ = fmt.Fprintf(os.Stdout, Info(format)+"\n", a...)
+	if enabled(LevelNormal) {
+		_, _ = fmt.Fprintln(outWriter(), Info(render(i18n.Sprintf(format, a...))))
+	}
+}
+
+// PrintVerbosef prints a formatted detail message at the verbose level only.
+func PrintVerbosef(format string, a ...interface{}) {
+	if enabled(LevelVerbose) {
+		_, _ = fmt.Fprintln(outWriter(), render(i18n.Sprintf(format, a...)))
+	}
 }
 
-// Printf prints a formatted message.
+// Printf prints a formatted message. Results such as tables are printed at every level.
 func Printf(format string, a ...interface{}) {
-	_, _ = fmt.Fprintf(os.Stdout, format+"\n", a...)
+	_, _ = fmt.Fprintln(outWriter(), render(i18n.Sprintf(format, a...)))
 }
 
 // Fatalf prints an error message and exits with code 1.
@@ -59,7 +118,7 @@ func Fatalf(format string, a ...interface{}) {
 
 // Prompt asks the user for input with a colored prompt
 func Prompt(prompt string) string {
-	fmt.Print(Info(prompt + ": "))
+	_, _ = fmt.Fprint(outWriter(), Info(render(i18n.T(prompt)+": ")))
 	var input string
 	_, _ = fmt.Scanln(&input)
 	return input
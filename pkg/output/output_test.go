@@ -10,7 +10,9 @@
 package output
 
 import (
+	"bytes"
 	"errors"
+	"strings"
 	"testing"
 
 	ccmderrors "github.com/gifflet/ccmd/pkg/errors"
@@ -58,3 +60,22 @@ func TestPrintError(t *testing.T) {
 		})
 	}
 }
+
+func TestSetOutput(t *testing.T) {
+	var out, errOut bytes.Buffer
+
+	restore := SetOutput(&out, &errOut)
+	PrintInfof("info %d", 1)
+	PrintErrorf("failure")
+	restore()
+
+	if !strings.Contains(out.String(), "info 1") {
+		t.Errorf("expected info on the redirected writer, got %q", out.String())
+	}
+	if !strings.Contains(errOut.String(), "failure") {
+		t.Errorf("expected error on the redirected writer, got %q", errOut.String())
+	}
+	if outWriter() == &out {
+		t.Error("writers were not restored")
+	}
+}
@@ -48,20 +48,23 @@ func (p *ProgressBar) Increment() {
 func (p *ProgressBar) Complete() {
 	p.current = p.total
 	p.render()
-	fmt.Println() // New line after completion
+	if enabled(LevelNormal) {
+		_, _ = fmt.Fprintln(outWriter()) // New line after completion
+	}
 }
 
 func (p *ProgressBar) render() {
-	if p.total == 0 {
+	if p.total == 0 || !enabled(LevelNormal) {
 		return
 	}
 
 	percent := float64(p.current) / float64(p.total)
 	filled := int(percent * float64(p.width))
 
-	bar := strings.Repeat("█", filled) + strings.Repeat("░", p.width-filled)
+	theme := CurrentTheme()
+	bar := strings.Repeat(theme.BarFilled, filled) + strings.Repeat(theme.BarEmpty, p.width-filled)
 
-	fmt.Printf("\r%s [%s] %d/%d (%.0f%%)",
+	_, _ = fmt.Fprintf(outWriter(), "\r%s [%s] %d/%d (%.0f%%)",
 		p.message,
 		Info(bar),
 		p.current,
@@ -12,10 +12,12 @@ package output
 
 import (
 	"fmt"
+	"os"
 	"time"
 )
 
-// Spinner provides a simple loading indicator
+// Spinner provides a simple loading indicator. It animates on terminals only; elsewhere,
+// such as in CI logs, the message is printed once.
 type Spinner struct {
 	message string
 	chars   []string
@@ -24,11 +26,12 @@ type Spinner struct {
 	done    chan bool
 }
 
-// NewSpinner creates a new spinner with the given message
+// NewSpinner creates a new spinner with the given message, using the frames of the
+// current theme
 func NewSpinner(message string) *Spinner {
 	return &Spinner{
 		message: message,
-		chars:   []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"},
+		chars:   CurrentTheme().Spinner,
 		delay:   100 * time.Millisecond,
 		done:    make(chan bool),
 	}
@@ -36,6 +39,14 @@ func NewSpinner(message string) *Spinner {
 
 // Start begins the spinner animation
 func (s *Spinner) Start() {
+	if !enabled(LevelNormal) {
+		return
+	}
+	if !isTerminal(outWriter()) || os.Getenv("TERM") == "dumb" {
+		PrintInfof("%s", s.message)
+		return
+	}
+
 	s.active = true
 	go func() {
 		i := 0
@@ -44,7 +55,7 @@ func (s *Spinner) Start() {
 			case <-s.done:
 				return
 			default:
-				fmt.Printf("\r%s %s ", Info(s.chars[i]), s.message)
+				_, _ = fmt.Fprintf(outWriter(), "\r%s %s ", Info(s.chars[i]), s.message)
 				i = (i + 1) % len(s.chars)
 				time.Sleep(s.delay)
 			}
@@ -54,11 +65,12 @@ func (s *Spinner) Start() {
 
 // Stop stops the spinner and clears the line
 func (s *Spinner) Stop() {
-	if s.active {
-		s.done <- true
-		s.active = false
+	if !s.active {
+		return
 	}
-	fmt.Print("\r\033[K") // Clear the line
+	s.done <- true
+	s.active = false
+	_, _ = fmt.Fprint(outWriter(), "\r\033[K") // Clear the line
 }
 
 // Success stops the spinner and shows a success message
@@ -0,0 +1,181 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package output
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// Table formats
+const (
+	FormatTable = "table" // aligned columns for terminals
+	FormatCSV   = "csv"   // comma-separated values with a header row
+	FormatTSV   = "tsv"   // tab-separated values with a header row
+)
+
+// Status cells colored by Column.Status
+const (
+	StatusOK     = "OK"
+	StatusBroken = "BROKEN"
+	StatusUpdate = "UPDATE"
+)
+
+// statusColors colors the known status values; other values are printed as they are
+var statusColors = map[string]func(a ...interface{}) string{
+	StatusOK:     Success,
+	StatusBroken: Error,
+	StatusUpdate: Warning,
+}
+
+// Column describes one column of a Table
+type Column struct {
+	Header string
+	// MaxWidth truncates longer cells with an ellipsis; 0 never truncates
+	MaxWidth int
+	Right    bool // right-align, for numbers
+	Status   bool // color OK, BROKEN and UPDATE cells
+}
+
+// Table renders rows as aligned columns sized to their content, or as CSV or TSV for
+// scripts and spreadsheets
+type Table struct {
+	columns []Column
+	rows    [][]string
+}
+
+// NewTable creates a table with the given columns
+func NewTable(columns ...Column) *Table {
+	return &Table{columns: columns}
+}
+
+// AddRow appends a row. Missing cells are empty and extra cells are dropped.
+func (t *Table) AddRow(cells ...string) {
+	row := make([]string, len(t.columns))
+	copy(row, cells)
+	t.rows = append(t.rows, row)
+}
+
+// Len returns the number of rows
+func (t *Table) Len() int {
+	return len(t.rows)
+}
+
+// Print writes the table to the output in the given format, FormatTable when empty.
+// Tables are results and are printed at every level.
+func (t *Table) Print(format string) error {
+	return t.Write(outWriter(), format)
+}
+
+// Write writes the table to w in the given format, FormatTable when empty
+func (t *Table) Write(w io.Writer, format string) error {
+	switch format {
+	case "", FormatTable:
+		t.writeAligned(w)
+		return nil
+	case FormatCSV:
+		return t.writeSeparated(w, ',')
+	case FormatTSV:
+		return t.writeSeparated(w, '\t')
+	default:
+		return fmt.Errorf("unknown table format %q (expected table, csv or tsv)", format)
+	}
+}
+
+// TableFormat returns the format chosen by --csv and --tsv flags
+func TableFormat(csv, tsv bool) string {
+	switch {
+	case csv:
+		return FormatCSV
+	case tsv:
+		return FormatTSV
+	default:
+		return FormatTable
+	}
+}
+
+// writeAligned writes the header, a rule and the rows, each column as wide as its widest
+// cell up to MaxWidth. Cells are rendered for the theme before they are measured, and
+// padded before they are colored, so neither shifts the columns.
+func (t *Table) writeAligned(w io.Writer) {
+	rendered := make([][]string, len(t.rows))
+	widths := make([]int, len(t.columns))
+	for i, col := range t.columns {
+		widths[i] = utf8.RuneCountInString(col.Header)
+	}
+	for r, row := range t.rows {
+		rendered[r] = make([]string, len(row))
+		for i, col := range t.columns {
+			rendered[r][i] = truncate(render(row[i]), col.MaxWidth)
+			widths[i] = max(widths[i], utf8.RuneCountInString(rendered[r][i]))
+		}
+	}
+
+	cells := make([]string, len(t.columns))
+	for i, col := range t.columns {
+		cells[i] = pad(col.Header, widths[i], col.Right)
+	}
+	header := strings.TrimRight(strings.Join(cells, "  "), " ")
+	_, _ = fmt.Fprintln(w, header)
+	_, _ = fmt.Fprintln(w, strings.Repeat("-", utf8.RuneCountInString(header)))
+
+	for r, row := range t.rows {
+		for i, col := range t.columns {
+			cells[i] = pad(rendered[r][i], widths[i], col.Right)
+			if paint, ok := statusColors[row[i]]; ok && col.Status {
+				cells[i] = paint(cells[i])
+			}
+		}
+		_, _ = fmt.Fprintln(w, strings.TrimRight(strings.Join(cells, "  "), " "))
+	}
+}
+
+// writeSeparated writes the header and the rows as CSV with the given separator, with
+// cells neither truncated nor colored
+func (t *Table) writeSeparated(w io.Writer, separator rune) error {
+	writer := csv.NewWriter(w)
+	writer.Comma = separator
+
+	header := make([]string, len(t.columns))
+	for i, col := range t.columns {
+		header[i] = col.Header
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	if err := writer.WriteAll(t.rows); err != nil {
+		return err
+	}
+	return writer.Error()
+}
+
+// truncate shortens s to width runes, ending it with the theme's ellipsis; width 0 keeps s
+func truncate(s string, width int) string {
+	if width <= 0 || utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	ellipsis := []rune(render("…"))
+	if width <= len(ellipsis) {
+		return string(ellipsis[:width])
+	}
+	return string([]rune(s)[:width-len(ellipsis)]) + string(ellipsis)
+}
+
+// pad fills s with spaces to width runes
+func pad(s string, width int, right bool) string {
+	fill := strings.Repeat(" ", max(0, width-utf8.RuneCountInString(s)))
+	if right {
+		return fill + s
+	}
+	return s + fill
+}
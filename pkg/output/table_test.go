@@ -0,0 +1,77 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package output
+
+import (
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testTable() *Table {
+	table := NewTable(
+		Column{Header: "NAME"},
+		Column{Header: "DESCRIPTION", MaxWidth: 12},
+		Column{Header: "SIZE", Right: true},
+		Column{Header: "STATUS", Status: true},
+	)
+	table.AddRow("review", "Reviews pull requests", "1.2 KB", StatusOK)
+	table.AddRow("lint", "Lints, \"fast\"", "900 B", StatusBroken)
+	return table
+}
+
+func TestTable(t *testing.T) {
+	buf := useRenderer(t, ThemeUnicode, LevelQuiet)
+
+	require.NoError(t, testTable().Print(""))
+	assert.Equal(t, ""+
+		"NAME    DESCRIPTION     SIZE  STATUS\n"+
+		"------------------------------------\n"+
+		"review  Reviews pul…  1.2 KB  OK\n"+
+		"lint    Lints, \"fas…   900 B  BROKEN\n", buf.String())
+}
+
+func TestTableASCIIEllipsis(t *testing.T) {
+	buf := useRenderer(t, ThemeASCII, LevelNormal)
+
+	require.NoError(t, testTable().Print(FormatTable))
+	assert.Contains(t, buf.String(), "review  Reviews p...  1.2 KB  OK\n")
+}
+
+func TestTableStatusColors(t *testing.T) {
+	buf := useRenderer(t, ThemeUnicode, LevelNormal)
+	color.NoColor = false
+
+	table := NewTable(Column{Header: "STATUS", Status: true}, Column{Header: "NOTE"})
+	table.AddRow(StatusUpdate, "UPDATE")
+	require.NoError(t, table.Print(FormatTable))
+	assert.Contains(t, buf.String(), Warning("UPDATE")+"  UPDATE\n")
+}
+
+func TestTableSeparated(t *testing.T) {
+	buf := useRenderer(t, ThemeUnicode, LevelNormal)
+
+	require.NoError(t, testTable().Print(FormatCSV))
+	assert.Equal(t, ""+
+		"NAME,DESCRIPTION,SIZE,STATUS\n"+
+		"review,Reviews pull requests,1.2 KB,OK\n"+
+		"lint,\"Lints, \"\"fast\"\"\",900 B,BROKEN\n", buf.String())
+
+	buf.Reset()
+	require.NoError(t, testTable().Print(TableFormat(false, true)))
+	assert.Equal(t, ""+
+		"NAME\tDESCRIPTION\tSIZE\tSTATUS\n"+
+		"review\tReviews pull requests\t1.2 KB\tOK\n"+
+		"lint\t\"Lints, \"\"fast\"\"\"\t900 B\tBROKEN\n", buf.String())
+
+	assert.Error(t, testTable().Print("xml"))
+}
@@ -0,0 +1,179 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package output
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/fatih/color"
+	"github.com/mattn/go-isatty"
+)
+
+// Theme names
+const (
+	// ThemeAuto picks ThemeASCII on dumb terminals and ThemeUnicode elsewhere
+	ThemeAuto = "auto"
+	// ThemeUnicode uses check marks, braille spinners and block progress bars
+	ThemeUnicode = "unicode"
+	// ThemeASCII uses ASCII only, for dumb terminals, Windows consoles and log aggregators
+	ThemeASCII = "ascii"
+)
+
+// Level controls which messages are printed
+type Level int
+
+const (
+	// LevelQuiet prints warnings, errors and command results only
+	LevelQuiet Level = iota - 1
+	// LevelNormal adds progress and success messages
+	LevelNormal
+	// LevelVerbose adds the details printed with PrintVerbosef
+	LevelVerbose
+)
+
+// Theme is the set of glyphs the renderer uses
+type Theme struct {
+	Name      string
+	Spinner   []string // spinner frames
+	BarFilled string   // progress bar glyphs
+	BarEmpty  string
+
+	// glyphs replaces marks such as ✓ in messages; nil keeps them
+	glyphs *strings.Replacer
+}
+
+var themes = map[string]Theme{
+	ThemeUnicode: {
+		Name:      ThemeUnicode,
+		Spinner:   []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"},
+		BarFilled: "█",
+		BarEmpty:  "░",
+	},
+	ThemeASCII: {
+		Name:      ThemeASCII,
+		Spinner:   []string{"|", "/", "-", `\`},
+		BarFilled: "#",
+		BarEmpty:  "-",
+		glyphs: strings.NewReplacer(
+			"✓", "[ok]", "✗", "[x]", "⚠", "[!]", "→", "->", "←", "<-", "•", "*",
+			"…", "...", "—", "-", "–", "-", "█", "#", "░", "-", "│", "|", "─", "-",
+			"├", "+", "└", "`",
+		),
+	},
+}
+
+// Renderer state, changed by SetTheme and SetLevel
+var (
+	renderMu     sync.RWMutex
+	currentTheme = themes[ThemeUnicode]
+	currentLevel = LevelNormal
+)
+
+func init() {
+	if os.Getenv("TERM") == "dumb" {
+		currentTheme = themes[ThemeASCII]
+	}
+}
+
+// Themes returns the names accepted by SetTheme
+func Themes() []string {
+	names := []string{ThemeAuto}
+	for name := range themes {
+		names = append(names, name)
+	}
+	sort.Strings(names[1:])
+	return names
+}
+
+// SetTheme selects the glyphs used for marks, spinners and progress bars. Empty and
+// ThemeAuto pick ThemeASCII when TERM is dumb.
+func SetTheme(name string) error {
+	if name == "" || name == ThemeAuto {
+		name = ThemeUnicode
+		if os.Getenv("TERM") == "dumb" {
+			name = ThemeASCII
+		}
+	}
+
+	theme, ok := themes[name]
+	if !ok {
+		return fmt.Errorf("unknown theme %q (valid: %s)", name, strings.Join(Themes(), ", "))
+	}
+
+	renderMu.Lock()
+	currentTheme = theme
+	renderMu.Unlock()
+	return nil
+}
+
+// CurrentTheme returns the active theme
+func CurrentTheme() Theme {
+	renderMu.RLock()
+	defer renderMu.RUnlock()
+	return currentTheme
+}
+
+// SetLevel sets which messages are printed
+func SetLevel(level Level) {
+	renderMu.Lock()
+	currentLevel = level
+	renderMu.Unlock()
+}
+
+// CurrentLevel returns the active message level
+func CurrentLevel() Level {
+	renderMu.RLock()
+	defer renderMu.RUnlock()
+	return currentLevel
+}
+
+// SetColor enables or disables colors: "always", "never", or "auto" to color
+// terminals only. NO_COLOR and dumb terminals disable colors in auto mode.
+func SetColor(mode string) {
+	switch mode {
+	case "never":
+		color.NoColor = true
+	case "always":
+		color.NoColor = false
+	default:
+		_, noColor := os.LookupEnv("NO_COLOR")
+		color.NoColor = noColor || os.Getenv("TERM") == "dumb" || !isTerminal(os.Stdout)
+	}
+}
+
+// render applies the theme to a message
+func render(s string) string {
+	if theme := CurrentTheme(); theme.glyphs != nil {
+		return theme.glyphs.Replace(s)
+	}
+	return s
+}
+
+// enabled reports whether messages of a level are printed
+func enabled(level Level) bool {
+	return CurrentLevel() >= level
+}
+
+// isTerminal reports whether w is an interactive terminal, where spinners animate
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	return ok && IsTerminal(f)
+}
+
+// IsTerminal reports whether f is an interactive terminal. Other character devices,
+// such as /dev/null, are not.
+func IsTerminal(f *os.File) bool {
+	return isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+}
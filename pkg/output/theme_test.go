@@ -0,0 +1,104 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package output
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// useRenderer captures output with the given theme and level, without colors
+func useRenderer(t *testing.T, theme string, level Level) *bytes.Buffer {
+	t.Helper()
+
+	prevTheme, prevLevel, prevNoColor := CurrentTheme(), CurrentLevel(), color.NoColor
+	t.Cleanup(func() {
+		require.NoError(t, SetTheme(prevTheme.Name))
+		SetLevel(prevLevel)
+		color.NoColor = prevNoColor
+	})
+
+	require.NoError(t, SetTheme(theme))
+	SetLevel(level)
+	SetColor("never")
+
+	var buf bytes.Buffer
+	t.Cleanup(SetOutput(&buf, &buf))
+	return &buf
+}
+
+func TestASCIITheme(t *testing.T) {
+	buf := useRenderer(t, ThemeASCII, LevelNormal)
+
+	PrintSuccessf("✓ %s installed", "review")
+	PrintErrorf("✗ failed")
+	Printf("  ⚠ broken → fix")
+	assert.Equal(t, "[ok] review installed\n[x] failed\n  [!] broken -> fix\n", buf.String())
+
+	buf.Reset()
+	bar := NewProgressBar(2, "Copying")
+	bar.width = 4
+	bar.Update(1)
+	assert.Contains(t, buf.String(), "[##--] 1/2")
+}
+
+func TestLevels(t *testing.T) {
+	buf := useRenderer(t, ThemeUnicode, LevelQuiet)
+
+	PrintInfof("progress")
+	PrintSuccessf("done")
+	PrintVerbosef("detail")
+	PrintWarningf("careful")
+	Printf("result")
+	assert.Equal(t, "careful\nresult\n", buf.String())
+
+	buf.Reset()
+	SetLevel(LevelVerbose)
+	PrintVerbosef("detail %d", 1)
+	assert.Equal(t, "detail 1\n", buf.String())
+}
+
+func TestSpinnerWithoutTerminal(t *testing.T) {
+	buf := useRenderer(t, ThemeUnicode, LevelNormal)
+
+	spinner := NewSpinner("Fetching...")
+	spinner.Start()
+	spinner.Success("Fetched")
+	assert.Equal(t, "Fetching...\n✓ Fetched\n", buf.String(), "no animation or control codes outside a terminal")
+}
+
+func TestSetTheme(t *testing.T) {
+	useRenderer(t, ThemeUnicode, LevelNormal)
+
+	assert.Equal(t, []string{ThemeAuto, ThemeASCII, ThemeUnicode}, Themes())
+	assert.Error(t, SetTheme("fancy"))
+
+	t.Setenv("TERM", "dumb")
+	require.NoError(t, SetTheme(ThemeAuto))
+	assert.Equal(t, ThemeASCII, CurrentTheme().Name)
+}
+
+func TestIsTerminal(t *testing.T) {
+	// /dev/null is a character device, but not a terminal
+	null, err := os.Open(os.DevNull)
+	require.NoError(t, err)
+	defer null.Close()
+	assert.False(t, IsTerminal(null))
+
+	file, err := os.CreateTemp(t.TempDir(), "out")
+	require.NoError(t, err)
+	defer file.Close()
+	assert.False(t, IsTerminal(file))
+}
@@ -0,0 +1,441 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+// Package repospec parses the repository specs accepted wherever ccmd names a command
+// source: on the command line, in ccmd.yaml and in ccmd-lock.yaml.
+//
+// The grammar, informally:
+//
+//	spec      = source [ "//" command ] [ "@" version ]
+//	source    = url | scp | local | shorthand
+//	url       = scheme "://" [ user "@" ] host [ ":" port ] "/" path
+//	scp       = [ user "@" ] host ":" path      host has a dot or a user, or is localhost
+//	local     = ( "/" | "./" | "../" ) path
+//	shorthand = [ alias ":" ] path | host "/" owner "/" path      host has a dot
+//	path      = segment *( "/" segment ) [ ".git" ]
+//
+// The version starts at the first "@" that follows a "/" of the source, so scp paths
+// such as git@host:user@company/repo keep their "@" and versions may contain "/"
+// (feat/x) and, as constraints, spaces (>=1.0 <2.0). When the source ends in ".git",
+// the version starts right after it. Host names are compared in lower case; paths keep
+// their case and may be nested (group/subgroup/project). Remote sources are ASCII: a
+// look-alike such as the fullwidth "＠" is reported rather than taken as part of the path.
+package repospec
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+const (
+	// CommandSeparator separates a repository from one of its commands: owner/repo//command
+	CommandSeparator = "//"
+	// VersionSeparator separates a source from the version requested
+	VersionSeparator = "@"
+	// MaxLength is the longest spec accepted, in bytes. Longer input is a mistake, such as
+	// a pasted file, and is rejected before it reaches git.
+	MaxLength = 1024
+
+	gitSuffix = ".git"
+)
+
+// Form is the way a spec names its repository
+type Form int
+
+const (
+	// FormShorthand is owner/repo, host.tld/owner/repo or alias:owner/repo
+	FormShorthand Form = iota
+	// FormURL is scheme://[user@]host[:port]/path
+	FormURL
+	// FormSCP is the scp-like syntax of git, [user@]host:path
+	FormSCP
+	// FormLocal is a path on disk
+	FormLocal
+)
+
+// String returns the name of the form
+func (f Form) String() string {
+	switch f {
+	case FormURL:
+		return "url"
+	case FormSCP:
+		return "scp"
+	case FormLocal:
+		return "local"
+	default:
+		return "shorthand"
+	}
+}
+
+// Spec is a parsed repository spec
+type Spec struct {
+	Form   Form
+	Scheme string // URL form only, lower case
+	User   string // user of the URL and scp forms, such as git
+	Host   string // lower case; for shorthands only when named or resolved
+	Port   string
+	Alias  string // alias prefix of a shorthand such as gitlab:group/project, lower case
+	// Path is the repository below its host, without surrounding slashes or .git suffix.
+	// For local repositories it is the path on disk without ./ and surrounding slashes.
+	Path    string
+	Command string // command of a multi-command repository
+	Version string // tag, branch, commit or version constraint
+	// Source is the repository as written, without command and version
+	Source string
+}
+
+// HostPolicy decides whether repositories may come from a host. It returns nil to accept
+// the host.
+type HostPolicy func(host string) error
+
+// AllowHosts returns a policy accepting only the given hosts, compared in lower case. With
+// no hosts it accepts any.
+func AllowHosts(hosts ...string) HostPolicy {
+	allowed := make(map[string]bool, len(hosts))
+	for _, host := range hosts {
+		if host = strings.ToLower(strings.TrimSpace(host)); host != "" {
+			allowed[host] = true
+		}
+	}
+	return func(host string) error {
+		if len(allowed) == 0 || allowed[strings.ToLower(host)] {
+			return nil
+		}
+		return errors.InvalidInput(fmt.Sprintf("host %s is not allowed", host))
+	}
+}
+
+// Options resolves the hosts of shorthands while parsing
+type Options struct {
+	// DefaultHost is the host of shorthands that name none
+	DefaultHost string
+	// Aliases maps lower case alias prefixes such as gitlab to hosts. When set, an alias
+	// it does not list is an error.
+	Aliases map[string]string
+	// Policy, when set, rejects specs whose host it does not accept. Local repositories
+	// have no host and are not checked.
+	Policy HostPolicy
+}
+
+// Parse parses a repository spec without resolving the host of shorthands
+func Parse(spec string) (Spec, error) {
+	return ParseWith(spec, Options{})
+}
+
+// ParseWith parses a repository spec, gives shorthands their host from the alias or the
+// default host, and applies the host policy
+func ParseWith(spec string, opts Options) (Spec, error) {
+	s, err := parse(spec)
+	if err != nil {
+		return Spec{}, err
+	}
+
+	if s.Form == FormShorthand && s.Host == "" {
+		if s.Alias != "" {
+			host, ok := opts.Aliases[s.Alias]
+			if !ok && opts.Aliases != nil {
+				return Spec{}, errors.InvalidInput(fmt.Sprintf("unknown host alias %q in %q", s.Alias, spec))
+			}
+			s.Host = strings.ToLower(host)
+		} else {
+			s.Host = strings.ToLower(opts.DefaultHost)
+		}
+	}
+
+	if opts.Policy != nil && s.Form != FormLocal && s.Scheme != "file" {
+		if err := opts.Policy(s.Host); err != nil {
+			return Spec{}, err
+		}
+	}
+	return s, nil
+}
+
+func parse(spec string) (Spec, error) {
+	if spec == "" {
+		return Spec{}, errors.InvalidInput("empty repository spec")
+	}
+	if len(spec) > MaxLength {
+		return Spec{}, errors.InvalidInput(fmt.Sprintf("repository spec is %d bytes long; the limit is %d", len(spec), MaxLength))
+	}
+	for _, r := range spec {
+		if unicode.IsControl(r) || r == unicode.ReplacementChar {
+			return Spec{}, errors.InvalidInput(fmt.Sprintf("repository spec %q contains %q", spec, r))
+		}
+	}
+
+	var s Spec
+	start := 0 // where the path begins, after scheme, authority or scp host
+
+	switch {
+	case isURL(spec):
+		idx := strings.Index(spec, "://")
+		s.Form, s.Scheme = FormURL, strings.ToLower(spec[:idx])
+		authority := spec[idx+3:]
+		slash := strings.Index(authority, "/")
+		if slash == -1 {
+			return Spec{}, errors.InvalidInput(fmt.Sprintf("repository URL %q has no path", spec))
+		}
+		authority = authority[:slash]
+		start = idx + 3 + slash
+		if at := strings.LastIndex(authority, "@"); at != -1 {
+			s.User, authority = authority[:at], authority[at+1:]
+		}
+		s.Host, s.Port = splitPort(authority)
+		if s.Host == "" && s.Scheme != "file" {
+			return Spec{}, errors.InvalidInput(fmt.Sprintf("repository URL %q has no host", spec))
+		}
+
+	case isLocal(spec):
+		s.Form = FormLocal
+
+	default:
+		colon := strings.Index(spec, ":")
+		slash := strings.Index(spec, "/")
+		if colon > 0 && (slash == -1 || colon < slash) {
+			prefix := spec[:colon]
+			start = colon + 1
+			if at := strings.LastIndex(prefix, "@"); at != -1 || strings.Contains(prefix, ".") || prefix == "localhost" {
+				s.Form = FormSCP
+				if at != -1 {
+					s.User, prefix = prefix[:at], prefix[at+1:]
+				}
+				s.Host = strings.ToLower(prefix)
+				if s.Host == "" || at == 0 {
+					return Spec{}, errors.InvalidInput(fmt.Sprintf("repository %q has no host or user", spec))
+				}
+			} else {
+				s.Form, s.Alias = FormShorthand, strings.ToLower(prefix)
+			}
+			break
+		}
+
+		s.Form = FormShorthand
+		if first, rest, ok := strings.Cut(spec, "/"); ok && strings.Contains(first, ".") && strings.Contains(rest, "/") {
+			s.Host = strings.ToLower(first)
+			start = len(first) + 1
+		}
+	}
+
+	rest := spec[start:]
+	if at := versionIndex(rest); at != -1 {
+		s.Version = rest[at+1:]
+		rest = rest[:at]
+		if s.Version == "" {
+			return Spec{}, errors.InvalidInput(fmt.Sprintf("repository spec %q has an empty version", spec))
+		}
+	}
+
+	// The path of a URL starts with a slash that is not a command separator
+	offset := 0
+	if s.Form == FormURL || s.Form == FormLocal {
+		offset = len(rest) - len(strings.TrimLeft(rest, "/"))
+	}
+	if idx := strings.Index(rest[offset:], CommandSeparator); idx != -1 {
+		idx += offset
+		s.Command = rest[idx+len(CommandSeparator):]
+		rest = rest[:idx]
+		if s.Command == "" || strings.Contains(s.Command, "/") {
+			return Spec{}, errors.InvalidInput(fmt.Sprintf("repository spec %q has an invalid command %q", spec, s.Command))
+		}
+	}
+
+	// Version constraints may contain spaces, repositories may not
+	if strings.IndexFunc(spec[:start]+rest, unicode.IsSpace) != -1 {
+		return Spec{}, errors.InvalidInput(fmt.Sprintf("unexpected space in repository %q", spec))
+	}
+
+	s.Source = spec[:start] + rest
+	if s.Form != FormLocal && s.Scheme != "file" {
+		if i := NonASCIIIndex(s.Source); i != -1 {
+			return Spec{}, errors.InvalidInput(nonASCIIMessage(s.Source, i))
+		}
+	}
+	path, err := cleanPath(rest, s.Form)
+	if err != nil {
+		return Spec{}, errors.InvalidInput(fmt.Sprintf("repository spec %q: %v", spec, err))
+	}
+	s.Path = path
+
+	return s, nil
+}
+
+// lookalikes maps characters often pasted in place of the separators of a spec
+var lookalikes = map[rune]rune{
+	'＠': '@', // fullwidth commercial at
+	'﹫': '@', // small commercial at
+	'／': '/', // fullwidth solidus
+	'∕': '/', // division slash
+	'⁄': '/', // fraction slash
+	'：': ':', // fullwidth colon
+}
+
+// NonASCIIIndex returns the byte index of the first character of s outside ASCII, or -1
+func NonASCIIIndex(s string) int {
+	return strings.IndexFunc(s, func(r rune) bool { return r > unicode.MaxASCII })
+}
+
+// nonASCIIMessage describes the character at byte i of a remote source
+func nonASCIIMessage(source string, i int) string {
+	r, _ := utf8.DecodeRuneInString(source[i:])
+	if ascii, ok := lookalikes[r]; ok {
+		return fmt.Sprintf("repository %q contains %q (%U) at byte %d; use an ASCII %q", source, r, r, i, ascii)
+	}
+	return fmt.Sprintf("repository %q contains the non-ASCII character %q (%U) at byte %d", source, r, r, i)
+}
+
+// isURL reports whether spec starts with a scheme followed by ://
+func isURL(spec string) bool {
+	idx := strings.Index(spec, "://")
+	if idx < 1 {
+		return false
+	}
+	for i, r := range spec[:idx] {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+		case i > 0 && (r >= '0' && r <= '9' || r == '+' || r == '-' || r == '.'):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func isLocal(spec string) bool {
+	return spec == "." || spec == ".." || strings.HasPrefix(spec, "/") ||
+		strings.HasPrefix(spec, "./") || strings.HasPrefix(spec, "../")
+}
+
+// splitPort splits host:port, when what follows the last colon is a port number
+func splitPort(authority string) (host, port string) {
+	if idx := strings.LastIndex(authority, ":"); idx != -1 && !strings.Contains(authority[idx:], "]") {
+		port := authority[idx+1:]
+		if strings.Trim(port, "0123456789") == "" {
+			return strings.ToLower(authority[:idx]), port
+		}
+	}
+	return strings.ToLower(authority), ""
+}
+
+// versionIndex returns the index of the "@" starting the version in the path part of a
+// spec, or -1. It is the one right after a .git suffix, or else the first that follows a
+// slash; a path without slashes takes its first.
+func versionIndex(rest string) int {
+	if idx := strings.LastIndex(rest, gitSuffix+VersionSeparator); idx != -1 {
+		return idx + len(gitSuffix)
+	}
+
+	slash := strings.Index(rest, "/")
+	if slash == -1 {
+		return strings.Index(rest, VersionSeparator)
+	}
+	if idx := strings.Index(rest[slash:], VersionSeparator); idx != -1 {
+		return slash + idx
+	}
+	return -1
+}
+
+// cleanPath trims the slashes and .git suffix of a repository path and checks its
+// segments. Only local paths may go up with "..".
+func cleanPath(path string, form Form) (string, error) {
+	if form == FormLocal {
+		for strings.HasPrefix(path, "./") {
+			path = path[2:]
+		}
+	}
+	path = strings.TrimSuffix(strings.Trim(path, "/"), gitSuffix)
+	path = strings.TrimSuffix(path, "/")
+	if path == "" || path == "." {
+		if form == FormLocal {
+			return ".", nil
+		}
+		return "", fmt.Errorf("empty repository path")
+	}
+
+	for _, segment := range strings.Split(path, "/") {
+		switch segment {
+		case "":
+			return "", fmt.Errorf("empty segment in %q", path)
+		case ".", "..":
+			if form != FormLocal {
+				return "", fmt.Errorf("relative segment in %q", path)
+			}
+		}
+	}
+	return path, nil
+}
+
+// String returns the spec as written: source, command and version
+func (s Spec) String() string {
+	spec := s.Source
+	if s.Command != "" {
+		spec += CommandSeparator + s.Command
+	}
+	if s.Version != "" {
+		spec += VersionSeparator + s.Version
+	}
+	return spec
+}
+
+// Repository returns the source with its command, without the version
+func (s Spec) Repository() string {
+	if s.Command == "" {
+		return s.Source
+	}
+	return s.Source + CommandSeparator + s.Command
+}
+
+// RepoPath returns the path of the repository, followed by //command when the spec
+// names one. It identifies a repository across URL forms.
+func (s Spec) RepoPath() string {
+	if s.Command == "" {
+		return s.Path
+	}
+	return s.Path + CommandSeparator + s.Command
+}
+
+// Owner returns the first segment of the path: the owner, organization or group
+func (s Spec) Owner() string {
+	owner, _, ok := strings.Cut(s.Path, "/")
+	if !ok {
+		return ""
+	}
+	return owner
+}
+
+// Name returns the last segment of the path, the repository's own name
+func (s Spec) Name() string {
+	return s.Path[strings.LastIndex(s.Path, "/")+1:]
+}
+
+// Remote reports whether the repository is reached over the network
+func (s Spec) Remote() bool {
+	return s.Form != FormLocal && s.Scheme != "file"
+}
+
+// Shorthand returns the shortest spec naming the same repository and command, without
+// the version: owner/repo on the default host, host.tld/owner/repo on others. Local
+// repositories, hosts with a port or without a dot, and single segment paths, which
+// shorthands cannot express, keep their source.
+func (s Spec) Shorthand(defaultHost string) string {
+	switch {
+	case !s.Remote() || s.Port != "":
+		return s.Repository()
+	case s.Form == FormShorthand && s.Host == "" && s.Alias == "",
+		s.Host != "" && strings.EqualFold(s.Host, defaultHost) && strings.Contains(s.Path, "/"):
+		return s.RepoPath()
+	case strings.Contains(s.Host, ".") && strings.Contains(s.Path, "/"):
+		return s.Host + "/" + s.RepoPath()
+	default:
+		return s.Repository()
+	}
+}
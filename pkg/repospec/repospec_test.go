@@ -0,0 +1,204 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package repospec
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		spec string
+		want Spec
+	}{
+		{"owner/repo", Spec{Form: FormShorthand, Path: "owner/repo", Source: "owner/repo"}},
+		{"owner/repo@v1.0.0", Spec{Form: FormShorthand, Path: "owner/repo", Version: "v1.0.0", Source: "owner/repo"}},
+		{"owner/repo//review@^1.0", Spec{Form: FormShorthand, Path: "owner/repo", Command: "review", Version: "^1.0", Source: "owner/repo"}},
+		{"owner/repo@>=1.0 <2.0", Spec{Form: FormShorthand, Path: "owner/repo", Version: ">=1.0 <2.0", Source: "owner/repo"}},
+		{"owner/repo@feat/x", Spec{Form: FormShorthand, Path: "owner/repo", Version: "feat/x", Source: "owner/repo"}},
+		{"hello@1.0", Spec{Form: FormShorthand, Path: "hello", Version: "1.0", Source: "hello"}},
+		{"codeberg.org/owner/repo", Spec{Form: FormShorthand, Host: "codeberg.org", Path: "owner/repo", Source: "codeberg.org/owner/repo"}},
+		{"gl:group/sub/project", Spec{Form: FormShorthand, Alias: "gl", Path: "group/sub/project", Source: "gl:group/sub/project"}},
+		{
+			"https://token@GitHub.com:443/owner/repo.git@main",
+			Spec{Form: FormURL, Scheme: "https", User: "token", Host: "github.com", Port: "443", Path: "owner/repo",
+				Version: "main", Source: "https://token@GitHub.com:443/owner/repo.git"},
+		},
+		{
+			"https://gitlab.com/org/team/repo.git//lint",
+			Spec{Form: FormURL, Scheme: "https", Host: "gitlab.com", Path: "org/team/repo", Command: "lint",
+				Source: "https://gitlab.com/org/team/repo.git"},
+		},
+		{
+			"ssh://git@git.corp.com/team/repo.git",
+			Spec{Form: FormURL, Scheme: "ssh", User: "git", Host: "git.corp.com", Path: "team/repo", Source: "ssh://git@git.corp.com/team/repo.git"},
+		},
+		{"file:///srv/repo", Spec{Form: FormURL, Scheme: "file", Path: "srv/repo", Source: "file:///srv/repo"}},
+		{
+			"git@github.com:user@company/repo.git@feature-branch",
+			Spec{Form: FormSCP, User: "git", Host: "github.com", Path: "user@company/repo", Version: "feature-branch",
+				Source: "git@github.com:user@company/repo.git"},
+		},
+		{
+			"git@github.com:gifflet/parallax@feat/optimize",
+			Spec{Form: FormSCP, User: "git", Host: "github.com", Path: "gifflet/parallax", Version: "feat/optimize",
+				Source: "git@github.com:gifflet/parallax"},
+		},
+		{"gitlab.com:group/project", Spec{Form: FormSCP, Host: "gitlab.com", Path: "group/project", Source: "gitlab.com:group/project"}},
+		{"./commands/review", Spec{Form: FormLocal, Path: "commands/review", Source: "./commands/review"}},
+		{"../shared/repo", Spec{Form: FormLocal, Path: "../shared/repo", Source: "../shared/repo"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := Parse(tt.spec)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.spec, got.String())
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"owner/repo@",
+		"owner/repo//",
+		"owner/repo//a/b",
+		"owner /repo",
+		"https://github.com",
+		"https:///owner/repo",
+		"owner/../repo",
+		"@host:owner/repo",
+		"owner/repo\x00",
+	} {
+		_, err := Parse(spec)
+		assert.ErrorIs(t, err, errors.ErrInvalidInput, "%q", spec)
+	}
+}
+
+func TestParseWith(t *testing.T) {
+	opts := Options{DefaultHost: "GitHub.com", Aliases: map[string]string{"gitlab": "gitlab.com"}}
+
+	spec, err := ParseWith("owner/repo", opts)
+	require.NoError(t, err)
+	assert.Equal(t, "github.com", spec.Host)
+
+	spec, err = ParseWith("gitlab:group/project", opts)
+	require.NoError(t, err)
+	assert.Equal(t, "gitlab.com", spec.Host)
+
+	_, err = ParseWith("corp:team/repo", opts)
+	assert.ErrorIs(t, err, errors.ErrInvalidInput)
+
+	opts.Policy = AllowHosts("gitlab.com")
+	_, err = ParseWith("owner/repo", opts)
+	assert.ErrorContains(t, err, "github.com is not allowed")
+	_, err = ParseWith("https://GitLab.com/group/project.git", opts)
+	assert.NoError(t, err)
+	_, err = ParseWith("./local/repo", opts)
+	assert.NoError(t, err, "local repositories have no host")
+
+	assert.NoError(t, AllowHosts()("anything.example"))
+}
+
+func TestSpecNames(t *testing.T) {
+	spec, err := Parse("git@gitlab.com:org/team/repo.git//lint@v1")
+	require.NoError(t, err)
+	assert.Equal(t, "org", spec.Owner())
+	assert.Equal(t, "repo", spec.Name())
+	assert.Equal(t, "org/team/repo//lint", spec.RepoPath())
+	assert.Equal(t, "git@gitlab.com:org/team/repo.git//lint", spec.Repository())
+	assert.True(t, spec.Remote())
+
+	spec, err = Parse("single")
+	require.NoError(t, err)
+	assert.Empty(t, spec.Owner())
+	assert.Equal(t, "single", spec.Name())
+}
+
+func TestShorthand(t *testing.T) {
+	tests := []struct {
+		spec string
+		want string
+	}{
+		{"https://github.com/owner/repo.git@v1", "owner/repo"},
+		{"git@github.com:owner/repo.git//review", "owner/repo//review"},
+		{"https://gitlab.com/org/team/repo.git", "gitlab.com/org/team/repo"},
+		{"gitlab:group/project", "gitlab:group/project"},
+		{"owner/repo", "owner/repo"},
+		{"https://localhost/owner/repo.git", "https://localhost/owner/repo.git"},
+		{"https://git.corp.com:8443/team/repo.git", "https://git.corp.com:8443/team/repo.git"},
+		{"file:///srv/repo", "file:///srv/repo"},
+		{"./repo", "./repo"},
+	}
+
+	for _, tt := range tests {
+		spec, err := Parse(tt.spec)
+		require.NoError(t, err, tt.spec)
+		assert.Equal(t, tt.want, spec.Shorthand("github.com"), tt.spec)
+	}
+}
+
+func FuzzParse(f *testing.F) {
+	for _, seed := range []string{
+		"owner/repo",
+		"owner/repo@v1.0.0",
+		"owner/repo//review@^1.0",
+		"owner/repo@>=1.0 <2.0",
+		"gl:group/sub/project",
+		"codeberg.org/owner/repo",
+		"https://token@github.com:443/owner/repo.git@main",
+		"ssh://git@git.corp.com/team/repo.git//lint",
+		"git@github.com:user@company/repo.git@feature-branch",
+		"file:///srv/repo",
+		"../shared/repo",
+		"a:@/.git@",
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, input string) {
+		spec, err := Parse(input)
+		if err != nil {
+			return
+		}
+
+		// A parsed spec is written back as it was given, and parses the same way again
+		if spec.String() != input {
+			t.Fatalf("Parse(%q).String() = %q", input, spec.String())
+		}
+		again, err := Parse(spec.String())
+		if err != nil {
+			t.Fatalf("Parse(%q) failed on its own output: %v", input, err)
+		}
+		if again != spec {
+			t.Fatalf("Parse(%q) = %+v, then %+v", input, spec, again)
+		}
+
+		if spec.Path == "" || strings.HasPrefix(spec.Path, "/") || strings.HasSuffix(spec.Path, "/") {
+			t.Fatalf("Parse(%q) has path %q", input, spec.Path)
+		}
+		if strings.Contains(spec.Command, "/") {
+			t.Fatalf("Parse(%q) has command %q", input, spec.Command)
+		}
+		if spec.Host != strings.ToLower(spec.Host) {
+			t.Fatalf("Parse(%q) has host %q", input, spec.Host)
+		}
+		_ = spec.Shorthand("github.com")
+		_ = spec.Name()
+	})
+}
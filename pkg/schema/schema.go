@@ -0,0 +1,230 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+// Package schema publishes the JSON Schema of ccmd command metadata and validates
+// decoded YAML or JSON documents against it.
+package schema
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// CommandV2 is the JSON Schema of command metadata, version 2
+//
+//go:embed command.v2.json
+var CommandV2 []byte
+
+// Schema is a JSON Schema document. Validate supports the keywords used by the ccmd
+// schemas: type, required, properties, items, enum, pattern and minLength.
+type Schema struct {
+	ID          string             `json:"$id,omitempty"`
+	Title       string             `json:"title,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Type        string             `json:"type,omitempty"`
+	Required    []string           `json:"required,omitempty"`
+	Properties  map[string]*Schema `json:"properties,omitempty"`
+	Items       *Schema            `json:"items,omitempty"`
+	Enum        []interface{}      `json:"enum,omitempty"`
+	Pattern     string             `json:"pattern,omitempty"`
+	MinLength   int                `json:"minLength,omitempty"`
+
+	pattern *regexp.Regexp
+}
+
+// Violation is a value that does not match the schema
+type Violation struct {
+	Path    string // dotted path of the value, e.g. tags[1]; empty for the document
+	Keyword string // schema keyword that failed, e.g. type or required
+	Message string
+}
+
+// String formats the violation as path: message
+func (v Violation) String() string {
+	if v.Path == "" {
+		return v.Message
+	}
+	return v.Path + ": " + v.Message
+}
+
+var (
+	commandOnce   sync.Once
+	commandSchema *Schema
+)
+
+// Command returns the parsed command metadata schema
+func Command() *Schema {
+	commandOnce.Do(func() {
+		s, err := Parse(CommandV2)
+		if err != nil {
+			panic(fmt.Sprintf("schema: invalid embedded command schema: %v", err))
+		}
+		commandSchema = s
+	})
+	return commandSchema
+}
+
+// Parse decodes a JSON Schema document and compiles its patterns
+func Parse(data []byte) (*Schema, error) {
+	var s Schema
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	if err := s.compile(); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (s *Schema) compile() error {
+	if s.Pattern != "" {
+		re, err := regexp.Compile(s.Pattern)
+		if err != nil {
+			return fmt.Errorf("pattern %q: %w", s.Pattern, err)
+		}
+		s.pattern = re
+	}
+	for name, prop := range s.Properties {
+		if err := prop.compile(); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	if s.Items != nil {
+		return s.Items.compile()
+	}
+	return nil
+}
+
+// PropertyNames returns the names of the properties defined at the top level, sorted
+func (s *Schema) PropertyNames() []string {
+	names := make([]string, 0, len(s.Properties))
+	for name := range s.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Validate checks a document decoded from YAML or JSON and returns its violations,
+// ordered by path. A nil result means the document is valid.
+func (s *Schema) Validate(doc interface{}) []Violation {
+	var violations []Violation
+	s.validate("", doc, &violations)
+	sort.SliceStable(violations, func(i, j int) bool {
+		return violations[i].Path < violations[j].Path
+	})
+	return violations
+}
+
+func (s *Schema) validate(path string, value interface{}, violations *[]Violation) {
+	report := func(keyword, format string, args ...interface{}) {
+		*violations = append(*violations, Violation{Path: path, Keyword: keyword, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if s.Type != "" && !hasType(value, s.Type) {
+		report("type", "expected %s, got %s", s.Type, typeName(value))
+		return
+	}
+
+	if len(s.Enum) > 0 && !inEnum(value, s.Enum) {
+		allowed := make([]string, len(s.Enum))
+		for i, v := range s.Enum {
+			allowed[i] = fmt.Sprintf("%q", fmt.Sprint(v))
+		}
+		report("enum", "must be one of %s, got %q", strings.Join(allowed, ", "), fmt.Sprint(value))
+	}
+
+	switch v := value.(type) {
+	case string:
+		if s.MinLength > 0 && utf8.RuneCountInString(v) < s.MinLength {
+			if s.MinLength == 1 {
+				report("minLength", "must not be empty")
+			} else {
+				report("minLength", "must be at least %d characters", s.MinLength)
+			}
+		}
+		if s.pattern != nil && !s.pattern.MatchString(v) {
+			report("pattern", "%q does not match %s", v, s.Pattern)
+		}
+	case []interface{}:
+		if s.Items != nil {
+			for i, item := range v {
+				s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item, violations)
+			}
+		}
+	case map[string]interface{}:
+		for _, name := range s.Required {
+			if _, ok := v[name]; !ok {
+				*violations = append(*violations, Violation{
+					Path:    joinPath(path, name),
+					Keyword: "required",
+					Message: "is required",
+				})
+			}
+		}
+		for name, prop := range s.Properties {
+			if child, ok := v[name]; ok {
+				prop.validate(joinPath(path, name), child, violations)
+			}
+		}
+	}
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func hasType(value interface{}, want string) bool {
+	got := typeName(value)
+	return got == want || (want == "number" && got == "integer")
+}
+
+// typeName returns the JSON Schema type of a decoded YAML or JSON value
+func typeName(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case int, int64, uint64:
+		return "integer"
+	case float64:
+		if v == float64(int64(v)) {
+			return "integer"
+		}
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return reflect.TypeOf(value).String()
+	}
+}
+
+func inEnum(value interface{}, enum []interface{}) bool {
+	for _, allowed := range enum {
+		if reflect.DeepEqual(value, allowed) {
+			return true
+		}
+	}
+	return false
+}
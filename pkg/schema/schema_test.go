@@ -0,0 +1,83 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package schema
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCommandSchema(t *testing.T) {
+	s := Command()
+	if !strings.HasSuffix(s.ID, "/command.v2.json") {
+		t.Errorf("unexpected schema id %q", s.ID)
+	}
+
+	names := strings.Join(s.PropertyNames(), ",")
+	for _, want := range []string{"name", "version", "entry", "tags", "commands"} {
+		if !strings.Contains(names, want) {
+			t.Errorf("schema has no %q property (got %s)", want, names)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	valid := map[string]interface{}{
+		"name":        "review",
+		"version":     "1.0.0",
+		"description": "Code review helper",
+		"author":      "acme",
+		"repository":  "github.com/acme/review",
+		"tags":        []interface{}{"git", "review"},
+	}
+	if violations := Command().Validate(valid); violations != nil {
+		t.Fatalf("expected no violations, got %v", violations)
+	}
+
+	invalid := map[string]interface{}{
+		"name":    "re:view",
+		"version": 1,
+		"type":    "script",
+		"tags":    []interface{}{"git", 3},
+		"author":  "",
+	}
+	var got []string
+	for _, v := range Command().Validate(invalid) {
+		got = append(got, v.Keyword+"@"+v.Path)
+	}
+	want := []string{
+		"minLength@author",
+		"required@description",
+		"pattern@name",
+		"required@repository",
+		"type@tags[1]",
+		"enum@type",
+		"type@version",
+	}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("violations = %v, want %v", got, want)
+	}
+}
+
+func TestViolationString(t *testing.T) {
+	v := Violation{Path: "tags[0]", Keyword: "type", Message: "expected string, got integer"}
+	if v.String() != "tags[0]: expected string, got integer" {
+		t.Errorf("unexpected string %q", v.String())
+	}
+	if (Violation{Message: "expected object, got string"}).String() != "expected object, got string" {
+		t.Error("a document-level violation should have no path prefix")
+	}
+}
+
+func TestParseRejectsInvalidPattern(t *testing.T) {
+	if _, err := Parse([]byte(`{"properties": {"name": {"pattern": "("}}}`)); err == nil {
+		t.Error("expected an error for an invalid pattern")
+	}
+}
@@ -0,0 +1,464 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+// Package telemetry records traces and metrics of ccmd operations and exports them to
+// an OpenTelemetry collector over OTLP/HTTP with JSON encoding. It is disabled until
+// Enable is called, and then only records what callers pass explicitly: span names,
+// durations, sizes, counts, hosts and error codes. Repository names, paths, file
+// contents and error messages are never recorded.
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+// DefaultEndpoint is the OTLP/HTTP endpoint of a collector running locally
+const DefaultEndpoint = "http://localhost:4318"
+
+// Environment variables of the OpenTelemetry specification that are honoured
+const (
+	EndpointEnv = "OTEL_EXPORTER_OTLP_ENDPOINT"
+	HeadersEnv  = "OTEL_EXPORTER_OTLP_HEADERS"
+)
+
+// scopeName identifies ccmd as the instrumentation scope of everything it exports
+const scopeName = "github.com/gifflet/ccmd"
+
+// Metric names
+const (
+	MetricDuration = "ccmd.operation.duration" // histogram of span durations in milliseconds
+	MetricFailures = "ccmd.operation.failures" // failed spans by error code
+	MetricBytes    = "ccmd.copy.bytes"         // bytes copied into the project
+)
+
+// durationBounds are the bucket boundaries of MetricDuration, in milliseconds
+var durationBounds = []float64{10, 50, 100, 250, 500, 1000, 2500, 5000, 10000, 30000, 60000}
+
+// Config configures the exporter
+type Config struct {
+	Endpoint string            // base URL of the collector; /v1/traces and /v1/metrics are appended
+	Headers  map[string]string // sent with every export, e.g. for authentication
+	Version  string            // ccmd version, exported as service.version
+	Client   *http.Client      // nil uses http.DefaultClient
+}
+
+// Attr is an attribute of a span or metric point
+type Attr struct {
+	Key   string
+	Value interface{} // string, int64 or bool
+}
+
+// String returns a string attribute
+func String(key, value string) Attr { return Attr{key, value} }
+
+// Int returns an integer attribute
+func Int(key string, value int64) Attr { return Attr{key, value} }
+
+// Bool returns a boolean attribute
+func Bool(key string, value bool) Attr { return Attr{key, value} }
+
+// recorder buffers the spans and aggregates the metrics of one invocation
+type recorder struct {
+	cfg   Config
+	start time.Time
+
+	mu      sync.Mutex
+	spans   []*Span
+	points  map[string]*point // by metric name and attributes
+	metrics map[string]string // unit by metric name
+}
+
+// point is the aggregate of one metric for one set of attributes
+type point struct {
+	metric  string
+	attrs   []Attr
+	count   int64
+	sum     float64
+	buckets []int64 // histograms only
+}
+
+var active atomic.Pointer[recorder]
+
+// Enable starts recording. Without an endpoint, OTEL_EXPORTER_OTLP_ENDPOINT or
+// DefaultEndpoint is used; OTEL_EXPORTER_OTLP_HEADERS adds to the headers.
+func Enable(cfg Config) {
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = os.Getenv(EndpointEnv)
+	}
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = DefaultEndpoint
+	}
+	cfg.Endpoint = strings.TrimRight(cfg.Endpoint, "/")
+	headers := ParseHeaders(os.Getenv(HeadersEnv))
+	for key, value := range cfg.Headers {
+		headers[key] = value
+	}
+	cfg.Headers = headers
+	if cfg.Client == nil {
+		cfg.Client = http.DefaultClient
+	}
+
+	active.Store(&recorder{
+		cfg:     cfg,
+		start:   time.Now(),
+		points:  make(map[string]*point),
+		metrics: make(map[string]string),
+	})
+}
+
+// Enabled reports whether telemetry is being recorded
+func Enabled() bool {
+	return active.Load() != nil
+}
+
+// Shutdown stops recording and exports what was recorded. Nothing is retried: a
+// collector that cannot be reached only loses this invocation's data.
+func Shutdown(ctx context.Context) error {
+	r := active.Swap(nil)
+	if r == nil {
+		return nil
+	}
+	return r.export(ctx)
+}
+
+// ParseHeaders parses headers in the OTEL_EXPORTER_OTLP_HEADERS format:
+// comma-separated key=value pairs with URL-encoded values
+func ParseHeaders(s string) map[string]string {
+	headers := make(map[string]string)
+	for _, pair := range strings.Split(s, ",") {
+		key, value, ok := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			continue
+		}
+		if decoded, err := url.QueryUnescape(strings.TrimSpace(value)); err == nil {
+			value = decoded
+		}
+		headers[key] = strings.TrimSpace(value)
+	}
+	return headers
+}
+
+// Span is a timed operation. A nil Span, returned while telemetry is disabled, ignores
+// every call.
+type Span struct {
+	r       *recorder
+	traceID [16]byte
+	id      [8]byte
+	parent  [8]byte
+	name    string
+	start   time.Time
+	end     time.Time
+	code    string // error code of a failed span
+
+	mu    sync.Mutex
+	attrs []Attr
+}
+
+type spanKey struct{}
+
+// Start starts a span, as a child of the span in ctx if there is one
+func Start(ctx context.Context, name string, attrs ...Attr) (context.Context, *Span) {
+	r := active.Load()
+	if r == nil {
+		return ctx, nil
+	}
+
+	s := &Span{r: r, name: name, start: time.Now(), attrs: attrs}
+	if parent, ok := ctx.Value(spanKey{}).(*Span); ok && parent.r == r {
+		s.traceID, s.parent = parent.traceID, parent.id
+	} else {
+		_, _ = rand.Read(s.traceID[:])
+	}
+	_, _ = rand.Read(s.id[:])
+	return context.WithValue(ctx, spanKey{}, s), s
+}
+
+// SetAttributes adds attributes to the span
+func (s *Span) SetAttributes(attrs ...Attr) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.attrs = append(s.attrs, attrs...)
+	s.mu.Unlock()
+}
+
+// End ends the span with the outcome of its operation. Its duration is added to
+// MetricDuration and, when err is not nil, MetricFailures counts its error code.
+func (s *Span) End(err error) {
+	if s == nil {
+		return
+	}
+	s.end = time.Now()
+	attrs := []Attr{String("operation", s.name), String("result", "success")}
+	if err != nil {
+		s.code = errors.Code(err)
+		attrs = []Attr{String("operation", s.name), String("result", "failure"), String("error.code", s.code)}
+		s.r.add(MetricFailures, "1", 1, attrs, false)
+	}
+	s.r.add(MetricDuration, "ms", float64(s.end.Sub(s.start).Microseconds())/1000, attrs, true)
+
+	s.r.mu.Lock()
+	s.r.spans = append(s.r.spans, s)
+	s.r.mu.Unlock()
+}
+
+// Add adds value to a counter, attributed to the operation of the span in ctx
+func Add(ctx context.Context, metric, unit string, value int64, attrs ...Attr) {
+	r := active.Load()
+	if r == nil {
+		return
+	}
+	if s, ok := ctx.Value(spanKey{}).(*Span); ok {
+		attrs = append([]Attr{String("operation", s.name)}, attrs...)
+	}
+	r.add(metric, unit, float64(value), attrs, false)
+}
+
+// add aggregates a measurement into a counter or a histogram
+func (r *recorder) add(metric, unit string, value float64, attrs []Attr, histogram bool) {
+	key := metric
+	for _, attr := range attrs {
+		key += fmt.Sprintf("\x00%s=%v", attr.Key, attr.Value)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.metrics[metric] = unit
+	p := r.points[key]
+	if p == nil {
+		p = &point{metric: metric, attrs: attrs}
+		if histogram {
+			p.buckets = make([]int64, len(durationBounds)+1)
+		}
+		r.points[key] = p
+	}
+	p.count++
+	p.sum += value
+	if histogram {
+		p.buckets[sort.SearchFloat64s(durationBounds, value)]++
+	}
+}
+
+// export sends the spans and metrics to the collector
+func (r *recorder) export(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	resource := otlpResource{Attributes: encodeAttrs([]Attr{
+		String("service.name", "ccmd"),
+		String("service.version", r.cfg.Version),
+		String("os.type", runtime.GOOS),
+		String("host.arch", runtime.GOARCH),
+	})}
+	scope := otlpScope{Name: scopeName, Version: r.cfg.Version}
+
+	if len(r.spans) > 0 {
+		spans := make([]otlpSpan, len(r.spans))
+		for i, s := range r.spans {
+			spans[i] = s.encode()
+		}
+		body := map[string]interface{}{"resourceSpans": []interface{}{map[string]interface{}{
+			"resource":   resource,
+			"scopeSpans": []interface{}{map[string]interface{}{"scope": scope, "spans": spans}},
+		}}}
+		if err := r.post(ctx, "/v1/traces", body); err != nil {
+			return err
+		}
+	}
+
+	if len(r.points) > 0 {
+		body := map[string]interface{}{"resourceMetrics": []interface{}{map[string]interface{}{
+			"resource":     resource,
+			"scopeMetrics": []interface{}{map[string]interface{}{"scope": scope, "metrics": r.encodeMetrics()}},
+		}}}
+		if err := r.post(ctx, "/v1/metrics", body); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// post sends one OTLP request
+func (r *recorder) post(ctx context.Context, path string, body interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.cfg.Endpoint+path, bytes.NewReader(data))
+	if err != nil {
+		return errors.InvalidInput(fmt.Sprintf("invalid telemetry endpoint %q: %v", r.cfg.Endpoint, err))
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range r.cfg.Headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := r.cfg.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("export telemetry to %s: %w", r.cfg.Endpoint, err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("export telemetry to %s: %s", r.cfg.Endpoint+path, resp.Status)
+	}
+	return nil
+}
+
+// OTLP JSON encoding, see opentelemetry-proto. 64-bit integers are encoded as strings
+// and trace and span IDs as hex.
+
+type otlpResource struct {
+	Attributes []otlpAttr `json:"attributes"`
+}
+
+type otlpScope struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+type otlpAttr struct {
+	Key   string                 `json:"key"`
+	Value map[string]interface{} `json:"value"`
+}
+
+type otlpSpan struct {
+	TraceID      string     `json:"traceId"`
+	SpanID       string     `json:"spanId"`
+	ParentSpanID string     `json:"parentSpanId,omitempty"`
+	Name         string     `json:"name"`
+	Kind         int        `json:"kind"`
+	Start        string     `json:"startTimeUnixNano"`
+	End          string     `json:"endTimeUnixNano"`
+	Attributes   []otlpAttr `json:"attributes,omitempty"`
+	Status       otlpStatus `json:"status"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"` // 1 ok, 2 error
+	Message string `json:"message,omitempty"`
+}
+
+// spanKindInternal is SPAN_KIND_INTERNAL
+const spanKindInternal = 1
+
+// aggregationDelta is AGGREGATION_TEMPORALITY_DELTA: each invocation exports its own counts
+const aggregationDelta = 1
+
+func (s *Span) encode() otlpSpan {
+	s.mu.Lock()
+	attrs := append([]Attr(nil), s.attrs...)
+	s.mu.Unlock()
+
+	span := otlpSpan{
+		TraceID:    hex.EncodeToString(s.traceID[:]),
+		SpanID:     hex.EncodeToString(s.id[:]),
+		Name:       s.name,
+		Kind:       spanKindInternal,
+		Start:      strconv.FormatInt(s.start.UnixNano(), 10),
+		End:        strconv.FormatInt(s.end.UnixNano(), 10),
+		Attributes: encodeAttrs(attrs),
+		Status:     otlpStatus{Code: 1},
+	}
+	if s.parent != [8]byte{} {
+		span.ParentSpanID = hex.EncodeToString(s.parent[:])
+	}
+	if s.code != "" {
+		// The error code only: messages can hold repository names and paths
+		span.Status = otlpStatus{Code: 2, Message: s.code}
+		span.Attributes = append(span.Attributes, encodeAttrs([]Attr{String("error.code", s.code)})...)
+	}
+	return span
+}
+
+func (r *recorder) encodeMetrics() []interface{} {
+	start := strconv.FormatInt(r.start.UnixNano(), 10)
+	now := strconv.FormatInt(time.Now().UnixNano(), 10)
+
+	byMetric := make(map[string][]map[string]interface{})
+	histograms := make(map[string]bool)
+	for _, p := range r.points {
+		dp := map[string]interface{}{
+			"attributes":        encodeAttrs(p.attrs),
+			"startTimeUnixNano": start,
+			"timeUnixNano":      now,
+		}
+		if p.buckets != nil {
+			histograms[p.metric] = true
+			counts := make([]string, len(p.buckets))
+			for i, n := range p.buckets {
+				counts[i] = strconv.FormatInt(n, 10)
+			}
+			dp["count"] = strconv.FormatInt(p.count, 10)
+			dp["sum"] = p.sum
+			dp["bucketCounts"] = counts
+			dp["explicitBounds"] = durationBounds
+		} else {
+			dp["asInt"] = strconv.FormatInt(int64(p.sum), 10)
+		}
+		byMetric[p.metric] = append(byMetric[p.metric], dp)
+	}
+
+	names := make([]string, 0, len(byMetric))
+	for name := range byMetric {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	metrics := make([]interface{}, 0, len(names))
+	for _, name := range names {
+		metric := map[string]interface{}{"name": name, "unit": r.metrics[name]}
+		if histograms[name] {
+			metric["histogram"] = map[string]interface{}{"aggregationTemporality": aggregationDelta, "dataPoints": byMetric[name]}
+		} else {
+			metric["sum"] = map[string]interface{}{"aggregationTemporality": aggregationDelta, "isMonotonic": true, "dataPoints": byMetric[name]}
+		}
+		metrics = append(metrics, metric)
+	}
+	return metrics
+}
+
+func encodeAttrs(attrs []Attr) []otlpAttr {
+	encoded := make([]otlpAttr, 0, len(attrs))
+	for _, attr := range attrs {
+		var value map[string]interface{}
+		switch v := attr.Value.(type) {
+		case string:
+			value = map[string]interface{}{"stringValue": v}
+		case int64:
+			value = map[string]interface{}{"intValue": strconv.FormatInt(v, 10)}
+		case bool:
+			value = map[string]interface{}{"boolValue": v}
+		default:
+			value = map[string]interface{}{"stringValue": fmt.Sprint(v)}
+		}
+		encoded = append(encoded, otlpAttr{Key: attr.Key, Value: value})
+	}
+	return encoded
+}
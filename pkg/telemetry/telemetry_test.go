@@ -0,0 +1,137 @@
+/*
+ * This file is part of ccmd.
+ *
+ * Copyright (c) 2025 Guilherme Silva Sousa
+ *
+ * Licensed under the MIT License
+ * See LICENSE file in the project root for full license information.
+ */
+
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gifflet/ccmd/pkg/errors"
+)
+
+// collector records the OTLP requests it receives by path
+type collector struct {
+	mu       sync.Mutex
+	requests map[string][]byte
+	headers  http.Header
+}
+
+func newCollector(t *testing.T) (*collector, string) {
+	c := &collector{requests: make(map[string][]byte)}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		c.mu.Lock()
+		c.requests[r.URL.Path] = body
+		c.headers = r.Header.Clone()
+		c.mu.Unlock()
+	}))
+	t.Cleanup(server.Close)
+	return c, server.URL
+}
+
+func TestDisabled(t *testing.T) {
+	require.False(t, Enabled())
+
+	ctx, span := Start(context.Background(), "install")
+	assert.Nil(t, span)
+	assert.Nil(t, ctx.Value(spanKey{}))
+	span.SetAttributes(String("repo.host", "github.com"))
+	span.End(errors.NotFound("x"))
+	Add(ctx, MetricBytes, "By", 10)
+	assert.NoError(t, Shutdown(context.Background()))
+}
+
+func TestExport(t *testing.T) {
+	c, endpoint := newCollector(t)
+	t.Setenv(HeadersEnv, "authorization=Bearer%20secret, x-team = tools")
+	Enable(Config{Endpoint: endpoint + "/", Version: "1.2.3"})
+	require.True(t, Enabled())
+
+	ctx, parent := Start(context.Background(), "sync")
+	childCtx, child := Start(ctx, "install", String("repo.host", "github.com"))
+	Add(childCtx, MetricBytes, "By", 512)
+	child.End(errors.NotFound(`command "acme/secret-repo"`))
+	parent.SetAttributes(Int("failed", 1))
+	parent.End(nil)
+
+	require.NoError(t, Shutdown(context.Background()))
+	assert.False(t, Enabled())
+	assert.Equal(t, "Bearer secret", c.headers.Get("Authorization"))
+	assert.Equal(t, "tools", c.headers.Get("X-Team"))
+
+	var traces struct {
+		ResourceSpans []struct {
+			Resource   otlpResource
+			ScopeSpans []struct {
+				Scope otlpScope
+				Spans []otlpSpan
+			}
+		}
+	}
+	require.NoError(t, json.Unmarshal(c.requests["/v1/traces"], &traces))
+	assert.NotContains(t, string(c.requests["/v1/traces"]), "secret-repo")
+	spans := traces.ResourceSpans[0].ScopeSpans[0].Spans
+	require.Len(t, spans, 2)
+
+	install, sync := spans[0], spans[1]
+	assert.Equal(t, "install", install.Name)
+	assert.Equal(t, sync.TraceID, install.TraceID)
+	assert.Equal(t, sync.SpanID, install.ParentSpanID)
+	assert.Empty(t, sync.ParentSpanID)
+	assert.Equal(t, otlpStatus{Code: 2, Message: errors.CodeNotFound}, install.Status)
+	assert.Equal(t, 1, sync.Status.Code)
+	assert.Len(t, install.TraceID, 32)
+	assert.Len(t, install.SpanID, 16)
+
+	var metrics struct {
+		ResourceMetrics []struct {
+			ScopeMetrics []struct {
+				Metrics []map[string]json.RawMessage
+			}
+		}
+	}
+	require.NoError(t, json.Unmarshal(c.requests["/v1/metrics"], &metrics))
+	names := []string{}
+	for _, metric := range metrics.ResourceMetrics[0].ScopeMetrics[0].Metrics {
+		var name string
+		require.NoError(t, json.Unmarshal(metric["name"], &name))
+		names = append(names, name)
+	}
+	assert.Equal(t, []string{MetricBytes, MetricDuration, MetricFailures}, names)
+	assert.Contains(t, string(c.requests["/v1/metrics"]), `"asInt":"512"`)
+	assert.Contains(t, string(c.requests["/v1/metrics"]), `{"key":"error.code","value":{"stringValue":"not_found"}}`)
+}
+
+func TestExportFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	Enable(Config{Endpoint: server.URL})
+	_, span := Start(context.Background(), "update")
+	span.End(nil)
+	err := Shutdown(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "503")
+}
+
+func TestParseHeaders(t *testing.T) {
+	assert.Equal(t, map[string]string{"api-key": "a b", "team": "x=y"}, ParseHeaders("api-key=a%20b, team=x=y,,bad"))
+	assert.Empty(t, ParseHeaders(""))
+}
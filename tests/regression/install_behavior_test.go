@@ -120,6 +120,11 @@ func TestInstallCommandBehavior(t *testing.T) {
 
 // TestInstallCommandErrorCases documents current error handling behavior
 func TestInstallCommandErrorCases(t *testing.T) {
+	// Run outside the repository so failed installs leave no files behind
+	oldDir, _ := os.Getwd()
+	defer os.Chdir(oldDir)
+	os.Chdir(t.TempDir())
+
 	testCases := []struct {
 		name        string
 		opts        core.InstallOptions